@@ -16,6 +16,7 @@ import (
 	"github.com/rumsystem/quorum/internal/pkg/options"
 	"github.com/rumsystem/quorum/internal/pkg/storage"
 	chainstorage "github.com/rumsystem/quorum/internal/pkg/storage/chain"
+	node_storage "github.com/rumsystem/quorum/internal/pkg/storage/node"
 	"github.com/rumsystem/quorum/internal/pkg/utils"
 	"github.com/rumsystem/quorum/pkg/chainapi/api"
 	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
@@ -110,6 +111,10 @@ func runBootstrapNode(config cli.BootstrapNodeFlag) {
 		cancel()
 		logger.Fatalf(err.Error())
 	}
+	if err := storage.SetDefaultEngine(nodeoptions.StorageEngine); err != nil {
+		cancel()
+		logger.Fatalf(err.Error())
+	}
 
 	// overwrite by cli flags
 	nodeoptions.EnableRelay = config.EnableRelay
@@ -166,6 +171,11 @@ func runBootstrapNode(config cli.BootstrapNodeFlag) {
 		logger.Fatalf(err.Error())
 	}
 
+	nodeDb := node_storage.NewNodeStorage(dbManager.Db)
+	if err := nodeoptions.InitJWTStore(ctx, nodeDb); err != nil {
+		logger.Fatalf(err.Error())
+	}
+
 	nodectx.InitCtx(ctx, "", bootstrapNode, dbManager, chainstorage.NewChainStorage(dbManager), "pubsub", utils.GitCommit, nodectx.BOOTSTRAP_NODE)
 	nodectx.GetNodeCtx().Keystore = ks
 	nodectx.GetNodeCtx().PublicKey = keys.PubKey
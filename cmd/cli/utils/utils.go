@@ -26,7 +26,7 @@ func CheckTrx(groupId string, trxId string, status string) ([]string, error) {
 			if item.State == chain.PublishQueueItemStateSuccess {
 				successed = append(successed, item.Trx.TrxId)
 			}
-			if item.RetryCount > chain.MAX_RETRY_COUNT && item.State == chain.PublishQueueItemStateFail {
+			if item.State == chain.PublishQueueItemStateFail {
 				rejected = append(rejected, item.Trx.TrxId)
 			}
 		}
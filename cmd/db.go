@@ -6,9 +6,14 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/dgraph-io/badger/v3"
+	"github.com/rumsystem/quorum/internal/pkg/appdata"
+	chain "github.com/rumsystem/quorum/internal/pkg/chainsdk/core"
 	"github.com/rumsystem/quorum/internal/pkg/storage"
+	chainstorage "github.com/rumsystem/quorum/internal/pkg/storage/chain"
+	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
 	quorumpb "github.com/rumsystem/quorum/pkg/pb"
 	"github.com/spf13/cobra"
 	"go.etcd.io/bbolt"
@@ -23,6 +28,8 @@ const (
 var (
 	_migrateParam dbParam
 	_compactParam dbParam
+	_rebuildParam rebuildAppdbParam
+	_verifyParam  verifyDbParam
 
 	kinds = []string{"db", "appdb", "groups", "pubqueue"} // FIXME: hardcode
 )
@@ -30,7 +37,7 @@ var (
 var (
 	dbCmd = &cobra.Command{
 		Use:              "db",
-		Short:            "database tool, migrate or compact",
+		Short:            "database tool, migrate, compact, verify or rebuild appdb",
 		TraverseChildren: true,
 	}
 
@@ -53,6 +60,26 @@ var (
 			}
 		},
 	}
+
+	verifyDbCmd = &cobra.Command{
+		Use:   "verify",
+		Short: "verify blocks and derived tables of groups in chain db, the node should be stopped",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := verifyDb(); err != nil {
+				logger.Fatal(err)
+			}
+		},
+	}
+
+	rebuildAppdbCmd = &cobra.Command{
+		Use:   "rebuild-appdb",
+		Short: "rebuild app indexes from blocks in chain db",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := rebuildAppdb(); err != nil {
+				logger.Fatal(err)
+			}
+		},
+	}
 )
 
 type (
@@ -61,11 +88,31 @@ type (
 		DataDir    string
 		NewDataDir string
 	}
+
+	rebuildAppdbParam struct {
+		PeerName string
+		DataDir  string
+		NodeName string
+		VerTag   string
+	}
+
+	verifyDbParam struct {
+		PeerName     string
+		DataDir      string
+		NodeName     string
+		GroupId      string
+		KeyStoreDir  string
+		KeyStoreName string
+		ProducerNode bool
+		Repair       bool
+	}
 )
 
 func init() {
 	dbCmd.AddCommand(migrateCmd)
 	dbCmd.AddCommand(compactCmd)
+	dbCmd.AddCommand(rebuildAppdbCmd)
+	dbCmd.AddCommand(verifyDbCmd)
 	rootCmd.AddCommand(dbCmd)
 
 	// migrate
@@ -85,6 +132,28 @@ func init() {
 	compactFlags.StringVar(&_compactParam.DataDir, "datadir", "data", "data dir")
 	compactFlags.StringVar(&_compactParam.NewDataDir, "newdatadir", "", "new data dir")
 	migrateCmd.MarkFlagRequired("newdatadir")
+
+	// rebuild appdb
+	rebuildFlags := rebuildAppdbCmd.Flags()
+	rebuildFlags.SortFlags = false
+
+	rebuildFlags.StringVar(&_rebuildParam.PeerName, "peername", "peer", "peer name")
+	rebuildFlags.StringVar(&_rebuildParam.DataDir, "datadir", "data", "data dir")
+	rebuildFlags.StringVar(&_rebuildParam.NodeName, "nodename", "fullnode_default", "node name used as key prefix in chain db")
+	rebuildFlags.StringVar(&_rebuildParam.VerTag, "vertag", "", "rebuild tag, use the tag printed by an interrupted rebuild to resume it")
+
+	// verify
+	verifyFlags := verifyDbCmd.Flags()
+	verifyFlags.SortFlags = false
+
+	verifyFlags.StringVar(&_verifyParam.PeerName, "peername", "peer", "peer name")
+	verifyFlags.StringVar(&_verifyParam.DataDir, "datadir", "data", "data dir")
+	verifyFlags.StringVar(&_verifyParam.NodeName, "nodename", "fullnode_default", "node name used as key prefix in chain db")
+	verifyFlags.StringVar(&_verifyParam.GroupId, "groupid", "", "group id, verify all groups if empty")
+	verifyFlags.StringVar(&_verifyParam.KeyStoreDir, "keystoredir", "./keystore/", "keystore dir, only used to verify signatures")
+	verifyFlags.StringVar(&_verifyParam.KeyStoreName, "keystorename", "default", "keystore name")
+	verifyFlags.BoolVar(&_verifyParam.ProducerNode, "producernode", false, "chain db of a producer node, which does not keep app config")
+	verifyFlags.BoolVar(&_verifyParam.Repair, "repair", false, "replace mismatched producers, users and app config by the replay result")
 }
 
 func openBadgerDB(dbDir string) (*badger.DB, error) {
@@ -228,3 +297,93 @@ func compactAll() error {
 
 	return nil
 }
+
+func rebuildAppdb() error {
+	_dbParam := _rebuildParam
+	datapath := filepath.Join(_dbParam.DataDir, _dbParam.PeerName)
+
+	vertag := _dbParam.VerTag
+	if vertag == "" {
+		vertag = time.Now().Format("20060102150405")
+	}
+	fmt.Printf("rebuild appdb with tag %s, rerun with --vertag %s to resume if interrupted\n", vertag, vertag)
+
+	chainDb, err := storage.NewStore(context.Background(), datapath, "db")
+	if err != nil {
+		return err
+	}
+	defer chainDb.Close()
+
+	appdb, err := appdata.CreateAppDb(datapath)
+	if err != nil {
+		return err
+	}
+	defer appdb.Close()
+
+	if err := appdb.Rebuild(vertag, chainDb, _dbParam.NodeName); err != nil {
+		return err
+	}
+
+	fmt.Printf("rebuild appdb done\n")
+	return nil
+}
+
+func verifyDb() error {
+	_dbParam := _verifyParam
+
+	//signatures are verified by the keystore, no key is unlocked
+	if _, err := localcrypto.InitKeystore(_dbParam.KeyStoreName, _dbParam.KeyStoreDir); err != nil {
+		return err
+	}
+
+	dbManager, err := storage.CreateDb(filepath.Join(_dbParam.DataDir, _dbParam.PeerName))
+	if err != nil {
+		return err
+	}
+	defer dbManager.Db.Close()
+	defer dbManager.GroupInfoDb.Close()
+
+	groupItemsBytes, err := dbManager.GetGroupsBytes()
+	if err != nil {
+		return err
+	}
+
+	cs := chainstorage.NewChainStorage(dbManager)
+	found := false
+	issues := 0
+	for _, b := range groupItemsBytes {
+		item := &quorumpb.GroupItem{}
+		if err := proto.Unmarshal(b, item); err != nil {
+			return err
+		}
+		if _dbParam.GroupId != "" && item.GroupId != _dbParam.GroupId {
+			continue
+		}
+		found = true
+
+		fmt.Printf("verify group %s (%s)\n", item.GroupId, item.GroupName)
+		result, err := chain.VerifyGroupChain(cs, item, _dbParam.NodeName, _dbParam.ProducerNode, _dbParam.Repair)
+		if err != nil {
+			return fmt.Errorf("verify group %s failed: %s", item.GroupId, err)
+		}
+
+		fmt.Printf("  top block %d, %d blocks and %d trxs checked, pruned to %d, state checked: %v\n",
+			result.TopBlock, result.CheckedBlocks, result.CheckedTrxs, result.PrunedTo, result.StateChecked)
+		for _, issue := range result.Issues {
+			fmt.Printf("  [%s] block %d: %s\n", issue.Kind, issue.BlockId, issue.Detail)
+		}
+		if len(result.Repaired) > 0 {
+			fmt.Printf("  repaired: %s\n", strings.Join(result.Repaired, ", "))
+		}
+		issues += len(result.Issues)
+	}
+
+	if _dbParam.GroupId != "" && !found {
+		return fmt.Errorf("group %s not found", _dbParam.GroupId)
+	}
+	if issues > 0 {
+		return fmt.Errorf("%d issues found", issues)
+	}
+	fmt.Printf("verify done, no issue found\n")
+	return nil
+}
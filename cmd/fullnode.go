@@ -19,10 +19,12 @@ import (
 	"github.com/rumsystem/quorum/internal/pkg/cli"
 	"github.com/rumsystem/quorum/internal/pkg/conn"
 	"github.com/rumsystem/quorum/internal/pkg/conn/p2p"
+	"github.com/rumsystem/quorum/internal/pkg/filestore"
 	"github.com/rumsystem/quorum/internal/pkg/nodectx"
 	"github.com/rumsystem/quorum/internal/pkg/options"
 	"github.com/rumsystem/quorum/internal/pkg/storage"
 	chainstorage "github.com/rumsystem/quorum/internal/pkg/storage/chain"
+	node_storage "github.com/rumsystem/quorum/internal/pkg/storage/node"
 	"github.com/rumsystem/quorum/internal/pkg/utils"
 	"github.com/rumsystem/quorum/pkg/chainapi/api"
 	appapi "github.com/rumsystem/quorum/pkg/chainapi/appapi"
@@ -124,6 +126,10 @@ func runFullnode(config cli.FullNodeFlag) {
 		cancel()
 		logger.Fatalf(err.Error())
 	}
+	if err := storage.SetDefaultEngine(nodeoptions.StorageEngine); err != nil {
+		cancel()
+		logger.Fatalf(err.Error())
+	}
 
 	// overwrite by cli flags
 	nodeoptions.EnableRelay = config.EnableRelay
@@ -170,6 +176,10 @@ func runFullnode(config cli.FullNodeFlag) {
 		logger.Fatalf(err.Error())
 	}
 	newchainstorage := chainstorage.NewChainStorage(dbManager)
+	nodeDb := node_storage.NewNodeStorage(dbManager.Db)
+	if err := nodeoptions.InitJWTStore(ctx, nodeDb); err != nil {
+		logger.Fatalf(err.Error())
+	}
 
 	//normal node connections: low watermarks: 10  hi watermarks 200, grace 60s
 	cm, err := connmgr.NewConnManager(10, nodeoptions.ConnsHi, connmgr.WithGracePeriod(60*time.Second))
@@ -209,6 +219,13 @@ func runFullnode(config cli.FullNodeFlag) {
 		logger.Fatalf(err.Error())
 	}
 
+	//initial publish queue, trxs will be resent till they are packaged
+	pubqueueDb, err := storage.OpenStore(ctx, datapath, "pubqueue")
+	if err != nil {
+		logger.Fatalf(err.Error())
+	}
+	chain.InitPublishQueueWatcher(ctx, chain.GetGroupMgr(), pubqueueDb, config.AutoAck)
+
 	if err := fullNode.Bootstrap(ctx, config.BootstrapPeers); err != nil {
 		logger.Fatal(err)
 	}
@@ -223,9 +240,21 @@ func runFullnode(config cli.FullNodeFlag) {
 	if err != nil {
 		logger.Fatalf(err.Error())
 	}
+	appdb.EnableSearchIndex = nodeoptions.EnableSearchIndex
 
 	CheckLockError(err)
 
+	appdata.InitWebhookAgent(ctx, appdb, nodename)
+
+	//prune blocks by retention policy of groups, blocks not synced to appdb or not delivered to webhooks are kept
+	chain.InitBlockPruner(ctx, appdb.GetPruneHold)
+
+	fileStore, err := filestore.NewFileStore(ctx, datapath+"/files")
+	if err != nil {
+		logger.Fatalf(err.Error())
+	}
+	fullNode.SetRexFile(fileStore)
+
 	// init the websocket manager
 	websocketManager := api.NewWebsocketManager()
 	go websocketManager.Start()
@@ -243,6 +272,7 @@ func runFullnode(config cli.FullNodeFlag) {
 		Ctx:              ctx,
 		GitCommit:        utils.GitCommit,
 		Appdb:            appdb,
+		FileStore:        fileStore,
 		ChainAPIdb:       newchainstorage,
 		WebsocketManager: websocketManager,
 	}
@@ -258,6 +288,7 @@ func runFullnode(config cli.FullNodeFlag) {
 		ConfigDir: config.ConfigDir,
 		PeerName:  config.PeerName,
 		NodeName:  nodectx.GetNodeCtx().Name,
+		NodeDb:    nodeDb,
 	}
 	startParam := api.StartServerParam{
 		IsDebug:       config.IsDebug,
@@ -278,6 +309,7 @@ func runFullnode(config cli.FullNodeFlag) {
 	chain.GetGroupMgr().TeardownAllGroups()
 	//close ctx db
 	nodectx.GetDbMgr().CloseDb()
+	pubqueueDb.Close()
 
 	//cleanup before exit
 	logger.Infof("On Signal <%s>", signalType)
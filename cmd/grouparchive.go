@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rumsystem/quorum/internal/pkg/storage"
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_exportGroupParam exportGroupParam
+	_importGroupParam importGroupParam
+)
+
+var (
+	groupCmd = &cobra.Command{
+		Use:   "group",
+		Short: "group tool, export or import the chain of a group",
+	}
+
+	exportGroupCmd = &cobra.Command{
+		Use:   "export",
+		Short: "export all blocks of a group to an archive file, the node should be stopped",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := exportGroup(); err != nil {
+				logger.Fatal(err)
+			}
+		},
+	}
+
+	importGroupCmd = &cobra.Command{
+		Use:   "import",
+		Short: "import an archive file into a joined group of a running node",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := importGroup(); err != nil {
+				logger.Fatal(err)
+			}
+		},
+	}
+)
+
+type (
+	exportGroupParam struct {
+		PeerName string
+		DataDir  string
+		NodeName string
+		GroupId  string
+		File     string
+	}
+
+	importGroupParam struct {
+		ApiUrl  string
+		Jwt     string
+		GroupId string
+		File    string
+	}
+)
+
+func init() {
+	groupCmd.AddCommand(exportGroupCmd)
+	groupCmd.AddCommand(importGroupCmd)
+	rootCmd.AddCommand(groupCmd)
+
+	// export
+	exportFlags := exportGroupCmd.Flags()
+	exportFlags.SortFlags = false
+
+	exportFlags.StringVar(&_exportGroupParam.PeerName, "peername", "peer", "peer name")
+	exportFlags.StringVar(&_exportGroupParam.DataDir, "datadir", "data", "data dir")
+	exportFlags.StringVar(&_exportGroupParam.NodeName, "nodename", "fullnode_default", "node name used as key prefix in chain db")
+	exportFlags.StringVar(&_exportGroupParam.GroupId, "groupid", "", "group id")
+	exportFlags.StringVar(&_exportGroupParam.File, "file", "", "archive filename")
+	exportGroupCmd.MarkFlagRequired("groupid")
+	exportGroupCmd.MarkFlagRequired("file")
+
+	// import
+	importFlags := importGroupCmd.Flags()
+	importFlags.SortFlags = false
+
+	importFlags.StringVar(&_importGroupParam.ApiUrl, "apiurl", "http://127.0.0.1:5215", "api url of the running node")
+	importFlags.StringVar(&_importGroupParam.Jwt, "jwt", "", "jwt of the running node")
+	importFlags.StringVar(&_importGroupParam.GroupId, "groupid", "", "group id")
+	importFlags.StringVar(&_importGroupParam.File, "file", "", "archive filename")
+	importGroupCmd.MarkFlagRequired("groupid")
+	importGroupCmd.MarkFlagRequired("file")
+}
+
+func exportGroup() error {
+	_param := _exportGroupParam
+
+	dbManager, err := storage.CreateDb(filepath.Join(_param.DataDir, _param.PeerName))
+	if err != nil {
+		return err
+	}
+	defer dbManager.Db.Close()
+	defer dbManager.GroupInfoDb.Close()
+
+	f, err := os.Create(_param.File)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	count, err := handlers.ExportGroupArchive(dbManager, _param.GroupId, w, _param.NodeName)
+	if err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("export %d blocks of group %s to %s\n", count, _param.GroupId, _param.File)
+	return nil
+}
+
+func importGroup() error {
+	_param := _importGroupParam
+
+	f, err := os.Open(_param.File)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	url := fmt.Sprintf("%s/api/v1/group/%s/import", strings.TrimRight(_param.ApiUrl, "/"), _param.GroupId)
+	req, err := http.NewRequest(http.MethodPost, url, bufio.NewReader(f))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if _param.Jwt != "" {
+		req.Header.Set("Authorization", "Bearer "+_param.Jwt)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("import group %s failed: %s", _param.GroupId, body)
+	}
+
+	fmt.Printf("%s\n", body)
+	return nil
+}
@@ -1,10 +1,18 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v4"
+
 	"github.com/rumsystem/quorum/internal/pkg/options"
+	"github.com/rumsystem/quorum/internal/pkg/storage"
+	node_storage "github.com/rumsystem/quorum/internal/pkg/storage/node"
 	"github.com/rumsystem/quorum/internal/pkg/utils"
 	"github.com/spf13/cobra"
 )
@@ -14,6 +22,8 @@ var (
 	jwtName     string
 	jwtGroupId  string
 	jwtDuration time.Duration
+	jwtScope    utils.JWTScope
+	jwtDataDir  string
 
 	// parse
 	jwtToken string
@@ -34,7 +44,7 @@ var jwtCreateNodeCmd = &cobra.Command{
 	Use:   "node",
 	Short: "Create jwt for node sdk and save to config file",
 	Run: func(cmd *cobra.Command, args []string) {
-		createNodeToken(configDir, peerName, jwtName, jwtDuration, jwtGroupId)
+		createNodeToken(configDir, peerName, jwtName, jwtDuration, jwtGroupId, &jwtScope)
 	},
 }
 
@@ -42,7 +52,7 @@ var jwtCreateChainCmd = &cobra.Command{
 	Use:   "chain",
 	Short: "Create jwt for chain sdk and save to config file",
 	Run: func(cmd *cobra.Command, args []string) {
-		createChainToken(configDir, peerName, jwtName, jwtDuration)
+		createChainToken(configDir, peerName, jwtName, jwtDuration, &jwtScope)
 	},
 }
 
@@ -71,6 +81,7 @@ func init() {
 	createNodeFlags.StringVarP(&jwtName, "name", "n", "", "name of the node jwt")
 	createNodeFlags.DurationVarP(&jwtDuration, "duration", "d", time.Hour*24*365, "duration of node jwt")
 	createNodeFlags.StringVarP(&jwtGroupId, "groupid", "g", "", "allow group for node jwt")
+	addJWTScopeFlags(jwtCreateNodeCmd)
 
 	jwtCreateNodeCmd.MarkFlagRequired("name")
 	jwtCreateNodeCmd.MarkFlagRequired("groupid")
@@ -82,6 +93,7 @@ func init() {
 	createChainFlags.StringVarP(&peerName, "peername", "p", "peer", "peer name")
 	createChainFlags.StringVarP(&jwtName, "name", "n", "", "name of the node jwt")
 	createChainFlags.DurationVarP(&jwtDuration, "duration", "d", time.Hour*24*365, "duration of node jwt")
+	addJWTScopeFlags(jwtCreateChainCmd)
 
 	jwtCreateChainCmd.MarkFlagRequired("name")
 
@@ -95,6 +107,15 @@ func init() {
 	jwtParseCmd.MarkFlagRequired("token")
 }
 
+func addJWTScopeFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.StringVar(&jwtDataDir, "datadir", "data", "data dir, to record the token in audit log of node db")
+	flags.BoolVar(&jwtScope.ReadOnly, "readonly", false, "read only jwt, can not send trx or change node")
+	flags.StringSliceVar(&jwtScope.TrxTypes, "trxtypes", nil, "allowed trx types, e.g. POST,ANNOUNCE, empty for all")
+	flags.IntVar(&jwtScope.RateLimit, "ratelimit", 0, "max requests per minute, 0 for unlimited")
+	flags.IntVar(&jwtScope.DailyPostQuota, "postquota", 0, "max posts per day, 0 for unlimited")
+}
+
 // getJWTKey get jwt key or fatal
 func getJWTKey(configDir string, peerName string) string {
 	opt, err := options.InitNodeOptions(configDir, peerName)
@@ -104,37 +125,90 @@ func getJWTKey(configDir string, peerName string) string {
 	return opt.JWT.Key
 }
 
-func newToken(role string, groupid string, name string, duration time.Duration, configdir, peername string) (string, error) {
+func newToken(role string, groupid string, name string, duration time.Duration, scope *utils.JWTScope, configdir, peername string) (string, error) {
 	nodeoptions, err := options.InitNodeOptions(configdir, peername)
 	if err != nil {
 		logger.Fatalf("init node option failed: %s", err)
 	}
+	// audit log is saved in node db of the configured engine
+	if err := storage.SetDefaultEngine(nodeoptions.StorageEngine); err != nil {
+		logger.Fatalf("init node option failed: %s", err)
+	}
 
 	if role == "node" {
-		return nodeoptions.NewNodeJWT(groupid, name, time.Now().Add(duration))
+		return nodeoptions.NewNodeJWT(groupid, name, time.Now().Add(duration), scope)
 	} else if role == "chain" {
-		return nodeoptions.NewChainJWT(name, time.Now().Add(duration))
+		return nodeoptions.NewChainJWT(name, time.Now().Add(duration), scope)
 	} else {
 		return "", fmt.Errorf("invalid token role: %s", role)
 	}
 }
 
-func createNodeToken(configDir string, peerName string, name string, duration time.Duration, groupid string) {
-	token, err := newToken("node", groupid, name, duration, configDir, peerName)
+func createNodeToken(configDir string, peerName string, name string, duration time.Duration, groupid string, scope *utils.JWTScope) {
+	if err := validateJWTScope(scope); err != nil {
+		logger.Fatalf("create node token failed: %s", err)
+	}
+	token, err := newToken("node", groupid, name, duration, scope, configDir, peerName)
 	if err != nil {
 		logger.Fatalf("create node token failed: %s", err)
 	}
+	addJWTAuditLog(jwtDataDir, peerName, token)
 	fmt.Printf("new nodesdk token: %s\n", token)
 }
 
-func createChainToken(configDir string, peerName string, name string, duration time.Duration) {
-	token, err := newToken("chain", "", name, duration, configDir, peerName)
+func createChainToken(configDir string, peerName string, name string, duration time.Duration, scope *utils.JWTScope) {
+	if err := validateJWTScope(scope); err != nil {
+		logger.Fatalf("create chain token failed: %s", err)
+	}
+	token, err := newToken("chain", "", name, duration, scope, configDir, peerName)
 	if err != nil {
 		logger.Fatalf("create chain token failed: %s", err)
 	}
+	addJWTAuditLog(jwtDataDir, peerName, token)
 	fmt.Printf("new chain token: %s\n", token)
 }
 
+// addJWTAuditLog records token creation in node db, it is skipped if the db is locked by a running node
+func addJWTAuditLog(dataDir string, peerName string, tokenStr string) {
+	if storage.GetDefaultEngine() == storage.ENGINE_MEMORY {
+		// nothing to record, node db of memory engine lives in the node process
+		return
+	}
+	db, err := storage.OpenStore(context.Background(), filepath.Join(dataDir, peerName), "db")
+	if err != nil {
+		logger.Warnf("open node db failed, token is not recorded in audit log: %s", err)
+		return
+	}
+	defer db.Close()
+
+	token, err := utils.ParseJWTTokenUnverified(tokenStr)
+	if err != nil {
+		logger.Warnf("parse token failed: %s", err)
+		return
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	item := &node_storage.JWTAuditItem{
+		Action:    node_storage.JWTActionCreate,
+		Jti:       utils.GetJWTId(token),
+		ExpiresAt: utils.GetJWTExpiresAt(token),
+		Operator:  "cli",
+	}
+	item.Name, _ = claims["name"].(string)
+	item.Role, _ = claims["role"].(string)
+	item.GroupId, _ = claims["allowGroup"].(string)
+
+	if err := node_storage.NewNodeStorage(db).AddJWTAuditLog(item); err != nil {
+		logger.Warnf("add token audit log failed: %s", err)
+	}
+}
+
+func validateJWTScope(scope *utils.JWTScope) error {
+	for i, v := range scope.TrxTypes {
+		scope.TrxTypes[i] = strings.ToUpper(v)
+	}
+	return validator.New().Struct(scope)
+}
+
 func parseToken(configDir string, peerName string, token string) {
 	key := getJWTKey(configDir, peerName)
 	claims, err := utils.ParseJWTToken(token, key)
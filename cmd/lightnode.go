@@ -85,6 +85,10 @@ func runLightnode(config cli.LightnodeFlag) {
 		cancel()
 		logger.Fatalf(err.Error())
 	}
+	if err := storage.SetDefaultEngine(nodeoptions.StorageEngine); err != nil {
+		cancel()
+		logger.Fatalf(err.Error())
+	}
 
 	keystoreParam := InitKeystoreParam{
 		KeystoreName:   config.KeyStoreName,
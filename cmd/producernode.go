@@ -21,6 +21,7 @@ import (
 	"github.com/rumsystem/quorum/internal/pkg/options"
 	"github.com/rumsystem/quorum/internal/pkg/storage"
 	chainstorage "github.com/rumsystem/quorum/internal/pkg/storage/chain"
+	node_storage "github.com/rumsystem/quorum/internal/pkg/storage/node"
 	"github.com/rumsystem/quorum/internal/pkg/utils"
 	"github.com/rumsystem/quorum/pkg/chainapi/api"
 	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
@@ -113,6 +114,10 @@ func runProducerNode(config cli.ProducerNodeFlag) {
 		cancel()
 		logger.Fatalf(err.Error())
 	}
+	if err := storage.SetDefaultEngine(nodeoptions.StorageEngine); err != nil {
+		cancel()
+		logger.Fatalf(err.Error())
+	}
 
 	nodeoptions.EnableRelay = false
 
@@ -160,6 +165,10 @@ func runProducerNode(config cli.ProducerNodeFlag) {
 	}
 
 	newchainstorage := chainstorage.NewChainStorage(dbManager)
+	nodeDb := node_storage.NewNodeStorage(dbManager.Db)
+	if err := nodeoptions.InitJWTStore(ctx, nodeDb); err != nil {
+		logger.Fatalf(err.Error())
+	}
 
 	//normal node connections: low watermarks: 10  hi watermarks 200, grace 60s
 	cm, err := connmgr.NewConnManager(10, nodeoptions.ConnsHi, connmgr.WithGracePeriod(60*time.Second))
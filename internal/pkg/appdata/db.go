@@ -25,9 +25,10 @@ const SED_PREFIX string = "sed_"
 const STATUS_PREFIX string = "stu_"
 
 type AppDb struct {
-	Db       storage.QuorumStorage
-	seq      map[string]storage.Sequence
-	DataPath string
+	Db                storage.QuorumStorage
+	seq               map[string]storage.Sequence
+	DataPath          string
+	EnableSearchIndex bool
 }
 
 func NewAppDb() *AppDb {
@@ -62,11 +63,6 @@ func (appdb *AppDb) GetSeqId(seqkey string) (uint64, error) {
 	return appdb.seq[seqkey].Next()
 }
 
-func (appdb *AppDb) Rebuild(vertag string, chainDb storage.QuorumStorage) error {
-
-	return nil
-}
-
 func (appdb *AppDb) GetGroupContentBySenders(groupid string, senders []string, starttrx string, num int, reverse bool, starttrxinclude bool) (trxidList []string, err error) {
 	prefix := fmt.Sprintf("%s%s-%s", CNT_PREFIX, GRP_PREFIX, groupid)
 	sendermap := make(map[string]bool)
@@ -249,6 +245,32 @@ func (appdb *AppDb) AddMetaByTrx(blockId uint64, groupid string, trxs []*quorump
 	return err
 }
 
+// GetPruneHold return the lowest block of the group still to be read by appdb sync or webhooks,
+// all blocks are held if it is unknown
+func (appdb *AppDb) GetPruneHold(groupid string) uint64 {
+	blockIdStr, err := appdb.GetGroupStatus(groupid, "Block")
+	if err != nil {
+		return 1
+	}
+	synced, _ := strconv.ParseUint(blockIdStr, 10, 64)
+	hold := synced + 1
+
+	webhooks, err := appdb.GetWebhooks(groupid)
+	if err != nil {
+		return 1
+	}
+	for _, w := range webhooks {
+		if w.NextBlock < hold {
+			hold = w.NextBlock
+		}
+	}
+	if hold == 0 {
+		// genesis block is never pruned
+		hold = 1
+	}
+	return hold
+}
+
 func (appdb *AppDb) Release() error {
 	for seqkey := range appdb.seq {
 		err := appdb.seq[seqkey].Release()
@@ -1,6 +1,7 @@
 package appdata
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -9,13 +10,12 @@ import (
 	quorumpb "github.com/rumsystem/quorum/pkg/pb"
 )
 
-func ifTrxWithNonceSliceEqual(left []TrxIdNonce, right []TrxIdNonce) bool {
+func ifTrxIdSliceEqual(left []string, right []string) bool {
 	if len(left) != len(right) {
 		return false
 	}
 	for i, vl := range left {
-		vr := right[i]
-		if vl.TrxId != vr.TrxId || vl.Nonce != vr.Nonce {
+		if vl != right[i] {
 			return false
 		}
 	}
@@ -28,16 +28,14 @@ func makemockdb(temppath string, groupid string) (*AppDb, error) {
 	tempdir := fmt.Sprintf("%s/%s/%s", temppath, name, dbname)
 	appdatalog.Debugf("tempdir %s", tempdir)
 
-	var err error
-	db := storage.QSBadger{}
-	err = db.Init(tempdir)
+	db, err := storage.NewBadgerStore(context.Background(), tempdir, dbname)
 	if err != nil {
 		return nil, err
 	}
 	app := NewAppDb()
-	app.Db = &db
+	app.Db = db
 	app.DataPath = tempdir
-	blockId1 := "bcd19312-c62a-4a20-8fdd-0fd66883d314"
+	blockId1 := uint64(1)
 	_ = blockId1
 	_ = groupid
 
@@ -108,24 +106,28 @@ func TestAppDb(t *testing.T) {
 	if err != nil {
 		t.Errorf("AddMetaByTrx err: %s", err)
 	}
-	result, _ := app.GetGroupContentBySenders(groupid, []string{}, "b2a3b9aa-bd16-4e80-8497-6d95eddfec52", 2, 20, true, false)
-	target := []TrxIdNonce{}
-	target = append(target, TrxIdNonce{"c778c5d0-7fd0-4bdd-867b-cc0bd1d125eb", 0})
-	target = append(target, TrxIdNonce{"b2a3b9aa-bd16-4e80-8497-6d95eddfec52", 0})
-
-	isequal := ifTrxWithNonceSliceEqual(result, target)
+	// collecting starts after the first trx with id starttrx in the order
+	result, _ := app.GetGroupContentBySenders(groupid, []string{}, "b2a3b9aa-bd16-4e80-8497-6d95eddfec52", 20, true, false)
+	target := []string{}
+	target = append(target, "b2a3b9aa-bd16-4e80-8497-6d95eddfec52")
+	target = append(target, "c778c5d0-7fd0-4bdd-867b-cc0bd1d125eb")
+	target = append(target, "b2a3b9aa-bd16-4e80-8497-6d95eddfec52")
+
+	isequal := ifTrxIdSliceEqual(result, target)
 	if isequal == false {
 		t.Log("result", result)
 		t.Log("target", target)
 		t.Errorf("Content result not match with target.")
 	}
 
-	result, _ = app.GetGroupContentBySenders(groupid, []string{}, "b2a3b9aa-bd16-4e80-8497-6d95eddfec52", 2, 20, false, false)
-	target = []TrxIdNonce{}
-	target = append(target, TrxIdNonce{"b2a3b9aa-bd16-4e80-8497-6d95eddfec52", 1})
-	target = append(target, TrxIdNonce{"0b742adb-69dc-4c81-acea-e7aa19d6e150", 0})
+	result, _ = app.GetGroupContentBySenders(groupid, []string{}, "b2a3b9aa-bd16-4e80-8497-6d95eddfec52", 20, false, false)
+	target = []string{}
+	target = append(target, "c778c5d0-7fd0-4bdd-867b-cc0bd1d125eb")
+	target = append(target, "b2a3b9aa-bd16-4e80-8497-6d95eddfec52")
+	target = append(target, "b2a3b9aa-bd16-4e80-8497-6d95eddfec52")
+	target = append(target, "0b742adb-69dc-4c81-acea-e7aa19d6e150")
 
-	isequal = ifTrxWithNonceSliceEqual(result, target)
+	isequal = ifTrxIdSliceEqual(result, target)
 	if isequal == false {
 		t.Log("result", result)
 		t.Log("target", target)
@@ -0,0 +1,166 @@
+package appdata
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rumsystem/quorum/internal/pkg/storage"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+var REBUILD_PROGRESS_INTERVAL = uint64(1000) // report rebuild progress every n blocks
+
+const (
+	statusBlock   = "Block"
+	statusRebuild = "Rebuild" // tag of the rebuild which reset the group indexes
+)
+
+// Rebuild regenerate the app indexes of all groups from blocks saved in chainDb,
+// vertag identifies a rebuild, call it again with the same vertag to resume an interrupted rebuild,
+// a different vertag drops the existing indexes and rebuilds them from the first block.
+func (appdb *AppDb) Rebuild(vertag string, chainDb storage.QuorumStorage, prefix ...string) error {
+	if vertag == "" {
+		return errors.New("empty rebuild tag")
+	}
+
+	tops, err := getStoredTopBlocks(chainDb, prefix...)
+	if err != nil {
+		return err
+	}
+
+	groupIds := []string{}
+	for groupId := range tops {
+		groupIds = append(groupIds, groupId)
+	}
+	sort.Strings(groupIds)
+
+	for i, groupId := range groupIds {
+		appdatalog.Infof("<%s> rebuild group (%d/%d), top block <%d>", groupId, i+1, len(groupIds), tops[groupId])
+		if err := appdb.rebuildGroup(vertag, groupId, tops[groupId], chainDb, prefix...); err != nil {
+			return fmt.Errorf("rebuild group %s failed: %s", groupId, err)
+		}
+	}
+
+	return nil
+}
+
+func (appdb *AppDb) rebuildGroup(vertag string, groupId string, topBlock uint64, chainDb storage.QuorumStorage, prefix ...string) error {
+	tag, err := appdb.GetGroupStatus(groupId, statusRebuild)
+	if err != nil {
+		return err
+	}
+
+	if tag != vertag {
+		if err := appdb.resetGroupIndexes(groupId); err != nil {
+			return err
+		}
+		if err := appdb.setGroupStatus(groupId, statusRebuild, vertag); err != nil {
+			return err
+		}
+	}
+
+	blockIdStr, err := appdb.GetGroupStatus(groupId, statusBlock)
+	if err != nil {
+		return err
+	}
+	if blockIdStr == "" {
+		blockIdStr = "0"
+	}
+	lastBlock, err := strconv.ParseUint(blockIdStr, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	if lastBlock > 0 && lastBlock < topBlock {
+		appdatalog.Infof("<%s> resume rebuild from block <%d>", groupId, lastBlock+1)
+	}
+
+	for blockId := lastBlock + 1; blockId <= topBlock; blockId++ {
+		value, err := chainDb.Get([]byte(storage.GetBlockKey(groupId, blockId, prefix...)))
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			//gap in local chain, the remaining blocks will be indexed by appsync once it is repaired
+			appdatalog.Warnf("<%s> block <%d> not found, stop rebuild at block <%d>", groupId, blockId, blockId-1)
+			return nil
+		}
+
+		block := &quorumpb.Block{}
+		if err := proto.Unmarshal(value, block); err != nil {
+			return err
+		}
+
+		if err := appdb.AddMetaByTrx(block.BlockId, groupId, block.Trxs); err != nil {
+			return err
+		}
+
+		if blockId%REBUILD_PROGRESS_INTERVAL == 0 || blockId == topBlock {
+			appdatalog.Infof("<%s> rebuild progress <%d/%d>", groupId, blockId, topBlock)
+		}
+	}
+
+	return nil
+}
+
+// resetGroupIndexes drop the sync status and content indexes of a group
+func (appdb *AppDb) resetGroupIndexes(groupId string) error {
+	appdatalog.Infof("<%s> reset app indexes", groupId)
+
+	if err := appdb.Db.Delete([]byte(fmt.Sprintf("%s%s_%s", STATUS_PREFIX, groupId, statusBlock))); err != nil {
+		return err
+	}
+
+	for _, p := range []string{CNT_PREFIX, SDR_PREFIX} {
+		if _, err := appdb.Db.PrefixDelete([]byte(fmt.Sprintf("%s%s-%s", p, GRP_PREFIX, groupId))); err != nil {
+			return err
+		}
+	}
+
+	//restart the content sequence, drop the leased one without release
+	seqkey := SEQ_PREFIX + CNT_PREFIX + GRP_PREFIX + groupId
+	delete(appdb.seq, seqkey)
+	return appdb.Db.Delete([]byte(seqkey))
+}
+
+func (appdb *AppDb) setGroupStatus(groupid string, name string, value string) error {
+	key := fmt.Sprintf("%s%s_%s", STATUS_PREFIX, groupid, name)
+	return appdb.Db.Set([]byte(key), []byte(value))
+}
+
+// getStoredTopBlocks return the highest stored block id of each group in chainDb
+func getStoredTopBlocks(chainDb storage.QuorumStorage, prefix ...string) (map[string]uint64, error) {
+	tops := make(map[string]uint64)
+	blkPrefix := storage.GetBlockPrefix("", prefix...)
+
+	_, err := chainDb.PrefixForeachKey([]byte(blkPrefix), []byte(blkPrefix), false, func(k []byte, err error) error {
+		if err != nil {
+			return err
+		}
+
+		//key format: <blkPrefix><group_id>_<block_id>
+		tail := string(k[len(blkPrefix):])
+		idx := strings.LastIndex(tail, "_")
+		if idx <= 0 {
+			return nil
+		}
+
+		blockId, err := strconv.ParseUint(tail[idx+1:], 10, 64)
+		if err != nil {
+			appdatalog.Warnf("invalid block key: %s", k)
+			return nil
+		}
+
+		groupId := tail[:idx]
+		if blockId > tops[groupId] {
+			tops[groupId] = blockId
+		}
+		return nil
+	})
+
+	return tops, err
+}
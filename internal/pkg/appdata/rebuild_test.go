@@ -0,0 +1,95 @@
+package appdata
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/rumsystem/quorum/internal/pkg/storage"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+const rebuildTestSender = "CAISIQKDY1R5hZ09yG1+i/Kdk8E/KDT8Wm/PrKmgtsdtXFHXEg=="
+
+func rebuildTestTrxId(groupid string, blockId uint64) string {
+	return fmt.Sprintf("%s-0000-0000-0000-%012d", groupid, blockId)
+}
+
+func saveRebuildTestBlock(t *testing.T, chainDb storage.QuorumStorage, groupid string, blockId uint64) {
+	block := &quorumpb.Block{
+		GroupId: groupid,
+		BlockId: blockId,
+		Trxs: []*quorumpb.Trx{
+			{TrxId: rebuildTestTrxId(groupid, blockId), SenderPubkey: rebuildTestSender, Type: quorumpb.TrxType_POST},
+			{TrxId: "not-a-post", SenderPubkey: rebuildTestSender, Type: quorumpb.TrxType_APP_CONFIG},
+		},
+	}
+	value, err := proto.Marshal(block)
+	if err != nil {
+		t.Fatalf("marshal block failed: %s", err)
+	}
+	if err := chainDb.Set([]byte(storage.GetBlockKey(groupid, blockId)), value); err != nil {
+		t.Fatalf("save block failed: %s", err)
+	}
+}
+
+func checkRebuildTestGroup(t *testing.T, app *AppDb, groupid string, topBlock uint64) {
+	expected := []string{}
+	for blockId := uint64(1); blockId <= topBlock; blockId++ {
+		expected = append(expected, rebuildTestTrxId(groupid, blockId))
+	}
+	trxids, err := app.GetGroupContentBySenders(groupid, nil, "", 100, false, false)
+	if err != nil {
+		t.Fatalf("GetGroupContentBySenders failed: %s", err)
+	}
+	if !ifTrxIdSliceEqual(trxids, expected) {
+		t.Fatalf("<%s> indexed trxs <%v>, expect <%v>", groupid, trxids, expected)
+	}
+	status, err := app.GetGroupStatus(groupid, statusBlock)
+	if err != nil {
+		t.Fatalf("GetGroupStatus failed: %s", err)
+	}
+	if status != fmt.Sprint(topBlock) {
+		t.Fatalf("<%s> indexed to block <%s>, expect <%d>", groupid, status, topBlock)
+	}
+}
+
+func TestRebuild(t *testing.T) {
+	chainDb := storage.NewMemStore()
+	app := NewAppDb()
+	app.Db = storage.NewMemStore()
+
+	groupA, groupB := "aaaaaaaa", "bbbbbbbb"
+	for blockId := uint64(1); blockId <= 3; blockId++ {
+		saveRebuildTestBlock(t, chainDb, groupA, blockId)
+	}
+	//block 3 of groupB is missing
+	saveRebuildTestBlock(t, chainDb, groupB, 1)
+	saveRebuildTestBlock(t, chainDb, groupB, 2)
+	saveRebuildTestBlock(t, chainDb, groupB, 4)
+
+	if err := app.Rebuild("", chainDb); err == nil {
+		t.Fatal("rebuild without tag should fail")
+	}
+
+	if err := app.Rebuild("v1", chainDb); err != nil {
+		t.Fatalf("Rebuild failed: %s", err)
+	}
+	checkRebuildTestGroup(t, app, groupA, 3)
+	checkRebuildTestGroup(t, app, groupB, 2)
+
+	//the same tag resumes from the last indexed block
+	saveRebuildTestBlock(t, chainDb, groupB, 3)
+	if err := app.Rebuild("v1", chainDb); err != nil {
+		t.Fatalf("resume Rebuild failed: %s", err)
+	}
+	checkRebuildTestGroup(t, app, groupA, 3)
+	checkRebuildTestGroup(t, app, groupB, 4)
+
+	//a new tag drops the indexes and rebuilds them from the first block
+	if err := app.Rebuild("v2", chainDb); err != nil {
+		t.Fatalf("Rebuild with new tag failed: %s", err)
+	}
+	checkRebuildTestGroup(t, app, groupA, 3)
+	checkRebuildTestGroup(t, app, groupB, 4)
+}
@@ -0,0 +1,325 @@
+package appdata
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+)
+
+const IDX_PREFIX string = "idx_" //search index, idx_<groupid>_<term>_<inverted timestamp>_<trxid>
+const IDD_PREFIX string = "idd_" //indexed doc, idd_<groupid>_<trxid>
+
+var MAX_SEARCH_TERM_LEN = 64      // longer terms are not indexed
+var MAX_SEARCH_QUERY_TERMS = 16   // terms in a query
+var MAX_SEARCH_RESULT_NUM = 100   // results per page
+var SEARCH_SCAN_BATCH_SIZE = 1000 // index keys loaded per scan
+
+// searchable fields of an ActivityStreams activity and its object
+var searchableFields = []string{"content", "name", "summary"}
+
+type SearchDoc struct {
+	Sender    string
+	TimeStamp int64
+	Terms     []string
+}
+
+type SearchQuery struct {
+	GroupId   string
+	Keywords  string
+	Senders   []string
+	StartTime int64  //in nanoseconds, 0 for no limit
+	EndTime   int64  //in nanoseconds, 0 for no limit
+	StartTrx  string //last trx of previous page
+	Num       int
+}
+
+// Tokenize split text into lower case terms, each CJK rune is a term since there is no space between words
+func Tokenize(text string) []string {
+	terms := []string{}
+	seen := make(map[string]bool)
+	add := func(term string) {
+		if term == "" || len(term) > MAX_SEARCH_TERM_LEN || seen[term] {
+			return
+		}
+		seen[term] = true
+		terms = append(terms, term)
+	}
+
+	var word strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case isCJK(r):
+			add(word.String())
+			word.Reset()
+			add(string(r))
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			word.WriteRune(r)
+		default:
+			add(word.String())
+			word.Reset()
+		}
+	}
+	add(word.String())
+	return terms
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// GetSearchableText return content, name and summary of a post, include the ones of its object
+func GetSearchableText(data []byte) string {
+	activity := make(map[string]interface{})
+	if err := json.Unmarshal(data, &activity); err != nil {
+		return ""
+	}
+
+	texts := []string{}
+	collect := func(obj map[string]interface{}) {
+		for _, field := range searchableFields {
+			if s, ok := obj[field].(string); ok {
+				texts = append(texts, s)
+			}
+		}
+	}
+
+	collect(activity)
+	if obj, ok := activity["object"].(map[string]interface{}); ok {
+		collect(obj)
+	}
+	return strings.Join(texts, "\n")
+}
+
+// DecryptTrxData return decrypted data of a trx, posts of private group are encrypted for announced users, others are encrypted by group cipher key
+func DecryptTrxData(groupitem *quorumpb.GroupItem, trx *quorumpb.Trx) ([]byte, error) {
+	if trx.Type == quorumpb.TrxType_POST && groupitem.EncryptType == quorumpb.GroupEncryptType_PRIVATE {
+		ks := localcrypto.GetKeystore()
+		return ks.Decrypt(groupitem.GroupId, trx.Data)
+	}
+
+	ciperKey, err := hex.DecodeString(groupitem.CipherKey)
+	if err != nil {
+		return nil, err
+	}
+	return localcrypto.AesDecode(trx.Data, ciperKey)
+}
+
+func searchTermPrefix(groupid string, term string) string {
+	return fmt.Sprintf("%s%s_%s_", IDX_PREFIX, groupid, term)
+}
+
+// newer trx comes first
+func searchIndexKey(groupid string, term string, timestamp int64, trxid string) string {
+	return fmt.Sprintf("%s%020d_%s", searchTermPrefix(groupid, term), math.MaxInt64-timestamp, trxid)
+}
+
+func searchDocKey(groupid string, trxid string) string {
+	return fmt.Sprintf("%s%s_%s", IDD_PREFIX, groupid, trxid)
+}
+
+// AddSearchIndex index the text of a trx, trx already indexed is skipped
+func (appdb *AppDb) AddSearchIndex(groupid string, trx *quorumpb.Trx, text string) error {
+	docKey := searchDocKey(groupid, trx.TrxId)
+	exist, err := appdb.Db.IsExist([]byte(docKey))
+	if err != nil || exist {
+		return err
+	}
+
+	terms := Tokenize(text)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	doc := &SearchDoc{Sender: trx.SenderPubkey, TimeStamp: trx.TimeStamp, Terms: terms}
+	value, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	keys := [][]byte{[]byte(docKey)}
+	values := [][]byte{value}
+	for _, term := range terms {
+		keys = append(keys, []byte(searchIndexKey(groupid, term, trx.TimeStamp, trx.TrxId)))
+		values = append(values, nil)
+	}
+	return appdb.Db.BatchWrite(keys, values)
+}
+
+func (appdb *AppDb) getSearchDoc(groupid string, trxid string) (*SearchDoc, error) {
+	value, err := appdb.Db.Get([]byte(searchDocKey(groupid, trxid)))
+	if err != nil || value == nil {
+		return nil, err
+	}
+
+	doc := &SearchDoc{}
+	if err := json.Unmarshal(value, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// DelGroupSearchIndex remove all search index of a group
+func (appdb *AppDb) DelGroupSearchIndex(groupid string) error {
+	if _, err := appdb.Db.PrefixDelete([]byte(fmt.Sprintf("%s%s_", IDX_PREFIX, groupid))); err != nil {
+		return err
+	}
+	_, err := appdb.Db.PrefixDelete([]byte(fmt.Sprintf("%s%s_", IDD_PREFIX, groupid)))
+	return err
+}
+
+// SearchGroupContent return trx ids of posts match all keywords, newest first
+func (appdb *AppDb) SearchGroupContent(q *SearchQuery) ([]string, error) {
+	terms := Tokenize(q.Keywords)
+	if len(terms) == 0 {
+		return nil, errors.New("no keyword to search")
+	}
+	if len(terms) > MAX_SEARCH_QUERY_TERMS {
+		return nil, fmt.Errorf("too many keywords, maximum %d", MAX_SEARCH_QUERY_TERMS)
+	}
+
+	num := q.Num
+	if num <= 0 || num > MAX_SEARCH_RESULT_NUM {
+		num = MAX_SEARCH_RESULT_NUM
+	}
+
+	sendermap := make(map[string]bool)
+	for _, s := range q.Senders {
+		sendermap[s] = true
+	}
+
+	//scan index of the first term, check the others by indexed doc
+	prefix := searchTermPrefix(q.GroupId, terms[0])
+	seek := prefix
+	if q.EndTime > 0 {
+		seek = fmt.Sprintf("%s%020d", prefix, math.MaxInt64-q.EndTime)
+	}
+
+	if q.StartTrx != "" {
+		doc, err := appdb.getSearchDoc(q.GroupId, q.StartTrx)
+		if err != nil {
+			return nil, err
+		}
+		if doc == nil {
+			return nil, fmt.Errorf("trx %s is not indexed", q.StartTrx)
+		}
+		//start after it
+		if startKey := searchIndexKey(q.GroupId, terms[0], doc.TimeStamp, q.StartTrx) + "\x00"; startKey > seek {
+			seek = startKey
+		}
+	}
+
+	trxids := []string{}
+	for len(trxids) < num {
+		keys := []string{}
+		_, err := appdb.Db.PrefixForeachKey([]byte(seek), []byte(prefix), false, func(k []byte, err error) error {
+			if err != nil {
+				return err
+			}
+			keys = append(keys, string(k))
+			if len(keys) == SEARCH_SCAN_BATCH_SIZE {
+				// use this to break loop
+				return errors.New("OK")
+			}
+			return nil
+		})
+		if err != nil && err.Error() != "OK" {
+			return nil, err
+		}
+		if len(keys) == 0 {
+			break
+		}
+
+		for _, key := range keys {
+			parts := strings.SplitN(key[len(prefix):], "_", 2)
+			if len(parts) != 2 {
+				appdatalog.Warnf("invalid search index key: %s", key)
+				continue
+			}
+			inverted, err := strconv.ParseInt(parts[0], 10, 64)
+			if err != nil {
+				appdatalog.Warnf("invalid search index key: %s", key)
+				continue
+			}
+			if q.StartTime > 0 && math.MaxInt64-inverted < q.StartTime {
+				return trxids, nil
+			}
+
+			trxid := parts[1]
+			doc, err := appdb.getSearchDoc(q.GroupId, trxid)
+			if err != nil {
+				return nil, err
+			}
+			if doc == nil || !hasAllTerms(doc, terms[1:]) {
+				continue
+			}
+			if len(sendermap) > 0 && !sendermap[doc.Sender] {
+				continue
+			}
+
+			trxids = append(trxids, trxid)
+			if len(trxids) == num {
+				break
+			}
+		}
+
+		seek = keys[len(keys)-1] + "\x00"
+	}
+
+	return trxids, nil
+}
+
+func hasAllTerms(doc *SearchDoc, terms []string) bool {
+	docterms := make(map[string]bool)
+	for _, t := range doc.Terms {
+		docterms[t] = true
+	}
+	for _, t := range terms {
+		if !docterms[t] {
+			return false
+		}
+	}
+	return true
+}
+
+// indexBlockTrxs add posts of a block to search index
+func (appsync *AppSync) indexBlockTrxs(groupid string, block *quorumpb.Block) {
+	var groupitem *quorumpb.GroupItem
+	for _, trx := range block.Trxs {
+		if trx.Type != quorumpb.TrxType_POST {
+			continue
+		}
+
+		if groupitem == nil {
+			item, err := appsync.groupmgr.GetGroupItem(groupid)
+			if err != nil {
+				appsynclog.Warnf("index group %s failed: %s", groupid, err)
+				return
+			}
+			groupitem = item
+		}
+
+		data, err := DecryptTrxData(groupitem, trx)
+		if err != nil {
+			//private post not for me
+			appsynclog.Debugf("can not decrypt trx %s for search index: %s", trx.TrxId, err)
+			continue
+		}
+
+		text := GetSearchableText(data)
+		if text == "" {
+			continue
+		}
+
+		if err := appsync.appdb.AddSearchIndex(groupid, trx, text); err != nil {
+			appsynclog.Warnf("index trx %s failed: %s", trx.TrxId, err)
+		}
+	}
+}
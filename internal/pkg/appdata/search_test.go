@@ -0,0 +1,130 @@
+package appdata
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rumsystem/quorum/internal/pkg/storage"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+)
+
+func TestTokenize(t *testing.T) {
+	long := strings.Repeat("a", MAX_SEARCH_TERM_LEN+1)
+	terms := Tokenize("Hello, hello World! 你好quorum 2023 " + long)
+	expected := []string{"hello", "world", "你", "好", "quorum", "2023"}
+	if !reflect.DeepEqual(terms, expected) {
+		t.Fatalf("terms <%v>, expect <%v>", terms, expected)
+	}
+}
+
+func TestGetSearchableText(t *testing.T) {
+	data := []byte(`{"type":"Create","name":"activity","object":{"type":"Note","content":"note content","summary":"note summary","url":"not searchable"}}`)
+	text := GetSearchableText(data)
+	for _, s := range []string{"activity", "note content", "note summary"} {
+		if !strings.Contains(text, s) {
+			t.Fatalf("searchable text <%s> does not contain <%s>", text, s)
+		}
+	}
+	if strings.Contains(text, "not searchable") {
+		t.Fatalf("searchable text <%s> contains other fields", text)
+	}
+	if GetSearchableText([]byte("not json")) != "" {
+		t.Fatal("invalid post should have no searchable text")
+	}
+}
+
+func newSearchTestDb(t *testing.T, groupid string) *AppDb {
+	app := NewAppDb()
+	app.Db = storage.NewMemStore()
+	posts := []struct {
+		sender string
+		text   string
+	}{
+		{"alice", "quorum search index"},
+		{"bob", "search for posts"},
+		{"alice", "quorum consensus"},
+		{"bob", "quorum search by bob"},
+		{"alice", "quorum search again"},
+	}
+	for i, p := range posts {
+		trx := &quorumpb.Trx{TrxId: fmt.Sprintf("trx%d", i), SenderPubkey: p.sender, TimeStamp: int64(i + 1)}
+		if err := app.AddSearchIndex(groupid, trx, p.text); err != nil {
+			t.Fatalf("AddSearchIndex failed: %s", err)
+		}
+	}
+	return app
+}
+
+func TestSearchGroupContent(t *testing.T) {
+	groupid := "group1"
+	app := newSearchTestDb(t, groupid)
+	batchSize := SEARCH_SCAN_BATCH_SIZE
+	SEARCH_SCAN_BATCH_SIZE = 2
+	defer func() { SEARCH_SCAN_BATCH_SIZE = batchSize }()
+
+	for _, tc := range []struct {
+		name     string
+		query    SearchQuery
+		expected []string
+	}{
+		{"all terms, newest first", SearchQuery{Keywords: "Quorum search"}, []string{"trx4", "trx3", "trx0"}},
+		{"senders", SearchQuery{Keywords: "quorum search", Senders: []string{"alice"}}, []string{"trx4", "trx0"}},
+		{"time range", SearchQuery{Keywords: "search", StartTime: 2, EndTime: 4}, []string{"trx3", "trx1"}},
+		{"first page", SearchQuery{Keywords: "search", Num: 2}, []string{"trx4", "trx3"}},
+		{"next page", SearchQuery{Keywords: "search", Num: 2, StartTrx: "trx3"}, []string{"trx1", "trx0"}},
+		{"no match", SearchQuery{Keywords: "quorum missing"}, []string{}},
+	} {
+		tc.query.GroupId = groupid
+		trxids, err := app.SearchGroupContent(&tc.query)
+		if err != nil {
+			t.Fatalf("%s: SearchGroupContent failed: %s", tc.name, err)
+		}
+		if !reflect.DeepEqual(trxids, tc.expected) {
+			t.Fatalf("%s: got <%v>, expect <%v>", tc.name, trxids, tc.expected)
+		}
+	}
+
+	if _, err := app.SearchGroupContent(&SearchQuery{GroupId: groupid, Keywords: "!!"}); err == nil {
+		t.Fatal("query without keywords should fail")
+	}
+	if _, err := app.SearchGroupContent(&SearchQuery{GroupId: groupid, Keywords: "search", StartTrx: "unknown"}); err == nil {
+		t.Fatal("query starting from a trx not indexed should fail")
+	}
+}
+
+func TestAddSearchIndexOnce(t *testing.T) {
+	groupid := "group1"
+	app := newSearchTestDb(t, groupid)
+
+	//a trx indexed again keeps its first terms
+	trx := &quorumpb.Trx{TrxId: "trx0", SenderPubkey: "alice", TimeStamp: 1}
+	if err := app.AddSearchIndex(groupid, trx, "replaced"); err != nil {
+		t.Fatalf("AddSearchIndex failed: %s", err)
+	}
+	trxids, err := app.SearchGroupContent(&SearchQuery{GroupId: groupid, Keywords: "replaced"})
+	if err != nil || len(trxids) != 0 {
+		t.Fatalf("trx indexed twice, got <%v> <%v>", trxids, err)
+	}
+}
+
+func TestDelGroupSearchIndex(t *testing.T) {
+	app := newSearchTestDb(t, "group1")
+	trx := &quorumpb.Trx{TrxId: "other", SenderPubkey: "alice", TimeStamp: 1}
+	if err := app.AddSearchIndex("group2", trx, "quorum"); err != nil {
+		t.Fatalf("AddSearchIndex failed: %s", err)
+	}
+
+	if err := app.DelGroupSearchIndex("group1"); err != nil {
+		t.Fatalf("DelGroupSearchIndex failed: %s", err)
+	}
+	trxids, err := app.SearchGroupContent(&SearchQuery{GroupId: "group1", Keywords: "quorum"})
+	if err != nil || len(trxids) != 0 {
+		t.Fatalf("index of deleted group found <%v> <%v>", trxids, err)
+	}
+	trxids, err = app.SearchGroupContent(&SearchQuery{GroupId: "group2", Keywords: "quorum"})
+	if err != nil || !reflect.DeepEqual(trxids, []string{"other"}) {
+		t.Fatalf("index of other groups should be kept, got <%v> <%v>", trxids, err)
+	}
+}
@@ -79,6 +79,10 @@ func (appsync *AppSync) ParseBlockTrxs(groupid string, block *quorumpb.Block) er
 		return err
 	}
 
+	if appsync.appdb.EnableSearchIndex {
+		appsync.indexBlockTrxs(groupid, block)
+	}
+
 	pushOnChainTrxQueue(block.Trxs)
 
 	return nil
@@ -11,7 +11,7 @@ import (
 
 func CreateAppDb(path string) (*AppDb, error) {
 	ctx := context.Background()
-	db, err := storage.NewStore(ctx, path, "appdb")
+	db, err := storage.OpenStore(ctx, path, "appdb")
 	if err != nil {
 		return nil, err
 	}
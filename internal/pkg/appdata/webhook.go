@@ -0,0 +1,342 @@
+package appdata
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	chain "github.com/rumsystem/quorum/internal/pkg/chainsdk/core"
+	"github.com/rumsystem/quorum/internal/pkg/logging"
+	"github.com/rumsystem/quorum/internal/pkg/nodectx"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+)
+
+var webhooklog = logging.Logger("webhook")
+
+var WEBHOOK_WATCH_INTERVAL = 1 * time.Second        // interval to check new blocks
+var WEBHOOK_TIMEOUT = 10 * time.Second              // timeout of each delivery
+var WEBHOOK_MAX_EVENTS = 100                        // events delivered to a webhook in each round
+var WEBHOOK_MAX_RETRY = 10                          // attempts of an event before give up
+var WEBHOOK_RETRY_BASE_DELAY = 1 * time.Second      // doubled after each failed attempt
+var WEBHOOK_RETRY_MAX_DELAY = 5 * time.Minute       // upper bound of retry delay
+var WEBHOOK_DELIVERY_RETENTION = 7 * 24 * time.Hour // delivery logs older than this are pruned
+var WEBHOOK_DELIVERY_PRUNE_INTERVAL = 1 * time.Hour // interval to prune delivery logs
+
+const (
+	WEBHOOK_HEADER_SIGNATURE = "X-Quorum-Signature" // sha256=<hex of hmac-sha256(secret, timestamp + "." + body)>
+	WEBHOOK_HEADER_TIMESTAMP = "X-Quorum-Timestamp" // unix seconds
+	WEBHOOK_HEADER_DELIVERY  = "X-Quorum-Delivery"  // id of the event, same for retries
+)
+
+// WebhookEvent is the body posted to webhook url
+type WebhookEvent struct {
+	WebhookId  string        `json:"webhook_id"`
+	DeliveryId string        `json:"delivery_id"`
+	GroupId    string        `json:"group_id"`
+	BlockId    uint64        `json:"block_id"`
+	TrxIndex   int           `json:"trx_index"`
+	Trx        *quorumpb.Trx `json:"trx"`
+}
+
+// webhookChain reads blocks of joined groups for webhooks, implemented by nodeWebhookChain
+type webhookChain interface {
+	GetCurrentBlockId(groupId string) (uint64, bool) // false if the group is not joined
+	GetBlock(groupId string, blockId uint64) (*quorumpb.Block, error)
+	GetPrunedTo(groupId string) (uint64, error) // blocks to it only have headers
+}
+
+type nodeWebhookChain struct {
+	nodename string
+}
+
+func (c *nodeWebhookChain) GetCurrentBlockId(groupId string) (uint64, bool) {
+	group, ok := chain.GetGroupMgr().GetLocalGroup(groupId)
+	if !ok {
+		return 0, false
+	}
+	return group.GetCurrentBlockId(), true
+}
+
+func (c *nodeWebhookChain) GetBlock(groupId string, blockId uint64) (*quorumpb.Block, error) {
+	return nodectx.GetNodeCtx().GetChainStorage().GetBlock(groupId, blockId, false, c.nodename)
+}
+
+func (c *nodeWebhookChain) GetPrunedTo(groupId string) (uint64, error) {
+	state, err := nodectx.GetNodeCtx().GetChainStorage().GetPruneState(groupId, c.nodename)
+	if err != nil {
+		return 0, err
+	}
+	return state.PrunedTo, nil
+}
+
+// WebhookAgent follows the chain of each webhook's group by its own cursor, so no event is lost when node or receiver restarts,
+// events of a webhook are delivered in order, at least once. each webhook is delivered by its own worker, so a slow receiver
+// does not hold back the others
+type WebhookAgent struct {
+	appdb  *AppDb
+	chain  webhookChain
+	client *http.Client
+
+	mu      sync.Mutex
+	running map[string]bool // webhooks being delivered by a worker
+}
+
+func newWebhookAgent(appdb *AppDb, chain webhookChain) *WebhookAgent {
+	return &WebhookAgent{
+		appdb:   appdb,
+		chain:   chain,
+		client:  &http.Client{Timeout: WEBHOOK_TIMEOUT},
+		running: make(map[string]bool),
+	}
+}
+
+// InitWebhookAgent starts delivering events to registered webhooks until ctx is done
+func InitWebhookAgent(ctx context.Context, appdb *AppDb, nodename string) *WebhookAgent {
+	webhookAgent := newWebhookAgent(appdb, &nodeWebhookChain{nodename: nodename})
+
+	go func() {
+		ticker := time.NewTicker(WEBHOOK_WATCH_INTERVAL)
+		defer ticker.Stop()
+		lastPrune := time.Time{}
+		for {
+			select {
+			case <-ctx.Done():
+				webhooklog.Debug("webhook agent stopped")
+				return
+			case <-ticker.C:
+				webhookAgent.deliverAll()
+				if time.Since(lastPrune) > WEBHOOK_DELIVERY_PRUNE_INTERVAL {
+					if _, err := appdb.PruneWebhookDeliveries(time.Now().Add(-WEBHOOK_DELIVERY_RETENTION)); err != nil {
+						webhooklog.Warnf("prune webhook delivery logs failed: %s", err)
+					}
+					lastPrune = time.Now()
+				}
+			}
+		}
+	}()
+
+	return webhookAgent
+}
+
+func (agent *WebhookAgent) deliverAll() {
+	webhooks, err := agent.appdb.GetWebhooks("")
+	if err != nil {
+		webhooklog.Errorf("get webhooks failed: %s", err)
+		return
+	}
+
+	now := time.Now().UnixNano()
+	for _, w := range webhooks {
+		if w.RetryAt > now || !agent.startWorker(w.Id) {
+			continue
+		}
+		go func(w *Webhook) {
+			defer agent.stopWorker(w.Id)
+			agent.deliverPending(w)
+		}(w)
+	}
+}
+
+// startWorker return false if the webhook is being delivered by another worker
+func (agent *WebhookAgent) startWorker(webhookId string) bool {
+	agent.mu.Lock()
+	defer agent.mu.Unlock()
+	if agent.running[webhookId] {
+		return false
+	}
+	agent.running[webhookId] = true
+	return true
+}
+
+func (agent *WebhookAgent) stopWorker(webhookId string) {
+	agent.mu.Lock()
+	defer agent.mu.Unlock()
+	delete(agent.running, webhookId)
+}
+
+// deliverPending delivers events after the cursor of w, it stops at a failed event which will be retried later
+func (agent *WebhookAgent) deliverPending(w *Webhook) {
+	currBlockId, ok := agent.chain.GetCurrentBlockId(w.GroupId)
+	if !ok {
+		return
+	}
+	prunedTo, err := agent.chain.GetPrunedTo(w.GroupId)
+	if err != nil {
+		webhooklog.Errorf("<%s> get prune state for webhook <%s> failed: %s", w.GroupId, w.Id, err)
+		return
+	}
+
+	changed := false
+	defer func() {
+		if !changed {
+			return
+		}
+		if err := agent.appdb.saveWebhookProgress(w); err != nil {
+			webhooklog.Errorf("<%s> save progress of webhook <%s> failed: %s", w.GroupId, w.Id, err)
+		}
+	}()
+
+	var block *quorumpb.Block
+	for sent := 0; sent < WEBHOOK_MAX_EVENTS; {
+		if w.NextBlock > currBlockId {
+			return
+		}
+		if w.NextBlock > 0 && w.NextBlock <= prunedTo {
+			agent.skipPruned(w, prunedTo)
+			changed = true
+			continue
+		}
+		if block == nil || block.BlockId != w.NextBlock {
+			block, err = agent.chain.GetBlock(w.GroupId, w.NextBlock)
+			if err != nil {
+				webhooklog.Errorf("<%s> get block %d for webhook <%s> failed: %s", w.GroupId, w.NextBlock, w.Id, err)
+				return
+			}
+		}
+
+		if w.NextIndex >= len(block.Trxs) {
+			w.NextBlock++
+			w.NextIndex = 0
+			changed = true
+			continue
+		}
+
+		trx := block.Trxs[w.NextIndex]
+		if !w.MatchTrxType(trx.Type.String()) {
+			w.NextIndex++
+			changed = true
+			continue
+		}
+
+		sent++
+		changed = true
+		w.Attempts++
+		if agent.deliver(w, block.BlockId, trx) {
+			w.NextIndex++
+			w.Attempts = 0
+			w.RetryAt = 0
+			continue
+		}
+
+		if w.Attempts >= WEBHOOK_MAX_RETRY {
+			webhooklog.Warnf("<%s> webhook <%s> give up trx <%s> after %d attempts", w.GroupId, w.Id, trx.TrxId, w.Attempts)
+			w.NextIndex++
+			w.Attempts = 0
+			w.RetryAt = 0
+			continue
+		}
+
+		delay := WEBHOOK_RETRY_BASE_DELAY << (w.Attempts - 1)
+		if delay > WEBHOOK_RETRY_MAX_DELAY || delay <= 0 {
+			delay = WEBHOOK_RETRY_MAX_DELAY
+		}
+		w.RetryAt = time.Now().Add(delay).UnixNano()
+		return
+	}
+}
+
+// skipPruned moves the cursor of w after the pruned blocks, which only have headers, and records the skipped events
+// as undeliverable in delivery log
+func (agent *WebhookAgent) skipPruned(w *Webhook, prunedTo uint64) {
+	webhooklog.Warnf("<%s> webhook <%s> skip pruned blocks <%d> to <%d>", w.GroupId, w.Id, w.NextBlock, prunedTo)
+	log := &WebhookDelivery{
+		WebhookId:  w.Id,
+		DeliveryId: fmt.Sprintf("%d_%d", w.NextBlock, w.NextIndex),
+		GroupId:    w.GroupId,
+		BlockId:    w.NextBlock,
+		State:      WebhookDeliveryPruned,
+		Error:      fmt.Sprintf("blocks %d to %d are pruned, their events are not delivered", w.NextBlock, prunedTo),
+	}
+	if err := agent.appdb.AddWebhookDelivery(log); err != nil {
+		webhooklog.Warnf("<%s> add webhook delivery log failed: %s", w.GroupId, err)
+	}
+
+	w.NextBlock = prunedTo + 1
+	w.NextIndex = 0
+	w.Attempts = 0
+	w.RetryAt = 0
+}
+
+// deliver posts one event to the webhook url and records the attempt in delivery log
+func (agent *WebhookAgent) deliver(w *Webhook, blockId uint64, trx *quorumpb.Trx) bool {
+	event := &WebhookEvent{
+		WebhookId:  w.Id,
+		DeliveryId: fmt.Sprintf("%d_%d", blockId, w.NextIndex),
+		GroupId:    w.GroupId,
+		BlockId:    blockId,
+		TrxIndex:   w.NextIndex,
+		Trx:        trx,
+	}
+	log := &WebhookDelivery{
+		WebhookId:  w.Id,
+		DeliveryId: event.DeliveryId,
+		GroupId:    w.GroupId,
+		BlockId:    blockId,
+		TrxId:      trx.TrxId,
+		Attempt:    w.Attempts,
+		State:      WebhookDeliverySuccess,
+	}
+
+	statusCode, err := agent.post(w, event)
+	log.StatusCode = statusCode
+	if err != nil {
+		log.Error = err.Error()
+		log.State = WebhookDeliveryRetry
+		if w.Attempts >= WEBHOOK_MAX_RETRY {
+			log.State = WebhookDeliveryFail
+		}
+		webhooklog.Debugf("<%s> deliver trx <%s> to webhook <%s> failed: %s", w.GroupId, trx.TrxId, w.Id, err)
+	}
+
+	if err := agent.appdb.AddWebhookDelivery(log); err != nil {
+		webhooklog.Warnf("<%s> add webhook delivery log failed: %s", w.GroupId, err)
+	}
+	return log.State == WebhookDeliverySuccess
+}
+
+func (agent *WebhookAgent) post(w *Webhook, event *WebhookEvent) (int, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequest(http.MethodPost, w.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WEBHOOK_HEADER_TIMESTAMP, timestamp)
+	req.Header.Set(WEBHOOK_HEADER_DELIVERY, event.DeliveryId)
+	req.Header.Set(WEBHOOK_HEADER_SIGNATURE, "sha256="+WebhookSignature(w.Secret, timestamp, body))
+
+	resp, err := agent.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// WebhookSignature signs timestamp and body with secret, receivers should compute it the same way to verify a delivery
+func WebhookSignature(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,227 @@
+package appdata
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rumsystem/quorum/internal/pkg/storage"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+)
+
+type fakeWebhookChain struct {
+	currBlockId uint64
+	prunedTo    uint64
+	blocks      map[uint64]*quorumpb.Block
+}
+
+func (c *fakeWebhookChain) GetCurrentBlockId(groupId string) (uint64, bool) {
+	return c.currBlockId, groupId == "whgroup"
+}
+
+func (c *fakeWebhookChain) GetBlock(groupId string, blockId uint64) (*quorumpb.Block, error) {
+	if block, ok := c.blocks[blockId]; ok {
+		return block, nil
+	}
+	return nil, errors.New("block not found")
+}
+
+func (c *fakeWebhookChain) GetPrunedTo(groupId string) (uint64, error) {
+	return c.prunedTo, nil
+}
+
+// webhookReceiver records trx ids of events with valid signature, it answers with status
+type webhookReceiver struct {
+	mu     sync.Mutex
+	secret string
+	status int
+	block  chan struct{} // requests wait till it is closed if it is not nil
+	trxIds []string
+	calls  int
+}
+
+func (r *webhookReceiver) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	r.calls++
+	block := r.block
+	r.mu.Unlock()
+	if block != nil {
+		<-block
+	}
+
+	body, _ := io.ReadAll(req.Body)
+	signature := "sha256=" + WebhookSignature(r.secret, req.Header.Get(WEBHOOK_HEADER_TIMESTAMP), body)
+	if req.Header.Get(WEBHOOK_HEADER_SIGNATURE) != signature {
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	event := &WebhookEvent{}
+	if err := json.Unmarshal(body, event); err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.status == http.StatusOK {
+		r.trxIds = append(r.trxIds, event.Trx.TrxId)
+	}
+	rw.WriteHeader(r.status)
+}
+
+func (r *webhookReceiver) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.trxIds...)
+}
+
+func newTestWebhookAgent(t *testing.T) (*WebhookAgent, *fakeWebhookChain) {
+	c := &fakeWebhookChain{currBlockId: 3, blocks: make(map[uint64]*quorumpb.Block)}
+	trxs := map[uint64][]*quorumpb.Trx{
+		1: {{TrxId: "a", Type: quorumpb.TrxType_POST}, {TrxId: "b", Type: quorumpb.TrxType_ANNOUNCE}},
+		2: {{TrxId: "c", Type: quorumpb.TrxType_POST}},
+		3: {{TrxId: "d", Type: quorumpb.TrxType_POST}},
+	}
+	for blockId, blockTrxs := range trxs {
+		c.blocks[blockId] = &quorumpb.Block{GroupId: "whgroup", BlockId: blockId, Trxs: blockTrxs}
+	}
+
+	appdb := NewAppDb()
+	appdb.Db = storage.NewMemStore()
+	return newWebhookAgent(appdb, c), c
+}
+
+func addTestWebhook(t *testing.T, agent *WebhookAgent, id string, receiver *webhookReceiver) *Webhook {
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+	w := &Webhook{Id: id, GroupId: "whgroup", Url: server.URL, TrxTypes: []string{"POST"}, Secret: receiver.secret, NextBlock: 1}
+	if err := agent.appdb.AddWebhook(w); err != nil {
+		t.Fatalf("AddWebhook failed: %s", err)
+	}
+	return w
+}
+
+func checkWebhookDeliveries(t *testing.T, agent *WebhookAgent, webhookId string, state string, expected int) []*WebhookDelivery {
+	deliveries, err := agent.appdb.GetWebhookDeliveries(webhookId, state, 100)
+	if err != nil {
+		t.Fatalf("GetWebhookDeliveries failed: %s", err)
+	}
+	if len(deliveries) != expected {
+		t.Fatalf("<%d> %s deliveries, expect <%d>", len(deliveries), state, expected)
+	}
+	return deliveries
+}
+
+func TestWebhookDeliverInOrder(t *testing.T) {
+	agent, _ := newTestWebhookAgent(t)
+	receiver := &webhookReceiver{secret: "whsecret", status: http.StatusOK}
+	w := addTestWebhook(t, agent, "wh1", receiver)
+
+	agent.deliverPending(w)
+	if received := receiver.received(); len(received) != 3 || received[0] != "a" || received[1] != "c" || received[2] != "d" {
+		t.Fatalf("received <%v>, expect posts in order", received)
+	}
+	stored, err := agent.appdb.GetWebhook("whgroup", "wh1")
+	if err != nil {
+		t.Fatalf("GetWebhook failed: %s", err)
+	}
+	if stored.NextBlock != 4 || stored.NextIndex != 0 {
+		t.Fatalf("cursor <%d, %d>, expect <4, 0>", stored.NextBlock, stored.NextIndex)
+	}
+	checkWebhookDeliveries(t, agent, "wh1", WebhookDeliverySuccess, 3)
+}
+
+func TestWebhookRetry(t *testing.T) {
+	agent, _ := newTestWebhookAgent(t)
+	receiver := &webhookReceiver{secret: "whsecret", status: http.StatusInternalServerError}
+	w := addTestWebhook(t, agent, "wh1", receiver)
+
+	maxRetry := WEBHOOK_MAX_RETRY
+	WEBHOOK_MAX_RETRY = 2
+	defer func() { WEBHOOK_MAX_RETRY = maxRetry }()
+
+	agent.deliverPending(w)
+	if w.NextBlock != 1 || w.NextIndex != 0 || w.Attempts != 1 || w.RetryAt <= time.Now().UnixNano() {
+		t.Fatalf("failed event is not kept for retry <%+v>", w)
+	}
+	checkWebhookDeliveries(t, agent, "wh1", WebhookDeliveryRetry, 1)
+
+	//give up after WEBHOOK_MAX_RETRY attempts, the next event is tried
+	w.RetryAt = 0
+	agent.deliverPending(w)
+	checkWebhookDeliveries(t, agent, "wh1", WebhookDeliveryFail, 1)
+	if w.NextBlock != 2 || w.NextIndex != 0 || w.Attempts != 1 {
+		t.Fatalf("failed event is not skipped <%+v>", w)
+	}
+}
+
+func TestWebhookSkipPrunedBlocks(t *testing.T) {
+	agent, c := newTestWebhookAgent(t)
+	c.prunedTo = 2
+	receiver := &webhookReceiver{secret: "whsecret", status: http.StatusOK}
+	w := addTestWebhook(t, agent, "wh1", receiver)
+
+	agent.deliverPending(w)
+	if received := receiver.received(); len(received) != 1 || received[0] != "d" {
+		t.Fatalf("received <%v>, expect events after pruned blocks", received)
+	}
+	deliveries := checkWebhookDeliveries(t, agent, "wh1", WebhookDeliveryPruned, 1)
+	if deliveries[0].BlockId != 1 {
+		t.Fatalf("pruned delivery from block <%d>, expect 1", deliveries[0].BlockId)
+	}
+	if w.NextBlock != 4 {
+		t.Fatalf("cursor <%d>, expect 4", w.NextBlock)
+	}
+}
+
+func TestWebhookWorkers(t *testing.T) {
+	agent, _ := newTestWebhookAgent(t)
+	slow := &webhookReceiver{secret: "slow", status: http.StatusOK, block: make(chan struct{})}
+	fast := &webhookReceiver{secret: "fast", status: http.StatusOK}
+	addTestWebhook(t, agent, "slow", slow)
+	addTestWebhook(t, agent, "fast", fast)
+
+	//a slow receiver does not hold back the others
+	agent.deliverAll()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(fast.received()) < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if received := fast.received(); len(received) != 3 {
+		t.Fatalf("fast webhook received <%v> while the slow one is blocked", received)
+	}
+
+	//the slow webhook is not delivered again while its worker is running
+	agent.deliverAll()
+	time.Sleep(50 * time.Millisecond)
+	slow.mu.Lock()
+	calls := slow.calls
+	slow.mu.Unlock()
+	if calls != 1 {
+		t.Fatalf("slow webhook called <%d> times, expect 1", calls)
+	}
+
+	close(slow.block)
+	deadline = time.Now().Add(5 * time.Second)
+	for len(slow.received()) < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if received := slow.received(); len(received) != 3 {
+		t.Fatalf("slow webhook received <%v>", received)
+	}
+	for time.Now().Before(deadline) {
+		agent.mu.Lock()
+		running := len(agent.running)
+		agent.mu.Unlock()
+		if running == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("webhook workers are not stopped")
+}
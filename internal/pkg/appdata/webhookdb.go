@@ -0,0 +1,255 @@
+package appdata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const WHK_PREFIX string = "whk_" //webhook, whk_<groupid>_<webhookid>
+const WHL_PREFIX string = "whl_" //webhook delivery log, whl_<webhookid>_<timestamp>
+
+const (
+	WebhookDeliverySuccess = "SUCCESS"
+	WebhookDeliveryRetry   = "RETRY"  // failed, will be retried
+	WebhookDeliveryFail    = "FAIL"   // failed and given up, the event is skipped
+	WebhookDeliveryPruned  = "PRUNED" // blocks from BlockId are pruned before delivered, their events are skipped
+)
+
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// webhookMu serializes changes of webhooks between api and delivery agent
+var webhookMu sync.Mutex
+
+type Webhook struct {
+	Id        string   `json:"id"`
+	GroupId   string   `json:"group_id"`
+	Url       string   `json:"url"`
+	TrxTypes  []string `json:"trx_types"` // empty for all
+	Secret    string   `json:"secret"`    // key of HMAC-SHA256 signature
+	CreatedAt int64    `json:"created_at"`
+
+	// delivery cursor, the next event is the trx at NextIndex of block NextBlock
+	NextBlock uint64 `json:"next_block"`
+	NextIndex int    `json:"next_index"`
+
+	// retry state of the next event
+	Attempts int   `json:"attempts"`
+	RetryAt  int64 `json:"retry_at"` // in nanoseconds, 0 for no delay
+
+	// bumped when webhook is changed by api, so progress of a stale copy is dropped
+	Version uint64 `json:"version"`
+}
+
+type WebhookDelivery struct {
+	WebhookId  string `json:"webhook_id"`
+	DeliveryId string `json:"delivery_id"`
+	GroupId    string `json:"group_id"`
+	BlockId    uint64 `json:"block_id"`
+	TrxId      string `json:"trx_id"`
+	Attempt    int    `json:"attempt"`
+	State      string `json:"state"`
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error"`
+	CreatedAt  int64  `json:"created_at"` // in nanoseconds
+}
+
+func webhookKey(groupId string, webhookId string) []byte {
+	return []byte(fmt.Sprintf("%s%s_%s", WHK_PREFIX, groupId, webhookId))
+}
+
+func webhookDeliveryPrefix(webhookId string) []byte {
+	return []byte(fmt.Sprintf("%s%s_", WHL_PREFIX, webhookId))
+}
+
+func webhookDeliveryKey(webhookId string, timestamp int64) []byte {
+	return []byte(fmt.Sprintf("%s%s_%020d", WHL_PREFIX, webhookId, timestamp))
+}
+
+func (w *Webhook) MatchTrxType(trxType string) bool {
+	if len(w.TrxTypes) == 0 {
+		return true
+	}
+	for _, t := range w.TrxTypes {
+		if t == trxType {
+			return true
+		}
+	}
+	return false
+}
+
+func (appdb *AppDb) AddWebhook(w *Webhook) error {
+	webhookMu.Lock()
+	defer webhookMu.Unlock()
+
+	return appdb.setWebhook(w)
+}
+
+func (appdb *AppDb) GetWebhook(groupId string, webhookId string) (*Webhook, error) {
+	value, err := appdb.Db.Get(webhookKey(groupId, webhookId))
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, ErrWebhookNotFound
+	}
+
+	w := &Webhook{}
+	if err := json.Unmarshal(value, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// GetWebhooks returns webhooks of a group, or all webhooks if groupId is empty
+func (appdb *AppDb) GetWebhooks(groupId string) ([]*Webhook, error) {
+	prefix := WHK_PREFIX
+	if groupId != "" {
+		prefix = fmt.Sprintf("%s%s_", WHK_PREFIX, groupId)
+	}
+
+	webhooks := []*Webhook{}
+	err := appdb.Db.PrefixForeach([]byte(prefix), func(k []byte, v []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		w := &Webhook{}
+		if err := json.Unmarshal(v, w); err != nil {
+			appdatalog.Warnf("unmarshal webhook <%s> failed: %s", k, err)
+			return nil
+		}
+		webhooks = append(webhooks, w)
+		return nil
+	})
+	return webhooks, err
+}
+
+// ReplayWebhook moves cursor of a webhook back (or forward) to the first trx of fromBlock
+func (appdb *AppDb) ReplayWebhook(groupId string, webhookId string, fromBlock uint64) (*Webhook, error) {
+	webhookMu.Lock()
+	defer webhookMu.Unlock()
+
+	w, err := appdb.GetWebhook(groupId, webhookId)
+	if err != nil {
+		return nil, err
+	}
+	w.NextBlock = fromBlock
+	w.NextIndex = 0
+	w.Attempts = 0
+	w.RetryAt = 0
+	w.Version++
+	return w, appdb.setWebhook(w)
+}
+
+func (appdb *AppDb) RemoveWebhook(groupId string, webhookId string) error {
+	webhookMu.Lock()
+	defer webhookMu.Unlock()
+
+	if _, err := appdb.GetWebhook(groupId, webhookId); err != nil {
+		return err
+	}
+	if err := appdb.Db.Delete(webhookKey(groupId, webhookId)); err != nil {
+		return err
+	}
+	_, err := appdb.Db.PrefixDelete(webhookDeliveryPrefix(webhookId))
+	return err
+}
+
+func (appdb *AppDb) RemoveGroupWebhooks(groupId string) error {
+	webhooks, err := appdb.GetWebhooks(groupId)
+	if err != nil {
+		return err
+	}
+	for _, w := range webhooks {
+		if err := appdb.RemoveWebhook(groupId, w.Id); err != nil && err != ErrWebhookNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveWebhookProgress saves cursor and retry state of w, it is dropped if the webhook was changed or removed meanwhile
+func (appdb *AppDb) saveWebhookProgress(w *Webhook) error {
+	webhookMu.Lock()
+	defer webhookMu.Unlock()
+
+	stored, err := appdb.GetWebhook(w.GroupId, w.Id)
+	if err != nil {
+		if err == ErrWebhookNotFound {
+			return nil
+		}
+		return err
+	}
+	if stored.Version != w.Version {
+		return nil
+	}
+	return appdb.setWebhook(w)
+}
+
+func (appdb *AppDb) setWebhook(w *Webhook) error {
+	value, err := json.Marshal(w)
+	if err != nil {
+		return err
+	}
+	return appdb.Db.Set(webhookKey(w.GroupId, w.Id), value)
+}
+
+func (appdb *AppDb) AddWebhookDelivery(d *WebhookDelivery) error {
+	if d.CreatedAt == 0 {
+		d.CreatedAt = time.Now().UnixNano()
+	}
+	value, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return appdb.Db.Set(webhookDeliveryKey(d.WebhookId, d.CreatedAt), value)
+}
+
+// GetWebhookDeliveries returns the latest num delivery logs of a webhook, the latest one comes first
+func (appdb *AppDb) GetWebhookDeliveries(webhookId string, state string, num int) ([]*WebhookDelivery, error) {
+	prefix := webhookDeliveryPrefix(webhookId)
+	deliveries := []*WebhookDelivery{}
+	_, err := appdb.Db.PrefixForeachKey(prefix, prefix, true, func(k []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		value, err := appdb.Db.Get(k)
+		if err != nil {
+			return err
+		}
+		d := &WebhookDelivery{}
+		if err := json.Unmarshal(value, d); err != nil {
+			appdatalog.Warnf("unmarshal webhook delivery <%s> failed: %s", k, err)
+			return nil
+		}
+		if state != "" && d.State != state {
+			return nil
+		}
+		deliveries = append(deliveries, d)
+		if len(deliveries) == num {
+			// use this to break loop
+			return errors.New("OK")
+		}
+		return nil
+	})
+	if err != nil && err.Error() == "OK" {
+		err = nil
+	}
+	return deliveries, err
+}
+
+// PruneWebhookDeliveries removes delivery logs created before t
+func (appdb *AppDb) PruneWebhookDeliveries(t time.Time) (int, error) {
+	return appdb.Db.PrefixCondDelete([]byte(WHL_PREFIX), func(k []byte, v []byte, err error) (bool, error) {
+		if err != nil {
+			return false, err
+		}
+		d := &WebhookDelivery{}
+		if err := json.Unmarshal(v, d); err != nil {
+			return true, nil
+		}
+		return d.CreatedAt < t.UnixNano(), nil
+	})
+}
@@ -34,6 +34,7 @@ type Chain struct {
 	userPool     map[string]*quorumpb.UserItem
 	trxFactory   *rumchaindata.TrxFactory
 	rexSyncer    *RexSyncer
+	gapRepairer  *GapRepairer
 	chaindata    *ChainData
 	Consensus    def.Consensus
 	CurrBlock    uint64
@@ -54,6 +55,9 @@ func (chain *Chain) NewChain(item *quorumpb.GroupItem, nodename string, loadChai
 	//initial Syncer
 	chain.rexSyncer = NewRexSyncer(chain.groupItem.GroupId, chain.nodename, chain, chain)
 
+	//initial cached block gap repairer
+	chain.gapRepairer = NewGapRepairer(chain.groupItem.GroupId, chain.nodename, chain)
+
 	//initial chaindata manager
 	chain.chaindata = &ChainData{
 		nodename:       chain.nodename,
@@ -201,6 +205,11 @@ func (chain *Chain) HandleTrxPsConn(trx *quorumpb.Trx) error {
 		return fmt.Errorf("invalid trx, signature verify failed")
 	}
 
+	if err := rumchaindata.CheckTrxTime(trx, time.Now()); err != nil {
+		chain_log.Warningf("<%s> trx <%s> rejected, <%s>", chain.groupItem.GroupId, trx.TrxId, err.Error())
+		return err
+	}
+
 	switch trx.Type {
 	case
 		quorumpb.TrxType_POST,
@@ -208,7 +217,8 @@ func (chain *Chain) HandleTrxPsConn(trx *quorumpb.Trx) error {
 		quorumpb.TrxType_PRODUCER,
 		quorumpb.TrxType_USER,
 		quorumpb.TrxType_APP_CONFIG,
-		quorumpb.TrxType_CHAIN_CONFIG:
+		quorumpb.TrxType_CHAIN_CONFIG,
+		quorumpb.TrxType_STAKE:
 		chain.producerAddTrx(trx)
 	default:
 		chain_log.Warningf("<%s> unsupported msg type", chain.groupItem.GroupId)
@@ -234,6 +244,8 @@ func (chain *Chain) producerAddTrx(trx *quorumpb.Trx) error {
 func (chain *Chain) HandleBlockPsConn(block *quorumpb.Block) error {
 	chain_log.Debugf("<%s> HandleBlockPsConn called", chain.groupItem.GroupId)
 
+	chain.rexSyncer.UpdHighestBlockSeen(block.BlockId)
+
 	// all approved producers ignore block from psconn (they gonna build block by themselves)
 	if chain.isProducer() {
 		return nil
@@ -327,7 +339,11 @@ func (chain *Chain) HandleTrxRex(trx *quorumpb.Trx, s network.Stream) error {
 	case quorumpb.TrxType_REQ_BLOCK:
 		chain.handleReqBlocks(trx, s)
 	case quorumpb.TrxType_REQ_BLOCK_RESP:
-		chain.handleReqBlockResp(trx)
+		chain.handleReqBlockResp(trx, s)
+	case quorumpb.TrxType_REQ_SNAPSHOT:
+		chain.handleReqSnapshot(trx, s)
+	case quorumpb.TrxType_REQ_SNAPSHOT_RESP:
+		chain.handleReqSnapshotResp(trx, s)
 	default:
 		//do nothing
 	}
@@ -370,7 +386,7 @@ func (chain *Chain) handleReqBlocks(trx *quorumpb.Trx, s network.Stream) error {
 	}
 }
 
-func (chain *Chain) handleReqBlockResp(trx *quorumpb.Trx) {
+func (chain *Chain) handleReqBlockResp(trx *quorumpb.Trx, s network.Stream) {
 	chain_log.Debugf("<%s> handleReqBlockResp called", chain.groupItem.GroupId)
 
 	//decode resp
@@ -406,10 +422,15 @@ func (chain *Chain) handleReqBlockResp(trx *quorumpb.Trx) {
 	}
 
 	result := &SyncResult{
-		TaskId: reqBlockResp.FromBlock,
-		Data:   reqBlockResp,
+		TaskId:   reqBlockResp.FromBlock,
+		Provider: streamPeer(s),
+		Data:     reqBlockResp,
 	}
 
+	//resp of gap repair request is not a syncer task
+	if chain.gapRepairer.HandleResult(result) {
+		return
+	}
 	chain.rexSyncer.AddResult(result)
 }
 
@@ -500,6 +521,22 @@ func (chain *Chain) updProducerConfig() {
 	chain.Consensus.Producer().RecreateBft()
 }
 
+func (chain *Chain) applyStakeTrx(trx *quorumpb.Trx, nodename string) {
+	if chain.groupItem.ConsenseType != quorumpb.GroupConsenseType_POS {
+		chain_log.Warningf("<%s> STAKE trx <%s> ignored, group consensus is not pos", chain.groupItem.GroupId, trx.TrxId)
+		return
+	}
+
+	err := nodectx.GetNodeCtx().GetChainStorage().UpdateStakeTrx(trx, nodename)
+	if err != nil {
+		chain_log.Warningf("<%s> apply STAKE trx <%s> failed with error <%s>", chain.groupItem.GroupId, trx.TrxId, err.Error())
+		return
+	}
+
+	//producer weights changed, re-elect producers
+	chain.updProducerConfig()
+}
+
 func (chain *Chain) updUserList() {
 	chain_log.Debugf("<%s> updUserList called", chain.groupItem.GroupId)
 
@@ -627,6 +664,16 @@ func (chain *Chain) GetLastRexSyncResult() (*chaindef.RexSyncResult, error) {
 	return chain.rexSyncer.GetLastRexSyncResult()
 }
 
+func (chain *Chain) GetSyncProgress() *chaindef.SyncProgress {
+	progress := chain.rexSyncer.GetSyncProgress()
+	progress.Status = chain.GetRexSyncerStatus()
+	return progress
+}
+
+func (chain *Chain) GetGapRepairStatus() *chaindef.GapRepairStatus {
+	return chain.gapRepairer.GetStatus()
+}
+
 func (chain *Chain) ApplyTrxsFullNode(trxs []*quorumpb.Trx, nodename string) error {
 	chain_log.Debugf("<%s> ApplyTrxsFullNode called", chain.groupItem.GroupId)
 	for _, trx := range trxs {
@@ -694,6 +741,9 @@ func (chain *Chain) ApplyTrxsFullNode(trxs []*quorumpb.Trx, nodename string) err
 		case quorumpb.TrxType_CHAIN_CONFIG:
 			chain_log.Debugf("<%s> apply CHAIN_CONFIG trx", chain.groupItem.GroupId)
 			nodectx.GetNodeCtx().GetChainStorage().UpdateChainConfigTrx(trx, nodename)
+		case quorumpb.TrxType_STAKE:
+			chain_log.Debugf("<%s> apply STAKE trx", chain.groupItem.GroupId)
+			chain.applyStakeTrx(trx, nodename)
 		default:
 			chain_log.Warningf("<%s> unsupported msgType <%s>", chain.groupItem.GroupId, trx.Type.String())
 		}
@@ -763,6 +813,9 @@ func (chain *Chain) ApplyTrxsProducerNode(trxs []*quorumpb.Trx, nodename string)
 		case quorumpb.TrxType_CHAIN_CONFIG:
 			chain_log.Debugf("<%s> apply CHAIN_CONFIG trx", chain.groupItem.GroupId)
 			nodectx.GetNodeCtx().GetChainStorage().UpdateChainConfigTrx(trx, nodename)
+		case quorumpb.TrxType_STAKE:
+			chain_log.Debugf("<%s> apply STAKE trx", chain.groupItem.GroupId)
+			chain.applyStakeTrx(trx, nodename)
 		default:
 			chain_log.Warningf("<%s> unsupported msgType <%s>", chain.groupItem.GroupId, trx.Type)
 		}
@@ -799,6 +852,9 @@ func (chain *Chain) VerifySign(hash, signature []byte, pubkey string) (bool, err
 func (chain *Chain) StartSync() error {
 	chain_log.Debugf("<%s> StartSync called", chain.groupItem.GroupId)
 
+	//owner may also receive blocks from other producers
+	chain.gapRepairer.Start()
+
 	if chain.isOwner() {
 		chain_log.Debugf("<%s> owner no need to sync", chain.groupItem.GroupId)
 		return nil
@@ -813,6 +869,9 @@ func (chain *Chain) StopSync() {
 	if chain.rexSyncer != nil {
 		chain.rexSyncer.Stop()
 	}
+	if chain.gapRepairer != nil {
+		chain.gapRepairer.Stop()
+	}
 }
 
 //local sync
@@ -56,6 +56,25 @@ func (d *ChainData) GetReqBlocks(trx *quorumpb.Trx) (requester string, fromBlock
 		return "", 0, 0, nil, -1, errors.New("requester don't have sufficient privileges")
 	}
 
+	//blocks pruned by retention policy only have headers, answer BLOCK_NOT_FOUND with the header of
+	//the first full block, so the requester knows where the pruned range ends
+	pruneState, err := nodectx.GetNodeCtx().GetChainStorage().GetPruneState(reqBlockItem.GroupId, d.nodename)
+	if err != nil {
+		return "", 0, 0, nil, -1, err
+	}
+	if reqBlockItem.FromBlock > 0 && reqBlockItem.FromBlock <= pruneState.PrunedTo {
+		var headers []*quorumpb.Block
+		header, err := nodectx.GetNodeCtx().GetChainStorage().GetBlock(reqBlockItem.GroupId, pruneState.PrunedTo+1, false, d.nodename)
+		if err == nil && header.BlockId == pruneState.PrunedTo+1 {
+			//the header can be verified without trxs only if it has trxs root
+			if len(header.TrxsRoot) > 0 {
+				header.Trxs = nil
+			}
+			headers = append(headers, header)
+		}
+		return reqBlockItem.ReqPubkey, reqBlockItem.FromBlock, reqBlockItem.BlksRequested, headers, quorumpb.ReqBlkResult_BLOCK_NOT_FOUND, nil
+	}
+
 	exist := false
 	exist, err = nodectx.GetNodeCtx().GetChainStorage().IsBlockExist(reqBlockItem.GroupId, reqBlockItem.FromBlock, false, d.nodename)
 	if err != nil {
@@ -81,7 +100,7 @@ func (d *ChainData) GetReqBlocks(trx *quorumpb.Trx) (requester string, fromBlock
 		totalBlockBytes = totalBlockBytes + len(pdate)
 		//check if reach maximum length, may have more
 		if totalBlockBytes > MAX_BLOCK_IN_RESP_BYTES {
-			return reqBlockItem.ReqPubkey, reqBlockItem.FromBlock, reqBlockItem.BlksRequested, blocks, quorumpb.ReqBlkResult_BLOCK_IN_RESP, nil
+			return reqBlockItem.ReqPubkey, reqBlockItem.FromBlock, reqBlockItem.BlksRequested, bs, quorumpb.ReqBlkResult_BLOCK_IN_RESP, nil
 		}
 
 		//put block into blocks list
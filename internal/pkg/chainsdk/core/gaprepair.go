@@ -0,0 +1,360 @@
+package chain
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rumsystem/quorum/internal/pkg/chainsdk/def"
+	"github.com/rumsystem/quorum/internal/pkg/conn"
+	"github.com/rumsystem/quorum/internal/pkg/logging"
+	"github.com/rumsystem/quorum/internal/pkg/nodectx"
+	rumchaindata "github.com/rumsystem/quorum/pkg/data"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+)
+
+var GAP_REPAIR_INTERVAL = 10 * 1000       // in millseconds
+var GAP_REPAIR_DELAY = 10 * 1000          // in millseconds, gaps not filled by rexsyncer in time are repaired
+var GAP_REQ_TIMEOUT = 10 * 1000           // in millseconds
+var MAX_GAP_REQ_BLOCKS = 50               // max blocks asked by one repair request
+var MAX_GAP_REQS = 3                      // repair requests in flight, each to a different provider
+var CACHED_BLOCK_MAX_AGE = 24 * time.Hour // cached blocks produced before it are evicted
+var MAX_CACHED_BLOCKS = 5000              // cached blocks of a group, the highest ones are evicted
+
+// reasons of evicting a cached block
+const (
+	EvictStale    = "STALE"    // block id is not above the top block
+	EvictExpired  = "EXPIRED"  // older than CACHED_BLOCK_MAX_AGE
+	EvictInvalid  = "INVALID"  // bad hash or sign, or not linked to its parent
+	EvictOverflow = "OVERFLOW" // more than MAX_CACHED_BLOCKS
+)
+
+var gap_repair_log = logging.Logger("gaprepair")
+
+// gapChain is the chain cached blocks belong to, implemented by *Chain
+type gapChain interface {
+	GetCurrBlockId() uint64
+	GetTrxFactory() def.TrxFactoryIface
+	ApplyBlocks(blocks []*quorumpb.Block) error
+}
+
+// cachedBlockStorage keeps blocks and cached blocks of the chain, implemented by *chainstorage.Storage
+type cachedBlockStorage interface {
+	GetCachedBlocks(groupId string, prefix ...string) ([]*quorumpb.Block, error)
+	GetBlock(groupId string, blockId uint64, cached bool, prefix ...string) (*quorumpb.Block, error)
+	RmBlock(groupId string, blockId uint64, cached bool, prefix ...string) error
+}
+
+type gapReq struct {
+	from     uint64
+	to       uint64
+	provider peer.ID
+	deadline int64 //in nanoseconds
+}
+
+// GapRepairer watches blocks cached because their parents are missing. holes between the top block and cached
+// blocks are asked from providers by exact block ids, and invalid or too old cached blocks are evicted
+type GapRepairer struct {
+	groupId    string
+	nodename   string
+	chain      gapChain
+	getStorage func() cachedBlockStorage
+	getPeers   func() (syncPeers, error)
+
+	mu        sync.Mutex
+	cancel    context.CancelFunc
+	gaps      []*def.BlockGap
+	pending   map[uint64]*gapReq //first block of the gap -> request
+	cached    []*quorumpb.Block
+	requested uint64
+	repaired  uint64
+	evicted   map[string]uint64
+	lastRunAt int64
+}
+
+func NewGapRepairer(groupId string, nodename string, chain *Chain) *GapRepairer {
+	return &GapRepairer{
+		groupId:  groupId,
+		nodename: nodename,
+		chain:    chain,
+		getStorage: func() cachedBlockStorage {
+			return nodectx.GetNodeCtx().GetChainStorage()
+		},
+		getPeers: func() (syncPeers, error) {
+			return conn.GetConn().GetConnMgr(groupId)
+		},
+		pending: make(map[uint64]*gapReq),
+		evicted: make(map[string]uint64),
+	}
+}
+
+func (gr *GapRepairer) Start() {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+	if gr.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	gr.cancel = cancel
+	go func() {
+		ticker := time.NewTicker(time.Duration(GAP_REPAIR_INTERVAL) * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				gr.repair()
+			}
+		}
+	}()
+}
+
+func (gr *GapRepairer) Stop() {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+	if gr.cancel != nil {
+		gr.cancel()
+		gr.cancel = nil
+	}
+	gr.pending = make(map[uint64]*gapReq)
+}
+
+func (gr *GapRepairer) GetStatus() *def.GapRepairStatus {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+
+	status := &def.GapRepairStatus{
+		CachedBlocks: len(gr.cached),
+		Gaps:         []*def.BlockGap{},
+		Pending:      len(gr.pending),
+		Requested:    gr.requested,
+		Repaired:     gr.repaired,
+		Evicted:      make(map[string]uint64),
+		LastRunAt:    gr.lastRunAt,
+	}
+	if len(gr.cached) > 0 {
+		status.LowestCached = gr.cached[0].BlockId
+		status.HighestCached = gr.cached[len(gr.cached)-1].BlockId
+	}
+	for _, gap := range gr.gaps {
+		g := *gap
+		status.Gaps = append(status.Gaps, &g)
+	}
+	for reason, n := range gr.evicted {
+		status.Evicted[reason] = n
+	}
+	return status
+}
+
+// repair evicts bad cached blocks, moves cached blocks connected to the top block to chain, then asks missing blocks
+func (gr *GapRepairer) repair() {
+	blocks, err := gr.getStorage().GetCachedBlocks(gr.groupId, gr.nodename)
+	if err != nil {
+		gap_repair_log.Warningf("<%s> get cached blocks failed: %s", gr.groupId, err)
+		return
+	}
+
+	now := time.Now()
+	currBlockId := gr.chain.GetCurrBlockId()
+	var cached []*quorumpb.Block
+	for _, block := range blocks {
+		if reason := gr.checkCachedBlock(block, cached, currBlockId, now); reason != "" {
+			gr.evict(block, reason)
+			continue
+		}
+		cached = append(cached, block)
+	}
+	if len(cached) > MAX_CACHED_BLOCKS {
+		for _, block := range cached[MAX_CACHED_BLOCKS:] {
+			gr.evict(block, EvictOverflow)
+		}
+		cached = cached[:MAX_CACHED_BLOCKS]
+	}
+
+	//parent arrived but cached blocks were not moved, apply them again
+	if len(cached) > 0 && cached[0].BlockId == currBlockId+1 {
+		gap_repair_log.Debugf("<%s> cached block <%d> is connected to top block, apply it", gr.groupId, cached[0].BlockId)
+		if err := gr.chain.ApplyBlocks([]*quorumpb.Block{cached[0]}); err != nil {
+			gap_repair_log.Warningf("<%s> apply cached block <%d> failed: %s", gr.groupId, cached[0].BlockId, err)
+		}
+		return
+	}
+
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+	gr.cached = cached
+	gr.lastRunAt = now.UnixNano()
+	gr.updGaps(currBlockId, now.UnixNano())
+	gr.sendReqs(now.UnixNano())
+}
+
+// checkCachedBlock return the reason to evict the block, or empty if it should be kept. prev is kept blocks before it
+func (gr *GapRepairer) checkCachedBlock(block *quorumpb.Block, prev []*quorumpb.Block, currBlockId uint64, now time.Time) string {
+	if block.GroupId != gr.groupId {
+		return EvictInvalid
+	}
+	if block.BlockId <= currBlockId {
+		return EvictStale
+	}
+	if block.TimeStamp < now.Add(-CACHED_BLOCK_MAX_AGE).UnixNano() {
+		return EvictExpired
+	}
+	if valid, _ := rumchaindata.ValidBlock(block); !valid {
+		return EvictInvalid
+	}
+
+	//must be linked to its parent if the parent is known
+	var parentHash []byte
+	if block.BlockId == currBlockId+1 {
+		parent, err := gr.getStorage().GetBlock(gr.groupId, currBlockId, false, gr.nodename)
+		if err == nil {
+			parentHash = parent.BlockHash
+		}
+	} else if len(prev) > 0 && prev[len(prev)-1].BlockId == block.BlockId-1 {
+		parentHash = prev[len(prev)-1].BlockHash
+	}
+	if parentHash != nil && !bytes.Equal(block.PrevHash, parentHash) {
+		return EvictInvalid
+	}
+	return ""
+}
+
+func (gr *GapRepairer) evict(block *quorumpb.Block, reason string) {
+	gap_repair_log.Debugf("<%s> evict cached block <%d>, reason <%s>", gr.groupId, block.BlockId, reason)
+	if err := gr.getStorage().RmBlock(gr.groupId, block.BlockId, true, gr.nodename); err != nil {
+		gap_repair_log.Warningf("<%s> remove cached block <%d> failed: %s", gr.groupId, block.BlockId, err)
+		return
+	}
+	gr.mu.Lock()
+	gr.evicted[reason]++
+	gr.mu.Unlock()
+}
+
+// updGaps finds missing block ranges between the top block and cached blocks, the first seen time of known gaps is kept
+func (gr *GapRepairer) updGaps(currBlockId uint64, now int64) {
+	known := make(map[uint64]*def.BlockGap)
+	for _, gap := range gr.gaps {
+		known[gap.From] = gap
+	}
+
+	gr.gaps = nil
+	next := currBlockId + 1
+	for _, block := range gr.cached {
+		if block.BlockId > next {
+			gap := &def.BlockGap{From: next, To: block.BlockId - 1, Since: now}
+			if g, ok := known[next]; ok {
+				gap.Since = g.Since
+				gap.Requests = g.Requests
+			}
+			gr.gaps = append(gr.gaps, gap)
+		}
+		next = block.BlockId + 1
+	}
+
+	for from, req := range gr.pending {
+		if _, ok := known[from]; !ok || req.deadline < now {
+			delete(gr.pending, from)
+		}
+	}
+}
+
+// sendReqs asks gaps older than GAP_REPAIR_DELAY from providers, a gap is retried with another provider after timeout
+func (gr *GapRepairer) sendReqs(now int64) {
+	connMgr, err := gr.getPeers()
+	if err != nil {
+		return
+	}
+
+	busy := make(map[peer.ID]bool)
+	for _, req := range gr.pending {
+		busy[req.provider] = true
+	}
+	var providers []peer.ID
+	for _, p := range connMgr.GetSyncPeers(MAX_GAP_REQS * 2) {
+		if !busy[p] {
+			providers = append(providers, p)
+		}
+	}
+
+	for _, gap := range gr.gaps {
+		if len(gr.pending) >= MAX_GAP_REQS || len(providers) == 0 {
+			return
+		}
+		if _, ok := gr.pending[gap.From]; ok || now-gap.Since < int64(GAP_REPAIR_DELAY)*int64(time.Millisecond) {
+			continue
+		}
+
+		//rotate providers by retries, so a gap is not always asked from the same one
+		i := gap.Requests % len(providers)
+		p := providers[i]
+		providers = append(providers[:i], providers[i+1:]...)
+
+		to := gap.To
+		if to-gap.From+1 > uint64(MAX_GAP_REQ_BLOCKS) {
+			to = gap.From + uint64(MAX_GAP_REQ_BLOCKS) - 1
+		}
+		trx, err := gr.chain.GetTrxFactory().GetReqBlocksTrx("", gr.groupId, gap.From, int32(to-gap.From+1))
+		if err != nil {
+			gap_repair_log.Warningf("<%s> create req block trx failed: %s", gr.groupId, err)
+			return
+		}
+
+		gap.Requests++
+		gr.requested++
+		gr.pending[gap.From] = &gapReq{
+			from:     gap.From,
+			to:       to,
+			provider: p,
+			deadline: now + int64(GAP_REQ_TIMEOUT)*int64(time.Millisecond),
+		}
+		gap_repair_log.Debugf("<%s> ask missing blocks <%d> to <%d> from <%s>", gr.groupId, gap.From, to, p)
+		go func(from uint64) {
+			if err := connMgr.SendReqTrxRexToPeer(trx, p); err != nil {
+				gap_repair_log.Debugf("<%s> send gap req <%d> to <%s> failed: %s", gr.groupId, from, p, err)
+				gr.mu.Lock()
+				delete(gr.pending, from)
+				gr.mu.Unlock()
+			}
+		}(gap.From)
+	}
+}
+
+// HandleResult takes the resp of a repair request, return false if it is not asked by the repairer
+func (gr *GapRepairer) HandleResult(result *SyncResult) bool {
+	resp, ok := result.Data.(*quorumpb.ReqBlockResp)
+	if !ok {
+		return false
+	}
+
+	gr.mu.Lock()
+	req, ok := gr.pending[resp.FromBlock]
+	if !ok || req.provider != result.Provider {
+		gr.mu.Unlock()
+		return false
+	}
+	delete(gr.pending, resp.FromBlock)
+
+	var blocks []*quorumpb.Block
+	if resp.Blocks != nil && resp.Result != quorumpb.ReqBlkResult_BLOCK_NOT_FOUND {
+		for _, block := range resp.Blocks.Blocks {
+			if block.BlockId >= req.from && block.BlockId <= req.to {
+				blocks = append(blocks, block)
+			}
+		}
+	}
+	gr.repaired += uint64(len(blocks))
+	gr.mu.Unlock()
+
+	gap_repair_log.Debugf("<%s> gap req <%d> answered by <%s>, result <%s>, <%d> blocks", gr.groupId, resp.FromBlock, result.Provider, resp.Result, len(blocks))
+	if len(blocks) == 0 {
+		return true
+	}
+	if err := gr.chain.ApplyBlocks(blocks); err != nil {
+		gap_repair_log.Warningf("<%s> apply repaired blocks from <%s> failed: %s", gr.groupId, result.Provider, err)
+	}
+	return true
+}
@@ -0,0 +1,204 @@
+package chain
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	rumchaindata "github.com/rumsystem/quorum/pkg/data"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+)
+
+const gapTestGroup = "gapgroup"
+
+// fakeBlockStorage keeps blocks of the chain and cached blocks by block id
+type fakeBlockStorage struct {
+	blocks map[uint64]*quorumpb.Block
+	cached map[uint64]*quorumpb.Block
+}
+
+func (s *fakeBlockStorage) GetCachedBlocks(groupId string, prefix ...string) ([]*quorumpb.Block, error) {
+	blocks := []*quorumpb.Block{}
+	for _, block := range s.cached {
+		blocks = append(blocks, block)
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].BlockId < blocks[j].BlockId })
+	return blocks, nil
+}
+
+func (s *fakeBlockStorage) GetBlock(groupId string, blockId uint64, cached bool, prefix ...string) (*quorumpb.Block, error) {
+	blocks := s.blocks
+	if cached {
+		blocks = s.cached
+	}
+	if block, ok := blocks[blockId]; ok {
+		return block, nil
+	}
+	return nil, errors.New("block not found")
+}
+
+func (s *fakeBlockStorage) RmBlock(groupId string, blockId uint64, cached bool, prefix ...string) error {
+	if cached {
+		delete(s.cached, blockId)
+	} else {
+		delete(s.blocks, blockId)
+	}
+	return nil
+}
+
+// newGapTestBlock creates a block with a valid hash, its producer pubkey is not a key so the sign is not checked
+func newGapTestBlock(t *testing.T, blockId uint64, prevHash []byte, timestamp int64) *quorumpb.Block {
+	block := &quorumpb.Block{
+		GroupId:        gapTestGroup,
+		BlockId:        blockId,
+		PrevHash:       prevHash,
+		ProducerPubkey: "not a key",
+		TimeStamp:      timestamp,
+	}
+	hash, err := rumchaindata.BlockHash(block)
+	if err != nil {
+		t.Fatalf("BlockHash failed: %s", err)
+	}
+	block.BlockHash = hash
+	return block
+}
+
+func newTestGapRepairer(t *testing.T, currBlockId uint64, providers int) (*GapRepairer, *fakeSyncChain, *fakeBlockStorage, *fakeSyncPeers) {
+	chain := &fakeSyncChain{currBlockId: currBlockId}
+	storage := &fakeBlockStorage{blocks: make(map[uint64]*quorumpb.Block), cached: make(map[uint64]*quorumpb.Block)}
+	storage.blocks[currBlockId] = newGapTestBlock(t, currBlockId, nil, time.Now().UnixNano())
+	peers := &fakeSyncPeers{penalized: make(map[peer.ID]int)}
+	for i := 0; i < providers; i++ {
+		peers.peers = append(peers.peers, peer.ID(string(rune('a'+i))))
+	}
+
+	gr := NewGapRepairer(gapTestGroup, "gapnode", nil)
+	gr.chain = chain
+	gr.getStorage = func() cachedBlockStorage { return storage }
+	gr.getPeers = func() (syncPeers, error) { return peers, nil }
+	return gr, chain, storage, peers
+}
+
+func checkGaps(t *testing.T, gr *GapRepairer, expected [][2]uint64) {
+	status := gr.GetStatus()
+	if len(status.Gaps) != len(expected) {
+		t.Fatalf("gaps <%+v>, expect <%v>", status.Gaps, expected)
+	}
+	for i, gap := range status.Gaps {
+		if gap.From != expected[i][0] || gap.To != expected[i][1] {
+			t.Fatalf("gaps <%+v>, expect <%v>", status.Gaps, expected)
+		}
+	}
+}
+
+func TestGapRepairerEvictCachedBlocks(t *testing.T) {
+	gr, _, storage, _ := newTestGapRepairer(t, 5, 0)
+	now := time.Now().UnixNano()
+
+	b7 := newGapTestBlock(t, 7, []byte("unknown parent"), now)
+	b8 := newGapTestBlock(t, 8, []byte("not b7"), now)
+	b9 := newGapTestBlock(t, 9, nil, time.Now().Add(-CACHED_BLOCK_MAX_AGE-time.Minute).UnixNano())
+	b10 := newGapTestBlock(t, 10, nil, now)
+	b10.BlockHash = []byte("bad hash")
+	b12 := newGapTestBlock(t, 12, nil, now)
+	b13 := newGapTestBlock(t, 13, b12.BlockHash, now)
+	for _, block := range []*quorumpb.Block{newGapTestBlock(t, 3, nil, now), b7, b8, b9, b10, b12, b13} {
+		storage.cached[block.BlockId] = block
+	}
+
+	maxCached := MAX_CACHED_BLOCKS
+	MAX_CACHED_BLOCKS = 2
+	defer func() { MAX_CACHED_BLOCKS = maxCached }()
+
+	gr.repair()
+
+	for _, id := range []uint64{7, 12} {
+		if _, ok := storage.cached[id]; !ok {
+			t.Fatalf("cached block <%d> is evicted", id)
+		}
+	}
+	if len(storage.cached) != 2 {
+		t.Fatalf("cached blocks <%d>, expect 2", len(storage.cached))
+	}
+	status := gr.GetStatus()
+	expected := map[string]uint64{EvictStale: 1, EvictInvalid: 2, EvictExpired: 1, EvictOverflow: 1}
+	for reason, n := range expected {
+		if status.Evicted[reason] != n {
+			t.Fatalf("evicted <%v>, expect <%v>", status.Evicted, expected)
+		}
+	}
+	if status.CachedBlocks != 2 || status.LowestCached != 7 || status.HighestCached != 12 {
+		t.Fatalf("unexpected status <%+v>", status)
+	}
+	checkGaps(t, gr, [][2]uint64{{6, 6}, {8, 11}})
+}
+
+func TestGapRepairerRequestMissingBlocks(t *testing.T) {
+	gr, chain, storage, _ := newTestGapRepairer(t, 5, 2)
+	now := time.Now().UnixNano()
+	storage.cached[7] = newGapTestBlock(t, 7, nil, now)
+	storage.cached[20] = newGapTestBlock(t, 20, nil, now)
+	storage.cached[30] = newGapTestBlock(t, 30, nil, now)
+
+	//new gaps are left to the syncer for GAP_REPAIR_DELAY
+	gr.repair()
+	checkGaps(t, gr, [][2]uint64{{6, 6}, {8, 19}, {21, 29}})
+	if status := gr.GetStatus(); status.Pending != 0 {
+		t.Fatalf("<%d> gap requests sent before GAP_REPAIR_DELAY", status.Pending)
+	}
+
+	delay, maxBlocks := GAP_REPAIR_DELAY, MAX_GAP_REQ_BLOCKS
+	GAP_REPAIR_DELAY, MAX_GAP_REQ_BLOCKS = 0, 5
+	defer func() { GAP_REPAIR_DELAY, MAX_GAP_REQ_BLOCKS = delay, maxBlocks }()
+
+	//each gap is asked from a different provider, only 2 providers for 3 gaps
+	gr.repair()
+	gr.mu.Lock()
+	if len(gr.pending) != 2 {
+		gr.mu.Unlock()
+		t.Fatalf("<%d> gap requests pending, expect 2", len(gr.pending))
+	}
+	req6, req8 := gr.pending[6], gr.pending[8]
+	gr.mu.Unlock()
+	if req6 == nil || req8 == nil || req6.provider == req8.provider {
+		t.Fatalf("gaps are not asked from different providers")
+	}
+	if req8.to != 12 {
+		t.Fatalf("gap from block 8 asked to <%d>, expect at most MAX_GAP_REQ_BLOCKS blocks", req8.to)
+	}
+
+	resp := func(from uint64, ids ...uint64) *quorumpb.ReqBlockResp {
+		bundle := &quorumpb.BlocksBundle{}
+		for _, id := range ids {
+			bundle.Blocks = append(bundle.Blocks, &quorumpb.Block{BlockId: id})
+		}
+		return &quorumpb.ReqBlockResp{FromBlock: from, Result: quorumpb.ReqBlkResult_BLOCK_IN_RESP, Blocks: bundle}
+	}
+
+	//resp not asked by the repairer is left to the syncer
+	if gr.HandleResult(&SyncResult{Provider: req8.provider, Data: resp(6, 6)}) {
+		t.Fatal("resp from another provider is taken")
+	}
+	if gr.HandleResult(&SyncResult{Provider: req6.provider, Data: resp(1, 1)}) {
+		t.Fatal("resp of a range not asked is taken")
+	}
+
+	//blocks out of the asked range are dropped
+	if !gr.HandleResult(&SyncResult{Provider: req6.provider, Data: resp(6, 6, 7, 8)}) {
+		t.Fatal("resp of gap request is not taken")
+	}
+	if chain.currBlockId != 6 || len(chain.applied) != 1 {
+		t.Fatalf("applied blocks <%v>, expect block 6 only", chain.applied)
+	}
+	if status := gr.GetStatus(); status.Repaired != 1 || status.Pending != 1 {
+		t.Fatalf("unexpected status <%+v>", status)
+	}
+
+	//cached block connected to the top block is applied
+	gr.repair()
+	if chain.currBlockId != 7 {
+		t.Fatalf("cached block connected to top block is not applied, top block <%d>", chain.currBlockId)
+	}
+}
@@ -270,6 +270,15 @@ func (grp *Group) UpdProducer(item *quorumpb.BFTProducerBundleItem) (string, err
 	return grp.sendTrx(trx)
 }
 
+func (grp *Group) UpdStake(item *quorumpb.StakeItem) (string, error) {
+	group_log.Debugf("<%s> UpdStake called", grp.Item.GroupId)
+	trx, err := grp.ChainCtx.GetTrxFactory().GetStakeTrx("", item)
+	if err != nil {
+		return "", err
+	}
+	return grp.sendTrx(trx)
+}
+
 func (grp *Group) UpdUser(item *quorumpb.UserItem) (string, error) {
 	group_log.Debugf("<%s> UpdUser called", grp.Item.GroupId)
 	trx, err := grp.ChainCtx.GetTrxFactory().GetRegUserTrx("", item)
@@ -308,6 +317,12 @@ func (grp *Group) sendTrx(trx *quorumpb.Trx) (string, error) {
 	if err != nil {
 		return "", err
 	}
+
+	//save to pubqueue before send, conn will compress trx data in place
+	if err := TrxEnqueue(grp.Item.GroupId, trx); err != nil {
+		group_log.Warningf("<%s> enqueue trx <%s> failed: %s", grp.Item.GroupId, trx.TrxId, err)
+	}
+
 	err = connMgr.SendUserTrxPubsub(trx)
 	if err != nil {
 		return "", err
@@ -2,6 +2,7 @@ package chain
 
 import (
 	"fmt"
+	"sync"
 
 	chaindef "github.com/rumsystem/quorum/internal/pkg/chainsdk/def"
 	"github.com/rumsystem/quorum/internal/pkg/logging"
@@ -12,7 +13,10 @@ import (
 
 var groupMgr_log = logging.Logger("groupmgr")
 
+// GroupMgr keeps joined groups, Groups is changed with mu held by AddGroup and RemoveGroup,
+// goroutines running besides api handlers should read it by GetLocalGroup and GetLocalGroups
 type GroupMgr struct {
+	mu     sync.RWMutex
 	Groups map[string]*Group
 }
 
@@ -46,7 +50,9 @@ func (groupMgr *GroupMgr) LoadAllGroups() error {
 			groupMgr_log.Fatalf(err.Error())
 		} else {
 			groupMgr_log.Debugf("load group: %s", item.GroupId)
+			groupMgr.mu.Lock()
 			groupMgr.Groups[item.GroupId] = group
+			groupMgr.mu.Unlock()
 			group.LoadGroup(item)
 		}
 	}
@@ -84,15 +90,46 @@ func (groupmgr *GroupMgr) TeardownAllGroups() {
 }
 
 func (groupmgr *GroupMgr) GetGroupItem(groupId string) (*quorumpb.GroupItem, error) {
-	if grp, ok := groupmgr.Groups[groupId]; ok {
+	if grp, ok := groupmgr.GetLocalGroup(groupId); ok {
 		return grp.Item, nil
 	}
 	return nil, fmt.Errorf("group not exist: %s", groupId)
 }
 
 func (groupmgr *GroupMgr) GetGroup(groupId string) (chaindef.GroupIface, error) {
-	if grp, ok := groupmgr.Groups[groupId]; ok {
+	if grp, ok := groupmgr.GetLocalGroup(groupId); ok {
 		return grp, nil
 	}
 	return nil, fmt.Errorf("group not exist: %s", groupId)
 }
+
+func (groupmgr *GroupMgr) GetLocalGroup(groupId string) (*Group, bool) {
+	groupmgr.mu.RLock()
+	defer groupmgr.mu.RUnlock()
+	grp, ok := groupmgr.Groups[groupId]
+	return grp, ok
+}
+
+// GetLocalGroups returns a copy of joined groups
+func (groupmgr *GroupMgr) GetLocalGroups() map[string]*Group {
+	groupmgr.mu.RLock()
+	defer groupmgr.mu.RUnlock()
+	groups := make(map[string]*Group, len(groupmgr.Groups))
+	for groupId, grp := range groupmgr.Groups {
+		groups[groupId] = grp
+	}
+	return groups
+}
+
+// AddGroup adds a group by the group id of its item, the group item must be set
+func (groupmgr *GroupMgr) AddGroup(group *Group) {
+	groupmgr.mu.Lock()
+	defer groupmgr.mu.Unlock()
+	groupmgr.Groups[group.Item.GroupId] = group
+}
+
+func (groupmgr *GroupMgr) RemoveGroup(groupId string) {
+	groupmgr.mu.Lock()
+	defer groupmgr.mu.Unlock()
+	delete(groupmgr.Groups, groupId)
+}
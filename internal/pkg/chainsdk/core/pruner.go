@@ -0,0 +1,122 @@
+package chain
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rumsystem/quorum/internal/pkg/logging"
+	"github.com/rumsystem/quorum/internal/pkg/nodectx"
+	chainstorage "github.com/rumsystem/quorum/internal/pkg/storage/chain"
+)
+
+var pruner_log = logging.Logger("pruner")
+
+var BLOCK_PRUNE_INTERVAL = 10 * time.Minute // interval to prune groups by their retention policy
+var BLOCK_PRUNE_BATCH = uint64(1000)        // max blocks of a group pruned in one round
+
+// PruneHold return the lowest block of a group still read by a local consumer (appdb sync, webhooks),
+// blocks from it are not pruned, 0 for no limit
+type PruneHold func(groupId string) uint64
+
+// BlockPruner prunes blocks and trxs of joined groups by their retention policy
+type BlockPruner struct {
+	holds []PruneHold
+	mu    sync.Mutex
+}
+
+var blockPruner *BlockPruner
+
+func GetBlockPruner() *BlockPruner {
+	return blockPruner
+}
+
+func InitBlockPruner(ctx context.Context, holds ...PruneHold) *BlockPruner {
+	pruner_log.Debug("InitBlockPruner called")
+	blockPruner = &BlockPruner{holds: holds}
+
+	go func() {
+		ticker := time.NewTicker(BLOCK_PRUNE_INTERVAL)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				blockPruner.PruneAll()
+			}
+		}
+	}()
+	return blockPruner
+}
+
+// PruneAll prunes every joined group once
+func (p *BlockPruner) PruneAll() {
+	for groupId, group := range GetGroupMgr().Groups {
+		if _, err := p.PruneGroup(group); err != nil {
+			pruner_log.Warningf("<%s> prune blocks failed: %s", groupId, err)
+		}
+	}
+}
+
+// PruneGroup prunes at most BLOCK_PRUNE_BATCH blocks of the group, the top block is always kept with its trxs
+func (p *BlockPruner) PruneGroup(group *Group) (*chainstorage.PruneState, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	groupId := group.Item.GroupId
+	cs := nodectx.GetNodeCtx().GetChainStorage()
+	policy, err := cs.GetRetentionPolicy(groupId, group.Nodename)
+	if err != nil {
+		return nil, err
+	}
+	state, err := cs.GetPruneState(groupId, group.Nodename)
+	if err != nil || policy.Mode == chainstorage.RetentionAll {
+		return state, err
+	}
+
+	currBlockId := group.GetCurrentBlockId()
+	if currBlockId <= state.PrunedTo+1 {
+		return state, nil
+	}
+	to := currBlockId - 1
+	if to > state.PrunedTo+BLOCK_PRUNE_BATCH {
+		to = state.PrunedTo + BLOCK_PRUNE_BATCH
+	}
+	for _, hold := range p.holds {
+		if h := hold(groupId); h > 0 && to >= h {
+			to = h - 1
+		}
+	}
+
+	switch policy.Mode {
+	case chainstorage.RetentionBlocks:
+		if currBlockId <= policy.Value {
+			return state, nil
+		}
+		if keepFrom := currBlockId - policy.Value + 1; to >= keepFrom {
+			to = keepFrom - 1
+		}
+	case chainstorage.RetentionDays:
+		// blocks are in time order, stop at the first block in retention
+		cutoff := time.Now().Add(-time.Duration(policy.Value) * 24 * time.Hour).UnixNano()
+		last := state.PrunedTo
+		for blockId := state.PrunedTo + 1; blockId <= to; blockId++ {
+			block, err := cs.GetBlock(groupId, blockId, false, group.Nodename)
+			if err != nil {
+				return nil, err
+			}
+			if block.TimeStamp >= cutoff {
+				break
+			}
+			last = blockId
+		}
+		to = last
+	}
+
+	if to <= state.PrunedTo {
+		return state, nil
+	}
+	pruner_log.Infof("<%s> prune blocks <%d> to <%d> by retention policy <%s>", groupId, state.PrunedTo+1, to, policy.Mode)
+	return cs.PruneBlocks(groupId, to, group.Nodename)
+}
@@ -0,0 +1,300 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	chaindef "github.com/rumsystem/quorum/internal/pkg/chainsdk/def"
+	"github.com/rumsystem/quorum/internal/pkg/logging"
+	"github.com/rumsystem/quorum/internal/pkg/storage"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+var pubqueue_log = logging.Logger("pubqueue")
+
+const (
+	PublishQueueItemStatePending = "PENDING"
+	PublishQueueItemStateSuccess = "SUCCESS"
+	PublishQueueItemStateFail    = "FAIL"
+)
+
+var PUBQUEUE_WATCH_INTERVAL = 1 * 1000    // in millseconds
+var PUBQUEUE_RESEND_BASE_DELAY = 1 * 1000 // in millseconds, doubled after each resend
+var PUBQUEUE_RESEND_MAX_DELAY = 60 * 1000 // in millseconds
+var PUBQUEUE_EXPIRE_GRACE = 10 * 1000     // in millseconds, a trx packaged right before it expired may reach the chain later
+
+type PublishQueueItem struct {
+	GroupId    string
+	State      string
+	RetryCount int // resend times, a trx is resent till it is on chain or expired
+	UpdateAt   int64
+	Trx        *quorumpb.Trx
+}
+
+type PublishQueueWatcher struct {
+	db            storage.QuorumStorage
+	groupMgrIface chaindef.GroupMgrIface
+	autoAck       bool
+
+	mu sync.Mutex
+}
+
+var publishQueueWatcher *PublishQueueWatcher
+
+func GetPubQueueWatcher() *PublishQueueWatcher {
+	return publishQueueWatcher
+}
+
+func InitPublishQueueWatcher(ctx context.Context, groupMgrIface chaindef.GroupMgrIface, db storage.QuorumStorage, autoAck bool) *PublishQueueWatcher {
+	pubqueue_log.Debug("InitPublishQueueWatcher called")
+	publishQueueWatcher = &PublishQueueWatcher{
+		db:            db,
+		groupMgrIface: groupMgrIface,
+		autoAck:       autoAck,
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(PUBQUEUE_WATCH_INTERVAL) * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				pubqueue_log.Debug("publish queue watcher stopped")
+				return
+			case <-ticker.C:
+				publishQueueWatcher.doRefresh()
+			}
+		}
+	}()
+
+	return publishQueueWatcher
+}
+
+// TrxEnqueue save a trx to the publish queue of its group, the trx will be resent till it is packaged or expired.
+// the trx should be a signed one and trx.Data should not be compressed yet.
+func TrxEnqueue(groupId string, trx *quorumpb.Trx) error {
+	if publishQueueWatcher == nil {
+		return nil
+	}
+	return publishQueueWatcher.enqueue(groupId, trx)
+}
+
+func (pq *PublishQueueWatcher) enqueue(groupId string, trx *quorumpb.Trx) error {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+
+	key := []byte(storage.GetPubQueueKey(groupId, trx.TrxId))
+	exist, err := pq.db.IsExist(key)
+	if err != nil {
+		return err
+	}
+
+	if exist {
+		//resend of a queued trx, keep the original item
+		return nil
+	}
+
+	item := &PublishQueueItem{
+		GroupId:    groupId,
+		State:      PublishQueueItemStatePending,
+		RetryCount: 0,
+		UpdateAt:   time.Now().UnixNano(),
+		Trx:        proto.Clone(trx).(*quorumpb.Trx),
+	}
+
+	pubqueue_log.Debugf("<%s> enqueue trx <%s>", groupId, trx.TrxId)
+	return pq.saveItem(item)
+}
+
+func (pq *PublishQueueWatcher) saveItem(item *PublishQueueItem) error {
+	value, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	key := storage.GetPubQueueKey(item.GroupId, item.Trx.TrxId)
+	return pq.db.Set([]byte(key), value)
+}
+
+func (pq *PublishQueueWatcher) getItems(groupId string) ([]*PublishQueueItem, error) {
+	items := []*PublishQueueItem{}
+	key := storage.GetPubQueuePrefix(groupId)
+	err := pq.db.PrefixForeach([]byte(key), func(k []byte, v []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		item := &PublishQueueItem{}
+		if err := json.Unmarshal(v, item); err != nil {
+			pubqueue_log.Warningf("decode pubqueue item <%s> failed: %s", k, err)
+			return nil
+		}
+		items = append(items, item)
+		return nil
+	})
+	return items, err
+}
+
+// GetGroupItems list items of a group, filtered by state and trxId if they are not empty
+func (pq *PublishQueueWatcher) GetGroupItems(groupId string, state string, trxId string) ([]*PublishQueueItem, error) {
+	if groupId == "" {
+		return nil, fmt.Errorf("group id can't be empty")
+	}
+
+	items, err := pq.getItems(groupId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*PublishQueueItem{}
+	for _, item := range items {
+		if state != "" && item.State != state {
+			continue
+		}
+		if trxId != "" && item.Trx.TrxId != trxId {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// Ack remove finished (SUCCESS or FAIL) items from the queue, return trx ids which are removed
+func (pq *PublishQueueWatcher) Ack(trxIds []string) ([]string, error) {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+
+	wanted := make(map[string]bool)
+	for _, trxId := range trxIds {
+		wanted[trxId] = true
+	}
+
+	items, err := pq.getItems("")
+	if err != nil {
+		return nil, err
+	}
+
+	acked := []string{}
+	for _, item := range items {
+		if !wanted[item.Trx.TrxId] || item.State == PublishQueueItemStatePending {
+			continue
+		}
+		key := storage.GetPubQueueKey(item.GroupId, item.Trx.TrxId)
+		if err := pq.db.Delete([]byte(key)); err != nil {
+			return acked, err
+		}
+		acked = append(acked, item.Trx.TrxId)
+	}
+
+	return acked, nil
+}
+
+type resendTask struct {
+	group chaindef.GroupIface
+	trx   *quorumpb.Trx
+}
+
+func (pq *PublishQueueWatcher) doRefresh() {
+	tasks := pq.refreshItems()
+
+	//send outside the lock, sendTrx will try to enqueue the trx again
+	for _, task := range tasks {
+		pubqueue_log.Debugf("<%s> resend trx <%s>, retry <%d>", task.trx.GroupId, task.trx.TrxId, task.trx.ResendCount)
+		if _, err := task.group.SendRawTrx(task.trx); err != nil {
+			pubqueue_log.Warningf("<%s> resend trx <%s> failed: %s", task.trx.GroupId, task.trx.TrxId, err)
+		}
+	}
+}
+
+func (pq *PublishQueueWatcher) refreshItems() []*resendTask {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+
+	tasks := []*resendTask{}
+	items, err := pq.getItems("")
+	if err != nil {
+		pubqueue_log.Warningf("load pubqueue items failed: %s", err)
+		return tasks
+	}
+
+	for _, item := range items {
+		//SUCCESS and FAIL are final, they are kept till acked
+		if item.State != PublishQueueItemStatePending {
+			continue
+		}
+
+		group, err := pq.groupMgrIface.GetGroup(item.GroupId)
+		if err != nil {
+			//group left or not loaded yet, keep the item till it is acked
+			continue
+		}
+
+		if pq.isOnChain(group, item.Trx.TrxId) {
+			pubqueue_log.Debugf("<%s> trx <%s> is on chain", item.GroupId, item.Trx.TrxId)
+			pq.updateItem(item, PublishQueueItemStateSuccess)
+			continue
+		}
+
+		now := time.Now()
+		if item.Trx.Expired != 0 && now.UnixNano() > item.Trx.Expired {
+			//producers reject it now, wait for blocks packaged before it expired
+			if now.UnixNano() > item.Trx.Expired+int64(PUBQUEUE_EXPIRE_GRACE)*int64(time.Millisecond) {
+				pubqueue_log.Debugf("<%s> trx <%s> expired", item.GroupId, item.Trx.TrxId)
+				pq.updateItem(item, PublishQueueItemStateFail)
+			}
+			continue
+		}
+
+		if now.Sub(time.Unix(0, item.UpdateAt)) < resendDelay(item.RetryCount) {
+			continue
+		}
+
+		//resend a copy, conn will compress trx data in place
+		trx := proto.Clone(item.Trx).(*quorumpb.Trx)
+		trx.ResendCount = int64(item.RetryCount + 1)
+		tasks = append(tasks, &resendTask{group: group, trx: trx})
+
+		item.RetryCount += 1
+		pq.updateItem(item, PublishQueueItemStatePending)
+	}
+
+	return tasks
+}
+
+func (pq *PublishQueueWatcher) isOnChain(group chaindef.GroupIface, trxId string) bool {
+	trx, err := group.GetTrx(trxId)
+	if err == nil && trx != nil && trx.TrxId == trxId {
+		return true
+	}
+	return false
+}
+
+func (pq *PublishQueueWatcher) updateItem(item *PublishQueueItem, state string) {
+	item.State = state
+	item.UpdateAt = time.Now().UnixNano()
+
+	if state == PublishQueueItemStateSuccess && pq.autoAck {
+		key := storage.GetPubQueueKey(item.GroupId, item.Trx.TrxId)
+		if err := pq.db.Delete([]byte(key)); err != nil {
+			pubqueue_log.Warningf("<%s> auto ack trx <%s> failed: %s", item.GroupId, item.Trx.TrxId, err)
+		}
+		return
+	}
+
+	if err := pq.saveItem(item); err != nil {
+		pubqueue_log.Warningf("<%s> update pubqueue item <%s> failed: %s", item.GroupId, item.Trx.TrxId, err)
+	}
+}
+
+func resendDelay(retryCount int) time.Duration {
+	delay := PUBQUEUE_RESEND_BASE_DELAY
+	for i := 0; i < retryCount && delay < PUBQUEUE_RESEND_MAX_DELAY; i++ {
+		delay = delay * 2
+	}
+	if delay > PUBQUEUE_RESEND_MAX_DELAY {
+		delay = PUBQUEUE_RESEND_MAX_DELAY
+	}
+	return time.Duration(delay) * time.Millisecond
+}
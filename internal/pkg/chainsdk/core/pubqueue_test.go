@@ -0,0 +1,169 @@
+package chain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	chaindef "github.com/rumsystem/quorum/internal/pkg/chainsdk/def"
+	"github.com/rumsystem/quorum/internal/pkg/storage"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+)
+
+// fakePubQueueGroup takes trxs in onChain as packaged, and records trxs sent and lookups of trxs
+type fakePubQueueGroup struct {
+	chaindef.GroupIface
+	onChain map[string]bool
+	sent    []*quorumpb.Trx
+	lookups int
+}
+
+func (g *fakePubQueueGroup) SendRawTrx(trx *quorumpb.Trx) (string, error) {
+	g.sent = append(g.sent, trx)
+	return trx.TrxId, nil
+}
+
+func (g *fakePubQueueGroup) GetTrx(trxId string) (*quorumpb.Trx, error) {
+	g.lookups++
+	if g.onChain[trxId] {
+		return &quorumpb.Trx{TrxId: trxId}, nil
+	}
+	return nil, errors.New("trx not found")
+}
+
+type fakePubQueueGroupMgr struct {
+	groups map[string]chaindef.GroupIface
+}
+
+func (m *fakePubQueueGroupMgr) GetGroup(groupId string) (chaindef.GroupIface, error) {
+	if group, ok := m.groups[groupId]; ok {
+		return group, nil
+	}
+	return nil, errors.New("group not found")
+}
+
+func newTestPubQueue(autoAck bool) (*PublishQueueWatcher, *fakePubQueueGroup) {
+	group := &fakePubQueueGroup{onChain: make(map[string]bool)}
+	mgr := &fakePubQueueGroupMgr{groups: map[string]chaindef.GroupIface{"pqgroup": group}}
+	return &PublishQueueWatcher{db: storage.NewMemStore(), groupMgrIface: mgr, autoAck: autoAck}, group
+}
+
+func getPubQueueItem(t *testing.T, pq *PublishQueueWatcher, trxId string) *PublishQueueItem {
+	items, err := pq.GetGroupItems("pqgroup", "", trxId)
+	if err != nil {
+		t.Fatalf("GetGroupItems failed: %s", err)
+	}
+	if len(items) != 1 {
+		return nil
+	}
+	return items[0]
+}
+
+func TestPubQueueResendTillOnChain(t *testing.T) {
+	pq, group := newTestPubQueue(false)
+	baseDelay := PUBQUEUE_RESEND_BASE_DELAY
+	PUBQUEUE_RESEND_BASE_DELAY = 0
+	defer func() { PUBQUEUE_RESEND_BASE_DELAY = baseDelay }()
+
+	trx := &quorumpb.Trx{TrxId: "trx1", GroupId: "pqgroup", Expired: time.Now().Add(time.Minute).UnixNano()}
+	if err := pq.enqueue("pqgroup", trx); err != nil {
+		t.Fatalf("enqueue failed: %s", err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		pq.doRefresh()
+		//resend enqueues the trx again, it keeps the queued item
+		if err := pq.enqueue("pqgroup", trx); err != nil {
+			t.Fatalf("enqueue failed: %s", err)
+		}
+		item := getPubQueueItem(t, pq, "trx1")
+		if item == nil || item.State != PublishQueueItemStatePending || item.RetryCount != i {
+			t.Fatalf("resend <%d>, unexpected item <%+v>", i, item)
+		}
+		if len(group.sent) != i || group.sent[i-1].ResendCount != int64(i) {
+			t.Fatalf("resend <%d>, <%d> trxs sent", i, len(group.sent))
+		}
+	}
+
+	group.onChain["trx1"] = true
+	pq.doRefresh()
+	if item := getPubQueueItem(t, pq, "trx1"); item == nil || item.State != PublishQueueItemStateSuccess {
+		t.Fatalf("trx on chain, unexpected item <%+v>", item)
+	}
+	if len(group.sent) != 3 {
+		t.Fatal("trx on chain is resent")
+	}
+
+	//final items are not looked up again
+	lookups := group.lookups
+	pq.doRefresh()
+	if group.lookups != lookups {
+		t.Fatal("trx of SUCCESS item is looked up again")
+	}
+
+	acked, err := pq.Ack([]string{"trx1"})
+	if err != nil || len(acked) != 1 {
+		t.Fatalf("Ack got <%v> <%v>", acked, err)
+	}
+	if getPubQueueItem(t, pq, "trx1") != nil {
+		t.Fatal("acked item is not removed")
+	}
+}
+
+func TestPubQueueExpire(t *testing.T) {
+	pq, group := newTestPubQueue(false)
+	grace := time.Duration(PUBQUEUE_EXPIRE_GRACE) * time.Millisecond
+
+	expired := &quorumpb.Trx{TrxId: "expired", GroupId: "pqgroup", Expired: time.Now().Add(-grace - time.Second).UnixNano()}
+	packaged := &quorumpb.Trx{TrxId: "packaged", GroupId: "pqgroup", Expired: time.Now().Add(-time.Second).UnixNano()}
+	for _, trx := range []*quorumpb.Trx{expired, packaged} {
+		if err := pq.enqueue("pqgroup", trx); err != nil {
+			t.Fatalf("enqueue failed: %s", err)
+		}
+	}
+
+	//pending items can not be acked
+	if acked, _ := pq.Ack([]string{"packaged"}); len(acked) != 0 {
+		t.Fatalf("pending items acked <%v>", acked)
+	}
+
+	pq.doRefresh()
+	if len(group.sent) != 0 {
+		t.Fatalf("expired trxs are resent <%v>", group.sent)
+	}
+	if item := getPubQueueItem(t, pq, "expired"); item == nil || item.State != PublishQueueItemStateFail {
+		t.Fatalf("trx expired, unexpected item <%+v>", item)
+	}
+	if item := getPubQueueItem(t, pq, "packaged"); item == nil || item.State != PublishQueueItemStatePending {
+		t.Fatalf("trx expired in grace period, unexpected item <%+v>", item)
+	}
+
+	//block packaged before the trx expired arrives in the grace period
+	group.onChain["packaged"] = true
+	group.onChain["expired"] = true
+	lookups := group.lookups
+	pq.doRefresh()
+	if group.lookups != lookups+1 {
+		t.Fatalf("<%d> trxs looked up, expect only the pending one", group.lookups-lookups)
+	}
+	if item := getPubQueueItem(t, pq, "packaged"); item == nil || item.State != PublishQueueItemStateSuccess {
+		t.Fatalf("trx on chain, unexpected item <%+v>", item)
+	}
+	if item := getPubQueueItem(t, pq, "expired"); item == nil || item.State != PublishQueueItemStateFail {
+		t.Fatalf("FAIL is not final, unexpected item <%+v>", item)
+	}
+}
+
+func TestPubQueueAutoAck(t *testing.T) {
+	pq, group := newTestPubQueue(true)
+	trx := &quorumpb.Trx{TrxId: "trx1", GroupId: "pqgroup", Expired: time.Now().Add(time.Minute).UnixNano()}
+	if err := pq.enqueue("pqgroup", trx); err != nil {
+		t.Fatalf("enqueue failed: %s", err)
+	}
+
+	group.onChain["trx1"] = true
+	pq.doRefresh()
+	if item := getPubQueueItem(t, pq, "trx1"); item != nil {
+		t.Fatalf("SUCCESS item is not removed by auto ack <%+v>", item)
+	}
+}
@@ -3,30 +3,39 @@ package chain
 import (
 	"context"
 	"fmt"
-	"math/rand"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/rumsystem/quorum/internal/pkg/chainsdk/def"
 	"github.com/rumsystem/quorum/internal/pkg/conn"
 	"github.com/rumsystem/quorum/internal/pkg/logging"
 
+	rumchaindata "github.com/rumsystem/quorum/pkg/data"
 	quorumpb "github.com/rumsystem/quorum/pkg/pb"
-
-	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
 )
 
-var TASK_RETRY_NUM = 30                // task retry times
 var REQ_BLOCKS_PER_REQUEST = int32(10) // ask for n blocks per request
 var SYNC_BLOCK_TASK_TIMEOUT = 4 * 1000 // in millseconds
 var SYNC_BLOCK_FREQ_ADJ = 5 * 1000     // in millseconds
 var MAXIMUM_DELAY_DURATION = 60 * 1000 // is millseconds
+var MAX_SYNC_PROVIDERS = 3             // ranges requested at the same time, each from a different provider
+var SYNC_WINDOW_RANGES = 8             // ranges can be requested ahead of the last applied block
+var SYNC_SCHEDULE_INTERVAL = 500       // in millseconds
+var SYNC_RATE_WINDOW = 30 * 1000       // in millseconds, blocks/sec is calculated over it
+var MAX_SYNC_HISTORY = 50              // recent task outcomes kept for progress report
+var MAX_SNAPSHOT_PROVIDERS = 7         // providers asked for snapshot before syncing from blocks
+
+const SNAPSHOT_TASK_ID = uint64(0) // block sync tasks always start from block 1
 
 var rex_syncer_log = logging.Logger("rsyncer")
 
 type SyncResult struct {
-	TaskId uint64
-	Data   interface{}
+	TaskId   uint64
+	Provider peer.ID
+	Data     interface{} //*quorumpb.ReqBlockResp, *quorumpb.ReqSnapshotResp, or error if the req can't be sent
 }
 
 type SyncerStatus uint
@@ -37,33 +46,79 @@ const (
 	CLOSED
 )
 
+type SyncTaskType uint
+
+const (
+	SyncBlockTask SyncTaskType = iota
+	SyncSnapshotTask
+)
+
 type SyncTask struct {
-	TaskId      uint64 //block
+	Type        SyncTaskType
+	TaskId      uint64 //first block of the range
 	ReqBlockNum int32
-	DelayTime   int
-	TriggerTime int64
+	TriggerTime int64 //in nanoseconds, task will not be sent before it
+	Deadline    int64 //in nanoseconds
+	SentAt      int64 //in nanoseconds
+	RetryCount  int
+	Provider    peer.ID
 }
 
+// syncChain is the chain blocks are synced for, implemented by *Chain
+type syncChain interface {
+	GetTrxFactory() def.TrxFactoryIface
+	isOwnerByPubkey(pubkey string) bool
+	ApplyBlocks(blocks []*quorumpb.Block) error
+	CheckSnapshot(snapshot *quorumpb.Snapshot) error
+	ApplySnapshot(snapshots []*quorumpb.Snapshot) error
+}
+
+// syncPeers picks providers and sends requests to them, implemented by *conn.ConnMgr of the group
+type syncPeers interface {
+	GetSyncPeers(n int) []peer.ID
+	SendReqTrxRexToPeer(trx *quorumpb.Trx, p peer.ID) error
+	RewardSyncPeer(p peer.ID, blocks uint64)
+	PenalizeSyncPeer(p peer.ID)
+}
+
+// RexSyncer download blocks from several providers at the same time, each task asks a range of blocks from one provider,
+// results are buffered and applied in order. providers are picked by block provider score, slow or bad ones are penalized
 type RexSyncer struct {
 	GroupId  string
 	nodename string
 	cdnIface def.ChainDataSyncIface
-	chainCtx *Chain
+	chainCtx syncChain
+	getPeers func() (syncPeers, error)
 
-	//chan signals
-	taskq   chan *SyncTask
 	resultq chan *SyncResult
 
-	Status            SyncerStatus
-	mustatus          sync.RWMutex
-	CurrRetryCount    uint
-	CurrentDely       int
-	CurrentTask       *SyncTask
-	CurrentTaskCancel context.CancelFunc
+	mu            sync.Mutex
+	ctx           context.Context
+	cancel        context.CancelFunc
+	Status        SyncerStatus
+	snapshotTask  *SyncTask
+	snapshots     []*quorumpb.Snapshot   //snapshots received, applied when f + 1 producers signed the same state
+	snapshotPeers map[peer.ID]bool       //providers asked for snapshot
+	inflight      map[uint64]*SyncTask   //first block of range -> task
+	retryq        []*SyncTask            //timeout or failed tasks, will be sent to other providers
+	buffered      map[uint64]*SyncResult //first block of range -> result not applied yet
+	nextFrom      uint64                 //first block of next new range
+	topReached    bool                   //no more blocks from providers, stop asking new ranges in this round
+	topBlock      uint64                 //first block providers don't have
+	nextRoundAt   int64                  //in nanoseconds
+
+	highestSeen uint64
+	rateSamples []rateSample
+	history     []*def.SyncTaskOutcome
 
 	LastSyncResult *def.RexSyncResult
 }
 
+type rateSample struct {
+	at      int64 //in nanoseconds
+	blockId uint64
+}
+
 func NewRexSyncer(groupid string, nodename string, cdnIface def.ChainDataSyncIface, chainCtx *Chain) *RexSyncer {
 	rex_syncer_log.Debugf("<%s> NewRexSyncer called", groupid)
 
@@ -72,299 +127,673 @@ func NewRexSyncer(groupid string, nodename string, cdnIface def.ChainDataSyncIfa
 	rs.nodename = nodename
 	rs.cdnIface = cdnIface
 	rs.chainCtx = chainCtx
+	rs.getPeers = func() (syncPeers, error) {
+		return conn.GetConn().GetConnMgr(groupid)
+	}
 
 	rex_syncer_log.Debugf("<%s> Init rex syncer channels", rs.GroupId)
-	rs.taskq = make(chan *SyncTask)
 	rs.resultq = make(chan *SyncResult)
 
 	rs.Status = IDLE
-	rs.CurrentTask = nil
-	rs.CurrentDely = 0
-
 	rs.LastSyncResult = nil
 	return rs
 }
 
-func (rs *RexSyncer) GetCurrentTask() *SyncTask {
-	return rs.CurrentTask
-}
-
 func (rs *RexSyncer) GetSyncerStatus() SyncerStatus {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
 	return rs.Status
 }
 
 func (rs *RexSyncer) Start() {
 	rex_syncer_log.Debugf("<%s> Start called", rs.GroupId)
 
-	//start taskq
-	go func() {
-		for task := range rs.taskq {
-			if rs.Status == CLOSED {
-				return
-			}
-			//calculate current delay
-			task.DelayTime += int(rs.CurrRetryCount)*SYNC_BLOCK_FREQ_ADJ + rs.CurrentDely
-			if task.DelayTime > MAXIMUM_DELAY_DURATION {
-				task.DelayTime = MAXIMUM_DELAY_DURATION
-			}
-
-			task.TriggerTime = time.Now().Unix() + int64(task.DelayTime)/1000
-			taskTimeout := task.DelayTime + SYNC_BLOCK_TASK_TIMEOUT
-			rex_syncer_log.Debugf("<%s> get task <%d> from taskq, set task timeout to <%d>", rs.GroupId, task.TaskId, taskTimeout)
-			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(taskTimeout)*time.Millisecond)
-			rs.runTask(ctx, task, cancel)
-		}
-	}()
+	rs.mu.Lock()
+	if rs.cancel != nil {
+		rs.mu.Unlock()
+		rex_syncer_log.Debugf("<%s> rexsyncer is running", rs.GroupId)
+		return
+	}
 
-	//start resultq
-	go func() {
-		for result := range rs.resultq {
-			if rs.Status == CLOSED {
-				return
-			}
-			rs.handleResult(result)
-		}
-	}()
+	rs.ctx, rs.cancel = context.WithCancel(context.Background())
+	rs.Status = IDLE
+	rs.resetRound()
+
+	//a new joined node try to get a snapshot first, fallback to sync blocks if no snapshot available
+	rs.snapshotTask = nil
+	rs.snapshots = nil
+	rs.snapshotPeers = make(map[peer.ID]bool)
+	if isSnapshotEnabled() && rs.cdnIface.GetCurrBlockId() == 0 {
+		rs.snapshotTask = rs.newSyncSnapshotTask()
+	}
+	ctx := rs.ctx
+	rs.mu.Unlock()
 
-	task := rs.newSyncBlockTask()
-	rs.AddTask(task)
+	go rs.run(ctx)
 }
 
 func (rs *RexSyncer) Stop() {
 	rex_syncer_log.Debugf("<%s> Stop called", rs.GroupId)
-	rs.mustatus.Lock()
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
 	rs.Status = CLOSED
-	rs.mustatus.Unlock()
-	// TODO: don't close channel until destory all related timers
-	//safeCloseTaskQ(rs.taskq)
-	//safeCloseResultQ(rs.resultq)
+	if rs.cancel != nil {
+		rs.cancel()
+		rs.cancel = nil
+	}
 	rex_syncer_log.Debugf("<%s> rexsyncer stop success.", rs.GroupId)
 }
 
 func (rs *RexSyncer) GetLastRexSyncResult() (*def.RexSyncResult, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
 	if rs.LastSyncResult == nil {
 		return nil, fmt.Errorf("no valid rex sync result yet")
 	}
 
-	if rs.CurrentTask != nil {
-		rs.LastSyncResult.NextSyncTaskTimeStamp = rs.CurrentTask.TriggerTime
+	result := *rs.LastSyncResult
+	if rs.topReached {
+		result.NextSyncTaskTimeStamp = rs.nextRoundAt / int64(time.Second)
 	} else {
-		return nil, fmt.Errorf("try again")
+		result.NextSyncTaskTimeStamp = time.Now().Unix()
 	}
-
-	return rs.LastSyncResult, nil
+	return &result, nil
 }
 
-func safeClose(ch chan struct{}) (recovered bool) {
-	defer func() {
-		if recover() != nil {
-			recovered = true
+func (rs *RexSyncer) AddResult(result *SyncResult) {
+	rs.mu.Lock()
+	ctx := rs.ctx
+	rs.mu.Unlock()
+
+	if ctx == nil {
+		return
+	}
+
+	go func() {
+		select {
+		case rs.resultq <- result:
+		case <-ctx.Done():
 		}
 	}()
-	if ch == nil {
-		return false
-	}
-	close(ch)
-	return false
 }
 
-func safeCloseTaskQ(ch chan *SyncTask) (recovered bool) {
-	defer func() {
-		if recover() != nil {
-			recovered = true
+func (rs *RexSyncer) run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(SYNC_SCHEDULE_INTERVAL) * time.Millisecond)
+	defer ticker.Stop()
+
+	rs.schedule()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case result := <-rs.resultq:
+			rs.handleResult(result)
+			rs.schedule()
+		case <-ticker.C:
+			rs.schedule()
 		}
-	}()
-	if ch == nil {
-		return false
 	}
-	close(ch)
-	return false
 }
 
-func safeCloseResultQ(ch chan *SyncResult) (recovered bool) {
-	defer func() {
-		if recover() != nil {
-			recovered = true
+func (rs *RexSyncer) resetRound() {
+	rs.inflight = make(map[uint64]*SyncTask)
+	rs.retryq = nil
+	rs.buffered = make(map[uint64]*SyncResult)
+	rs.nextFrom = rs.cdnIface.GetCurrBlockId() + 1
+	rs.topReached = false
+	rs.topBlock = 0
+}
+
+// schedule check timeout tasks and send tasks to idle providers
+func (rs *RexSyncer) schedule() {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.Status == CLOSED {
+		return
+	}
+
+	now := time.Now().UnixNano()
+	rs.sampleRate(now)
+	rs.checkTimeout(now)
+
+	connMgr, err := rs.getPeers()
+	if err != nil {
+		return
+	}
+
+	//get snapshot before syncing blocks, each provider is asked once
+	if rs.snapshotTask != nil {
+		if rs.snapshotTask.Provider == "" {
+			peers := connMgr.GetSyncPeers(MAX_SNAPSHOT_PROVIDERS)
+			for _, p := range peers {
+				if !rs.snapshotPeers[p] {
+					rs.snapshotPeers[p] = true
+					rs.sendTask(connMgr, rs.snapshotTask, p, now)
+					break
+				}
+			}
+
+			//all providers asked, no snapshot confirmed
+			if len(peers) > 0 && rs.snapshotTask.Provider == "" {
+				rex_syncer_log.Debugf("<%s> no snapshot confirmed by <%d> providers, sync from blocks", rs.GroupId, len(peers))
+				rs.snapshotTask = nil
+				rs.snapshots = nil
+			}
+		}
+		if rs.snapshotTask != nil {
+			return
 		}
-	}()
-	if ch == nil {
-		return false
 	}
-	close(ch)
-	return false
-}
 
-func (rs *RexSyncer) runTask(ctx context.Context, task *SyncTask, cancel context.CancelFunc) error {
-	//TODO: close this goroutine when the processTask func return. add some defer signal?
-	rex_syncer_log.Debugf("runTask called, taskId <%d>, retry <%d>", task.TaskId, rs.CurrRetryCount)
-	go func() {
-		rs.CurrentTask = task //set current task
-		rs.CurrentTaskCancel = cancel
-		err := rs.syncBlockTaskSender(task)
-		if err != nil {
-			rex_syncer_log.Debugf("todo add retry task <%d>", task.TaskId)
-			//retry
+	if rs.topReached && len(rs.inflight) == 0 && len(rs.retryq) == 0 {
+		rs.Status = IDLE
+		if now < rs.nextRoundAt {
+			return
 		}
-	}()
+		rex_syncer_log.Debugf("<%s> start next sync round from block <%d>", rs.GroupId, rs.cdnIface.GetCurrBlockId()+1)
+		rs.resetRound()
+	}
 
-	select {
-	//case <-rs.taskdone:
-	//	return nil
-	case <-ctx.Done():
-		switch ctx.Err() {
-		case context.DeadlineExceeded:
-			if rs.Status != CLOSED {
-				//a workround, should cancel the ctx for current task
-				if rs.CurrentTask != nil {
-					rex_syncer_log.Debugf("task <%d> timeout", task.TaskId)
-					rs.CurrRetryCount += 1
-					rex_syncer_log.Debugf("CurrRetryCount <%d>", rs.CurrRetryCount)
-
-					//remove current task
-					rs.CurrentTask = nil
-					rs.CurrentTaskCancel = nil
-					rs.Status = IDLE
-
-					//start next round with highest epoch
-					task := rs.newSyncBlockTask()
-					rs.AddTask(task)
-				}
+	if len(rs.inflight) < MAX_SYNC_PROVIDERS {
+		busy := make(map[peer.ID]bool)
+		for _, task := range rs.inflight {
+			busy[task.Provider] = true
+		}
+
+		currBlockId := rs.cdnIface.GetCurrBlockId()
+		for _, p := range connMgr.GetSyncPeers(MAX_SYNC_PROVIDERS + len(rs.inflight)) {
+			if len(rs.inflight) >= MAX_SYNC_PROVIDERS {
+				break
+			}
+			if busy[p] {
+				continue
+			}
+			task := rs.nextTask(currBlockId, now)
+			if task == nil {
+				break
 			}
-		case context.Canceled:
-			rex_syncer_log.Debugf("task <%d> done", task.TaskId)
+			busy[p] = true
+			rs.sendTask(connMgr, task, p, now)
 		}
-		return nil
+	}
+
+	if len(rs.inflight) > 0 {
+		rs.Status = SYNCING
+	} else {
+		rs.Status = IDLE
 	}
 }
 
-func (rs *RexSyncer) AddTask(task *SyncTask) {
-	rex_syncer_log.Debugf("Gsyncer addTask called")
-	go func() {
-		rs.mustatus.Lock()
-		defer rs.mustatus.Unlock()
-		if rs.Status != CLOSED {
-			rs.taskq <- task
+func (rs *RexSyncer) checkTimeout(now int64) {
+	if rs.snapshotTask != nil && rs.snapshotTask.Provider != "" && now > rs.snapshotTask.Deadline {
+		//provider without snapshot will not response
+		rex_syncer_log.Debugf("<%s> snapshot task timeout, ask another provider", rs.GroupId)
+		rs.recordOutcome(rs.snapshotTask, "TIMEOUT", 0, now)
+		rs.nextSnapshotProvider()
+	}
+
+	for from, task := range rs.inflight {
+		if now <= task.Deadline {
+			continue
 		}
-	}()
+		rex_syncer_log.Debugf("<%s> task <%d> timeout, provider <%s>", rs.GroupId, from, task.Provider)
+		rs.recordOutcome(task, "TIMEOUT", 0, now)
+		rs.penalize(task.Provider)
+		delete(rs.inflight, from)
+		rs.retryTask(task, now)
+	}
 }
 
-func (rs *RexSyncer) AddResult(result *SyncResult) {
-	go func() {
-		rs.mustatus.Lock()
-		defer rs.mustatus.Unlock()
-		if rs.Status != CLOSED {
-			rs.resultq <- result
+// nextTask return a task ready to send, failed tasks first, nil if nothing to do
+func (rs *RexSyncer) nextTask(currBlockId uint64, now int64) *SyncTask {
+	for i := 0; i < len(rs.retryq); i++ {
+		task := rs.retryq[i]
+		_, inflight := rs.inflight[task.TaskId]
+		_, buffered := rs.buffered[task.TaskId]
+		if task.TaskId+uint64(task.ReqBlockNum) <= currBlockId+1 || inflight || buffered {
+			//all blocks of the range already applied, or the range is being fetched by another task
+			rs.retryq = append(rs.retryq[:i], rs.retryq[i+1:]...)
+			i--
+			continue
 		}
-	}()
-}
+		if task.TriggerTime <= now {
+			rs.retryq = append(rs.retryq[:i], rs.retryq[i+1:]...)
+			return task
+		}
+	}
 
-// task generators
-func (rs *RexSyncer) newSyncBlockTask() *SyncTask {
-	rex_syncer_log.Debugf("<%s> newSyncBlockTask called", rs.GroupId)
-	nextBlock := rs.cdnIface.GetCurrBlockId() + uint64(1)
-	randDelay := rand.Intn(500)
-	return &SyncTask{TaskId: nextBlock, ReqBlockNum: REQ_BLOCKS_PER_REQUEST, DelayTime: randDelay}
+	if rs.topReached {
+		return nil
+	}
+
+	if rs.nextFrom <= currBlockId {
+		rs.nextFrom = currBlockId + 1
+	}
+
+	//don't go too far ahead of applied blocks
+	window := currBlockId + 1 + uint64(SYNC_WINDOW_RANGES)*uint64(REQ_BLOCKS_PER_REQUEST)
+	if rs.nextFrom >= window {
+		return nil
+	}
+
+	task := rs.newSyncBlockTask(rs.nextFrom)
+	rs.nextFrom += uint64(REQ_BLOCKS_PER_REQUEST)
+	return task
 }
 
-func (rs *RexSyncer) syncBlockTaskSender(task *SyncTask) error {
-	rex_syncer_log.Debugf("<%s> syncBlockTaskSender called", rs.GroupId)
+func (rs *RexSyncer) retryTask(task *SyncTask, now int64) {
+	if rs.topReached && task.TaskId >= rs.topBlock {
+		return
+	}
+
+	//retry with another provider immediately, then slow down
+	delay := (task.RetryCount) * SYNC_BLOCK_FREQ_ADJ
+	if delay > MAXIMUM_DELAY_DURATION {
+		delay = MAXIMUM_DELAY_DURATION
+	}
 
-	var trx *quorumpb.Trx
-	var trxerr error
+	task.RetryCount += 1
+	task.Provider = ""
+	task.TriggerTime = now + int64(delay)*int64(time.Millisecond)
+	rs.retryq = append(rs.retryq, task)
+}
 
-	trx, trxerr = rs.chainCtx.GetTrxFactory().GetReqBlocksTrx("", rs.GroupId, task.TaskId, task.ReqBlockNum)
-	if trxerr != nil {
-		return trxerr
+func (rs *RexSyncer) setTopReached(topBlock uint64, delay int, now int64) {
+	if !rs.topReached || topBlock < rs.topBlock {
+		rs.topBlock = topBlock
 	}
+	rs.topReached = true
+	rs.nextRoundAt = now + int64(delay)*int64(time.Millisecond)
+}
 
-	connMgr, err := conn.GetConn().GetConnMgr(rs.GroupId)
-	if err != nil {
-		return err
+func (rs *RexSyncer) sendTask(connMgr syncPeers, task *SyncTask, p peer.ID, now int64) {
+	task.Provider = p
+	task.SentAt = now
+	task.Deadline = now + int64(SYNC_BLOCK_TASK_TIMEOUT)*int64(time.Millisecond)
+	if task.Type == SyncBlockTask {
+		rs.inflight[task.TaskId] = task
 	}
 
-	rex_syncer_log.Debugf("<%s> sleep <%d> millseconds before send the req", rs.GroupId, task.DelayTime)
-	time.Sleep(time.Duration(task.DelayTime) * time.Millisecond)
+	go func() {
+		var trx *quorumpb.Trx
+		var err error
+		if task.Type == SyncSnapshotTask {
+			rex_syncer_log.Debugf("<%s> ask snapshot from <%s>", rs.GroupId, p)
+			trx, err = rs.chainCtx.GetTrxFactory().GetReqSnapshotTrx("", rs.GroupId)
+		} else {
+			rex_syncer_log.Debugf("<%s> ask <%d> blocks from block <%d>, provider <%s>, retry <%d>", rs.GroupId, task.ReqBlockNum, task.TaskId, p, task.RetryCount)
+			trx, err = rs.chainCtx.GetTrxFactory().GetReqBlocksTrx("", rs.GroupId, task.TaskId, task.ReqBlockNum)
+		}
+
+		if err == nil {
+			err = connMgr.SendReqTrxRexToPeer(trx, p)
+		}
+
+		if err != nil {
+			rex_syncer_log.Debugf("<%s> send task <%d> to <%s> failed: %s", rs.GroupId, task.TaskId, p, err)
+			rs.AddResult(&SyncResult{TaskId: task.TaskId, Provider: p, Data: err})
+		}
+	}()
+}
+
+// task generators
+func (rs *RexSyncer) newSyncBlockTask(fromBlock uint64) *SyncTask {
+	return &SyncTask{Type: SyncBlockTask, TaskId: fromBlock, ReqBlockNum: REQ_BLOCKS_PER_REQUEST}
+}
 
-	//set status to SYNCING since the syncing task is always running and the "real" sync work (after send out reqBlock) only start after sleep
-	rs.Status = SYNCING
-	return connMgr.SendReqTrxRex(trx)
+func (rs *RexSyncer) newSyncSnapshotTask() *SyncTask {
+	rex_syncer_log.Debugf("<%s> newSyncSnapshotTask called", rs.GroupId)
+	return &SyncTask{Type: SyncSnapshotTask, TaskId: SNAPSHOT_TASK_ID}
 }
 
-func (rs *RexSyncer) handleResult(result *SyncResult) error {
+func (rs *RexSyncer) handleResult(result *SyncResult) {
 	rex_syncer_log.Debugf("<%s> handleResult called", rs.GroupId)
-	//check if the resp is what we are waiting for
-	if rs.CurrentTask == nil {
-		rex_syncer_log.Debugf("<%s> CurrentTask is nil, ignore", rs.GroupId)
-		return rumerrors.ErrTaskIdMismatch
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.Status == CLOSED {
+		return
+	}
+
+	now := time.Now().UnixNano()
+	switch data := result.Data.(type) {
+	case *quorumpb.ReqSnapshotResp:
+		rs.handleSnapshotResult(result.Provider, data)
+	case *quorumpb.ReqBlockResp:
+		rs.handleBlockResult(result, data, now)
+	case error:
+		if result.TaskId == SNAPSHOT_TASK_ID {
+			if rs.snapshotTask != nil && rs.snapshotTask.Provider == result.Provider {
+				rs.recordOutcome(rs.snapshotTask, "SEND_FAILED", 0, now)
+				rs.penalize(result.Provider)
+				rs.nextSnapshotProvider()
+			}
+			return
+		}
+
+		task, ok := rs.inflight[result.TaskId]
+		if ok && task.Provider == result.Provider {
+			rs.recordOutcome(task, "SEND_FAILED", 0, now)
+			rs.penalize(result.Provider)
+			delete(rs.inflight, result.TaskId)
+			rs.retryTask(task, now)
+		}
 	}
-	if result.TaskId != rs.CurrentTask.TaskId {
-		//chain_log.Warningf("<%s> HandleReqBlockResp error <%s>", sr.groupId, rumerrors.ErrEpochMismatch)
-		return rumerrors.ErrTaskIdMismatch
+}
+
+func (rs *RexSyncer) handleBlockResult(result *SyncResult, resp *quorumpb.ReqBlockResp, now int64) {
+	task, ok := rs.inflight[resp.FromBlock]
+	if !ok || task.Provider != result.Provider {
+		//late resp of a timeout task, or resp of a previous round
+		rex_syncer_log.Debugf("<%s> no task waiting for resp from block <%d>, ignore", rs.GroupId, resp.FromBlock)
+		return
 	}
+	delete(rs.inflight, resp.FromBlock)
 
-	reqBlockResp := result.Data.(*quorumpb.ReqBlockResp)
+	var blocks []*quorumpb.Block
+	if resp.Blocks != nil {
+		blocks = resp.Blocks.Blocks
+	}
+	rs.recordOutcome(task, resp.Result.String(), len(blocks), now)
+	if len(blocks) > 0 {
+		rs.updHighestSeen(blocks[len(blocks)-1].BlockId)
+	}
 
 	rex_syncer_log.Debugf("- Receive valid reqBlockResp, provider <%s> result <%s> from block <%d> total <%d> blocks provided",
-		reqBlockResp.ProviderPubkey,
-		reqBlockResp.Result.String(),
-		reqBlockResp.FromBlock,
-		len(reqBlockResp.Blocks.Blocks))
-
-	//Since a valid response is retrieved, finish current task
-	/*
-		only 1 producer (owner) is supported in this version
-		node should only accept BLOCK_NOT_FOUND from group owner and ignore all other BLOCK_NOT_FOUND msg
-		TBD, stop only when received BLOCK_NOT_FOUND from F + 1 producers, otherwise continue sync
-	*/
-
-	//check if resp is from owner
-	isOwner := rs.chainCtx.isOwnerByPubkey(reqBlockResp.ProviderPubkey)
-
-	switch reqBlockResp.Result {
+		resp.ProviderPubkey,
+		resp.Result.String(),
+		resp.FromBlock,
+		len(blocks))
+
+	rs.LastSyncResult = &def.RexSyncResult{
+		Provider:              resp.ProviderPubkey,
+		FromBlock:             resp.FromBlock,
+		BlockProvided:         resp.BlksProvided,
+		SyncResult:            resp.Result.String(),
+		LastSyncTaskTimestamp: time.Now().Unix(),
+		NextSyncTaskTimeStamp: -1,
+	}
+
+	//BLOCK_NOT_FOUND from owner means no more blocks, other providers may fall behind
+	isOwner := rs.chainCtx.isOwnerByPubkey(resp.ProviderPubkey)
+
+	switch resp.Result {
 	case quorumpb.ReqBlkResult_BLOCK_NOT_FOUND:
-		if isOwner {
-			rs.CurrentDely = MAXIMUM_DELAY_DURATION
-			chain_log.Debugf("<%s> receive BLOCK_NOT_FOUND from group owner, set delay to <%d>", rs.GroupId, rs.CurrentDely)
+		if isPrunedResp(resp, blocks) && !isValidHeader(blocks[0]) {
+			rex_syncer_log.Warningf("<%s> invalid pruned range header from <%s>", rs.GroupId, resp.ProviderPubkey)
+			rs.penalize(result.Provider)
+			rs.retryTask(task, now)
+		} else if isPrunedResp(resp, blocks) {
+			//provider only keeps headers of the range, ask others, a node joined after all providers pruned the range needs a snapshot
+			if task.RetryCount+1 >= MAX_SYNC_PROVIDERS {
+				rex_syncer_log.Warningf("<%s> block <%d> pruned by <%d> providers, set delay to <%d>", rs.GroupId, resp.FromBlock, task.RetryCount+1, MAXIMUM_DELAY_DURATION)
+				rs.setTopReached(resp.FromBlock, MAXIMUM_DELAY_DURATION, now)
+			} else {
+				rex_syncer_log.Debugf("<%s> block <%d> pruned by <%s>, full blocks start from <%d>", rs.GroupId, resp.FromBlock, resp.ProviderPubkey, blocks[0].BlockId)
+				rs.retryTask(task, now)
+			}
+		} else if isOwner {
+			rex_syncer_log.Debugf("<%s> receive BLOCK_NOT_FOUND from group owner, set delay to <%d>", rs.GroupId, MAXIMUM_DELAY_DURATION)
+			rs.setTopReached(resp.FromBlock, MAXIMUM_DELAY_DURATION, now)
+			rs.updHighestSeen(resp.FromBlock - 1)
+		} else if task.RetryCount+1 >= MAX_SYNC_PROVIDERS {
+			rex_syncer_log.Debugf("<%s> block <%d> not found by <%d> providers, set delay to <%d>", rs.GroupId, resp.FromBlock, task.RetryCount+1, SYNC_BLOCK_FREQ_ADJ)
+			rs.setTopReached(resp.FromBlock, SYNC_BLOCK_FREQ_ADJ, now)
+		} else {
+			rs.retryTask(task, now)
 		}
 
 	case quorumpb.ReqBlkResult_BLOCK_IN_RESP_ON_TOP:
-		rs.chainCtx.ApplyBlocks(reqBlockResp.Blocks.Blocks)
+		rs.reward(result.Provider, len(blocks))
+		rs.buffered[resp.FromBlock] = result
+		delay := SYNC_BLOCK_FREQ_ADJ
 		if isOwner {
-			rs.CurrentDely = MAXIMUM_DELAY_DURATION
-			chain_log.Debugf("<%s> receive BLOCK_IN_RESP_ON_TOP from group owner, apply blocks, set task delay to <%d>", rs.GroupId, rs.CurrentDely)
+			delay = MAXIMUM_DELAY_DURATION
 		}
+		rex_syncer_log.Debugf("<%s> receive BLOCK_IN_RESP_ON_TOP from <%s>, set delay to <%d>", rs.GroupId, resp.ProviderPubkey, delay)
+		rs.setTopReached(resp.FromBlock+uint64(len(blocks)), delay, now)
 
 	case quorumpb.ReqBlkResult_BLOCK_IN_RESP:
-		rs.CurrentDely = 0
-		chain_log.Debugf("<%s> HandleReqBlockResp - receive BLOCK_IN_RESP from node <%s>, apply all blocks and reset syncer timer to <%d>", rs.GroupId, reqBlockResp.ProviderPubkey, rs.CurrentDely)
-		rs.chainCtx.ApplyBlocks(reqBlockResp.Blocks.Blocks)
+		rs.reward(result.Provider, len(blocks))
+		rs.buffered[resp.FromBlock] = result
 	default:
 
 	}
 
-	//received something, reset current retry count
-	rs.CurrRetryCount = 0
+	rs.applyBuffered(now)
+}
+
+// isPrunedResp return true if the provider answered with the header of its first full block, which means
+// the requested block is pruned by its retention policy
+func isPrunedResp(resp *quorumpb.ReqBlockResp, blocks []*quorumpb.Block) bool {
+	return len(blocks) == 1 && blocks[0].BlockId > resp.FromBlock
+}
+
+// isValidHeader checks the header a provider sent for its pruned range, the header has no trxs
+func isValidHeader(header *quorumpb.Block) bool {
+	valid, err := rumchaindata.ValidBlockHeader(header)
+	return err == nil && valid
+}
+
+// applyBuffered apply buffered blocks in order, stop at the first gap.
+// a range is kept till the top block moves past it, blocks of it cached by the chain wait for the gap repairer
+func (rs *RexSyncer) applyBuffered(now int64) {
+	for {
+		currBlockId := rs.cdnIface.GetCurrBlockId()
+
+		//the lowest range connected to the top block
+		var from uint64
+		var result *SyncResult
+		for k, r := range rs.buffered {
+			if k <= currBlockId+1 && (result == nil || k < from) {
+				from, result = k, r
+			}
+		}
+		if result == nil {
+			return
+		}
+
+		resp := result.Data.(*quorumpb.ReqBlockResp)
+		var blocks []*quorumpb.Block
+		for _, block := range resp.Blocks.Blocks {
+			if block.BlockId > currBlockId {
+				blocks = append(blocks, block)
+			}
+		}
+		if len(blocks) == 0 {
+			delete(rs.buffered, from)
+			continue
+		}
+
+		rex_syncer_log.Debugf("<%s> apply <%d> blocks from block <%d>", rs.GroupId, len(blocks), blocks[0].BlockId)
+		if err := rs.chainCtx.ApplyBlocks(blocks); err != nil {
+			rex_syncer_log.Warningf("<%s> apply blocks from <%s> failed with error <%s>", rs.GroupId, result.Provider, err.Error())
+			delete(rs.buffered, from)
+			rs.penalize(result.Provider)
+			rs.recordOutcome(&SyncTask{TaskId: from, Provider: result.Provider, SentAt: now}, "APPLY_FAILED", len(blocks), now)
+			//ask the same range from another provider, blocks already applied are skipped when it comes back
+			rs.retryTask(rs.newSyncBlockTask(from), now)
+			return
+		}
+
+		if rs.cdnIface.GetCurrBlockId() == currBlockId {
+			//blocks are cached, wait for the missing ones
+			return
+		}
+	}
+}
+
+func (rs *RexSyncer) handleSnapshotResult(provider peer.ID, resp *quorumpb.ReqSnapshotResp) {
+	if rs.snapshotTask == nil || rs.snapshotTask.Provider != provider {
+		return
+	}
+	task := rs.snapshotTask
+
+	rex_syncer_log.Debugf("- Receive valid reqSnapshotResp, provider <%s>", resp.ProviderPubkey)
+	if resp.Snapshot == nil {
+		rs.recordOutcome(task, "SNAPSHOT_INVALID", 0, time.Now().UnixNano())
+		rs.penalize(provider)
+		rs.nextSnapshotProvider()
+		return
+	}
+
+	if err := rs.chainCtx.CheckSnapshot(resp.Snapshot); err != nil {
+		rex_syncer_log.Warningf("<%s> snapshot from <%s> is invalid, error <%s>", rs.GroupId, resp.ProviderPubkey, err.Error())
+		rs.recordOutcome(task, "SNAPSHOT_INVALID", 0, time.Now().UnixNano())
+		rs.penalize(provider)
+		rs.nextSnapshotProvider()
+		return
+	}
+
+	//a single producer can not make others apply its state, wait for the same state signed by f + 1 producers
+	rs.snapshots = append(rs.snapshots, resp.Snapshot)
+	if err := rs.chainCtx.ApplySnapshot(rs.snapshots); err != nil {
+		rex_syncer_log.Debugf("<%s> snapshot from <%s> not applied, error <%s>", rs.GroupId, resp.ProviderPubkey, err.Error())
+		rs.recordOutcome(task, "SNAPSHOT_UNCONFIRMED", 0, time.Now().UnixNano())
+		rs.nextSnapshotProvider()
+		return
+	}
+	rs.snapshotTask = nil
+	rs.snapshots = nil
+	rs.recordOutcome(task, "SNAPSHOT_APPLIED", 1, time.Now().UnixNano())
+	rs.updHighestSeen(resp.Snapshot.Block.BlockId)
 
 	rs.LastSyncResult = &def.RexSyncResult{
-		Provider:              reqBlockResp.ProviderPubkey,
-		FromBlock:             reqBlockResp.FromBlock,
-		BlockProvided:         reqBlockResp.BlksProvided,
-		SyncResult:            reqBlockResp.Result.String(),
+		Provider:              resp.ProviderPubkey,
+		FromBlock:             resp.Snapshot.Block.BlockId,
+		BlockProvided:         1,
+		SyncResult:            "SNAPSHOT_APPLIED",
 		LastSyncTaskTimestamp: time.Now().Unix(),
 		NextSyncTaskTimeStamp: -1,
 	}
 
-	//rs.taskdone <- struct{}{}
-	if rs.CurrentTaskCancel != nil {
-		rs.CurrentTaskCancel()
+	//continue with blocks after the snapshot
+	rs.resetRound()
+}
+
+// nextSnapshotProvider ask snapshot from another provider, sync from blocks if too many providers asked
+func (rs *RexSyncer) nextSnapshotProvider() {
+	rs.snapshotTask.RetryCount += 1
+	rs.snapshotTask.Provider = ""
+	if rs.snapshotTask.RetryCount >= MAX_SNAPSHOT_PROVIDERS {
+		rex_syncer_log.Debugf("<%s> no snapshot confirmed by <%d> providers, sync from blocks", rs.GroupId, rs.snapshotTask.RetryCount)
+		rs.snapshotTask = nil
+		rs.snapshots = nil
+	}
+}
+
+// UpdHighestBlockSeen is called when a block from peers received
+func (rs *RexSyncer) UpdHighestBlockSeen(blockId uint64) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.updHighestSeen(blockId)
+}
+
+func (rs *RexSyncer) updHighestSeen(blockId uint64) {
+	if blockId > rs.highestSeen {
+		rs.highestSeen = blockId
 	}
-	rs.CurrentTaskCancel = nil
-	rs.CurrentTask = nil
-	rs.Status = IDLE
+}
+
+func (rs *RexSyncer) sampleRate(now int64) {
+	rs.rateSamples = append(rs.rateSamples, rateSample{at: now, blockId: rs.cdnIface.GetCurrBlockId()})
+
+	expired := now - int64(SYNC_RATE_WINDOW)*int64(time.Millisecond)
+	i := 0
+	for i < len(rs.rateSamples)-1 && rs.rateSamples[i].at < expired {
+		i++
+	}
+	rs.rateSamples = rs.rateSamples[i:]
+}
+
+func (rs *RexSyncer) recordOutcome(task *SyncTask, result string, blocks int, now int64) {
+	outcome := &def.SyncTaskOutcome{
+		TaskId:         task.TaskId,
+		Provider:       task.Provider.String(),
+		Result:         result,
+		BlocksProvided: blocks,
+		Duration:       (now - task.SentAt) / int64(time.Millisecond),
+		TimeStamp:      now,
+	}
+
+	rs.history = append(rs.history, outcome)
+	if len(rs.history) > MAX_SYNC_HISTORY {
+		rs.history = rs.history[len(rs.history)-MAX_SYNC_HISTORY:]
+	}
+}
+
+// GetSyncProgress report local top block, highest block seen from peers, sync rate, ETA, current providers and recent task outcomes
+func (rs *RexSyncer) GetSyncProgress() *def.SyncProgress {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	currBlockId := rs.cdnIface.GetCurrBlockId()
+	highest := rs.highestSeen
+	if highest < currBlockId {
+		highest = currBlockId
+	}
+
+	progress := &def.SyncProgress{
+		GroupId:          rs.GroupId,
+		LocalTopBlock:    currBlockId,
+		HighestBlockSeen: highest,
+		ETA:              -1,
+		Providers:        []*def.SyncProviderInfo{},
+		History:          make([]*def.SyncTaskOutcome, len(rs.history)),
+		TimeStamp:        time.Now().UnixNano(),
+	}
+	copy(progress.History, rs.history)
+
+	if len(rs.rateSamples) > 1 {
+		first := rs.rateSamples[0]
+		last := rs.rateSamples[len(rs.rateSamples)-1]
+		seconds := float64(last.at-first.at) / float64(time.Second)
+		if seconds > 0 && last.blockId >= first.blockId {
+			progress.BlocksPerSecond = float64(last.blockId-first.blockId) / seconds
+		}
+	}
+
+	if highest == currBlockId {
+		progress.ETA = 0
+	} else if progress.BlocksPerSecond > 0 {
+		progress.ETA = int64(float64(highest-currBlockId) / progress.BlocksPerSecond)
+	}
+
+	if rs.snapshotTask != nil && rs.snapshotTask.Provider != "" {
+		progress.Providers = append(progress.Providers, &def.SyncProviderInfo{Peer: rs.snapshotTask.Provider.String(), SentAt: rs.snapshotTask.SentAt})
+	}
+	for _, task := range rs.inflight {
+		progress.Providers = append(progress.Providers, &def.SyncProviderInfo{
+			Peer:      task.Provider.String(),
+			FromBlock: task.TaskId,
+			ReqBlocks: task.ReqBlockNum,
+			SentAt:    task.SentAt,
+		})
+	}
+	sort.Slice(progress.Providers, func(i, j int) bool {
+		return progress.Providers[i].FromBlock < progress.Providers[j].FromBlock
+	})
+
+	return progress
+}
 
-	//start next round
-	task := rs.newSyncBlockTask()
-	rs.AddTask(task)
+func (rs *RexSyncer) reward(p peer.ID, blocks int) {
+	if connMgr, err := rs.getPeers(); err == nil && blocks > 0 {
+		connMgr.RewardSyncPeer(p, uint64(blocks))
+	}
+}
+
+func (rs *RexSyncer) penalize(p peer.ID) {
+	if connMgr, err := rs.getPeers(); err == nil {
+		connMgr.PenalizeSyncPeer(p)
+	}
+}
 
-	return nil
+func streamPeer(s network.Stream) peer.ID {
+	if s == nil {
+		return ""
+	}
+	return s.Conn().RemotePeer()
 }
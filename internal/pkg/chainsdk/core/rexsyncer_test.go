@@ -0,0 +1,290 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rumsystem/quorum/internal/pkg/chainsdk/def"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+)
+
+// fakeSyncChain applies blocks in order, a block with hash "bad" fails to apply
+type fakeSyncChain struct {
+	def.TrxFactoryIface
+	currBlockId uint64
+	applied     []uint64
+}
+
+func (c *fakeSyncChain) HandlePsConnMessage(pkg *quorumpb.Package) error { return nil }
+func (c *fakeSyncChain) HandleTrxPsConn(trx *quorumpb.Trx) error         { return nil }
+func (c *fakeSyncChain) HandleBlockPsConn(block *quorumpb.Block) error   { return nil }
+func (c *fakeSyncChain) HandleTrxRex(trx *quorumpb.Trx, fromstream network.Stream) error {
+	return nil
+}
+func (c *fakeSyncChain) HandleBlockRex(block *quorumpb.Block, fromstream network.Stream) error {
+	return nil
+}
+func (c *fakeSyncChain) HandleHBPsConn(hb *quorumpb.HBMsgv1) error { return nil }
+func (c *fakeSyncChain) HandleHBRex(hb *quorumpb.HBMsgv1) error    { return nil }
+func (c *fakeSyncChain) GetCurrBlockId() uint64                    { return c.currBlockId }
+
+func (c *fakeSyncChain) GetTrxFactory() def.TrxFactoryIface { return c }
+func (c *fakeSyncChain) isOwnerByPubkey(pubkey string) bool { return pubkey == "owner" }
+
+func (c *fakeSyncChain) GetReqBlocksTrx(keyalias string, groupId string, fromBlock uint64, blkReq int32) (*quorumpb.Trx, error) {
+	return &quorumpb.Trx{TrxId: fmt.Sprintf("req-%d", fromBlock)}, nil
+}
+
+func (c *fakeSyncChain) GetReqSnapshotTrx(keyalias string, groupId string) (*quorumpb.Trx, error) {
+	return &quorumpb.Trx{TrxId: "req-snapshot"}, nil
+}
+
+func (c *fakeSyncChain) ApplyBlocks(blocks []*quorumpb.Block) error {
+	for _, block := range blocks {
+		if block.BlockId != c.currBlockId+1 {
+			//not the next one, a real chain caches it
+			return nil
+		}
+		if string(block.BlockHash) == "bad" {
+			return errors.New("invalid block")
+		}
+		c.currBlockId = block.BlockId
+		c.applied = append(c.applied, block.BlockId)
+	}
+	return nil
+}
+
+func (c *fakeSyncChain) CheckSnapshot(snapshot *quorumpb.Snapshot) error    { return nil }
+func (c *fakeSyncChain) ApplySnapshot(snapshots []*quorumpb.Snapshot) error { return nil }
+
+type fakeSyncPeers struct {
+	mu        sync.Mutex
+	peers     []peer.ID
+	penalized map[peer.ID]int
+}
+
+func (p *fakeSyncPeers) GetSyncPeers(n int) []peer.ID {
+	if n > len(p.peers) {
+		n = len(p.peers)
+	}
+	return p.peers[:n]
+}
+
+func (p *fakeSyncPeers) SendReqTrxRexToPeer(trx *quorumpb.Trx, pid peer.ID) error { return nil }
+func (p *fakeSyncPeers) RewardSyncPeer(pid peer.ID, blocks uint64)                {}
+
+func (p *fakeSyncPeers) PenalizeSyncPeer(pid peer.ID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.penalized[pid]++
+}
+
+func newTestRexSyncer(t *testing.T, providers int) (*RexSyncer, *fakeSyncChain, *fakeSyncPeers) {
+	chain := &fakeSyncChain{}
+	peers := &fakeSyncPeers{penalized: make(map[peer.ID]int)}
+	for i := 0; i < providers; i++ {
+		peers.peers = append(peers.peers, peer.ID(fmt.Sprintf("provider-%d", i)))
+	}
+
+	rs := NewRexSyncer("syncgroup", "syncnode", chain, nil)
+	rs.chainCtx = chain
+	rs.getPeers = func() (syncPeers, error) { return peers, nil }
+	rs.snapshotPeers = make(map[peer.ID]bool)
+	rs.resetRound()
+	return rs, chain, peers
+}
+
+// blocksResp answers the inflight task of the range from block from, ids in [from, to]
+func blocksResp(t *testing.T, rs *RexSyncer, from, to uint64, result quorumpb.ReqBlkResult, bad uint64) *SyncResult {
+	task, ok := rs.inflight[from]
+	if !ok {
+		t.Fatalf("no task for range from <%d>, inflight <%v>", from, rs.inflight)
+	}
+	bundle := &quorumpb.BlocksBundle{}
+	for id := from; id <= to; id++ {
+		block := &quorumpb.Block{BlockId: id}
+		if id == bad {
+			block.BlockHash = []byte("bad")
+		}
+		bundle.Blocks = append(bundle.Blocks, block)
+	}
+	resp := &quorumpb.ReqBlockResp{
+		ProviderPubkey: string(task.Provider),
+		Result:         result,
+		FromBlock:      from,
+		BlksRequested:  task.ReqBlockNum,
+		BlksProvided:   int32(len(bundle.Blocks)),
+		Blocks:         bundle,
+	}
+	return &SyncResult{TaskId: from, Provider: task.Provider, Data: resp}
+}
+
+func TestRexSyncerApplyInOrder(t *testing.T) {
+	rs, chain, _ := newTestRexSyncer(t, 3)
+	rs.schedule()
+	if len(rs.inflight) != MAX_SYNC_PROVIDERS {
+		t.Fatalf("<%d> tasks inflight, expect <%d>", len(rs.inflight), MAX_SYNC_PROVIDERS)
+	}
+	providers := make(map[peer.ID]bool)
+	for _, task := range rs.inflight {
+		providers[task.Provider] = true
+	}
+	if len(providers) != MAX_SYNC_PROVIDERS {
+		t.Fatalf("tasks are sent to <%d> providers", len(providers))
+	}
+
+	//later ranges come first, they are kept till the gap is filled
+	rs.handleResult(blocksResp(t, rs, 21, 30, quorumpb.ReqBlkResult_BLOCK_IN_RESP, 0))
+	rs.handleResult(blocksResp(t, rs, 11, 20, quorumpb.ReqBlkResult_BLOCK_IN_RESP, 0))
+	if chain.currBlockId != 0 || len(rs.buffered) != 2 {
+		t.Fatalf("applied to <%d> with block 1 missing, <%d> ranges buffered", chain.currBlockId, len(rs.buffered))
+	}
+	rs.handleResult(blocksResp(t, rs, 1, 10, quorumpb.ReqBlkResult_BLOCK_IN_RESP, 0))
+	if chain.currBlockId != 30 || len(rs.buffered) != 0 {
+		t.Fatalf("applied to <%d>, <%d> ranges buffered", chain.currBlockId, len(rs.buffered))
+	}
+	for i, id := range chain.applied {
+		if id != uint64(i+1) {
+			t.Fatalf("blocks applied out of order: %v", chain.applied)
+		}
+	}
+
+	//new ranges follow the applied ones
+	rs.schedule()
+	for _, from := range []uint64{31, 41, 51} {
+		if _, ok := rs.inflight[from]; !ok {
+			t.Fatalf("range from <%d> is not requested, inflight <%v>", from, rs.inflight)
+		}
+	}
+}
+
+func TestRexSyncerRetryApplyFailed(t *testing.T) {
+	rs, chain, peers := newTestRexSyncer(t, 4)
+	rs.schedule()
+	inflight := make(map[uint64]peer.ID)
+	for from, task := range rs.inflight {
+		inflight[from] = task.Provider
+	}
+
+	//block 5 is invalid, blocks 1 - 4 are applied, the range is asked again
+	bad := rs.inflight[1].Provider
+	rs.handleResult(blocksResp(t, rs, 1, 10, quorumpb.ReqBlkResult_BLOCK_IN_RESP, 5))
+	if chain.currBlockId != 4 {
+		t.Fatalf("applied to <%d>, expect 4", chain.currBlockId)
+	}
+	if peers.penalized[bad] != 1 {
+		t.Fatal("provider of invalid blocks is not penalized")
+	}
+	if len(rs.retryq) != 1 || rs.retryq[0].TaskId != 1 {
+		t.Fatalf("retry queue <%v>, expect the failed range", rs.retryq)
+	}
+
+	rs.schedule()
+	task, ok := rs.inflight[1]
+	if !ok {
+		t.Fatal("failed range is not retried")
+	}
+	if task.RetryCount != 1 {
+		t.Fatalf("retry count <%d>", task.RetryCount)
+	}
+	for from, p := range inflight {
+		if from == 1 {
+			continue
+		}
+		if rs.inflight[from] == nil || rs.inflight[from].Provider != p {
+			t.Fatalf("task of range <%d> is replaced by the retry", from)
+		}
+		if task.Provider == p {
+			t.Fatalf("retry is sent to <%s>, which is busy with range <%d>", p, from)
+		}
+	}
+
+	//a retry of a range already inflight or buffered is dropped instead of replacing it
+	rs.handleResult(blocksResp(t, rs, 21, 30, quorumpb.ReqBlkResult_BLOCK_IN_RESP, 0))
+	rs.retryq = append(rs.retryq, rs.newSyncBlockTask(11), rs.newSyncBlockTask(21))
+	sent := rs.inflight[11]
+	rs.schedule()
+	if rs.inflight[11] != sent || rs.inflight[21] != nil || len(rs.retryq) != 0 {
+		t.Fatal("inflight task is replaced by a retry of the same range")
+	}
+	if _, ok := rs.inflight[31]; !ok {
+		t.Fatalf("next range is not requested, inflight <%v>", rs.inflight)
+	}
+
+	rs.handleResult(blocksResp(t, rs, 1, 10, quorumpb.ReqBlkResult_BLOCK_IN_RESP, 0))
+	rs.handleResult(blocksResp(t, rs, 11, 20, quorumpb.ReqBlkResult_BLOCK_IN_RESP, 0))
+	if chain.currBlockId != 30 {
+		t.Fatalf("applied to <%d> after retry, expect 30", chain.currBlockId)
+	}
+	for i, id := range chain.applied {
+		if id != uint64(i+1) {
+			t.Fatalf("blocks applied out of order: %v", chain.applied)
+		}
+	}
+}
+
+func TestRexSyncerKeepBufferedTillApplied(t *testing.T) {
+	rs, chain, _ := newTestRexSyncer(t, 3)
+	rs.schedule()
+
+	//block 1 is missing in the resp, blocks of the range are only cached by the chain
+	result := blocksResp(t, rs, 1, 10, quorumpb.ReqBlkResult_BLOCK_IN_RESP, 0)
+	resp := result.Data.(*quorumpb.ReqBlockResp)
+	resp.Blocks.Blocks = resp.Blocks.Blocks[1:]
+	rs.handleResult(result)
+	rs.handleResult(blocksResp(t, rs, 11, 20, quorumpb.ReqBlkResult_BLOCK_IN_RESP, 0))
+	if chain.currBlockId != 0 || rs.buffered[1] == nil || rs.buffered[11] == nil {
+		t.Fatalf("applied to <%d>, buffered <%v>, expect ranges kept", chain.currBlockId, rs.buffered)
+	}
+
+	//block 1 is got by the gap repairer, the kept range goes on from it
+	chain.ApplyBlocks([]*quorumpb.Block{{BlockId: 1}})
+	rs.handleResult(blocksResp(t, rs, 21, 30, quorumpb.ReqBlkResult_BLOCK_IN_RESP, 0))
+	if chain.currBlockId != 30 || len(rs.buffered) != 0 {
+		t.Fatalf("applied to <%d>, <%d> ranges buffered", chain.currBlockId, len(rs.buffered))
+	}
+	for i, id := range chain.applied {
+		if id != uint64(i+1) {
+			t.Fatalf("blocks applied out of order: %v", chain.applied)
+		}
+	}
+}
+
+func TestRexSyncerTopReached(t *testing.T) {
+	rs, chain, _ := newTestRexSyncer(t, 3)
+	rs.schedule()
+
+	rs.handleResult(blocksResp(t, rs, 1, 5, quorumpb.ReqBlkResult_BLOCK_IN_RESP_ON_TOP, 0))
+	if !rs.topReached || rs.topBlock != 6 || chain.currBlockId != 5 {
+		t.Fatalf("top reached <%v> top block <%d> applied to <%d>", rs.topReached, rs.topBlock, chain.currBlockId)
+	}
+
+	//ranges above the top are not retried
+	rs.handleResult(&SyncResult{TaskId: 11, Provider: rs.inflight[11].Provider, Data: errors.New("send failed")})
+	if len(rs.retryq) != 0 {
+		t.Fatalf("range above the top is retried: %v", rs.retryq)
+	}
+
+	//owner has no more blocks
+	task := rs.inflight[21]
+	resp := &quorumpb.ReqBlockResp{ProviderPubkey: "owner", Result: quorumpb.ReqBlkResult_BLOCK_NOT_FOUND, FromBlock: 21, BlksRequested: task.ReqBlockNum}
+	rs.handleResult(&SyncResult{TaskId: 21, Provider: task.Provider, Data: resp})
+	if len(rs.inflight) != 0 {
+		t.Fatalf("<%d> tasks inflight", len(rs.inflight))
+	}
+
+	//no new ranges are asked till the next round
+	rs.schedule()
+	if len(rs.inflight) != 0 || rs.GetSyncerStatus() != IDLE {
+		t.Fatalf("<%d> tasks sent after top reached, status <%d>", len(rs.inflight), rs.Status)
+	}
+	rs.nextRoundAt = 0
+	rs.schedule()
+	if _, ok := rs.inflight[6]; !ok || rs.topReached {
+		t.Fatalf("next round does not start from block 6, inflight <%v>", rs.inflight)
+	}
+}
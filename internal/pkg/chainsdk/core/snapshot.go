@@ -0,0 +1,246 @@
+package chain
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/rumsystem/quorum/internal/pkg/conn"
+	"github.com/rumsystem/quorum/internal/pkg/logging"
+	"github.com/rumsystem/quorum/internal/pkg/nodectx"
+	"github.com/rumsystem/quorum/internal/pkg/options"
+	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
+	rumchaindata "github.com/rumsystem/quorum/pkg/data"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+var snapshot_log = logging.Logger("snapshot")
+
+var SNAPSHOT_BLOCK_INTERVAL = uint64(100) // producer creates a new snapshot every n blocks
+
+func isSnapshotEnabled() bool {
+	nodeoptions := options.GetNodeOptions()
+	return nodeoptions != nil && nodeoptions.EnableSnapshot
+}
+
+// TryCreateSnapshot is called by consensus after blocks applied,
+// producer creates a snapshot of the derived chain state if enough blocks applied since last one
+func (chain *Chain) TryCreateSnapshot() {
+	if !isSnapshotEnabled() || !chain.isProducer() {
+		return
+	}
+
+	//all producers create snapshots at the same blocks, so a joining node can get the same state signed by several of them
+	currBlockId := chain.GetCurrBlockId()
+	if currBlockId == 0 || currBlockId%SNAPSHOT_BLOCK_INTERVAL != 0 {
+		return
+	}
+
+	last, err := nodectx.GetNodeCtx().GetChainStorage().GetSnapshot(chain.groupItem.GroupId, chain.nodename)
+	if err != nil {
+		snapshot_log.Warningf("<%s> get snapshot failed with error <%s>", chain.groupItem.GroupId, err.Error())
+		return
+	}
+
+	if last != nil && last.Block.BlockId >= currBlockId {
+		return
+	}
+
+	if err := chain.createSnapshot(currBlockId); err != nil {
+		snapshot_log.Warningf("<%s> create snapshot at block <%d> failed with error <%s>", chain.groupItem.GroupId, currBlockId, err.Error())
+	}
+}
+
+func (chain *Chain) createSnapshot(blockId uint64) error {
+	snapshot_log.Debugf("<%s> createSnapshot called, block <%d>", chain.groupItem.GroupId, blockId)
+
+	block, err := nodectx.GetNodeCtx().GetChainStorage().GetBlock(chain.groupItem.GroupId, blockId, false, chain.nodename)
+	if err != nil {
+		return err
+	}
+
+	items, err := nodectx.GetNodeCtx().GetChainStorage().GetSnapshotItems(chain.groupItem.GroupId, chain.nodename)
+	if err != nil {
+		return err
+	}
+
+	ks := localcrypto.GetKeystore()
+	//epoch of the block, not the current one, it is the same on all producers
+	snapshot, err := rumchaindata.CreateSnapshotByEthKey(chain.groupItem.GroupId, block.Epoch, block, items, chain.groupItem.UserSignPubkey, ks, "")
+	if err != nil {
+		return err
+	}
+
+	snapshot_log.Infof("<%s> snapshot created at block <%d>, <%d> items", chain.groupItem.GroupId, blockId, len(items))
+	return nodectx.GetNodeCtx().GetChainStorage().SaveSnapshot(snapshot, chain.nodename)
+}
+
+func (chain *Chain) handleReqSnapshot(trx *quorumpb.Trx, s network.Stream) error {
+	snapshot_log.Debugf("<%s> handleReqSnapshot called", chain.groupItem.GroupId)
+	if !isSnapshotEnabled() {
+		return nil
+	}
+
+	ciperKey, err := hex.DecodeString(chain.groupItem.CipherKey)
+	if err != nil {
+		return err
+	}
+
+	decryptData, err := localcrypto.AesDecode(trx.Data, ciperKey)
+	if err != nil {
+		return err
+	}
+
+	reqSnapshot := &quorumpb.ReqSnapshot{}
+	if err := proto.Unmarshal(decryptData, reqSnapshot); err != nil {
+		return err
+	}
+
+	if trx.SenderPubkey != reqSnapshot.ReqPubkey {
+		return errors.New("trx sender/snapshot requester mismatch")
+	}
+
+	//same privilege as request blocks
+	isAllow, err := nodectx.GetNodeCtx().GetChainStorage().CheckTrxTypeAuth(trx.GroupId, trx.SenderPubkey, quorumpb.TrxType_REQ_BLOCK, chain.nodename)
+	if err != nil {
+		return err
+	}
+	if !isAllow {
+		snapshot_log.Debugf("<%s> user <%s>: trxType <%s> is denied", chain.groupItem.GroupId, trx.SenderPubkey, quorumpb.TrxType_REQ_BLOCK.String())
+		return errors.New("requester don't have sufficient privileges")
+	}
+
+	snapshot, err := nodectx.GetNodeCtx().GetChainStorage().GetSnapshot(chain.groupItem.GroupId, chain.nodename)
+	if err != nil {
+		return err
+	}
+
+	//no snapshot yet, requester will sync from blocks after timeout
+	if snapshot == nil {
+		return nil
+	}
+
+	snapshot_log.Debugf("<%s> send REQ_SNAPSHOT_RESP, block <%d> to <%s>", chain.groupItem.GroupId, snapshot.Block.BlockId, reqSnapshot.ReqPubkey)
+	trx, err = chain.trxFactory.GetReqSnapshotRespTrx("", chain.groupItem.GroupId, reqSnapshot.ReqPubkey, snapshot)
+	if err != nil {
+		return err
+	}
+
+	cmgr, err := conn.GetConn().GetConnMgr(chain.groupItem.GroupId)
+	if err != nil {
+		return err
+	}
+	return cmgr.SendRespTrxRex(trx, s)
+}
+
+func (chain *Chain) handleReqSnapshotResp(trx *quorumpb.Trx, s network.Stream) {
+	snapshot_log.Debugf("<%s> handleReqSnapshotResp called", chain.groupItem.GroupId)
+
+	ciperKey, err := hex.DecodeString(chain.groupItem.CipherKey)
+	if err != nil {
+		snapshot_log.Warningf("<%s> handleReqSnapshotResp error <%s>", chain.groupItem.GroupId, err.Error())
+		return
+	}
+
+	decryptData, err := localcrypto.AesDecode(trx.Data, ciperKey)
+	if err != nil {
+		snapshot_log.Warningf("<%s> handleReqSnapshotResp error <%s>", chain.groupItem.GroupId, err.Error())
+		return
+	}
+
+	resp := &quorumpb.ReqSnapshotResp{}
+	if err := proto.Unmarshal(decryptData, resp); err != nil {
+		snapshot_log.Warningf("<%s> handleReqSnapshotResp error <%s>", chain.groupItem.GroupId, err.Error())
+		return
+	}
+
+	//if not asked by me, ignore it
+	if resp.RequesterPubkey != chain.groupItem.UserSignPubkey {
+		return
+	}
+
+	if trx.SenderPubkey != resp.ProviderPubkey {
+		snapshot_log.Debugf("<%s> handleReqSnapshotResp - Trx Sender/snapshot provider mismatch", chain.groupItem.GroupId)
+		return
+	}
+
+	chain.rexSyncer.AddResult(&SyncResult{
+		TaskId:   SNAPSHOT_TASK_ID,
+		Provider: streamPeer(s),
+		Data:     resp,
+	})
+}
+
+// snapshotSigners return producers trusted to sign a snapshot, and how many of them are needed (f + 1),
+// a new joined node knows the owner only, it trusts snapshot from the owner
+func (chain *Chain) snapshotSigners() (map[string]bool, int) {
+	producers := map[string]bool{chain.groupItem.OwnerPubKey: true}
+	for pubkey := range chain.producerPool {
+		producers[pubkey] = true
+	}
+	return producers, (len(producers)-1)/3 + 1
+}
+
+func (chain *Chain) checkSnapshotBlock(snapshot *quorumpb.Snapshot) error {
+	if snapshot.GroupId != chain.groupItem.GroupId {
+		return fmt.Errorf("snapshot group <%s> mismatch", snapshot.GroupId)
+	}
+
+	if snapshot.Block == nil || snapshot.Block.BlockId <= chain.GetCurrBlockId() {
+		return fmt.Errorf("snapshot block is not newer than current block <%d>", chain.GetCurrBlockId())
+	}
+	return nil
+}
+
+// CheckSnapshot verify a snapshot is signed by a producer and newer than local chain
+func (chain *Chain) CheckSnapshot(snapshot *quorumpb.Snapshot) error {
+	if err := chain.checkSnapshotBlock(snapshot); err != nil {
+		return err
+	}
+
+	producers, _ := chain.snapshotSigners()
+	_, err := rumchaindata.VerifySnapshotQuorum([]*quorumpb.Snapshot{snapshot}, producers, 1)
+	return err
+}
+
+// ApplySnapshot replace local chain state with the state signed by f + 1 producers in snapshots, only works for a chain behind the snapshot
+func (chain *Chain) ApplySnapshot(snapshots []*quorumpb.Snapshot) error {
+	snapshot_log.Debugf("<%s> ApplySnapshot called, <%d> snapshots", chain.groupItem.GroupId, len(snapshots))
+
+	producers, quorum := chain.snapshotSigners()
+	snapshot, err := rumchaindata.VerifySnapshotQuorum(snapshots, producers, quorum)
+	if err != nil {
+		return err
+	}
+
+	if err := chain.checkSnapshotBlock(snapshot); err != nil {
+		return err
+	}
+
+	cs := nodectx.GetNodeCtx().GetChainStorage()
+	if err := cs.ApplySnapshotItems(chain.groupItem.GroupId, snapshot.Items, chain.nodename); err != nil {
+		return err
+	}
+
+	if err := cs.AddBlock(snapshot.Block, false, chain.nodename); err != nil {
+		return err
+	}
+
+	chain.SetCurrBlockId(snapshot.Block.BlockId)
+	chain.SetCurrEpoch(snapshot.Epoch)
+	chain.SetLastUpdate(snapshot.Block.TimeStamp)
+	if err := chain.SaveChainInfoToDb(); err != nil {
+		return err
+	}
+
+	//reload state from the snapshot
+	chain.updProducerList()
+	chain.updUserList()
+	chain.updProducerConfig()
+	chain.UpdConnMgrProducer()
+
+	snapshot_log.Infof("<%s> snapshot applied, continue sync from block <%d>", chain.groupItem.GroupId, snapshot.Block.BlockId+1)
+	return nil
+}
@@ -0,0 +1,299 @@
+package chain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	chaindef "github.com/rumsystem/quorum/internal/pkg/chainsdk/def"
+	"github.com/rumsystem/quorum/internal/pkg/logging"
+	"github.com/rumsystem/quorum/internal/pkg/nodectx"
+	chainstorage "github.com/rumsystem/quorum/internal/pkg/storage/chain"
+	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
+	rumchaindata "github.com/rumsystem/quorum/pkg/data"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+var verifier_log = logging.Logger("verifier")
+
+var VERIFY_PROGRESS_INTERVAL = uint64(10000) // log verify progress every n blocks
+
+// kinds of chain issues
+const (
+	IssueMissingBlock    = "MISSING_BLOCK"
+	IssueInvalidBlock    = "INVALID_BLOCK"
+	IssueUnknownProducer = "UNKNOWN_PRODUCER"
+	IssueInvalidTrx      = "INVALID_TRX"
+	IssueStateMismatch   = "STATE_MISMATCH"
+)
+
+// VerifyGroupChain walks blocks of a group from genesis to the top block, checks their linkage, hashes, producer
+// signs and trx signs, and replays PRODUCER, USER and APP_CONFIG trxs to cross-check the derived tables.
+// producer node does not apply APP_CONFIG trxs, so its app config table is not checked.
+// if repair is true, mismatched derived tables are replaced by the replay result, broken blocks are only reported
+func VerifyGroupChain(cs *chainstorage.Storage, item *quorumpb.GroupItem, nodename string, producerNode bool, repair bool) (*chaindef.ChainVerifyResult, error) {
+	groupId := item.GroupId
+	topBlock, _, _, err := cs.GetChainInfo(groupId, nodename)
+	if err != nil {
+		return nil, err
+	}
+	pruned, err := cs.GetPruneState(groupId, nodename)
+	if err != nil {
+		return nil, err
+	}
+	ciperKey, err := hex.DecodeString(item.CipherKey)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &chaindef.ChainVerifyResult{
+		GroupId:   groupId,
+		TopBlock:  topBlock,
+		PrunedTo:  pruned.PrunedTo,
+		Issues:    []*chaindef.ChainIssue{},
+		Repaired:  []string{},
+		TimeStamp: time.Now().UnixNano(),
+	}
+	addIssue := func(blockId uint64, kind string, format string, a ...interface{}) {
+		result.Issues = append(result.Issues, &chaindef.ChainIssue{BlockId: blockId, Kind: kind, Detail: fmt.Sprintf(format, a...)})
+	}
+
+	//the owner is added as the first producer when the group is created, not by a trx
+	ownerPubkey, _ := localcrypto.Libp2pPubkeyToEthBase64(item.OwnerPubKey)
+	replay := cs.NewReplayStorage()
+	producers, err := cs.GetProducers(groupId, nodename)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range producers {
+		if pk, _ := localcrypto.Libp2pPubkeyToEthBase64(p.ProducerPubkey); pk == ownerPubkey {
+			if err := replay.AddProducer(p, nodename); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	//state can be replayed only if all trxs from genesis are kept
+	replayable := true
+	applied := make(map[string]bool)
+	var parent *quorumpb.Block
+	var missingFrom uint64
+	missing := false
+	for blockId := uint64(0); blockId <= topBlock; blockId++ {
+		if blockId > 0 && blockId%VERIFY_PROGRESS_INTERVAL == 0 {
+			verifier_log.Infof("<%s> verify block <%d/%d>", groupId, blockId, topBlock)
+		}
+
+		exist, err := cs.IsBlockExist(groupId, blockId, false, nodename)
+		if err != nil {
+			return nil, err
+		}
+		if !exist {
+			//a group joined by snapshot has no blocks before the snapshot block
+			if !missing {
+				missing = true
+				missingFrom = blockId
+			}
+			parent = nil
+			replayable = false
+			continue
+		}
+		if missing {
+			addIssue(missingFrom, IssueMissingBlock, "blocks %d to %d not found", missingFrom, blockId-1)
+			missing = false
+		}
+
+		block, err := cs.GetBlock(groupId, blockId, false, nodename)
+		if err != nil {
+			addIssue(blockId, IssueInvalidBlock, "read block failed: %s", err)
+			parent = nil
+			replayable = false
+			continue
+		}
+		result.CheckedBlocks++
+
+		isPruned := blockId > 0 && blockId <= pruned.PrunedTo
+		if err := verifyBlock(block, parent, isPruned); err != nil {
+			addIssue(blockId, IssueInvalidBlock, "%s", err)
+		}
+		parent = block
+
+		//producer set in effect is the one before trxs of the block are applied
+		if blockId > 0 && replayable {
+			if ok, err := isProducerOf(replay, groupId, block.ProducerPubkey, ownerPubkey, nodename); err != nil {
+				return nil, err
+			} else if !ok {
+				addIssue(blockId, IssueUnknownProducer, "block produced by <%s> which is not a producer", block.ProducerPubkey)
+			}
+		}
+
+		if isPruned {
+			replayable = false
+			continue
+		}
+		for _, trx := range block.Trxs {
+			result.CheckedTrxs++
+			if ok, err := rumchaindata.VerifyTrx(trx); !ok {
+				addIssue(blockId, IssueInvalidTrx, "trx <%s> sign verify failed: %v", trx.TrxId, err)
+			}
+			if !replayable || applied[trx.TrxId] {
+				continue
+			}
+			if producerNode && trx.Type == quorumpb.TrxType_APP_CONFIG {
+				continue
+			}
+			applied[trx.TrxId] = true
+			replayTrx(replay, trx, ciperKey, nodename)
+		}
+	}
+
+	if missing {
+		addIssue(missingFrom, IssueMissingBlock, "blocks %d to %d not found", missingFrom, topBlock)
+	}
+
+	if !replayable {
+		verifier_log.Infof("<%s> derived tables not checked, blocks from genesis are not all kept", groupId)
+		return result, nil
+	}
+	result.StateChecked = true
+
+	for _, table := range chainstorage.StateTables {
+		if producerNode && table == chainstorage.StateAppConfig {
+			continue
+		}
+		local, err := cs.GetStateTable(groupId, table, nodename)
+		if err != nil {
+			return nil, err
+		}
+		expected, err := replay.GetStateTable(groupId, table, nodename)
+		if err != nil {
+			return nil, err
+		}
+		diffs := diffStateTable(local, expected)
+		for _, d := range diffs {
+			addIssue(topBlock, IssueStateMismatch, "%s: %s", table, d)
+		}
+		if len(diffs) == 0 || !repair {
+			continue
+		}
+
+		if err := cs.ReplaceStateTable(groupId, table, expected, nodename); err != nil {
+			return nil, err
+		}
+		verifier_log.Infof("<%s> derived table <%s> repaired, %d items", groupId, table, len(expected))
+		result.Repaired = append(result.Repaired, table)
+	}
+	return result, nil
+}
+
+// verifyBlock checks a block against its parent, parent is nil if it is unknown.
+// pruned blocks have no trxs, only their headers are checked
+func verifyBlock(block, parent *quorumpb.Block, isPruned bool) error {
+	if block.BlockId == 0 {
+		if valid, err := rumchaindata.ValidGenesisBlock(block); !valid {
+			return fmt.Errorf("invalid genesis block: %v", err)
+		}
+		return nil
+	}
+
+	var valid bool
+	var err error
+	switch {
+	case isPruned && len(block.Trxs) == 0:
+		if parent != nil && !bytes.Equal(block.PrevHash, parent.BlockHash) {
+			return fmt.Errorf("prevhash mismatch with parent block")
+		}
+		valid, err = rumchaindata.ValidBlockHeader(block)
+	case parent != nil:
+		valid, err = rumchaindata.ValidBlockWithParent(block, parent)
+	default:
+		valid, err = rumchaindata.ValidBlock(block)
+	}
+	if !valid {
+		if err == nil {
+			err = fmt.Errorf("producer sign verify failed")
+		}
+		return err
+	}
+	return nil
+}
+
+func isProducerOf(cs *chainstorage.Storage, groupId string, pubkey string, ownerPubkey string, nodename string) (bool, error) {
+	pk, _ := localcrypto.Libp2pPubkeyToEthBase64(pubkey)
+	if pk == ownerPubkey {
+		return true, nil
+	}
+	producers, err := cs.GetProducers(groupId, nodename)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range producers {
+		if ppk, _ := localcrypto.Libp2pPubkeyToEthBase64(p.ProducerPubkey); ppk == pk {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// replayTrx applies a trx to derived tables the same way as the chain does, failed trxs are ignored by the chain too
+func replayTrx(cs *chainstorage.Storage, trx *quorumpb.Trx, ciperKey []byte, nodename string) {
+	if trx.Type != quorumpb.TrxType_PRODUCER && trx.Type != quorumpb.TrxType_USER && trx.Type != quorumpb.TrxType_APP_CONFIG {
+		return
+	}
+	decryptData, err := localcrypto.AesDecode(trx.Data, ciperKey)
+	if err != nil {
+		verifier_log.Debugf("<%s> decrypt trx <%s> failed: %s", trx.GroupId, trx.TrxId, err)
+		return
+	}
+
+	decrypted := proto.Clone(trx).(*quorumpb.Trx)
+	decrypted.Data = decryptData
+	switch trx.Type {
+	case quorumpb.TrxType_PRODUCER:
+		err = cs.UpdateProducerTrx(decrypted, nodename)
+	case quorumpb.TrxType_USER:
+		err = cs.UpdateUserTrx(decrypted, nodename)
+	case quorumpb.TrxType_APP_CONFIG:
+		err = cs.UpdateAppConfigTrx(decrypted, nodename)
+	}
+	if err != nil {
+		verifier_log.Debugf("<%s> replay trx <%s> failed: %s", trx.GroupId, trx.TrxId, err)
+	}
+}
+
+func diffStateTable(local, expected map[string][]byte) []string {
+	var diffs []string
+	for k, v := range expected {
+		lv, ok := local[k]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("key <%s> missing", k))
+		} else if !bytes.Equal(lv, v) {
+			diffs = append(diffs, fmt.Sprintf("key <%s> differs from replay", k))
+		}
+	}
+	for k := range local {
+		if _, ok := expected[k]; !ok {
+			diffs = append(diffs, fmt.Sprintf("key <%s> not in replay", k))
+		}
+	}
+	sort.Strings(diffs)
+	return diffs
+}
+
+// VerifyChain verifies the chain of the group, in-memory producer and user lists are reloaded if derived tables are repaired
+func (chain *Chain) VerifyChain(repair bool) (*chaindef.ChainVerifyResult, error) {
+	producerNode := nodectx.GetNodeCtx().NodeType == nodectx.PRODUCER_NODE
+	result, err := VerifyGroupChain(nodectx.GetNodeCtx().GetChainStorage(), chain.groupItem, chain.nodename, producerNode, repair)
+	if err != nil || len(result.Repaired) == 0 {
+		return result, err
+	}
+
+	chain.updProducerList()
+	chain.updUserList()
+	chain.updProducerConfig()
+	chain.UpdConnMgrProducer()
+	return result, nil
+}
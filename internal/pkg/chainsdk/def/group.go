@@ -21,3 +21,68 @@ type RexSyncResult struct {
 	LastSyncTaskTimestamp int64
 	NextSyncTaskTimeStamp int64
 }
+
+type SyncProviderInfo struct {
+	Peer      string
+	FromBlock uint64
+	ReqBlocks int32
+	SentAt    int64
+}
+
+type SyncTaskOutcome struct {
+	TaskId         uint64 //first block of the range, 0 for snapshot
+	Provider       string
+	Result         string
+	BlocksProvided int
+	Duration       int64 //in milliseconds
+	TimeStamp      int64
+}
+
+type SyncProgress struct {
+	GroupId          string
+	Status           string
+	LocalTopBlock    uint64
+	HighestBlockSeen uint64
+	BlocksPerSecond  float64
+	ETA              int64 //in seconds, -1 if unknown
+	Providers        []*SyncProviderInfo
+	History          []*SyncTaskOutcome
+	TimeStamp        int64
+}
+
+type BlockGap struct {
+	From     uint64 //first missing block
+	To       uint64 //last missing block
+	Since    int64  //first seen, in nanoseconds
+	Requests int    //repair requests sent for the gap
+}
+
+type GapRepairStatus struct {
+	CachedBlocks  int
+	LowestCached  uint64
+	HighestCached uint64
+	Gaps          []*BlockGap
+	Pending       int               //repair requests waiting for resp
+	Requested     uint64            //repair requests sent
+	Repaired      uint64            //blocks received by repair requests
+	Evicted       map[string]uint64 //evicted cached blocks by reason
+	LastRunAt     int64
+}
+
+type ChainIssue struct {
+	BlockId uint64 //first block of the issue, top block for state issues
+	Kind    string
+	Detail  string
+}
+
+type ChainVerifyResult struct {
+	GroupId       string
+	TopBlock      uint64
+	CheckedBlocks uint64
+	CheckedTrxs   uint64
+	PrunedTo      uint64 //blocks to it only have headers
+	StateChecked  bool   //false if derived tables can not be replayed from genesis
+	Issues        []*ChainIssue
+	Repaired      []string //derived tables rewritten by the replay
+	TimeStamp     int64
+}
@@ -11,6 +11,7 @@ import (
 	logging "github.com/ipfs/go-log/v2"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
 	chaindef "github.com/rumsystem/quorum/internal/pkg/chainsdk/def"
 	"github.com/rumsystem/quorum/internal/pkg/conn/pubsubconn"
 	"github.com/rumsystem/quorum/internal/pkg/nodectx"
@@ -231,30 +232,85 @@ func (connMgr *ConnMgr) SendReqTrxRex(trx *quorumpb.Trx) error {
 		return errors.New("RumExchange is nil, please set enablerumexchange as true")
 	}
 
+	rummsg, err := getRexTrxMsg(trx)
+	if err != nil {
+		return err
+	}
+
+	psconn := connMgr.getUserConn()
+	if psconn == nil {
+		return fmt.Errorf("no user conn for %s. (can be ignored)", connMgr.GroupId)
+	}
+	channelpeers := psconn.Topic.ListPeers()
+	return nodectx.GetNodeCtx().Node.RumExchange.Publish(trx.GroupId, channelpeers, rummsg)
+}
+
+// SendReqTrxRexToPeer send a req trx to the given peer, used by syncer to ask different peers at the same time
+func (connMgr *ConnMgr) SendReqTrxRexToPeer(trx *quorumpb.Trx, p peer.ID) error {
+	conn_log.Debugf("<%s> SendReqTrxRexToPeer called, peer <%s>", connMgr.GroupId, p)
+	if nodectx.GetNodeCtx().Node.RumExchange == nil {
+		return errors.New("RumExchange is nil, please set enablerumexchange as true")
+	}
+
+	rummsg, err := getRexTrxMsg(trx)
+	if err != nil {
+		return err
+	}
+	return nodectx.GetNodeCtx().Node.RumExchange.PublishToSyncPeer(rummsg, p)
+}
+
+// GetSyncPeers return at most n peers of the user channel, ordered by their block provider score
+func (connMgr *ConnMgr) GetSyncPeers(n int) []peer.ID {
+	if nodectx.GetNodeCtx().Node.RumExchange == nil {
+		return nil
+	}
+
+	psconn := connMgr.getUserConn()
+	if psconn == nil {
+		return nil
+	}
+	return nodectx.GetNodeCtx().Node.RumExchange.SyncPeers(psconn.Topic.ListPeers(), n)
+}
+
+// GetUserChannelPeers return peers subscribed to the user channel of the group
+func (connMgr *ConnMgr) GetUserChannelPeers() []peer.ID {
+	psconn := connMgr.getUserConn()
+	if psconn == nil {
+		return nil
+	}
+	return psconn.Topic.ListPeers()
+}
+
+func (connMgr *ConnMgr) RewardSyncPeer(p peer.ID, blocks uint64) {
+	if nodectx.GetNodeCtx().Node.RumExchange != nil {
+		nodectx.GetNodeCtx().Node.RumExchange.RewardSyncPeer(p, blocks)
+	}
+}
+
+func (connMgr *ConnMgr) PenalizeSyncPeer(p peer.ID) {
+	if nodectx.GetNodeCtx().Node.RumExchange != nil {
+		nodectx.GetNodeCtx().Node.RumExchange.PenalizeSyncPeer(p)
+	}
+}
+
+func getRexTrxMsg(trx *quorumpb.Trx) (*quorumpb.RumDataMsg, error) {
 	// compress trx.Data
 	compressedContent := new(bytes.Buffer)
 	if err := utils.Compress(bytes.NewReader(trx.Data), compressedContent); err != nil {
-		return err
+		return nil, err
 	}
 	trx.Data = compressedContent.Bytes()
 
 	pbBytes, err := proto.Marshal(trx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	pkg := &quorumpb.Package{
 		Type: quorumpb.PackageType_TRX,
 		Data: pbBytes,
 	}
-	rummsg := &quorumpb.RumDataMsg{MsgType: quorumpb.RumDataMsgType_CHAIN_DATA, DataPackage: pkg}
-
-	psconn := connMgr.getUserConn()
-	if psconn == nil {
-		return fmt.Errorf("no user conn for %s. (can be ignored)", connMgr.GroupId)
-	}
-	channelpeers := psconn.Topic.ListPeers()
-	return nodectx.GetNodeCtx().Node.RumExchange.Publish(trx.GroupId, channelpeers, rummsg)
+	return &quorumpb.RumDataMsg{MsgType: quorumpb.RumDataMsgType_CHAIN_DATA, DataPackage: pkg}, nil
 }
 
 func (connMgr *ConnMgr) SendRespTrxRex(trx *quorumpb.Trx, s network.Stream) error {
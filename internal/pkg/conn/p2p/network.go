@@ -32,6 +32,7 @@ type Node struct {
 	SkipPeers        []string
 	Pubsub           *pubsub.PubSub
 	RumExchange      *RexService
+	RexFile          *RexFileService
 	Ddht             *dual.DHT
 	Info             *NodeInfo
 	RoutingDiscovery *discoveryrouting.RoutingDiscovery
@@ -143,3 +144,9 @@ func (node *Node) SetRumExchange(ctx context.Context) {
 	//node.peerStatus = peerStatus
 	node.RumExchange = rexservice
 }
+
+// SetRexFile enables the protocol to transfer blobs of shared files with group peers
+func (node *Node) SetRexFile(provider RexFileProvider) {
+	node.RexFile = NewRexFileService(node.Host, node.NetworkName, ProtocolPrefix, provider)
+	networklog.Infof("Enable protocol RexFile")
+}
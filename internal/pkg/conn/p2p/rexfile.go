@@ -0,0 +1,145 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	msgio "github.com/libp2p/go-msgio"
+	"github.com/rumsystem/quorum/internal/pkg/logging"
+)
+
+var rexfilelog = logging.Logger("rexfile")
+
+const RexFileIDVer = "1.0.0"
+
+var REX_FILE_STREAM_TIMEOUT = 30 * time.Second // deadline of a blob request, including transfer
+
+// RexFileProvider returns a blob (a file chunk or manifest) of a file shared in a group,
+// it should return an error if the blob is unknown or the peer is not allowed to read it
+type RexFileProvider interface {
+	ServeBlob(groupId string, fileId string, hash string, from peer.ID) ([]byte, error)
+}
+
+type RexFileReq struct {
+	GroupId string `json:"group_id"`
+	FileId  string `json:"file_id"`
+	Hash    string `json:"hash"`
+}
+
+type RexFileResp struct {
+	Error string `json:"error,omitempty"`
+	Size  int    `json:"size"`
+}
+
+// RexFileService transfers blobs of files between group peers, each request is a stream with
+// a json request frame, answered by a json response frame followed by the raw blob frame
+type RexFileService struct {
+	Host       host.Host
+	ProtocolId protocol.ID
+	provider   RexFileProvider
+}
+
+func NewRexFileService(h host.Host, Networkname string, ProtocolPrefix string, provider RexFileProvider) *RexFileService {
+	customprotocol := fmt.Sprintf("%s/%s/rexfile/%s", ProtocolPrefix, Networkname, RexFileIDVer)
+	rexf := &RexFileService{Host: h, ProtocolId: protocol.ID(customprotocol), provider: provider}
+	h.SetStreamHandler(rexf.ProtocolId, rexf.Handler)
+	rexfilelog.Debugf("new rexfile service SetStreamHandler: %s", customprotocol)
+	return rexf
+}
+
+func (r *RexFileService) Handler(s network.Stream) {
+	defer s.Close()
+	remotePeer := s.Conn().RemotePeer()
+	s.SetDeadline(time.Now().Add(REX_FILE_STREAM_TIMEOUT))
+
+	reader := msgio.NewVarintReaderSize(s, MessageSizeMax)
+	writer := msgio.NewVarintWriter(s)
+
+	msgdata, err := reader.ReadMsg()
+	if err != nil {
+		rexfilelog.Debugf("read request from %s failed: %s", remotePeer, err)
+		_ = s.Reset()
+		return
+	}
+	req := &RexFileReq{}
+	err = json.Unmarshal(msgdata, req)
+	reader.ReleaseMsg(msgdata)
+
+	var blob []byte
+	if err == nil {
+		blob, err = r.provider.ServeBlob(req.GroupId, req.FileId, req.Hash, remotePeer)
+	}
+
+	resp := &RexFileResp{Size: len(blob)}
+	if err != nil {
+		rexfilelog.Debugf("<%s> serve blob <%s> to %s failed: %s", req.GroupId, req.Hash, remotePeer, err)
+		resp.Error = err.Error()
+		resp.Size = 0
+	}
+	respdata, _ := json.Marshal(resp)
+	if err := writer.WriteMsg(respdata); err != nil {
+		_ = s.Reset()
+		return
+	}
+	if resp.Error == "" {
+		if err := writer.WriteMsg(blob); err != nil {
+			rexfilelog.Debugf("write blob <%s> to %s failed: %s", req.Hash, remotePeer, err)
+			_ = s.Reset()
+		}
+	}
+}
+
+// Fetch asks peer p for a blob, the caller should verify the content by its hash
+func (r *RexFileService) Fetch(ctx context.Context, p peer.ID, req *RexFileReq) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, REX_FILE_STREAM_TIMEOUT)
+	defer cancel()
+
+	s, err := r.Host.NewStream(ctx, p, r.ProtocolId)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		s.SetDeadline(deadline)
+	}
+
+	reqdata, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := msgio.NewVarintWriter(s).WriteMsg(reqdata); err != nil {
+		_ = s.Reset()
+		return nil, err
+	}
+
+	reader := msgio.NewVarintReaderSize(s, MessageSizeMax)
+	respdata, err := reader.ReadMsg()
+	if err != nil {
+		_ = s.Reset()
+		return nil, err
+	}
+	resp := &RexFileResp{}
+	if err := json.Unmarshal(respdata, resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+
+	blob, err := reader.ReadMsg()
+	if err != nil {
+		_ = s.Reset()
+		return nil, err
+	}
+	if len(blob) != resp.Size {
+		return nil, fmt.Errorf("blob size mismatch, expect %d, got %d", resp.Size, len(blob))
+	}
+	return blob, nil
+}
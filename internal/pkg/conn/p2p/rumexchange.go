@@ -200,6 +200,39 @@ func (r *RexService) Publish(groupid string, channelpeers []peer.ID, msg *quorum
 	return rumerrors.ErrNoPeersAvailable
 }
 
+// SyncPeers return at most n peers to sync blocks from, bad peers are excluded and
+// the others are ordered by block provider score, so a peer with a higher score has higher chance to be picked
+func (r *RexService) SyncPeers(channelpeers []peer.ID, n int) []peer.ID {
+	connectedpeers := r.Host.Network().Peers()
+	if len(channelpeers) > 0 {
+		connectedpeers = channelpeers
+	}
+	peers := r.peerstore.filterPeers(context.Background(), connectedpeers, 1.0)
+	if len(peers) > n {
+		peers = peers[:n]
+	}
+	return peers
+}
+
+// PublishToSyncPeer publish a sync request to the given peer
+func (r *RexService) PublishToSyncPeer(msg *quorumpb.RumDataMsg, p peer.ID) error {
+	if err := r.PublishToPeerId(msg, peer.Encode(p)); err != nil {
+		return err
+	}
+	r.peerstore.Scorers().BlockProviderScorer().Touch(p)
+	return nil
+}
+
+// RewardSyncPeer increase block provider score of a peer by blocks it provided
+func (r *RexService) RewardSyncPeer(p peer.ID, blocks uint64) {
+	r.peerstore.Scorers().BlockProviderScorer().IncrementProcessedBlocks(p, blocks)
+}
+
+// PenalizeSyncPeer record a bad (timeout or invalid) response from a peer
+func (r *RexService) PenalizeSyncPeer(p peer.ID) {
+	r.peerstore.Scorers().BadResponsesScorer().Increment(p)
+}
+
 func (r *RexService) HandleRumExchangeMsg(rummsg *quorumpb.RumDataMsg, s network.Stream) {
 	rumMsgSize := float64(metric.GetProtoSize(rummsg))
 	switch rummsg.MsgType {
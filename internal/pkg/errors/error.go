@@ -21,6 +21,9 @@ var (
 	ErrInvalidTrxID     = errors.New("Invalid trx id")
 	ErrInvalidTrxIDList = errors.New("Invalid trx id list")
 	ErrInvalidTrxData   = errors.New("Invalid trx data")
+	ErrTrxExpired       = errors.New("Trx expired")
+	ErrTrxFromFuture    = errors.New("Trx timestamp is too far in the future")
+	ErrTrxReplayed      = errors.New("Trx already packaged")
 
 	ErrPrivateGroupNotSupported   = errors.New("Private group is not supported")
 	ErrEncryptionTypeNotSupported = errors.New("Encryption type is not supported")
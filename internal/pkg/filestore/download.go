@@ -0,0 +1,240 @@
+package filestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/rumsystem/quorum/internal/pkg/conn"
+	"github.com/rumsystem/quorum/internal/pkg/conn/p2p"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	"github.com/rumsystem/quorum/internal/pkg/nodectx"
+)
+
+var FILE_DOWNLOAD_WORKERS = 4 // chunks fetched in parallel for a file
+var FILE_FETCH_PEERS = 8      // peers asked for a blob, ordered by their provider score
+var FILE_FETCH_RETRY = 3      // rounds over peers before a chunk fails
+
+const (
+	DownloadManifest = "MANIFEST"
+	DownloadChunks   = "DOWNLOADING"
+	DownloadDone     = "DONE"
+	DownloadFailed   = "FAILED"
+)
+
+type DownloadStatus struct {
+	GroupId     string `json:"group_id" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	FileId      string `json:"file_id" example:"5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8"`
+	Status      string `json:"status" example:"DOWNLOADING"`
+	TotalChunks int    `json:"total_chunks" example:"3"`
+	DoneChunks  int    `json:"done_chunks" example:"1"`
+	Error       string `json:"error,omitempty"`
+	StartedAt   int64  `json:"started_at" example:"1672531200000000000"`
+	UpdatedAt   int64  `json:"updated_at" example:"1672531200000000000"`
+}
+
+type downloadJob struct {
+	mu     sync.Mutex
+	status DownloadStatus
+}
+
+func (job *downloadJob) update(fn func(s *DownloadStatus)) {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	fn(&job.status)
+	job.status.UpdatedAt = time.Now().UnixNano()
+}
+
+func (job *downloadJob) get() *DownloadStatus {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	s := job.status
+	return &s
+}
+
+func downloadKey(groupId string, fileId string) string {
+	return groupId + "_" + fileId
+}
+
+// Download starts to fetch a file shared in a group from group peers, chunks already in local store are skipped,
+// so calling it again after a failure resumes the download
+func (fs *FileStore) Download(groupId string, fileId string) (*DownloadStatus, error) {
+	if !IsValidHash(fileId) {
+		return nil, fmt.Errorf("invalid file id: %s", fileId)
+	}
+
+	fs.jobsMu.Lock()
+	defer fs.jobsMu.Unlock()
+
+	key := downloadKey(groupId, fileId)
+	if job, ok := fs.jobs[key]; ok {
+		s := job.get()
+		if s.Status == DownloadManifest || s.Status == DownloadChunks {
+			return s, nil
+		}
+	}
+
+	now := time.Now().UnixNano()
+	job := &downloadJob{status: DownloadStatus{
+		GroupId:   groupId,
+		FileId:    fileId,
+		Status:    DownloadManifest,
+		StartedAt: now,
+		UpdatedAt: now,
+	}}
+	fs.jobs[key] = job
+	go fs.runDownload(job, groupId, fileId)
+	return job.get(), nil
+}
+
+// GetDownload returns status of the latest download of a file in a group, nil if there is none
+func (fs *FileStore) GetDownload(groupId string, fileId string) *DownloadStatus {
+	fs.jobsMu.Lock()
+	defer fs.jobsMu.Unlock()
+	if job, ok := fs.jobs[downloadKey(groupId, fileId)]; ok {
+		return job.get()
+	}
+	return nil
+}
+
+func (fs *FileStore) runDownload(job *downloadJob, groupId string, fileId string) {
+	err := fs.download(job, groupId, fileId)
+	if err != nil {
+		filestorelog.Warnf("<%s> download file <%s> failed: %s", groupId, fileId, err)
+		job.update(func(s *DownloadStatus) {
+			s.Status = DownloadFailed
+			s.Error = err.Error()
+		})
+		return
+	}
+	filestorelog.Debugf("<%s> download file <%s> done", groupId, fileId)
+	job.update(func(s *DownloadStatus) {
+		s.Status = DownloadDone
+	})
+}
+
+func (fs *FileStore) download(job *downloadJob, groupId string, fileId string) error {
+	ctx, cancel := context.WithCancel(fs.ctx)
+	defer cancel()
+
+	m, err := fs.GetManifest(fileId)
+	if err != nil {
+		if err != ErrFileNotFound {
+			return err
+		}
+		data, err := fs.fetchBlob(ctx, groupId, fileId, fileId)
+		if err != nil {
+			return fmt.Errorf("fetch manifest failed: %w", err)
+		}
+		if m, err = ParseManifest(fileId, data); err != nil {
+			return err
+		}
+		if err := fs.PutBlob(fileId, data); err != nil {
+			return err
+		}
+	}
+
+	missing := fs.MissingChunks(m)
+	job.update(func(s *DownloadStatus) {
+		s.Status = DownloadChunks
+		s.TotalChunks = len(m.Chunks)
+		s.DoneChunks = len(m.Chunks) - len(missing)
+	})
+
+	indexes := make(chan int, len(missing))
+	for _, i := range missing {
+		indexes <- i
+	}
+	close(indexes)
+
+	var errOnce sync.Once
+	var fetchErr error
+	var wg sync.WaitGroup
+	for w := 0; w < FILE_DOWNLOAD_WORKERS; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if ctx.Err() != nil {
+					return
+				}
+				if err := fs.fetchChunk(ctx, groupId, fileId, m.Chunks[i].Hash); err != nil {
+					errOnce.Do(func() {
+						fetchErr = fmt.Errorf("fetch chunk %d failed: %w", i, err)
+						cancel()
+					})
+					return
+				}
+				job.update(func(s *DownloadStatus) {
+					s.DoneChunks++
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	if fetchErr != nil {
+		return fetchErr
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	// broken chunks are removed by VerifyFile, so they are fetched again by the next download
+	if err := fs.VerifyFile(m); err != nil {
+		return err
+	}
+	return fs.AddGroupFile(groupId, fileId)
+}
+
+func (fs *FileStore) fetchChunk(ctx context.Context, groupId string, fileId string, hash string) error {
+	var err error
+	for retry := 0; retry < FILE_FETCH_RETRY; retry++ {
+		var data []byte
+		data, err = fs.fetchBlob(ctx, groupId, fileId, hash)
+		if err == nil {
+			return fs.PutBlob(hash, data)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return err
+}
+
+// fetchBlob asks group peers for a blob one by one, starting from a random one so parallel fetches spread over peers
+func (fs *FileStore) fetchBlob(ctx context.Context, groupId string, fileId string, hash string) ([]byte, error) {
+	rexfile := nodectx.GetNodeCtx().Node.RexFile
+	if rexfile == nil {
+		return nil, errors.New("RexFile is nil, please enable rumexchange")
+	}
+	connMgr, err := conn.GetConn().GetConnMgr(groupId)
+	if err != nil {
+		return nil, err
+	}
+	peers := connMgr.GetSyncPeers(FILE_FETCH_PEERS)
+	if len(peers) == 0 {
+		return nil, rumerrors.ErrNoPeersAvailable
+	}
+
+	req := &p2p.RexFileReq{GroupId: groupId, FileId: fileId, Hash: hash}
+	start := rand.Intn(len(peers))
+	for i := range peers {
+		p := peers[(start+i)%len(peers)]
+		data, fetchErr := rexfile.Fetch(ctx, p, req)
+		if fetchErr != nil {
+			err = fetchErr
+			continue
+		}
+		if Sha256Hex(data) != hash {
+			filestorelog.Debugf("<%s> blob <%s> from <%s> is broken", groupId, hash, p)
+			connMgr.PenalizeSyncPeer(p)
+			err = ErrHashMismatch
+			continue
+		}
+		return data, nil
+	}
+	return nil, err
+}
@@ -0,0 +1,341 @@
+package filestore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	chain "github.com/rumsystem/quorum/internal/pkg/chainsdk/core"
+	"github.com/rumsystem/quorum/internal/pkg/conn"
+	"github.com/rumsystem/quorum/internal/pkg/logging"
+)
+
+var filestorelog = logging.Logger("filestore")
+
+var FILE_CHUNK_SIZE int64 = 1 << 20     // 1MB, chunk size of new uploads
+var FILE_MAX_SIZE int64 = 4 << 30       // 4GB
+var FILE_MANIFEST_CACHE_SIZE = 64       // manifests kept in memory to serve chunk requests
+var FILE_MANIFEST_MAX_SIZE = 1 << 20    // a manifest of FILE_MAX_SIZE with 1MB chunks is about 300KB
+var FILE_MAX_CHUNK_SIZE int64 = 4 << 20 // chunks larger than this are refused, must be less than rex message size
+var FILE_MEDIA_TYPE_DEFAULT = "application/octet-stream"
+
+var (
+	ErrFileNotFound   = errors.New("file not found")
+	ErrBlobNotFound   = errors.New("blob not found")
+	ErrHashMismatch   = errors.New("sha256 mismatch")
+	ErrFileIncomplete = errors.New("file is incomplete")
+)
+
+var hashRegexp = regexp.MustCompile("^[0-9a-f]{64}$")
+
+type ChunkInfo struct {
+	Hash string `json:"hash" example:"4a5c5e6b0a1e0f1c7b1a9e3c8d2f6b5a4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b"`
+	Size int64  `json:"size" example:"1048576"`
+}
+
+// Manifest describes a file by the hashes of its chunks, id of the file is sha256 of the manifest json,
+// so a manifest fetched from any peer can be verified by the file id
+type Manifest struct {
+	Name      string      `json:"name" example:"book.epub"`
+	MediaType string      `json:"media_type" example:"application/epub+zip"`
+	Size      int64       `json:"size" example:"3145728"`
+	Sha256    string      `json:"sha256" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	ChunkSize int64       `json:"chunk_size" example:"1048576"`
+	Chunks    []ChunkInfo `json:"chunks"`
+}
+
+// FileStore keeps blobs (chunks and manifests) on disk by their sha256, a blob is shared by all
+// files and groups containing it, and files shared in a group are recorded by group references
+//
+//	blobs/<hash[:2]>/<hash>
+//	groups/<group_id>/<file_id>
+//	uploads/<upload_id>.json
+type FileStore struct {
+	ctx context.Context
+	dir string
+
+	uploadMu sync.Mutex
+
+	cacheMu   sync.Mutex
+	manifests map[string]map[string]bool // file id => chunk hashes
+
+	jobsMu sync.Mutex
+	jobs   map[string]*downloadJob
+}
+
+// NewFileStore opens the store in dir, downloads are stopped when ctx is done
+func NewFileStore(ctx context.Context, dir string) (*FileStore, error) {
+	for _, sub := range []string{"blobs", "groups", "uploads"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0700); err != nil {
+			return nil, err
+		}
+	}
+	return &FileStore{
+		ctx:       ctx,
+		dir:       dir,
+		manifests: make(map[string]map[string]bool),
+		jobs:      make(map[string]*downloadJob),
+	}, nil
+}
+
+func Sha256Hex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func IsValidHash(hash string) bool {
+	return hashRegexp.MatchString(hash)
+}
+
+func (fs *FileStore) blobPath(hash string) string {
+	return filepath.Join(fs.dir, "blobs", hash[:2], hash)
+}
+
+func (fs *FileStore) groupDir(groupId string) string {
+	return filepath.Join(fs.dir, "groups", groupId)
+}
+
+func (fs *FileStore) HasBlob(hash string) bool {
+	if !IsValidHash(hash) {
+		return false
+	}
+	_, err := os.Stat(fs.blobPath(hash))
+	return err == nil
+}
+
+func (fs *FileStore) GetBlob(hash string) ([]byte, error) {
+	if !IsValidHash(hash) {
+		return nil, fmt.Errorf("invalid hash: %s", hash)
+	}
+	data, err := os.ReadFile(fs.blobPath(hash))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrBlobNotFound
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// PutBlob verifies data by hash and saves it, the blob is written to a temp file and renamed,
+// so a crashed or concurrent write never leaves a broken blob
+func (fs *FileStore) PutBlob(hash string, data []byte) error {
+	if !IsValidHash(hash) {
+		return fmt.Errorf("invalid hash: %s", hash)
+	}
+	if Sha256Hex(data) != hash {
+		return ErrHashMismatch
+	}
+	if fs.HasBlob(hash) {
+		return nil
+	}
+
+	path := fs.blobPath(hash)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), hash+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// PutManifest saves the manifest as a blob and returns the file id
+func (fs *FileStore) PutManifest(m *Manifest) (string, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	fileId := Sha256Hex(data)
+	return fileId, fs.PutBlob(fileId, data)
+}
+
+func (fs *FileStore) GetManifest(fileId string) (*Manifest, error) {
+	data, err := fs.GetBlob(fileId)
+	if err != nil {
+		if err == ErrBlobNotFound {
+			return nil, ErrFileNotFound
+		}
+		return nil, err
+	}
+	return ParseManifest(fileId, data)
+}
+
+// ParseManifest verifies manifest data by the file id and checks its chunks add up to the file size
+func ParseManifest(fileId string, data []byte) (*Manifest, error) {
+	if len(data) > FILE_MANIFEST_MAX_SIZE {
+		return nil, errors.New("manifest is too large")
+	}
+	if Sha256Hex(data) != fileId {
+		return nil, ErrHashMismatch
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+
+	size := int64(0)
+	for i, c := range m.Chunks {
+		if !IsValidHash(c.Hash) || c.Size <= 0 || c.Size > m.ChunkSize {
+			return nil, fmt.Errorf("invalid chunk %d of manifest", i)
+		}
+		if i < len(m.Chunks)-1 && c.Size != m.ChunkSize {
+			return nil, fmt.Errorf("invalid chunk %d of manifest", i)
+		}
+		size += c.Size
+	}
+	if size != m.Size || m.ChunkSize <= 0 || m.ChunkSize > FILE_MAX_CHUNK_SIZE || !IsValidHash(m.Sha256) {
+		return nil, errors.New("invalid manifest")
+	}
+	return m, nil
+}
+
+// MissingChunks returns indexes of chunks not in local store
+func (fs *FileStore) MissingChunks(m *Manifest) []int {
+	missing := []int{}
+	for i, c := range m.Chunks {
+		if !fs.HasBlob(c.Hash) {
+			missing = append(missing, i)
+		}
+	}
+	return missing
+}
+
+// VerifyFile checks every chunk and the sha256 of the whole file
+func (fs *FileStore) VerifyFile(m *Manifest) error {
+	filehash := sha256.New()
+	for i, c := range m.Chunks {
+		data, err := fs.GetBlob(c.Hash)
+		if err != nil {
+			return fmt.Errorf("chunk %d: %w", i, err)
+		}
+		if Sha256Hex(data) != c.Hash {
+			os.Remove(fs.blobPath(c.Hash))
+			return fmt.Errorf("chunk %d: %w", i, ErrHashMismatch)
+		}
+		filehash.Write(data)
+	}
+	if hex.EncodeToString(filehash.Sum(nil)) != m.Sha256 {
+		return ErrHashMismatch
+	}
+	return nil
+}
+
+func (fs *FileStore) AddGroupFile(groupId string, fileId string) error {
+	dir := fs.groupDir(groupId)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, fileId), nil, 0600)
+}
+
+func (fs *FileStore) HasGroupFile(groupId string, fileId string) bool {
+	if !IsValidHash(fileId) {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(fs.groupDir(groupId), fileId))
+	return err == nil
+}
+
+// GetGroupFiles returns ids of files shared in a group
+func (fs *FileStore) GetGroupFiles(groupId string) ([]string, error) {
+	entries, err := os.ReadDir(fs.groupDir(groupId))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+	fileIds := []string{}
+	for _, e := range entries {
+		if IsValidHash(e.Name()) {
+			fileIds = append(fileIds, e.Name())
+		}
+	}
+	return fileIds, nil
+}
+
+// RemoveGroupFiles removes references of files in a group, blobs are kept as they may be shared by other groups
+func (fs *FileStore) RemoveGroupFiles(groupId string) error {
+	return os.RemoveAll(fs.groupDir(groupId))
+}
+
+// ServeBlob implements p2p.RexFileProvider, a blob is only served to peers in the user channel of
+// a group this node joined, and only if it belongs to a file shared in the group
+func (fs *FileStore) ServeBlob(groupId string, fileId string, hash string, from peer.ID) ([]byte, error) {
+	if _, ok := chain.GetGroupMgr().Groups[groupId]; !ok {
+		return nil, ErrFileNotFound
+	}
+	connMgr, err := conn.GetConn().GetConnMgr(groupId)
+	if err != nil {
+		return nil, err
+	}
+	isMember := false
+	for _, p := range connMgr.GetUserChannelPeers() {
+		if p == from {
+			isMember = true
+			break
+		}
+	}
+	if !isMember {
+		return nil, fmt.Errorf("peer %s is not in group", from)
+	}
+
+	if !fs.HasGroupFile(groupId, fileId) {
+		return nil, ErrFileNotFound
+	}
+	if hash != fileId {
+		chunks, err := fs.getChunkSet(fileId)
+		if err != nil {
+			return nil, err
+		}
+		if !chunks[hash] {
+			return nil, ErrBlobNotFound
+		}
+	}
+	return fs.GetBlob(hash)
+}
+
+func (fs *FileStore) getChunkSet(fileId string) (map[string]bool, error) {
+	fs.cacheMu.Lock()
+	chunks, ok := fs.manifests[fileId]
+	fs.cacheMu.Unlock()
+	if ok {
+		return chunks, nil
+	}
+
+	m, err := fs.GetManifest(fileId)
+	if err != nil {
+		return nil, err
+	}
+	chunks = make(map[string]bool, len(m.Chunks))
+	for _, c := range m.Chunks {
+		chunks[c.Hash] = true
+	}
+
+	fs.cacheMu.Lock()
+	if len(fs.manifests) >= FILE_MANIFEST_CACHE_SIZE {
+		fs.manifests = make(map[string]map[string]bool)
+	}
+	fs.manifests[fileId] = chunks
+	fs.cacheMu.Unlock()
+	return chunks, nil
+}
@@ -0,0 +1,81 @@
+package filestore
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+// FileReader reads a complete file from its chunks, it implements io.ReadSeeker so it can be served
+// with http.ServeContent and clients can resume a download by range requests
+type FileReader struct {
+	fs     *FileStore
+	m      *Manifest
+	offset int64
+
+	index int // index of the opened chunk
+	chunk *os.File
+}
+
+// OpenFile returns a reader of the file, all chunks of the file should be in local store
+func (fs *FileStore) OpenFile(m *Manifest) (*FileReader, error) {
+	if len(fs.MissingChunks(m)) > 0 {
+		return nil, ErrFileIncomplete
+	}
+	return &FileReader{fs: fs, m: m, index: -1}, nil
+}
+
+func (r *FileReader) Read(p []byte) (int, error) {
+	if r.offset >= r.m.Size {
+		return 0, io.EOF
+	}
+
+	index := int(r.offset / r.m.ChunkSize)
+	if index != r.index {
+		if r.chunk != nil {
+			r.chunk.Close()
+			r.chunk = nil
+		}
+		f, err := os.Open(r.fs.blobPath(r.m.Chunks[index].Hash))
+		if err != nil {
+			return 0, err
+		}
+		r.chunk = f
+		r.index = index
+	}
+
+	chunkOffset := r.offset - int64(index)*r.m.ChunkSize
+	if remain := r.m.Chunks[index].Size - chunkOffset; int64(len(p)) > remain {
+		p = p[:remain]
+	}
+	n, err := r.chunk.ReadAt(p, chunkOffset)
+	r.offset += int64(n)
+	if err == io.EOF && n == len(p) {
+		err = nil
+	}
+	return n, err
+}
+
+func (r *FileReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.m.Size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	r.offset = offset
+	return offset, nil
+}
+
+func (r *FileReader) Close() error {
+	if r.chunk != nil {
+		return r.chunk.Close()
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package filestore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	guuid "github.com/google/uuid"
+)
+
+var ErrUploadNotFound = errors.New("upload not found")
+
+// Upload is a resumable upload session, chunks can be uploaded in any order and in parallel,
+// uploaded chunks are saved as blobs at once, so a broken upload only resends the missing chunks
+type Upload struct {
+	Id        string      `json:"upload_id" example:"0e1b5a0c-6e0d-4b5e-9d7a-2d7e0a7c7b3e"`
+	GroupId   string      `json:"group_id" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	Name      string      `json:"name" example:"book.epub"`
+	MediaType string      `json:"media_type" example:"application/epub+zip"`
+	Size      int64       `json:"size" example:"3145728"`
+	Sha256    string      `json:"sha256" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"` // optional, checked on completion
+	ChunkSize int64       `json:"chunk_size" example:"1048576"`
+	Chunks    []ChunkInfo `json:"chunks"` // hash is empty if the chunk is not uploaded yet
+	CreatedAt int64       `json:"created_at" example:"1672531200000000000"`
+}
+
+// Missing returns indexes of chunks not uploaded yet
+func (u *Upload) Missing() []int {
+	missing := []int{}
+	for i, c := range u.Chunks {
+		if c.Hash == "" {
+			missing = append(missing, i)
+		}
+	}
+	return missing
+}
+
+func (fs *FileStore) uploadPath(uploadId string) string {
+	return filepath.Join(fs.dir, "uploads", uploadId+".json")
+}
+
+func (fs *FileStore) NewUpload(groupId string, name string, mediaType string, size int64, sha256hex string) (*Upload, error) {
+	if size <= 0 || size > FILE_MAX_SIZE {
+		return nil, fmt.Errorf("file size should be in (0, %d]", FILE_MAX_SIZE)
+	}
+	if sha256hex != "" && !IsValidHash(sha256hex) {
+		return nil, fmt.Errorf("invalid sha256: %s", sha256hex)
+	}
+	if mediaType == "" {
+		mediaType = FILE_MEDIA_TYPE_DEFAULT
+	}
+
+	chunkSize := FILE_CHUNK_SIZE
+	n := (size + chunkSize - 1) / chunkSize
+	chunks := make([]ChunkInfo, n)
+	for i := range chunks {
+		chunks[i].Size = chunkSize
+	}
+	chunks[n-1].Size = size - (n-1)*chunkSize
+
+	u := &Upload{
+		Id:        guuid.NewString(),
+		GroupId:   groupId,
+		Name:      filepath.Base(name),
+		MediaType: mediaType,
+		Size:      size,
+		Sha256:    sha256hex,
+		ChunkSize: chunkSize,
+		Chunks:    chunks,
+		CreatedAt: time.Now().UnixNano(),
+	}
+
+	fs.uploadMu.Lock()
+	defer fs.uploadMu.Unlock()
+	return u, fs.saveUpload(u)
+}
+
+func (fs *FileStore) GetUpload(uploadId string) (*Upload, error) {
+	if _, err := guuid.Parse(uploadId); err != nil {
+		return nil, ErrUploadNotFound
+	}
+	data, err := os.ReadFile(fs.uploadPath(uploadId))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrUploadNotFound
+		}
+		return nil, err
+	}
+	u := &Upload{}
+	if err := json.Unmarshal(data, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// PutUploadChunk saves the chunk at index of an upload, the chunk is checked against expectHash if it is given
+func (fs *FileStore) PutUploadChunk(uploadId string, index int, data []byte, expectHash string) (*ChunkInfo, error) {
+	u, err := fs.GetUpload(uploadId)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(u.Chunks) {
+		return nil, fmt.Errorf("chunk index should be in [0, %d)", len(u.Chunks))
+	}
+	if int64(len(data)) != u.Chunks[index].Size {
+		return nil, fmt.Errorf("size of chunk %d should be %d, got %d", index, u.Chunks[index].Size, len(data))
+	}
+
+	hash := Sha256Hex(data)
+	if expectHash != "" && expectHash != hash {
+		return nil, ErrHashMismatch
+	}
+	if err := fs.PutBlob(hash, data); err != nil {
+		return nil, err
+	}
+
+	fs.uploadMu.Lock()
+	defer fs.uploadMu.Unlock()
+	// reload, other chunks may be saved meanwhile
+	u, err = fs.GetUpload(uploadId)
+	if err != nil {
+		return nil, err
+	}
+	u.Chunks[index].Hash = hash
+	if err := fs.saveUpload(u); err != nil {
+		return nil, err
+	}
+	return &u.Chunks[index], nil
+}
+
+// CompleteUpload verifies the whole file, saves its manifest and shares it in the group of the upload
+func (fs *FileStore) CompleteUpload(uploadId string) (string, *Manifest, error) {
+	u, err := fs.GetUpload(uploadId)
+	if err != nil {
+		return "", nil, err
+	}
+	if missing := u.Missing(); len(missing) > 0 {
+		return "", nil, fmt.Errorf("%w, %d chunks missing", ErrFileIncomplete, len(missing))
+	}
+
+	filehash := sha256.New()
+	for i, c := range u.Chunks {
+		data, err := fs.GetBlob(c.Hash)
+		if err != nil {
+			return "", nil, fmt.Errorf("chunk %d: %w", i, err)
+		}
+		filehash.Write(data)
+	}
+	sha256hex := hex.EncodeToString(filehash.Sum(nil))
+	if u.Sha256 != "" && u.Sha256 != sha256hex {
+		return "", nil, fmt.Errorf("file %w, expect %s, got %s", ErrHashMismatch, u.Sha256, sha256hex)
+	}
+
+	m := &Manifest{
+		Name:      u.Name,
+		MediaType: u.MediaType,
+		Size:      u.Size,
+		Sha256:    sha256hex,
+		ChunkSize: u.ChunkSize,
+		Chunks:    u.Chunks,
+	}
+	fileId, err := fs.PutManifest(m)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := fs.AddGroupFile(u.GroupId, fileId); err != nil {
+		return "", nil, err
+	}
+	if err := fs.RemoveUpload(uploadId); err != nil {
+		filestorelog.Warnf("remove upload <%s> failed: %s", uploadId, err)
+	}
+	return fileId, m, nil
+}
+
+// RemoveUpload removes an upload session, uploaded chunks are kept as blobs
+func (fs *FileStore) RemoveUpload(uploadId string) error {
+	if _, err := guuid.Parse(uploadId); err != nil {
+		return ErrUploadNotFound
+	}
+	fs.uploadMu.Lock()
+	defer fs.uploadMu.Unlock()
+	err := os.Remove(fs.uploadPath(uploadId))
+	if os.IsNotExist(err) {
+		return ErrUploadNotFound
+	}
+	return err
+}
+
+func (fs *FileStore) saveUpload(u *Upload) error {
+	data, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+	tmp := fs.uploadPath(u.Id) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fs.uploadPath(u.Id))
+}
@@ -87,4 +87,31 @@ var (
 		},
 		[]string{"action"},
 	)
+
+	MempoolTrxs = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "mempool_trxs",
+			Help:      "Current count of trxs waiting in the trx buffer of a group",
+		},
+		[]string{"group_id"},
+	)
+
+	MempoolSenders = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "mempool_senders",
+			Help:      "Current count of senders with trxs waiting in the trx buffer of a group",
+		},
+		[]string{"group_id"},
+	)
+
+	MempoolEvictedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "mempool_evicted_total",
+			Help:      "Total count of trxs evicted from the trx buffer of a group",
+		},
+		[]string{"group_id", "reason"},
+	)
 )
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/rumsystem/quorum/internal/pkg/logging"
+)
+
+var jwtLimitLogger = logging.Logger("jwtlimit")
+
+type (
+	// JWTLimitConfig defines the config for jwt limit middleware
+	JWTLimitConfig struct {
+		// Skipper defines a function to skip middleware.
+		Skipper middleware.Skipper
+
+		// LimitFunc returns key of the token and its limits, a zero limit means unlimited,
+		// request is not limited if ok is false
+		LimitFunc JWTLimitFunc
+
+		// IsPostFunc reports whether the request posts content to a group, which is counted by daily post quota
+		IsPostFunc func(echo.Context) bool
+
+		// Store keeps daily post counts, so a restart does not reset the quota. counts are kept in memory only if it is nil
+		Store JWTCountStore
+	}
+
+	JWTLimitFunc func(echo.Context) (key string, rateLimit int, dailyPostQuota int, ok bool)
+
+	// JWTCountStore persists counts of keys in a time window
+	JWTCountStore interface {
+		GetJWTCount(key string, window time.Time) (int, error)
+		SetJWTCount(key string, window time.Time, count int) error
+		PruneJWTCount(window time.Time) (int, error) // removes counts of other windows
+	}
+
+	// windowCounter counts hits of keys in fixed time windows
+	windowCounter struct {
+		mu     sync.Mutex
+		window time.Duration
+		start  time.Time // start of the current window
+		counts map[string]int
+		store  JWTCountStore // nil if counts are not persisted
+	}
+)
+
+// Errors
+var (
+	ErrRateLimitExceeded = echo.NewHTTPError(http.StatusTooManyRequests, "jwt rate limit exceeded")
+	ErrPostQuotaExceeded = echo.NewHTTPError(http.StatusTooManyRequests, "jwt daily post quota exceeded")
+)
+
+func newWindowCounter(window time.Duration, store JWTCountStore) *windowCounter {
+	return &windowCounter{window: window, counts: make(map[string]int), store: store}
+}
+
+// hit counts one hit of key, returns false if key already reached limit in current window
+func (w *windowCounter) hit(key string, limit int, now time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	start := now.Truncate(w.window)
+	if !start.Equal(w.start) {
+		// drop counts of expired windows, so they do not grow with revoked tokens
+		w.start = start
+		w.counts = make(map[string]int)
+		if w.store != nil {
+			if _, err := w.store.PruneJWTCount(start); err != nil {
+				jwtLimitLogger.Warnf("prune jwt counts failed: %s", err)
+			}
+		}
+	}
+
+	count, ok := w.counts[key]
+	if !ok && w.store != nil {
+		var err error
+		if count, err = w.store.GetJWTCount(key, start); err != nil {
+			jwtLimitLogger.Warnf("get jwt count of <%s> failed: %s", key, err)
+		}
+		w.counts[key] = count
+	}
+
+	if count >= limit {
+		return false
+	}
+	w.counts[key] = count + 1
+	if w.store != nil {
+		if err := w.store.SetJWTCount(key, start, count+1); err != nil {
+			jwtLimitLogger.Warnf("save jwt count of <%s> failed: %s", key, err)
+		}
+	}
+	return true
+}
+
+// JWTLimitWithConfig returns a middleware which limits requests per minute and posts per day (UTC) of each key,
+// request counters are kept in memory and reset when node restarts, post counters are kept in config.Store
+func JWTLimitWithConfig(config JWTLimitConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = middleware.DefaultSkipper
+	}
+	if config.LimitFunc == nil {
+		panic("echo: jwt limit middleware requires limit func")
+	}
+	if config.IsPostFunc == nil {
+		config.IsPostFunc = func(echo.Context) bool { return false }
+	}
+
+	requests := newWindowCounter(time.Minute, nil)
+	posts := newWindowCounter(24*time.Hour, config.Store)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			key, rateLimit, dailyPostQuota, ok := config.LimitFunc(c)
+			if !ok {
+				return next(c)
+			}
+
+			now := time.Now().UTC()
+			if rateLimit > 0 && !requests.hit(key, rateLimit, now) {
+				return ErrRateLimitExceeded
+			}
+			if dailyPostQuota > 0 && config.IsPostFunc(c) && !posts.hit(key, dailyPostQuota, now) {
+				return ErrPostQuotaExceeded
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rumsystem/quorum/internal/pkg/storage"
+	node_storage "github.com/rumsystem/quorum/internal/pkg/storage/node"
+)
+
+func TestWindowCounter(t *testing.T) {
+	w := newWindowCounter(time.Minute, nil)
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		if !w.hit("a", 3, now.Add(time.Duration(i)*time.Second)) {
+			t.Fatalf("hit <%d> should be allowed", i)
+		}
+	}
+	if w.hit("a", 3, now.Add(59*time.Second)) {
+		t.Fatal("hit over limit should be rejected")
+	}
+	if !w.hit("b", 3, now) {
+		t.Fatal("keys should be counted separately")
+	}
+	if !w.hit("a", 3, now.Add(time.Minute)) {
+		t.Fatal("count should be reset in the next window")
+	}
+	if _, ok := w.counts["b"]; ok {
+		t.Fatal("counts of the expired window should be dropped")
+	}
+}
+
+func TestWindowCounterStore(t *testing.T) {
+	store := node_storage.NewNodeStorage(storage.NewMemStore())
+	now := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	w := newWindowCounter(24*time.Hour, store)
+	for i := 0; i < 2; i++ {
+		if !w.hit("a", 2, now) {
+			t.Fatalf("hit <%d> should be allowed", i)
+		}
+	}
+
+	// node restarted, counts are loaded from the store
+	w = newWindowCounter(24*time.Hour, store)
+	if w.hit("a", 2, now.Add(time.Hour)) {
+		t.Fatal("quota should be kept across restarts")
+	}
+	if !w.hit("a", 2, now.Add(24*time.Hour)) {
+		t.Fatal("quota should be reset on the next day")
+	}
+	if count, err := store.GetJWTCount("a", now.Truncate(24*time.Hour)); err != nil || count != 0 {
+		t.Fatalf("count of the last day should be pruned, got <%d> <%v>", count, err)
+	}
+}
+
+func TestJWTLimitWithConfig(t *testing.T) {
+	store := node_storage.NewNodeStorage(storage.NewMemStore())
+	newHandler := func() echo.HandlerFunc {
+		mw := JWTLimitWithConfig(JWTLimitConfig{
+			LimitFunc: func(c echo.Context) (string, int, int, bool) {
+				// refreshed tokens of the same owner share the key
+				return "node|app|group1", 100, 2, c.Request().Header.Get("Authorization") != ""
+			},
+			IsPostFunc: func(c echo.Context) bool { return c.Request().Method == http.MethodPost },
+			Store:      store,
+		})
+		return mw(func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+	}
+	request := func(h echo.HandlerFunc, method string, token string) error {
+		req := httptest.NewRequest(method, "/", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return h(echo.New().NewContext(req, httptest.NewRecorder()))
+	}
+
+	h := newHandler()
+	for _, token := range []string{"token", "refreshed token"} {
+		if err := request(h, http.MethodPost, token); err != nil {
+			t.Fatalf("post should be allowed: %s", err)
+		}
+	}
+	if err := request(h, http.MethodGet, "refreshed token"); err != nil {
+		t.Fatalf("get is not counted by post quota: %s", err)
+	}
+
+	h = newHandler()
+	if err := request(h, http.MethodPost, "refreshed token"); !errors.Is(err, ErrPostQuotaExceeded) {
+		t.Fatalf("post quota should be kept across restarts, got <%v>", err)
+	}
+	if err := request(h, http.MethodPost, ""); err != nil {
+		t.Fatalf("request without limits should be allowed: %s", err)
+	}
+}
@@ -14,6 +14,7 @@ func JWTSkipper(c echo.Context) bool {
 	path := c.Request().URL.Path
 	skipPathPrefix := []string{
 		"/api/v1/ws/trx",
+		"/api/v1/ws/sync",
 	}
 	for _, v := range skipPathPrefix {
 		if strings.HasPrefix(path, v) {
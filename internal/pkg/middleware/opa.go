@@ -40,6 +40,9 @@ type (
 
 		// EvalFunc, rego eval function
 		EvalFunc OpaRegoEvalFunc
+
+		// OpaPolicy, reloadable policy, Policy, Query and EvalFunc are ignored if it is set
+		OpaPolicy *OpaPolicy
 	}
 
 	// OpaSuccessHandler defines a function which is executed for a valid token.
@@ -99,31 +102,40 @@ func OpaWithConfig(config OpaConfig) echo.MiddlewareFunc {
 		config.Skipper = DefaultOpaConfig.Skipper
 	}
 
-	if config.Policy == "" {
-		panic("echo: opa middleware requires opa rego policy")
-	}
-
-	if config.Query == "" {
-		panic("echo: opa middleware requires opa rego query")
-	}
-
 	if config.InputFunc == nil {
 		panic("echo: opa middleware requires opa rego input function")
 	}
 
-	if config.EvalFunc == nil {
-		// panic("echo: opa middleware requires opa rego eval function")
-		config.EvalFunc = DefaultEvalFunc
-	}
+	var eval func(context.Context, interface{}) (bool, error)
+	if config.OpaPolicy != nil {
+		eval = config.OpaPolicy.Eval
+	} else {
+		if config.Policy == "" {
+			panic("echo: opa middleware requires opa rego policy")
+		}
 
-	ctx := context.Background()
+		if config.Query == "" {
+			panic("echo: opa middleware requires opa rego query")
+		}
 
-	query, err := rego.New(
-		rego.Query(config.Query),
-		rego.Module("policy.rego", config.Policy),
-	).PrepareForEval(ctx)
-	if err != nil {
-		panic(fmt.Sprintf("echo: opa middleware initial opa failed: %s", err))
+		if config.EvalFunc == nil {
+			// panic("echo: opa middleware requires opa rego eval function")
+			config.EvalFunc = DefaultEvalFunc
+		}
+
+		ctx := context.Background()
+
+		query, err := rego.New(
+			rego.Query(config.Query),
+			rego.Module("policy.rego", config.Policy),
+		).PrepareForEval(ctx)
+		if err != nil {
+			panic(fmt.Sprintf("echo: opa middleware initial opa failed: %s", err))
+		}
+
+		eval = func(ctx context.Context, input interface{}) (bool, error) {
+			return config.EvalFunc(ctx, query, input)
+		}
 	}
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
@@ -138,7 +150,7 @@ func OpaWithConfig(config OpaConfig) echo.MiddlewareFunc {
 
 			input := config.InputFunc(c)
 			ctx := context.Background()
-			ok, err := config.EvalFunc(ctx, query, input)
+			ok, err := eval(ctx, input)
 			if err != nil {
 				if config.ErrorHandler != nil {
 					return config.ErrorHandler(err)
@@ -0,0 +1,187 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/rumsystem/quorum/internal/pkg/logging"
+)
+
+var opaPolicyLogger = logging.Logger("opa")
+
+var OPA_POLICY_RELOAD_INTERVAL = 5 * time.Second // interval to check changes of policy files
+
+const opaPolicyFileExt = ".rego"
+
+type (
+	// OpaPolicy holds the builtin policy and policy files loaded from a directory,
+	// policy files are reloaded when any of them changed, a broken change keeps the active policy
+	OpaPolicy struct {
+		mu       sync.RWMutex
+		builtin  string
+		query    string
+		dir      string
+		prepared rego.PreparedEvalQuery
+		files    []string
+		digest   string
+		failed   string // digest of policy files failed to compile, avoid compiling them again
+		loadedAt time.Time
+		lastErr  error
+	}
+
+	OpaPolicyStatus struct {
+		Query     string    `json:"query" example:"x = data.quorum.restapi.authz.decision"`
+		Dir       string    `json:"dir" example:"config/policies"`
+		Files     []string  `json:"files" example:"readonly.rego"`
+		LoadedAt  time.Time `json:"loaded_at" example:"2022-12-28T08:10:36.675204+00:00"`
+		LastError string    `json:"last_error" example:""`
+	}
+)
+
+// NewOpaPolicy compiles the builtin policy with all policy files in dir,
+// an error is returned if they can not be compiled, dir is ignored if it does not exist
+func NewOpaPolicy(builtin string, query string, dir string) (*OpaPolicy, error) {
+	p := &OpaPolicy{builtin: builtin, query: query, dir: dir}
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Reload reads policy files again and replaces the active policy if they changed
+func (p *OpaPolicy) Reload() error {
+	modules, digest, err := p.readPolicyFiles()
+	if err != nil {
+		p.setError(err)
+		return err
+	}
+
+	p.mu.RLock()
+	changed := p.loadedAt.IsZero() || (digest != p.digest && digest != p.failed)
+	p.mu.RUnlock()
+	if !changed {
+		return nil
+	}
+
+	options := []func(*rego.Rego){
+		rego.Query(p.query),
+		rego.Module("builtin.rego", p.builtin),
+	}
+	files := []string{}
+	for name, module := range modules {
+		options = append(options, rego.Module(name, module))
+		files = append(files, name)
+	}
+	sort.Strings(files)
+
+	prepared, err := rego.New(options...).PrepareForEval(context.Background())
+	if err != nil {
+		err = fmt.Errorf("compile opa policy failed: %s", err)
+		p.mu.Lock()
+		p.failed = digest
+		p.lastErr = err
+		p.mu.Unlock()
+		return err
+	}
+
+	p.mu.Lock()
+	p.prepared = prepared
+	p.files = files
+	p.digest = digest
+	p.loadedAt = time.Now()
+	p.lastErr = nil
+	p.mu.Unlock()
+
+	opaPolicyLogger.Infof("opa policy loaded, policy files: %v", files)
+	return nil
+}
+
+// Watch reloads policy files periodically until ctx is done
+func (p *OpaPolicy) Watch(ctx context.Context) {
+	ticker := time.NewTicker(OPA_POLICY_RELOAD_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.Reload(); err != nil {
+				opaPolicyLogger.Errorf("reload opa policy failed, keep the active one: %s", err)
+			}
+		}
+	}
+}
+
+// Eval evaluates input against the active policy
+func (p *OpaPolicy) Eval(ctx context.Context, input interface{}) (bool, error) {
+	p.mu.RLock()
+	prepared := p.prepared
+	p.mu.RUnlock()
+
+	return DefaultEvalFunc(ctx, prepared, input)
+}
+
+func (p *OpaPolicy) Status() *OpaPolicyStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	status := &OpaPolicyStatus{
+		Query:    p.query,
+		Dir:      p.dir,
+		Files:    append([]string{}, p.files...),
+		LoadedAt: p.loadedAt,
+	}
+	if p.lastErr != nil {
+		status.LastError = p.lastErr.Error()
+	}
+	return status
+}
+
+func (p *OpaPolicy) setError(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastErr = err
+}
+
+// readPolicyFiles return file name -> module, and a digest of all the files
+func (p *OpaPolicy) readPolicyFiles() (map[string]string, string, error) {
+	modules := make(map[string]string)
+	if p.dir == "" {
+		return modules, "", nil
+	}
+
+	entries, err := os.ReadDir(p.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return modules, "", nil
+		}
+		return nil, "", err
+	}
+
+	hash := sha256.New()
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != opaPolicyFileExt {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(p.dir, entry.Name()))
+		if err != nil {
+			return nil, "", err
+		}
+		modules[entry.Name()] = string(data)
+
+		hash.Write([]byte(entry.Name()))
+		hash.Write(data)
+	}
+
+	return modules, hex.EncodeToString(hash.Sum(nil)), nil
+}
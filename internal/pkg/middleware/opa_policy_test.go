@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testBuiltinPolicy = `package quorum.test.authz
+
+default allow = false
+
+allow {
+	input.role == "admin"
+}
+`
+
+const testOperatorPolicy = `package quorum.test.authz
+
+allow {
+	input.role == "reader"
+	input.method == "GET"
+}
+`
+
+const testPolicyQuery = "x = data.quorum.test.authz.allow"
+
+func checkOpaEval(t *testing.T, p *OpaPolicy, input map[string]interface{}, expected bool) {
+	allow, err := p.Eval(context.Background(), input)
+	if err != nil && !errors.Is(err, ErrNoPermission) {
+		t.Fatalf("Eval failed: %s", err)
+	}
+	if allow != expected {
+		t.Fatalf("Eval <%v> got <%v>, expect <%v>", input, allow, expected)
+	}
+}
+
+func TestOpaPolicyReload(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "policies")
+	reader := map[string]interface{}{"role": "reader", "method": "GET"}
+	admin := map[string]interface{}{"role": "admin", "method": "POST"}
+
+	//policy dir does not exist, only the builtin policy is loaded
+	p, err := NewOpaPolicy(testBuiltinPolicy, testPolicyQuery, dir)
+	if err != nil {
+		t.Fatalf("NewOpaPolicy failed: %s", err)
+	}
+	checkOpaEval(t, p, admin, true)
+	checkOpaEval(t, p, reader, false)
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "reader.rego"), []byte(testOperatorPolicy), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a policy"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Reload(); err != nil {
+		t.Fatalf("Reload failed: %s", err)
+	}
+	checkOpaEval(t, p, admin, true)
+	checkOpaEval(t, p, reader, true)
+	if status := p.Status(); !reflect.DeepEqual(status.Files, []string{"reader.rego"}) || status.LastError != "" {
+		t.Fatalf("unexpected policy status <%+v>", status)
+	}
+
+	//a broken policy file keeps the active policy
+	if err := os.WriteFile(filepath.Join(dir, "broken.rego"), []byte("package quorum.test.authz\nallow {"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Reload(); err == nil {
+		t.Fatal("Reload of broken policy should fail")
+	}
+	checkOpaEval(t, p, reader, true)
+	if status := p.Status(); !reflect.DeepEqual(status.Files, []string{"reader.rego"}) || status.LastError == "" {
+		t.Fatalf("unexpected policy status <%+v>", status)
+	}
+
+	//files removed, back to the builtin policy
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Reload(); err != nil {
+		t.Fatalf("Reload failed: %s", err)
+	}
+	checkOpaEval(t, p, reader, false)
+	if status := p.Status(); len(status.Files) != 0 || status.LastError != "" {
+		t.Fatalf("unexpected policy status <%+v>", status)
+	}
+}
+
+func TestNewOpaPolicyBroken(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.rego"), []byte("package quorum.test.authz\nallow {"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewOpaPolicy(testBuiltinPolicy, testPolicyQuery, dir); err == nil {
+		t.Fatal("NewOpaPolicy with broken policy file should fail")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"sync"
 
 	"github.com/rumsystem/quorum/internal/pkg/logging"
+	node_storage "github.com/rumsystem/quorum/internal/pkg/storage/node"
 )
 
 var optionslog = logging.Logger("options")
@@ -16,12 +17,17 @@ type NodeOptions struct {
 	EnableDevNetwork  bool
 	EnableSnapshot    bool
 	EnablePubQue      bool
+	EnableSearchIndex bool
+	OpaQuery          string // rule of opa policy which decides if a request is allowed
+	OpaPolicyDir      string // directory of operator supplied opa policy files (*.rego)
 	MaxPeers          int
 	ConnsHi           int
 	NetworkName       string
+	StorageEngine     string // kv engine of node db: bolt, badger or memory
 	JWT               *JWT
 	SignKeyMap        map[string]string
 	mu                sync.RWMutex
+	jwtStore          *node_storage.Storage // revoked jwt, nil for command line tools
 }
 
 type (
@@ -33,7 +39,7 @@ type (
 
 	JWTListItem struct {
 		Normal []*TokenItem `json:"normal" mapstructure:"normal"`
-		Revoke []*TokenItem `json:"revoke" mapstructure:"revoke"`
+		Revoke []*TokenItem `json:"revoke" mapstructure:"revoke"` // only used when node db is not available, moved to node db on start
 	}
 
 	TokenItem struct {
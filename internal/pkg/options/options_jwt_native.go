@@ -1,13 +1,119 @@
 package options
 
 import (
+	"context"
 	"errors"
 	"time"
 
+	"github.com/golang-jwt/jwt/v4"
+	node_storage "github.com/rumsystem/quorum/internal/pkg/storage/node"
 	"github.com/rumsystem/quorum/internal/pkg/utils"
 )
 
-func (opt *NodeOptions) NewChainJWT(name string, exp time.Time) (string, error) {
+var JWT_PRUNE_INTERVAL = 1 * time.Hour // interval to prune expired tokens from revocation list
+
+// InitJWTStore saves revoked tokens to node db instead of config file,
+// tokens revoked before are moved to node db, and expired ones are pruned periodically until ctx is done
+func (opt *NodeOptions) InitJWTStore(ctx context.Context, store *node_storage.Storage) error {
+	opt.mu.Lock()
+	opt.jwtStore = store
+
+	moved := 0
+	lists := []*JWTListItem{}
+	if opt.JWT.Chain != nil {
+		lists = append(lists, opt.JWT.Chain)
+	}
+	for _, g := range opt.JWT.Node {
+		lists = append(lists, g)
+	}
+	for _, l := range lists {
+		for _, v := range l.Revoke {
+			if err := opt.saveRevokedJWT(v.Token); err != nil {
+				opt.mu.Unlock()
+				return err
+			}
+			moved++
+		}
+		l.Revoke = nil
+	}
+
+	var err error
+	if moved > 0 {
+		logger.Infof("%d revoked jwt moved from config file to node db", moved)
+		err = opt.writeToconfig()
+	}
+	opt.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(JWT_PRUNE_INTERVAL)
+		defer ticker.Stop()
+		for {
+			count, err := store.PruneRevokedJWT(time.Now())
+			if err != nil {
+				logger.Warnf("prune revoked jwt failed: %s", err)
+			} else if count > 0 {
+				logger.Infof("%d expired jwt pruned from revocation list", count)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
+
+// saveRevokedJWT saves token to revocation list of node db, caller should hold opt.mu
+func (opt *NodeOptions) saveRevokedJWT(tokenStr string) error {
+	token, err := utils.ParseJWTTokenUnverified(tokenStr)
+	if err != nil {
+		return err
+	}
+	item := &node_storage.RevokedJWTItem{
+		Jti:       utils.GetJWTId(token),
+		ExpiresAt: utils.GetJWTExpiresAt(token),
+		RevokedAt: time.Now().UnixNano(),
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	item.Name, _ = claims["name"].(string)
+	item.Role, _ = claims["role"].(string)
+	item.GroupId, _ = claims["allowGroup"].(string)
+
+	return opt.jwtStore.SaveRevokedJWT(item)
+}
+
+// JWTStore returns node db of jwt, nil if it is not available
+func (opt *NodeOptions) JWTStore() *node_storage.Storage {
+	opt.mu.RLock()
+	defer opt.mu.RUnlock()
+	return opt.jwtStore
+}
+
+// isRevokedJWT checks revocation list of node db, it always returns false if node db is not available
+func (opt *NodeOptions) isRevokedJWT(tokenStr string) bool {
+	if opt.jwtStore == nil {
+		return false
+	}
+	token, err := utils.ParseJWTTokenUnverified(tokenStr)
+	if err != nil {
+		return true
+	}
+	revoked, err := opt.jwtStore.IsJWTRevoked(utils.GetJWTId(token))
+	if err != nil {
+		logger.Warnf("check revoked jwt failed: %s", err)
+		return true
+	}
+	return revoked
+}
+
+// NewChainJWT creates a chain jwt and saves it to config file, scope can be nil
+func (opt *NodeOptions) NewChainJWT(name string, exp time.Time, scope *utils.JWTScope) (string, error) {
 	opt.mu.Lock()
 	defer opt.mu.Unlock()
 
@@ -19,7 +125,7 @@ func (opt *NodeOptions) NewChainJWT(name string, exp time.Time) (string, error)
 		opt.JWT.Chain.Normal = []*TokenItem{}
 	}
 
-	token, err := utils.NewJWTToken(name, "chain", "*", opt.JWT.Key, exp)
+	token, err := utils.NewScopedJWTToken(name, "chain", "*", scope, opt.JWT.Key, exp)
 	if err != nil {
 		return "", err
 	}
@@ -51,10 +157,11 @@ func (opt *NodeOptions) GetOrCreateNodeJwt(groupid, name string, exp time.Time)
 		return token, nil
 	}
 
-	return opt.NewNodeJWT(groupid, name, exp)
+	return opt.NewNodeJWT(groupid, name, exp, nil)
 }
 
-func (opt *NodeOptions) NewNodeJWT(groupid, name string, exp time.Time) (string, error) {
+// NewNodeJWT creates a node jwt for groupid and saves it to config file, scope can be nil
+func (opt *NodeOptions) NewNodeJWT(groupid, name string, exp time.Time, scope *utils.JWTScope) (string, error) {
 	opt.mu.Lock()
 	defer opt.mu.Unlock()
 
@@ -68,7 +175,7 @@ func (opt *NodeOptions) NewNodeJWT(groupid, name string, exp time.Time) (string,
 		g = opt.JWT.Node[groupid]
 	}
 
-	token, err := utils.NewJWTToken(name, "node", groupid, opt.JWT.Key, exp)
+	token, err := utils.NewScopedJWTToken(name, "node", groupid, scope, opt.JWT.Key, exp)
 	if err != nil {
 		return "", err
 	}
@@ -85,7 +192,9 @@ func (opt *NodeOptions) RevokeChainJWT(token string) error {
 	var tokens []*TokenItem
 	for _, v := range opt.JWT.Chain.Normal {
 		if v.Token == token {
-			opt.JWT.Chain.Revoke = append(opt.JWT.Chain.Revoke, v)
+			if err := opt.revoke(opt.JWT.Chain, v); err != nil {
+				return err
+			}
 			continue
 		}
 		tokens = append(tokens, v)
@@ -107,7 +216,9 @@ func (opt *NodeOptions) RevokeNodeJWT(groupid, token string) error {
 	var tokens []*TokenItem
 	for _, v := range g.Normal {
 		if v.Token == token {
-			g.Revoke = append(g.Revoke, v)
+			if err := opt.revoke(g, v); err != nil {
+				return err
+			}
 			continue
 		}
 		tokens = append(tokens, v)
@@ -117,6 +228,15 @@ func (opt *NodeOptions) RevokeNodeJWT(groupid, token string) error {
 	return opt.writeToconfig()
 }
 
+// revoke saves item to node db if it is available, or to revoke list of config file
+func (opt *NodeOptions) revoke(l *JWTListItem, item *TokenItem) error {
+	if opt.jwtStore == nil {
+		l.Revoke = append(l.Revoke, item)
+		return nil
+	}
+	return opt.saveRevokedJWT(item.Token)
+}
+
 func (opt *NodeOptions) RemoveChainJWT(token string) error {
 	opt.mu.Lock()
 	defer opt.mu.Unlock()
@@ -189,7 +309,7 @@ func (opt *NodeOptions) IsValidChainJWT(token string) bool {
 
 	for _, v := range opt.JWT.Chain.Normal {
 		if token == v.Token {
-			return true
+			return !opt.isRevokedJWT(token)
 		}
 	}
 
@@ -212,7 +332,7 @@ func (opt *NodeOptions) IsValidNodeJWT(groupid, token string) bool {
 
 	for _, v := range g.Normal {
 		if token == v.Token {
-			return true
+			return !opt.isRevokedJWT(token)
 		}
 	}
 
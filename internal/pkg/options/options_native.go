@@ -25,6 +25,8 @@ const JWTKeyLength = 32
 const defaultNetworkName = "staten"
 const defaultMaxPeers = 50
 const defaultConnsHi = 100
+const defaultStorageEngine = "bolt"
+const defaultOpaQuery = "data.quorum.restapi.authz.decision"
 
 func GetNodeOptions() *NodeOptions {
 	return nodeopts
@@ -130,6 +132,9 @@ func initConfigfile(dir, keyname string) error {
 	})
 	viper.SetDefault("EnableSnapshot", true)
 	viper.SetDefault("EnablePubQue", true)
+	viper.SetDefault("EnableSearchIndex", false)
+	viper.SetDefault("OpaQuery", defaultOpaQuery)
+	viper.SetDefault("StorageEngine", defaultStorageEngine)
 
 	return nil
 }
@@ -145,6 +150,10 @@ func load(configdir, peername string) (*NodeOptions, error) {
 		panic(err)
 	}
 
+	if options.OpaPolicyDir == "" {
+		options.OpaPolicyDir = filepath.Join(configdir, peername+"_policies")
+	}
+
 	return options, nil
 }
 
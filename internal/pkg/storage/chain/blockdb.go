@@ -1,7 +1,8 @@
 package chainstorage
 
 import (
-	"errors"
+	"sort"
+	"strconv"
 
 	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
 	s "github.com/rumsystem/quorum/internal/pkg/storage"
@@ -9,9 +10,44 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-// add block
+// add block, trxs in a block saved to chain are indexed by trx_id
 func (cs *Storage) AddBlock(block *quorumpb.Block, cached bool, prefix ...string) error {
-	return cs.dbmgr.SaveBlock(block, cached, prefix...)
+	if err := cs.dbmgr.SaveBlock(block, cached, prefix...); err != nil {
+		return err
+	}
+	if cached {
+		return nil
+	}
+	return cs.SaveTrxBlockIndex(block, prefix...)
+}
+
+// SaveTrxBlockIndex save trx_id -> block_id for all trxs in the block
+func (cs *Storage) SaveTrxBlockIndex(block *quorumpb.Block, prefix ...string) error {
+	if len(block.Trxs) == 0 {
+		return nil
+	}
+
+	keys := [][]byte{}
+	values := [][]byte{}
+	for _, trx := range block.Trxs {
+		keys = append(keys, []byte(s.GetTrxBlockKey(block.GroupId, trx.TrxId, prefix...)))
+		values = append(values, []byte(strconv.FormatUint(block.BlockId, 10)))
+	}
+	return cs.dbmgr.Db.BatchWrite(keys, values)
+}
+
+// GetTrxBlockId return false if the trx is not indexed
+func (cs *Storage) GetTrxBlockId(groupId string, trxId string, prefix ...string) (uint64, bool, error) {
+	value, err := cs.dbmgr.Db.Get([]byte(s.GetTrxBlockKey(groupId, trxId, prefix...)))
+	if err != nil || value == nil {
+		return 0, false, err
+	}
+
+	blockId, err := strconv.ParseUint(string(value), 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	return blockId, true, nil
 }
 
 // add genesis block
@@ -38,35 +74,48 @@ func (cs *Storage) IsBlockExist(groupId string, blockId uint64, cached bool, pre
 	return cs.dbmgr.IsBlockExist(groupId, blockId, cached, prefix...)
 }
 
+// GatherBlocksFromCache return the block and cached blocks connected after it by block id
 func (cs *Storage) GatherBlocksFromCache(block *quorumpb.Block, prefix ...string) ([]*quorumpb.Block, error) {
+	// block ids in keys are not padded, so cached blocks are looked up one by one instead of by prefix order
+	blocks := []*quorumpb.Block{block}
+	for blockId := block.BlockId + 1; ; blockId++ {
+		exist, err := cs.dbmgr.IsBlockExist(block.GroupId, blockId, true, prefix...)
+		if err != nil {
+			return nil, err
+		}
+		if !exist {
+			return blocks, nil
+		}
+		b, err := cs.dbmgr.GetBlock(block.GroupId, blockId, true, prefix...)
+		if err != nil {
+			return nil, err
+		}
+		if b.GroupId != block.GroupId || b.BlockId != blockId {
+			return blocks, nil
+		}
+		blocks = append(blocks, b)
+	}
+}
+
+// GetCachedBlocks return all cached blocks of a group sorted by block id, keys which can not be parsed as a block are skipped
+func (cs *Storage) GetCachedBlocks(groupId string, prefix ...string) ([]*quorumpb.Block, error) {
 	var blocks []*quorumpb.Block
-	blocks = append(blocks, block)
-	currBlockId := block.BlockId
-	pre := s.GetCachedBlockPrefix(block.GroupId, prefix...)
+	pre := s.GetCachedBlockPrefix(groupId, prefix...)
 	err := cs.dbmgr.Db.PrefixForeach([]byte(pre), func(k []byte, v []byte, err error) error {
 		if err != nil {
 			return err
 		}
-
 		b := &quorumpb.Block{}
-		perr := proto.Unmarshal(v, b)
-		if perr != nil {
-			return perr
-		}
-
-		currBlockId += 1
-		if b.GroupId == block.GroupId && b.BlockId == currBlockId {
-			blocks = append(blocks, b)
+		if err := proto.Unmarshal(v, b); err != nil {
+			logger.Warnf("unmarshal cached block <%s> failed: %s", k, err)
 			return nil
-		} else {
-			return errors.New("NO_MORE_BLOCK")
 		}
+		blocks = append(blocks, b)
+		return nil
 	})
-
-	//search done, no more block to attach
-	if err == nil || err.Error() == "NO_MORE_BLOCK" {
-		return blocks, nil
+	if err != nil {
+		return nil, err
 	}
-
-	return nil, err
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].BlockId < blocks[j].BlockId })
+	return blocks, nil
 }
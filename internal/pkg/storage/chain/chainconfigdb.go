@@ -64,6 +64,22 @@ func (cs *Storage) UpdateChainConfig(data []byte, prefix ...string) (err error)
 		}
 
 		return cs.dbmgr.Db.Delete([]byte(key))
+	} else if item.Type == quorumpb.ChainConfigType_SET_MEMPOOL_POLICY {
+		policyItem := &quorumpb.MempoolPolicyItem{}
+		if err := proto.Unmarshal(item.Data, policyItem); err != nil {
+			return err
+		}
+
+		key := s.GetChainConfigMempoolKey(item.GroupId, prefix...)
+		return cs.dbmgr.Db.Set([]byte(key), data)
+	} else if item.Type == quorumpb.ChainConfigType_SET_EPOCH_PACING {
+		pacingItem := &quorumpb.EpochPacingItem{}
+		if err := proto.Unmarshal(item.Data, pacingItem); err != nil {
+			return err
+		}
+
+		key := s.GetChainConfigPacingKey(item.GroupId, prefix...)
+		return cs.dbmgr.Db.Set([]byte(key), data)
 	} else {
 		return errors.New("Unsupported ChainConfig type")
 	}
@@ -100,6 +116,62 @@ func (cs *Storage) GetTrxAuthModeByGroupId(groupId string, trxType quorumpb.TrxT
 	return trxAuthitem.Mode, nil
 }
 
+// GetMempoolPolicy return the mempool policy set by group owner, nil if not set
+func (cs *Storage) GetMempoolPolicy(groupId string, prefix ...string) (*quorumpb.MempoolPolicyItem, error) {
+	key := s.GetChainConfigMempoolKey(groupId, prefix...)
+	isExist, err := cs.dbmgr.Db.IsExist([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+	if !isExist {
+		return nil, nil
+	}
+
+	value, err := cs.dbmgr.Db.Get([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+
+	chainConfigItem := &quorumpb.ChainConfigItem{}
+	if err := proto.Unmarshal(value, chainConfigItem); err != nil {
+		return nil, err
+	}
+
+	policyItem := &quorumpb.MempoolPolicyItem{}
+	if err := proto.Unmarshal(chainConfigItem.Data, policyItem); err != nil {
+		return nil, err
+	}
+	return policyItem, nil
+}
+
+// GetEpochPacing return the epoch pacing set by group owner, nil if not set
+func (cs *Storage) GetEpochPacing(groupId string, prefix ...string) (*quorumpb.EpochPacingItem, error) {
+	key := s.GetChainConfigPacingKey(groupId, prefix...)
+	isExist, err := cs.dbmgr.Db.IsExist([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+	if !isExist {
+		return nil, nil
+	}
+
+	value, err := cs.dbmgr.Db.Get([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+
+	chainConfigItem := &quorumpb.ChainConfigItem{}
+	if err := proto.Unmarshal(value, chainConfigItem); err != nil {
+		return nil, err
+	}
+
+	pacingItem := &quorumpb.EpochPacingItem{}
+	if err := proto.Unmarshal(chainConfigItem.Data, pacingItem); err != nil {
+		return nil, err
+	}
+	return pacingItem, nil
+}
+
 func (cs *Storage) GetSendTrxAuthListByGroupId(groupId string, listType quorumpb.AuthListType, prefix ...string) ([]*quorumpb.ChainConfigItem, []*quorumpb.ChainSendTrxRuleListItem, error) {
 	var chainConfigList []*quorumpb.ChainConfigItem
 	var sendTrxRuleList []*quorumpb.ChainSendTrxRuleListItem
@@ -65,8 +65,8 @@ func (cs *Storage) RemoveTrxHBB(trxId, queueId string) error {
 		return errors.New("Trx not exist")
 	}
 
-	_, err = cs.dbmgr.Db.PrefixDelete([]byte(key))
-	return err
+	//key of a trx is a prefix of keys of trxs with longer ids, only delete the key itself
+	return cs.dbmgr.Db.Delete([]byte(key))
 }
 
 func (cs *Storage) RemoveAllTrxHBB(queueId string) error {
@@ -99,3 +99,31 @@ func (cs *Storage) GetTrxByIdHBB(trxId string, queueId string) (*quorumpb.Trx, e
 
 	return trx, nil
 }
+
+// SaveThresholdKey saves the dkg result of the group, it contains the key share of the producer
+func (cs *Storage) SaveThresholdKey(groupId string, key *quorumpb.HBThresholdKey, prefix ...string) error {
+	value, err := proto.Marshal(key)
+	if err != nil {
+		return err
+	}
+	return cs.dbmgr.Db.Set([]byte(s.GetThresholdKeyKey(groupId, prefix...)), value)
+}
+
+// GetThresholdKey return the saved dkg result of the group, nil if not found
+func (cs *Storage) GetThresholdKey(groupId string, prefix ...string) (*quorumpb.HBThresholdKey, error) {
+	key := []byte(s.GetThresholdKeyKey(groupId, prefix...))
+	exist, err := cs.dbmgr.Db.IsExist(key)
+	if err != nil || !exist {
+		return nil, err
+	}
+
+	value, err := cs.dbmgr.Db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	tk := &quorumpb.HBThresholdKey{}
+	if err := proto.Unmarshal(value, tk); err != nil {
+		return nil, err
+	}
+	return tk, nil
+}
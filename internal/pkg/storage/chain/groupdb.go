@@ -126,6 +126,22 @@ func RemoveGroupData(db s.QuorumStorage, groupId string, prefix ...string) error
 	key = s.GetTrxPrefix(groupId, prefix...)
 	keys = append(keys, key)
 
+	// trx -> block index
+	key = s.GetTrxBlockPrefix(groupId, prefix...)
+	keys = append(keys, key)
+
+	// stake and latest snapshot
+	key = s.GetStakePrefix(groupId, prefix...)
+	keys = append(keys, key)
+	key = s.GetSnapshotKey(groupId, prefix...)
+	keys = append(keys, key)
+
+	// retention policy and pruned range
+	key = s.GetRetentionKey(groupId, prefix...)
+	keys = append(keys, key)
+	key = s.GetPruneStateKey(groupId, prefix...)
+	keys = append(keys, key)
+
 	//remove all
 	for _, key_prefix := range keys {
 		_, err := db.PrefixDelete([]byte(key_prefix))
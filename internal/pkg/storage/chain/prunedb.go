@@ -0,0 +1,181 @@
+package chainstorage
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	s "github.com/rumsystem/quorum/internal/pkg/storage"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// retention modes
+const (
+	RetentionAll    = "all"    // keep all blocks, the default
+	RetentionBlocks = "blocks" // keep bodies of the last n blocks
+	RetentionDays   = "days"   // keep bodies of blocks produced in the last n days
+	RetentionState  = "state"  // keep only derived state and block headers
+)
+
+type (
+	// RetentionPolicy decides which blocks of a group keep their trxs, derived state and block headers are always kept
+	RetentionPolicy struct {
+		Mode  string `json:"mode" example:"blocks"`
+		Value uint64 `json:"value" example:"100000"` // blocks or days to keep, unused by all and state
+	}
+
+	// PruneState is the pruned range of a group, blocks in [1, PrunedTo] are saved as headers without trxs
+	// (except old blocks without trxs root), and their trxs are saved without data
+	PruneState struct {
+		PrunedTo   uint64 `json:"pruned_to" example:"1000"` // 0 if nothing is pruned
+		PrunedAt   int64  `json:"pruned_at" example:"1680000000000000000"`
+		PrunedTrxs uint64 `json:"pruned_trxs" example:"5000"`
+	}
+)
+
+func (p *RetentionPolicy) Validate() error {
+	switch p.Mode {
+	case RetentionAll, RetentionState:
+		return nil
+	case RetentionBlocks, RetentionDays:
+		if p.Value == 0 {
+			return fmt.Errorf("value of retention mode %s should be greater than 0", p.Mode)
+		}
+		return nil
+	}
+	return fmt.Errorf("invalid retention mode: %s", p.Mode)
+}
+
+func (cs *Storage) SetRetentionPolicy(groupId string, policy *RetentionPolicy, prefix ...string) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	value, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	return cs.dbmgr.Db.Set([]byte(s.GetRetentionKey(groupId, prefix...)), value)
+}
+
+// GetRetentionPolicy return the policy of a group, all blocks are kept if it is not set
+func (cs *Storage) GetRetentionPolicy(groupId string, prefix ...string) (*RetentionPolicy, error) {
+	policy := &RetentionPolicy{Mode: RetentionAll}
+	value, err := cs.dbmgr.Db.Get([]byte(s.GetRetentionKey(groupId, prefix...)))
+	if err != nil || value == nil {
+		return policy, err
+	}
+	if err := json.Unmarshal(value, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (cs *Storage) GetPruneState(groupId string, prefix ...string) (*PruneState, error) {
+	state := &PruneState{}
+	value, err := cs.dbmgr.Db.Get([]byte(s.GetPruneStateKey(groupId, prefix...)))
+	if err != nil || value == nil {
+		return state, err
+	}
+	if err := json.Unmarshal(value, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// IsBlockPruned return true if only the header of the block is kept, genesis block is never pruned
+func (cs *Storage) IsBlockPruned(groupId string, blockId uint64, prefix ...string) (bool, error) {
+	state, err := cs.GetPruneState(groupId, prefix...)
+	if err != nil {
+		return false, err
+	}
+	return blockId > 0 && blockId <= state.PrunedTo, nil
+}
+
+// PruneBlocks drops trxs of blocks after the pruned range until toBlockId (included). block headers with trxs root
+// are kept for chain validation, trxs are kept without data so applied trxs are still known, except the latest
+// producer list trx which is needed by the chain. blocks without trxs root (created by old version) are kept
+// with their trxs, their hashes cover the trxs. cached blocks in the range are removed
+func (cs *Storage) PruneBlocks(groupId string, toBlockId uint64, prefix ...string) (*PruneState, error) {
+	state, err := cs.GetPruneState(groupId, prefix...)
+	if err != nil {
+		return nil, err
+	}
+	if toBlockId <= state.PrunedTo {
+		return state, nil
+	}
+
+	keepTrxId, err := cs.dbmgr.Db.Get([]byte(s.GetProducerTrxIDKey(groupId, prefix...)))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := [][]byte{}
+	values := [][]byte{}
+	for blockId := state.PrunedTo + 1; blockId <= toBlockId; blockId++ {
+		exist, err := cs.dbmgr.IsBlockExist(groupId, blockId, false, prefix...)
+		if err != nil {
+			return nil, err
+		}
+		if !exist {
+			continue
+		}
+		block, err := cs.dbmgr.GetBlock(groupId, blockId, false, prefix...)
+		if err != nil {
+			return nil, err
+		}
+		if err := cs.dbmgr.Db.Delete([]byte(s.GetCachedBlockKey(groupId, blockId, prefix...))); err != nil {
+			return nil, err
+		}
+		if len(block.TrxsRoot) == 0 {
+			continue
+		}
+
+		for _, trx := range block.Trxs {
+			if trx.TrxId == string(keepTrxId) {
+				continue
+			}
+			trxKey := []byte(s.GetTrxKey(groupId, trx.TrxId, prefix...))
+			exist, err := cs.dbmgr.Db.IsExist(trxKey)
+			if err != nil {
+				return nil, err
+			}
+			if !exist {
+				continue
+			}
+			stub := proto.Clone(trx).(*quorumpb.Trx)
+			stub.Data = nil
+			value, err := proto.Marshal(stub)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, trxKey)
+			values = append(values, value)
+			state.PrunedTrxs++
+		}
+
+		block.Trxs = nil
+		value, err := proto.Marshal(block)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, []byte(s.GetBlockKey(groupId, blockId, prefix...)))
+		values = append(values, value)
+	}
+
+	state.PrunedTo = toBlockId
+	state.PrunedAt = time.Now().UnixNano()
+	value, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	keys = append(keys, []byte(s.GetPruneStateKey(groupId, prefix...)))
+	values = append(values, value)
+
+	// headers, trxs and the new range are saved at once, an interrupted prune is redone from the old range
+	if err := cs.dbmgr.Db.BatchWrite(keys, values); err != nil {
+		return nil, err
+	}
+	chaindb_log.Debugf("<%s> blocks pruned to <%d>", groupId, toBlockId)
+	return state, nil
+}
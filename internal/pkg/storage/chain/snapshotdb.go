@@ -0,0 +1,98 @@
+package chainstorage
+
+import (
+	"fmt"
+	"strings"
+
+	s "github.com/rumsystem/quorum/internal/pkg/storage"
+	"github.com/rumsystem/quorum/internal/pkg/utils"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// GetSnapshotItems collect all derived state of a group, node prefix is removed from keys
+func (cs *Storage) GetSnapshotItems(groupId string, prefix ...string) ([]*quorumpb.SnapshotItem, error) {
+	nodeprefix := utils.GetPrefix(prefix...)
+	var items []*quorumpb.SnapshotItem
+	for _, p := range s.GetSnapshotStatePrefixes(groupId, prefix...) {
+		err := cs.dbmgr.Db.PrefixForeach([]byte(p), func(k []byte, v []byte, err error) error {
+			if err != nil {
+				return err
+			}
+			value := make([]byte, len(v))
+			copy(value, v)
+			items = append(items, &quorumpb.SnapshotItem{
+				Key:   strings.TrimPrefix(string(k), nodeprefix),
+				Value: value,
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
+
+// ApplySnapshotItems replace all derived state of a group with items in a snapshot
+func (cs *Storage) ApplySnapshotItems(groupId string, items []*quorumpb.SnapshotItem, prefix ...string) error {
+	nodeprefix := utils.GetPrefix(prefix...)
+	allowed := s.GetSnapshotStatePrefixes(groupId)
+
+	keys := [][]byte{}
+	values := [][]byte{}
+	for _, item := range items {
+		valid := false
+		for _, p := range allowed {
+			if strings.HasPrefix(item.Key, p) {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("invalid snapshot item key <%s>", item.Key)
+		}
+		keys = append(keys, []byte(nodeprefix+item.Key))
+		values = append(values, item.Value)
+	}
+
+	for _, p := range s.GetSnapshotStatePrefixes(groupId, prefix...) {
+		if _, err := cs.dbmgr.Db.PrefixDelete([]byte(p)); err != nil {
+			return err
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+	return cs.dbmgr.Db.BatchWrite(keys, values)
+}
+
+func (cs *Storage) SaveSnapshot(snapshot *quorumpb.Snapshot, prefix ...string) error {
+	data, err := proto.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	key := s.GetSnapshotKey(snapshot.GroupId, prefix...)
+	return cs.dbmgr.Db.Set([]byte(key), data)
+}
+
+// GetSnapshot return the latest snapshot of a group, nil if no snapshot created yet
+func (cs *Storage) GetSnapshot(groupId string, prefix ...string) (*quorumpb.Snapshot, error) {
+	key := s.GetSnapshotKey(groupId, prefix...)
+	exist, err := cs.dbmgr.Db.IsExist([]byte(key))
+	if err != nil || !exist {
+		return nil, err
+	}
+
+	value, err := cs.dbmgr.Db.Get([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &quorumpb.Snapshot{}
+	if err := proto.Unmarshal(value, snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
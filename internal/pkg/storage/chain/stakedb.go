@@ -0,0 +1,136 @@
+package chainstorage
+
+import (
+	"errors"
+	"fmt"
+
+	s "github.com/rumsystem/quorum/internal/pkg/storage"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// MAX_STAKE caps the total stake of a staker, so stake sums of all producers (even times 3) never overflow int64
+// in the stake-weighted consensus, see consensus.NewConfig
+const MAX_STAKE int64 = 1 << 48
+
+// UpdateStakeTrx apply a stake trx, stake is granted by group owner (e.g. after the staker deposited),
+// a staker can only withdraw its own stake
+func (cs *Storage) UpdateStakeTrx(trx *quorumpb.Trx, prefix ...string) error {
+	item := &quorumpb.StakeItem{}
+	if err := proto.Unmarshal(trx.Data, item); err != nil {
+		return err
+	}
+
+	if item.GroupId != trx.GroupId {
+		return fmt.Errorf("stake group <%s> mismatch with trx group <%s>", item.GroupId, trx.GroupId)
+	}
+
+	groupInfo, err := cs.GetGroupInfo(trx.GroupId)
+	if err != nil {
+		return err
+	}
+
+	isOwner := trx.SenderPubkey == groupInfo.OwnerPubKey
+	switch item.Action {
+	case quorumpb.ActionType_ADD:
+		if !isOwner {
+			return fmt.Errorf("stake of <%s> can only be added by group owner, trx sender <%s>", item.StakerPubkey, trx.SenderPubkey)
+		}
+	case quorumpb.ActionType_REMOVE:
+		if !isOwner && item.StakerPubkey != trx.SenderPubkey {
+			return fmt.Errorf("staker <%s> mismatch with trx sender <%s>", item.StakerPubkey, trx.SenderPubkey)
+		}
+	}
+
+	return cs.UpdateStake(item, prefix...)
+}
+
+// UpdateStake add or withdraw stake, the saved item keeps the total stake of the staker
+func (cs *Storage) UpdateStake(item *quorumpb.StakeItem, prefix ...string) error {
+	if item.Amount <= 0 || item.Amount > MAX_STAKE {
+		return fmt.Errorf("stake amount <%d> should be in (0, %d]", item.Amount, MAX_STAKE)
+	}
+
+	var total int64
+	curr, err := cs.GetStake(item.GroupId, item.StakerPubkey, prefix...)
+	if err != nil {
+		return err
+	}
+	if curr != nil {
+		total = curr.Amount
+	}
+
+	key := s.GetStakeKey(item.GroupId, item.StakerPubkey, prefix...)
+	switch item.Action {
+	case quorumpb.ActionType_ADD:
+		//both are not larger than MAX_STAKE, the sum never overflows
+		total = total + item.Amount
+		if total > MAX_STAKE {
+			return fmt.Errorf("total stake of <%s> exceeds <%d>", item.StakerPubkey, MAX_STAKE)
+		}
+	case quorumpb.ActionType_REMOVE:
+		total = total - item.Amount
+		if total <= 0 {
+			chaindb_log.Infof("stake of <%s> withdrawn", item.StakerPubkey)
+			return cs.dbmgr.Db.Delete([]byte(key))
+		}
+	default:
+		return errors.New("unknown stake action")
+	}
+
+	saved := &quorumpb.StakeItem{
+		GroupId:      item.GroupId,
+		StakerPubkey: item.StakerPubkey,
+		Action:       quorumpb.ActionType_ADD,
+		Amount:       total,
+		TimeStamp:    item.TimeStamp,
+		Memo:         item.Memo,
+	}
+
+	data, err := proto.Marshal(saved)
+	if err != nil {
+		return err
+	}
+
+	chaindb_log.Infof("update stake with key %s, total <%d>", key, total)
+	return cs.dbmgr.Db.Set([]byte(key), data)
+}
+
+// GetStake return nil if staker has no stake
+func (cs *Storage) GetStake(groupId string, pubkey string, prefix ...string) (*quorumpb.StakeItem, error) {
+	key := s.GetStakeKey(groupId, pubkey, prefix...)
+	exist, err := cs.dbmgr.Db.IsExist([]byte(key))
+	if err != nil || !exist {
+		return nil, err
+	}
+
+	value, err := cs.dbmgr.Db.Get([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+
+	item := &quorumpb.StakeItem{}
+	if err := proto.Unmarshal(value, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (cs *Storage) GetStakes(groupId string, prefix ...string) ([]*quorumpb.StakeItem, error) {
+	var sList []*quorumpb.StakeItem
+	key := s.GetStakePrefix(groupId, prefix...)
+
+	err := cs.dbmgr.Db.PrefixForeach([]byte(key), func(k []byte, v []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		item := &quorumpb.StakeItem{}
+		if err := proto.Unmarshal(v, item); err != nil {
+			return err
+		}
+		sList = append(sList, item)
+		return nil
+	})
+
+	return sList, err
+}
@@ -0,0 +1,79 @@
+package chainstorage
+
+import (
+	"fmt"
+
+	s "github.com/rumsystem/quorum/internal/pkg/storage"
+)
+
+// derived tables of a group which can be rebuilt by replaying trxs in blocks
+const (
+	StateProducers = "producers"
+	StateUsers     = "users"
+	StateAppConfig = "appconfig"
+)
+
+var StateTables = []string{StateProducers, StateUsers, StateAppConfig}
+
+func getStatePrefix(groupId string, table string, prefix ...string) string {
+	switch table {
+	case StateProducers:
+		return s.GetProducerPrefix(groupId, prefix...)
+	case StateUsers:
+		return s.GetUserPrefix(groupId, prefix...)
+	case StateAppConfig:
+		return s.GetAppConfigPrefix(groupId, prefix...) + "_"
+	}
+	return ""
+}
+
+// NewReplayStorage return a storage with empty chain data in memory, group info is read from cs.
+// trxs can be applied to it to rebuild derived tables without touching the chain db
+func (cs *Storage) NewReplayStorage() *Storage {
+	return &Storage{&s.DbMgr{
+		GroupInfoDb: cs.dbmgr.GroupInfoDb,
+		Db:          s.NewMemStore(),
+		DataPath:    cs.dbmgr.DataPath,
+	}}
+}
+
+// GetStateTable return all items of a derived table of the group, by key
+func (cs *Storage) GetStateTable(groupId string, table string, prefix ...string) (map[string][]byte, error) {
+	items := make(map[string][]byte)
+	p := getStatePrefix(groupId, table, prefix...)
+	if p == "" {
+		return nil, fmt.Errorf("unknown state table %s", table)
+	}
+	err := cs.dbmgr.Db.PrefixForeach([]byte(p), func(k []byte, v []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		value := make([]byte, len(v))
+		copy(value, v)
+		items[string(k)] = value
+		return nil
+	})
+	return items, err
+}
+
+// ReplaceStateTable removes all items of a derived table of the group and saves items instead
+func (cs *Storage) ReplaceStateTable(groupId string, table string, items map[string][]byte, prefix ...string) error {
+	p := getStatePrefix(groupId, table, prefix...)
+	if p == "" {
+		return fmt.Errorf("unknown state table %s", table)
+	}
+	if _, err := cs.dbmgr.Db.PrefixDelete([]byte(p)); err != nil {
+		return err
+	}
+
+	keys := [][]byte{}
+	values := [][]byte{}
+	for k, v := range items {
+		keys = append(keys, []byte(k))
+		values = append(values, v)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return cs.dbmgr.Db.BatchWrite(keys, values)
+}
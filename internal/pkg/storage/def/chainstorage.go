@@ -23,4 +23,5 @@ type APIHandlerIface interface {
 	GetAnnounceProducersByGroup(groupId string, prefix ...string) ([]*quorumpb.AnnounceItem, error)
 	GetAnnounceUsersByGroup(groupId string, prefix ...string) ([]*quorumpb.AnnounceItem, error)
 	GetProducers(groupId string, prefix ...string) ([]*quorumpb.ProducerItem, error)
+	GetStakes(groupId string, prefix ...string) ([]*quorumpb.StakeItem, error)
 }
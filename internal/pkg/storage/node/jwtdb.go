@@ -0,0 +1,191 @@
+package node_storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/rumsystem/quorum/internal/pkg/logging"
+)
+
+var nodedb_log = logging.Logger("nodedb")
+
+const (
+	JWTActionCreate  = "create"
+	JWTActionRefresh = "refresh"
+	JWTActionRevoke  = "revoke"
+	JWTActionRemove  = "remove"
+)
+
+type (
+	// NodeDb is the part of storage.QuorumStorage used by node storage, this package is imported by
+	// options and must not depend on internal/pkg/storage
+	NodeDb interface {
+		Set(key []byte, val []byte) error
+		Get(key []byte) ([]byte, error)
+		IsExist([]byte) (bool, error)
+		PrefixForeach(prefix []byte, fn func([]byte, []byte, error) error) error
+		PrefixCondDelete(prefix []byte, fn func(k []byte, v []byte, err error) (bool, error)) (int, error)
+	}
+
+	// Storage keeps node level data which does not belong to any group
+	Storage struct {
+		db NodeDb
+	}
+
+	RevokedJWTItem struct {
+		Jti       string `json:"jti"`
+		Name      string `json:"name"`
+		Role      string `json:"role"`
+		GroupId   string `json:"group_id"`
+		ExpiresAt int64  `json:"expires_at"` // unix seconds
+		RevokedAt int64  `json:"revoked_at"` // unix nano
+	}
+
+	JWTAuditItem struct {
+		Action     string `json:"action" example:"create"`
+		Jti        string `json:"jti" example:"a4ae3a6e-2ebc-4f16-a8a3-5f1f4e1ab6b1"`
+		Name       string `json:"name" example:"allow-513bd3f2-a0bc-470b-8063-ec9549f34b7d"`
+		Role       string `json:"role" example:"node"`
+		GroupId    string `json:"group_id" example:"513bd3f2-a0bc-470b-8063-ec9549f34b7d"`
+		ExpiresAt  int64  `json:"expires_at" example:"1703751036"`       // unix seconds
+		Operator   string `json:"operator" example:"localhost"`          // name of the jwt used by operator, or where the operator is
+		RemoteAddr string `json:"remote_addr" example:"127.0.0.1"`       // empty for command line
+		CreatedAt  int64  `json:"created_at" example:"1680000000000000"` // unix nano
+	}
+
+	// JWTAuditFilter filters audit logs, zero value fields are ignored
+	JWTAuditFilter struct {
+		GroupId   string
+		Name      string
+		Action    string
+		StartTime int64 // unix nano, included
+		EndTime   int64 // unix nano, excluded
+		Num       int   // return the latest num logs
+	}
+)
+
+func NewNodeStorage(db NodeDb) *Storage {
+	return &Storage{db: db}
+}
+
+func (ns *Storage) SaveRevokedJWT(item *RevokedJWTItem) error {
+	value, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return ns.db.Set([]byte(getJWTRevokeKey(item.Jti)), value)
+}
+
+func (ns *Storage) IsJWTRevoked(jti string) (bool, error) {
+	return ns.db.IsExist([]byte(getJWTRevokeKey(jti)))
+}
+
+func (ns *Storage) GetRevokedJWTs() ([]*RevokedJWTItem, error) {
+	items := []*RevokedJWTItem{}
+	err := ns.db.PrefixForeach([]byte(getJWTRevokePrefix()), func(k []byte, v []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		item := &RevokedJWTItem{}
+		if err := json.Unmarshal(v, item); err != nil {
+			return err
+		}
+		items = append(items, item)
+		return nil
+	})
+	return items, err
+}
+
+// PruneRevokedJWT removes revoked tokens which are already expired, they are rejected by expiry anyway
+func (ns *Storage) PruneRevokedJWT(now time.Time) (int, error) {
+	return ns.db.PrefixCondDelete([]byte(getJWTRevokePrefix()), func(k []byte, v []byte, err error) (bool, error) {
+		if err != nil {
+			return false, err
+		}
+		item := &RevokedJWTItem{}
+		if err := json.Unmarshal(v, item); err != nil {
+			nodedb_log.Warnf("unmarshal revoked jwt <%s> failed: %s", k, err)
+			return false, nil
+		}
+		return item.ExpiresAt > 0 && item.ExpiresAt < now.Unix(), nil
+	})
+}
+
+func (ns *Storage) AddJWTAuditLog(item *JWTAuditItem) error {
+	if item.CreatedAt == 0 {
+		item.CreatedAt = time.Now().UnixNano()
+	}
+	value, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return ns.db.Set([]byte(getJWTAuditKey(item.CreatedAt, item.Jti)), value)
+}
+
+// GetJWTAuditLogs returns audit logs matching filter, the latest one comes first
+func (ns *Storage) GetJWTAuditLogs(filter *JWTAuditFilter) ([]*JWTAuditItem, error) {
+	items := []*JWTAuditItem{}
+	err := ns.db.PrefixForeach([]byte(getJWTAuditPrefix()), func(k []byte, v []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		item := &JWTAuditItem{}
+		if err := json.Unmarshal(v, item); err != nil {
+			nodedb_log.Warnf("unmarshal jwt audit log <%s> failed: %s", k, err)
+			return nil
+		}
+
+		if (filter.GroupId != "" && item.GroupId != filter.GroupId) ||
+			(filter.Name != "" && item.Name != filter.Name) ||
+			(filter.Action != "" && item.Action != filter.Action) ||
+			item.CreatedAt < filter.StartTime ||
+			(filter.EndTime > 0 && item.CreatedAt >= filter.EndTime) {
+			return nil
+		}
+
+		items = append(items, item)
+		if filter.Num > 0 && len(items) > filter.Num {
+			items = items[1:]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+		items[i], items[j] = items[j], items[i]
+	}
+	return items, nil
+}
+
+// GetJWTCount returns the count of key in the window starting at window, 0 if it is not counted yet
+func (ns *Storage) GetJWTCount(key string, window time.Time) (int, error) {
+	dbKey := []byte(getJWTCountKey(window.Unix(), key))
+	exist, err := ns.db.IsExist(dbKey)
+	if err != nil || !exist {
+		return 0, err
+	}
+	value, err := ns.db.Get(dbKey)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(value))
+}
+
+func (ns *Storage) SetJWTCount(key string, window time.Time, count int) error {
+	return ns.db.Set([]byte(getJWTCountKey(window.Unix(), key)), []byte(strconv.Itoa(count)))
+}
+
+// PruneJWTCount removes counts of windows other than window
+func (ns *Storage) PruneJWTCount(window time.Time) (int, error) {
+	current := []byte(getJWTCountPrefix(window.Unix()))
+	return ns.db.PrefixCondDelete([]byte(JWT_COUNT_PREFIX+"_"), func(k []byte, v []byte, err error) (bool, error) {
+		if err != nil {
+			return false, err
+		}
+		return !bytes.HasPrefix(k, current), nil
+	})
+}
@@ -0,0 +1,95 @@
+package node_storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rumsystem/quorum/internal/pkg/storage"
+)
+
+func TestPruneRevokedJWT(t *testing.T) {
+	ns := NewNodeStorage(storage.NewMemStore())
+	now := time.Now()
+
+	items := []*RevokedJWTItem{
+		{Jti: "expired", ExpiresAt: now.Add(-time.Hour).Unix()},
+		{Jti: "valid", ExpiresAt: now.Add(time.Hour).Unix()},
+		{Jti: "noexpiry"},
+	}
+	for _, item := range items {
+		if err := ns.SaveRevokedJWT(item); err != nil {
+			t.Fatalf("SaveRevokedJWT failed: %s", err)
+		}
+	}
+
+	n, err := ns.PruneRevokedJWT(now)
+	if err != nil {
+		t.Fatalf("PruneRevokedJWT failed: %s", err)
+	}
+	if n != 1 {
+		t.Fatalf("pruned <%d> revoked jwt, expect 1", n)
+	}
+
+	for jti, expected := range map[string]bool{"expired": false, "valid": true, "noexpiry": true} {
+		revoked, err := ns.IsJWTRevoked(jti)
+		if err != nil {
+			t.Fatalf("IsJWTRevoked failed: %s", err)
+		}
+		if revoked != expected {
+			t.Fatalf("jwt <%s> revoked <%v>, expect <%v>", jti, revoked, expected)
+		}
+	}
+
+	revoked, err := ns.GetRevokedJWTs()
+	if err != nil {
+		t.Fatalf("GetRevokedJWTs failed: %s", err)
+	}
+	if len(revoked) != 2 {
+		t.Fatalf("got <%d> revoked jwt, expect 2", len(revoked))
+	}
+}
+
+func TestGetJWTAuditLogs(t *testing.T) {
+	ns := NewNodeStorage(storage.NewMemStore())
+
+	logs := []*JWTAuditItem{
+		{Action: JWTActionCreate, Jti: "a", Name: "alice", GroupId: "group1", CreatedAt: 1},
+		{Action: JWTActionCreate, Jti: "b", Name: "bob", GroupId: "group2", CreatedAt: 2},
+		{Action: JWTActionRevoke, Jti: "a", Name: "alice", GroupId: "group1", CreatedAt: 3},
+		{Action: JWTActionRefresh, Jti: "c", Name: "alice", GroupId: "group1", CreatedAt: 4},
+	}
+	for _, item := range logs {
+		if err := ns.AddJWTAuditLog(item); err != nil {
+			t.Fatalf("AddJWTAuditLog failed: %s", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		filter   JWTAuditFilter
+		expected []int64
+	}{
+		{"all, latest first", JWTAuditFilter{}, []int64{4, 3, 2, 1}},
+		{"group", JWTAuditFilter{GroupId: "group1"}, []int64{4, 3, 1}},
+		{"name and action", JWTAuditFilter{Name: "alice", Action: JWTActionCreate}, []int64{1}},
+		{"time range", JWTAuditFilter{StartTime: 2, EndTime: 4}, []int64{3, 2}},
+		{"latest num", JWTAuditFilter{Num: 2}, []int64{4, 3}},
+	} {
+		items, err := ns.GetJWTAuditLogs(&tc.filter)
+		if err != nil {
+			t.Fatalf("%s: GetJWTAuditLogs failed: %s", tc.name, err)
+		}
+		created := []int64{}
+		for _, item := range items {
+			created = append(created, item.CreatedAt)
+		}
+		if len(created) != len(tc.expected) {
+			t.Fatalf("%s: got logs created at <%v>, expect <%v>", tc.name, created, tc.expected)
+		}
+		for i := range created {
+			if created[i] != tc.expected[i] {
+				t.Fatalf("%s: got logs created at <%v>, expect <%v>", tc.name, created, tc.expected)
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package node_storage
+
+import "fmt"
+
+const (
+	JWT_REVOKE_PREFIX = "jwt_rvk" //revoked jwt
+	JWT_AUDIT_PREFIX  = "jwt_log" //audit log of jwt
+	JWT_COUNT_PREFIX  = "jwt_cnt" //requests counted for jwt limits
+)
+
+func getJWTRevokePrefix() string {
+	return JWT_REVOKE_PREFIX + "_"
+}
+
+func getJWTRevokeKey(jti string) string {
+	return getJWTRevokePrefix() + jti
+}
+
+func getJWTAuditPrefix() string {
+	return JWT_AUDIT_PREFIX + "_"
+}
+
+// getJWTAuditKey pads timestamp so audit logs are sorted by time
+func getJWTAuditKey(timestamp int64, jti string) string {
+	return fmt.Sprintf("%s%020d_%s", getJWTAuditPrefix(), timestamp, jti)
+}
+
+func getJWTCountPrefix(window int64) string {
+	return fmt.Sprintf("%s_%d_", JWT_COUNT_PREFIX, window)
+}
+
+func getJWTCountKey(window int64, key string) string {
+	return getJWTCountPrefix(window) + key
+}
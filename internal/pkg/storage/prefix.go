@@ -25,7 +25,14 @@ const (
 	TRX_AUTH_TYPE_PREFIX = "trx_auth"  //trx auth type
 	ALLW_LIST_PREFIX     = "alw_list"  //allow list
 	DENY_LIST_PREFIX     = "dny_list"  //deny list
+	MEMPOOL_PREFIX       = "mempool"   //mempool policy
+	PACING_PREFIX        = "pacing"    //epoch pacing
 	PRD_TRX_ID_PREFIX    = "prd_trxid" //trxid of latest trx which update group producer list
+	STK_PREFIX           = "stk"       //producer stake (pos)
+	SNP_PREFIX           = "snp"       //latest snapshot
+	TRX_BLK_PREFIX       = "trxblk"    //id of the block which contains the trx
+	RTN_PREFIX           = "rtn"       //block retention policy
+	PRN_PREFIX           = "prn"       //pruned block range
 
 	// groupinfo db
 	GROUPITEM_PREFIX = "grpitem"
@@ -35,6 +42,10 @@ const (
 	// consensus db
 	CNS_BUFD_TRX = "cns_bf_trx" //buffered trx (used by acs)
 	CNS_BUFD_MSG = "cns_bf_msg" //buffered message (used by bba & rbc)
+	CNS_TPK      = "cns_tpk"    //threshold key share of producer (used by dkg)
+
+	// pubqueue db
+	PUBQUEUE_PREFIX = "pubqueue" //outgoing trx waiting to be packaged
 )
 
 func _getEthPubkey(libp2pPubkey string) string {
@@ -119,6 +130,17 @@ func GetProducerKey(groupId string, pk string, prefix ...string) string {
 	return _prefix + pk
 }
 
+func GetStakePrefix(groupId string, prefix ...string) string {
+	nodeprefix := utils.GetPrefix(prefix...)
+	return nodeprefix + STK_PREFIX + "_" + groupId + "_"
+}
+
+func GetStakeKey(groupId string, pubkey string, prefix ...string) string {
+	_prefix := GetStakePrefix(groupId, prefix...)
+	pk := _getEthPubkey(pubkey)
+	return _prefix + pk
+}
+
 func GetUserPrefix(groupId string, prefix ...string) string {
 	nodeprefix := utils.GetPrefix(prefix...)
 	return nodeprefix + USR_PREFIX + "_" + groupId + "_"
@@ -198,6 +220,16 @@ func GetChainConfigDenyPrefix(groupId string, prefix ...string) string {
 	return _prefix + "_" + DENY_LIST_PREFIX
 }
 
+func GetChainConfigMempoolKey(groupId string, prefix ...string) string {
+	_prefix := GetChainConfigPrefix(groupId, prefix...)
+	return _prefix + "_" + MEMPOOL_PREFIX
+}
+
+func GetChainConfigPacingKey(groupId string, prefix ...string) string {
+	_prefix := GetChainConfigPrefix(groupId, prefix...)
+	return _prefix + "_" + PACING_PREFIX
+}
+
 func GetAppConfigPrefix(groupId string, prefix ...string) string {
 	nodeprefix := utils.GetPrefix(prefix...)
 	return nodeprefix + APP_CONFIG_PREFIX + "_" + groupId
@@ -212,6 +244,23 @@ func GetProducerTrxIDKey(groupId string, prefix ...string) string {
 	nodeprefix := utils.GetPrefix(prefix...)
 	return nodeprefix + PRD_TRX_ID_PREFIX + "_" + groupId
 }
+func GetSnapshotKey(groupId string, prefix ...string) string {
+	nodeprefix := utils.GetPrefix(prefix...)
+	return nodeprefix + SNP_PREFIX + "_" + groupId
+}
+
+// GetSnapshotStatePrefixes return prefixes of all derived group state which should be included in a snapshot
+func GetSnapshotStatePrefixes(groupId string, prefix ...string) []string {
+	return []string{
+		GetProducerPrefix(groupId, prefix...),
+		GetUserPrefix(groupId, prefix...),
+		GetAnnouncedPrefix(groupId, prefix...),
+		GetAppConfigPrefix(groupId, prefix...) + "_",
+		GetChainConfigPrefix(groupId, prefix...) + "_",
+		GetStakePrefix(groupId, prefix...),
+	}
+}
+
 func GetTrxPrefix(groupId string, prefix ...string) string {
 	nodeprefix := utils.GetPrefix(prefix...)
 	key := nodeprefix + TRX_PREFIX + "_" + groupId + "_"
@@ -227,6 +276,25 @@ func GetTrxKey(groupId, trxId string, prefix ...string) string {
 	return key
 }
 
+func GetTrxBlockPrefix(groupId string, prefix ...string) string {
+	nodeprefix := utils.GetPrefix(prefix...)
+	return nodeprefix + TRX_BLK_PREFIX + "_" + groupId + "_"
+}
+
+func GetTrxBlockKey(groupId, trxId string, prefix ...string) string {
+	return GetTrxBlockPrefix(groupId, prefix...) + trxId
+}
+
+func GetRetentionKey(groupId string, prefix ...string) string {
+	nodeprefix := utils.GetPrefix(prefix...)
+	return nodeprefix + RTN_PREFIX + "_" + groupId
+}
+
+func GetPruneStateKey(groupId string, prefix ...string) string {
+	nodeprefix := utils.GetPrefix(prefix...)
+	return nodeprefix + PRN_PREFIX + "_" + groupId
+}
+
 func GetSeedKey(groupID string) []byte {
 	return []byte(fmt.Sprintf("%s_%s", GROUPSEED_PREFIX, groupID))
 }
@@ -240,6 +308,11 @@ func GetTrxHBBKey(queueId string, trxId string) string {
 	return prefix + trxId
 }
 
+func GetThresholdKeyKey(groupId string, prefix ...string) string {
+	nodeprefix := utils.GetPrefix(prefix...)
+	return nodeprefix + CNS_TPK + "_" + groupId
+}
+
 // Relay
 func GetRelayPrefix() string {
 	return RELAY_PREFIX
@@ -264,3 +337,16 @@ func GetRelayActivityKey(groupId, _type string) string {
 func GetRelayApprovedKey(groupId, _type string) string {
 	return GetRelayPrefix() + "_approved_" + groupId + "_" + _type
 }
+
+// Pubqueue
+func GetPubQueuePrefix(groupId string) string {
+	key := PUBQUEUE_PREFIX + "_"
+	if groupId != "" {
+		key = key + groupId + "_"
+	}
+	return key
+}
+
+func GetPubQueueKey(groupId string, trxId string) string {
+	return GetPubQueuePrefix(groupId) + trxId
+}
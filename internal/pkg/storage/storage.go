@@ -1,5 +1,14 @@
 package storage
 
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
 type QuorumStorage interface {
 	Init(path string) error
 	Close() error
@@ -22,3 +31,149 @@ type Sequence interface {
 	Next() (uint64, error)
 	Release() error
 }
+
+// storage engines
+const (
+	ENGINE_BOLT   = "bolt"   // b+tree, the default engine
+	ENGINE_BADGER = "badger" // lsm tree, less write amplification for write heavy nodes like producers
+	ENGINE_MEMORY = "memory" // nothing is written to disk, for tests and ephemeral nodes
+)
+
+// Engine opens the bucket in dir as a QuorumStorage
+type Engine func(ctx context.Context, dir string, bucket string) (QuorumStorage, error)
+
+var (
+	enginesMu     sync.RWMutex
+	engines       = map[string]Engine{}
+	defaultEngine = ENGINE_BOLT
+)
+
+// RegisterEngine adds an engine which can be selected by name, a registered engine is replaced
+func RegisterEngine(name string, engine Engine) {
+	enginesMu.Lock()
+	defer enginesMu.Unlock()
+	engines[name] = engine
+}
+
+// GetEngines returns names of registered engines
+func GetEngines() []string {
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+	names := []string{}
+	for name := range engines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetDefaultEngine selects the engine used by OpenStore and CreateDb, empty name for bolt
+func SetDefaultEngine(name string) error {
+	if name == "" {
+		name = ENGINE_BOLT
+	}
+	enginesMu.Lock()
+	defer enginesMu.Unlock()
+	if _, ok := engines[name]; !ok {
+		return fmt.Errorf("unknown storage engine: %s", name)
+	}
+	defaultEngine = name
+	return nil
+}
+
+func GetDefaultEngine() string {
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+	return defaultEngine
+}
+
+// OpenStore opens the bucket in dir with the default engine
+func OpenStore(ctx context.Context, dir string, bucket string) (QuorumStorage, error) {
+	return OpenStoreWithEngine(ctx, GetDefaultEngine(), dir, bucket)
+}
+
+func OpenStoreWithEngine(ctx context.Context, name string, dir string, bucket string) (QuorumStorage, error) {
+	enginesMu.RLock()
+	engine, ok := engines[name]
+	enginesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown storage engine: %s", name)
+	}
+	return engine(ctx, dir, bucket)
+}
+
+// kvSequence implements Sequence on any QuorumStorage, the lease is saved at key as a big endian uint64,
+// mu should be shared by all sequences of the store
+type kvSequence struct {
+	mu        *sync.Mutex
+	store     QuorumStorage
+	key       []byte
+	next      uint64
+	leased    uint64
+	bandwidth uint64
+}
+
+func newKVSequence(mu *sync.Mutex, store QuorumStorage, key []byte, bandwidth uint64) (Sequence, error) {
+	if len(key) == 0 {
+		return nil, errors.New("empty key")
+	}
+	if bandwidth == 0 {
+		return nil, errors.New("zero bandwidth")
+	}
+
+	seq := &kvSequence{mu: mu, store: store, key: key, bandwidth: bandwidth}
+	mu.Lock()
+	defer mu.Unlock()
+	return seq, seq.updateLease()
+}
+
+func (seq *kvSequence) Next() (uint64, error) {
+	seq.mu.Lock()
+	defer seq.mu.Unlock()
+	if seq.next >= seq.leased {
+		if err := seq.updateLease(); err != nil {
+			return 0, err
+		}
+	}
+	val := seq.next
+	seq.next++
+	return val, nil
+}
+
+func (seq *kvSequence) Release() error {
+	seq.mu.Lock()
+	defer seq.mu.Unlock()
+	val, err := seq.store.Get(seq.key)
+	if err != nil {
+		return err
+	}
+	if len(val) == 8 && binary.BigEndian.Uint64(val) == seq.leased {
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], seq.next)
+		if err := seq.store.Set(seq.key, buf[:]); err != nil {
+			return err
+		}
+	}
+	seq.leased = seq.next
+	return nil
+}
+
+func (seq *kvSequence) updateLease() error {
+	val, err := seq.store.Get(seq.key)
+	if err != nil {
+		return err
+	}
+	seq.next = 0
+	if len(val) == 8 {
+		seq.next = binary.BigEndian.Uint64(val)
+	}
+
+	lease := seq.next + seq.bandwidth
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], lease)
+	if err := seq.store.Set(seq.key, buf[:]); err != nil {
+		return err
+	}
+	seq.leased = lease
+	return nil
+}
@@ -0,0 +1,262 @@
+//go:build !js
+// +build !js
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v3"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	"github.com/rumsystem/quorum/internal/pkg/utils"
+)
+
+var BADGER_GC_INTERVAL = 10 * time.Minute // interval to run value log gc
+var BADGER_GC_DISCARD_RATIO = 0.5
+
+func init() {
+	RegisterEngine(ENGINE_BADGER, func(ctx context.Context, dir string, bucket string) (QuorumStorage, error) {
+		return NewBadgerStore(ctx, dir, bucket)
+	})
+}
+
+// BadgerStore is a QuorumStorage on badger, an lsm tree engine which writes sequentially,
+// so it has much less write amplification than bolt for nodes producing blocks
+type BadgerStore struct {
+	db           *badger.DB
+	databasePath string
+	ctx          context.Context
+	cancel       context.CancelFunc
+}
+
+func NewBadgerStore(ctx context.Context, dir string, bucket string) (*BadgerStore, error) {
+	if err := utils.EnsureDir(dir); err != nil {
+		dbmgr_log.Errorf("check or create directory failed: %s", err)
+		return nil, err
+	}
+
+	path := filepath.Join(dir, bucket+"_badger")
+	db, err := badger.Open(badger.DefaultOptions(path).WithLogger(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &BadgerStore{db: db, databasePath: path, ctx: ctx, cancel: cancel}
+	go s.runGC()
+	return s, nil
+}
+
+func (s *BadgerStore) runGC() {
+	ticker := time.NewTicker(BADGER_GC_INTERVAL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			// rewrite value log files until there is nothing to collect
+			for s.db.RunValueLogGC(BADGER_GC_DISCARD_RATIO) == nil {
+			}
+		}
+	}
+}
+
+func (s *BadgerStore) Init(path string) error {
+	return nil
+}
+
+func (s *BadgerStore) Close() error {
+	s.cancel()
+	return s.db.Close()
+}
+
+// DatabasePath at which this database writes files.
+func (s *BadgerStore) DatabasePath() string {
+	return s.databasePath
+}
+
+func (s *BadgerStore) Set(key []byte, val []byte) error {
+	if len(key) == 0 {
+		return rumerrors.ErrEmptyKey
+	}
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(key, val)
+	})
+}
+
+func (s *BadgerStore) Delete(key []byte) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(key)
+	})
+}
+
+// Get returns a nil value if the key does not exist
+func (s *BadgerStore) Get(key []byte) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, rumerrors.ErrEmptyKey
+	}
+
+	var val []byte
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(key)
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+		val, err = item.ValueCopy(nil)
+		if val == nil {
+			// empty value of an existing key
+			val = []byte{}
+		}
+		return err
+	})
+	if err != nil {
+		dbmgr_log.Warnf("kvdb Get %s failed: %s", key, err)
+		return nil, err
+	}
+	return val, nil
+}
+
+func (s *BadgerStore) IsExist(key []byte) (bool, error) {
+	val, err := s.Get(key)
+	return val != nil, err
+}
+
+// deleteKeys deletes keys in batches, so it is not limited by the size of a transaction
+func (s *BadgerStore) deleteKeys(keys [][]byte) error {
+	wb := s.db.NewWriteBatch()
+	defer wb.Cancel()
+	for _, k := range keys {
+		if err := wb.Delete(k); err != nil {
+			return err
+		}
+	}
+	return wb.Flush()
+}
+
+func (s *BadgerStore) PrefixDelete(prefix []byte) (int, error) {
+	dbmgr_log.Debugf("delete key by prefix: %s", prefix)
+
+	keys := [][]byte{}
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			keys = append(keys, it.Item().KeyCopy(nil))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(keys), s.deleteKeys(keys)
+}
+
+func (s *BadgerStore) PrefixCondDelete(prefix []byte, fn func(k []byte, v []byte, err error) (bool, error)) (int, error) {
+	keys := [][]byte{}
+	err := s.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			item := it.Item()
+			v, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			ok, err := fn(item.KeyCopy(nil), v, nil)
+			if err != nil {
+				return err
+			}
+			if ok {
+				keys = append(keys, item.KeyCopy(nil))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(keys), s.deleteKeys(keys)
+}
+
+// PrefixForeachKey iterates keys from prefix while they start with valid, or all keys with valid in reverse order
+func (s *BadgerStore) PrefixForeachKey(prefix []byte, valid []byte, reverse bool, fn func([]byte, error) error) (int, error) {
+	matched := 0
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		opts.Reverse = reverse
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		seek := prefix
+		if reverse {
+			// the largest key with valid
+			seek = append(append([]byte{}, valid...), bytes.Repeat([]byte{0xff}, 16)...)
+		}
+		for it.Seek(seek); it.ValidForPrefix(valid); it.Next() {
+			if err := fn(it.Item().KeyCopy(nil), nil); err != nil {
+				return err
+			}
+			matched += 1
+		}
+		return nil
+	})
+	return matched, err
+}
+
+func (s *BadgerStore) PrefixForeach(prefix []byte, fn func([]byte, []byte, error) error) error {
+	return s.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			item := it.Item()
+			v, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			if err := fn(item.KeyCopy(nil), v, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *BadgerStore) Foreach(fn func(k []byte, v []byte, err error) error) error {
+	return s.PrefixForeach(nil, fn)
+}
+
+func (s *BadgerStore) BatchWrite(keys [][]byte, vals [][]byte) error {
+	if len(keys) != len(vals) {
+		return errors.New("keys' and values' length should be equal")
+	}
+
+	return s.db.Update(func(txn *badger.Txn) error {
+		for i, k := range keys {
+			if err := txn.Set(k, vals[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *BadgerStore) GetSequence(key []byte, bandwidth uint64) (Sequence, error) {
+	if len(key) == 0 {
+		return nil, errors.New("empty key")
+	}
+	if bandwidth == 0 {
+		return nil, errors.New("zero bandwidth")
+	}
+	return s.db.GetSequence(key, bandwidth)
+}
@@ -0,0 +1,219 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+)
+
+var (
+	memStoresMu sync.Mutex
+	memStores   = map[string]*MemStore{} // opened memory stores by path, so a reopened store keeps its data
+)
+
+func init() {
+	RegisterEngine(ENGINE_MEMORY, func(ctx context.Context, dir string, bucket string) (QuorumStorage, error) {
+		path := filepath.Join(dir, bucket)
+		memStoresMu.Lock()
+		defer memStoresMu.Unlock()
+		s, ok := memStores[path]
+		if !ok {
+			s = NewMemStore()
+			memStores[path] = s
+		}
+		return s, nil
+	})
+}
+
+// MemStore is an in-memory QuorumStorage, keys are kept sorted so prefix iteration is in the same order as bolt.
+// Iterations run on a snapshot of the matched items, so fn can read or write the store
+type MemStore struct {
+	mu    sync.RWMutex
+	keys  []string // sorted
+	data  map[string][]byte
+	seqMu sync.Mutex
+}
+
+func NewMemStore() *MemStore {
+	return &MemStore{data: make(map[string][]byte)}
+}
+
+func (s *MemStore) Init(path string) error {
+	return nil
+}
+
+// Close keeps the data, it is dropped when the process exits
+func (s *MemStore) Close() error {
+	return nil
+}
+
+func (s *MemStore) Set(key []byte, val []byte) error {
+	if len(key) == 0 {
+		return rumerrors.ErrEmptyKey
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set(string(key), val)
+	return nil
+}
+
+func (s *MemStore) set(k string, val []byte) {
+	if _, ok := s.data[k]; !ok {
+		i := sort.SearchStrings(s.keys, k)
+		s.keys = append(s.keys, "")
+		copy(s.keys[i+1:], s.keys[i:])
+		s.keys[i] = k
+	}
+	s.data[k] = append([]byte{}, val...)
+}
+
+func (s *MemStore) Delete(key []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delete(string(key))
+	return nil
+}
+
+func (s *MemStore) delete(k string) {
+	if _, ok := s.data[k]; !ok {
+		return
+	}
+	delete(s.data, k)
+	i := sort.SearchStrings(s.keys, k)
+	s.keys = append(s.keys[:i], s.keys[i+1:]...)
+}
+
+func (s *MemStore) Get(key []byte) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, rumerrors.ErrEmptyKey
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	val, ok := s.data[string(key)]
+	if !ok {
+		return nil, nil
+	}
+	return append([]byte{}, val...), nil
+}
+
+func (s *MemStore) IsExist(key []byte) (bool, error) {
+	val, err := s.Get(key)
+	return val != nil, err
+}
+
+// items returns a copy of the items with prefix in key order
+func (s *MemStore) items(prefix []byte) ([][]byte, [][]byte) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := [][]byte{}
+	vals := [][]byte{}
+	for i := sort.SearchStrings(s.keys, string(prefix)); i < len(s.keys); i++ {
+		k := s.keys[i]
+		if !bytes.HasPrefix([]byte(k), prefix) {
+			break
+		}
+		keys = append(keys, []byte(k))
+		vals = append(vals, append([]byte{}, s.data[k]...))
+	}
+	return keys, vals
+}
+
+func (s *MemStore) PrefixDelete(prefix []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	start := sort.SearchStrings(s.keys, string(prefix))
+	end := start
+	for end < len(s.keys) && bytes.HasPrefix([]byte(s.keys[end]), prefix) {
+		delete(s.data, s.keys[end])
+		end++
+	}
+	s.keys = append(s.keys[:start], s.keys[end:]...)
+	return end - start, nil
+}
+
+func (s *MemStore) PrefixCondDelete(prefix []byte, fn func(k []byte, v []byte, err error) (bool, error)) (int, error) {
+	keys, vals := s.items(prefix)
+	matched := 0
+	for i, k := range keys {
+		ok, err := fn(k, vals[i], nil)
+		if err != nil {
+			return matched, err
+		}
+		if ok {
+			s.Delete(k)
+			matched += 1
+		}
+	}
+	return matched, nil
+}
+
+// PrefixForeachKey iterates keys from prefix while they start with valid, or all keys with valid in reverse order
+func (s *MemStore) PrefixForeachKey(prefix []byte, valid []byte, reverse bool, fn func([]byte, error) error) (int, error) {
+	keys := [][]byte{}
+	s.mu.RLock()
+	if reverse {
+		for i := len(s.keys) - 1; i >= 0; i-- {
+			if bytes.HasPrefix([]byte(s.keys[i]), valid) {
+				keys = append(keys, []byte(s.keys[i]))
+			}
+		}
+	} else {
+		for i := sort.SearchStrings(s.keys, string(prefix)); i < len(s.keys); i++ {
+			if !bytes.HasPrefix([]byte(s.keys[i]), valid) {
+				break
+			}
+			keys = append(keys, []byte(s.keys[i]))
+		}
+	}
+	s.mu.RUnlock()
+
+	matched := 0
+	for _, k := range keys {
+		if err := fn(k, nil); err != nil {
+			return matched, err
+		}
+		matched += 1
+	}
+	return matched, nil
+}
+
+func (s *MemStore) PrefixForeach(prefix []byte, fn func([]byte, []byte, error) error) error {
+	keys, vals := s.items(prefix)
+	for i, k := range keys {
+		if err := fn(k, vals[i], nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *MemStore) Foreach(fn func(k []byte, v []byte, err error) error) error {
+	return s.PrefixForeach(nil, fn)
+}
+
+func (s *MemStore) BatchWrite(keys [][]byte, vals [][]byte) error {
+	if len(keys) != len(vals) {
+		return errors.New("keys' and values' length should be equal")
+	}
+	for _, k := range keys {
+		if len(k) == 0 {
+			return rumerrors.ErrEmptyKey
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, k := range keys {
+		s.set(string(k), vals[i])
+	}
+	return nil
+}
+
+func (s *MemStore) GetSequence(key []byte, bandwidth uint64) (Sequence, error) {
+	return newKVSequence(&s.seqMu, s, key, bandwidth)
+}
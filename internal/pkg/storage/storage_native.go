@@ -32,6 +32,12 @@ type Store struct {
 	ctx          context.Context
 }
 
+func init() {
+	RegisterEngine(ENGINE_BOLT, func(ctx context.Context, dir string, bucket string) (QuorumStorage, error) {
+		return NewStore(ctx, dir, bucket)
+	})
+}
+
 func getDBPath(dir string, bucket string) string {
 	return filepath.Join(dir, bucket+".db")
 }
@@ -314,14 +320,16 @@ func (s *Store) GetSequence(key []byte, bandwidth uint64) (Sequence, error) {
 	return seq, err
 }
 
+// CreateDb opens group and data db in path with the default engine
 func CreateDb(path string) (*DbMgr, error) {
 	ctx := context.Background()
-	groupDb, err := NewStore(ctx, path, "groups")
+	groupDb, err := OpenStore(ctx, path, "groups")
 	if err != nil {
 		return nil, err
 	}
-	dataDb, err := NewStore(ctx, path, "db")
+	dataDb, err := OpenStore(ctx, path, "db")
 	if err != nil {
+		groupDb.Close()
 		return nil, err
 	}
 
@@ -0,0 +1,323 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+)
+
+// conformance tests, every registered engine should pass them
+
+func TestEngines(t *testing.T) {
+	engines := GetEngines()
+	for _, name := range []string{ENGINE_BOLT, ENGINE_BADGER, ENGINE_MEMORY} {
+		found := false
+		for _, e := range engines {
+			found = found || e == name
+		}
+		if !found {
+			t.Errorf("engine %s is not registered", name)
+		}
+	}
+
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s QuorumStorage)
+	}{
+		{"SetGet", testSetGet},
+		{"Delete", testDelete},
+		{"PrefixForeach", testPrefixForeach},
+		{"PrefixForeachKey", testPrefixForeachKey},
+		{"PrefixDelete", testPrefixDelete},
+		{"PrefixCondDelete", testPrefixCondDelete},
+		{"BatchWrite", testBatchWrite},
+		{"Sequence", testSequence},
+	}
+
+	for _, engine := range engines {
+		for _, tt := range tests {
+			t.Run(engine+"/"+tt.name, func(t *testing.T) {
+				s, err := OpenStoreWithEngine(context.Background(), engine, t.TempDir(), "test")
+				if err != nil {
+					t.Fatalf("open store failed: %s", err)
+				}
+				defer s.Close()
+				tt.fn(t, s)
+			})
+		}
+		t.Run(engine+"/Reopen", func(t *testing.T) {
+			testReopen(t, engine)
+		})
+	}
+}
+
+func mustSet(t *testing.T, s QuorumStorage, keys ...string) {
+	for _, k := range keys {
+		if err := s.Set([]byte(k), []byte("v_"+k)); err != nil {
+			t.Fatalf("set %s failed: %s", k, err)
+		}
+	}
+}
+
+func collectKeys(t *testing.T, s QuorumStorage, prefix string) []string {
+	keys := []string{}
+	err := s.PrefixForeach([]byte(prefix), func(k []byte, v []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(v, []byte("v_"+string(k))) {
+			t.Errorf("value of %s is %s", k, v)
+		}
+		keys = append(keys, string(k))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("PrefixForeach failed: %s", err)
+	}
+	return keys
+}
+
+func expectKeys(t *testing.T, got []string, expect ...string) {
+	if fmt.Sprint(got) != fmt.Sprint(expect) {
+		t.Errorf("keys %v, expect %v", got, expect)
+	}
+}
+
+func testSetGet(t *testing.T, s QuorumStorage) {
+	mustSet(t, s, "a")
+	val, err := s.Get([]byte("a"))
+	if err != nil || string(val) != "v_a" {
+		t.Errorf("Get a: %s, %v", val, err)
+	}
+
+	val, err = s.Get([]byte("missing"))
+	if err != nil || val != nil {
+		t.Errorf("Get missing key should return nil, got %v, %v", val, err)
+	}
+	if _, err := s.Get(nil); err == nil {
+		t.Errorf("Get empty key should fail")
+	}
+
+	if err := s.Set([]byte("empty"), []byte{}); err != nil {
+		t.Fatalf("set empty value failed: %s", err)
+	}
+	ok, err := s.IsExist([]byte("empty"))
+	if err != nil || !ok {
+		t.Errorf("key with empty value should exist, got %v, %v", ok, err)
+	}
+	ok, _ = s.IsExist([]byte("missing"))
+	if ok {
+		t.Errorf("missing key should not exist")
+	}
+
+	if err := s.Set([]byte("a"), []byte("new")); err != nil {
+		t.Fatalf("overwrite failed: %s", err)
+	}
+	val, _ = s.Get([]byte("a"))
+	if string(val) != "new" {
+		t.Errorf("Get a after overwrite: %s", val)
+	}
+}
+
+func testDelete(t *testing.T, s QuorumStorage) {
+	mustSet(t, s, "a", "b")
+	if err := s.Delete([]byte("a")); err != nil {
+		t.Fatalf("Delete failed: %s", err)
+	}
+	if err := s.Delete([]byte("missing")); err != nil {
+		t.Errorf("Delete missing key should not fail: %s", err)
+	}
+	ok, _ := s.IsExist([]byte("a"))
+	if ok {
+		t.Errorf("deleted key exists")
+	}
+	expectKeys(t, collectKeys(t, s, ""), "b")
+}
+
+func testPrefixForeach(t *testing.T, s QuorumStorage) {
+	mustSet(t, s, "p_2", "p_10", "p_1", "q_1", "o_1", "p")
+	expectKeys(t, collectKeys(t, s, "p_"), "p_1", "p_10", "p_2")
+	expectKeys(t, collectKeys(t, s, "x"))
+
+	all := []string{}
+	err := s.Foreach(func(k []byte, v []byte, err error) error {
+		all = append(all, string(k))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Foreach failed: %s", err)
+	}
+	expectKeys(t, all, "o_1", "p", "p_1", "p_10", "p_2", "q_1")
+
+	// fn can read the store and stop the iteration by an error
+	stop := fmt.Errorf("stop")
+	n := 0
+	err = s.PrefixForeach([]byte("p_"), func(k []byte, v []byte, err error) error {
+		n++
+		if val, err := s.Get(k); err != nil || !bytes.Equal(val, v) {
+			t.Errorf("Get %s in PrefixForeach: %s, %v", k, val, err)
+		}
+		return stop
+	})
+	if err != stop || n != 1 {
+		t.Errorf("PrefixForeach should stop at the first error, got %v after %d", err, n)
+	}
+}
+
+func testPrefixForeachKey(t *testing.T, s QuorumStorage) {
+	mustSet(t, s, "k_a_1", "k_a_2", "k_a_3", "k_b_1", "k_b_2", "j_1", "l_1")
+
+	keys := []string{}
+	fn := func(k []byte, err error) error {
+		keys = append(keys, string(k))
+		return err
+	}
+
+	// from prefix while keys start with valid
+	n, err := s.PrefixForeachKey([]byte("k_a_2"), []byte("k_a_"), false, fn)
+	if err != nil || n != 2 {
+		t.Errorf("PrefixForeachKey matched %d, %v", n, err)
+	}
+	expectKeys(t, keys, "k_a_2", "k_a_3")
+
+	keys = []string{}
+	n, err = s.PrefixForeachKey([]byte("k_"), []byte("k_"), true, fn)
+	if err != nil || n != 5 {
+		t.Errorf("reverse PrefixForeachKey matched %d, %v", n, err)
+	}
+	expectKeys(t, keys, "k_b_2", "k_b_1", "k_a_3", "k_a_2", "k_a_1")
+
+	keys = []string{}
+	n, _ = s.PrefixForeachKey([]byte("k_b_"), []byte("k_b_"), true, fn)
+	if n != 2 {
+		t.Errorf("reverse PrefixForeachKey matched %d", n)
+	}
+	expectKeys(t, keys, "k_b_2", "k_b_1")
+}
+
+func testPrefixDelete(t *testing.T, s QuorumStorage) {
+	mustSet(t, s, "d_1", "d_2", "d_3", "e_1")
+	n, err := s.PrefixDelete([]byte("d_"))
+	if err != nil || n != 3 {
+		t.Errorf("PrefixDelete deleted %d, %v", n, err)
+	}
+	expectKeys(t, collectKeys(t, s, ""), "e_1")
+
+	n, err = s.PrefixDelete([]byte("x"))
+	if err != nil || n != 0 {
+		t.Errorf("PrefixDelete of no key deleted %d, %v", n, err)
+	}
+}
+
+func testPrefixCondDelete(t *testing.T, s QuorumStorage) {
+	mustSet(t, s, "c_1", "c_2", "c_3", "c_4", "d_1")
+	n, err := s.PrefixCondDelete([]byte("c_"), func(k []byte, v []byte, err error) (bool, error) {
+		if !bytes.Equal(v, []byte("v_"+string(k))) {
+			t.Errorf("value of %s is %s", k, v)
+		}
+		return k[2]%2 == 0, err
+	})
+	if err != nil || n != 2 {
+		t.Errorf("PrefixCondDelete deleted %d, %v", n, err)
+	}
+	expectKeys(t, collectKeys(t, s, ""), "c_1", "c_3", "d_1")
+}
+
+func testBatchWrite(t *testing.T, s QuorumStorage) {
+	keys := [][]byte{}
+	vals := [][]byte{}
+	for i := 0; i < 1000; i++ {
+		k := fmt.Sprintf("b_%04d", i)
+		keys = append(keys, []byte(k))
+		vals = append(vals, []byte("v_"+k))
+	}
+	if err := s.BatchWrite(keys, vals); err != nil {
+		t.Fatalf("BatchWrite failed: %s", err)
+	}
+	if got := collectKeys(t, s, "b_"); len(got) != 1000 {
+		t.Errorf("BatchWrite saved %d keys", len(got))
+	}
+
+	if err := s.BatchWrite(keys[:2], vals[:1]); err == nil {
+		t.Errorf("BatchWrite with different length of keys and values should fail")
+	}
+}
+
+func testSequence(t *testing.T, s QuorumStorage) {
+	if _, err := s.GetSequence([]byte("seq"), 0); err == nil {
+		t.Errorf("sequence with zero bandwidth should fail")
+	}
+
+	seq, err := s.GetSequence([]byte("seq"), 10)
+	if err != nil {
+		t.Fatalf("GetSequence failed: %s", err)
+	}
+	for i := uint64(0); i < 25; i++ {
+		n, err := seq.Next()
+		if err != nil || n != i {
+			t.Fatalf("Next returned %d, %v, expect %d", n, err, i)
+		}
+	}
+	if err := seq.Release(); err != nil {
+		t.Fatalf("Release failed: %s", err)
+	}
+
+	// a new sequence continues from the released one
+	seq, err = s.GetSequence([]byte("seq"), 10)
+	if err != nil {
+		t.Fatalf("GetSequence failed: %s", err)
+	}
+	n, err := seq.Next()
+	if err != nil || n != 25 {
+		t.Errorf("Next after release returned %d, %v, expect 25", n, err)
+	}
+	seq.Release()
+}
+
+func testReopen(t *testing.T, engine string) {
+	dir := t.TempDir()
+	s, err := OpenStoreWithEngine(context.Background(), engine, dir, "test")
+	if err != nil {
+		t.Fatalf("open store failed: %s", err)
+	}
+	mustSet(t, s, "r_1", "r_2")
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close failed: %s", err)
+	}
+
+	s, err = OpenStoreWithEngine(context.Background(), engine, dir, "test")
+	if err != nil {
+		t.Fatalf("reopen store failed: %s", err)
+	}
+	defer s.Close()
+	expectKeys(t, collectKeys(t, s, "r_"), "r_1", "r_2")
+
+	other, err := OpenStoreWithEngine(context.Background(), engine, dir, "other")
+	if err != nil {
+		t.Fatalf("open other bucket failed: %s", err)
+	}
+	defer other.Close()
+	expectKeys(t, collectKeys(t, other, ""))
+}
+
+func TestSetDefaultEngine(t *testing.T) {
+	defer SetDefaultEngine(ENGINE_BOLT)
+
+	if err := SetDefaultEngine("unknown"); err == nil {
+		t.Errorf("unknown engine should fail")
+	}
+	if err := SetDefaultEngine(ENGINE_MEMORY); err != nil {
+		t.Fatalf("SetDefaultEngine failed: %s", err)
+	}
+	dbMgr, err := CreateDb(t.TempDir())
+	if err != nil {
+		t.Fatalf("CreateDb failed: %s", err)
+	}
+	if _, ok := dbMgr.Db.(*MemStore); !ok {
+		t.Errorf("CreateDb should use the default engine, got %T", dbMgr.Db)
+	}
+	if err := SetDefaultEngine(""); err != nil || GetDefaultEngine() != ENGINE_BOLT {
+		t.Errorf("empty engine should be bolt, got %s, %v", GetDefaultEngine(), err)
+	}
+}
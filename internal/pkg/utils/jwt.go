@@ -1,25 +1,118 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 )
 
+// JWTScope restricts what a token can do, the zero value means no restriction
+type JWTScope struct {
+	ReadOnly       bool     `json:"read_only" example:"false"`
+	TrxTypes       []string `json:"trx_types" validate:"dive,oneof=POST ANNOUNCE PRODUCER USER CHAIN_CONFIG APP_CONFIG STAKE" example:"POST"` // allowed trx types, empty for all
+	RateLimit      int      `json:"rate_limit" validate:"gte=0" example:"60"`                                                                 // requests per minute, 0 for unlimited, shared by tokens with the same name, role and group
+	DailyPostQuota int      `json:"daily_post_quota" validate:"gte=0" example:"100"`                                                          // posts per day (UTC), 0 for unlimited, shared like RateLimit and kept across node restarts
+}
+
 // NewJWTToken creates a new jwt token
 func NewJWTToken(name string, role string, allowGroup string, jwtKey string, exp time.Time) (string, error) {
+	return NewScopedJWTToken(name, role, allowGroup, nil, jwtKey, exp)
+}
 
+// NewScopedJWTToken creates a new jwt token with scope claims, scope can be nil
+func NewScopedJWTToken(name string, role string, allowGroup string, scope *JWTScope, jwtKey string, exp time.Time) (string, error) {
 	token := jwt.New(jwt.SigningMethodHS256)
 	claims := token.Claims.(jwt.MapClaims)
 	claims["name"] = name
 	claims["role"] = role
 	claims["allowGroup"] = allowGroup
 	claims["exp"] = exp.Unix()
+	claims["jti"] = uuid.New().String()
+
+	// only set the restricted claims, so tokens without scope are the same as before
+	if scope != nil {
+		if scope.ReadOnly {
+			claims["readOnly"] = true
+		}
+		if len(scope.TrxTypes) > 0 {
+			claims["trxTypes"] = scope.TrxTypes
+		}
+		if scope.RateLimit > 0 {
+			claims["rateLimit"] = scope.RateLimit
+		}
+		if scope.DailyPostQuota > 0 {
+			claims["dailyPostQuota"] = scope.DailyPostQuota
+		}
+	}
 
 	return token.SignedString([]byte(jwtKey))
 }
 
+// GetJWTId returns jti of token, tokens created before jti was added are identified by hash of the token
+func GetJWTId(token *jwt.Token) string {
+	if claims, ok := token.Claims.(jwt.MapClaims); ok {
+		if jti, ok := claims["jti"].(string); ok && jti != "" {
+			return jti
+		}
+	}
+
+	hash := sha256.Sum256([]byte(token.Raw))
+	return hex.EncodeToString(hash[:])
+}
+
+// GetJWTOwner returns role, name and allowed group of token, they are kept when the token is refreshed,
+// so limits counted by owner are not reset by a refresh
+func GetJWTOwner(token *jwt.Token) string {
+	claims, _ := token.Claims.(jwt.MapClaims)
+	role, _ := claims["role"].(string)
+	name, _ := claims["name"].(string)
+	allowGroup, _ := claims["allowGroup"].(string)
+	return fmt.Sprintf("%s|%s|%s", role, name, allowGroup)
+}
+
+// GetJWTExpiresAt returns exp of token in unix seconds, 0 if it does not have one
+func GetJWTExpiresAt(token *jwt.Token) int64 {
+	if claims, ok := token.Claims.(jwt.MapClaims); ok {
+		if exp, ok := claims["exp"].(float64); ok {
+			return int64(exp)
+		}
+	}
+	return 0
+}
+
+// GetJWTScope gets scope claims from a parsed token, missing claims are left as zero value
+func GetJWTScope(token *jwt.Token) *JWTScope {
+	scope := &JWTScope{}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return scope
+	}
+
+	if v, ok := claims["readOnly"].(bool); ok {
+		scope.ReadOnly = v
+	}
+	if v, ok := claims["trxTypes"].([]interface{}); ok {
+		for _, item := range v {
+			if t, ok := item.(string); ok {
+				scope.TrxTypes = append(scope.TrxTypes, t)
+			}
+		}
+	}
+	// numbers in MapClaims are decoded as float64
+	if v, ok := claims["rateLimit"].(float64); ok {
+		scope.RateLimit = int(v)
+	}
+	if v, ok := claims["dailyPostQuota"].(float64); ok {
+		scope.DailyPostQuota = int(v)
+	}
+
+	return scope
+}
+
 // ParseJWTToken parse jwt token string to *jwt.Token
 func ParseJWTToken(tokenStr string, jwtKey string) (*jwt.Token, error) {
 	claims := jwt.MapClaims{}
@@ -33,6 +126,13 @@ func ParseJWTToken(tokenStr string, jwtKey string) (*jwt.Token, error) {
 	return token, nil
 }
 
+// ParseJWTTokenUnverified parse jwt token string without verifying signature and expiry,
+// it should only be used for tokens already known to be issued by this node
+func ParseJWTTokenUnverified(tokenStr string) (*jwt.Token, error) {
+	token, _, err := jwt.NewParser().ParseUnverified(tokenStr, jwt.MapClaims{})
+	return token, err
+}
+
 // IsJWTTokenExpires checks if the token is expired
 func IsJWTTokenExpired(tokenStr string, jwtKey string) bool {
 	_, err := ParseJWTToken(tokenStr, jwtKey)
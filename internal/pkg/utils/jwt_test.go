@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetJWTOwner(t *testing.T) {
+	owner := func(name, role, allowGroup string, exp time.Time) (string, string) {
+		tokenStr, err := NewScopedJWTToken(name, role, allowGroup, &JWTScope{DailyPostQuota: 10}, "jwt key", exp)
+		if err != nil {
+			t.Fatalf("NewScopedJWTToken failed: %s", err)
+		}
+		token, err := ParseJWTTokenUnverified(tokenStr)
+		if err != nil {
+			t.Fatalf("ParseJWTTokenUnverified failed: %s", err)
+		}
+		return GetJWTOwner(token), GetJWTId(token)
+	}
+
+	now := time.Now()
+	o1, id1 := owner("app", "node", "group1", now.Add(time.Hour))
+	// a refreshed token has another jti and exp, but the same owner
+	o2, id2 := owner("app", "node", "group1", now.Add(30*24*time.Hour))
+	if id1 == id2 {
+		t.Fatal("tokens should have different jti")
+	}
+	if o1 != o2 {
+		t.Fatalf("refreshed token got another owner <%s>, expect <%s>", o2, o1)
+	}
+
+	for _, o := range []string{
+		func() string { o, _ := owner("app", "node", "group2", now); return o }(),
+		func() string { o, _ := owner("app2", "node", "group1", now); return o }(),
+		func() string { o, _ := owner("app", "chain", "group1", now); return o }(),
+	} {
+		if o == o1 {
+			t.Fatalf("tokens of different owners got the same owner <%s>", o)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	"github.com/rumsystem/quorum/internal/pkg/filestore"
+	"github.com/rumsystem/quorum/internal/pkg/utils"
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
+)
+
+// @Tags Files
+// @Summary CreateUpload
+// @Description Create a resumable upload session of a file, chunks of chunk_size are uploaded by UploadChunk then the upload is completed by CompleteUpload
+// @Accept json
+// @Produce json
+// @Param group_id path string true "Group Id"
+// @Param data body handlers.CreateUploadParam true "file info"
+// @Success 200 {object} handlers.UploadResult
+// @Router /api/v1/group/{group_id}/file/upload [post]
+func (h *Handler) CreateUpload(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	params := new(handlers.CreateUploadParam)
+	if err := cc.BindAndValidate(params); err != nil {
+		return err
+	}
+
+	res, err := handlers.CreateUpload(params, h.FileStore)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Tags Files
+// @Summary GetUpload
+// @Description Get an upload session, missing lists chunks to upload when resuming
+// @Produce json
+// @Param group_id path string true "Group Id"
+// @Param upload_id path string true "Upload Id"
+// @Success 200 {object} handlers.UploadResult
+// @Router /api/v1/group/{group_id}/file/upload/{upload_id} [get]
+func (h *Handler) GetUpload(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	params := new(handlers.UploadParam)
+	if err := cc.BindAndValidate(params); err != nil {
+		return err
+	}
+
+	res, err := handlers.GetUpload(params, h.FileStore)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Tags Files
+// @Summary UploadChunk
+// @Description Upload a chunk of the file as raw request body, chunks can be uploaded in parallel and in any order
+// @Accept octet-stream
+// @Produce json
+// @Param group_id path string true "Group Id"
+// @Param upload_id path string true "Upload Id"
+// @Param index path int true "Chunk index, starts from 0"
+// @Param sha256 query string false "sha256 of the chunk, checked before it is saved"
+// @Success 200 {object} filestore.ChunkInfo
+// @Router /api/v1/group/{group_id}/file/upload/{upload_id}/{index} [put]
+func (h *Handler) UploadChunk(c echo.Context) (err error) {
+	params := new(handlers.UploadChunkParam)
+	// body is the raw chunk, so only path and query params are bound
+	binder := new(echo.DefaultBinder)
+	if err := binder.BindPathParams(c, params); err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+	if err := binder.BindQueryParams(c, params); err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+	if err := c.Validate(params); err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(c.Request().Body, filestore.FILE_MAX_CHUNK_SIZE+1))
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+	if int64(len(data)) > filestore.FILE_MAX_CHUNK_SIZE {
+		return rumerrors.NewBadRequestError(fmt.Errorf("chunk is larger than %d", filestore.FILE_MAX_CHUNK_SIZE))
+	}
+
+	res, err := handlers.UploadChunk(params, data, h.FileStore)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Tags Files
+// @Summary CompleteUpload
+// @Description Verify sha256 of the uploaded file, save its manifest and post the file id to the group
+// @Produce json
+// @Param group_id path string true "Group Id"
+// @Param upload_id path string true "Upload Id"
+// @Success 200 {object} handlers.CompleteUploadResult
+// @Router /api/v1/group/{group_id}/file/upload/{upload_id}/complete [post]
+func (h *Handler) CompleteUpload(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	params := new(handlers.UploadParam)
+	if err := cc.BindAndValidate(params); err != nil {
+		return err
+	}
+
+	res, err := handlers.CompleteUpload(params, h.FileStore)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Tags Files
+// @Summary RemoveUpload
+// @Description Abort an upload session
+// @Produce json
+// @Param group_id path string true "Group Id"
+// @Param upload_id path string true "Upload Id"
+// @Success 200 {object} utils.SuccessResponse
+// @Router /api/v1/group/{group_id}/file/upload/{upload_id} [delete]
+func (h *Handler) RemoveUpload(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	params := new(handlers.UploadParam)
+	if err := cc.BindAndValidate(params); err != nil {
+		return err
+	}
+
+	if err := handlers.RemoveUpload(params, h.FileStore); err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return cc.Success()
+}
+
+// @Tags Files
+// @Summary GetGroupFiles
+// @Description Get files shared in a group by this node
+// @Produce json
+// @Param group_id path string true "Group Id"
+// @Success 200 {object} handlers.GroupFilesResult
+// @Router /api/v1/group/{group_id}/files [get]
+func (h *Handler) GetGroupFiles(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	params := new(handlers.GroupArchiveParam)
+	if err := cc.BindAndValidate(params); err != nil {
+		return err
+	}
+
+	res, err := handlers.GetGroupFiles(params.GroupId, h.FileStore)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Tags Files
+// @Summary GetFile
+// @Description Get manifest of a file and its download progress
+// @Produce json
+// @Param group_id path string true "Group Id"
+// @Param file_id path string true "File Id"
+// @Success 200 {object} handlers.FileResult
+// @Router /api/v1/group/{group_id}/file/{file_id} [get]
+func (h *Handler) GetFile(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	params := new(handlers.FileParam)
+	if err := cc.BindAndValidate(params); err != nil {
+		return err
+	}
+
+	res, err := handlers.GetFile(params, h.FileStore)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Tags Files
+// @Summary DownloadFile
+// @Description Fetch a file from group peers in parallel, every chunk and the whole file are verified by sha256, call it again to resume a failed download
+// @Produce json
+// @Param group_id path string true "Group Id"
+// @Param file_id path string true "File Id"
+// @Success 200 {object} filestore.DownloadStatus
+// @Router /api/v1/group/{group_id}/file/{file_id}/download [post]
+func (h *Handler) DownloadFile(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	params := new(handlers.FileParam)
+	if err := cc.BindAndValidate(params); err != nil {
+		return err
+	}
+
+	res, err := handlers.DownloadFile(params, h.FileStore)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Tags Files
+// @Summary GetFileContent
+// @Description Read a complete file, range requests are supported to resume a broken read
+// @Produce octet-stream
+// @Param group_id path string true "Group Id"
+// @Param file_id path string true "File Id"
+// @Success 200 {file} binary
+// @Router /api/v1/group/{group_id}/file/{file_id}/content [get]
+func (h *Handler) GetFileContent(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	params := new(handlers.FileParam)
+	if err := cc.BindAndValidate(params); err != nil {
+		return err
+	}
+
+	r, m, err := handlers.OpenFile(params, h.FileStore)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+	defer r.Close()
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, m.MediaType)
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": m.Name}))
+	header.Set("ETag", fmt.Sprintf(`"%s"`, m.Sha256))
+	http.ServeContent(c.Response(), c.Request(), m.Name, time.Time{}, r)
+	return nil
+}
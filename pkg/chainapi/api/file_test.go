@@ -0,0 +1,149 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rumsystem/quorum/internal/pkg/filestore"
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
+)
+
+func uploadFile(api string, groupID string, name string, content []byte) (*handlers.CompleteUploadResult, error) {
+	sum := sha256.Sum256(content)
+	createParam := handlers.CreateUploadParam{
+		Name:      name,
+		MediaType: "text/plain",
+		Size:      int64(len(content)),
+		Sha256:    hex.EncodeToString(sum[:]),
+	}
+	var upload handlers.UploadResult
+	path := fmt.Sprintf("/api/v1/group/%s/file/upload", groupID)
+	if _, _, err := requestAPI(api, path, "POST", createParam, nil, &upload, true); err != nil {
+		return nil, err
+	}
+	if upload.Upload == nil || len(upload.Missing) != len(upload.Chunks) {
+		return nil, fmt.Errorf("invalid upload: %+v", upload)
+	}
+
+	// upload chunks, the body is the raw chunk
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/octet-stream")
+	for _, index := range upload.Missing {
+		start := int64(index) * upload.ChunkSize
+		end := start + upload.ChunkSize
+		if end > int64(len(content)) {
+			end = int64(len(content))
+		}
+		chunkPath := fmt.Sprintf("/api/v1/group/%s/file/upload/%s/%d", groupID, upload.Id, index)
+		var chunk filestore.ChunkInfo
+		if _, _, err := requestAPI(api, chunkPath, "PUT", content[start:end], headers, &chunk, true); err != nil {
+			return nil, err
+		}
+		if chunk.Size != end-start {
+			return nil, fmt.Errorf("chunk %d saved %d bytes, expect: %d", index, chunk.Size, end-start)
+		}
+	}
+
+	completePath := fmt.Sprintf("/api/v1/group/%s/file/upload/%s/complete", groupID, upload.Id)
+	var result handlers.CompleteUploadResult
+	if _, _, err := requestAPI(api, completePath, "POST", nil, nil, &result, true); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func getFile(api string, groupID string, fileID string) (*handlers.FileResult, error) {
+	path := fmt.Sprintf("/api/v1/group/%s/file/%s", groupID, fileID)
+	var result handlers.FileResult
+	if _, _, err := requestAPI(api, path, "GET", nil, nil, &result, true); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func getFileContent(api string, groupID string, fileID string) ([]byte, error) {
+	path := fmt.Sprintf("/api/v1/group/%s/file/%s/content", groupID, fileID)
+	_, content, err := requestAPI(api, path, "GET", nil, nil, nil, true)
+	return content, err
+}
+
+func TestUploadFile(t *testing.T) {
+	t.Parallel()
+
+	// create group
+	createGroupParam := handlers.CreateGroupParam{
+		GroupName:      "test-file",
+		ConsensusType:  "poa",
+		EncryptionType: "public",
+		AppKey:         "default",
+	}
+	group, err := createGroup(peerapi, createGroupParam)
+	if err != nil {
+		t.Fatalf("createGroup failed: %s, payload: %+v", err, createGroupParam)
+	}
+
+	content := []byte(RandString(4096))
+	uploaded, err := uploadFile(peerapi, group.GroupId, "test.txt", content)
+	if err != nil {
+		t.Fatalf("uploadFile failed: %s", err)
+	}
+	if uploaded.FileId == "" || uploaded.TrxId == "" || uploaded.Manifest == nil {
+		t.Fatalf("invalid upload result: %+v", uploaded)
+	}
+
+	file, err := getFile(peerapi, group.GroupId, uploaded.FileId)
+	if err != nil {
+		t.Fatalf("getFile failed: %s", err)
+	}
+	if !file.Complete || file.MissingChunks != 0 {
+		t.Errorf("uploaded file is not complete: %+v", file)
+	}
+
+	var files handlers.GroupFilesResult
+	filesPath := fmt.Sprintf("/api/v1/group/%s/files", group.GroupId)
+	if _, _, err := requestAPI(peerapi, filesPath, "GET", nil, nil, &files, true); err != nil {
+		t.Fatalf("get group files failed: %s", err)
+	}
+	if len(files.Files) != 1 || files.Files[0].FileId != uploaded.FileId {
+		t.Errorf("files of group: %+v", files.Files)
+	}
+
+	read, err := getFileContent(peerapi, group.GroupId, uploaded.FileId)
+	if err != nil {
+		t.Fatalf("getFileContent failed: %s", err)
+	}
+	if !bytes.Equal(read, content) {
+		t.Errorf("read %d bytes, not the content uploaded", len(read))
+	}
+
+	// fetched by a node joined the group
+	joinGroupParam := handlers.JoinGroupParamV2{
+		Seed: group.Seed,
+	}
+	if _, err := joinGroup(peerapi2, joinGroupParam); err != nil {
+		t.Fatalf("joinGroup failed: %s, payload: %+v", err, joinGroupParam)
+	}
+
+	time.Sleep(25 * time.Second)
+
+	downloadPath := fmt.Sprintf("/api/v1/group/%s/file/%s/download", group.GroupId, uploaded.FileId)
+	var status filestore.DownloadStatus
+	if _, _, err := requestAPI(peerapi2, downloadPath, "POST", nil, nil, &status, true); err != nil {
+		t.Fatalf("download file failed: %s", err)
+	}
+
+	time.Sleep(10 * time.Second)
+
+	read, err = getFileContent(peerapi2, group.GroupId, uploaded.FileId)
+	if err != nil {
+		t.Fatalf("getFileContent from the joined node failed: %s", err)
+	}
+	if !bytes.Equal(read, content) {
+		t.Errorf("downloaded %d bytes, not the content uploaded", len(read))
+	}
+}
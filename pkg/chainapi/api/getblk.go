@@ -8,6 +8,8 @@ import (
 	"github.com/labstack/echo/v4"
 	chain "github.com/rumsystem/quorum/internal/pkg/chainsdk/core"
 	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	"github.com/rumsystem/quorum/internal/pkg/utils"
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
 	_ "github.com/rumsystem/quorum/pkg/pb" //import for swaggo
 )
 
@@ -43,3 +45,26 @@ func (h *Handler) GetBlock(c echo.Context) (err error) {
 		return rumerrors.NewBadRequestError(fmt.Sprintf("Group %s not exist", groupid))
 	}
 }
+
+// @Tags Chain
+// @Summary GetBlocks
+// @Description Get blocks of a group in a range, from `from` to `to` (included), or downwards when reverse. blocks not found are listed in `missing_block_ids`
+// @Produce json
+// @Param group_id path string  true "Group Id"
+// @Param params query handlers.GetBlocksParam false "range params"
+// @Success 200 {object} handlers.GetBlocksResult
+// @Router /api/v1/group/{group_id}/blocks [get]
+func (h *Handler) GetBlocks(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	var params handlers.GetBlocksParam
+	if err := cc.BindAndValidate(&params); err != nil {
+		return err
+	}
+
+	res, err := handlers.GetBlocks(&params)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
+	"github.com/rumsystem/quorum/pkg/pb"
+)
+
+func getBlocks(api string, payload handlers.GetBlocksParam) (*handlers.GetBlocksResult, error) {
+	path := fmt.Sprintf("/api/v1/group/%s/blocks", payload.GroupId)
+	var result handlers.GetBlocksResult
+	if _, _, err := requestAPI(api, path, "GET", payload, nil, &result, false); err != nil {
+		return nil, err
+	}
+	if result.GroupId != payload.GroupId {
+		return nil, fmt.Errorf("group id not match, expect: %s, actual: %s", payload.GroupId, result.GroupId)
+	}
+	return &result, nil
+}
+
+func getGroupTrxs(api string, payload handlers.GetGroupTrxsParam) (*handlers.GetGroupTrxsResult, error) {
+	path := fmt.Sprintf("/api/v1/group/%s/trxs", payload.GroupId)
+	var result handlers.GetGroupTrxsResult
+	if _, _, err := requestAPI(api, path, "GET", payload, nil, &result, false); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func getTrxBlock(api string, groupID string, trxID string) (*pb.Block, error) {
+	path := fmt.Sprintf("/api/v1/trx/%s/%s/block", groupID, trxID)
+	var block pb.Block
+	if _, _, err := requestAPI(api, path, "GET", nil, nil, &block, true); err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+func TestGetBlocks(t *testing.T) {
+	t.Parallel()
+
+	// create group
+	createGroupParam := handlers.CreateGroupParam{
+		GroupName:      "test-get-blocks",
+		ConsensusType:  "poa",
+		EncryptionType: "public",
+		AppKey:         "default",
+	}
+	group, err := createGroup(peerapi, createGroupParam)
+	if err != nil {
+		t.Fatalf("createGroup failed: %s, payload: %+v", err, createGroupParam)
+	}
+
+	// post to group
+	postGroupParam := PostGroupParam{
+		Data: map[string]interface{}{
+			"type":    "Note",
+			"content": "Hello World",
+			"name":    "get blocks testing",
+		},
+		GroupID: group.GroupId,
+	}
+	postResult, err := postToGroup(peerapi, postGroupParam)
+	if err != nil {
+		t.Fatalf("postToGroup failed: %s, payload: %+v", err, postGroupParam)
+	}
+
+	time.Sleep(25 * time.Second)
+
+	// blocks from genesis
+	result, err := getBlocks(peerapi, handlers.GetBlocksParam{GroupId: group.GroupId, Num: 10})
+	if err != nil {
+		t.Fatalf("getBlocks failed: %s", err)
+	}
+	if len(result.Blocks) < 2 {
+		t.Fatalf("expect genesis block and the block of the post, got %d blocks", len(result.Blocks))
+	}
+	if len(result.MissingBlockIds) != 0 {
+		t.Errorf("blocks %v are missing in local chain", result.MissingBlockIds)
+	}
+	for i, block := range result.Blocks {
+		if block.BlockId != uint64(i) {
+			t.Errorf("blocks out of order, expect: %d, actual: %d", i, block.BlockId)
+		}
+	}
+
+	// reverse from the top block
+	top := result.Blocks[len(result.Blocks)-1].BlockId
+	reversed, err := getBlocks(peerapi, handlers.GetBlocksParam{GroupId: group.GroupId, Num: 1, Reverse: true})
+	if err != nil {
+		t.Fatalf("getBlocks reverse failed: %s", err)
+	}
+	if len(reversed.Blocks) != 1 || reversed.Blocks[0].BlockId < top {
+		t.Errorf("reverse page does not start from the top block %d: %+v", top, reversed.Blocks)
+	}
+	if !reversed.HasMore || reversed.NextBlockId != reversed.Blocks[0].BlockId-1 {
+		t.Errorf("reverse page has_more: %v, next_block_id: %d", reversed.HasMore, reversed.NextBlockId)
+	}
+
+	// the post is found by trx type and by its id
+	trxs, err := getGroupTrxs(peerapi, handlers.GetGroupTrxsParam{GroupId: group.GroupId, TrxType: "POST", Num: 100})
+	if err != nil {
+		t.Fatalf("getGroupTrxs failed: %s", err)
+	}
+	found := false
+	for _, trx := range trxs.Trxs {
+		if trx.Trx.TrxId == postResult.TrxId {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("trx %s not found in group trxs", postResult.TrxId)
+	}
+
+	block, err := getTrxBlock(peerapi, group.GroupId, postResult.TrxId)
+	if err != nil {
+		t.Fatalf("getTrxBlock failed: %s", err)
+	}
+	if block.GroupId != group.GroupId || block.BlockId == 0 {
+		t.Errorf("trx %s is in block %d of group %s", postResult.TrxId, block.BlockId, block.GroupId)
+	}
+}
@@ -16,21 +16,22 @@ import (
 )
 
 type GroupInfo struct {
-	GroupId         string             `json:"group_id" validate:"required,uuid4" example:"c0020941-e648-40c9-92dc-682645acd17e"`
-	GroupName       string             `json:"group_name" validate:"required" example:"demo-app"`
-	OwnerPubKey     string             `json:"owner_pubkey" validate:"required" example:"CAISIQLW2nWw+IhoJbTUmoq2ioT5plvvw/QmSeK2uBy090/3hg=="`
-	UserPubkey      string             `json:"user_pubkey" validate:"required" example:"CAISIQO7ury6x7aWpwUVn6mj2dZFqme3BAY5xDkYjqW/EbFFcA=="`
-	UserEthaddr     string             `json:"user_eth_addr" validate:"required" example:"0495180230ae0f585ca0b4fc0767e616eaed45e400f470ed50c91668e1ed76c278b7fc5a129ff154c6b200a26cc78b7b4acc5b3915cdf66286c942aa5b65166ff5"`
-	ConsensusType   string             `json:"consensus_type" validate:"required" example:"POA"`
-	EncryptionType  string             `json:"encryption_type" validate:"required" example:"PUBLIC"`
-	CipherKey       string             `json:"cipher_key" validate:"required" example:"58044622d48c4d91932583a05db3ff87f29acacb62e701916f7f0bbc6e446e5d"`
-	AppKey          string             `json:"app_key" validate:"required" example:"test_app"`
-	CurrtEpoch      uint64             `json:"currt_epoch" validate:"required" example:"0"`
-	CurrtTopBlock   uint64             `json:"currt_top_block" validate:"required" example:"0"`
-	LastUpdated     int64              `json:"last_updated" validate:"required" example:"1633022375303983600"`
-	RexSyncerStatus string             `json:"rex_syncer_status" validate:"required" example:"IDLE"`
-	RexSyncerResult *def.RexSyncResult `json:"rex_Syncer_result" validate:"required"`
-	Peers           []peer.ID          `json:"peers" validate:"required" example:"16Uiu2HAkuXLC2hZTRbWToCNztyWB39KDi8g66ou3YrSzeTbsWsFG,16Uiu2HAm8XVpfQrJYaeL7XtrHC3FvfKt2QW7P8R3MBenYyHxu8Kk"`
+	GroupId         string               `json:"group_id" validate:"required,uuid4" example:"c0020941-e648-40c9-92dc-682645acd17e"`
+	GroupName       string               `json:"group_name" validate:"required" example:"demo-app"`
+	OwnerPubKey     string               `json:"owner_pubkey" validate:"required" example:"CAISIQLW2nWw+IhoJbTUmoq2ioT5plvvw/QmSeK2uBy090/3hg=="`
+	UserPubkey      string               `json:"user_pubkey" validate:"required" example:"CAISIQO7ury6x7aWpwUVn6mj2dZFqme3BAY5xDkYjqW/EbFFcA=="`
+	UserEthaddr     string               `json:"user_eth_addr" validate:"required" example:"0495180230ae0f585ca0b4fc0767e616eaed45e400f470ed50c91668e1ed76c278b7fc5a129ff154c6b200a26cc78b7b4acc5b3915cdf66286c942aa5b65166ff5"`
+	ConsensusType   string               `json:"consensus_type" validate:"required" example:"POA"`
+	EncryptionType  string               `json:"encryption_type" validate:"required" example:"PUBLIC"`
+	CipherKey       string               `json:"cipher_key" validate:"required" example:"58044622d48c4d91932583a05db3ff87f29acacb62e701916f7f0bbc6e446e5d"`
+	AppKey          string               `json:"app_key" validate:"required" example:"test_app"`
+	CurrtEpoch      uint64               `json:"currt_epoch" validate:"required" example:"0"`
+	CurrtTopBlock   uint64               `json:"currt_top_block" validate:"required" example:"0"`
+	LastUpdated     int64                `json:"last_updated" validate:"required" example:"1633022375303983600"`
+	RexSyncerStatus string               `json:"rex_syncer_status" validate:"required" example:"IDLE"`
+	RexSyncerResult *def.RexSyncResult   `json:"rex_Syncer_result" validate:"required"`
+	GapRepair       *def.GapRepairStatus `json:"gap_repair"`
+	Peers           []peer.ID            `json:"peers" validate:"required" example:"16Uiu2HAkuXLC2hZTRbWToCNztyWB39KDi8g66ou3YrSzeTbsWsFG,16Uiu2HAm8XVpfQrJYaeL7XtrHC3FvfKt2QW7P8R3MBenYyHxu8Kk"`
 }
 
 type GroupInfoList struct {
@@ -124,6 +125,7 @@ func getGroupInfo(groupId string) (*GroupInfo, error) {
 	}
 	group.RexSyncerStatus = value.GetRexSyncerStatus()
 	group.RexSyncerResult, _ = value.ChainCtx.GetLastRexSyncResult()
+	group.GapRepair = value.ChainCtx.GetGapRepairStatus()
 	group.Peers = nodectx.GetNodeCtx().ListGroupPeers(groupId)
 
 	return group, nil
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	"github.com/rumsystem/quorum/internal/pkg/utils"
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
+)
+
+// @Tags Chain
+// @Summary GetGroupTrxs
+// @Description List transactions on chain of a group, filtered by type, senders and time window (unix nano), in block order
+// @Produce json
+// @Param group_id path string  true "Group Id"
+// @Param params query handlers.GetGroupTrxsParam false "filter params"
+// @Success 200 {object} handlers.GetGroupTrxsResult
+// @Router /api/v1/group/{group_id}/trxs [get]
+func (h *Handler) GetGroupTrxs(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	var params handlers.GetGroupTrxsParam
+	if err := cc.BindAndValidate(&params); err != nil {
+		return err
+	}
+
+	res, err := handlers.GetGroupTrxs(&params)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -31,3 +31,26 @@ func (h *Handler) GetTrx(c echo.Context) (err error) {
 
 	return c.JSON(http.StatusOK, trx)
 }
+
+// @Tags Chain
+// @Summary GetTrxBlock
+// @Description Get the block which contains a transaction
+// @Produce json
+// @Param group_id path string  true "Group Id"
+// @Param trx_id path string  true "Transaction Id"
+// @Success 200 {object} pb.Block
+// @Router /api/v1/trx/{group_id}/{trx_id}/block [get]
+func (h *Handler) GetTrxBlock(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	var params handlers.GetTrxParam
+	if err := cc.BindAndValidate(&params); err != nil {
+		return err
+	}
+
+	block, err := handlers.GetTrxBlock(params.GroupId, params.TrxId)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, block)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	"github.com/rumsystem/quorum/internal/pkg/utils"
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
+)
+
+// @Tags Groups
+// @Summary ExportGroupArchive
+// @Description Stream all blocks of a group, from genesis to the top one, as a versioned and checksummed group archive
+// @Produce octet-stream
+// @Param group_id path string true "Group Id"
+// @Success 200 {file} binary
+// @Router /api/v1/group/{group_id}/export [get]
+func (h *Handler) ExportGroupArchive(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	var params handlers.GroupArchiveParam
+	if err := cc.BindAndValidate(&params); err != nil {
+		return err
+	}
+
+	cs := h.NodeCtx.GetChainStorage()
+	exist, err := cs.IsBlockExist(params.GroupId, 0, false, h.NodeCtx.Name)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+	if !exist {
+		return rumerrors.NewBadRequestError(rumerrors.ErrGroupNotFound)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMEOctetStream)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%s.rumgroup", params.GroupId))
+	c.Response().WriteHeader(http.StatusOK)
+
+	//response is already started, errors can only be logged, the archive will fail checksum on import
+	count, err := handlers.ExportGroupArchive(cs, params.GroupId, c.Response(), h.NodeCtx.Name)
+	if err != nil {
+		c.Logger().Errorf("export group %s failed after %d blocks: %s", params.GroupId, count, err)
+	}
+	return nil
+}
+
+// @Tags Groups
+// @Summary ImportGroupArchive
+// @Description Import blocks from a group archive (request body) into a joined group, every block is verified with its parent before applied
+// @Accept octet-stream
+// @Produce json
+// @Param group_id path string true "Group Id"
+// @Success 200 {object} handlers.ImportGroupArchiveResult
+// @Router /api/v1/group/{group_id}/import [post]
+func (h *Handler) ImportGroupArchive(c echo.Context) (err error) {
+	var params handlers.GroupArchiveParam
+	// body is the archive, so only path params are bound
+	binder := new(echo.DefaultBinder)
+	if err := binder.BindPathParams(c, &params); err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+	if err := c.Validate(&params); err != nil {
+		return err
+	}
+
+	res, err := handlers.ImportGroupArchive(params.GroupId, c.Request().Body)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
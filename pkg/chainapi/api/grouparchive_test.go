@@ -0,0 +1,105 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
+)
+
+func exportGroupArchive(api string, groupID string) ([]byte, error) {
+	path := fmt.Sprintf("/api/v1/group/%s/export", groupID)
+	_, archive, err := requestAPI(api, path, "GET", nil, nil, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+func importGroupArchive(api string, groupID string, archive []byte) (*handlers.ImportGroupArchiveResult, error) {
+	path := fmt.Sprintf("/api/v1/group/%s/import", groupID)
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/octet-stream")
+	var result handlers.ImportGroupArchiveResult
+	if _, _, err := requestAPI(api, path, "POST", archive, headers, &result, false); err != nil {
+		return nil, err
+	}
+	if result.GroupId != groupID {
+		return nil, fmt.Errorf("group id not match, expect: %s, actual: %s", groupID, result.GroupId)
+	}
+	return &result, nil
+}
+
+func TestExportImportGroupArchive(t *testing.T) {
+	t.Parallel()
+
+	// create group
+	createGroupParam := handlers.CreateGroupParam{
+		GroupName:      "test-group-archive",
+		ConsensusType:  "poa",
+		EncryptionType: "public",
+		AppKey:         "default",
+	}
+	group, err := createGroup(peerapi, createGroupParam)
+	if err != nil {
+		t.Fatalf("createGroup failed: %s, payload: %+v", err, createGroupParam)
+	}
+
+	// post to group
+	postGroupParam := PostGroupParam{
+		Data: map[string]interface{}{
+			"type":    "Note",
+			"content": "Hello World",
+			"name":    "group archive testing",
+		},
+		GroupID: group.GroupId,
+	}
+	if _, err := postToGroup(peerapi, postGroupParam); err != nil {
+		t.Fatalf("postToGroup failed: %s, payload: %+v", err, postGroupParam)
+	}
+
+	time.Sleep(25 * time.Second)
+
+	archive, err := exportGroupArchive(peerapi, group.GroupId)
+	if err != nil {
+		t.Fatalf("exportGroupArchive failed: %s", err)
+	}
+	if len(archive) == 0 {
+		t.Fatalf("exported archive is empty")
+	}
+
+	// import to a node joined the group, blocks synced already are skipped
+	joinGroupParam := handlers.JoinGroupParamV2{
+		Seed: group.Seed,
+	}
+	if _, err := joinGroup(peerapi2, joinGroupParam); err != nil {
+		t.Fatalf("joinGroup failed: %s, payload: %+v", err, joinGroupParam)
+	}
+
+	result, err := importGroupArchive(peerapi2, group.GroupId, archive)
+	if err != nil {
+		t.Fatalf("importGroupArchive failed: %s", err)
+	}
+	if result.TopBlock == 0 || result.Imported+result.Skipped != result.TopBlock {
+		t.Errorf("imported %d and skipped %d blocks, top block: %d", result.Imported, result.Skipped, result.TopBlock)
+	}
+
+	// archive of another group or not an archive
+	other, err := createGroup(peerapi, handlers.CreateGroupParam{
+		GroupName:      "test-group-archive-other",
+		ConsensusType:  "poa",
+		EncryptionType: "public",
+		AppKey:         "default",
+	})
+	if err != nil {
+		t.Fatalf("createGroup failed: %s", err)
+	}
+	if _, err := importGroupArchive(peerapi, other.GroupId, archive); err == nil {
+		t.Errorf("archive of group %s is imported to group %s", group.GroupId, other.GroupId)
+	}
+	if _, err := importGroupArchive(peerapi, other.GroupId, []byte("not an archive")); err == nil {
+		t.Errorf("invalid archive is imported")
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/rumsystem/quorum/internal/pkg/appdata"
 	"github.com/rumsystem/quorum/internal/pkg/conn/p2p"
+	"github.com/rumsystem/quorum/internal/pkg/filestore"
 	"github.com/rumsystem/quorum/internal/pkg/nodectx"
 	"github.com/rumsystem/quorum/internal/pkg/storage/def"
 )
@@ -18,5 +19,6 @@ type (
 		Appdb            *appdata.AppDb
 		ChainAPIdb       def.APIHandlerIface
 		WebsocketManager *WebsocketManager
+		FileStore        *filestore.FileStore
 	}
 )
@@ -192,7 +192,7 @@ func (h *Handler) JoinGroupV2() echo.HandlerFunc {
 		}
 
 		//add group to context
-		groupmgr.Groups[group.Item.GroupId] = group
+		groupmgr.AddGroup(group)
 
 		var bufferResult bytes.Buffer
 		bufferResult.Write(genesisBlockBytes)
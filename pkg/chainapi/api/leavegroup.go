@@ -25,7 +25,7 @@ func (h *Handler) LeaveGroup(c echo.Context) (err error) {
 		return err
 	}
 
-	res, err := handlers.LeaveGroup(params, h.Appdb)
+	res, err := handlers.LeaveGroup(params, h.Appdb, h.FileStore)
 	if err != nil {
 		return rumerrors.NewBadRequestError(err)
 	}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	"github.com/rumsystem/quorum/internal/pkg/utils"
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
+)
+
+// @Tags Groups
+// @Summary GetMempool
+// @Description Get trxs waiting in the trx buffer of a producer, includes buffer depth, trx count of each sender and the mempool policy set by group owner
+// @Produce json
+// @Param group_id path string true "Group Id"
+// @Success 200 {object} handlers.MempoolResult
+// @Router /api/v1/group/{group_id}/mempool [get]
+func (h *Handler) GetMempool(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	var params handlers.GetMempoolParam
+	if err := cc.BindAndValidate(&params); err != nil {
+		return err
+	}
+
+	res, err := handlers.GetMempool(params.GroupId)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
+)
+
+func getMempool(api string, groupID string) (*handlers.MempoolResult, error) {
+	path := fmt.Sprintf("/api/v1/group/%s/mempool", groupID)
+	var result handlers.MempoolResult
+	if _, _, err := requestAPI(api, path, "GET", nil, nil, &result, true); err != nil {
+		return nil, err
+	}
+	if result.GroupId != groupID {
+		return nil, fmt.Errorf("group id not match, expect: %s, actual: %s", groupID, result.GroupId)
+	}
+	if result.Policy == nil || result.Policy.BatchSize <= 0 {
+		return nil, fmt.Errorf("invalid mempool policy: %+v", result.Policy)
+	}
+
+	count := 0
+	for _, sender := range result.Senders {
+		count += sender.Count
+	}
+	if count != result.Depth {
+		return nil, fmt.Errorf("depth is %d, but senders have %d trxs", result.Depth, count)
+	}
+	return &result, nil
+}
+
+func TestGetMempool(t *testing.T) {
+	t.Parallel()
+
+	// create group
+	createGroupParam := handlers.CreateGroupParam{
+		GroupName:      "test-mempool",
+		ConsensusType:  "poa",
+		EncryptionType: "public",
+		AppKey:         "default",
+	}
+	group, err := createGroup(peerapi, createGroupParam)
+	if err != nil {
+		t.Fatalf("createGroup failed: %s, payload: %+v", err, createGroupParam)
+	}
+
+	if _, err := getMempool(peerapi, group.GroupId); err != nil {
+		t.Fatalf("getMempool failed: %s", err)
+	}
+
+	// post to group, the trx leaves the mempool once it is packaged
+	postGroupParam := PostGroupParam{
+		Data: map[string]interface{}{
+			"type":    "Note",
+			"content": "Hello World",
+			"name":    "mempool testing",
+		},
+		GroupID: group.GroupId,
+	}
+	if _, err := postToGroup(peerapi, postGroupParam); err != nil {
+		t.Fatalf("postToGroup failed: %s, payload: %+v", err, postGroupParam)
+	}
+
+	time.Sleep(25 * time.Second)
+
+	result, err := getMempool(peerapi, group.GroupId)
+	if err != nil {
+		t.Fatalf("getMempool failed: %s", err)
+	}
+	if result.Depth != 0 {
+		t.Errorf("%d trxs left in mempool after packaged: %+v", result.Depth, result.Senders)
+	}
+}
@@ -1,15 +1,77 @@
 package api
 
 import (
+	"context"
+	"net/http"
 	"strings"
 
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 	"github.com/rumsystem/quorum/internal/pkg/logging"
+	rummiddleware "github.com/rumsystem/quorum/internal/pkg/middleware"
+	"github.com/rumsystem/quorum/internal/pkg/options"
+	"github.com/rumsystem/quorum/internal/pkg/utils"
 	appapi "github.com/rumsystem/quorum/pkg/chainapi/appapi"
 )
 
 var opaLogger = logging.Logger("opa")
 
+// opaPolicy is the active policy of api server, nil before server started
+var opaPolicy *rummiddleware.OpaPolicy
+
+// opaMiddleware loads the builtin policy and operator policy files, policy files are reloaded on change
+func opaMiddleware(nodeopt *options.NodeOptions, skipper middleware.Skipper) echo.MiddlewareFunc {
+	policy, err := rummiddleware.NewOpaPolicy(policyStr, "x = "+nodeopt.OpaQuery, nodeopt.OpaPolicyDir)
+	if err != nil {
+		opaLogger.Fatalf("load opa policy failed: %s", err)
+	}
+	opaPolicy = policy
+	go policy.Watch(context.Background())
+
+	return rummiddleware.OpaWithConfig(rummiddleware.OpaConfig{
+		Skipper:   skipper,
+		InputFunc: opaInputFunc,
+		OpaPolicy: policy,
+	})
+}
+
+// jwtLimitMiddleware enforces rate limit and daily post quota claims of jwt, tokens are counted by owner,
+// daily post counts are kept in node db
+func jwtLimitMiddleware(nodeopt *options.NodeOptions, skipper middleware.Skipper) echo.MiddlewareFunc {
+	var store rummiddleware.JWTCountStore
+	if jwtStore := nodeopt.JWTStore(); jwtStore != nil {
+		store = jwtStore
+	}
+
+	return rummiddleware.JWTLimitWithConfig(rummiddleware.JWTLimitConfig{
+		Skipper: skipper,
+		LimitFunc: func(c echo.Context) (string, int, int, bool) {
+			token, err := appapi.GetJWTToken(c)
+			if err != nil {
+				return "", 0, 0, false
+			}
+			scope := appapi.GetJWTScope(token)
+			return utils.GetJWTOwner(token), scope.RateLimit, scope.DailyPostQuota, true
+		},
+		IsPostFunc: isPostRequest,
+		Store:      store,
+	})
+}
+
+// isPostRequest reports whether the request sends a POST trx to a group
+func isPostRequest(c echo.Context) bool {
+	if c.Request().Method != http.MethodPost {
+		return false
+	}
+
+	switch c.Path() {
+	case "/api/v1/group/:group_id/content", "/api/v1/node/:group_id/trx", "/api/v1/group/:group_id/file/upload/:upload_id/complete":
+		return true
+	}
+	return false
+}
+
 func opaInputFunc(c echo.Context) interface{} {
 	token, err := appapi.GetJWTToken(c)
 	if err != nil {
@@ -18,10 +80,23 @@ func opaInputFunc(c echo.Context) interface{} {
 	}
 
 	r := c.Request()
+	return opaInput(r.Method, r.URL.Path, token)
+}
+
+func opaInput(method string, path string, token *jwt.Token) map[string]interface{} {
+	scope := appapi.GetJWTScope(token)
+	trxTypes := scope.TrxTypes
+	if trxTypes == nil {
+		trxTypes = []string{}
+	}
+
 	return map[string]interface{}{
-		"method":       r.Method,
-		"path":         strings.Split(strings.Trim(r.URL.Path, "/"), "/"),
+		"method":       method,
+		"path":         strings.Split(strings.Trim(path, "/"), "/"),
+		"name":         appapi.GetJWTName(token),
 		"role":         appapi.GetJWTRole(token),
 		"allow_groups": appapi.GetJWTAllowGroups(token),
+		"read_only":    scope.ReadOnly,
+		"trx_types":    trxTypes,
 	}
 }
@@ -1,16 +1,82 @@
 package api
 
+// policyStr is the builtin policy, operator policy files in the same package can add more
+// allow rules, or deny rules which take precedence over allow
 const policyStr = `package quorum.restapi.authz
 
 import future.keywords.in
 
 default allow = false
 
+default deny = false
+
+default decision = false
+
+decision {
+	allow
+	not deny
+}
+
 # allow all for chain role
 allow {
 	input.role == "chain"
 }
 
+##########################
+# rules for scoped token #
+##########################
+
+# read only token can not change anything, except refreshing itself
+deny {
+	input.read_only
+	input.method != "GET"
+	input.path != ["app", "api", "v1", "token", "refresh"]
+}
+
+# token with trx_types can only send trx of these types
+deny {
+	count(input.trx_types) > 0
+	trx_type := request_trx_type
+	not trx_type in input.trx_types
+}
+
+group_trx_types := {
+	"announce": "ANNOUNCE",
+	"producer": "PRODUCER",
+	"user": "USER",
+	"chainconfig": "CHAIN_CONFIG",
+	"appconfig": "APP_CONFIG",
+	"stake": "STAKE",
+}
+
+# trx type sent by the request, undefined if the request does not send trx
+request_trx_type = "POST" {
+	input.method == "POST"
+	input.path = ["api", "v1", "group", _, "content"]
+}
+
+request_trx_type = "POST" {
+	input.method == "POST"
+	input.path = ["api", "v1", "node", _, "trx"]
+}
+
+request_trx_type = "POST" {
+	input.method == "POST"
+	input.path = ["api", "v1", "group", _, "file", "upload", _, "complete"]
+}
+
+request_trx_type = "ANNOUNCE" {
+	input.method == "POST"
+	input.path = ["api", "v1", "node", _, "announce"]
+}
+
+request_trx_type = trx_type {
+	some name
+	input.method == "POST"
+	input.path = ["api", "v1", "group", name]
+	trx_type := group_trx_types[name]
+}
+
 #######################
 # rules for node role #
 #######################
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	"github.com/rumsystem/quorum/internal/pkg/utils"
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
+)
+
+type PubQueueAckPayload struct {
+	TrxIds []string `json:"trx_ids" validate:"required,min=1,dive,uuid4"`
+}
+
+// @Tags Chain
+// @Summary GetPubQueue
+// @Description Get the publish queue of a group, trxs in the queue will be resent till they are packaged
+// @Produce json
+// @Param group_id path string true "Group Id"
+// @Param trx query string false "Transaction Id"
+// @Param status query string false "PENDING, SUCCESS or FAIL"
+// @Success 200 {object} handlers.PubQueueInfo
+// @Router /api/v1/group/{group_id}/pubqueue [get]
+func (h *Handler) GetPubQueue(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	var params handlers.GetPubQueueParam
+	if err := cc.BindAndValidate(&params); err != nil {
+		return err
+	}
+
+	res, err := handlers.GetPubQueue(params.GroupId, params.Status, params.TrxId)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Tags Chain
+// @Summary PubQueueAck
+// @Description Remove finished (SUCCESS or FAIL) trxs from the publish queue
+// @Accept json
+// @Produce json
+// @Param data body PubQueueAckPayload true "trx ids"
+// @Success 200 {array} string
+// @Router /api/v1/trx/ack [post]
+func (h *Handler) PubQueueAck(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	var params PubQueueAckPayload
+	if err := cc.BindAndValidate(&params); err != nil {
+		return err
+	}
+
+	res, err := handlers.PubQueueAck(params.TrxIds)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
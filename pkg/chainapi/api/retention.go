@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	"github.com/rumsystem/quorum/internal/pkg/utils"
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
+)
+
+// @Tags Groups
+// @Summary GetRetention
+// @Description Get block retention policy of a group and its pruned range, blocks in [1, pruned_to] only keep headers and their trxs have no data
+// @Produce json
+// @Param group_id path string true "Group Id"
+// @Success 200 {object} handlers.RetentionResult
+// @Router /api/v1/group/{group_id}/retention [get]
+func (h *Handler) GetRetention(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	var params handlers.GetSyncProgressParam
+	if err := cc.BindAndValidate(&params); err != nil {
+		return err
+	}
+
+	res, err := handlers.GetRetention(params.GroupId)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Tags Groups
+// @Summary SetRetention
+// @Description Set block retention policy of a group: all, blocks (keep the last n blocks), days (keep blocks of the last n days) or state (keep only derived state and block headers). Old blocks are pruned in background and can not be restored by a looser policy
+// @Accept json
+// @Produce json
+// @Param group_id path string true "Group Id"
+// @Param data body handlers.SetRetentionParam true "retention policy"
+// @Success 200 {object} handlers.RetentionResult
+// @Router /api/v1/group/{group_id}/retention [post]
+func (h *Handler) SetRetention(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	params := new(handlers.SetRetentionParam)
+	if err := cc.BindAndValidate(params); err != nil {
+		return err
+	}
+
+	res, err := handlers.SetRetention(params)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
+)
+
+func getRetention(api string, groupID string) (*handlers.RetentionResult, error) {
+	path := fmt.Sprintf("/api/v1/group/%s/retention", groupID)
+	var result handlers.RetentionResult
+	if _, _, err := requestAPI(api, path, "GET", nil, nil, &result, true); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func setRetention(api string, groupID string, payload handlers.SetRetentionParam) (*handlers.RetentionResult, error) {
+	path := fmt.Sprintf("/api/v1/group/%s/retention", groupID)
+	var result handlers.RetentionResult
+	if _, _, err := requestAPI(api, path, "POST", payload, nil, &result, true); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func TestRetention(t *testing.T) {
+	t.Parallel()
+
+	// create group
+	createGroupParam := handlers.CreateGroupParam{
+		GroupName:      "test-retention",
+		ConsensusType:  "poa",
+		EncryptionType: "public",
+		AppKey:         "default",
+	}
+	group, err := createGroup(peerapi, createGroupParam)
+	if err != nil {
+		t.Fatalf("createGroup failed: %s, payload: %+v", err, createGroupParam)
+	}
+
+	// all blocks are kept by default
+	result, err := getRetention(peerapi, group.GroupId)
+	if err != nil {
+		t.Fatalf("getRetention failed: %s", err)
+	}
+	if result.GroupId != group.GroupId || result.Policy == nil || result.Policy.Mode != "all" {
+		t.Errorf("default retention of group: %+v", result)
+	}
+	if result.Pruned != nil && result.Pruned.PrunedTo != 0 {
+		t.Errorf("new group is pruned to block %d", result.Pruned.PrunedTo)
+	}
+
+	param := handlers.SetRetentionParam{Mode: "blocks", Value: 1000}
+	result, err = setRetention(peerapi, group.GroupId, param)
+	if err != nil {
+		t.Fatalf("setRetention failed: %s, payload: %+v", err, param)
+	}
+	if result.Policy == nil || result.Policy.Mode != param.Mode || result.Policy.Value != param.Value {
+		t.Errorf("retention policy saved: %+v, expect: %+v", result.Policy, param)
+	}
+
+	result, err = getRetention(peerapi, group.GroupId)
+	if err != nil {
+		t.Fatalf("getRetention failed: %s", err)
+	}
+	if result.Policy == nil || result.Policy.Mode != param.Mode || result.Policy.Value != param.Value {
+		t.Errorf("retention policy: %+v, expect: %+v", result.Policy, param)
+	}
+
+	for _, invalid := range []handlers.SetRetentionParam{{Mode: "forever"}, {Mode: "blocks", Value: 0}} {
+		if _, err := setRetention(peerapi, group.GroupId, invalid); err == nil {
+			t.Errorf("invalid retention policy %+v is saved", invalid)
+		}
+	}
+}
@@ -34,12 +34,8 @@ func StartBootstrapNodeServer(config StartServerParam, signalch chan os.Signal,
 	e := utils.NewEcho(config.IsDebug)
 	customJWTConfig := appapi.CustomJWTConfig(nodeopt.JWT.Key)
 	e.Use(middleware.JWTWithConfig(customJWTConfig))
-	e.Use(rummiddleware.OpaWithConfig(rummiddleware.OpaConfig{
-		Skipper:   rummiddleware.LocalhostSkipper,
-		Policy:    policyStr,
-		Query:     "x = data.quorum.restapi.authz.allow", // FIXME: hardcode
-		InputFunc: opaInputFunc,
-	}))
+	e.Use(opaMiddleware(nodeopt, rummiddleware.LocalhostSkipper))
+	e.Use(jwtLimitMiddleware(nodeopt, rummiddleware.LocalhostSkipper))
 
 	r := e.Group("/api")
 	r.GET("/quit", quitapp)
@@ -69,12 +65,8 @@ func StartProducerServer(config StartServerParam, signalch chan os.Signal, h *Ha
 	e := utils.NewEcho(config.IsDebug)
 	customJWTConfig := appapi.CustomJWTConfig(nodeopt.JWT.Key)
 	e.Use(middleware.JWTWithConfig(customJWTConfig))
-	e.Use(rummiddleware.OpaWithConfig(rummiddleware.OpaConfig{
-		Skipper:   rummiddleware.LocalhostSkipper,
-		Policy:    policyStr,
-		Query:     "x = data.quorum.restapi.authz.allow", // FIXME: hardcode
-		InputFunc: opaInputFunc,
-	}))
+	e.Use(opaMiddleware(nodeopt, rummiddleware.LocalhostSkipper))
+	e.Use(jwtLimitMiddleware(nodeopt, rummiddleware.LocalhostSkipper))
 	r := e.Group("/api")
 	r.GET("/quit", quitapp)
 
@@ -84,12 +76,16 @@ func StartProducerServer(config StartServerParam, signalch chan os.Signal, h *Ha
 	r.POST("/v1/group/leave", h.LeaveGroup)
 	r.POST("/v1/group/clear", h.ClearGroupData)
 	r.POST("/v1/group/announce", h.Announce)
+	r.POST("/v1/group/stake", h.GroupStake)
 
 	r.GET("/v1/node", h.GetNodeInfo)
 	r.GET("/v1/network", h.GetNetwork(&node.Host, node.Info, nodeopt, ethaddr))
 	//r.GET("/v1/network/stats", h.GetNetworkStatsSummary)
 	r.GET("/v1/block/:group_id/:block_id", h.GetBlock)
 	r.GET("/v1/trx/:group_id/:trx_id", h.GetTrx)
+	r.GET("/v1/trx/:group_id/:trx_id/block", h.GetTrxBlock)
+	r.GET("/v1/group/:group_id/blocks", h.GetBlocks)
+	r.GET("/v1/group/:group_id/trxs", h.GetGroupTrxs)
 
 	r.GET("/v1/groups", h.GetGroups)
 	r.GET("/v1/group/:group_id", h.GetGroupById)
@@ -97,10 +93,18 @@ func StartProducerServer(config StartServerParam, signalch chan os.Signal, h *Ha
 	r.GET("/v1/group/:group_id/trx/denylist", h.GetChainTrxDenyList)
 	r.GET("/v1/group/:group_id/trx/auth/:trx_type", h.GetChainTrxAuthMode)
 	r.GET("/v1/group/:group_id/producers", h.GetGroupProducers)
+	r.GET("/v1/group/:group_id/stakes", h.GetGroupStakes)
 	r.GET("/v1/group/:group_id/announced/users", h.GetAnnouncedGroupUsers)
 	r.GET("/v1/group/:group_id/announced/user/:sign_pubkey", h.GetAnnouncedGroupUser)
 	r.GET("/v1/group/:group_id/announced/producers", h.GetAnnouncedGroupProducer)
 	r.GET("/v1/group/:group_id/seed", h.GetGroupSeedHandler)
+	r.GET("/v1/group/:group_id/syncprogress", h.GetSyncProgress)
+	r.GET("/v1/group/:group_id/mempool", h.GetMempool)
+	r.POST("/v1/group/:group_id/verify", h.VerifyChain)
+	r.GET("/v1/policy", h.GetOpaPolicy)
+	r.POST("/v1/policy/dryrun", h.OpaDryRun)
+	r.GET("/v1/group/:group_id/export", h.ExportGroupArchive)
+	r.POST("/v1/group/:group_id/import", h.ImportGroupArchive)
 
 	// start https or http server
 	host := config.APIHost
@@ -127,12 +131,8 @@ func StartFullNodeServer(config StartServerParam, signalch chan os.Signal, h *Ha
 	e := utils.NewEcho(config.IsDebug)
 	customJWTConfig := appapi.CustomJWTConfig(nodeopt.JWT.Key)
 	e.Use(middleware.JWTWithConfig(customJWTConfig))
-	e.Use(rummiddleware.OpaWithConfig(rummiddleware.OpaConfig{
-		Skipper:   rummiddleware.JWTSkipper,
-		Policy:    policyStr,
-		Query:     "x = data.quorum.restapi.authz.allow", // FIXME: hardcode
-		InputFunc: opaInputFunc,
-	}))
+	e.Use(opaMiddleware(nodeopt, rummiddleware.JWTSkipper))
+	e.Use(jwtLimitMiddleware(nodeopt, rummiddleware.JWTSkipper))
 
 	// prometheus metric
 	e.GET("/metrics", h.Metrics)
@@ -155,6 +155,8 @@ func StartFullNodeServer(config StartServerParam, signalch chan os.Signal, h *Ha
 	r.POST("/v1/group/producer", h.GroupProducer)
 	r.POST("/v1/group/user", h.GroupUser)
 	r.POST("/v1/group/announce", h.Announce)
+	r.POST("/v1/group/stake", h.GroupStake)
+	r.POST("/v1/trx/ack", h.PubQueueAck)
 
 	r.GET("/v1/node", h.GetNodeInfo)
 	r.GET("/v1/network", h.GetNetwork(&node.Host, node.Info, nodeopt, ethaddr))
@@ -162,18 +164,46 @@ func StartFullNodeServer(config StartServerParam, signalch chan os.Signal, h *Ha
 	//r.GET("/v1/network/peers/ping", h.PingPeers(node))
 	r.GET("/v1/block/:group_id/:block_id", h.GetBlock)
 	r.GET("/v1/trx/:group_id/:trx_id", h.GetTrx)
+	r.GET("/v1/trx/:group_id/:trx_id/block", h.GetTrxBlock)
+	r.GET("/v1/group/:group_id/blocks", h.GetBlocks)
+	r.GET("/v1/group/:group_id/trxs", h.GetGroupTrxs)
 	r.GET("/v1/groups", h.GetGroups)
 	r.GET("/v1/group/:group_id", h.GetGroupById)
 	r.GET("/v1/group/:group_id/trx/allowlist", h.GetChainTrxAllowList)
 	r.GET("/v1/group/:group_id/trx/denylist", h.GetChainTrxDenyList)
 	r.GET("/v1/group/:group_id/trx/auth/:trx_type", h.GetChainTrxAuthMode)
 	r.GET("/v1/group/:group_id/producers", h.GetGroupProducers)
+	r.GET("/v1/group/:group_id/stakes", h.GetGroupStakes)
 	r.GET("/v1/group/:group_id/announced/users", h.GetAnnouncedGroupUsers)
 	r.GET("/v1/group/:group_id/announced/user/:sign_pubkey", h.GetAnnouncedGroupUser)
 	r.GET("/v1/group/:group_id/announced/producers", h.GetAnnouncedGroupProducer)
 	r.GET("/v1/group/:group_id/appconfig/keylist", h.GetAppConfigKey)
 	r.GET("/v1/group/:group_id/appconfig/:key", h.GetAppConfigItem)
 	r.GET("/v1/group/:group_id/seed", h.GetGroupSeedHandler)
+	r.GET("/v1/group/:group_id/pubqueue", h.GetPubQueue)
+	r.GET("/v1/group/:group_id/syncprogress", h.GetSyncProgress)
+	r.GET("/v1/group/:group_id/mempool", h.GetMempool)
+	r.GET("/v1/group/:group_id/retention", h.GetRetention)
+	r.POST("/v1/group/:group_id/retention", h.SetRetention)
+	r.POST("/v1/group/:group_id/verify", h.VerifyChain)
+	r.GET("/v1/policy", h.GetOpaPolicy)
+	r.POST("/v1/policy/dryrun", h.OpaDryRun)
+	r.GET("/v1/group/:group_id/export", h.ExportGroupArchive)
+	r.POST("/v1/group/:group_id/import", h.ImportGroupArchive)
+	r.POST("/v1/group/:group_id/webhook", h.AddWebhook)
+	r.GET("/v1/group/:group_id/webhooks", h.GetWebhooks)
+	r.DELETE("/v1/group/:group_id/webhook/:webhook_id", h.RemoveWebhook)
+	r.POST("/v1/group/:group_id/webhook/:webhook_id/replay", h.ReplayWebhook)
+	r.GET("/v1/group/:group_id/webhook/:webhook_id/deliveries", h.GetWebhookDeliveries)
+	r.POST("/v1/group/:group_id/file/upload", h.CreateUpload)
+	r.GET("/v1/group/:group_id/file/upload/:upload_id", h.GetUpload)
+	r.PUT("/v1/group/:group_id/file/upload/:upload_id/:index", h.UploadChunk)
+	r.POST("/v1/group/:group_id/file/upload/:upload_id/complete", h.CompleteUpload)
+	r.DELETE("/v1/group/:group_id/file/upload/:upload_id", h.RemoveUpload)
+	r.GET("/v1/group/:group_id/files", h.GetGroupFiles)
+	r.GET("/v1/group/:group_id/file/:file_id", h.GetFile)
+	r.POST("/v1/group/:group_id/file/:file_id/download", h.DownloadFile)
+	r.GET("/v1/group/:group_id/file/:file_id/content", h.GetFileContent)
 
 	//app api
 	a.POST("/v1/token", apph.CreateToken)
@@ -181,8 +211,10 @@ func StartFullNodeServer(config StartServerParam, signalch chan os.Signal, h *Ha
 	a.POST("/v1/token/refresh", apph.RefreshToken)
 	a.POST("/v1/token/revoke", apph.RevokeToken)
 	a.GET("/v1/token/list", apph.ListToken)
+	a.GET("/v1/token/audit", apph.GetTokenAuditLogs)
 
 	a.GET("/v1/group/:group_id/content", apph.ContentByPeers)
+	a.GET("/v1/group/:group_id/search", apph.SearchGroupContent)
 
 	if nodeopt.EnableRelay {
 		r.POST("/v1/network/relay", h.AddRelayServers)
@@ -193,6 +225,7 @@ func StartFullNodeServer(config StartServerParam, signalch chan os.Signal, h *Ha
 
 	// websocket
 	r.GET("/v1/ws/trx", h.WebsocketManager.WsConnect)
+	r.GET("/v1/ws/sync", h.SyncProgressWs)
 
 	//for nodesdk
 	{
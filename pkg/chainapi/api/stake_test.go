@@ -0,0 +1,111 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
+)
+
+func groupStake(api string, payload handlers.StakeParam) (*handlers.StakeResult, error) {
+	var result handlers.StakeResult
+	if _, _, err := requestAPI(api, "/api/v1/group/stake", "POST", payload, nil, &result, false); err != nil {
+		return nil, err
+	}
+	if result.GroupId != payload.GroupId || result.Amount != payload.Amount {
+		return nil, fmt.Errorf("stake result not match, payload: %+v, result: %+v", payload, result)
+	}
+	return &result, nil
+}
+
+func getGroupStakes(api string, groupID string) ([]*handlers.StakeListItem, error) {
+	path := fmt.Sprintf("/api/v1/group/%s/stakes", groupID)
+	var result []*handlers.StakeListItem
+	if _, _, err := requestAPI(api, path, "GET", nil, nil, &result, true); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func TestGroupStake(t *testing.T) {
+	t.Parallel()
+
+	// create group
+	createGroupParam := handlers.CreateGroupParam{
+		GroupName:      "test-stake",
+		ConsensusType:  "pos",
+		EncryptionType: "public",
+		AppKey:         "default",
+	}
+	group, err := createGroup(peerapi, createGroupParam)
+	if err != nil {
+		t.Fatalf("createGroup failed: %s, payload: %+v", err, createGroupParam)
+	}
+
+	// owner stakes for itself
+	stakeParam := handlers.StakeParam{
+		GroupId: group.GroupId,
+		Action:  "add",
+		Amount:  100,
+		Memo:    "stake testing",
+	}
+	result, err := groupStake(peerapi, stakeParam)
+	if err != nil {
+		t.Fatalf("groupStake failed: %s, payload: %+v", err, stakeParam)
+	}
+	if result.StakerPubkey == "" {
+		t.Errorf("staker pubkey should default to the owner")
+	}
+
+	time.Sleep(25 * time.Second)
+
+	stakes, err := getGroupStakes(peerapi, group.GroupId)
+	if err != nil {
+		t.Fatalf("getGroupStakes failed: %s", err)
+	}
+	found := false
+	for _, stake := range stakes {
+		if stake.StakerPubkey == result.StakerPubkey && stake.Amount == stakeParam.Amount {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("stake of %s not found in %+v", result.StakerPubkey, stakes)
+	}
+
+	// a user can not add stake
+	joinGroupParam := handlers.JoinGroupParamV2{
+		Seed: group.Seed,
+	}
+	if _, err := joinGroup(peerapi2, joinGroupParam); err != nil {
+		t.Fatalf("joinGroup failed: %s, payload: %+v", err, joinGroupParam)
+	}
+	if _, err := groupStake(peerapi2, stakeParam); err == nil {
+		t.Errorf("stake added by a user, not the group owner")
+	}
+}
+
+func TestGroupStakeNotPos(t *testing.T) {
+	t.Parallel()
+
+	createGroupParam := handlers.CreateGroupParam{
+		GroupName:      "test-stake-poa",
+		ConsensusType:  "poa",
+		EncryptionType: "public",
+		AppKey:         "default",
+	}
+	group, err := createGroup(peerapi, createGroupParam)
+	if err != nil {
+		t.Fatalf("createGroup failed: %s, payload: %+v", err, createGroupParam)
+	}
+
+	stakeParam := handlers.StakeParam{
+		GroupId: group.GroupId,
+		Action:  "add",
+		Amount:  100,
+	}
+	if _, err := groupStake(peerapi, stakeParam); err == nil {
+		t.Errorf("stake added to a poa group")
+	}
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	chaindef "github.com/rumsystem/quorum/internal/pkg/chainsdk/def"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	"github.com/rumsystem/quorum/internal/pkg/utils"
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
+)
+
+var SYNC_PROGRESS_WS_INTERVAL = 1 * time.Second
+
+type SyncProgressWsParam struct {
+	GroupId string `query:"group_id" validate:"omitempty,uuid4" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+}
+
+// @Tags Groups
+// @Summary GetSyncProgress
+// @Description Get sync progress of a group, includes local top block, highest block seen from peers, blocks/sec, ETA (in seconds, -1 if unknown), current providers and recent sync task outcomes
+// @Produce json
+// @Param group_id path string true "Group Id"
+// @Success 200 {object} chaindef.SyncProgress
+// @Router /api/v1/group/{group_id}/syncprogress [get]
+func (h *Handler) GetSyncProgress(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	var params handlers.GetSyncProgressParam
+	if err := cc.BindAndValidate(&params); err != nil {
+		return err
+	}
+
+	res, err := handlers.GetSyncProgress(params.GroupId)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Tags Groups
+// @Summary SyncProgressWs
+// @Description Websocket stream of sync progress, a SyncProgress is sent when the progress of a group changed or the group is syncing
+// @Param group_id query string false "Group Id, all groups if empty"
+// @Router /api/v1/ws/sync [get]
+func (h *Handler) SyncProgressWs(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	var params SyncProgressWsParam
+	if err := cc.BindAndValidate(&params); err != nil {
+		return err
+	}
+
+	if params.GroupId != "" {
+		if _, err := handlers.GetSyncProgress(params.GroupId); err != nil {
+			return rumerrors.NewBadRequestError(err)
+		}
+	}
+
+	ws, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			_type, _, err := ws.ReadMessage()
+			if err != nil || _type == websocket.CloseMessage {
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer ws.Close()
+		ticker := time.NewTicker(SYNC_PROGRESS_WS_INTERVAL)
+		defer ticker.Stop()
+
+		lastSent := make(map[string]*chaindef.SyncProgress)
+		for {
+			select {
+			case <-done:
+				wsLogger.Debugf("sync progress client disconnect")
+				return
+			case <-ticker.C:
+				var progresses []*chaindef.SyncProgress
+				if params.GroupId != "" {
+					progress, err := handlers.GetSyncProgress(params.GroupId)
+					if err != nil {
+						//group left
+						ws.WriteMessage(websocket.CloseMessage, []byte{})
+						return
+					}
+					progresses = append(progresses, progress)
+				} else {
+					progresses = handlers.GetAllSyncProgress()
+				}
+
+				for _, progress := range progresses {
+					if !isSyncProgressChanged(lastSent[progress.GroupId], progress) {
+						continue
+					}
+					if err := ws.WriteJSON(progress); err != nil {
+						wsLogger.Debugf("write sync progress failed: %s", err)
+						return
+					}
+					lastSent[progress.GroupId] = progress
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func isSyncProgressChanged(last *chaindef.SyncProgress, curr *chaindef.SyncProgress) bool {
+	if last == nil || curr.Status == "SYNCING" {
+		return true
+	}
+	return last.Status != curr.Status ||
+		last.LocalTopBlock != curr.LocalTopBlock ||
+		last.HighestBlockSeen != curr.HighestBlockSeen ||
+		lastOutcomeAt(last) != lastOutcomeAt(curr)
+}
+
+func lastOutcomeAt(progress *chaindef.SyncProgress) int64 {
+	if len(progress.History) == 0 {
+		return 0
+	}
+	return progress.History[len(progress.History)-1].TimeStamp
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	chaindef "github.com/rumsystem/quorum/internal/pkg/chainsdk/def"
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
+)
+
+func getSyncProgress(api string, groupID string) (*chaindef.SyncProgress, error) {
+	path := fmt.Sprintf("/api/v1/group/%s/syncprogress", groupID)
+	var progress chaindef.SyncProgress
+	if _, _, err := requestAPI(api, path, "GET", nil, nil, &progress, true); err != nil {
+		return nil, err
+	}
+	if progress.GroupId != groupID {
+		return nil, fmt.Errorf("group id not match, expect: %s, actual: %s", groupID, progress.GroupId)
+	}
+	return &progress, nil
+}
+
+func TestGetSyncProgress(t *testing.T) {
+	t.Parallel()
+
+	// create group
+	createGroupParam := handlers.CreateGroupParam{
+		GroupName:      "test-sync-progress",
+		ConsensusType:  "poa",
+		EncryptionType: "public",
+		AppKey:         "default",
+	}
+	group, err := createGroup(peerapi, createGroupParam)
+	if err != nil {
+		t.Fatalf("createGroup failed: %s, payload: %+v", err, createGroupParam)
+	}
+
+	progress, err := getSyncProgress(peerapi, group.GroupId)
+	if err != nil {
+		t.Fatalf("getSyncProgress failed: %s", err)
+	}
+	if progress.HighestBlockSeen < progress.LocalTopBlock {
+		t.Errorf("highest block seen %d is lower than local top block %d", progress.HighestBlockSeen, progress.LocalTopBlock)
+	}
+	if progress.TimeStamp == 0 {
+		t.Errorf("sync progress without timestamp")
+	}
+
+	if _, err := getSyncProgress(peerapi, "ac0eea7c-2f3c-4c67-80b3-136e46b924a8"); err == nil {
+		t.Errorf("got sync progress of a group not joined")
+	}
+}
+
+// TestJoinedGroupSynced checks a node joined later catches up with the owner,
+// the joined node applies the group state from snapshots of producers and blocks from its peers
+func TestJoinedGroupSynced(t *testing.T) {
+	t.Parallel()
+
+	// create group and post before the other node joins
+	createGroupParam := handlers.CreateGroupParam{
+		GroupName:      "test-join-sync",
+		ConsensusType:  "poa",
+		EncryptionType: "public",
+		AppKey:         "default",
+	}
+	group, err := createGroup(peerapi, createGroupParam)
+	if err != nil {
+		t.Fatalf("createGroup failed: %s, payload: %+v", err, createGroupParam)
+	}
+
+	postGroupParam := PostGroupParam{
+		Data: map[string]interface{}{
+			"type":    "Note",
+			"content": "Hello World",
+			"name":    "join sync testing",
+		},
+		GroupID: group.GroupId,
+	}
+	if _, err := postToGroup(peerapi, postGroupParam); err != nil {
+		t.Fatalf("postToGroup failed: %s, payload: %+v", err, postGroupParam)
+	}
+
+	time.Sleep(25 * time.Second)
+
+	ownerProgress, err := getSyncProgress(peerapi, group.GroupId)
+	if err != nil {
+		t.Fatalf("getSyncProgress failed: %s", err)
+	}
+	if ownerProgress.LocalTopBlock == 0 {
+		t.Fatalf("no block produced for the post")
+	}
+
+	// join group
+	joinGroupParam := handlers.JoinGroupParamV2{
+		Seed: group.Seed,
+	}
+	if _, err := joinGroup(peerapi2, joinGroupParam); err != nil {
+		t.Fatalf("joinGroup failed: %s, payload: %+v", err, joinGroupParam)
+	}
+
+	time.Sleep(25 * time.Second)
+
+	progress, err := getSyncProgress(peerapi2, group.GroupId)
+	if err != nil {
+		t.Fatalf("getSyncProgress failed: %s", err)
+	}
+	if progress.LocalTopBlock < ownerProgress.LocalTopBlock {
+		t.Errorf("joined node synced to block %d, owner is on block %d, status: %s", progress.LocalTopBlock, ownerProgress.LocalTopBlock, progress.Status)
+	}
+}
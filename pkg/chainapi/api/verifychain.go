@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	"github.com/rumsystem/quorum/internal/pkg/utils"
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
+)
+
+// @Tags Groups
+// @Summary VerifyChain
+// @Description Verify blocks of a group from genesis: linkage, hashes, producer and trx signatures, then cross-check producers, users and app config against a replay of trxs. Mismatched derived tables are rewritten if repair is true, broken blocks are only reported
+// @Accept json
+// @Produce json
+// @Param group_id path string true "Group Id"
+// @Param data body handlers.VerifyChainParam true "verify params"
+// @Success 200 {object} def.ChainVerifyResult
+// @Router /api/v1/group/{group_id}/verify [post]
+func (h *Handler) VerifyChain(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	params := new(handlers.VerifyChainParam)
+	if err := cc.BindAndValidate(params); err != nil {
+		return err
+	}
+
+	res, err := handlers.VerifyChain(params)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	chaindef "github.com/rumsystem/quorum/internal/pkg/chainsdk/def"
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
+)
+
+func verifyChain(api string, groupID string, repair bool) (*chaindef.ChainVerifyResult, error) {
+	path := fmt.Sprintf("/api/v1/group/%s/verify", groupID)
+	payload := handlers.VerifyChainParam{Repair: repair}
+	var result chaindef.ChainVerifyResult
+	if _, _, err := requestAPI(api, path, "POST", payload, nil, &result, true); err != nil {
+		return nil, err
+	}
+	if result.GroupId != groupID {
+		return nil, fmt.Errorf("group id not match, expect: %s, actual: %s", groupID, result.GroupId)
+	}
+	return &result, nil
+}
+
+func TestVerifyChain(t *testing.T) {
+	t.Parallel()
+
+	// create group
+	createGroupParam := handlers.CreateGroupParam{
+		GroupName:      "test-verify-chain",
+		ConsensusType:  "poa",
+		EncryptionType: "public",
+		AppKey:         "default",
+	}
+	group, err := createGroup(peerapi, createGroupParam)
+	if err != nil {
+		t.Fatalf("createGroup failed: %s, payload: %+v", err, createGroupParam)
+	}
+
+	// post to group
+	postGroupParam := PostGroupParam{
+		Data: map[string]interface{}{
+			"type":    "Note",
+			"content": "Hello World",
+			"name":    "verify chain testing",
+		},
+		GroupID: group.GroupId,
+	}
+	if _, err := postToGroup(peerapi, postGroupParam); err != nil {
+		t.Fatalf("postToGroup failed: %s, payload: %+v", err, postGroupParam)
+	}
+
+	time.Sleep(25 * time.Second)
+
+	result, err := verifyChain(peerapi, group.GroupId, false)
+	if err != nil {
+		t.Fatalf("verifyChain failed: %s", err)
+	}
+	if result.TopBlock == 0 || result.CheckedBlocks < result.TopBlock || result.CheckedTrxs == 0 {
+		t.Errorf("top block: %d, checked %d blocks and %d trxs", result.TopBlock, result.CheckedBlocks, result.CheckedTrxs)
+	}
+	if !result.StateChecked {
+		t.Errorf("state of an unpruned chain is not checked")
+	}
+	for _, issue := range result.Issues {
+		t.Errorf("issue of a healthy chain: block %d, %s: %s", issue.BlockId, issue.Kind, issue.Detail)
+	}
+	if len(result.Repaired) != 0 {
+		t.Errorf("tables repaired without repair: %v", result.Repaired)
+	}
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	"github.com/rumsystem/quorum/internal/pkg/utils"
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
+)
+
+// @Tags Groups
+// @Summary AddWebhook
+// @Description Register a webhook, on chain trxs of the group are posted to the url with a HMAC-SHA256 signature in X-Quorum-Signature header
+// @Accept json
+// @Produce json
+// @Param group_id path string true "Group Id"
+// @Param data body handlers.AddWebhookParam true "webhook params"
+// @Success 200 {object} appdata.Webhook
+// @Router /api/v1/group/{group_id}/webhook [post]
+func (h *Handler) AddWebhook(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	params := new(handlers.AddWebhookParam)
+	if err := cc.BindAndValidate(params); err != nil {
+		return err
+	}
+
+	res, err := handlers.AddWebhook(params, h.Appdb)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Tags Groups
+// @Summary GetWebhooks
+// @Description Get webhooks of a group and their delivery cursor
+// @Produce json
+// @Param group_id path string true "Group Id"
+// @Success 200 {object} handlers.WebhookListResult
+// @Router /api/v1/group/{group_id}/webhooks [get]
+func (h *Handler) GetWebhooks(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	params := new(handlers.GroupArchiveParam)
+	if err := cc.BindAndValidate(params); err != nil {
+		return err
+	}
+
+	res, err := handlers.GetWebhooks(params.GroupId, h.Appdb)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Tags Groups
+// @Summary RemoveWebhook
+// @Description Remove a webhook and its delivery logs
+// @Produce json
+// @Param group_id path string true "Group Id"
+// @Param webhook_id path string true "Webhook Id"
+// @Success 200 {object} utils.SuccessResponse
+// @Router /api/v1/group/{group_id}/webhook/{webhook_id} [delete]
+func (h *Handler) RemoveWebhook(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	params := new(handlers.WebhookParam)
+	if err := cc.BindAndValidate(params); err != nil {
+		return err
+	}
+
+	if err := handlers.RemoveWebhook(params, h.Appdb); err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return cc.Success()
+}
+
+// @Tags Groups
+// @Summary ReplayWebhook
+// @Description Move the delivery cursor of a webhook to the first trx of a block, trxs from the block will be delivered again
+// @Accept json
+// @Produce json
+// @Param group_id path string true "Group Id"
+// @Param webhook_id path string true "Webhook Id"
+// @Param data body handlers.ReplayWebhookParam true "replay params"
+// @Success 200 {object} appdata.Webhook
+// @Router /api/v1/group/{group_id}/webhook/{webhook_id}/replay [post]
+func (h *Handler) ReplayWebhook(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	params := new(handlers.ReplayWebhookParam)
+	if err := cc.BindAndValidate(params); err != nil {
+		return err
+	}
+
+	res, err := handlers.ReplayWebhook(params, h.Appdb)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Tags Groups
+// @Summary GetWebhookDeliveries
+// @Description Get delivery logs of a webhook, the latest one comes first
+// @Produce json
+// @Param group_id path string true "Group Id"
+// @Param webhook_id path string true "Webhook Id"
+// @Param state query string false "SUCCESS, RETRY, FAIL or PRUNED"
+// @Param num query int false "number of logs, default 100"
+// @Success 200 {object} handlers.WebhookDeliveriesResult
+// @Router /api/v1/group/{group_id}/webhook/{webhook_id}/deliveries [get]
+func (h *Handler) GetWebhookDeliveries(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	params := new(handlers.GetWebhookDeliveriesParam)
+	if err := cc.BindAndValidate(params); err != nil {
+		return err
+	}
+
+	res, err := handlers.GetWebhookDeliveries(params, h.Appdb)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rumsystem/quorum/internal/pkg/appdata"
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
+)
+
+func addWebhook(api string, groupID string, payload handlers.AddWebhookParam) (*appdata.Webhook, error) {
+	path := fmt.Sprintf("/api/v1/group/%s/webhook", groupID)
+	var webhook appdata.Webhook
+	if _, _, err := requestAPI(api, path, "POST", payload, nil, &webhook, true); err != nil {
+		return nil, err
+	}
+	if webhook.Id == "" || webhook.GroupId != groupID {
+		return nil, fmt.Errorf("invalid webhook: %+v", webhook)
+	}
+	return &webhook, nil
+}
+
+func getWebhooks(api string, groupID string) (*handlers.WebhookListResult, error) {
+	path := fmt.Sprintf("/api/v1/group/%s/webhooks", groupID)
+	var result handlers.WebhookListResult
+	if _, _, err := requestAPI(api, path, "GET", nil, nil, &result, true); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func getWebhookDeliveries(api string, groupID string, webhookID string) (*handlers.WebhookDeliveriesResult, error) {
+	path := fmt.Sprintf("/api/v1/group/%s/webhook/%s/deliveries", groupID, webhookID)
+	var result handlers.WebhookDeliveriesResult
+	if _, _, err := requestAPI(api, path, "GET", nil, nil, &result, true); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// webhookReceiver records events posted to it, events with a bad signature are rejected
+type webhookReceiver struct {
+	mu     sync.Mutex
+	secret string
+	events []*appdata.WebhookEvent
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	timestamp := req.Header.Get(appdata.WEBHOOK_HEADER_TIMESTAMP)
+	if req.Header.Get(appdata.WEBHOOK_HEADER_SIGNATURE) != "sha256="+appdata.WebhookSignature(r.secret, timestamp, body) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var event appdata.WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.mu.Lock()
+	r.events = append(r.events, &event)
+	r.mu.Unlock()
+}
+
+func (r *webhookReceiver) trxIds() map[string]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make(map[string]int)
+	for _, event := range r.events {
+		ids[event.Trx.TrxId]++
+	}
+	return ids
+}
+
+func TestWebhook(t *testing.T) {
+	t.Parallel()
+
+	// create group
+	createGroupParam := handlers.CreateGroupParam{
+		GroupName:      "test-webhook",
+		ConsensusType:  "poa",
+		EncryptionType: "public",
+		AppKey:         "default",
+	}
+	group, err := createGroup(peerapi, createGroupParam)
+	if err != nil {
+		t.Fatalf("createGroup failed: %s, payload: %+v", err, createGroupParam)
+	}
+
+	receiver := &webhookReceiver{secret: "webhook-test-secret"}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	fromBlock := uint64(0)
+	addParam := handlers.AddWebhookParam{
+		Url:       server.URL,
+		TrxTypes:  []string{"POST"},
+		Secret:    receiver.secret,
+		FromBlock: &fromBlock,
+	}
+	webhook, err := addWebhook(peerapi, group.GroupId, addParam)
+	if err != nil {
+		t.Fatalf("addWebhook failed: %s, payload: %+v", err, addParam)
+	}
+
+	// secret is only returned when added
+	webhooks, err := getWebhooks(peerapi, group.GroupId)
+	if err != nil {
+		t.Fatalf("getWebhooks failed: %s", err)
+	}
+	if len(webhooks.Webhooks) != 1 || webhooks.Webhooks[0].Id != webhook.Id || webhooks.Webhooks[0].Secret != "" {
+		t.Errorf("webhooks of group: %+v", webhooks.Webhooks)
+	}
+
+	// post to group
+	postGroupParam := PostGroupParam{
+		Data: map[string]interface{}{
+			"type":    "Note",
+			"content": "Hello World",
+			"name":    "webhook testing",
+		},
+		GroupID: group.GroupId,
+	}
+	postResult, err := postToGroup(peerapi, postGroupParam)
+	if err != nil {
+		t.Fatalf("postToGroup failed: %s, payload: %+v", err, postGroupParam)
+	}
+
+	time.Sleep(25 * time.Second)
+
+	if n := receiver.trxIds()[postResult.TrxId]; n != 1 {
+		t.Errorf("trx %s delivered %d times, expect once", postResult.TrxId, n)
+	}
+	deliveries, err := getWebhookDeliveries(peerapi, group.GroupId, webhook.Id)
+	if err != nil {
+		t.Fatalf("getWebhookDeliveries failed: %s", err)
+	}
+	delivered := false
+	for _, delivery := range deliveries.Deliveries {
+		if delivery.TrxId == postResult.TrxId && delivery.State == "SUCCESS" {
+			delivered = true
+		}
+	}
+	if !delivered {
+		t.Errorf("no successful delivery of trx %s: %+v", postResult.TrxId, deliveries.Deliveries)
+	}
+
+	// replay from genesis delivers the post again
+	replayPath := fmt.Sprintf("/api/v1/group/%s/webhook/%s/replay", group.GroupId, webhook.Id)
+	replayParam := handlers.ReplayWebhookParam{FromBlock: 0}
+	var replayed appdata.Webhook
+	if _, _, err := requestAPI(peerapi, replayPath, "POST", replayParam, nil, &replayed, true); err != nil {
+		t.Fatalf("replay webhook failed: %s", err)
+	}
+
+	time.Sleep(5 * time.Second)
+
+	if n := receiver.trxIds()[postResult.TrxId]; n != 2 {
+		t.Errorf("trx %s delivered %d times after replay, expect twice", postResult.TrxId, n)
+	}
+
+	// remove webhook
+	removePath := fmt.Sprintf("/api/v1/group/%s/webhook/%s", group.GroupId, webhook.Id)
+	if _, _, err := requestAPI(peerapi, removePath, "DELETE", nil, nil, nil, true); err != nil {
+		t.Fatalf("remove webhook failed: %s", err)
+	}
+	webhooks, err = getWebhooks(peerapi, group.GroupId)
+	if err != nil {
+		t.Fatalf("getWebhooks failed: %s", err)
+	}
+	if len(webhooks.Webhooks) != 0 {
+		t.Errorf("webhook %s is not removed", webhook.Id)
+	}
+}
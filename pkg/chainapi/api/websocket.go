@@ -1,6 +1,8 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
 	"sync"
 	"time"
@@ -11,6 +13,7 @@ import (
 	"github.com/rumsystem/quorum/internal/pkg/appdata"
 	chain "github.com/rumsystem/quorum/internal/pkg/chainsdk/core"
 	"github.com/rumsystem/quorum/internal/pkg/logging"
+	"github.com/rumsystem/quorum/internal/pkg/nodectx"
 	quorumpb "github.com/rumsystem/quorum/pkg/pb"
 )
 
@@ -20,6 +23,16 @@ const (
 	maxOnChainTrxs          = 1024
 )
 
+var (
+	WS_WRITE_WAIT            = 10 * time.Second       // time allowed to write a message
+	WS_PONG_WAIT             = 60 * time.Second       // time allowed to read the next pong
+	WS_PING_PERIOD           = WS_PONG_WAIT * 9 / 10  // send pings with this period, must be less than WS_PONG_WAIT
+	WS_POLL_INTERVAL         = 500 * time.Millisecond // interval to check new blocks for subscribed clients
+	WS_SLOW_CONSUMER_TIMEOUT = 10 * time.Second       // client is dropped if its buffer is full for this long
+	WS_MAX_EVENTS_PER_POLL   = 1000                   // events replayed to a client in each poll
+	wsMaxMessageSize         = int64(64 * 1024)       // max size of message from client
+)
+
 var (
 	wsLogger = logging.Logger("websocket")
 
@@ -28,6 +41,8 @@ var (
 		WriteBufferSize: 1024,
 		CheckOrigin:     func(r *http.Request) bool { return true },
 	}
+
+	errSlowConsumer = errors.New("slow consumer")
 )
 
 type (
@@ -38,10 +53,58 @@ type (
 		UnRegister chan *Client
 	}
 
+	// Client receives all on chain trxs as they are, until it subscribes, after that it only receives
+	// WsTrxEvent matching the subscription, replayed from storage from the cursor of each group
 	Client struct {
 		Id              string
 		Socket          *websocket.Conn
-		OnChainTrxChann chan *quorumpb.Trx
+		OnChainTrxChann chan interface{}
+
+		mu           sync.Mutex
+		subscription *wsSubscription
+		done         chan struct{}
+		closeOnce    sync.Once
+	}
+
+	// WsSubscribeMsg is sent by client to subscribe, a new one replaces the previous subscription
+	WsSubscribeMsg struct {
+		Action   string          `json:"action" example:"subscribe"`
+		Groups   []WsGroupCursor `json:"groups"`                                                                 // empty for all groups, from their next block
+		TrxTypes []string        `json:"trx_types" example:"POST"`                                               // empty for all
+		Senders  []string        `json:"senders" example:"CAISIQOlA37+ghb05D5ZAKExjsto/H7eeCmkagcZ+BY/pjSOKw=="` // empty for all
+	}
+
+	// WsGroupCursor is where to resume a group, the next event is the trx at FromIndex of block FromBlock
+	WsGroupCursor struct {
+		GroupId   string  `json:"group_id" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+		FromBlock *uint64 `json:"from_block" example:"100"` // empty for the next block
+		FromIndex int     `json:"from_index" example:"0"`
+	}
+
+	// WsTrxEvent is sent to subscribed clients, client should save block_id and trx_index+1 as the cursor to resume
+	WsTrxEvent struct {
+		GroupId  string        `json:"group_id"`
+		BlockId  uint64        `json:"block_id"`
+		TrxIndex int           `json:"trx_index"`
+		Trx      *quorumpb.Trx `json:"trx"`
+	}
+
+	// WsReply is sent to client for a subscribe message
+	WsReply struct {
+		Action string          `json:"action" example:"subscribed"` // subscribed or error
+		Groups []WsGroupCursor `json:"groups,omitempty"`
+		Error  string          `json:"error,omitempty"`
+	}
+
+	wsSubscription struct {
+		cursors  map[string]*wsCursor
+		trxTypes map[string]bool
+		senders  map[string]bool
+	}
+
+	wsCursor struct {
+		nextBlock uint64
+		nextIndex int
 	}
 )
 
@@ -74,7 +137,7 @@ func (manager *WebsocketManager) register() {
 			wsLogger.Debugf("client %s connected", c.Id)
 			manager.RegisterClient(c)
 		case c := <-manager.UnRegister:
-			c.Socket.Close()
+			c.close()
 
 			manager.UnRegisterClient(c)
 		}
@@ -108,9 +171,25 @@ func (manager *WebsocketManager) handleEvent(event *appdata.OnChainTrxEvent) {
 		return
 	}
 
+	manager.Lock.Lock()
+	clients := make([]*Client, 0, len(manager.Clients))
 	for _, c := range manager.Clients {
+		clients = append(clients, c)
+	}
+	manager.Lock.Unlock()
+
+	for _, c := range clients {
+		if c.isSubscribed() {
+			continue
+		}
 		wsLogger.Debugf("put event %+v to client: %s", event, c.Id)
-		c.OnChainTrxChann <- trx
+		select {
+		case c.OnChainTrxChann <- trx:
+		default:
+			// never block the broadcast on a slow client
+			wsLogger.Warnf("client %s is too slow, drop it", c.Id)
+			manager.UnRegister <- c
+		}
 	}
 }
 
@@ -144,19 +223,44 @@ func (c *Client) Read(manager *WebsocketManager) {
 		manager.UnRegister <- c
 	}()
 
+	c.Socket.SetReadLimit(wsMaxMessageSize)
+	c.Socket.SetReadDeadline(time.Now().Add(WS_PONG_WAIT))
+	c.Socket.SetPongHandler(func(string) error {
+		return c.Socket.SetReadDeadline(time.Now().Add(WS_PONG_WAIT))
+	})
+
 	for {
-		_type, msg, err := c.Socket.ReadMessage()
-		if err != nil || _type == websocket.CloseMessage {
-			wsLogger.Debugf("c.Socket.ReadMessage failed: %s, type: %s", err, _type)
-			break
+		var msg WsSubscribeMsg
+		if err := c.Socket.ReadJSON(&msg); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				c.send(&WsReply{Action: "error", Error: "invalid message: " + err.Error()})
+				continue
+			}
+			wsLogger.Debugf("c.Socket.ReadJSON failed: %s, client: %s", err, c.Id)
+			return
 		}
 
-		wsLogger.Debugf("got websocket msg: %s from: %s", msg, c.Id)
+		wsLogger.Debugf("got websocket msg: %+v from: %s", msg, c.Id)
+		if msg.Action != "subscribe" {
+			c.send(&WsReply{Action: "error", Error: "unknown action: " + msg.Action})
+			continue
+		}
+
+		reply, err := c.subscribe(&msg)
+		if err != nil {
+			c.send(&WsReply{Action: "error", Error: err.Error()})
+			continue
+		}
+		c.send(reply)
 	}
 }
 
 func (c *Client) Write() error {
+	ticker := time.NewTicker(WS_PING_PERIOD)
 	defer func() {
+		ticker.Stop()
 		wsLogger.Debugf("client [%s] disconnect", c.Id)
 		if err := c.Socket.Close(); err != nil {
 			wsLogger.Debugf("client [%s] disconnect failed: %s", c.Id, err)
@@ -165,20 +269,176 @@ func (c *Client) Write() error {
 
 	for {
 		select {
-		case event, ok := <-c.OnChainTrxChann:
-			if !ok {
-				return c.Socket.WriteMessage(websocket.CloseMessage, []byte{})
-			}
-
+		case <-c.done:
+			c.Socket.SetWriteDeadline(time.Now().Add(WS_WRITE_WAIT))
+			return c.Socket.WriteMessage(websocket.CloseMessage, []byte{})
+		case event := <-c.OnChainTrxChann:
+			c.Socket.SetWriteDeadline(time.Now().Add(WS_WRITE_WAIT))
 			if err := c.Socket.WriteJSON(event); err != nil {
 				wsLogger.Debugf("client [%s] write event %+v failed: %s", c.Id, event, err)
+				c.close()
+				return err
+			}
+		case <-ticker.C:
+			c.Socket.SetWriteDeadline(time.Now().Add(WS_WRITE_WAIT))
+			if err := c.Socket.WriteMessage(websocket.PingMessage, nil); err != nil {
+				wsLogger.Debugf("client [%s] ping failed: %s", c.Id, err)
+				c.close()
 				return err
 			}
 		}
 	}
 }
 
-// websocket handler
+// send puts a message to the buffer of client, it waits at most WS_SLOW_CONSUMER_TIMEOUT for a full buffer
+func (c *Client) send(msg interface{}) error {
+	timer := time.NewTimer(WS_SLOW_CONSUMER_TIMEOUT)
+	defer timer.Stop()
+
+	select {
+	case c.OnChainTrxChann <- msg:
+		return nil
+	case <-c.done:
+		return errors.New("client closed")
+	case <-timer.C:
+		wsLogger.Warnf("client %s is too slow, drop it", c.Id)
+		c.close()
+		return errSlowConsumer
+	}
+}
+
+func (c *Client) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+func (c *Client) isSubscribed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.subscription != nil
+}
+
+func (c *Client) subscribe(msg *WsSubscribeMsg) (*WsReply, error) {
+	groupmgr := chain.GetGroupMgr()
+	sub := &wsSubscription{
+		cursors:  make(map[string]*wsCursor),
+		trxTypes: make(map[string]bool),
+		senders:  make(map[string]bool),
+	}
+	for _, t := range msg.TrxTypes {
+		if _, ok := quorumpb.TrxType_value[t]; !ok {
+			return nil, errors.New("invalid trx type: " + t)
+		}
+		sub.trxTypes[t] = true
+	}
+	for _, s := range msg.Senders {
+		sub.senders[s] = true
+	}
+
+	groups := msg.Groups
+	if len(groups) == 0 {
+		for groupId := range groupmgr.Groups {
+			groups = append(groups, WsGroupCursor{GroupId: groupId})
+		}
+	}
+
+	reply := &WsReply{Action: "subscribed"}
+	for _, g := range groups {
+		group, ok := groupmgr.Groups[g.GroupId]
+		if !ok {
+			return nil, errors.New("group not found: " + g.GroupId)
+		}
+		cursor := &wsCursor{nextBlock: group.GetCurrentBlockId() + 1}
+		if g.FromBlock != nil {
+			cursor.nextBlock = *g.FromBlock
+			cursor.nextIndex = g.FromIndex
+		}
+		sub.cursors[g.GroupId] = cursor
+
+		from := cursor.nextBlock
+		reply.Groups = append(reply.Groups, WsGroupCursor{GroupId: g.GroupId, FromBlock: &from, FromIndex: cursor.nextIndex})
+	}
+
+	c.mu.Lock()
+	first := c.subscription == nil
+	c.subscription = sub
+	c.mu.Unlock()
+
+	if first {
+		go c.pump()
+	}
+	return reply, nil
+}
+
+// pump replays events of subscribed groups from storage, and follows new blocks
+func (c *Client) pump() {
+	ticker := time.NewTicker(WS_POLL_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		c.mu.Lock()
+		sub := c.subscription
+		c.mu.Unlock()
+
+		for groupId, cursor := range sub.cursors {
+			if err := c.pumpGroup(sub, groupId, cursor); err != nil {
+				return
+			}
+		}
+
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Client) pumpGroup(sub *wsSubscription, groupId string, cursor *wsCursor) error {
+	group, ok := chain.GetGroupMgr().Groups[groupId]
+	if !ok {
+		return nil
+	}
+
+	for sent := 0; sent < WS_MAX_EVENTS_PER_POLL && cursor.nextBlock <= group.GetCurrentBlockId(); {
+		block, err := nodectx.GetNodeCtx().GetChainStorage().GetBlock(groupId, cursor.nextBlock, false, group.Nodename)
+		if err != nil {
+			wsLogger.Errorf("<%s> get block %d for client %s failed: %s", groupId, cursor.nextBlock, c.Id, err)
+			return nil
+		}
+
+		for ; cursor.nextIndex < len(block.Trxs); cursor.nextIndex++ {
+			trx := block.Trxs[cursor.nextIndex]
+			if !sub.match(trx) {
+				continue
+			}
+			event := &WsTrxEvent{GroupId: groupId, BlockId: block.BlockId, TrxIndex: cursor.nextIndex, Trx: trx}
+			if err := c.send(event); err != nil {
+				return err
+			}
+			sent++
+		}
+		cursor.nextBlock++
+		cursor.nextIndex = 0
+	}
+	return nil
+}
+
+func (s *wsSubscription) match(trx *quorumpb.Trx) bool {
+	if len(s.trxTypes) > 0 && !s.trxTypes[trx.Type.String()] {
+		return false
+	}
+	if len(s.senders) > 0 && !s.senders[trx.SenderPubkey] {
+		return false
+	}
+	return true
+}
+
+// @Tags Chain
+// @Summary WsConnect
+// @Description Websocket of on chain trxs, send a WsSubscribeMsg to filter trxs and resume from a cursor, then WsTrxEvent is sent instead of Trx
+// @Router /api/v1/ws/trx [get]
 func (manager *WebsocketManager) WsConnect(c echo.Context) error {
 	ws, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
 	if err != nil {
@@ -188,7 +448,8 @@ func (manager *WebsocketManager) WsConnect(c echo.Context) error {
 	client := &Client{
 		Id:              guuid.NewString(),
 		Socket:          ws,
-		OnChainTrxChann: make(chan *quorumpb.Trx, maxOnChainTrxs),
+		OnChainTrxChann: make(chan interface{}, maxOnChainTrxs),
+		done:            make(chan struct{}),
 	}
 
 	manager.RegisterClient(client)
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+)
+
+func TestWsSubscriptionMatch(t *testing.T) {
+	post := &quorumpb.Trx{Type: quorumpb.TrxType_POST, SenderPubkey: "alice"}
+	announce := &quorumpb.Trx{Type: quorumpb.TrxType_ANNOUNCE, SenderPubkey: "bob"}
+
+	for _, tc := range []struct {
+		name     string
+		sub      wsSubscription
+		expected []bool // post, announce
+	}{
+		{"all", wsSubscription{}, []bool{true, true}},
+		{"trx types", wsSubscription{trxTypes: map[string]bool{"POST": true}}, []bool{true, false}},
+		{"senders", wsSubscription{senders: map[string]bool{"bob": true}}, []bool{false, true}},
+		{"both", wsSubscription{trxTypes: map[string]bool{"POST": true}, senders: map[string]bool{"bob": true}}, []bool{false, false}},
+	} {
+		for i, trx := range []*quorumpb.Trx{post, announce} {
+			if tc.sub.match(trx) != tc.expected[i] {
+				t.Errorf("%s: match %s of %s, expect %v", tc.name, trx.Type, trx.SenderPubkey, tc.expected[i])
+			}
+		}
+	}
+}
+
+// wsMessage is either a WsReply or a WsTrxEvent
+type wsMessage struct {
+	WsReply
+	WsTrxEvent
+}
+
+func wsSubscribe(api string, msg *WsSubscribeMsg) (*websocket.Conn, *WsReply, error) {
+	wsUrl := strings.Replace(api, "http", "ws", 1) + "/api/v1/ws/trx"
+	conn, _, err := websocket.DefaultDialer.Dial(wsUrl, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := conn.WriteJSON(msg); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	for {
+		m, err := wsRead(conn, 10*time.Second)
+		if err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+		if m.Action != "" {
+			return conn, &m.WsReply, nil
+		}
+	}
+}
+
+func wsRead(conn *websocket.Conn, timeout time.Duration) (*wsMessage, error) {
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	m := &wsMessage{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("unmarshal %s failed: %s", data, err)
+	}
+	return m, nil
+}
+
+// wsReadEvents reads events until n events are received or timeout
+func wsReadEvents(conn *websocket.Conn, n int, timeout time.Duration) []WsTrxEvent {
+	events := []WsTrxEvent{}
+	deadline := time.Now().Add(timeout)
+	for len(events) < n && time.Now().Before(deadline) {
+		m, err := wsRead(conn, time.Until(deadline))
+		if err != nil {
+			break
+		}
+		if m.Trx != nil {
+			events = append(events, m.WsTrxEvent)
+		}
+	}
+	return events
+}
+
+func TestWsSubscribeFilterAndResume(t *testing.T) {
+	t.Parallel()
+
+	createGroupParam := handlers.CreateGroupParam{
+		GroupName:      "test-ws-subscribe",
+		ConsensusType:  "poa",
+		EncryptionType: "public",
+		AppKey:         "default",
+	}
+	group, err := createGroup(peerapi, createGroupParam)
+	if err != nil {
+		t.Fatalf("createGroup failed: %s, payload: %+v", err, createGroupParam)
+	}
+
+	trxIds := []string{}
+	for i := 0; i < 2; i++ {
+		postGroupParam := PostGroupParam{
+			Data: map[string]interface{}{
+				"type":    "Note",
+				"content": fmt.Sprintf("ws post %d", i),
+				"name":    "ws testing",
+			},
+			GroupID: group.GroupId,
+		}
+		trx, err := postToGroup(peerapi, postGroupParam)
+		if err != nil {
+			t.Fatalf("postToGroup failed: %s, payload: %+v", err, postGroupParam)
+		}
+		trxIds = append(trxIds, trx.TrxId)
+	}
+
+	time.Sleep(25 * time.Second)
+
+	// invalid trx type is rejected
+	conn, reply, err := wsSubscribe(peerapi, &WsSubscribeMsg{Action: "subscribe", TrxTypes: []string{"NOT_A_TYPE"}})
+	if err != nil {
+		t.Fatalf("wsSubscribe failed: %s", err)
+	}
+	conn.Close()
+	if reply.Action != "error" {
+		t.Errorf("subscribe with invalid trx type got reply %+v", reply)
+	}
+
+	// replay posts from the genesis block
+	from := uint64(0)
+	msg := &WsSubscribeMsg{
+		Action:   "subscribe",
+		Groups:   []WsGroupCursor{{GroupId: group.GroupId, FromBlock: &from}},
+		TrxTypes: []string{"POST"},
+	}
+	conn, reply, err = wsSubscribe(peerapi, msg)
+	if err != nil {
+		t.Fatalf("wsSubscribe failed: %s", err)
+	}
+	if reply.Action != "subscribed" {
+		conn.Close()
+		t.Fatalf("subscribe got reply %+v", reply)
+	}
+	events := wsReadEvents(conn, 2, 10*time.Second)
+	conn.Close()
+	if len(events) != 2 {
+		t.Fatalf("got %d events, expect 2: %+v", len(events), events)
+	}
+	for i, e := range events {
+		if e.GroupId != group.GroupId || e.Trx.Type != quorumpb.TrxType_POST || e.Trx.TrxId != trxIds[i] {
+			t.Errorf("event %d: %+v, expect post %s", i, e, trxIds[i])
+		}
+	}
+
+	// resume after the first event, only the second post is replayed
+	from = events[0].BlockId
+	msg.Groups[0].FromIndex = events[0].TrxIndex + 1
+	conn, _, err = wsSubscribe(peerapi, msg)
+	if err != nil {
+		t.Fatalf("wsSubscribe failed: %s", err)
+	}
+	resumed := wsReadEvents(conn, 2, 5*time.Second)
+	conn.Close()
+	if len(resumed) != 1 || resumed[0].Trx.TrxId != trxIds[1] {
+		t.Errorf("resumed events %+v, expect post %s only", resumed, trxIds[1])
+	}
+
+	// senders filter out all posts
+	from = 0
+	msg.Groups[0].FromIndex = 0
+	msg.Senders = []string{"CAISIQOlA37+ghb05D5ZAKExjsto/H7eeCmkagcZ+BY/pjSOKw=="}
+	conn, _, err = wsSubscribe(peerapi, msg)
+	if err != nil {
+		t.Fatalf("wsSubscribe failed: %s", err)
+	}
+	filtered := wsReadEvents(conn, 1, 3*time.Second)
+	conn.Close()
+	if len(filtered) != 0 {
+		t.Errorf("got events %+v of other senders", filtered)
+	}
+}
@@ -1,16 +1,15 @@
 package appapi
 
 import (
-	"encoding/hex"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/rumsystem/quorum/internal/pkg/appdata"
 	chain "github.com/rumsystem/quorum/internal/pkg/chainsdk/core"
 	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
 	"github.com/rumsystem/quorum/internal/pkg/storage/def"
 	"github.com/rumsystem/quorum/internal/pkg/utils"
 	"github.com/rumsystem/quorum/pkg/chainapi/handlers"
-	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
 	quorumpb "github.com/rumsystem/quorum/pkg/pb"
 )
 
@@ -43,46 +42,90 @@ func (h *Handler) ContentByPeers(c echo.Context) (err error) {
 		return rumerrors.NewBadRequestError(err)
 	}
 
+	res, err := h.getDecryptedTrxs(groupitem, trxids)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// @Tags Apps
+// @Summary SearchGroupContents
+// @Description Search posts in a group by keywords (all keywords should match), newest first, only works when search index is enabled
+// @Produce json
+// @Param group_id path string  true "Group Id"
+// @Param params query handlers.SearchGroupCtnParams true "search group contents params"
+// @Success 200 {array} []quorumpb.Trx
+// @Router /app/api/v1/group/{group_id}/search [get]
+func (h *Handler) SearchGroupContent(c echo.Context) (err error) {
+	cc := c.(*utils.CustomContext)
+	var params handlers.SearchGroupCtnParams
+	if err := cc.BindAndValidate(&params); err != nil {
+		return err
+	}
+	if params.Num <= 0 {
+		params.Num = 20
+	}
+
+	if !h.Appdb.EnableSearchIndex {
+		return rumerrors.NewBadRequestError("search index is not enabled")
+	}
+
+	groupmgr := chain.GetGroupMgr()
+	groupitem, err := groupmgr.GetGroupItem(params.GroupId)
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	trxids, err := h.Appdb.SearchGroupContent(&appdata.SearchQuery{
+		GroupId:   params.GroupId,
+		Keywords:  params.Keywords,
+		Senders:   params.Senders,
+		StartTime: params.StartTime,
+		EndTime:   params.EndTime,
+		StartTrx:  params.StartTrx,
+		Num:       params.Num,
+	})
+	if err != nil {
+		return rumerrors.NewBadRequestError(err)
+	}
+
+	res, err := h.getDecryptedTrxs(groupitem, trxids)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (h *Handler) getDecryptedTrxs(groupitem *quorumpb.GroupItem, trxids []string) ([]*quorumpb.Trx, error) {
 	res := []*quorumpb.Trx{}
 	for _, trxid := range trxids {
-		trx, err := h.Trxdb.GetTrx(params.GroupId, trxid, def.Chain, h.NodeName)
+		trx, err := h.Trxdb.GetTrx(groupitem.GroupId, trxid, def.Chain, h.NodeName)
 		if err != nil {
-			logger.Errorf("GetTrx groupid: %s trxid: %s failed: %s", params.GroupId, trxid, err)
+			logger.Errorf("GetTrx groupid: %s trxid: %s failed: %s", groupitem.GroupId, trxid, err)
 			continue
 		}
 		if trx.TrxId == "" && len(trx.Data) == 0 {
-			logger.Warnf("GetTrx groupid: %s trxid: %s return empty trx, skip ...", params.GroupId, trxid)
+			logger.Warnf("GetTrx groupid: %s trxid: %s return empty trx, skip ...", groupitem.GroupId, trxid)
 			continue
 		}
 
 		//decrypt trx data
-		if trx.Type == quorumpb.TrxType_POST && groupitem.EncryptType == quorumpb.GroupEncryptType_PRIVATE {
-			//for post, private group, encrypted by age for all announced group user
-			ks := localcrypto.GetKeystore()
-			decryptData, err := ks.Decrypt(params.GroupId, trx.Data)
-			if err != nil {
+		decryptData, err := appdata.DecryptTrxData(groupitem, trx)
+		if err != nil {
+			if trx.Type == quorumpb.TrxType_POST && groupitem.EncryptType == quorumpb.GroupEncryptType_PRIVATE {
 				//can't decrypt, replace it
 				trx.Data = nil
-				logger.Warnf("can not decrypt trx.Data for groupid: %s trxid: %s failed: %s", params.GroupId, trxid, err)
+				logger.Warnf("can not decrypt trx.Data for groupid: %s trxid: %s failed: %s", groupitem.GroupId, trxid, err)
 			} else {
-				//set trx.Data to decrypted []byte
-				trx.Data = decryptData
+				return nil, err
 			}
 		} else {
-			//decode trx data
-			ciperKey, err := hex.DecodeString(groupitem.CipherKey)
-			if err != nil {
-				return err
-			}
-
-			decryptData, err := localcrypto.AesDecode(trx.Data, ciperKey)
-			if err != nil {
-				return err
-			}
+			//set trx.Data to decrypted []byte
 			trx.Data = decryptData
 		}
 
 		res = append(res, trx)
 	}
-	return c.JSON(http.StatusOK, res)
+	return res, nil
 }
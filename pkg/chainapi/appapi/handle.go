@@ -5,6 +5,7 @@ import (
 
 	"github.com/rumsystem/quorum/internal/pkg/appdata"
 	"github.com/rumsystem/quorum/internal/pkg/storage/def"
+	node_storage "github.com/rumsystem/quorum/internal/pkg/storage/node"
 )
 
 type Handler struct {
@@ -16,4 +17,5 @@ type Handler struct {
 	ConfigDir string
 	PeerName  string
 	NodeName  string
+	NodeDb    *node_storage.Storage
 }
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	chain "github.com/rumsystem/quorum/internal/pkg/chainsdk/core"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	"github.com/rumsystem/quorum/internal/pkg/filestore"
+)
+
+type CreateUploadParam struct {
+	GroupId   string `param:"group_id" json:"-" validate:"required,uuid4" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	Name      string `json:"name" validate:"required,max=255" example:"book.epub"`
+	MediaType string `json:"media_type" validate:"omitempty,max=255" example:"application/epub+zip"`
+	Size      int64  `json:"size" validate:"required,gt=0" example:"3145728"`
+	Sha256    string `json:"sha256" validate:"omitempty,len=64,hexadecimal" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"` // optional, checked on completion
+}
+
+type UploadParam struct {
+	GroupId  string `param:"group_id" validate:"required,uuid4" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	UploadId string `param:"upload_id" validate:"required,uuid4" example:"0e1b5a0c-6e0d-4b5e-9d7a-2d7e0a7c7b3e"`
+}
+
+type UploadChunkParam struct {
+	GroupId  string `param:"group_id" validate:"required,uuid4" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	UploadId string `param:"upload_id" validate:"required,uuid4" example:"0e1b5a0c-6e0d-4b5e-9d7a-2d7e0a7c7b3e"`
+	Index    int    `param:"index" validate:"gte=0" example:"0"`
+	Sha256   string `query:"sha256" validate:"omitempty,len=64,hexadecimal" example:"4a5c5e6b0a1e0f1c7b1a9e3c8d2f6b5a4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b"` // optional, checked before the chunk is saved
+}
+
+type FileParam struct {
+	GroupId string `param:"group_id" validate:"required,uuid4" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	FileId  string `param:"file_id" validate:"required,len=64,hexadecimal" example:"5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8"`
+}
+
+type UploadResult struct {
+	*filestore.Upload
+	Missing []int `json:"missing"` // indexes of chunks not uploaded yet
+}
+
+type CompleteUploadResult struct {
+	FileId   string              `json:"file_id" example:"5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8"`
+	TrxId    string              `json:"trx_id" example:"9e54c173-c1dd-429d-91fa-a6b43c14da77"`
+	Manifest *filestore.Manifest `json:"manifest"`
+}
+
+type FileResult struct {
+	FileId        string                    `json:"file_id" example:"5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8"`
+	Manifest      *filestore.Manifest       `json:"manifest"` // nil if the manifest is not fetched yet
+	MissingChunks int                       `json:"missing_chunks" example:"0"`
+	Complete      bool                      `json:"complete" example:"true"` // all chunks are in local store and can be read by the content api
+	Download      *filestore.DownloadStatus `json:"download,omitempty"`
+}
+
+type GroupFilesResult struct {
+	GroupId string        `json:"group_id" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	Files   []*FileResult `json:"files"`
+}
+
+func CreateUpload(params *CreateUploadParam, fs *filestore.FileStore) (*UploadResult, error) {
+	if _, ok := chain.GetGroupMgr().Groups[params.GroupId]; !ok {
+		return nil, rumerrors.ErrGroupNotFound
+	}
+	u, err := fs.NewUpload(params.GroupId, params.Name, params.MediaType, params.Size, params.Sha256)
+	if err != nil {
+		return nil, err
+	}
+	return &UploadResult{Upload: u, Missing: u.Missing()}, nil
+}
+
+func GetUpload(params *UploadParam, fs *filestore.FileStore) (*UploadResult, error) {
+	u, err := getGroupUpload(params.GroupId, params.UploadId, fs)
+	if err != nil {
+		return nil, err
+	}
+	return &UploadResult{Upload: u, Missing: u.Missing()}, nil
+}
+
+func UploadChunk(params *UploadChunkParam, data []byte, fs *filestore.FileStore) (*filestore.ChunkInfo, error) {
+	if _, err := getGroupUpload(params.GroupId, params.UploadId, fs); err != nil {
+		return nil, err
+	}
+	return fs.PutUploadChunk(params.UploadId, params.Index, data, params.Sha256)
+}
+
+// CompleteUpload verifies the uploaded file and posts its manifest to the group, members of the group
+// can download the file from any peer sharing it by the file id in the trx
+func CompleteUpload(params *UploadParam, fs *filestore.FileStore) (*CompleteUploadResult, error) {
+	group, ok := chain.GetGroupMgr().Groups[params.GroupId]
+	if !ok {
+		return nil, rumerrors.ErrGroupNotFound
+	}
+	if _, err := getGroupUpload(params.GroupId, params.UploadId, fs); err != nil {
+		return nil, err
+	}
+
+	fileId, m, err := fs.CompleteUpload(params.UploadId)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := json.Marshal(map[string]interface{}{
+		"type": "Create",
+		"object": map[string]interface{}{
+			"type":      "File",
+			"id":        fileId,
+			"name":      m.Name,
+			"mediaType": m.MediaType,
+			"size":      m.Size,
+			"sha256":    m.Sha256,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	trxId, err := group.PostToGroup(content)
+	if err != nil {
+		return nil, fmt.Errorf("file %s is saved, but post manifest failed: %s", fileId, err)
+	}
+	return &CompleteUploadResult{FileId: fileId, TrxId: trxId, Manifest: m}, nil
+}
+
+func RemoveUpload(params *UploadParam, fs *filestore.FileStore) error {
+	if _, err := getGroupUpload(params.GroupId, params.UploadId, fs); err != nil {
+		return err
+	}
+	return fs.RemoveUpload(params.UploadId)
+}
+
+func GetGroupFiles(groupId string, fs *filestore.FileStore) (*GroupFilesResult, error) {
+	fileIds, err := fs.GetGroupFiles(groupId)
+	if err != nil {
+		return nil, err
+	}
+	files := []*FileResult{}
+	for _, fileId := range fileIds {
+		files = append(files, getFileResult(groupId, fileId, fs))
+	}
+	return &GroupFilesResult{GroupId: groupId, Files: files}, nil
+}
+
+// GetFile returns manifest and local state of a file shared in the group or being downloaded
+func GetFile(params *FileParam, fs *filestore.FileStore) (*FileResult, error) {
+	res := getFileResult(params.GroupId, params.FileId, fs)
+	if !fs.HasGroupFile(params.GroupId, params.FileId) && res.Download == nil {
+		return nil, filestore.ErrFileNotFound
+	}
+	return res, nil
+}
+
+// DownloadFile starts or resumes fetching a file from group peers, progress is reported by GetFile
+func DownloadFile(params *FileParam, fs *filestore.FileStore) (*filestore.DownloadStatus, error) {
+	if _, ok := chain.GetGroupMgr().Groups[params.GroupId]; !ok {
+		return nil, rumerrors.ErrGroupNotFound
+	}
+	return fs.Download(params.GroupId, params.FileId)
+}
+
+// OpenFile returns a reader of a complete file shared in the group
+func OpenFile(params *FileParam, fs *filestore.FileStore) (*filestore.FileReader, *filestore.Manifest, error) {
+	if !fs.HasGroupFile(params.GroupId, params.FileId) {
+		return nil, nil, filestore.ErrFileNotFound
+	}
+	m, err := fs.GetManifest(params.FileId)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err := fs.OpenFile(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, m, nil
+}
+
+func getGroupUpload(groupId string, uploadId string, fs *filestore.FileStore) (*filestore.Upload, error) {
+	u, err := fs.GetUpload(uploadId)
+	if err != nil {
+		return nil, err
+	}
+	if u.GroupId != groupId {
+		return nil, filestore.ErrUploadNotFound
+	}
+	return u, nil
+}
+
+func getFileResult(groupId string, fileId string, fs *filestore.FileStore) *FileResult {
+	res := &FileResult{FileId: fileId, Download: fs.GetDownload(groupId, fileId)}
+	m, err := fs.GetManifest(fileId)
+	if err != nil {
+		return res
+	}
+	res.Manifest = m
+	res.MissingChunks = len(fs.MissingChunks(m))
+	res.Complete = res.MissingChunks == 0 && fs.HasGroupFile(groupId, fileId)
+	return res
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	chain "github.com/rumsystem/quorum/internal/pkg/chainsdk/core"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	"github.com/rumsystem/quorum/pkg/pb"
+)
+
+var MAX_EXPLORER_SCAN_BLOCKS = uint64(1000) // max blocks scanned by one trx list request
+
+type GetBlocksParam struct {
+	GroupId string `param:"group_id" url:"-" validate:"required,uuid4" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	From    uint64 `query:"from" url:"from" example:"1"`                                             // first block, 0 means the top block when reverse
+	To      uint64 `query:"to" url:"to,omitempty" example:"100"`                                     // last block (included), 0 means no bound
+	Num     int    `query:"num" url:"num,omitempty" validate:"omitempty,min=1,max=100" example:"20"` // default 20
+	Reverse bool   `query:"reverse" url:"reverse,omitempty" example:"false"`
+}
+
+type GetBlocksResult struct {
+	GroupId         string      `json:"group_id" validate:"required" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	Blocks          []*pb.Block `json:"blocks"`
+	MissingBlockIds []uint64    `json:"missing_block_ids"` // blocks in the page not found in local chain, skipped
+	HasMore         bool        `json:"has_more" example:"true"`
+	NextBlockId     uint64      `json:"next_block_id" example:"21"` // use as `from` of the next page if has_more
+}
+
+// GetBlocks return blocks of a group in [from, to], or [to, from] when reverse,
+// a page covers num block ids, blocks can not be read are reported in MissingBlockIds
+func GetBlocks(params *GetBlocksParam) (*GetBlocksResult, error) {
+	groupmgr := chain.GetGroupMgr()
+	group, ok := groupmgr.Groups[params.GroupId]
+	if !ok {
+		return nil, rumerrors.ErrGroupNotFound
+	}
+
+	num := params.Num
+	if num == 0 {
+		num = 20
+	}
+
+	result := &GetBlocksResult{GroupId: params.GroupId, Blocks: []*pb.Block{}, MissingBlockIds: []uint64{}}
+	cursor := newBlockCursor(group.GetCurrentBlockId(), params.From, params.To, params.Reverse)
+	for cursor.valid() {
+		if len(result.Blocks)+len(result.MissingBlockIds) == num {
+			result.HasMore = true
+			result.NextBlockId = cursor.curr
+			break
+		}
+
+		block, err := group.GetBlock(cursor.curr)
+		if err != nil || block == nil {
+			result.MissingBlockIds = append(result.MissingBlockIds, cursor.curr)
+		} else {
+			result.Blocks = append(result.Blocks, block)
+		}
+		cursor.next()
+	}
+
+	return result, nil
+}
+
+// blockCursor walks block ids between from and to, bounded by the top block of local chain
+type blockCursor struct {
+	curr    uint64
+	end     uint64
+	reverse bool
+	done    bool
+}
+
+func newBlockCursor(top uint64, from uint64, to uint64, reverse bool) *blockCursor {
+	c := &blockCursor{curr: from, end: to, reverse: reverse}
+	if reverse {
+		if c.curr == 0 || c.curr > top {
+			c.curr = top
+		}
+		c.done = c.curr < c.end
+	} else {
+		if c.end == 0 || c.end > top {
+			c.end = top
+		}
+		c.done = c.curr > c.end
+	}
+	return c
+}
+
+func (c *blockCursor) valid() bool {
+	return !c.done
+}
+
+func (c *blockCursor) next() {
+	if c.curr == c.end {
+		c.done = true
+		return
+	}
+	if c.reverse {
+		c.curr--
+	} else {
+		c.curr++
+	}
+}
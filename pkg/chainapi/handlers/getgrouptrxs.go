@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	chain "github.com/rumsystem/quorum/internal/pkg/chainsdk/core"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	"github.com/rumsystem/quorum/pkg/pb"
+)
+
+type GetGroupTrxsParam struct {
+	GroupId   string   `param:"group_id" url:"-" validate:"required,uuid4" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	TrxType   string   `query:"type" url:"type,omitempty" example:"POST"`
+	Senders   []string `query:"senders" url:"senders,omitempty" example:"CAISIQOxCH2yVZPR8t6gVvZapxcIPBwMh9jB80pDLNeuA5s8hQ=="`
+	StartTime int64    `query:"start_time" url:"start_time,omitempty" example:"1680000000000000000"` // unix nano, included
+	EndTime   int64    `query:"end_time" url:"end_time,omitempty" example:"1690000000000000000"`     // unix nano, excluded, 0 means no bound
+	FromBlock uint64   `query:"from_block" url:"from_block,omitempty" example:"1"`                   // 0 means the top block when reverse
+	FromIndex int      `query:"from_index" url:"from_index,omitempty" validate:"omitempty,min=0" example:"0"`
+	Num       int      `query:"num" url:"num,omitempty" validate:"omitempty,min=1,max=100" example:"20"` // default 20
+	Reverse   bool     `query:"reverse" url:"reverse,omitempty" example:"false"`
+}
+
+type BlockTrx struct {
+	BlockId uint64  `json:"block_id" example:"10"`
+	Index   int     `json:"index" example:"0"` // position of the trx in block
+	Trx     *pb.Trx `json:"trx"`
+}
+
+type GetGroupTrxsResult struct {
+	GroupId         string      `json:"group_id" validate:"required" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	Trxs            []*BlockTrx `json:"trxs"`
+	MissingBlockIds []uint64    `json:"missing_block_ids"` // blocks scanned but not found in local chain, skipped
+	HasMore         bool        `json:"has_more" example:"true"`
+	NextBlockId     uint64      `json:"next_block_id" example:"12"` // use as `from_block` of the next page if has_more
+	NextIndex       int         `json:"next_index" example:"3"`     // use as `from_index` of the next page if has_more
+}
+
+// GetGroupTrxs scan blocks from `from_block` and return trxs match the filter,
+// at most MAX_EXPLORER_SCAN_BLOCKS blocks are scanned by one request, continue with next_block_id/next_index if has_more
+func GetGroupTrxs(params *GetGroupTrxsParam) (*GetGroupTrxsResult, error) {
+	groupmgr := chain.GetGroupMgr()
+	group, ok := groupmgr.Groups[params.GroupId]
+	if !ok {
+		return nil, rumerrors.ErrGroupNotFound
+	}
+
+	var trxType *pb.TrxType
+	if params.TrxType != "" {
+		v, ok := pb.TrxType_value[strings.ToUpper(params.TrxType)]
+		if !ok {
+			return nil, fmt.Errorf("unknown trx type %s", params.TrxType)
+		}
+		t := pb.TrxType(v)
+		trxType = &t
+	}
+
+	senders := make(map[string]bool)
+	for _, s := range params.Senders {
+		senders[s] = true
+	}
+
+	num := params.Num
+	if num == 0 {
+		num = 20
+	}
+
+	result := &GetGroupTrxsResult{GroupId: params.GroupId, Trxs: []*BlockTrx{}, MissingBlockIds: []uint64{}}
+	cursor := newBlockCursor(group.GetCurrentBlockId(), params.FromBlock, 0, params.Reverse)
+	fromIndex := params.FromIndex
+	scanned := uint64(0)
+	for cursor.valid() {
+		if scanned == MAX_EXPLORER_SCAN_BLOCKS {
+			result.HasMore = true
+			result.NextBlockId = cursor.curr
+			result.NextIndex = 0
+			return result, nil
+		}
+
+		block, err := group.GetBlock(cursor.curr)
+		scanned++
+		if err != nil || block == nil {
+			result.MissingBlockIds = append(result.MissingBlockIds, cursor.curr)
+			fromIndex = 0
+			cursor.next()
+			continue
+		}
+
+		for i := range block.Trxs {
+			idx := i
+			if params.Reverse {
+				idx = len(block.Trxs) - 1 - i
+			}
+			if i < fromIndex {
+				continue
+			}
+
+			if len(result.Trxs) == num {
+				result.HasMore = true
+				result.NextBlockId = block.BlockId
+				result.NextIndex = i
+				return result, nil
+			}
+
+			trx := block.Trxs[idx]
+			if trxType != nil && trx.Type != *trxType {
+				continue
+			}
+			if len(senders) > 0 && !senders[trx.SenderPubkey] {
+				continue
+			}
+			if trx.TimeStamp < params.StartTime || (params.EndTime > 0 && trx.TimeStamp >= params.EndTime) {
+				continue
+			}
+			result.Trxs = append(result.Trxs, &BlockTrx{BlockId: block.BlockId, Index: idx, Trx: trx})
+		}
+
+		fromIndex = 0
+		cursor.next()
+	}
+
+	return result, nil
+}
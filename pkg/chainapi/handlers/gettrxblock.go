@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"fmt"
+
+	chain "github.com/rumsystem/quorum/internal/pkg/chainsdk/core"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	"github.com/rumsystem/quorum/internal/pkg/nodectx"
+	"github.com/rumsystem/quorum/pkg/pb"
+)
+
+// GetTrxBlock return the block which contains the trx
+func GetTrxBlock(groupId string, trxId string) (*pb.Block, error) {
+	groupmgr := chain.GetGroupMgr()
+	group, ok := groupmgr.Groups[groupId]
+	if !ok {
+		return nil, rumerrors.ErrGroupNotFound
+	}
+
+	cs := nodectx.GetNodeCtx().GetChainStorage()
+	blockId, ok, err := cs.GetTrxBlockId(groupId, trxId, group.Nodename)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		block, err := group.GetBlock(blockId)
+		if err != nil {
+			return nil, err
+		}
+		if blockHasTrx(block, trxId) {
+			return block, nil
+		}
+	}
+
+	//blocks saved before the index was added, search the chain and index the block found
+	exist, err := cs.IsTrxExist(groupId, trxId, group.Nodename)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, fmt.Errorf("trx %s not found on chain", trxId)
+	}
+
+	for blockId := group.GetCurrentBlockId(); ; blockId-- {
+		block, err := group.GetBlock(blockId)
+		if err != nil {
+			return nil, err
+		}
+		if blockHasTrx(block, trxId) {
+			if err := cs.SaveTrxBlockIndex(block, group.Nodename); err != nil {
+				logger.Warnf("save trx block index for block %d failed: %s", block.BlockId, err)
+			}
+			return block, nil
+		}
+		if blockId == 0 {
+			break
+		}
+	}
+
+	return nil, fmt.Errorf("block of trx %s not found", trxId)
+}
+
+func blockHasTrx(block *pb.Block, trxId string) bool {
+	for _, trx := range block.Trxs {
+		if trx.TrxId == trxId {
+			return true
+		}
+	}
+	return false
+}
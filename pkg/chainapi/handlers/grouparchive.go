@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	chain "github.com/rumsystem/quorum/internal/pkg/chainsdk/core"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	rumchaindata "github.com/rumsystem/quorum/pkg/data"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+)
+
+type GroupArchiveParam struct {
+	GroupId string `param:"group_id" validate:"required,uuid4" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+}
+
+type ImportGroupArchiveResult struct {
+	GroupId  string `json:"group_id" validate:"required" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	Imported uint64 `json:"imported" example:"100"`
+	Skipped  uint64 `json:"skipped" example:"10"`
+	TopBlock uint64 `json:"top_block" example:"110"`
+}
+
+// BlockGetter is implemented by both storage.DbMgr and chain storage, so a group can be exported with or without a running node
+type BlockGetter interface {
+	GetBlock(groupId string, blockId uint64, cached bool, prefix ...string) (*quorumpb.Block, error)
+	IsBlockExist(groupId string, blockId uint64, cached bool, prefix ...string) (bool, error)
+}
+
+// ExportGroupArchive writes all blocks of a group, from genesis to the top one, to w as a group archive
+func ExportGroupArchive(db BlockGetter, groupId string, w io.Writer, nodename string) (uint64, error) {
+	exist, err := db.IsBlockExist(groupId, 0, false, nodename)
+	if err != nil {
+		return 0, err
+	}
+	if !exist {
+		return 0, fmt.Errorf("genesis block of group %s not found", groupId)
+	}
+
+	aw, err := rumchaindata.NewGroupArchiveWriter(w, groupId)
+	if err != nil {
+		return 0, err
+	}
+
+	for blockId := uint64(0); ; blockId++ {
+		exist, err := db.IsBlockExist(groupId, blockId, false, nodename)
+		if err != nil {
+			return aw.Count(), err
+		}
+		if !exist {
+			break
+		}
+
+		block, err := db.GetBlock(groupId, blockId, false, nodename)
+		if err != nil {
+			return aw.Count(), err
+		}
+		if err := aw.WriteBlock(block); err != nil {
+			return aw.Count(), err
+		}
+	}
+
+	return aw.Count(), aw.Close()
+}
+
+// ImportGroupArchive verifies blocks in a group archive against their parents and applies them to a joined group
+func ImportGroupArchive(groupId string, r io.Reader) (*ImportGroupArchiveResult, error) {
+	groupmgr := chain.GetGroupMgr()
+	group, ok := groupmgr.Groups[groupId]
+	if !ok {
+		return nil, rumerrors.ErrGroupNotFound
+	}
+
+	ar, err := rumchaindata.NewGroupArchiveReader(r)
+	if err != nil {
+		return nil, err
+	}
+	if ar.Header.GroupId != groupId {
+		return nil, fmt.Errorf("archive of group %s can not be imported to group %s", ar.Header.GroupId, groupId)
+	}
+
+	result := &ImportGroupArchiveResult{GroupId: groupId}
+
+	//archive should start from the same genesis block as local chain
+	genesis, err := ar.Next()
+	if err != nil {
+		return nil, err
+	}
+	localGenesis, err := group.GetBlock(0)
+	if err != nil {
+		return nil, err
+	}
+	if genesis.BlockId != 0 || !bytes.Equal(genesis.BlockHash, localGenesis.BlockHash) {
+		return nil, fmt.Errorf("genesis block mismatch with local chain")
+	}
+
+	parent := genesis
+	for {
+		block, err := ar.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+
+		if valid, err := rumchaindata.ValidBlockWithParent(block, parent); !valid {
+			return result, fmt.Errorf("invalid block %d: %s", block.BlockId, err)
+		}
+		parent = block
+
+		if block.BlockId <= group.GetCurrentBlockId() {
+			local, err := group.GetBlock(block.BlockId)
+			if err != nil {
+				return result, err
+			}
+			if !bytes.Equal(local.BlockHash, block.BlockHash) {
+				return result, fmt.Errorf("block %d mismatch with local chain", block.BlockId)
+			}
+			result.Skipped++
+			continue
+		}
+
+		if err := group.ChainCtx.ApplyBlocks([]*quorumpb.Block{block}); err != nil {
+			return result, err
+		}
+		if group.GetCurrentBlockId() < block.BlockId {
+			return result, fmt.Errorf("apply block %d failed", block.BlockId)
+		}
+		result.Imported++
+	}
+
+	result.TopBlock = group.GetCurrentBlockId()
+	logger.Infof("<%s> group archive imported, %d blocks applied, %d skipped", groupId, result.Imported, result.Skipped)
+	return result, nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/rumsystem/quorum/internal/pkg/appdata"
 	chain "github.com/rumsystem/quorum/internal/pkg/chainsdk/core"
+	"github.com/rumsystem/quorum/internal/pkg/filestore"
 )
 
 type LeaveGroupParam struct {
@@ -16,7 +17,7 @@ type LeaveGroupResult struct {
 	GroupId string `json:"group_id" validate:"required,uuid4" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
 }
 
-func LeaveGroup(params *LeaveGroupParam, appdb *appdata.AppDb) (*LeaveGroupResult, error) {
+func LeaveGroup(params *LeaveGroupParam, appdb *appdata.AppDb, fs *filestore.FileStore) (*LeaveGroupResult, error) {
 	validate := validator.New()
 	if err := validate.Struct(params); err != nil {
 		return nil, err
@@ -33,7 +34,7 @@ func LeaveGroup(params *LeaveGroupParam, appdb *appdata.AppDb) (*LeaveGroupResul
 		return nil, err
 	}
 
-	delete(groupmgr.Groups, params.GroupId)
+	groupmgr.RemoveGroup(params.GroupId)
 
 	//var groupSignPubkey []byte
 	//ks := localcrypto.GetKeystore()
@@ -58,5 +59,19 @@ func LeaveGroup(params *LeaveGroupParam, appdb *appdata.AppDb) (*LeaveGroupResul
 		return nil, fmt.Errorf("save group seed failed: %s", err)
 	}
 
+	if err := appdb.DelGroupSearchIndex(params.GroupId); err != nil {
+		return nil, fmt.Errorf("delete group search index failed: %s", err)
+	}
+
+	if err := appdb.RemoveGroupWebhooks(params.GroupId); err != nil {
+		return nil, fmt.Errorf("remove group webhooks failed: %s", err)
+	}
+
+	if fs != nil {
+		if err := fs.RemoveGroupFiles(params.GroupId); err != nil {
+			return nil, fmt.Errorf("remove group files failed: %s", err)
+		}
+	}
+
 	return &LeaveGroupResult{GroupId: params.GroupId}, nil
 }
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"sort"
+
+	chain "github.com/rumsystem/quorum/internal/pkg/chainsdk/core"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	"github.com/rumsystem/quorum/pkg/consensus"
+)
+
+type GetMempoolParam struct {
+	GroupId string `param:"group_id" validate:"required,uuid4" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+}
+
+type MempoolSenderItem struct {
+	Pubkey          string `json:"pubkey" example:"CAISIQNGAO67UTFSuWzySHKdy4IjBI/Q5XDMELPUSxHpBwQDcQ=="`
+	Count           int    `json:"count" example:"3"`
+	OldestTimeStamp int64  `json:"oldest_timestamp" example:"1672641932286871200"`
+}
+
+type MempoolPolicyResult struct {
+	BatchSize        int   `json:"batch_size" example:"20"`
+	MaxTrxsPerSender int   `json:"max_trxs_per_sender" example:"0"`
+	MaxBufferSize    int   `json:"max_buffer_size" example:"10000"`
+	MaxTrxAge        int64 `json:"max_trx_age" example:"0"` // in seconds
+}
+
+type MempoolResult struct {
+	GroupId string               `json:"group_id" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	Depth   int                  `json:"depth" example:"12"`
+	Policy  *MempoolPolicyResult `json:"policy"`
+	Senders []*MempoolSenderItem `json:"senders"`
+}
+
+// GetMempool return trxs waiting in the trx buffer of a producer, senders with most trxs come first
+func GetMempool(groupId string) (*MempoolResult, error) {
+	group, ok := chain.GetGroupMgr().Groups[groupId]
+	if !ok {
+		return nil, rumerrors.ErrGroupNotFound
+	}
+
+	trxs, err := consensus.NewTrxBuffer(groupId).GetAllTrxInBuffer()
+	if err != nil {
+		return nil, err
+	}
+
+	senders := make(map[string]*MempoolSenderItem)
+	result := &MempoolResult{GroupId: groupId, Depth: len(trxs), Senders: []*MempoolSenderItem{}}
+	for _, trx := range trxs {
+		item, ok := senders[trx.SenderPubkey]
+		if !ok {
+			item = &MempoolSenderItem{Pubkey: trx.SenderPubkey, OldestTimeStamp: trx.TimeStamp}
+			senders[trx.SenderPubkey] = item
+			result.Senders = append(result.Senders, item)
+		}
+		item.Count++
+		if trx.TimeStamp < item.OldestTimeStamp {
+			item.OldestTimeStamp = trx.TimeStamp
+		}
+	}
+	sort.SliceStable(result.Senders, func(i, j int) bool {
+		if result.Senders[i].Count != result.Senders[j].Count {
+			return result.Senders[i].Count > result.Senders[j].Count
+		}
+		return result.Senders[i].OldestTimeStamp < result.Senders[j].OldestTimeStamp
+	})
+
+	policy := consensus.GetMempoolPolicy(groupId, group.Nodename)
+	result.Policy = &MempoolPolicyResult{
+		BatchSize:        policy.BatchSize,
+		MaxTrxsPerSender: policy.MaxTrxsPerSender,
+		MaxBufferSize:    policy.MaxBufferSize,
+		MaxTrxAge:        int64(policy.MaxTrxAge.Seconds()),
+	}
+	return result, nil
+}
@@ -21,7 +21,7 @@ import (
 
 type ChainConfigParams struct {
 	GroupId string `from:"group_id" json:"group_id"  validate:"required,uuid4" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
-	Type    string `from:"type"     json:"type"      validate:"required,oneof=set_trx_auth_mode upd_alw_list upd_dny_list" example:"upd_alw_list"`
+	Type    string `from:"type"     json:"type"      validate:"required,oneof=set_trx_auth_mode upd_alw_list upd_dny_list set_mempool_policy set_epoch_pacing" example:"upd_alw_list"`
 	Config  string `from:"config"   json:"config"    validate:"required" example:"{\"action\":\"add\",  \"pubkey\":\"CAISIQNGAO67UTFSuWzySHKdy4IjBI/Q5XDMELPUSxHpBwQDcQ==\", \"trx_type\":[\"post\", \"announce\", \"req_block_forward\", \"req_block_backward\", \"ask_peerid\"]}"`
 	Memo    string `from:"memo"     json:"memo" example:"comment/remark"`
 }
@@ -36,6 +36,19 @@ type ChainSendTrxRuleListItemParams struct {
 	TrxType []string `from:"trx_type" json:"trx_type" validate:"required"` // Example: ["POST", "ANNOUNCE"]
 }
 
+type MempoolPolicyParams struct {
+	BatchSize        int64 `from:"batch_size"          json:"batch_size"          validate:"gte=0" example:"20"`
+	MaxTrxsPerSender int64 `from:"max_trxs_per_sender" json:"max_trxs_per_sender" validate:"gte=0" example:"5"`
+	MaxBufferSize    int64 `from:"max_buffer_size"     json:"max_buffer_size"     validate:"gte=0" example:"10000"`
+	MaxTrxAge        int64 `from:"max_trx_age"         json:"max_trx_age"         validate:"gte=0" example:"600"` // in seconds
+}
+
+type EpochPacingParams struct {
+	MinInterval     int64 `from:"min_interval"      json:"min_interval"      validate:"gte=0" example:"0"`     // in milliseconds
+	BaseInterval    int64 `from:"base_interval"     json:"base_interval"     validate:"gte=0" example:"1000"`  // in milliseconds
+	MaxIdleInterval int64 `from:"max_idle_interval" json:"max_idle_interval" validate:"gte=0" example:"60000"` // in milliseconds
+}
+
 type ChainConfigResult struct {
 	GroupId          string `json:"group_id"     validate:"required,uuid4" example:"b3e1800a-af6e-4c67-af89-4ddcf831b6f7"`
 	GroupOwnerPubkey string `json:"owner_pubkey" validate:"required" example:"CAISIQPLW/J9xgdMWoJxFttChoGOOld8TpChnGFFyPADGL+0JA=="`
@@ -139,6 +152,53 @@ func MgrChainConfig(params *ChainConfigParams) (*ChainConfigResult, error) {
 			configItem.Type = quorumpb.ChainConfigType_UPD_DNY_LIST
 		}
 		configItem.Data = encodedcontent
+	} else if params.Type == strings.ToLower(quorumpb.ChainConfigType_SET_MEMPOOL_POLICY.String()) {
+		dataParams := MempoolPolicyParams{}
+		err := json.Unmarshal([]byte(params.Config), &dataParams)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := validate.Struct(dataParams); err != nil {
+			return nil, err
+		}
+
+		dataItem := quorumpb.MempoolPolicyItem{
+			BatchSize:        dataParams.BatchSize,
+			MaxTrxsPerSender: dataParams.MaxTrxsPerSender,
+			MaxBufferSize:    dataParams.MaxBufferSize,
+			MaxTrxAge:        dataParams.MaxTrxAge,
+		}
+		encodedcontent, err := proto.Marshal(&dataItem)
+		if err != nil {
+			return nil, err
+		}
+
+		configItem.Type = quorumpb.ChainConfigType_SET_MEMPOOL_POLICY
+		configItem.Data = encodedcontent
+	} else if params.Type == strings.ToLower(quorumpb.ChainConfigType_SET_EPOCH_PACING.String()) {
+		dataParams := EpochPacingParams{}
+		err := json.Unmarshal([]byte(params.Config), &dataParams)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := validate.Struct(dataParams); err != nil {
+			return nil, err
+		}
+
+		dataItem := quorumpb.EpochPacingItem{
+			MinInterval:     dataParams.MinInterval,
+			BaseInterval:    dataParams.BaseInterval,
+			MaxIdleInterval: dataParams.MaxIdleInterval,
+		}
+		encodedcontent, err := proto.Marshal(&dataItem)
+		if err != nil {
+			return nil, err
+		}
+
+		configItem.Type = quorumpb.ChainConfigType_SET_EPOCH_PACING
+		configItem.Data = encodedcontent
 	} else {
 		return nil, errors.New("Type not supported")
 	}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"errors"
+
+	chain "github.com/rumsystem/quorum/internal/pkg/chainsdk/core"
+)
+
+type GetPubQueueParam struct {
+	GroupId string `param:"group_id" validate:"required,uuid4" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	TrxId   string `query:"trx" validate:"omitempty,uuid4" example:"22d5c38d-5921-4b75-8562-c110dcfd5ee8"`
+	Status  string `query:"status" validate:"omitempty,oneof=PENDING SUCCESS FAIL" example:"PENDING"`
+}
+
+type PubQueueInfo struct {
+	GroupId string                    `json:"GroupId" validate:"required" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	Data    []*chain.PublishQueueItem `json:"Data"`
+}
+
+func GetPubQueue(groupId string, status string, trxId string) (*PubQueueInfo, error) {
+	watcher := chain.GetPubQueueWatcher()
+	if watcher == nil {
+		return nil, errors.New("publish queue is not enabled")
+	}
+
+	items, err := watcher.GetGroupItems(groupId, status, trxId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PubQueueInfo{GroupId: groupId, Data: items}, nil
+}
+
+func PubQueueAck(trxIds []string) ([]string, error) {
+	watcher := chain.GetPubQueueWatcher()
+	if watcher == nil {
+		return nil, errors.New("publish queue is not enabled")
+	}
+
+	return watcher.Ack(trxIds)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"fmt"
+
+	chain "github.com/rumsystem/quorum/internal/pkg/chainsdk/core"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	"github.com/rumsystem/quorum/internal/pkg/nodectx"
+	chainstorage "github.com/rumsystem/quorum/internal/pkg/storage/chain"
+)
+
+type SetRetentionParam struct {
+	GroupId string `param:"group_id" json:"-" validate:"required,uuid4" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	Mode    string `json:"mode" validate:"required,oneof=all blocks days state" example:"blocks"`
+	Value   uint64 `json:"value" example:"100000"` // blocks or days to keep
+}
+
+type RetentionResult struct {
+	GroupId      string                        `json:"group_id" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	Policy       *chainstorage.RetentionPolicy `json:"policy"`
+	Pruned       *chainstorage.PruneState      `json:"pruned"`
+	CurrentBlock uint64                        `json:"current_block" example:"120000"`
+}
+
+func GetRetention(groupId string) (*RetentionResult, error) {
+	group, ok := chain.GetGroupMgr().Groups[groupId]
+	if !ok {
+		return nil, rumerrors.ErrGroupNotFound
+	}
+
+	cs := nodectx.GetNodeCtx().GetChainStorage()
+	policy, err := cs.GetRetentionPolicy(groupId, group.Nodename)
+	if err != nil {
+		return nil, err
+	}
+	state, err := cs.GetPruneState(groupId, group.Nodename)
+	if err != nil {
+		return nil, err
+	}
+	return &RetentionResult{GroupId: groupId, Policy: policy, Pruned: state, CurrentBlock: group.GetCurrentBlockId()}, nil
+}
+
+// SetRetention saves the retention policy of a group, blocks are pruned by the node in background.
+// pruned blocks can not be restored by a looser policy
+func SetRetention(params *SetRetentionParam) (*RetentionResult, error) {
+	group, ok := chain.GetGroupMgr().Groups[params.GroupId]
+	if !ok {
+		return nil, rumerrors.ErrGroupNotFound
+	}
+
+	policy := &chainstorage.RetentionPolicy{Mode: params.Mode, Value: params.Value}
+	if err := nodectx.GetNodeCtx().GetChainStorage().SetRetentionPolicy(params.GroupId, policy, group.Nodename); err != nil {
+		return nil, err
+	}
+	return GetRetention(params.GroupId)
+}
+
+// checkBlockNotPruned return an error if trxs of the block are pruned
+func checkBlockNotPruned(group *chain.Group, blockId uint64) error {
+	state, err := nodectx.GetNodeCtx().GetChainStorage().GetPruneState(group.Item.GroupId, group.Nodename)
+	if err != nil {
+		return err
+	}
+	if blockId > 0 && blockId <= state.PrunedTo {
+		return fmt.Errorf("block %d is pruned, blocks before %d only have headers", blockId, state.PrunedTo+1)
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package handlers
+
+type SearchGroupCtnParams struct {
+	GroupId   string   `param:"group_id" json:"group_id" url:"-" validate:"required,uuid4"`
+	Keywords  string   `query:"q" json:"q" url:"q" validate:"required"`
+	Senders   []string `query:"senders" json:"senders" url:"senders"`
+	StartTime int64    `query:"start_time" json:"start_time" url:"start_time,omitempty" validate:"omitempty,min=0"`
+	EndTime   int64    `query:"end_time" json:"end_time" url:"end_time,omitempty" validate:"omitempty,min=0"`
+	StartTrx  string   `query:"start_trx" json:"start_trx" url:"start_trx,omitempty" validate:"omitempty,uuid4"`
+	Num       int      `query:"num" json:"num" url:"num"`
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	chain "github.com/rumsystem/quorum/internal/pkg/chainsdk/core"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	chainstorage "github.com/rumsystem/quorum/internal/pkg/storage/chain"
+	"github.com/rumsystem/quorum/internal/pkg/storage/def"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+)
+
+type StakeParam struct {
+	GroupId      string `json:"group_id" validate:"required,uuid4" example:"5ed3f9fe-81e2-450d-9146-7a329aac2b62"`
+	StakerPubkey string `json:"staker_pubkey" example:"CAISIQOxCH2yVZPR8t6gVvZapxcIPBwMh9jB80pDLNeuA5s8hQ=="` // staker to add stake to (owner only), or withdraw from, default to myself
+	Action       string `json:"action" validate:"required,oneof=add remove" example:"add"`
+	Amount       int64  `json:"amount" validate:"required,gt=0" example:"100"`
+	Memo         string `json:"memo" example:"comment/remark"`
+}
+
+type StakeResult struct {
+	TrxId        string `json:"trx_id" validate:"required,uuid4" example:"6bff5556-4dc9-4cb6-a595-2181aaebdc26"`
+	GroupId      string `json:"group_id" validate:"required,uuid4" example:"5ed3f9fe-81e2-450d-9146-7a329aac2b62"`
+	StakerPubkey string `json:"staker_pubkey" example:"CAISIQOxCH2yVZPR8t6gVvZapxcIPBwMh9jB80pDLNeuA5s8hQ=="`
+	Action       string `json:"action" example:"add"`
+	Amount       int64  `json:"amount" example:"100"`
+	Memo         string `json:"memo" example:"comment/remark"`
+}
+
+type StakeListItem struct {
+	StakerPubkey string `example:"CAISIQOxCH2yVZPR8t6gVvZapxcIPBwMh9jB80pDLNeuA5s8hQ=="`
+	Amount       int64  `example:"100"`
+	TimeStamp    int64  `example:"1634756661280204800"`
+	Memo         string `example:"comment/remark"`
+}
+
+func GroupStake(params *StakeParam) (*StakeResult, error) {
+	groupmgr := chain.GetGroupMgr()
+	group, ok := groupmgr.Groups[params.GroupId]
+	if !ok {
+		return nil, rumerrors.ErrGroupNotFound
+	}
+
+	if group.Item.ConsenseType != quorumpb.GroupConsenseType_POS {
+		return nil, rumerrors.ErrConsensusTypeNotSupported
+	}
+
+	if params.Amount > chainstorage.MAX_STAKE {
+		return nil, fmt.Errorf("stake amount should not be larger than %d", chainstorage.MAX_STAKE)
+	}
+
+	isOwner := group.Item.OwnerPubKey == group.Item.UserSignPubkey
+	stakerPubkey := params.StakerPubkey
+	if stakerPubkey == "" {
+		stakerPubkey = group.Item.UserSignPubkey
+	}
+
+	//stake is granted by group owner, a staker can only withdraw its own stake
+	if params.Action == "add" && !isOwner {
+		return nil, rumerrors.ErrOnlyGroupOwner
+	}
+	if stakerPubkey != group.Item.UserSignPubkey && !isOwner {
+		return nil, rumerrors.ErrOnlyGroupOwner
+	}
+
+	item := &quorumpb.StakeItem{}
+	item.GroupId = params.GroupId
+	item.StakerPubkey = stakerPubkey
+	item.Amount = params.Amount
+	item.Memo = params.Memo
+	item.TimeStamp = time.Now().UnixNano()
+	if params.Action == "add" {
+		item.Action = quorumpb.ActionType_ADD
+	} else {
+		item.Action = quorumpb.ActionType_REMOVE
+	}
+
+	trxId, err := group.UpdStake(item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StakeResult{
+		TrxId:        trxId,
+		GroupId:      params.GroupId,
+		StakerPubkey: stakerPubkey,
+		Action:       params.Action,
+		Amount:       params.Amount,
+		Memo:         params.Memo,
+	}, nil
+}
+
+func GetGroupStakes(chainapidb def.APIHandlerIface, groupid string) ([]*StakeListItem, error) {
+	groupmgr := chain.GetGroupMgr()
+	group, ok := groupmgr.Groups[groupid]
+	if !ok {
+		return nil, fmt.Errorf("Group %s not exist", groupid)
+	}
+
+	stakes, err := chainapidb.GetStakes(group.GroupId, group.Nodename)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*StakeListItem{}
+	for _, stake := range stakes {
+		result = append(result, &StakeListItem{
+			StakerPubkey: stake.StakerPubkey,
+			Amount:       stake.Amount,
+			TimeStamp:    stake.TimeStamp,
+			Memo:         stake.Memo,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	chain "github.com/rumsystem/quorum/internal/pkg/chainsdk/core"
+	chaindef "github.com/rumsystem/quorum/internal/pkg/chainsdk/def"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+)
+
+type GetSyncProgressParam struct {
+	GroupId string `param:"group_id" validate:"required,uuid4" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+}
+
+func GetSyncProgress(groupId string) (*chaindef.SyncProgress, error) {
+	groupmgr := chain.GetGroupMgr()
+	group, ok := groupmgr.Groups[groupId]
+	if !ok {
+		return nil, rumerrors.ErrGroupNotFound
+	}
+
+	return group.ChainCtx.GetSyncProgress(), nil
+}
+
+// GetAllSyncProgress return sync progress of all joined groups
+func GetAllSyncProgress() []*chaindef.SyncProgress {
+	result := []*chaindef.SyncProgress{}
+	groupmgr := chain.GetGroupMgr()
+	for _, group := range groupmgr.Groups {
+		result = append(result, group.ChainCtx.GetSyncProgress())
+	}
+	return result
+}
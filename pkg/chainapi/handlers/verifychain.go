@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	chain "github.com/rumsystem/quorum/internal/pkg/chainsdk/core"
+	chaindef "github.com/rumsystem/quorum/internal/pkg/chainsdk/def"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+)
+
+type VerifyChainParam struct {
+	GroupId string `param:"group_id" json:"-" validate:"required,uuid4" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	Repair  bool   `json:"repair" example:"false"` // replace mismatched derived tables by the replay result
+}
+
+// VerifyChain checks blocks of a group from genesis and cross-checks its derived tables against a replay,
+// blocks applied while verifying may be reported as state mismatch, verify again to confirm
+func VerifyChain(params *VerifyChainParam) (*chaindef.ChainVerifyResult, error) {
+	group, ok := chain.GetGroupMgr().Groups[params.GroupId]
+	if !ok {
+		return nil, rumerrors.ErrGroupNotFound
+	}
+
+	return group.ChainCtx.VerifyChain(params.Repair)
+}
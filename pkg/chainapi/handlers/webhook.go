@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	guuid "github.com/google/uuid"
+	"github.com/rumsystem/quorum/internal/pkg/appdata"
+	chain "github.com/rumsystem/quorum/internal/pkg/chainsdk/core"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+)
+
+type AddWebhookParam struct {
+	GroupId   string   `param:"group_id" json:"-" validate:"required,uuid4" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	Url       string   `json:"url" validate:"required,url" example:"https://example.com/quorum/webhook"`
+	TrxTypes  []string `json:"trx_types" example:"POST"` // empty for all
+	Secret    string   `json:"secret" validate:"omitempty,min=16" example:"0b7fd5e5a7d4a3e7c6a2f0d4f4e1c2b3"`
+	FromBlock *uint64  `json:"from_block" example:"0"` // default is the next block, set 0 to replay the whole chain
+}
+
+type WebhookParam struct {
+	GroupId   string `param:"group_id" validate:"required,uuid4" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	WebhookId string `param:"webhook_id" validate:"required,uuid4" example:"5d3ef6c4-3b4e-4a2e-9f39-a7f8b9d0c1e2"`
+}
+
+type ReplayWebhookParam struct {
+	GroupId   string `param:"group_id" json:"-" validate:"required,uuid4" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	WebhookId string `param:"webhook_id" json:"-" validate:"required,uuid4" example:"5d3ef6c4-3b4e-4a2e-9f39-a7f8b9d0c1e2"`
+	FromBlock uint64 `json:"from_block" example:"100"`
+}
+
+type GetWebhookDeliveriesParam struct {
+	GroupId   string `param:"group_id" validate:"required,uuid4" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	WebhookId string `param:"webhook_id" validate:"required,uuid4" example:"5d3ef6c4-3b4e-4a2e-9f39-a7f8b9d0c1e2"`
+	State     string `query:"state" validate:"omitempty,oneof=SUCCESS RETRY FAIL PRUNED" example:"FAIL"`
+	Num       int    `query:"num" validate:"omitempty,gte=1,lte=1000" example:"100"`
+}
+
+type WebhookListResult struct {
+	GroupId  string             `json:"group_id" example:"ac0eea7c-2f3c-4c67-80b3-136e46b924a8"`
+	Webhooks []*appdata.Webhook `json:"webhooks"`
+}
+
+type WebhookDeliveriesResult struct {
+	WebhookId  string                     `json:"webhook_id" example:"5d3ef6c4-3b4e-4a2e-9f39-a7f8b9d0c1e2"`
+	Deliveries []*appdata.WebhookDelivery `json:"deliveries"`
+}
+
+// AddWebhook registers a webhook of a group, the secret is generated if not given and only returned here
+func AddWebhook(params *AddWebhookParam, appdb *appdata.AppDb) (*appdata.Webhook, error) {
+	group, ok := chain.GetGroupMgr().Groups[params.GroupId]
+	if !ok {
+		return nil, rumerrors.ErrGroupNotFound
+	}
+
+	u, err := url.Parse(params.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid webhook url: %s", params.Url)
+	}
+	for _, t := range params.TrxTypes {
+		if _, ok := quorumpb.TrxType_value[t]; !ok {
+			return nil, fmt.Errorf("invalid trx type: %s", t)
+		}
+	}
+
+	secret := params.Secret
+	if secret == "" {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(buf)
+	}
+
+	nextBlock := group.GetCurrentBlockId() + 1
+	if params.FromBlock != nil {
+		nextBlock = *params.FromBlock
+		if err := checkBlockNotPruned(group, nextBlock); err != nil {
+			return nil, err
+		}
+	}
+
+	w := &appdata.Webhook{
+		Id:        guuid.NewString(),
+		GroupId:   params.GroupId,
+		Url:       params.Url,
+		TrxTypes:  params.TrxTypes,
+		Secret:    secret,
+		CreatedAt: time.Now().UnixNano(),
+		NextBlock: nextBlock,
+	}
+	if err := appdb.AddWebhook(w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// GetWebhooks returns webhooks of a group without secret
+func GetWebhooks(groupId string, appdb *appdata.AppDb) (*WebhookListResult, error) {
+	webhooks, err := appdb.GetWebhooks(groupId)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range webhooks {
+		w.Secret = ""
+	}
+	return &WebhookListResult{GroupId: groupId, Webhooks: webhooks}, nil
+}
+
+func RemoveWebhook(params *WebhookParam, appdb *appdata.AppDb) error {
+	return appdb.RemoveWebhook(params.GroupId, params.WebhookId)
+}
+
+// ReplayWebhook redelivers events from a block, events after the block will be delivered again
+func ReplayWebhook(params *ReplayWebhookParam, appdb *appdata.AppDb) (*appdata.Webhook, error) {
+	group, ok := chain.GetGroupMgr().Groups[params.GroupId]
+	if !ok {
+		return nil, rumerrors.ErrGroupNotFound
+	}
+	if params.FromBlock > group.GetCurrentBlockId()+1 {
+		return nil, errors.New("from_block is larger than the next block of the group")
+	}
+	if err := checkBlockNotPruned(group, params.FromBlock); err != nil {
+		return nil, err
+	}
+
+	w, err := appdb.ReplayWebhook(params.GroupId, params.WebhookId, params.FromBlock)
+	if err != nil {
+		return nil, err
+	}
+	w.Secret = ""
+	return w, nil
+}
+
+func GetWebhookDeliveries(params *GetWebhookDeliveriesParam, appdb *appdata.AppDb) (*WebhookDeliveriesResult, error) {
+	if _, err := appdb.GetWebhook(params.GroupId, params.WebhookId); err != nil {
+		return nil, err
+	}
+
+	num := params.Num
+	if num == 0 {
+		num = 100
+	}
+	deliveries, err := appdb.GetWebhookDeliveries(params.WebhookId, params.State, num)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookDeliveriesResult{WebhookId: params.WebhookId, Deliveries: deliveries}, nil
+}
@@ -1,331 +1,456 @@
 package consensus
 
-/*
-
 import (
 	"fmt"
 
-	"github.com/golang/protobuf/proto"
 	"github.com/rumsystem/quorum/internal/pkg/logging"
+	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
 	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/proto"
 )
 
-var bba_log = logging.Logger("bba")
-
-type AgreementMessage struct {
-	Epoch   int
-	Message interface{}
-}
-
-type BValRequest struct {
-	Value bool
-}
-
-type AuxRequest struct {
-	Value bool
-}
-
-type (
-	bbaMessageT struct {
-		senderId string
-		msg      *AgreementMessage
-		err      chan error
-	}
-
-	bbaInputT struct {
-		value bool
-		err   chan error
-	}
-
-	delayedMessage struct {
-		senderId string
-		msg      *AgreementMessage
-	}
-)
-
+var trx_bba_log = logging.Logger("tbba")
+
+// BBA is the binary byzantine agreement (Mostefaoui et al.) of one proposer in an ACS epoch,
+// it decides if proposal of the proposer will be included.
+// nodes are counted by stake, f is the faulty stake and a quorum holds more than 2/3 of total stake, see Config.
+// each round
+//  1. broadcast BVAL(est), relay BVAL(v) after received it from nodes hold more than f
+//  2. add v to binValues after received BVAL(v) from nodes hold more than 2f, broadcast AUX(v) for the first one
+//  3. wait AUX from a quorum with values in binValues, then broadcast CONF(binValues)
+//  4. wait CONF from a quorum with values in binValues, vals is the union of them, then get coin s of the round
+//     if vals is {v}, est = v and decide v if v == s, otherwise est = s
+//
+// s is the threshold coin, a node broadcasts its coin share of the round after step 4 and s is combined from
+// faultyNodes + 1 verified shares, so nobody knows it before an honest node finished step 4. CONF fixes vals of honest
+// nodes before that, otherwise a scheduler seeing the coin early could keep honest nodes split forever.
+// there is no coin before the threshold key is ready, rounds wait for it, see TrxBft.thresholdKeyReady.
+// all messages are signed by the sender and bound to the acs epoch.
+//
+// after decided, the instance keeps running till the next round with coin equals to the decision,
+// all honest nodes decide in this round, so they will not wait for it
 type BBA struct {
 	Config
-	acs       *ACS
-	groupId   string
-	epoch     uint32
-	binValues []bool
-	sentBvals []bool
-	recvBval  map[string]bool
-	recvAux   map[string]bool
-
-	done      bool
-	output    interface{}
-	estimated interface{}
-	decision  interface{}
-
-	delayedMessages []*quorumpb.AgreementMsg
-	msgCount        int
-}
 
-func NewBBA(cfg Config, acs *ACS, groupId string) *BBA {
-	if cfg.F == 0 {
-		cfg.F = (cfg.N - 1) / 3
-	}
+	groupId        string
+	proposerPubkey string
+
+	round     uint64
+	est       bool
+	hasInput  bool
+	binValues map[bool]bool
+	bvalSent  map[uint64]map[bool]bool
+	auxSent   map[uint64]bool
+	confSent  map[uint64]bool
+
+	recvBvals  map[uint64]map[bool]map[string]bool       //round -> value -> sender
+	recvAuxs   map[uint64]map[string]bool                //round -> sender -> value
+	recvConfs  map[uint64]map[string]map[bool]bool       //round -> sender -> values
+	recvCoins  map[uint64]map[string]*quorumpb.CoinShare //round -> sender -> coin share not verified yet
+	coinShares map[uint64]map[int][]byte                 //round -> share index -> verified coin share
+	coinSent   map[uint64]bool                           //round -> my coin share sent
+	coins      map[uint64]bool                           //round -> combined threshold coin
+
+	decided      bool
+	decision     bool
+	decidedRound uint64
+	halted       bool
+
+	acs *TrxACS //for callback when decided
+}
 
-	bba := &BBA{
-		Config:          cfg,
-		acs:             acs,
-		groupId:         groupId,
-		recvBval:        make(map[string]bool),
-		recvAux:         make(map[string]bool),
-		sentBvals:       []bool{},
-		binValues:       []bool{},
-		delayedMessages: []*quorumpb.AgreementMsg{},
+// NewBBA creates the bba of a proposer
+func NewBBA(cfg Config, acs *TrxACS, groupId, proposerPubkey string) *BBA {
+	trx_bba_log.Debugf("NewBBA called, Epoch <%d>, proposer <%s>", acs.Epoch, proposerPubkey)
+	return &BBA{
+		Config:         cfg,
+		acs:            acs,
+		groupId:        groupId,
+		proposerPubkey: proposerPubkey,
+		binValues:      make(map[bool]bool),
+		bvalSent:       make(map[uint64]map[bool]bool),
+		auxSent:        make(map[uint64]bool),
+		confSent:       make(map[uint64]bool),
+		recvBvals:      make(map[uint64]map[bool]map[string]bool),
+		recvAuxs:       make(map[uint64]map[string]bool),
+		recvConfs:      make(map[uint64]map[string]map[bool]bool),
+		recvCoins:      make(map[uint64]map[string]*quorumpb.CoinShare),
+		coinShares:     make(map[uint64]map[int][]byte),
+		coinSent:       make(map[uint64]bool),
+		coins:          make(map[uint64]bool),
 	}
-
-	return bba
 }
 
+// InputValue gives the initial estimation, it is ignored if the instance already left round 0 by messages from others
 func (b *BBA) InputValue(val bool) error {
-	if b.epoch != 0 || b.estimated != nil {
+	trx_bba_log.Debugf("<%s> InputValue called, value <%v>", b.proposerPubkey, val)
+	if b.hasInput || b.round > 0 || b.halted {
 		return nil
 	}
-
-	b.estimated = val
-	b.sentBvals = append(b.sentBvals, val)
-
-	msg, err := b.makeBValMsg(val)
-	if err != nil {
+	b.hasInput = true
+	b.est = val
+	if err := b.sendBval(0, val); err != nil {
 		return err
 	}
+	return b.processRound()
+}
 
-	SendHbbAgreement(b.groupId, msg)
-	return b.handleBvalRequest(msg)
+func (b *BBA) HasInput() bool {
+	return b.hasInput || b.round > 0
 }
 
-func (b *BBA) makeBValMsg(val bool) (*quorumpb.AgreementMsg, error) {
-	bval := &quorumpb.BvalReq{
-		Value: val,
+func (b *BBA) handleBvalMsg(bval *quorumpb.Bval) error {
+	if b.halted || bval.Epoch < 0 || uint64(bval.Epoch) < b.round {
+		return nil
 	}
-
-	bvalb, err := proto.Marshal(bval)
-	if err != nil {
-		return nil, err
+	round := uint64(bval.Epoch)
+	if _, ok := b.recvBvals[round]; !ok {
+		b.recvBvals[round] = make(map[bool]map[string]bool)
 	}
-
-	msg := &quorumpb.AgreementMsg{
-		Type:     quorumpb.AgreementMsgType_BVAL_REQ,
-		SenderId: b.MyNodeId,
-		Epoch:    int64(b.epoch),
-		Payload:  bvalb,
+	if _, ok := b.recvBvals[round][bval.Value]; !ok {
+		b.recvBvals[round][bval.Value] = make(map[string]bool)
 	}
-
-	return msg, nil
+	b.recvBvals[round][bval.Value][bval.SenderPubkey] = true
+	return b.processRound()
 }
 
-func (b *BBA) makeAuxMsg(val bool) (*quorumpb.AgreementMsg, error) {
-	aux := &quorumpb.AuxReq{
-		Value: val,
+func (b *BBA) handleAuxMsg(aux *quorumpb.Aux) error {
+	if b.halted || aux.Epoch < b.round {
+		return nil
 	}
-
-	auxb, err := proto.Marshal(aux)
-	if err != nil {
-		return nil, err
+	if _, ok := b.recvAuxs[aux.Epoch]; !ok {
+		b.recvAuxs[aux.Epoch] = make(map[string]bool)
 	}
-
-	msg := &quorumpb.AgreementMsg{
-		Type:     quorumpb.AgreementMsgType_AUX_REQ,
-		SenderId: b.MyNodeId,
-		Epoch:    int64(b.epoch),
-		Payload:  auxb,
+	//only the first AUX of a sender in a round counts
+	if _, ok := b.recvAuxs[aux.Epoch][aux.SenderPubkey]; !ok {
+		b.recvAuxs[aux.Epoch][aux.SenderPubkey] = aux.Value
 	}
-
-	return msg, nil
-}
-
-func (b *BBA) AcceptInput() bool {
-	return b.epoch == 0 && b.estimated == nil
+	return b.processRound()
 }
 
-//send message to messageCh
-func (b *BBA) HandleMessage(msg *quorumpb.AgreementMsg) error {
-	b.msgCount++
-	if b.done {
+func (b *BBA) handleConfMsg(conf *quorumpb.Conf) error {
+	if b.halted || conf.Epoch < b.round || len(conf.Values) == 0 {
 		return nil
 	}
+	if _, ok := b.recvConfs[conf.Epoch]; !ok {
+		b.recvConfs[conf.Epoch] = make(map[string]map[bool]bool)
+	}
+	//only the first CONF of a sender in a round counts
+	if _, ok := b.recvConfs[conf.Epoch][conf.SenderPubkey]; !ok {
+		values := make(map[bool]bool)
+		for _, v := range conf.Values {
+			values[v] = true
+		}
+		b.recvConfs[conf.Epoch][conf.SenderPubkey] = values
+	}
+	return b.processRound()
+}
 
-	if msg.Epoch < int64(b.epoch) {
+// coin shares are verified when the round needs the coin, the threshold key may not be ready here yet
+func (b *BBA) handleCoinMsg(coin *quorumpb.CoinShare) error {
+	if b.halted || coin.Epoch < b.round {
 		return nil
 	}
-
-	if msg.Epoch > int64(b.epoch) {
-		b.delayedMessages = append(b.delayedMessages, msg)
+	if _, ok := b.recvCoins[coin.Epoch]; !ok {
+		b.recvCoins[coin.Epoch] = make(map[string]*quorumpb.CoinShare)
 	}
-
-	switch msg.Type {
-	case quorumpb.AgreementMsgType_BVAL_REQ:
-		return b.handleBvalRequest(msg)
-	case quorumpb.AgreementMsgType_AUX_REQ:
-		return b.handleAuxRequest(msg)
-	default:
-		return fmt.Errorf("Unkonwn BBA message")
+	if _, ok := b.recvCoins[coin.Epoch][coin.SenderPubkey]; !ok {
+		b.recvCoins[coin.Epoch][coin.SenderPubkey] = coin
 	}
-
+	return b.processRound()
 }
 
-func (b *BBA) handleBvalRequest(msg *quorumpb.AgreementMsg) error {
-
-	bvalreq := &quorumpb.BvalReq{}
-	err := proto.Unmarshal(msg.Payload, bvalreq)
-	if err != nil {
-		return err
-	}
-	b.recvBval[msg.SenderId] = bvalreq.Value
-	lenBval := b.countBvals(bvalreq.Value)
-
-	//2f + 1node
-	if lenBval == 2*b.F+1 {
-		wasEmptyBinValues := len(b.binValues) == 0
-		b.binValues = append(b.binValues, bvalreq.Value)
+// processRound runs the current round with received messages, messages of later rounds are kept till the round starts
+func (b *BBA) processRound() error {
+	for !b.halted {
+		round := b.round
+		for _, v := range []bool{false, true} {
+			senders := b.recvBvals[round][v]
+			if b.exceeds(senders, b.f) && !b.bvalSent[round][v] {
+				if err := b.sendBval(round, v); err != nil {
+					return err
+				}
+			}
+			if b.exceeds(senders, 2*b.f) && !b.binValues[v] {
+				b.binValues[v] = true
+				if !b.auxSent[round] {
+					b.auxSent[round] = true
+					if err := b.sendAux(round, v); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		if len(b.binValues) == 0 {
+			return nil
+		}
 
-		if wasEmptyBinValues {
-			//b.addMessage(NewAgreementMsg(int(b.epoch), &AuxRequest{val}))
-			auxMsg, err := b.makeAuxMsg(bvalreq.Value)
-			if err != nil {
+		if !b.confSent[round] {
+			senders := make(map[string]bool)
+			for sender, v := range b.recvAuxs[round] {
+				if b.binValues[v] {
+					senders[sender] = true
+				}
+			}
+			if !b.isQuorum(senders) {
+				return nil
+			}
+			b.confSent[round] = true
+			if err := b.sendConf(round); err != nil {
 				return err
 			}
-			SendHbbAgreement(b.groupId, auxMsg)
-			b.handleAuxRequest(auxMsg)
+		}
 
+		//binValues may grow after CONF sent, CONF of others are counted with the latest one
+		vals := make(map[bool]bool)
+		senders := make(map[string]bool)
+		for sender, values := range b.recvConfs[round] {
+			if !b.hasBinValues(values) {
+				continue
+			}
+			for v := range values {
+				vals[v] = true
+			}
+			senders[sender] = true
+		}
+		if !b.isQuorum(senders) {
+			return nil
 		}
 
-		return nil
-	}
+		s, ok, err := b.getCoin(round)
+		if err != nil || !ok {
+			return err
+		}
+		trx_bba_log.Debugf("<%s> round <%d> done, vals <%v>, coin <%v>", b.proposerPubkey, round, vals, s)
+		if len(vals) == 1 {
+			v := vals[true]
+			b.est = v
+			if v == s {
+				if !b.decided {
+					b.decided = true
+					b.decision = v
+					b.decidedRound = round
+					trx_bba_log.Debugf("<%s> decided <%v> at round <%d>", b.proposerPubkey, v, round)
+					b.acs.BbaDone(b.proposerPubkey, v)
+				} else if round > b.decidedRound {
+					b.halted = true
+				}
+			}
+		} else {
+			b.est = s
+		}
 
-	if lenBval == b.F+1 && !b.hasSentBval(bvalreq.Value) {
-		b.sentBvals = append(b.sentBvals, bvalreq.Value)
+		if b.halted {
+			trx_bba_log.Debugf("<%s> halted at round <%d>", b.proposerPubkey, round)
+			return nil
+		}
 
-		bvalMsg, err := b.makeBValMsg(bvalreq.Value)
-		if err != nil {
+		//start next round
+		delete(b.recvBvals, round)
+		delete(b.recvAuxs, round)
+		delete(b.bvalSent, round)
+		delete(b.auxSent, round)
+		delete(b.recvConfs, round)
+		delete(b.confSent, round)
+		delete(b.recvCoins, round)
+		delete(b.coinShares, round)
+		delete(b.coinSent, round)
+		delete(b.coins, round)
+		b.round = round + 1
+		b.binValues = make(map[bool]bool)
+		if err := b.sendBval(b.round, b.est); err != nil {
 			return err
 		}
-		SendHbbAgreement(b.groupId, bvalMsg)
-		//b.addMessage(NewAgreementMsg(int(b.epoch), &BValRequest{val}))
-		return b.handleBvalRequest(bvalMsg)
 	}
-
 	return nil
 }
 
-func (b *BBA) handleAuxRequest(msg *quorumpb.AgreementMsg) error {
-	auxReq := &quorumpb.AuxReq{}
-	err := proto.Unmarshal(msg.Payload, auxReq)
-	if err != nil {
-		return err
+// hasBinValues return if all values are in binValues of the current round
+func (b *BBA) hasBinValues(values map[bool]bool) bool {
+	for v := range values {
+		if !b.binValues[v] {
+			return false
+		}
 	}
-	b.recvAux[msg.SenderId] = auxReq.Value
-	return b.tryOutputAgreement()
+	return true
 }
 
-func (b *BBA) tryOutputAgreement() error {
-	if len(b.binValues) == 0 {
-		return nil
+// getCoin return the coin of round, ok is false if shares of the threshold coin are not enough yet
+func (b *BBA) getCoin(round uint64) (value bool, ok bool, err error) {
+	if value, ok := b.coins[round]; ok {
+		return value, true, nil
 	}
 
-	lenOutputs, values := b.countOutputs()
-	if lenOutputs < b.N-b.F {
-		return nil
+	key := b.acs.bft.dkg.ReadyKey()
+	if key == nil {
+		trx_bba_log.Debugf("<%s> round <%d> wait for the threshold key to get the coin", b.proposerPubkey, round)
+		return false, false, nil
 	}
-
-	coin := b.epoch%2 == 0
-
-	if b.done || b.decision != nil && b.decision.(bool) == coin {
-		b.done = true
-		return nil
+	name := b.coinName(round)
+	if !b.coinSent[round] && key.Share != nil {
+		b.coinSent[round] = true
+		if err := b.sendCoinShare(key, round, name); err != nil {
+			return false, false, err
+		}
 	}
 
-	bba_log.Infof("Node (%s) is advancing to next epoch (%d)，received %d aux messages", b.MyNodeId, b.epoch+1, lenOutputs)
-	b.advanceEpoch()
-
-	if len(values) != 1 {
-		b.estimated = coin
-	} else {
-		b.estimated = values[0]
-
-		if b.decision == nil && values[0] == coin {
-			b.output = values[0]
-			b.decision = values[0]
-			b.msgCount = 0
+	if _, ok := b.coinShares[round]; !ok {
+		b.coinShares[round] = make(map[int][]byte)
+	}
+	for sender, coin := range b.recvCoins[round] {
+		//any Threshold shares give the same coin, others are not verified
+		if len(b.coinShares[round]) >= key.Threshold {
+			break
+		}
+		index := b.acs.bft.dkg.indexOf(sender)
+		if err := key.VerifyCoinShare(index, name, coin.Share, coin.Proof); err != nil {
+			trx_bba_log.Warnf("<%s> invalid coin share from <%s>: %s", b.proposerPubkey, sender, err)
+		} else {
+			b.coinShares[round][index] = coin.Share
 		}
+		delete(b.recvCoins[round], sender)
+	}
+	if len(b.coinShares[round]) < key.Threshold {
+		return false, false, nil
 	}
 
-	estimated := b.estimated.(bool)
-	b.sentBvals = append(b.sentBvals, estimated)
-
-	//b.addMessage(NewAgreementMsg(int(b.epoch), &BValRequest{estimated}))
-
-	bvalMsg, err := b.makeBValMsg(estimated)
+	value, err = key.CombineCoin(b.coinShares[round])
 	if err != nil {
-		return err
+		return false, false, err
 	}
+	b.coins[round] = value
+	return value, true, nil
+}
 
-	SendHbbAgreement(b.groupId, bvalMsg)
+// coinName identifies the coin of a round, coins of different bba instances and rounds are independent
+func (b *BBA) coinName(round uint64) []byte {
+	return []byte(fmt.Sprintf("%s:%d:%s:%d", b.groupId, b.acs.Epoch, b.proposerPubkey, round))
+}
 
-	for _, que := range b.delayedMessages {
-		if err := b.HandleMessage(que); err != nil {
-			bba_log.Warn(err)
-		}
+func (b *BBA) sendBval(round uint64, val bool) error {
+	if _, ok := b.bvalSent[round]; !ok {
+		b.bvalSent[round] = make(map[bool]bool)
 	}
+	b.bvalSent[round][val] = true
 
-	b.delayedMessages = []*quorumpb.AgreementMsg{}
-
-	return nil
+	return b.send(&quorumpb.Bval{
+		ProposerId:   b.proposerPubkey,
+		SenderPubkey: b.MyPubkey,
+		Epoch:        int64(round),
+		Value:        val,
+		AcsEpoch:     b.acs.Epoch,
+	})
 }
 
-// clear all and advance epoch
-func (b *BBA) advanceEpoch() {
-	b.binValues = []bool{}
-	b.sentBvals = []bool{}
-	b.recvAux = make(map[string]bool)
-	b.recvBval = make(map[string]bool)
-	b.epoch++
+func (b *BBA) sendAux(round uint64, val bool) error {
+	return b.send(&quorumpb.Aux{
+		ProposerId:   b.proposerPubkey,
+		SenderPubkey: b.MyPubkey,
+		Epoch:        round,
+		Value:        val,
+		AcsEpoch:     b.acs.Epoch,
+	})
 }
 
-func (b *BBA) countOutputs() (int, []bool) {
-	m := map[bool]string{}
-	for senderId, val := range b.recvAux {
-		m[val] = senderId
+// sendConf sends bin values of the round
+func (b *BBA) sendConf(round uint64) error {
+	var values []bool
+	for _, v := range []bool{false, true} {
+		if b.binValues[v] {
+			values = append(values, v)
+		}
 	}
+	return b.send(&quorumpb.Conf{
+		ProposerId:   b.proposerPubkey,
+		SenderPubkey: b.MyPubkey,
+		Epoch:        round,
+		Values:       values,
+		AcsEpoch:     b.acs.Epoch,
+	})
+}
 
-	vals := []bool{}
-	for _, val := range b.binValues {
-		if _, ok := m[val]; ok {
-			vals = append(vals, val)
-		}
+func (b *BBA) sendCoinShare(key *localcrypto.ThresholdKey, round uint64, name []byte) error {
+	share, proof, err := key.CoinShare(name)
+	if err != nil {
+		return err
 	}
-	return len(b.recvAux), vals
+	return b.send(&quorumpb.CoinShare{
+		ProposerId:   b.proposerPubkey,
+		SenderPubkey: b.MyPubkey,
+		Epoch:        round,
+		AcsEpoch:     b.acs.Epoch,
+		Share:        share,
+		Proof:        proof,
+	})
 }
 
-func (b *BBA) countBvals(ok bool) int {
-	n := 0
-	for _, val := range b.recvBval {
-		if val == ok {
-			n++
-		}
+func (b *BBA) send(msg proto.Message) error {
+	bbaMsg, err := MakeBBAMessage(b.acs.bft.ks, b.groupId, b.acs.bft.producer.nodename, msg)
+	if err != nil {
+		return err
 	}
-	return n
+	return SendHBAABMsg(b.acs.bft.broadcaster, b.groupId, bbaMsg, b.acs.Epoch)
 }
 
-func (b *BBA) hasSentBval(val bool) bool {
-	for _, ok := range b.sentBvals {
-		if ok == val {
-			return true
-		}
+// MakeBBAMessage signs a Bval, Aux, Conf or CoinShare by the sender and wraps it
+func MakeBBAMessage(ks localcrypto.Keystore, groupId, nodename string, msg proto.Message) (*quorumpb.BBAMsg, error) {
+	var msgType quorumpb.BBAMsgType
+	var sign *[]byte
+	switch m := msg.(type) {
+	case *quorumpb.Bval:
+		msgType, sign = quorumpb.BBAMsgType_BVAL, &m.SenderSign
+	case *quorumpb.Aux:
+		msgType, sign = quorumpb.BBAMsgType_AUX, &m.SenderSign
+	case *quorumpb.Conf:
+		msgType, sign = quorumpb.BBAMsgType_CONF, &m.SenderSign
+	case *quorumpb.CoinShare:
+		msgType, sign = quorumpb.BBAMsgType_COIN, &m.SenderSign
+	default:
+		return nil, fmt.Errorf("unknown bba message <%T>", msg)
 	}
-	return false
+
+	*sign = nil
+	bbytes, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	*sign, err = ks.EthSignByKeyName(groupId, localcrypto.Hash(bbytes), nodename)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &quorumpb.BBAMsg{Type: msgType, Payload: payload}, nil
 }
 
-func (b *BBA) Output() interface{} {
-	if b.output != nil {
-		out := b.output
-		b.output = nil
-		return out
+// VerifyBBASign verifies signature of Bval, Aux, Conf or CoinShare by pubkey of the sender in it
+func VerifyBBASign(ks localcrypto.Keystore, msg proto.Message) error {
+	var pubkey string
+	var sign []byte
+	unsigned := proto.Clone(msg)
+	switch m := unsigned.(type) {
+	case *quorumpb.Bval:
+		pubkey, sign = m.SenderPubkey, m.SenderSign
+		m.SenderSign = nil
+	case *quorumpb.Aux:
+		pubkey, sign = m.SenderPubkey, m.SenderSign
+		m.SenderSign = nil
+	case *quorumpb.Conf:
+		pubkey, sign = m.SenderPubkey, m.SenderSign
+		m.SenderSign = nil
+	case *quorumpb.CoinShare:
+		pubkey, sign = m.SenderPubkey, m.SenderSign
+		m.SenderSign = nil
+	default:
+		return fmt.Errorf("unknown bba message <%T>", msg)
 	}
-	return nil
+
+	bbytes, err := proto.Marshal(unsigned)
+	if err != nil {
+		return err
+	}
+	return verifyProducerSign(ks, pubkey, localcrypto.Hash(bbytes), sign)
 }
-*/
@@ -0,0 +1,289 @@
+package consensus
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// bbaNet runs the bba of one proposer among producers of an acs epoch, messages are delivered in order
+type bbaNet struct {
+	t        *testing.T
+	pubkeys  []string
+	keys     []*simKeystore
+	acs      []*TrxACS
+	proposer string
+	queue    []*quorumpb.HBMsgv1
+}
+
+type bbaLink struct{ net *bbaNet }
+
+func (l bbaLink) BroadcastHBMsg(groupId string, hbmsg *quorumpb.HBMsgv1) error {
+	l.net.queue = append(l.net.queue, hbmsg)
+	return nil
+}
+
+func (l bbaLink) BroadcastBlock(groupId string, block *quorumpb.Block) error { return nil }
+
+var bbaTestRuns int
+
+// newBbaNet creates producers with threshold keys dealt, so bba can get coins
+func newBbaNet(t *testing.T, n int) *bbaNet {
+	initSimCtx()
+	bbaTestRuns++
+	rng := rand.New(rand.NewSource(int64(bbaTestRuns)))
+	net := &bbaNet{t: t}
+	for i := 0; i < n; i++ {
+		ks := newSimKeystore(rng)
+		net.keys = append(net.keys, ks)
+		net.pubkeys = append(net.pubkeys, base64.RawURLEncoding.EncodeToString(ethcrypto.CompressPubkey(&ks.priv.PublicKey)))
+	}
+	net.proposer = net.pubkeys[0]
+
+	for i := 0; i < n; i++ {
+		cfg := NewConfig(net.pubkeys, nil, DEFAULT_BATCH_SIZE, net.pubkeys[i])
+		producer := &MolassesProducer{groupId: "bbagroup", nodename: fmt.Sprintf("bba-%d-producer-%d", bbaTestRuns, i)}
+		bft := newTrxBft(cfg, producer, net.keys[i], bbaLink{net})
+		acs := NewTrxACS(cfg, bft, 1)
+		bft.acsInsts = acs
+		net.acs = append(net.acs, acs)
+	}
+	net.dealThresholdKeys()
+	return net
+}
+
+// dealThresholdKeys gives producers threshold keys like a finished dkg
+func (net *bbaNet) dealThresholdKeys() {
+	n := len(net.acs)
+	dkg := net.acs[0].bft.dkg
+	var commitments [][][]byte
+	var shares [][][]byte
+	for i := 0; i < n; i++ {
+		c, s, err := localcrypto.NewDkgDeal(dkg.faultyNodes+1, n)
+		if err != nil {
+			net.t.Fatalf("NewDkgDeal failed: %s", err)
+		}
+		commitments = append(commitments, c)
+		shares = append(shares, s)
+	}
+	for _, acs := range net.acs {
+		d := acs.bft.dkg
+		var myShares [][]byte
+		for i := 0; i < n; i++ {
+			myShares = append(myShares, shares[i][d.myIndex])
+		}
+		key, err := localcrypto.NewThresholdKey(d.myIndex, d.faultyNodes+1, n, commitments, myShares)
+		if err != nil {
+			net.t.Fatalf("NewThresholdKey failed: %s", err)
+		}
+		d.key = key
+		d.ready = true
+	}
+}
+
+func (net *bbaNet) bba(i int) *BBA {
+	return net.acs[i].bbaInstances[net.proposer]
+}
+
+func (net *bbaNet) input(values ...bool) {
+	for i, v := range values {
+		if err := net.bba(i).InputValue(v); err != nil {
+			net.t.Fatalf("producer <%d> input failed: %s", i, err)
+		}
+	}
+}
+
+// run delivers all messages to all producers, return messages rejected
+func (net *bbaNet) run() int {
+	rejected := 0
+	for len(net.queue) > 0 {
+		hbmsg := net.queue[0]
+		net.queue = net.queue[1:]
+		for _, acs := range net.acs {
+			if err := acs.HandleMessage(hbmsg); err != nil {
+				rejected++
+			}
+		}
+	}
+	return rejected
+}
+
+func (net *bbaNet) checkAgreement() bool {
+	decision := net.bba(0).decision
+	for i := range net.acs {
+		bba := net.bba(i)
+		if !bba.decided || !bba.halted {
+			net.t.Fatalf("producer <%d> decided <%v> halted <%v> at round <%d>", i, bba.decided, bba.halted, bba.round)
+		}
+		if bba.decision != decision {
+			net.t.Fatalf("producer <%d> decided <%v>, producer 0 decided <%v>", i, bba.decision, decision)
+		}
+		if net.acs[i].bbaOutput[net.proposer] != decision {
+			net.t.Fatalf("producer <%d> acs got <%v>", i, net.acs[i].bbaOutput[net.proposer])
+		}
+	}
+	return decision
+}
+
+// dropMsgs delivers messages like run, but drops bba messages of type
+func (net *bbaNet) dropMsgs(msgType quorumpb.BBAMsgType) (dropped int) {
+	for len(net.queue) > 0 {
+		hbmsg := net.queue[0]
+		net.queue = net.queue[1:]
+		bbaMsg := &quorumpb.BBAMsg{}
+		proto.Unmarshal(hbmsg.Payload, bbaMsg)
+		if bbaMsg.Type == msgType {
+			dropped++
+			continue
+		}
+		for _, acs := range net.acs {
+			acs.HandleMessage(hbmsg)
+		}
+	}
+	return dropped
+}
+
+func TestBBADecideAndHalt(t *testing.T) {
+	for _, input := range []bool{true, false} {
+		net := newBbaNet(t, 4)
+		net.input(input, input, input, input)
+		if rejected := net.run(); rejected > 0 {
+			t.Fatalf("<%d> messages rejected", rejected)
+		}
+		if decision := net.checkAgreement(); decision != input {
+			t.Fatalf("unanimous input <%v>, but decided <%v>", input, decision)
+		}
+		for i := range net.acs {
+			//halts in a later round with coin equals to the decision
+			if bba := net.bba(i); bba.round <= bba.decidedRound {
+				t.Fatalf("producer <%d> decided at round <%d> halted at round <%d>", i, bba.decidedRound, bba.round)
+			}
+		}
+	}
+}
+
+func TestBBAMixedInputs(t *testing.T) {
+	for _, inputs := range [][]bool{
+		{true, true, false, false},
+		{true, false, false, false},
+		{false, true, true, true},
+	} {
+		net := newBbaNet(t, 4)
+		net.input(inputs...)
+		if rejected := net.run(); rejected > 0 {
+			t.Fatalf("<%d> messages rejected", rejected)
+		}
+		net.checkAgreement()
+	}
+}
+
+func TestBBAWaitsForRoundQuorum(t *testing.T) {
+	//a single BVAL can not move a round, 2f + 1 are needed to get a bin value
+	net := newBbaNet(t, 4)
+	net.input(true)
+	net.run()
+	for i := range net.acs {
+		bba := net.bba(i)
+		if len(bba.binValues) != 0 || bba.decided || bba.round != 0 {
+			t.Fatalf("producer <%d> moved with one BVAL: bin values <%v>, round <%d>", i, bba.binValues, bba.round)
+		}
+	}
+
+	//f + 1 BVAL(true) are relayed by all, so all of them decide
+	if err := net.bba(1).InputValue(true); err != nil {
+		t.Fatalf("input failed: %s", err)
+	}
+	net.run()
+	if !net.checkAgreement() {
+		t.Fatal("decided false")
+	}
+}
+func TestBBARejectForgedMessages(t *testing.T) {
+	net := newBbaNet(t, 4)
+	victim, forger := net.pubkeys[1], net.keys[2]
+
+	bval := &quorumpb.Bval{ProposerId: net.proposer, SenderPubkey: victim, Value: false, AcsEpoch: 1}
+	forged, err := MakeBBAMessage(forger, "bbagroup", "", bval)
+	if err != nil {
+		t.Fatalf("MakeBBAMessage failed: %s", err)
+	}
+	unsigned, _ := proto.Marshal(&quorumpb.Bval{ProposerId: net.proposer, SenderPubkey: victim, Value: false, AcsEpoch: 1})
+	otherEpoch, err := MakeBBAMessage(net.keys[1], "bbagroup", "", &quorumpb.Aux{ProposerId: net.proposer, SenderPubkey: victim, Value: false, AcsEpoch: 2})
+	if err != nil {
+		t.Fatalf("MakeBBAMessage failed: %s", err)
+	}
+
+	for name, bbaMsg := range map[string]*quorumpb.BBAMsg{
+		"signed by another producer": forged,
+		"not signed":                 {Type: quorumpb.BBAMsgType_BVAL, Payload: unsigned},
+		"of another epoch":           otherEpoch,
+	} {
+		payload, _ := proto.Marshal(bbaMsg)
+		hbmsg := &quorumpb.HBMsgv1{Epoch: 1, PayloadType: quorumpb.HBMsgPayloadType_BBA, Payload: payload}
+		if err := net.acs[0].HandleMessage(hbmsg); err == nil {
+			t.Fatalf("BBA message %s is accepted", name)
+		}
+	}
+	if len(net.bba(0).recvBvals) != 0 || len(net.bba(0).recvAuxs) != 0 {
+		t.Fatal("forged messages are counted")
+	}
+}
+
+func TestBBAThresholdCoinNeedsShares(t *testing.T) {
+	net := newBbaNet(t, 4)
+	net.input(true, true, true, true)
+
+	//drop all coin shares, rounds can not finish without the coin
+	net.dropMsgs(quorumpb.BBAMsgType_COIN)
+	for i := range net.acs {
+		if bba := net.bba(i); bba.decided || bba.round != 0 {
+			t.Fatalf("producer <%d> got the coin without coin shares", i)
+		}
+	}
+}
+
+func TestBBACoinAfterConf(t *testing.T) {
+	net := newBbaNet(t, 4)
+	net.input(true, false, true, false)
+
+	//without CONF of the round nobody reveals its coin share, so the coin is unknown while values can change
+	if dropped := net.dropMsgs(quorumpb.BBAMsgType_CONF); dropped == 0 {
+		t.Fatal("no CONF sent")
+	}
+	for i := range net.acs {
+		bba := net.bba(i)
+		if bba.coinSent[0] || len(bba.recvCoins[0]) != 0 || bba.round != 0 {
+			t.Fatalf("producer <%d> revealed the coin before CONF: sent <%v>, got <%d> shares", i, bba.coinSent[0], len(bba.recvCoins[0]))
+		}
+	}
+}
+
+func TestBBAWaitsForThresholdKey(t *testing.T) {
+	net := newBbaNet(t, 4)
+	for _, acs := range net.acs {
+		acs.bft.dkg.ready = false
+	}
+	net.input(true, true, true, true)
+	net.run()
+	for i := range net.acs {
+		if bba := net.bba(i); bba.decided || bba.round != 0 {
+			t.Fatalf("producer <%d> got the coin without the threshold key", i)
+		}
+	}
+
+	//rounds waiting for the coin go on after the key is ready
+	for _, acs := range net.acs {
+		acs.bft.dkg.ready = true
+		acs.bft.thresholdKeyReady()
+	}
+	net.run()
+	if !net.checkAgreement() {
+		t.Fatal("decided false")
+	}
+}
@@ -1,9 +1,68 @@
 package consensus
 
+import "sort"
+
+// Config of a bft, poa is taken as pos with stake 1 for each node.
+// f is the most stake faulty nodes hold, f * 3 < total stake, a quorum holds more than 2/3 of total stake.
+// shares of the threshold key and the erasure code are counted by nodes, faultyNodes is the most nodes whose stake
+// is no more than f, and ElectPosProducers keeps faultyNodes * 3 < N
 type Config struct {
-	N         int      // participating nodes
-	f         int      // faulty nodes
-	Nodes     []string // pubkey list for all partticipating nodes
-	BatchSize int      // maximum number of trxs will be commited in one epoch
-	MyPubkey  string   // my pubkey
+	N           int              // participating nodes
+	f           int64            // faulty stake
+	faultyNodes int              // faulty nodes
+	totalStake  int64            // stake of all nodes
+	Nodes       []string         // pubkey list for all partticipating nodes
+	BatchSize   int              // maximum number of trxs will be commited in one epoch
+	MyPubkey    string           // my pubkey
+	Weights     map[string]int64 // stake of each node
+}
+
+// NewConfig creates the config of nodes, weights is nil for poa.
+// stake of a pos producer is capped by MAX_STAKE of chain storage, so stake sums never overflow
+func NewConfig(nodes []string, weights map[string]int64, batchSize int, myPubkey string) Config {
+	if weights == nil {
+		weights = make(map[string]int64)
+		for _, node := range nodes {
+			weights[node] = 1
+		}
+	}
+
+	cfg := Config{N: len(nodes), Nodes: nodes, BatchSize: batchSize, MyPubkey: myPubkey, Weights: weights}
+	var stakes []int64
+	for _, node := range nodes {
+		stakes = append(stakes, weights[node])
+		cfg.totalStake += weights[node]
+	}
+	cfg.f = (cfg.totalStake - 1) / 3
+
+	//the most nodes can fail are those with lowest stake
+	sort.Slice(stakes, func(i, j int) bool { return stakes[i] < stakes[j] })
+	var failed int64
+	for _, stake := range stakes {
+		failed += stake
+		if failed > cfg.f {
+			break
+		}
+		cfg.faultyNodes++
+	}
+	return cfg
+}
+
+// stake return the stake held by nodes
+func (cfg *Config) stake(nodes map[string]bool) int64 {
+	var stake int64
+	for node := range nodes {
+		stake += cfg.Weights[node]
+	}
+	return stake
+}
+
+// isQuorum return true if nodes hold more than 2/3 of total stake, that is at least total - f
+func (cfg *Config) isQuorum(nodes map[string]bool) bool {
+	return cfg.stake(nodes)*3 > cfg.totalStake*2
+}
+
+// exceeds return true if nodes hold more than stake, nodes hold more than f has an honest one in them
+func (cfg *Config) exceeds(nodes map[string]bool, stake int64) bool {
+	return cfg.stake(nodes) > stake
 }
@@ -0,0 +1,708 @@
+package consensus
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rumsystem/quorum/internal/pkg/logging"
+	"github.com/rumsystem/quorum/internal/pkg/nodectx"
+	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+var dkg_log = logging.Logger("dkg")
+
+var DKG_RESEND_INTERVAL = 10 * time.Second // resend dkg messages till the threshold key is ready
+var DKG_JOIN_TIMEOUT = 30 * time.Second    // producers not joined the dkg after it are not waited for
+
+// DKG generates the threshold key used to encrypt proposals and to make common coins of bba among producers
+// of a bft.
+// producers are counted by stake, a quorum holds more than 2/3 of total stake and f is the faulty stake,
+// shares are counted by producers, the key needs faultyNodes + 1 of them, see Config.
+//  1. each producer broadcasts a dh pubkey (DKG_KEY)
+//  2. after got dh pubkeys of all producers, or a quorum of them after DKG_JOIN_TIMEOUT, each producer deals
+//     a random polynomial of degree faultyNodes, broadcasts commitments of it and shares encrypted to each
+//     producer (DKG_DEAL), producers without a dh pubkey yet get no share of the deal
+//  3. each producer broadcasts the hash of the first deal it got from a dealer (DKG_ECHO), if its share
+//     in the deal is invalid, the echo is a complaint revealing the dh key shared with the dealer,
+//     so everyone can check the share
+//  4. a deal is agreed after it got matching echoes from a quorum without a valid complaint. after deals of
+//     all dealers are settled (agreed, excluded or never to be agreed), or agreed dealers are a quorum after
+//     DKG_JOIN_TIMEOUT, each producer takes the agreed dealers, adds up its shares of them and broadcasts
+//     the group pubkey with the dealers (DKG_ACK). the dealers hold more than f, one of them is honest, so
+//     the key is secret.
+//  5. the key is ready after a quorum acked the same dealers, at least faultyNodes + 1 honest producers
+//     hold a share of it, so every encrypted proposal can be decrypted. a producer which took other
+//     dealers switches to the acked key, without a share if one of the deals is invalid for it
+//
+// a producer acks only once, so two keys can never be ready. faulty producers which never deal or echo can not
+// stop the key from being ready, honest producers take the same dealers once the timeout is longer than
+// the network delay. bba gets its common coin from the key, so epochs wait for it, see
+// TrxBft.thresholdKeyReady
+type DKG struct {
+	Config
+	groupId  string
+	nodename string
+	session  string
+	nodes    []string //sorted producers, index of a producer is the index of its share
+	myIndex  int
+
+	ks          localcrypto.Keystore
+	broadcaster Broadcaster
+
+	dhPriv    []byte
+	myMsgs    []*quorumpb.DKGMsg //resent till the key is ready
+	dealSent  bool
+	acked     bool
+	dhPubkeys map[string][]byte
+	deals     map[string]map[string]*quorumpb.DKGMsg  //dealer -> hash of deal -> DKG_DEAL
+	echoes    map[string]map[string]*quorumpb.DKGEcho //dealer -> sender -> echo
+	excluded  map[string]bool                         //dealers with a valid complaint
+	acks      map[string]*quorumpb.DKGAck             //sender -> ack
+
+	key        *localcrypto.ThresholdKey
+	dealers    []string //dealers of the key
+	ready      bool
+	started    bool
+	startedAt  time.Time
+	lastResend time.Time
+	onReady    func() //called without the lock after the key is ready
+
+	mu   sync.Mutex
+	stop chan struct{}
+}
+
+// DkgSession return the id of the dkg among producers of the group
+func DkgSession(groupId string, nodes []string) string {
+	sorted := append([]string{}, nodes...)
+	sort.Strings(sorted)
+	h := sha256.Sum256([]byte(groupId + ":" + strings.Join(sorted, ",")))
+	return hex.EncodeToString(h[:])
+}
+
+func NewDKG(cfg Config, groupId, nodename string, ks localcrypto.Keystore, broadcaster Broadcaster) *DKG {
+	d := &DKG{
+		Config:      cfg,
+		groupId:     groupId,
+		nodename:    nodename,
+		session:     DkgSession(groupId, cfg.Nodes),
+		nodes:       append([]string{}, cfg.Nodes...),
+		myIndex:     -1,
+		ks:          ks,
+		broadcaster: broadcaster,
+		dhPubkeys:   make(map[string][]byte),
+		deals:       make(map[string]map[string]*quorumpb.DKGMsg),
+		echoes:      make(map[string]map[string]*quorumpb.DKGEcho),
+		excluded:    make(map[string]bool),
+		acks:        make(map[string]*quorumpb.DKGAck),
+	}
+	sort.Strings(d.nodes)
+	d.myIndex = d.indexOf(cfg.MyPubkey)
+
+	//load key generated before restart
+	tk, err := nodectx.GetNodeCtx().GetChainStorage().GetThresholdKey(groupId, nodename)
+	if err != nil {
+		dkg_log.Warningf("<%s> load threshold key failed: %s", groupId, err)
+	} else if tk != nil && tk.Session == d.session {
+		d.key = &localcrypto.ThresholdKey{
+			Index:       d.myIndex,
+			Threshold:   int(tk.Threshold),
+			GroupPubkey: tk.GroupPubkey,
+			VerifyKeys:  tk.VerifyKeys,
+			Share:       tk.Share,
+		}
+		d.dealers = tk.Dealers
+		d.ready = tk.Ready
+		dkg_log.Debugf("<%s> threshold key of session <%s> loaded, ready <%v>", groupId, d.session[:8], d.ready)
+	}
+	return d
+}
+
+func (d *DKG) indexOf(pubkey string) int {
+	for i, node := range d.nodes {
+		if node == pubkey {
+			return i
+		}
+	}
+	return -1
+}
+
+// Start joins the dkg, messages are resent till the key is ready
+func (d *DKG) Start() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.started || d.ready || d.myIndex < 0 {
+		return
+	}
+	dkg_log.Debugf("<%s> dkg session <%s> started, <%d> producers", d.groupId, d.session[:8], len(d.nodes))
+
+	priv, pub, err := localcrypto.GenerateDHKey()
+	if err != nil {
+		dkg_log.Errorf("<%s> generate dh key failed: %s", d.groupId, err)
+		return
+	}
+	d.dhPriv = priv
+	if err := d.send(quorumpb.DKGMsgType_DKG_KEY, pub); err != nil {
+		dkg_log.Warningf("<%s> send dkg key failed: %s", d.groupId, err)
+	}
+	d.started = true
+	d.startedAt = time.Now()
+	d.stop = make(chan struct{})
+	go d.resendLoop(d.stop)
+	d.progress()
+}
+
+func (d *DKG) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stop != nil {
+		close(d.stop)
+		d.stop = nil
+	}
+}
+
+// ReadyKey return the threshold key to encrypt proposals and make coins, nil if the key is not ready
+func (d *DKG) ReadyKey() *localcrypto.ThresholdKey {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.ready {
+		return nil
+	}
+	return d.key
+}
+
+func (d *DKG) resendLoop(stop chan struct{}) {
+	ticker := time.NewTicker(DKG_RESEND_INTERVAL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			d.mu.Lock()
+			if d.ready {
+				d.mu.Unlock()
+				return
+			}
+			d.resend()
+			//go on without producers not joined yet
+			d.progress()
+			ready := d.ready
+			d.mu.Unlock()
+			if ready && d.onReady != nil {
+				d.onReady()
+			}
+		}
+	}
+}
+
+func (d *DKG) resend() {
+	d.lastResend = time.Now()
+	for _, msg := range d.myMsgs {
+		if err := SendHBDkgMsg(d.broadcaster, d.groupId, msg); err != nil {
+			dkg_log.Warningf("<%s> resend dkg message failed: %s", d.groupId, err)
+		}
+	}
+	//deals echoed are relayed, so producers the dealer did not send to can still get them
+	for dealer, echoes := range d.echoes {
+		echo, ok := echoes[d.MyPubkey]
+		if !ok || dealer == d.MyPubkey {
+			continue
+		}
+		if msg, ok := d.deals[dealer][hex.EncodeToString(echo.DealHash)]; ok {
+			if err := SendHBDkgMsg(d.broadcaster, d.groupId, msg); err != nil {
+				dkg_log.Warningf("<%s> relay dkg deal failed: %s", d.groupId, err)
+			}
+		}
+	}
+}
+
+func (d *DKG) send(msgType quorumpb.DKGMsgType, payload []byte) error {
+	msg := &quorumpb.DKGMsg{
+		Type:         msgType,
+		Session:      d.session,
+		SenderPubkey: d.MyPubkey,
+		Payload:      payload,
+	}
+	hash, err := dkgMsgHash(msg)
+	if err != nil {
+		return err
+	}
+	msg.SenderSign, err = d.ks.EthSignByKeyName(d.groupId, hash, d.nodename)
+	if err != nil {
+		return err
+	}
+	d.myMsgs = append(d.myMsgs, msg)
+	return SendHBDkgMsg(d.broadcaster, d.groupId, msg)
+}
+
+func dkgMsgHash(msg *quorumpb.DKGMsg) ([]byte, error) {
+	unsigned := &quorumpb.DKGMsg{
+		Type:         msg.Type,
+		Session:      msg.Session,
+		SenderPubkey: msg.SenderPubkey,
+		Payload:      msg.Payload,
+	}
+	b, err := proto.Marshal(unsigned)
+	if err != nil {
+		return nil, err
+	}
+	return localcrypto.Hash(b), nil
+}
+
+func verifyDkgMsg(ks localcrypto.Keystore, msg *quorumpb.DKGMsg) error {
+	hash, err := dkgMsgHash(msg)
+	if err != nil {
+		return err
+	}
+	if err := verifyProducerSign(ks, msg.SenderPubkey, hash, msg.SenderSign); err != nil {
+		return fmt.Errorf("invalid sign of dkg message: %s", err)
+	}
+	return nil
+}
+
+func (d *DKG) HandleMessage(payload []byte) error {
+	msg := &quorumpb.DKGMsg{}
+	if err := proto.Unmarshal(payload, msg); err != nil {
+		return err
+	}
+	if msg.Session != d.session {
+		dkg_log.Debugf("<%s> dkg message of other session, ignore", d.groupId)
+		return nil
+	}
+	if d.indexOf(msg.SenderPubkey) < 0 {
+		return fmt.Errorf("dkg message from <%s> which is not a producer", msg.SenderPubkey)
+	}
+	if err := verifyDkgMsg(d.ks, msg); err != nil {
+		return err
+	}
+
+	ready, err := d.handleMessage(msg)
+	if ready && d.onReady != nil {
+		d.onReady()
+	}
+	return err
+}
+
+// handleMessage return true if the key got ready by the message
+func (d *DKG) handleMessage(msg *quorumpb.DKGMsg) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	dkg_log.Debugf("<%s> dkg message <%s> from <%s>", d.groupId, msg.Type, msg.SenderPubkey)
+
+	//only the first message of a sender counts, except deals, which are agreed by echoes
+	switch msg.Type {
+	case quorumpb.DKGMsgType_DKG_KEY:
+		if _, ok := d.dhPubkeys[msg.SenderPubkey]; !ok {
+			d.dhPubkeys[msg.SenderPubkey] = msg.Payload
+		}
+		//a producer restarted before the key is ready, help it to finish
+		if d.ready && msg.SenderPubkey != d.MyPubkey && time.Since(d.lastResend) > DKG_RESEND_INTERVAL {
+			d.resend()
+		}
+	case quorumpb.DKGMsgType_DKG_DEAL:
+		deal := &quorumpb.DKGDeal{}
+		if err := proto.Unmarshal(msg.Payload, deal); err != nil {
+			return false, err
+		}
+		if len(deal.Commitments) != d.faultyNodes+1 || len(deal.EncShares) != len(d.nodes) || missingShares(deal) > d.faultyNodes {
+			return false, fmt.Errorf("invalid dkg deal from <%s>", msg.SenderPubkey)
+		}
+		if _, ok := d.deals[msg.SenderPubkey]; !ok {
+			d.deals[msg.SenderPubkey] = make(map[string]*quorumpb.DKGMsg)
+		}
+		//a dealer sends different deals only to split producers, keep a few of them till echoes tell the agreed one
+		if len(d.deals[msg.SenderPubkey]) < d.faultyNodes+1 {
+			d.deals[msg.SenderPubkey][hex.EncodeToString(localcrypto.Hash(msg.Payload))] = msg
+		}
+	case quorumpb.DKGMsgType_DKG_ECHO:
+		echo := &quorumpb.DKGEcho{}
+		if err := proto.Unmarshal(msg.Payload, echo); err != nil {
+			return false, err
+		}
+		if d.indexOf(echo.Dealer) < 0 {
+			return false, fmt.Errorf("dkg echo from <%s> for <%s> which is not a producer", msg.SenderPubkey, echo.Dealer)
+		}
+		if _, ok := d.echoes[echo.Dealer]; !ok {
+			d.echoes[echo.Dealer] = make(map[string]*quorumpb.DKGEcho)
+		}
+		if _, ok := d.echoes[echo.Dealer][msg.SenderPubkey]; !ok {
+			d.echoes[echo.Dealer][msg.SenderPubkey] = echo
+		}
+	case quorumpb.DKGMsgType_DKG_ACK:
+		ack := &quorumpb.DKGAck{}
+		if err := proto.Unmarshal(msg.Payload, ack); err != nil {
+			return false, err
+		}
+		if _, ok := d.acks[msg.SenderPubkey]; !ok {
+			d.acks[msg.SenderPubkey] = ack
+		}
+	default:
+		return false, fmt.Errorf("received unknown dkg message, type (%s)", msg.Type)
+	}
+
+	wasReady := d.ready
+	d.progress()
+	return d.ready && !wasReady, nil
+}
+
+func (d *DKG) progress() {
+	if !d.started || d.ready {
+		return
+	}
+
+	joined := make(map[string]bool)
+	for pubkey := range d.dhPubkeys {
+		joined[pubkey] = true
+	}
+	if !d.dealSent && d.joined(len(d.dhPubkeys), joined) {
+		if err := d.sendDeal(); err != nil {
+			dkg_log.Warningf("<%s> send dkg deal failed: %s", d.groupId, err)
+			return
+		}
+		d.dealSent = true
+	}
+
+	for _, dealer := range d.nodes {
+		if err := d.sendEcho(dealer); err != nil {
+			dkg_log.Warningf("<%s> send dkg echo for <%s> failed: %s", d.groupId, dealer, err)
+		}
+	}
+
+	if d.key == nil {
+		dealers, ok := d.agreedDealers()
+		if !ok {
+			return
+		}
+		key, err := d.buildKey(dealers)
+		if err != nil {
+			dkg_log.Warningf("<%s> build threshold key failed: %s", d.groupId, err)
+			return
+		}
+		d.key = key
+		d.dealers = dealers
+		d.saveKey()
+	}
+
+	if !d.acked && d.key.Share != nil {
+		if err := d.sendAck(); err != nil {
+			dkg_log.Warningf("<%s> send dkg ack failed: %s", d.groupId, err)
+		} else {
+			d.acked = true
+		}
+	}
+
+	d.tryReady()
+}
+
+func (d *DKG) sendDeal() error {
+	commitments, shares, err := localcrypto.NewDkgDeal(d.faultyNodes+1, len(d.nodes))
+	if err != nil {
+		return err
+	}
+	deal := &quorumpb.DKGDeal{Commitments: commitments}
+	for i, node := range d.nodes {
+		if _, ok := d.dhPubkeys[node]; !ok {
+			deal.EncShares = append(deal.EncShares, nil)
+			continue
+		}
+		aesKey, err := localcrypto.DHSharedKey(d.dhPriv, d.dhPubkeys[node])
+		if err != nil {
+			return err
+		}
+		encShare, err := localcrypto.AesEncrypt(shares[i], aesKey)
+		if err != nil {
+			return err
+		}
+		deal.EncShares = append(deal.EncShares, encShare)
+	}
+	payload, err := proto.Marshal(deal)
+	if err != nil {
+		return err
+	}
+	return d.send(quorumpb.DKGMsgType_DKG_DEAL, payload)
+}
+
+// sendEcho echoes the first deal got from dealer, with a complaint if the share in it is invalid
+func (d *DKG) sendEcho(dealer string) error {
+	if _, ok := d.echoes[dealer][d.MyPubkey]; ok || len(d.deals[dealer]) == 0 {
+		return nil
+	}
+	if _, ok := d.dhPubkeys[dealer]; !ok {
+		return nil
+	}
+
+	var msg *quorumpb.DKGMsg
+	for _, m := range d.deals[dealer] {
+		msg = m
+		break
+	}
+	echo := &quorumpb.DKGEcho{Dealer: dealer, DealHash: localcrypto.Hash(msg.Payload)}
+	if _, err := d.myShare(dealer, msg.Payload); err != nil && err != errNoDkgShare {
+		dkg_log.Warningf("<%s> invalid share from <%s>: %s, complain", d.groupId, dealer, err)
+		echo.SharedKey, echo.Proof, err = localcrypto.DHRevealKey(d.dhPriv, d.dhPubkeys[dealer])
+		if err != nil {
+			return err
+		}
+	}
+	payload, err := proto.Marshal(echo)
+	if err != nil {
+		return err
+	}
+	if _, ok := d.echoes[dealer]; !ok {
+		d.echoes[dealer] = make(map[string]*quorumpb.DKGEcho)
+	}
+	//my own echo comes back later, take it now so the deal I got is known
+	d.echoes[dealer][d.MyPubkey] = echo
+	return d.send(quorumpb.DKGMsgType_DKG_ECHO, payload)
+}
+
+// joined return if the dkg goes on, all producers have done a step or nodes are a quorum after DKG_JOIN_TIMEOUT
+func (d *DKG) joined(done int, nodes map[string]bool) bool {
+	return done == len(d.nodes) || d.isQuorum(nodes) && time.Since(d.startedAt) >= DKG_JOIN_TIMEOUT
+}
+
+// agreedDealers return dealers of the key, ok is false if they are not settled yet.
+// a deal is agreed after a quorum echoed it, it is got here and no valid complaint is in echoes of it
+func (d *DKG) agreedDealers() (dealers []string, ok bool) {
+	settled := 0
+	agreed := make(map[string]bool)
+	for _, dealer := range d.nodes {
+		if d.excluded[dealer] {
+			settled++
+			continue
+		}
+		//not agreed yet, or relayed later by producers echoed it
+		payload, ok := d.agreedDeal(dealer)
+		if !ok {
+			if d.neverAgreed(dealer) {
+				settled++
+			}
+			continue
+		}
+		if d.hasValidComplaint(dealer, payload) {
+			dkg_log.Warningf("<%s> dealer <%s> sent invalid shares, skip it", d.groupId, dealer)
+			d.excluded[dealer] = true
+			settled++
+			continue
+		}
+		dealers = append(dealers, dealer)
+		agreed[dealer] = true
+		settled++
+	}
+
+	if !d.joined(settled, agreed) {
+		return nil, false
+	}
+	if !d.exceeds(agreed, d.f) {
+		dkg_log.Warningf("<%s> only <%d> dealers are agreed, no threshold key", d.groupId, len(dealers))
+		return nil, false
+	}
+	return dealers, true
+}
+
+// neverAgreed return true if no deal of dealer can get matching echoes from a quorum, even with echoes not got yet
+func (d *DKG) neverAgreed(dealer string) bool {
+	echoed := d.echoedBy(dealer)
+	echoed[""] = make(map[string]bool) //no echo got yet
+	for _, senders := range echoed {
+		for _, node := range d.nodes {
+			if _, ok := d.echoes[dealer][node]; !ok {
+				senders[node] = true
+			}
+		}
+		if d.isQuorum(senders) {
+			return false
+		}
+	}
+	return true
+}
+
+// echoedBy return producers echoed each deal hash of dealer
+func (d *DKG) echoedBy(dealer string) map[string]map[string]bool {
+	echoed := make(map[string]map[string]bool)
+	for sender, echo := range d.echoes[dealer] {
+		hash := hex.EncodeToString(echo.DealHash)
+		if _, ok := echoed[hash]; !ok {
+			echoed[hash] = make(map[string]bool)
+		}
+		echoed[hash][sender] = true
+	}
+	return echoed
+}
+
+// hasValidComplaint return true if a complaint in echoes for the agreed deal shows the share to the complainer is invalid
+func (d *DKG) hasValidComplaint(dealer string, payload []byte) bool {
+	deal := &quorumpb.DKGDeal{}
+	if err := proto.Unmarshal(payload, deal); err != nil {
+		return true
+	}
+	hash := localcrypto.Hash(payload)
+	for sender, echo := range d.echoes[dealer] {
+		if len(echo.SharedKey) == 0 || !bytes.Equal(echo.DealHash, hash) {
+			continue
+		}
+		index := d.indexOf(sender)
+		if len(deal.EncShares[index]) == 0 {
+			//a missing share is allowed, the dealer did not get dh pubkey of the sender
+			continue
+		}
+		aesKey, err := localcrypto.VerifyDHReveal(d.dhPubkeys[sender], d.dhPubkeys[dealer], echo.SharedKey, echo.Proof)
+		if err != nil {
+			dkg_log.Warningf("<%s> invalid complaint from <%s> against <%s>: %s", d.groupId, sender, dealer, err)
+			continue
+		}
+		share, err := localcrypto.AesDecode(deal.EncShares[index], aesKey)
+		if err != nil || localcrypto.VerifyDkgShare(deal.Commitments, index, share) != nil {
+			return true
+		}
+		dkg_log.Warningf("<%s> complaint from <%s> against <%s> is wrong, the share is valid", d.groupId, sender, dealer)
+	}
+	return false
+}
+
+// buildKey builds the threshold key from agreed deals of dealers, without share if one of my shares is invalid,
+// it can still verify and combine decryption shares of others
+func (d *DKG) buildKey(dealers []string) (*localcrypto.ThresholdKey, error) {
+	var commitments [][][]byte
+	var shares [][]byte
+	valid := true
+	for _, dealer := range dealers {
+		payload, ok := d.agreedDeal(dealer)
+		if !ok {
+			return nil, fmt.Errorf("agreed deal of <%s> is not got", dealer)
+		}
+		deal := &quorumpb.DKGDeal{}
+		if err := proto.Unmarshal(payload, deal); err != nil {
+			return nil, err
+		}
+		commitments = append(commitments, deal.Commitments)
+		if !valid {
+			continue
+		}
+		share, err := d.myShare(dealer, payload)
+		if err != nil {
+			dkg_log.Warningf("<%s> invalid share from <%s>: %s, key without share", d.groupId, dealer, err)
+			valid = false
+			continue
+		}
+		shares = append(shares, share)
+	}
+	if !valid {
+		shares = nil
+	}
+	return localcrypto.NewThresholdKey(d.myIndex, d.faultyNodes+1, len(d.nodes), commitments, shares)
+}
+
+// agreedDeal return the deal of dealer echoed by a quorum
+func (d *DKG) agreedDeal(dealer string) ([]byte, bool) {
+	for hash, senders := range d.echoedBy(dealer) {
+		if !d.isQuorum(senders) {
+			continue
+		}
+		if msg, ok := d.deals[dealer][hash]; ok {
+			return msg.Payload, true
+		}
+	}
+	return nil, false
+}
+
+func (d *DKG) sendAck() error {
+	ack := &quorumpb.DKGAck{GroupPubkey: d.key.GroupPubkey, Dealers: d.dealers}
+	payload, err := proto.Marshal(ack)
+	if err != nil {
+		return err
+	}
+	return d.send(quorumpb.DKGMsgType_DKG_ACK, payload)
+}
+
+// tryReady makes the key acked by a quorum ready, if it is not the key built here, switches to it
+func (d *DKG) tryReady() {
+	acked := make(map[string]map[string]bool)
+	for sender, ack := range d.acks {
+		id := hex.EncodeToString(ack.GroupPubkey) + ":" + strings.Join(ack.Dealers, ",")
+		if _, ok := acked[id]; !ok {
+			acked[id] = make(map[string]bool)
+		}
+		acked[id][sender] = true
+		if !d.isQuorum(acked[id]) {
+			continue
+		}
+
+		if !bytes.Equal(ack.GroupPubkey, d.key.GroupPubkey) || strings.Join(d.dealers, ",") != strings.Join(ack.Dealers, ",") {
+			key, err := d.buildKey(ack.Dealers)
+			if err != nil {
+				dkg_log.Warningf("<%s> build the acked threshold key failed: %s", d.groupId, err)
+				return
+			}
+			if !bytes.Equal(key.GroupPubkey, ack.GroupPubkey) {
+				dkg_log.Warningf("<%s> acked threshold key does not match its dealers", d.groupId)
+				return
+			}
+			dkg_log.Warningf("<%s> switch to the threshold key acked by others, has share <%v>", d.groupId, key.Share != nil)
+			d.key = key
+			d.dealers = ack.Dealers
+		}
+		dkg_log.Infof("<%s> threshold key of session <%s> is ready", d.groupId, d.session[:8])
+		d.ready = true
+		d.saveKey()
+		return
+	}
+}
+
+var errNoDkgShare = errors.New("no share in the deal, dh pubkey was not got by the dealer")
+
+// missingShares return the number of producers got no share in a deal
+func missingShares(deal *quorumpb.DKGDeal) int {
+	missing := 0
+	for _, encShare := range deal.EncShares {
+		if len(encShare) == 0 {
+			missing++
+		}
+	}
+	return missing
+}
+
+// myShare decrypts and verifies my share in a deal of dealer
+func (d *DKG) myShare(dealer string, payload []byte) ([]byte, error) {
+	deal := &quorumpb.DKGDeal{}
+	if err := proto.Unmarshal(payload, deal); err != nil {
+		return nil, err
+	}
+	if len(deal.EncShares[d.myIndex]) == 0 {
+		return nil, errNoDkgShare
+	}
+	aesKey, err := localcrypto.DHSharedKey(d.dhPriv, d.dhPubkeys[dealer])
+	if err != nil {
+		return nil, err
+	}
+	share, err := localcrypto.AesDecode(deal.EncShares[d.myIndex], aesKey)
+	if err != nil {
+		return nil, err
+	}
+	return share, localcrypto.VerifyDkgShare(deal.Commitments, d.myIndex, share)
+}
+
+func (d *DKG) saveKey() {
+	tk := &quorumpb.HBThresholdKey{
+		Session:     d.session,
+		Nodes:       d.nodes,
+		Threshold:   int32(d.key.Threshold),
+		GroupPubkey: d.key.GroupPubkey,
+		VerifyKeys:  d.key.VerifyKeys,
+		Share:       d.key.Share,
+		Ready:       d.ready,
+		Dealers:     d.dealers,
+	}
+	if err := nodectx.GetNodeCtx().GetChainStorage().SaveThresholdKey(d.groupId, tk, d.nodename); err != nil {
+		dkg_log.Warningf("<%s> save threshold key failed: %s", d.groupId, err)
+	}
+}
@@ -0,0 +1,392 @@
+package consensus
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// dkgNet runs a dkg among producers, messages are delivered in order, tamper changes a message
+// from producer from to producer to, nil drops it. crashed producers never start
+type dkgNet struct {
+	t       *testing.T
+	keys    []*simKeystore
+	dkgs    []*DKG
+	queue   []dkgMsg
+	tamper  func(from, to int, msg *quorumpb.DKGMsg) *quorumpb.DKGMsg
+	crashed map[int]bool
+}
+
+type dkgMsg struct {
+	from int
+	msg  *quorumpb.DKGMsg
+}
+
+type dkgLink struct {
+	net  *dkgNet
+	from int
+}
+
+func (l dkgLink) BroadcastHBMsg(groupId string, hbmsg *quorumpb.HBMsgv1) error {
+	msg := &quorumpb.DKGMsg{}
+	if err := proto.Unmarshal(hbmsg.Payload, msg); err != nil {
+		return err
+	}
+	l.net.queue = append(l.net.queue, dkgMsg{from: l.from, msg: msg})
+	return nil
+}
+
+func (l dkgLink) BroadcastBlock(groupId string, block *quorumpb.Block) error { return nil }
+
+var dkgTestRuns int
+
+func newDkgNet(t *testing.T, n int) *dkgNet {
+	initSimCtx()
+	dkgTestRuns++
+	rng := rand.New(rand.NewSource(int64(dkgTestRuns)))
+	net := &dkgNet{t: t}
+	var pubkeys []string
+	for i := 0; i < n; i++ {
+		ks := newSimKeystore(rng)
+		net.keys = append(net.keys, ks)
+		pubkeys = append(pubkeys, base64.RawURLEncoding.EncodeToString(ethcrypto.CompressPubkey(&ks.priv.PublicKey)))
+	}
+	for i := 0; i < n; i++ {
+		cfg := NewConfig(pubkeys, nil, 0, pubkeys[i])
+		nodename := fmt.Sprintf("dkg-%d-producer-%d", dkgTestRuns, i)
+		net.dkgs = append(net.dkgs, NewDKG(cfg, "dkggroup", nodename, net.keys[i], dkgLink{net: net, from: i}))
+	}
+	t.Cleanup(func() {
+		for _, d := range net.dkgs {
+			d.Stop()
+		}
+	})
+	return net
+}
+
+func (net *dkgNet) run() {
+	for i, d := range net.dkgs {
+		if !net.crashed[i] {
+			d.Start()
+		}
+	}
+	net.deliver()
+}
+
+func (net *dkgNet) deliver() {
+	for len(net.queue) > 0 {
+		m := net.queue[0]
+		net.queue = net.queue[1:]
+		for to, d := range net.dkgs {
+			if net.crashed[to] {
+				continue
+			}
+			msg := m.msg
+			if net.tamper != nil && to != m.from {
+				if msg = net.tamper(m.from, to, msg); msg == nil {
+					continue
+				}
+			}
+			payload, _ := proto.Marshal(msg)
+			d.HandleMessage(payload)
+		}
+	}
+}
+
+// resign signs a tampered dkg message by the key of producer from
+func (net *dkgNet) resign(from int, msg *quorumpb.DKGMsg) *quorumpb.DKGMsg {
+	hash, err := dkgMsgHash(msg)
+	if err != nil {
+		net.t.Fatalf("hash dkg message failed: %s", err)
+	}
+	msg.SenderSign, err = net.keys[from].EthSignByKeyName("dkggroup", hash)
+	if err != nil {
+		net.t.Fatalf("sign dkg message failed: %s", err)
+	}
+	return msg
+}
+
+// checkReady checks all producers got the same ready key from at least minDealers dealers,
+// and it decrypts by shares of any f + 1 of them
+func (net *dkgNet) checkReady(minDealers int) *localcrypto.ThresholdKey {
+	var key *localcrypto.ThresholdKey
+	var dealers string
+	for i, d := range net.dkgs {
+		if net.crashed[i] {
+			continue
+		}
+		k := d.ReadyKey()
+		if k == nil {
+			net.t.Fatalf("threshold key of producer <%d> is not ready", i)
+		}
+		if len(d.dealers) < minDealers {
+			net.t.Fatalf("key of producer <%d> has <%d> dealers, expect at least <%d>", i, len(d.dealers), minDealers)
+		}
+		if key == nil {
+			key, dealers = k, strings.Join(d.dealers, ",")
+		} else if !bytes.Equal(k.GroupPubkey, key.GroupPubkey) || strings.Join(d.dealers, ",") != dealers {
+			net.t.Fatalf("producer <%d> got another group pubkey", i)
+		}
+	}
+
+	label := []byte("group:1:proposer")
+	ciphertext, err := localcrypto.ThresholdEncrypt(key.GroupPubkey, []byte("proposal"), label)
+	if err != nil {
+		net.t.Fatalf("ThresholdEncrypt failed: %s", err)
+	}
+	shares := make(map[int][]byte)
+	for i, d := range net.dkgs {
+		if net.crashed[i] {
+			continue
+		}
+		k := d.ReadyKey()
+		if k.Share == nil {
+			continue
+		}
+		share, proof, err := k.DecryptShare(ciphertext, label)
+		if err != nil {
+			net.t.Fatalf("DecryptShare failed: %s", err)
+		}
+		if err := key.VerifyDecryptShare(k.Index, ciphertext, label, share, proof); err != nil {
+			net.t.Fatalf("VerifyDecryptShare failed: %s", err)
+		}
+		shares[k.Index] = share
+		if len(shares) == key.Threshold {
+			break
+		}
+	}
+	plain, err := key.Combine(ciphertext, label, shares)
+	if err != nil || string(plain) != "proposal" {
+		net.t.Fatalf("decrypt by shares failed: %v", err)
+	}
+	return key
+}
+
+func decodeDeal(t *testing.T, msg *quorumpb.DKGMsg) *quorumpb.DKGDeal {
+	deal := &quorumpb.DKGDeal{}
+	if err := proto.Unmarshal(msg.Payload, deal); err != nil {
+		t.Fatalf("decode deal failed: %s", err)
+	}
+	return deal
+}
+
+func TestDKGHonest(t *testing.T) {
+	for _, n := range []int{1, 4, 7} {
+		net := newDkgNet(t, n)
+		net.run()
+		net.checkReady(n)
+		for i, d := range net.dkgs {
+			if d.ReadyKey().Share == nil {
+				t.Fatalf("producer <%d> got no share", i)
+			}
+		}
+	}
+}
+
+func TestDKGExcludeInvalidDealer(t *testing.T) {
+	net := newDkgNet(t, 4)
+	dealer, victim := 1, 2
+	var bad *quorumpb.DKGMsg
+	net.tamper = func(from, to int, msg *quorumpb.DKGMsg) *quorumpb.DKGMsg {
+		if from != dealer || msg.Type != quorumpb.DKGMsgType_DKG_DEAL {
+			return msg
+		}
+		//the same invalid deal to everyone, so it is agreed
+		if bad == nil {
+			deal := decodeDeal(t, msg)
+			deal.EncShares[net.dkgs[victim].myIndex] = []byte("not a share")
+			bad = proto.Clone(msg).(*quorumpb.DKGMsg)
+			bad.Payload, _ = proto.Marshal(deal)
+			net.resign(dealer, bad)
+		}
+		return bad
+	}
+	net.run()
+
+	//the dealer echoed the deal it kept, others echoed the invalid one, it gets the agreed one by relay
+	for _, d := range net.dkgs {
+		d.mu.Lock()
+		d.resend()
+		d.mu.Unlock()
+	}
+	net.deliver()
+	net.checkReady(3)
+	for i, d := range net.dkgs {
+		for _, pubkey := range d.dealers {
+			if pubkey == net.dkgs[dealer].MyPubkey {
+				t.Fatalf("producer <%d> took the dealer sent an invalid share", i)
+			}
+		}
+	}
+	if net.dkgs[victim].ReadyKey().Share == nil {
+		t.Fatal("producer complained got no share")
+	}
+}
+
+func TestDKGEquivocatingDealer(t *testing.T) {
+	net := newDkgNet(t, 4)
+	dealer := 3
+	alt := make(map[int]*quorumpb.DKGMsg)
+	net.tamper = func(from, to int, msg *quorumpb.DKGMsg) *quorumpb.DKGMsg {
+		//2 producers got the deal, 2 others got their own ones, no deal gets N - f echoes
+		if from != dealer || msg.Type != quorumpb.DKGMsgType_DKG_DEAL || to == 0 {
+			return msg
+		}
+		if _, ok := alt[to]; !ok {
+			deal := decodeDeal(t, msg)
+			commitments, _, err := localcrypto.NewDkgDeal(len(deal.Commitments), len(deal.EncShares))
+			if err != nil {
+				t.Fatalf("NewDkgDeal failed: %s", err)
+			}
+			deal.Commitments = commitments
+			m := proto.Clone(msg).(*quorumpb.DKGMsg)
+			m.Payload, _ = proto.Marshal(deal)
+			alt[to] = net.resign(dealer, m)
+		}
+		return alt[to]
+	}
+	net.run()
+
+	key := net.checkReady(3)
+	for _, d := range net.dkgs {
+		for _, pubkey := range d.dealers {
+			if pubkey == net.dkgs[dealer].MyPubkey {
+				t.Fatal("deal of the equivocating dealer is taken")
+			}
+		}
+	}
+	if key.Threshold != 2 {
+		t.Fatalf("threshold <%d>, expect 2", key.Threshold)
+	}
+}
+
+func TestDKGFalseComplaint(t *testing.T) {
+	net := newDkgNet(t, 4)
+	liar := 0
+	net.tamper = func(from, to int, msg *quorumpb.DKGMsg) *quorumpb.DKGMsg {
+		if from != liar || msg.Type != quorumpb.DKGMsgType_DKG_ECHO {
+			return msg
+		}
+		echo := &quorumpb.DKGEcho{}
+		proto.Unmarshal(msg.Payload, echo)
+		//a reveal by another dh key
+		priv, _, _ := localcrypto.GenerateDHKey()
+		echo.SharedKey, echo.Proof, _ = localcrypto.DHRevealKey(priv, net.dkgs[to].dhPubkeys[echo.Dealer])
+		m := proto.Clone(msg).(*quorumpb.DKGMsg)
+		m.Payload, _ = proto.Marshal(echo)
+		return net.resign(liar, m)
+	}
+	net.run()
+	net.checkReady(4)
+	for i, d := range net.dkgs {
+		if len(d.excluded) != 0 {
+			t.Fatalf("producer <%d> excluded an honest dealer by a false complaint", i)
+		}
+	}
+}
+
+func TestDKGRelayDeal(t *testing.T) {
+	net := newDkgNet(t, 4)
+	dealer, missed := 2, 3
+	net.tamper = func(from, to int, msg *quorumpb.DKGMsg) *quorumpb.DKGMsg {
+		if from == dealer && to == missed && msg.Type == quorumpb.DKGMsgType_DKG_DEAL {
+			return nil
+		}
+		return msg
+	}
+	net.run()
+	//others took the dealer, the key acked by them can not be built without its deal
+	for i, d := range net.dkgs {
+		if ready := d.ReadyKey() != nil; ready == (i == missed) {
+			t.Fatalf("producer <%d> ready <%v>", i, ready)
+		}
+	}
+
+	//producers echoed the deal relay it
+	for i, d := range net.dkgs {
+		if i == missed {
+			continue
+		}
+		d.mu.Lock()
+		d.resend()
+		d.mu.Unlock()
+	}
+	net.deliver()
+	net.checkReady(4)
+}
+
+func TestDKGAckOnce(t *testing.T) {
+	net := newDkgNet(t, 4)
+	net.run()
+	for i, d := range net.dkgs {
+		acks := 0
+		for _, msg := range d.myMsgs {
+			if msg.Type == quorumpb.DKGMsgType_DKG_ACK {
+				acks++
+				ack := &quorumpb.DKGAck{}
+				proto.Unmarshal(msg.Payload, ack)
+				if strings.Join(ack.Dealers, ",") != strings.Join(d.dealers, ",") {
+					t.Fatalf("producer <%d> acked other dealers", i)
+				}
+			}
+		}
+		if acks != 1 {
+			t.Fatalf("producer <%d> sent <%d> acks", i, acks)
+		}
+	}
+}
+
+func TestDKGCrashedProducer(t *testing.T) {
+	timeout := DKG_JOIN_TIMEOUT
+	DKG_JOIN_TIMEOUT = 0
+	defer func() { DKG_JOIN_TIMEOUT = timeout }()
+
+	for _, n := range []int{4, 7} {
+		net := newDkgNet(t, n)
+		f := (n - 1) / 3
+		net.crashed = make(map[int]bool)
+		for i := 0; i < f; i++ {
+			net.crashed[n-1-i] = true
+		}
+		net.run()
+		net.checkReady(n - f)
+		for i, d := range net.dkgs {
+			if !net.crashed[i] && d.ReadyKey().Share == nil {
+				t.Fatalf("producer <%d> got no share", i)
+			}
+		}
+	}
+}
+
+func TestDKGWaitForJoinTimeout(t *testing.T) {
+	net := newDkgNet(t, 4)
+	net.crashed = map[int]bool{3: true}
+	net.run()
+	for i, d := range net.dkgs {
+		if d.dealSent {
+			t.Fatalf("producer <%d> dealt before DKG_JOIN_TIMEOUT without dh pubkeys of all producers", i)
+		}
+	}
+
+	//deal after the timeout, dealers are taken after it too
+	timeout := DKG_JOIN_TIMEOUT
+	DKG_JOIN_TIMEOUT = 0
+	defer func() { DKG_JOIN_TIMEOUT = timeout }()
+	for i, d := range net.dkgs {
+		if !net.crashed[i] {
+			d.mu.Lock()
+			d.progress()
+			d.mu.Unlock()
+		}
+	}
+	net.deliver()
+	net.checkReady(3)
+}
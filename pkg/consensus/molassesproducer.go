@@ -1,6 +1,8 @@
 package consensus
 
 import (
+	"time"
+
 	"github.com/rumsystem/quorum/internal/pkg/logging"
 	"github.com/rumsystem/quorum/internal/pkg/nodectx"
 	"github.com/rumsystem/quorum/pkg/consensus/def"
@@ -16,6 +18,7 @@ type MolassesProducer struct {
 	cIface   def.ChainMolassesIface
 	groupId  string
 	bft      *TrxBft
+	seenTrxs *rumchaindata.SeenTrxIndex
 }
 
 func (producer *MolassesProducer) NewProducer(item *quorumpb.GroupItem, nodename string, iface def.ChainMolassesIface) {
@@ -24,6 +27,7 @@ func (producer *MolassesProducer) NewProducer(item *quorumpb.GroupItem, nodename
 	producer.cIface = iface
 	producer.nodename = nodename
 	producer.groupId = item.GroupId
+	producer.seenTrxs = rumchaindata.NewSeenTrxIndex(rumchaindata.MAX_SEEN_TRX_COUNT)
 
 	config, err := producer.createBftConfig()
 	if err != nil {
@@ -50,6 +54,17 @@ func (producer *MolassesProducer) StartPropose() {
 		}
 	}
 
+	//pos group, only elected producers propose
+	if isProducer && producer.grpItem.ConsenseType == quorumpb.GroupConsenseType_POS {
+		isProducer = false
+		for _, pubkey := range producer.bft.Nodes {
+			if producer.grpItem.UserSignPubkey == pubkey {
+				isProducer = true
+				break
+			}
+		}
+	}
+
 	if isProducer {
 		producer.bft.StartPropose()
 	}
@@ -64,6 +79,10 @@ func (producer *MolassesProducer) RecreateBft() {
 		return
 	}
 
+	//producers changed, threshold key of old producers is not used anymore
+	if producer.bft != nil {
+		producer.bft.dkg.Stop()
+	}
 	producer.bft = NewTrxBft(*config, producer)
 }
 
@@ -75,36 +94,41 @@ func (producer *MolassesProducer) createBftConfig() (*Config, error) {
 	}
 
 	var nodes []string
-	for _, producer := range producer_nodes {
-		nodes = append(nodes, producer.ProducerPubkey)
+	var weights map[string]int64
+	if producer.grpItem.ConsenseType == quorumpb.GroupConsenseType_POS {
+		//pos, elect producers by stake
+		candidates := make(map[string]int64)
+		for _, p := range producer_nodes {
+			candidates[p.ProducerPubkey] = 0
+			stake, err := nodectx.GetNodeCtx().GetChainStorage().GetStake(producer.groupId, p.ProducerPubkey, producer.nodename)
+			if err != nil {
+				return nil, err
+			}
+			if stake != nil {
+				candidates[p.ProducerPubkey] = stake.Amount
+			}
+		}
+		nodes, weights = ElectPosProducers(candidates, producer.grpItem.OwnerPubKey)
+	} else {
+		for _, producer := range producer_nodes {
+			nodes = append(nodes, producer.ProducerPubkey)
+		}
 	}
 
 	molaproducer_log.Debugf("Get <%d> producers", len(nodes))
 	for _, producerId := range nodes {
-		molaproducer_log.Debugf(">>> producer_id <%s>", producerId)
+		molaproducer_log.Debugf(">>> producer_id <%s>, stake <%d>", producerId, weights[producerId])
 	}
 
-	N := len(nodes)
-	f := (N - 1) / 3 //f * 3 < N
-
-	molaproducer_log.Debugf("Failable node <%d>", f)
-
-	//use fixed scalar size
-	scalar := 20
-	//batchSize := (len(nodes) * 2) * scalar
-	batchSize := scalar
+	//batch size can be changed by group owner with mempool policy
+	batchSize := GetMempoolPolicy(producer.groupId, producer.nodename).BatchSize
 
 	molaproducer_log.Debugf("batchSize <%d>", batchSize)
 
-	config := &Config{
-		N:         N,
-		f:         f,
-		Nodes:     nodes,
-		BatchSize: batchSize,
-		MyPubkey:  producer.grpItem.UserSignPubkey,
-	}
+	config := NewConfig(nodes, weights, batchSize, producer.grpItem.UserSignPubkey)
+	molaproducer_log.Debugf("Failable stake <%d>, nodes <%d>", config.f, config.faultyNodes)
 
-	return config, nil
+	return &config, nil
 }
 
 func (producer *MolassesProducer) AddBlock(block *quorumpb.Block) error {
@@ -144,7 +168,7 @@ func (producer *MolassesProducer) AddBlock(block *quorumpb.Block) error {
 			//valid block with parent block
 			valid, err := rumchaindata.ValidBlockWithParent(block, parentBlock)
 			if !valid {
-				molaproducer_log.Warningf("<%s> invalid block <%s>", producer.groupId, err.Error())
+				molaproducer_log.Warningf("<%s> invalid block <%d>: %v", producer.groupId, block.BlockId, err)
 				molaproducer_log.Debugf("<%s> remove invalid block <%d> from cache", producer.groupId, block.Epoch)
 				return nodectx.GetNodeCtx().GetChainStorage().RmBlock(block.GroupId, block.BlockId, true, producer.nodename)
 			} else {
@@ -204,8 +228,16 @@ func (producer *MolassesProducer) AddBlock(block *quorumpb.Block) error {
 				return err
 			}
 
+			//trxs packaged by other producers should not be packaged again
+			producer.markTrxsPackaged(trxs)
+
 			//apply trxs
-			return producer.cIface.ApplyTrxsProducerNode(trxs, producer.nodename)
+			if err := producer.cIface.ApplyTrxsProducerNode(trxs, producer.nodename); err != nil {
+				return err
+			}
+
+			producer.cIface.TryCreateSnapshot()
+			return nil
 		}
 	}
 	return nil
@@ -214,6 +246,18 @@ func (producer *MolassesProducer) AddBlock(block *quorumpb.Block) error {
 func (producer *MolassesProducer) AddTrx(trx *quorumpb.Trx) {
 	molaproducer_log.Debugf("<%s> AddTrx called", producer.groupId)
 
+	//check if trx expired or from future
+	if err := rumchaindata.CheckTrxTime(trx, time.Now()); err != nil {
+		molaproducer_log.Debugf("<%s> trx <%s> rejected, <%s>", producer.groupId, trx.TrxId, err.Error())
+		return
+	}
+
+	//check if trx packaged recently
+	if producer.seenTrxs.IsSeen(trx.TrxId) {
+		molaproducer_log.Debugf("<%s> trx <%s> already packaged, ignore", producer.groupId, trx.TrxId)
+		return
+	}
+
 	//check if trx sender is in group block list
 	isAllow, err := nodectx.GetNodeCtx().GetChainStorage().CheckTrxTypeAuth(trx.GroupId, trx.SenderPubkey, trx.Type, producer.nodename)
 	if err != nil {
@@ -239,6 +283,15 @@ func (producer *MolassesProducer) AddTrx(trx *quorumpb.Trx) {
 	}
 }
 
+func (producer *MolassesProducer) markTrxsPackaged(trxs []*quorumpb.Trx) {
+	for _, trx := range trxs {
+		producer.seenTrxs.Add(trx.TrxId, rumchaindata.GetTrxExpiredAt(trx))
+		if producer.bft != nil {
+			producer.bft.txBuffer.Delete(trx.TrxId)
+		}
+	}
+}
+
 func (producer *MolassesProducer) HandleHBMsg(hbmsg *quorumpb.HBMsgv1) error {
 	return producer.bft.HandleMessage(hbmsg)
 }
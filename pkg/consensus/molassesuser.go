@@ -63,7 +63,7 @@ func (user *MolassesUser) AddBlock(block *quorumpb.Block) error {
 			//valid block with parent block
 			valid, err := rumchaindata.ValidBlockWithParent(block, parentBlock)
 			if !valid {
-				molauser_log.Warningf("<%s> invalid block <%s>", user.groupId, err.Error())
+				molauser_log.Warningf("<%s> invalid block <%d>: %v", user.groupId, block.BlockId, err)
 				molauser_log.Debugf("<%s> remove invalid block <%d> from cache", user.groupId, block.BlockId)
 				return nodectx.GetNodeCtx().GetChainStorage().RmBlock(block.GroupId, block.BlockId, true, user.nodename)
 			} else {
@@ -7,13 +7,50 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-func SendHBRBCMsg(groupId string, msg *quorumpb.RBCMsg, epoch uint64) error {
+// Broadcaster sends consensus messages and new blocks of a group to other nodes
+type Broadcaster interface {
+	BroadcastHBMsg(groupId string, hbmsg *quorumpb.HBMsgv1) error
+	BroadcastBlock(groupId string, block *quorumpb.Block) error
+}
+
+// connBroadcaster sends by the conn manager of the group
+type connBroadcaster struct{}
+
+func (connBroadcaster) BroadcastHBMsg(groupId string, hbmsg *quorumpb.HBMsgv1) error {
 	connMgr, err := conn.GetConn().GetConnMgr(groupId)
 	if err != nil {
 		return err
 	}
+	return connMgr.BroadcastHBMsg(hbmsg)
+}
+
+func (connBroadcaster) BroadcastBlock(groupId string, block *quorumpb.Block) error {
+	connMgr, err := conn.GetConn().GetConnMgr(groupId)
+	if err != nil {
+		return err
+	}
+	return connMgr.BroadcastBlock(block)
+}
 
-	rbcb, err := proto.Marshal(msg)
+func SendHBRBCMsg(b Broadcaster, groupId string, msg *quorumpb.RBCMsg, epoch uint64) error {
+	return sendHBMsg(b, groupId, quorumpb.HBMsgPayloadType_RBC, msg, epoch)
+}
+
+func SendHBAABMsg(b Broadcaster, groupId string, msg *quorumpb.BBAMsg, epoch uint64) error {
+	return sendHBMsg(b, groupId, quorumpb.HBMsgPayloadType_BBA, msg, epoch)
+}
+
+func SendHBDecMsg(b Broadcaster, groupId string, msg *quorumpb.DecShare, epoch uint64) error {
+	return sendHBMsg(b, groupId, quorumpb.HBMsgPayloadType_DEC, msg, epoch)
+}
+
+// dkg messages are not bound to an epoch
+func SendHBDkgMsg(b Broadcaster, groupId string, msg *quorumpb.DKGMsg) error {
+	return sendHBMsg(b, groupId, quorumpb.HBMsgPayloadType_DKG, msg, 0)
+}
+
+func sendHBMsg(b Broadcaster, groupId string, payloadType quorumpb.HBMsgPayloadType, msg proto.Message, epoch uint64) error {
+	payload, err := proto.Marshal(msg)
 	if err != nil {
 		return err
 	}
@@ -21,14 +58,9 @@ func SendHBRBCMsg(groupId string, msg *quorumpb.RBCMsg, epoch uint64) error {
 	hbmsg := &quorumpb.HBMsgv1{
 		MsgId:       guuid.New().String(),
 		Epoch:       epoch,
-		PayloadType: quorumpb.HBMsgPayloadType_RBC,
-		Payload:     rbcb,
+		PayloadType: payloadType,
+		Payload:     payload,
 	}
 
-	return connMgr.BroadcastHBMsg(hbmsg)
-}
-
-func SendHBAABMsg(groupId string, msg *quorumpb.BBAMsg, epoch int64) error {
-	//TBD
-	return nil
+	return b.BroadcastHBMsg(groupId, hbmsg)
 }
@@ -0,0 +1,226 @@
+package consensus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rumsystem/quorum/internal/pkg/nodectx"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+var DEFAULT_MIN_PROPOSE_INTERVAL = 0 * time.Millisecond //busy groups propose back-to-back
+var DEFAULT_MAX_IDLE_INTERVAL = 60 * time.Second        //idle groups check trx buffer at least once a minute
+
+// EpochPacing controls how long a producer waits before starting the next epoch,
+// group owner can change it by SET_EPOCH_PACING chain config, so all producers of a group follow the same pacing
+type EpochPacing struct {
+	MinInterval     time.Duration // wait before next epoch when trx buffer holds a full batch
+	BaseInterval    time.Duration // wait before next epoch when trx buffer holds some trxs, gives trxs a chance to batch
+	MaxIdleInterval time.Duration // maximum wait between two checks of an empty trx buffer
+}
+
+// GetEpochPacing return epoch pacing of the group, default values are used for fields not set
+func GetEpochPacing(groupId string, prefix ...string) *EpochPacing {
+	pacing := &EpochPacing{
+		MinInterval:     DEFAULT_MIN_PROPOSE_INTERVAL,
+		BaseInterval:    time.Duration(DEFAULT_PROPOSE_PULSE) * time.Millisecond,
+		MaxIdleInterval: DEFAULT_MAX_IDLE_INTERVAL,
+	}
+
+	item, err := nodectx.GetNodeCtx().GetChainStorage().GetEpochPacing(groupId, prefix...)
+	if err != nil {
+		trx_bft_log.Warnf("<%s> get epoch pacing failed, use default, err <%s>", groupId, err.Error())
+		return pacing
+	}
+	if item == nil {
+		return pacing
+	}
+
+	if item.MinInterval > 0 {
+		pacing.MinInterval = time.Duration(item.MinInterval) * time.Millisecond
+	}
+	if item.BaseInterval > 0 {
+		pacing.BaseInterval = time.Duration(item.BaseInterval) * time.Millisecond
+	}
+	if item.MaxIdleInterval > 0 {
+		pacing.MaxIdleInterval = time.Duration(item.MaxIdleInterval) * time.Millisecond
+	}
+	if pacing.MinInterval > pacing.BaseInterval {
+		pacing.MinInterval = pacing.BaseInterval
+	}
+	if pacing.MaxIdleInterval < pacing.BaseInterval {
+		pacing.MaxIdleInterval = pacing.BaseInterval
+	}
+	return pacing
+}
+
+// waitEpoch blocks till the epoch of task should start, return false if the task is cancelled.
+//   - trx buffer holds a full batch, start after MinInterval
+//   - trx buffer holds some trxs, start after BaseInterval
+//   - trx buffer is empty, check it again later with the interval doubled each time up to MaxIdleInterval,
+//     so an idle group runs no consensus round at all
+//
+// a new trx or a signed message of the epoch from another producer wakes it up to check again,
+// once producers hold more than f stake start the epoch, others join it immediately (with an empty proposal if nothing to propose),
+// fewer of them count as a pending trx, so a single faulty producer can not drive epochs faster than BaseInterval
+func (bft *TrxBft) waitEpoch(task *ProposeTask, cancel chan struct{}) bool {
+	pacing := GetEpochPacing(bft.groupId, bft.producer.nodename)
+	batchSize := GetMempoolPolicy(bft.groupId, bft.producer.nodename).BatchSize
+
+	idleInterval := pacing.BaseInterval
+	var pendingSince time.Time
+	for {
+		starters := bft.epochStarters(task.Epoch)
+		if bft.exceeds(starters, bft.f) {
+			trx_bft_log.Debugf("<%s> epoch <%d> started by other producers, join it", bft.groupId, task.Epoch)
+			return true
+		}
+
+		depth, err := bft.txBuffer.GetBufferLen()
+		if err != nil {
+			trx_bft_log.Warnf("<%s> get buffer len failed <%s>", bft.groupId, err.Error())
+		}
+		if depth == 0 && len(starters) > 0 {
+			depth = 1
+		}
+
+		var wait time.Duration
+		if depth == 0 {
+			pendingSince = time.Time{}
+			wait = idleInterval
+			idleInterval *= 2
+			if idleInterval > pacing.MaxIdleInterval {
+				idleInterval = pacing.MaxIdleInterval
+			}
+		} else {
+			if pendingSince.IsZero() {
+				pendingSince = time.Now()
+			}
+			interval := pacing.BaseInterval
+			if depth >= batchSize {
+				interval = pacing.MinInterval
+			}
+			wait = time.Until(pendingSince.Add(interval))
+			if wait <= 0 {
+				return true
+			}
+		}
+
+		trx_bft_log.Debugf("<%s> epoch <%d>, <%d> trxs in buffer, wait <%s>", bft.groupId, task.Epoch, depth, wait)
+		timer := time.NewTimer(wait)
+		select {
+		case <-cancel:
+			timer.Stop()
+			return false
+		case <-bft.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// epochStarters return other producers signed messages of the epoch or a later one,
+// they hold more than f stake means at least one honest producer started it
+func (bft *TrxBft) epochStarters(epoch uint64) map[string]bool {
+	bft.acsMu.Lock()
+	defer bft.acsMu.Unlock()
+	senders := make(map[string]bool)
+	for e, producers := range bft.futureSenders {
+		if e < epoch {
+			continue
+		}
+		for pubkey := range producers {
+			senders[pubkey] = true
+		}
+	}
+	return senders
+}
+
+// hbMsgSender return the producer signed a rbc or bba message, empty for decryption shares,
+// they are not signed and verified by their proofs when handled
+func (bft *TrxBft) hbMsgSender(hbmsg *quorumpb.HBMsgv1) (string, error) {
+	var msg proto.Message
+	switch hbmsg.PayloadType {
+	case quorumpb.HBMsgPayloadType_RBC:
+		rbcMsg := &quorumpb.RBCMsg{}
+		if err := proto.Unmarshal(hbmsg.Payload, rbcMsg); err != nil {
+			return "", err
+		}
+		switch rbcMsg.Type {
+		case quorumpb.RBCMsgType_INIT_PROPOSE:
+			msg = &quorumpb.InitPropose{}
+		case quorumpb.RBCMsgType_ECHO:
+			msg = &quorumpb.Echo{}
+		case quorumpb.RBCMsgType_READY:
+			msg = &quorumpb.Ready{}
+		default:
+			return "", fmt.Errorf("unknown rbc message type <%s>", rbcMsg.Type)
+		}
+		if err := proto.Unmarshal(rbcMsg.Payload, msg); err != nil {
+			return "", err
+		}
+		if err := VerifyRBCSign(bft.ks, msg); err != nil {
+			return "", err
+		}
+	case quorumpb.HBMsgPayloadType_BBA:
+		bbaMsg := &quorumpb.BBAMsg{}
+		if err := proto.Unmarshal(hbmsg.Payload, bbaMsg); err != nil {
+			return "", err
+		}
+		switch bbaMsg.Type {
+		case quorumpb.BBAMsgType_BVAL:
+			msg = &quorumpb.Bval{}
+		case quorumpb.BBAMsgType_AUX:
+			msg = &quorumpb.Aux{}
+		case quorumpb.BBAMsgType_CONF:
+			msg = &quorumpb.Conf{}
+		case quorumpb.BBAMsgType_COIN:
+			msg = &quorumpb.CoinShare{}
+		default:
+			return "", fmt.Errorf("unknown bba message type <%s>", bbaMsg.Type)
+		}
+		if err := proto.Unmarshal(bbaMsg.Payload, msg); err != nil {
+			return "", err
+		}
+		if err := VerifyBBASign(bft.ks, msg); err != nil {
+			return "", err
+		}
+	case quorumpb.HBMsgPayloadType_DEC:
+		return "", nil
+	default:
+		return "", fmt.Errorf("unknown message type <%s>", hbmsg.PayloadType)
+	}
+
+	var sender string
+	switch m := msg.(type) {
+	case *quorumpb.InitPropose:
+		sender = m.ProposerPubkey
+	case *quorumpb.Echo:
+		sender = m.EchoProviderPubkey
+	case *quorumpb.Ready:
+		sender = m.ReadyProviderPubkey
+	case *quorumpb.Bval:
+		sender = m.SenderPubkey
+	case *quorumpb.Aux:
+		sender = m.SenderPubkey
+	case *quorumpb.Conf:
+		sender = m.SenderPubkey
+	case *quorumpb.CoinShare:
+		sender = m.SenderPubkey
+	}
+	for _, pubkey := range bft.Nodes {
+		if pubkey == sender {
+			return sender, nil
+		}
+	}
+	return "", fmt.Errorf("message from <%s> which is not a producer", sender)
+}
+
+// wakeUp let the waiting task check trx buffer and messages again
+func (bft *TrxBft) wakeUp() {
+	select {
+	case bft.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,58 @@
+package consensus
+
+import (
+	"testing"
+
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+func futureBvalMsg(t *testing.T, net *bbaNet, signer int, sender string, epoch uint64) *quorumpb.HBMsgv1 {
+	bbaMsg, err := MakeBBAMessage(net.keys[signer], "bbagroup", "", &quorumpb.Bval{ProposerId: net.proposer, SenderPubkey: sender, Value: true, AcsEpoch: epoch})
+	if err != nil {
+		t.Fatalf("MakeBBAMessage failed: %s", err)
+	}
+	payload, _ := proto.Marshal(bbaMsg)
+	return &quorumpb.HBMsgv1{Epoch: epoch, PayloadType: quorumpb.HBMsgPayloadType_BBA, Payload: payload}
+}
+
+func TestEpochStartedBySignedProducers(t *testing.T) {
+	net := newBbaNet(t, 4)
+	bft := net.acs[0].bft
+
+	//forged, unsigned and self sent messages are not counted
+	unsigned, _ := proto.Marshal(&quorumpb.BBAMsg{Type: quorumpb.BBAMsgType_BVAL})
+	for name, hbmsg := range map[string]*quorumpb.HBMsgv1{
+		"signed by another producer": futureBvalMsg(t, net, 2, net.pubkeys[1], 2),
+		"not signed":                 {Epoch: 2, PayloadType: quorumpb.HBMsgPayloadType_BBA, Payload: unsigned},
+	} {
+		if err := bft.HandleMessage(hbmsg); err == nil {
+			t.Fatalf("future message %s is accepted", name)
+		}
+	}
+	bft.HandleMessage(futureBvalMsg(t, net, 0, net.pubkeys[0], 2))
+	if n := len(bft.epochStarters(2)); n != 0 {
+		t.Fatalf("<%d> producers started epoch 2, expect none", n)
+	}
+
+	//a single producer, even sending many messages, is not enough
+	for i := 0; i < 3; i++ {
+		if err := bft.HandleMessage(futureBvalMsg(t, net, 1, net.pubkeys[1], 2)); err != nil {
+			t.Fatalf("HandleMessage failed: %s", err)
+		}
+	}
+	if n := len(bft.epochStarters(2)); n != 1 {
+		t.Fatalf("<%d> producers started epoch 2, expect 1", n)
+	}
+
+	//producers hold more than f, messages of later epochs count for earlier ones
+	if err := bft.HandleMessage(futureBvalMsg(t, net, 2, net.pubkeys[2], 3)); err != nil {
+		t.Fatalf("HandleMessage failed: %s", err)
+	}
+	if starters := bft.epochStarters(2); !bft.exceeds(starters, bft.f) {
+		t.Fatalf("<%d> producers started epoch 2, expect more than <%d> stake", len(starters), bft.f)
+	}
+	if n := len(bft.epochStarters(3)); n != 1 {
+		t.Fatalf("<%d> producers started epoch 3, expect 1", n)
+	}
+}
@@ -0,0 +1,56 @@
+package consensus
+
+import (
+	"sort"
+)
+
+var MAX_POS_PRODUCERS = 21 // maximum producers elected for a pos group
+var MIN_POS_STAKE int64 = 1
+
+type posCandidate struct {
+	pubkey string
+	stake  int64
+}
+
+// ElectPosProducers select producers with highest stake from candidates (pubkey -> stake),
+// candidates with stake less than MIN_POS_STAKE are not eligible.
+// producers with lowest stake are dropped till less than 1/3 of producers can fail, see Config, otherwise
+// the threshold key held by producers could be taken by faulty ones holding many small stakes.
+// owner will be the only producer if no candidate is eligible, so the group will not halt
+func ElectPosProducers(candidates map[string]int64, ownerPubkey string) ([]string, map[string]int64) {
+	var eligible []*posCandidate
+	for pubkey, stake := range candidates {
+		if stake >= MIN_POS_STAKE {
+			eligible = append(eligible, &posCandidate{pubkey: pubkey, stake: stake})
+		}
+	}
+
+	if len(eligible) == 0 {
+		return []string{ownerPubkey}, map[string]int64{ownerPubkey: MIN_POS_STAKE}
+	}
+
+	//sort by stake, then by pubkey, all producers should get the same result
+	sort.Slice(eligible, func(i, j int) bool {
+		if eligible[i].stake != eligible[j].stake {
+			return eligible[i].stake > eligible[j].stake
+		}
+		return eligible[i].pubkey < eligible[j].pubkey
+	})
+
+	if len(eligible) > MAX_POS_PRODUCERS {
+		eligible = eligible[:MAX_POS_PRODUCERS]
+	}
+
+	for {
+		nodes := []string{}
+		weights := make(map[string]int64)
+		for _, c := range eligible {
+			nodes = append(nodes, c.pubkey)
+			weights[c.pubkey] = c.stake
+		}
+		if cfg := NewConfig(nodes, weights, 0, ""); cfg.faultyNodes*3 < cfg.N {
+			return nodes, weights
+		}
+		eligible = eligible[:len(eligible)-1]
+	}
+}
@@ -0,0 +1,98 @@
+package consensus
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestElectPosProducers(t *testing.T) {
+	nodes, weights := ElectPosProducers(map[string]int64{"a": 10, "b": 12, "c": 10, "d": 0, "e": 11}, "owner")
+	if !reflect.DeepEqual(nodes, []string{"b", "e", "a", "c"}) {
+		t.Fatalf("elected <%v>, expect sorted by stake then by pubkey, no stake not eligible", nodes)
+	}
+	if len(weights) != 4 || weights["b"] != 12 || weights["d"] != 0 {
+		t.Fatalf("weights <%v>", weights)
+	}
+
+	//no eligible candidate, owner keeps producing
+	nodes, weights = ElectPosProducers(map[string]int64{"a": 0, "b": -5}, "owner")
+	if !reflect.DeepEqual(nodes, []string{"owner"}) || weights["owner"] != MIN_POS_STAKE {
+		t.Fatalf("elected <%v> weights <%v>, expect the owner only", nodes, weights)
+	}
+
+	//c and d hold less than 1/3 of total stake, 2 of 4 producers could fail
+	nodes, _ = ElectPosProducers(map[string]int64{"a": 30, "b": 20, "c": 10, "d": 10}, "owner")
+	if !reflect.DeepEqual(nodes, []string{"a", "b"}) {
+		t.Fatalf("elected <%v>, expect producers with low stake dropped", nodes)
+	}
+
+	candidates := make(map[string]int64)
+	for i := 0; i < MAX_POS_PRODUCERS+5; i++ {
+		candidates[fmt.Sprintf("p%02d", i)] = 100
+	}
+	nodes, _ = ElectPosProducers(candidates, "owner")
+	if len(nodes) != MAX_POS_PRODUCERS || nodes[0] != "p00" || nodes[len(nodes)-1] != fmt.Sprintf("p%02d", MAX_POS_PRODUCERS-1) {
+		t.Fatalf("elected <%d> producers <%v>", len(nodes), nodes)
+	}
+}
+
+func TestNewConfig(t *testing.T) {
+	for _, tc := range []struct {
+		weights     map[string]int64
+		f           int64
+		faultyNodes int
+	}{
+		{map[string]int64{"a": 1}, 0, 0},
+		{map[string]int64{"a": 1, "b": 1, "c": 1, "d": 1}, 1, 1},
+		//a holds 1/3 of total stake, can not fail
+		{map[string]int64{"a": 4, "b": 3, "c": 3, "d": 2}, 3, 1},
+		{map[string]int64{"a": 1, "b": 1, "c": 1, "d": 1, "e": 1, "f": 1, "g": 1}, 2, 2},
+		{map[string]int64{"a": 30, "b": 20, "c": 10, "d": 10}, 23, 2},
+		{map[string]int64{"a": 1 << 48, "b": 1 << 48, "c": 1 << 48, "d": 1 << 48}, 1 << 50 / 3, 1},
+	} {
+		var nodes []string
+		for node := range tc.weights {
+			nodes = append(nodes, node)
+		}
+		cfg := NewConfig(nodes, tc.weights, DEFAULT_BATCH_SIZE, "")
+		if cfg.N != len(nodes) || cfg.f != tc.f || cfg.faultyNodes != tc.faultyNodes {
+			t.Fatalf("config of <%v> has f <%d> and <%d> faulty nodes, expect <%d> and <%d>", tc.weights, cfg.f, cfg.faultyNodes, tc.f, tc.faultyNodes)
+		}
+	}
+
+	//poa, all nodes have the same stake
+	cfg := NewConfig([]string{"a", "b", "c", "d", "e"}, nil, DEFAULT_BATCH_SIZE, "a")
+	if cfg.f != 1 || cfg.faultyNodes != 1 || cfg.Weights["e"] != 1 {
+		t.Fatalf("poa config <%+v>", cfg)
+	}
+}
+
+func TestStakeQuorum(t *testing.T) {
+	cfg := NewConfig([]string{"a", "b", "c"}, nil, DEFAULT_BATCH_SIZE, "a")
+	if cfg.isQuorum(map[string]bool{"a": true, "b": true}) {
+		t.Fatal("2/3 of total stake is not more than 2/3")
+	}
+	if !cfg.isQuorum(map[string]bool{"a": true, "b": true, "c": true}) {
+		t.Fatal("all stake is not a quorum")
+	}
+	if cfg.isQuorum(map[string]bool{"a": true, "b": true, "x": true}) {
+		t.Fatal("node without stake is counted")
+	}
+
+	weights := map[string]int64{"a": 1 << 48, "b": 1 << 48, "c": 1 << 48, "d": 1}
+	cfg = NewConfig([]string{"a", "b", "c", "d"}, weights, DEFAULT_BATCH_SIZE, "a")
+	if !cfg.isQuorum(map[string]bool{"a": true, "b": true, "c": true}) {
+		t.Fatal("more than 2/3 of total stake is not a quorum")
+	}
+	if cfg.isQuorum(map[string]bool{"a": true, "d": true}) {
+		t.Fatal("less than 2/3 of total stake is a quorum")
+	}
+	//f is exactly stake of a, a faulty node
+	if !cfg.exceeds(map[string]bool{"a": true, "d": true}, cfg.f) || cfg.exceeds(map[string]bool{"a": true}, cfg.f) {
+		t.Fatalf("nodes hold more than f <%d> are not found", cfg.f)
+	}
+	if !cfg.exceeds(map[string]bool{"a": true, "b": true, "c": true}, 2*cfg.f) || cfg.exceeds(map[string]bool{"a": true, "d": true}, 2*cfg.f) {
+		t.Fatalf("nodes hold more than 2f <%d> are not found", 2*cfg.f)
+	}
+}
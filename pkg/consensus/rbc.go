@@ -2,9 +2,12 @@ package consensus
 
 import (
 	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
 	"sort"
 
 	"github.com/NebulousLabs/merkletree"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
 	"github.com/klauspost/reedsolomon"
 	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
 	quorumpb "github.com/rumsystem/quorum/pkg/pb"
@@ -17,7 +20,7 @@ func (p Echos) Len() int           { return len(p) }
 func (p Echos) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
 func (p Echos) Less(i, j int) bool { return p[i].Index < p[j].Index }
 
-func MakeRBCInitProposeMessage(groupId, nodename, proposerPubkey string, shards [][]byte, producerList []string, originalDataSize int) ([]*quorumpb.RBCMsg, error) {
+func MakeRBCInitProposeMessage(ks localcrypto.Keystore, groupId, nodename, proposerPubkey string, shards [][]byte, producerList []string, originalDataSize int) ([]*quorumpb.RBCMsg, error) {
 	msgs := make([]*quorumpb.RBCMsg, len(shards))
 
 	for i := 0; i < len(msgs); i++ {
@@ -49,7 +52,6 @@ func MakeRBCInitProposeMessage(groupId, nodename, proposerPubkey string, shards
 
 			//sign it
 			var signature []byte
-			ks := localcrypto.GetKeystore()
 			signature, err = ks.EthSignByKeyName(groupId, payloadhash, nodename)
 			if err != nil {
 				return nil, err
@@ -91,7 +93,6 @@ func MakeRBCInitProposeMessage(groupId, nodename, proposerPubkey string, shards
 
 			//sign it
 			var signature []byte
-			ks := localcrypto.GetKeystore()
 			signature, err = ks.EthSignByKeyName(groupId, payloadhash, nodename)
 			if err != nil {
 				return nil, err
@@ -118,7 +119,7 @@ func MakeRBCInitProposeMessage(groupId, nodename, proposerPubkey string, shards
 	return msgs, nil
 }
 
-func MakeRBCEchoMessage(groupId, nodename, echoProviderPubkey string, initP *quorumpb.InitPropose, originalDataSize int) (*quorumpb.RBCMsg, error) {
+func MakeRBCEchoMessage(ks localcrypto.Keystore, groupId, nodename, echoProviderPubkey string, initP *quorumpb.InitPropose, originalDataSize int) (*quorumpb.RBCMsg, error) {
 	//just dump my part of InitPropose to ProofMsg and sign it
 	payload := &quorumpb.Echo{
 		RootHash:               initP.RootHash,
@@ -140,7 +141,6 @@ func MakeRBCEchoMessage(groupId, nodename, echoProviderPubkey string, initP *quo
 
 	//sign it
 	var signature []byte
-	ks := localcrypto.GetKeystore()
 	signature, err = ks.EthSignByKeyName(groupId, payloadhash, nodename)
 	if err != nil {
 		return nil, err
@@ -159,12 +159,13 @@ func MakeRBCEchoMessage(groupId, nodename, echoProviderPubkey string, initP *quo
 	}, nil
 }
 
-func MakeRBCReadyMessage(groupId, nodename, providerPubkey, originalProposerPubkey string, roothash []byte) (*quorumpb.RBCMsg, error) {
+func MakeRBCReadyMessage(ks localcrypto.Keystore, groupId, nodename, providerPubkey, originalProposerPubkey string, roothash []byte, originalDataSize int64) (*quorumpb.RBCMsg, error) {
 	ready := &quorumpb.Ready{
 		RootHash:               roothash,
 		OriginalProposerPubkey: originalProposerPubkey,
 		ReadyProviderPubkey:    providerPubkey,
 		ReadyProviderSign:      nil,
+		OriginalDataSize:       originalDataSize,
 	}
 
 	//sign root_hash with my pubkey
@@ -176,7 +177,6 @@ func MakeRBCReadyMessage(groupId, nodename, providerPubkey, originalProposerPubk
 	readyHash := localcrypto.Hash(bbytes)
 
 	var signature []byte
-	ks := localcrypto.GetKeystore()
 	signature, err = ks.EthSignByKeyName(groupId, readyHash, nodename)
 	if err != nil {
 		return nil, err
@@ -196,6 +196,52 @@ func MakeRBCReadyMessage(groupId, nodename, providerPubkey, originalProposerPubk
 
 }
 
+// VerifyRBCSign verifies signature of InitPropose, Echo or Ready by pubkey of the proposer or provider in it
+func VerifyRBCSign(ks localcrypto.Keystore, msg proto.Message) error {
+	var pubkey string
+	var sign []byte
+	unsigned := proto.Clone(msg)
+	switch m := unsigned.(type) {
+	case *quorumpb.InitPropose:
+		pubkey, sign = m.ProposerPubkey, m.ProposerSign
+		m.ProposerSign = nil
+	case *quorumpb.Echo:
+		pubkey, sign = m.EchoProviderPubkey, m.EchoProviderSign
+		m.EchoProviderSign = nil
+	case *quorumpb.Ready:
+		pubkey, sign = m.ReadyProviderPubkey, m.ReadyProviderSign
+		m.ReadyProviderSign = nil
+	default:
+		return fmt.Errorf("unknown rbc message <%T>", msg)
+	}
+
+	bbytes, err := proto.Marshal(unsigned)
+	if err != nil {
+		return err
+	}
+	return verifyProducerSign(ks, pubkey, localcrypto.Hash(bbytes), sign)
+}
+
+// verifyProducerSign verifies sign of hash by base64 encoded compressed eth pubkey of a producer
+func verifyProducerSign(ks localcrypto.Keystore, pubkey string, hash []byte, sign []byte) error {
+	//keystore panics on empty sign
+	if len(sign) == 0 {
+		return fmt.Errorf("empty sign from <%s>", pubkey)
+	}
+	bytespubkey, err := base64.RawURLEncoding.DecodeString(pubkey)
+	if err != nil {
+		return err
+	}
+	ethpubkey, err := ethcrypto.DecompressPubkey(bytespubkey)
+	if err != nil {
+		return err
+	}
+	if !ks.EthVerifySign(hash, sign, ethpubkey) {
+		return fmt.Errorf("invalid sign from <%s>", pubkey)
+	}
+	return nil
+}
+
 func TryDecodeValue(echos Echos, enc reedsolomon.Encoder, numPShards int, numDShards int) ([]byte, error) {
 	//sort proof by indexId
 	sort.Sort(echos)
@@ -228,6 +274,9 @@ func TryDecodeValue(echos Echos, enc reedsolomon.Encoder, numPShards int, numDSh
 	receivedDataSize := len(value)
 	//diff
 	diff := receivedDataSize - int(originalDataSize)
+	if diff < 0 {
+		return nil, fmt.Errorf("original data size <%d> is larger than decoded <%d>", originalDataSize, receivedDataSize)
+	}
 	if diff != 0 {
 		trx_rbc_log.Debugf("ECC size different: diff <%d>, fixed", diff)
 		value = value[:len(value)-diff]
@@ -253,8 +302,21 @@ func ValidateEcho(req *quorumpb.Echo) bool {
 		uint64(req.Leaves))
 }
 
+// MerkleRoot return root hash of the merkle tree of shards
+func MerkleRoot(shards [][]byte) []byte {
+	tree := merkletree.New(sha256.New())
+	for _, shard := range shards {
+		tree.Push(shard)
+	}
+	return tree.Root()
+}
+
 func MakeShards(enc reedsolomon.Encoder, data []byte) ([][]byte, error) {
-	shards, err := enc.Split(data)
+	//Split takes spare capacity of data as padding and writes parity shards into it,
+	//split a copy so padding is always zero and data is not changed
+	buf := make([]byte, len(data))
+	copy(buf, data)
+	shards, err := enc.Split(buf)
 	if err != nil {
 		return nil, err
 	}
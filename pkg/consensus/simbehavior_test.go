@@ -0,0 +1,332 @@
+package consensus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/klauspost/reedsolomon"
+	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// simBehavior is how a faulty producer breaks the protocol, tamper returns messages producer to
+// receives when producer from broadcasts hbmsg. faulty producers run the same code as honest ones,
+// so they only lie to others by messages.
+type simBehavior interface {
+	tamper(s *Simulator, from, to *simNode, hbmsg *quorumpb.HBMsgv1) []*quorumpb.HBMsgv1
+}
+
+// crashed producer never starts, it sends and handles nothing
+type crashed struct{}
+
+func (crashed) tamper(s *Simulator, from, to *simNode, hbmsg *quorumpb.HBMsgv1) []*quorumpb.HBMsgv1 {
+	return nil
+}
+
+// bbaEquivocator sends opposite BVAL and AUX values to half of producers, signed by its own key
+type bbaEquivocator struct{}
+
+func (bbaEquivocator) tamper(s *Simulator, from, to *simNode, hbmsg *quorumpb.HBMsgv1) []*quorumpb.HBMsgv1 {
+	if hbmsg.PayloadType != quorumpb.HBMsgPayloadType_BBA || to.index%2 == 0 {
+		return []*quorumpb.HBMsgv1{hbmsg}
+	}
+
+	bbaMsg := &quorumpb.BBAMsg{}
+	if err := proto.Unmarshal(hbmsg.Payload, bbaMsg); err != nil {
+		s.t.Fatalf("decode bba message failed: %s", err)
+	}
+	var m proto.Message
+	switch bbaMsg.Type {
+	case quorumpb.BBAMsgType_BVAL:
+		bval := &quorumpb.Bval{}
+		proto.Unmarshal(bbaMsg.Payload, bval)
+		bval.Value = !bval.Value
+		m = bval
+	case quorumpb.BBAMsgType_AUX:
+		aux := &quorumpb.Aux{}
+		proto.Unmarshal(bbaMsg.Payload, aux)
+		aux.Value = !aux.Value
+		m = aux
+	default:
+		return []*quorumpb.HBMsgv1{hbmsg}
+	}
+	tampered, err := MakeBBAMessage(from.ks, s.groupId, from.nodename, m)
+	if err != nil {
+		s.t.Fatalf("sign bba message failed: %s", err)
+	}
+	return []*quorumpb.HBMsgv1{rewrapHBMsg(hbmsg, tampered)}
+}
+
+// bbaForger sends opposite BVAL and AUX values in the name of all other producers to half of producers,
+// the forged messages are signed by its own key
+type bbaForger struct{}
+
+func (bbaForger) tamper(s *Simulator, from, to *simNode, hbmsg *quorumpb.HBMsgv1) []*quorumpb.HBMsgv1 {
+	msgs := []*quorumpb.HBMsgv1{hbmsg}
+	if hbmsg.PayloadType != quorumpb.HBMsgPayloadType_BBA || to.index%2 == 0 {
+		return msgs
+	}
+
+	bbaMsg := &quorumpb.BBAMsg{}
+	if err := proto.Unmarshal(hbmsg.Payload, bbaMsg); err != nil {
+		s.t.Fatalf("decode bba message failed: %s", err)
+	}
+	for _, victim := range s.nodes {
+		if victim == from {
+			continue
+		}
+		var m proto.Message
+		switch bbaMsg.Type {
+		case quorumpb.BBAMsgType_BVAL:
+			bval := &quorumpb.Bval{}
+			proto.Unmarshal(bbaMsg.Payload, bval)
+			bval.SenderPubkey = victim.pubkey
+			bval.Value = !bval.Value
+			m = bval
+		case quorumpb.BBAMsgType_AUX:
+			aux := &quorumpb.Aux{}
+			proto.Unmarshal(bbaMsg.Payload, aux)
+			aux.SenderPubkey = victim.pubkey
+			aux.Value = !aux.Value
+			m = aux
+		default:
+			return msgs
+		}
+		forged, err := MakeBBAMessage(from.ks, s.groupId, from.nodename, m)
+		if err != nil {
+			s.t.Fatalf("sign bba message failed: %s", err)
+		}
+		msgs = append(msgs, rewrapHBMsg(hbmsg, forged))
+	}
+	return msgs
+}
+
+// rbcEquivocator proposes another value to half of producers
+type rbcEquivocator struct {
+	alt map[uint64][]*quorumpb.RBCMsg //epoch -> InitPropose of the other value
+}
+
+func (b *rbcEquivocator) tamper(s *Simulator, from, to *simNode, hbmsg *quorumpb.HBMsgv1) []*quorumpb.HBMsgv1 {
+	index := ownProposalIndex(s, from, hbmsg)
+	if index < 0 || to.index%2 == 0 {
+		return []*quorumpb.HBMsgv1{hbmsg}
+	}
+
+	if b.alt == nil {
+		b.alt = make(map[uint64][]*quorumpb.RBCMsg)
+	}
+	if _, ok := b.alt[hbmsg.Epoch]; !ok {
+		//a trx nobody sent, honest producers never package it if they agree
+		bundle := &quorumpb.HBTrxBundle{Trxs: []*quorumpb.Trx{{
+			TrxId:   s.groupId + "-equivocated",
+			GroupId: s.groupId,
+			Data:    from.proposal,
+			Expired: SIM_TRX_EXPIRED,
+		}}}
+		value, _ := proto.Marshal(bundle)
+		b.alt[hbmsg.Epoch] = makeSimInitProposes(s, from, value, nil)
+	}
+	return []*quorumpb.HBMsgv1{rewrapHBMsg(hbmsg, b.alt[hbmsg.Epoch][index])}
+}
+
+// sizeEquivocator proposes the same shards to all producers, but tells half of them a larger data size,
+// the decoded value has an extra zero byte for them
+type sizeEquivocator struct{}
+
+func (sizeEquivocator) tamper(s *Simulator, from, to *simNode, hbmsg *quorumpb.HBMsgv1) []*quorumpb.HBMsgv1 {
+	if ownProposalIndex(s, from, hbmsg) < 0 || to.index%2 == 0 {
+		return []*quorumpb.HBMsgv1{hbmsg}
+	}
+
+	rbcMsg, m := decodeSimRBC(s, hbmsg)
+	switch m := m.(type) {
+	case *quorumpb.InitPropose:
+		m.OriginalDataSize++
+	case *quorumpb.Echo:
+		m.OriginalDataSize++
+	}
+	signSimRBC(s, from.ks, m)
+	rbcMsg.Payload, _ = proto.Marshal(m)
+	return []*quorumpb.HBMsgv1{rewrapHBMsg(hbmsg, rbcMsg)}
+}
+
+// badShards proposes shards which are not an erasure code, with a valid merkle tree of them
+type badShards struct {
+	bad map[uint64][]*quorumpb.RBCMsg //epoch -> InitPropose of bad shards
+}
+
+func (b *badShards) tamper(s *Simulator, from, to *simNode, hbmsg *quorumpb.HBMsgv1) []*quorumpb.HBMsgv1 {
+	index := ownProposalIndex(s, from, hbmsg)
+	if index < 0 {
+		return []*quorumpb.HBMsgv1{hbmsg}
+	}
+
+	if b.bad == nil {
+		b.bad = make(map[uint64][]*quorumpb.RBCMsg)
+	}
+	if _, ok := b.bad[hbmsg.Epoch]; !ok {
+		value := append([]byte{}, from.proposal...)
+		b.bad[hbmsg.Epoch] = makeSimInitProposes(s, from, value, func(shards [][]byte) {
+			shards[0][0] ^= 0xff
+		})
+	}
+	return []*quorumpb.HBMsgv1{rewrapHBMsg(hbmsg, b.bad[hbmsg.Epoch][index])}
+}
+
+// wrongSigner signs its rbc messages with a key which is not its own, and sends READY in the name of
+// other producers, signed by its own key or not signed at all
+type wrongSigner struct {
+	ks *simKeystore
+}
+
+func (w *wrongSigner) tamper(s *Simulator, from, to *simNode, hbmsg *quorumpb.HBMsgv1) []*quorumpb.HBMsgv1 {
+	if hbmsg.PayloadType != quorumpb.HBMsgPayloadType_RBC {
+		return []*quorumpb.HBMsgv1{hbmsg}
+	}
+	if w.ks == nil {
+		w.ks = newSimKeystore(s.rng)
+	}
+
+	rbcMsg, m := decodeSimRBC(s, hbmsg)
+	signSimRBC(s, w.ks, m)
+	rbcMsg.Payload, _ = proto.Marshal(m)
+	msgs := []*quorumpb.HBMsgv1{rewrapHBMsg(hbmsg, rbcMsg)}
+
+	if rbcMsg.Type == quorumpb.RBCMsgType_INIT_PROPOSE {
+		victim := s.nodes[(from.index+1)%len(s.nodes)]
+		for _, ks := range []*simKeystore{from.ks, nil} {
+			ready := &quorumpb.Ready{
+				RootHash:               localcrypto.Hash(hbmsg.Payload),
+				OriginalProposerPubkey: victim.pubkey,
+				ReadyProviderPubkey:    victim.pubkey,
+				OriginalDataSize:       int64(len(hbmsg.Payload)),
+			}
+			if ks != nil {
+				signSimRBC(s, ks, ready)
+			}
+			payload, _ := proto.Marshal(ready)
+			msgs = append(msgs, rewrapHBMsg(hbmsg, &quorumpb.RBCMsg{Type: quorumpb.RBCMsgType_READY, Payload: payload}))
+		}
+	}
+	return msgs
+}
+
+// expiredReplayer proposes a trx which expired long ago, like a replay of an old trx which is not
+// in the seen trx index anymore. the same value is proposed to all producers
+type expiredReplayer struct {
+	replayed map[uint64][]*quorumpb.RBCMsg //epoch -> InitPropose of the expired trx
+}
+
+func (b *expiredReplayer) tamper(s *Simulator, from, to *simNode, hbmsg *quorumpb.HBMsgv1) []*quorumpb.HBMsgv1 {
+	index := ownProposalIndex(s, from, hbmsg)
+	if index < 0 {
+		return []*quorumpb.HBMsgv1{hbmsg}
+	}
+
+	if b.replayed == nil {
+		b.replayed = make(map[uint64][]*quorumpb.RBCMsg)
+	}
+	if _, ok := b.replayed[hbmsg.Epoch]; !ok {
+		bundle := &quorumpb.HBTrxBundle{TimeStamp: time.Now().UnixNano(), Trxs: []*quorumpb.Trx{{
+			TrxId:   fmt.Sprintf("%s-expired-%d", s.groupId, hbmsg.Epoch),
+			GroupId: s.groupId,
+			Expired: time.Now().Add(-time.Hour).UnixNano(),
+		}}}
+		value, _ := proto.Marshal(bundle)
+		b.replayed[hbmsg.Epoch] = makeSimInitProposes(s, from, value, nil)
+	}
+	return []*quorumpb.HBMsgv1{rewrapHBMsg(hbmsg, b.replayed[hbmsg.Epoch][index])}
+}
+
+// ownProposalIndex return index of the shard if hbmsg is an InitPropose or the ECHO of producer from for its own proposal, otherwise -1
+func ownProposalIndex(s *Simulator, from *simNode, hbmsg *quorumpb.HBMsgv1) int {
+	if hbmsg.PayloadType != quorumpb.HBMsgPayloadType_RBC {
+		return -1
+	}
+	_, m := decodeSimRBC(s, hbmsg)
+	switch m := m.(type) {
+	case *quorumpb.InitPropose:
+		if m.ProposerPubkey == from.pubkey {
+			return s.index[m.RecvNodePubkey]
+		}
+	case *quorumpb.Echo:
+		if m.OriginalProposerPubkey == from.pubkey && m.EchoProviderPubkey == from.pubkey {
+			return from.index
+		}
+	}
+	return -1
+}
+
+// makeSimInitProposes makes InitPropose of value for all producers, mutate changes shards before merkle trees built
+func makeSimInitProposes(s *Simulator, from *simNode, value []byte, mutate func(shards [][]byte)) []*quorumpb.RBCMsg {
+	ecc, err := reedsolomon.New(len(s.nodes)-2*s.f, 2*s.f)
+	if err != nil {
+		s.t.Fatalf("create ecc failed: %s", err)
+	}
+	shards, err := MakeShards(ecc, value)
+	if err != nil {
+		s.t.Fatalf("make shards failed: %s", err)
+	}
+	if mutate != nil {
+		mutate(shards)
+	}
+	msgs, err := MakeRBCInitProposeMessage(from.ks, s.groupId, from.nodename, from.pubkey, shards, s.pubkeys, len(value))
+	if err != nil {
+		s.t.Fatalf("make InitPropose failed: %s", err)
+	}
+	return msgs
+}
+
+func decodeSimRBC(s *Simulator, hbmsg *quorumpb.HBMsgv1) (*quorumpb.RBCMsg, proto.Message) {
+	rbcMsg := &quorumpb.RBCMsg{}
+	if err := proto.Unmarshal(hbmsg.Payload, rbcMsg); err != nil {
+		s.t.Fatalf("decode rbc message failed: %s", err)
+	}
+	var m proto.Message
+	switch rbcMsg.Type {
+	case quorumpb.RBCMsgType_INIT_PROPOSE:
+		m = &quorumpb.InitPropose{}
+	case quorumpb.RBCMsgType_ECHO:
+		m = &quorumpb.Echo{}
+	case quorumpb.RBCMsgType_READY:
+		m = &quorumpb.Ready{}
+	}
+	if err := proto.Unmarshal(rbcMsg.Payload, m); err != nil {
+		s.t.Fatalf("decode rbc payload failed: %s", err)
+	}
+	return rbcMsg, m
+}
+
+// signSimRBC signs an rbc message by ks, like MakeRBC* functions do
+func signSimRBC(s *Simulator, ks *simKeystore, m proto.Message) {
+	sign := func() []byte {
+		b, _ := proto.Marshal(m)
+		sig, err := ks.EthSignByKeyName(s.groupId, localcrypto.Hash(b))
+		if err != nil {
+			s.t.Fatalf("sign rbc message failed: %s", err)
+		}
+		return sig
+	}
+	switch m := m.(type) {
+	case *quorumpb.InitPropose:
+		m.ProposerSign = nil
+		m.ProposerSign = sign()
+	case *quorumpb.Echo:
+		m.EchoProviderSign = nil
+		m.EchoProviderSign = sign()
+	case *quorumpb.Ready:
+		m.ReadyProviderSign = nil
+		m.ReadyProviderSign = sign()
+	}
+}
+
+func rewrapHBMsg(hbmsg *quorumpb.HBMsgv1, m proto.Message) *quorumpb.HBMsgv1 {
+	payload, _ := proto.Marshal(m)
+	return &quorumpb.HBMsgv1{
+		MsgId:       hbmsg.MsgId,
+		Epoch:       hbmsg.Epoch,
+		PayloadType: hbmsg.PayloadType,
+		Payload:     payload,
+	}
+}
@@ -0,0 +1,149 @@
+package consensus
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func runSimulation(t *testing.T, cfg SimConfig) *Simulator {
+	s := NewSimulator(t, cfg)
+	start := time.Now()
+	s.Run()
+	t.Logf("seed <%d>: <%d> epochs, <%d> trxs, <%d> messages delivered, <%d> dropped, virtual time <%s>, took <%s>",
+		s.cfg.Seed, s.minHonestEpoch(), len(s.trxs), s.delivered, s.dropped, s.now, time.Since(start))
+	return s
+}
+
+func TestSimHonest(t *testing.T) {
+	for _, cfg := range []SimConfig{
+		{Seed: 1, Producers: 4, Epochs: 1000, MinDelay: time.Millisecond, MaxDelay: 50 * time.Millisecond, TrxInterval: 20 * time.Millisecond},
+		{Seed: 2, Producers: 7, Epochs: 200, MinDelay: time.Millisecond, MaxDelay: 500 * time.Millisecond, TrxInterval: 200 * time.Millisecond},
+		{Seed: 3, Producers: 1, Epochs: 200, TrxInterval: 10 * time.Millisecond, EpochPause: 100 * time.Millisecond},
+	} {
+		s := runSimulation(t, cfg)
+		s.CheckAgreement()
+		s.CheckValidity()
+		s.CheckLiveness()
+	}
+}
+
+func TestSimThresholdEncryption(t *testing.T) {
+	s := runSimulation(t, SimConfig{Seed: 4, Producers: 4, Epochs: 100, MinDelay: time.Millisecond, MaxDelay: 100 * time.Millisecond, TrxInterval: 20 * time.Millisecond})
+	for _, node := range s.honestNodes() {
+		if node.producer.bft.dkg.ReadyKey() == nil {
+			t.Fatalf("threshold key of producer <%d> is not ready", node.index)
+		}
+	}
+	s.CheckAgreement()
+	s.CheckValidity()
+	s.CheckLiveness()
+}
+
+func TestSimPartition(t *testing.T) {
+	for _, cfg := range []SimConfig{
+		//no side has a quorum, epochs continue after the partition heals
+		{Seed: 5, Producers: 4, Epochs: 200, MinDelay: time.Millisecond, MaxDelay: 50 * time.Millisecond, TrxInterval: 20 * time.Millisecond,
+			Partitions: []SimPartition{{From: 2 * time.Second, To: 30 * time.Second, Nodes: []int{0, 1}}}},
+		//repeated short partitions of different producers
+		{Seed: 6, Producers: 7, Epochs: 200, MinDelay: time.Millisecond, MaxDelay: 50 * time.Millisecond, TrxInterval: 50 * time.Millisecond,
+			Partitions: []SimPartition{
+				{From: 1 * time.Second, To: 3 * time.Second, Nodes: []int{0, 1, 2, 3}},
+				{From: 5 * time.Second, To: 6 * time.Second, Nodes: []int{6}},
+				{From: 8 * time.Second, To: 12 * time.Second, Nodes: []int{1, 3, 5}},
+			}},
+	} {
+		s := runSimulation(t, cfg)
+		s.CheckAgreement()
+		s.CheckValidity()
+		s.CheckLiveness()
+	}
+}
+
+func TestSimByzantine(t *testing.T) {
+	behaviors := map[string]func() simBehavior{
+		"crashed":         func() simBehavior { return crashed{} },
+		"bbaEquivocator":  func() simBehavior { return bbaEquivocator{} },
+		"bbaForger":       func() simBehavior { return bbaForger{} },
+		"rbcEquivocator":  func() simBehavior { return &rbcEquivocator{} },
+		"sizeEquivocator": func() simBehavior { return sizeEquivocator{} },
+		"badShards":       func() simBehavior { return &badShards{} },
+		"wrongSigner":     func() simBehavior { return &wrongSigner{} },
+	}
+	for name, behavior := range behaviors {
+		t.Run(name, func(t *testing.T) {
+			for _, cfg := range []SimConfig{
+				{Seed: 7, Producers: 4, Epochs: 100, MinDelay: time.Millisecond, MaxDelay: 50 * time.Millisecond, TrxInterval: 20 * time.Millisecond,
+					Faulty: map[int]simBehavior{1: behavior()}},
+				{Seed: 8, Producers: 7, Epochs: 50, MinDelay: time.Millisecond, MaxDelay: 200 * time.Millisecond, TrxInterval: 100 * time.Millisecond,
+					Faulty: map[int]simBehavior{0: behavior(), 4: behavior()}},
+			} {
+				s := runSimulation(t, cfg)
+				s.CheckAgreement()
+				s.CheckValidity()
+				s.CheckLiveness()
+			}
+		})
+	}
+}
+
+// expired trxs in agreed proposals are dropped by all honest producers
+func TestSimExpiredTrx(t *testing.T) {
+	s := runSimulation(t, SimConfig{Seed: 11, Producers: 4, Epochs: 100, MinDelay: time.Millisecond, MaxDelay: 50 * time.Millisecond, TrxInterval: 20 * time.Millisecond,
+		Faulty: map[int]simBehavior{3: &expiredReplayer{}}})
+	for _, node := range s.honestNodes() {
+		for epoch, trxs := range node.blocks {
+			for _, trxId := range trxs {
+				if strings.Contains(trxId, "-expired-") {
+					t.Errorf("producer <%d> packaged expired trx <%s> in epoch <%d>", node.index, trxId, epoch)
+				}
+			}
+		}
+	}
+	s.CheckAgreement()
+	s.CheckValidity()
+	s.CheckLiveness()
+}
+
+func TestMedianTimeStamp(t *testing.T) {
+	poa := map[string]int64{"a": 1, "b": 1, "c": 1, "d": 1}
+	for _, c := range []struct {
+		timestamps map[string]int64
+		weights    map[string]int64
+		median     int64
+	}{
+		{nil, poa, 0},
+		{map[string]int64{"a": 5}, poa, 5},
+		{map[string]int64{"a": 3, "b": 1, "c": 2}, poa, 2},
+		{map[string]int64{"a": 9, "b": 1, "c": 7, "d": 3}, poa, 7},
+		{map[string]int64{"a": 1 << 62, "b": 10, "c": 11, "d": 12}, poa, 12}, //a faulty producer can not move the median out of honest times
+		{map[string]int64{"a": 9, "b": 1, "c": 7, "d": 3}, map[string]int64{"a": 1, "b": 5, "c": 1, "d": 1}, 1},
+		{map[string]int64{"a": 1 << 62, "b": 10, "c": 11}, map[string]int64{"a": 2, "b": 2, "c": 3}, 11},
+	} {
+		if median := medianTimeStamp(c.timestamps, c.weights); median != c.median {
+			t.Errorf("median of %v with stake %v is %d, expect %d", c.timestamps, c.weights, median, c.median)
+		}
+	}
+}
+
+func TestSimDeterministic(t *testing.T) {
+	cfg := SimConfig{Seed: 9, Producers: 4, Epochs: 50, MinDelay: time.Millisecond, MaxDelay: 100 * time.Millisecond, TrxInterval: 20 * time.Millisecond,
+		Faulty: map[int]simBehavior{2: bbaEquivocator{}}}
+	//the dkg deals random polynomials, so coins are the same only with the same threshold keys
+	cfg.Keys = runSimulation(t, cfg).ThresholdKeys()
+	a := runSimulation(t, cfg)
+	b := runSimulation(t, cfg)
+	if a.Trace() != b.Trace() || a.now != b.now || a.stalled != b.stalled {
+		t.Fatalf("runs of seed <%d> are different", a.cfg.Seed)
+	}
+}
+
+// messages are not resent, a lost message may stall the consensus, only safety is checked
+func TestSimMessageLoss(t *testing.T) {
+	s := runSimulation(t, SimConfig{Seed: 10, Producers: 4, Epochs: 200, MinDelay: time.Millisecond, MaxDelay: 50 * time.Millisecond, TrxInterval: 20 * time.Millisecond,
+		DropRate: 0.001})
+	s.CheckAgreement()
+	if s.stalled {
+		t.Logf("seed <%d>: consensus stalled with lost messages\n%s", s.cfg.Seed, s.Describe())
+	}
+}
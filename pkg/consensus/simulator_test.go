@@ -0,0 +1,684 @@
+package consensus
+
+import (
+	"container/heap"
+	"context"
+	"crypto/ecdsa"
+	"encoding/base64"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	chaindef "github.com/rumsystem/quorum/internal/pkg/chainsdk/def"
+	"github.com/rumsystem/quorum/internal/pkg/nodectx"
+	"github.com/rumsystem/quorum/internal/pkg/storage"
+	chainstorage "github.com/rumsystem/quorum/internal/pkg/storage/chain"
+	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
+	rumchaindata "github.com/rumsystem/quorum/pkg/data"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+)
+
+var simSeed = flag.Int64("simseed", 0, "seed of consensus simulations, 0 to use seeds of the tests")
+var simEpochs = flag.Uint64("simepochs", 0, "epochs of consensus simulations, 0 to use epochs of the tests")
+
+var SIM_SHORT_EPOCHS uint64 = 25                                             //maximum epochs of a simulation with -short, every bba round verifies signed messages and coin shares
+var SIM_DRAIN_EPOCHS uint64 = 20                                             //maximum epochs run after cfg.Epochs till trxs left in buffers of honest producers are packaged
+var SIM_STALL_TIMEOUT = time.Minute                                          //consensus is stalled if an honest producer commits no epoch in this virtual time
+var SIM_TRX_EXPIRED = time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano() //trxs never expire in simulations
+
+// SimConfig describes a group of simulated producers and the network between them
+type SimConfig struct {
+	Seed        int64
+	Producers   int
+	Epochs      uint64                      // client sends trxs till all honest producers committed this epoch, the simulation ends after trxs left are packaged
+	MinDelay    time.Duration               // delay of a message is random between MinDelay and MaxDelay, so messages are reordered
+	MaxDelay    time.Duration               //
+	DropRate    float64                     // probability a message to other producers is lost
+	Partitions  []SimPartition              //
+	Faulty      map[int]simBehavior         // faulty producers by index, no more than f of them
+	Keys        []*localcrypto.ThresholdKey // threshold keys of producers by index, given instead of running the dkg
+	TrxInterval time.Duration               // client sends a trx to f + 1 random producers in each interval
+	EpochPause  time.Duration               // a producer waits this long after it committed an epoch before starting the next one, like epoch pacing
+}
+
+// SimPartition cuts producers in Nodes off from others between From and To,
+// messages sent across the partition are delivered after it heals
+type SimPartition struct {
+	From  time.Duration
+	To    time.Duration
+	Nodes []int
+}
+
+// Simulator runs producers of a group in one goroutine, messages between them are delivered by
+// a virtual clock in an order decided by the seed only, so every run of a seed is the same
+type Simulator struct {
+	t       *testing.T
+	cfg     SimConfig
+	rng     *rand.Rand
+	groupId string
+	f       int
+	nodes   []*simNode
+	pubkeys []string
+	index   map[string]int //pubkey -> index of producer
+
+	now    time.Duration
+	seq    uint64
+	events simEvents
+	inDkg  bool //dkg runs on a reliable network before the first epoch
+
+	trxs         []string //trxs sent by client
+	drainedEpoch uint64   //epoch all honest producers should commit after buffers drained, 0 if not drained yet
+	delivered    int
+	dropped      int
+	trace        hash.Hash64
+	stalled      bool
+}
+
+type simNode struct {
+	index    int
+	pubkey   string
+	nodename string
+	ks       *simKeystore
+	chain    *simChain
+	producer *MolassesProducer
+	behavior simBehavior //nil for honest producers
+
+	epoch      uint64              //epoch of the running acs, 0 if not running
+	proposal   []byte              //proposed data of the running epoch
+	blocks     map[uint64][]string //epoch -> ids of trxs packaged
+	rejected   int                 //messages handled with error
+	lastCommit time.Duration       //virtual time of the last epoch committed
+}
+
+var simCtxOnce sync.Once
+var simRuns int //producers of each run have their own names, they share the storage
+
+func initSimCtx() {
+	simCtxOnce.Do(func() {
+		dbmgr := &storage.DbMgr{GroupInfoDb: storage.NewMemStore(), Db: storage.NewMemStore(), Auth: storage.NewMemStore()}
+		nodectx.InitCtx(context.Background(), "sim", nil, dbmgr, chainstorage.NewChainStorage(dbmgr), "", "", nodectx.PRODUCER_NODE)
+	})
+}
+
+func NewSimulator(t *testing.T, cfg SimConfig) *Simulator {
+	if *simSeed != 0 {
+		cfg.Seed = *simSeed
+	}
+	if *simEpochs != 0 {
+		cfg.Epochs = *simEpochs
+	} else if testing.Short() && cfg.Epochs > SIM_SHORT_EPOCHS {
+		cfg.Epochs = SIM_SHORT_EPOCHS
+	}
+	if cfg.MaxDelay < cfg.MinDelay {
+		cfg.MaxDelay = cfg.MinDelay
+	}
+	initSimCtx()
+
+	//dkg messages are resent by a timer, nothing is lost before the first epoch
+	resendInterval := DKG_RESEND_INTERVAL
+	DKG_RESEND_INTERVAL = 24 * time.Hour
+
+	simRuns++
+	s := &Simulator{
+		t:       t,
+		cfg:     cfg,
+		rng:     rand.New(rand.NewSource(cfg.Seed)),
+		groupId: "simgroup", //the same for all runs, common coins of bba are derived from it
+		f:       (cfg.Producers - 1) / 3,
+		index:   make(map[string]int),
+		trace:   fnv.New64a(),
+	}
+
+	for i := 0; i < cfg.Producers; i++ {
+		ks := newSimKeystore(s.rng)
+		pubkey := base64.RawURLEncoding.EncodeToString(ethcrypto.CompressPubkey(&ks.priv.PublicKey))
+		s.pubkeys = append(s.pubkeys, pubkey)
+		s.index[pubkey] = i
+		s.nodes = append(s.nodes, &simNode{
+			index:    i,
+			pubkey:   pubkey,
+			nodename: fmt.Sprintf("sim-%d-producer-%d", simRuns, i),
+			ks:       ks,
+			chain:    &simChain{},
+			behavior: cfg.Faulty[i],
+			blocks:   make(map[uint64][]string),
+		})
+	}
+	if len(cfg.Faulty) > s.f {
+		t.Fatalf("<%d> faulty producers, but only <%d> can be tolerated", len(cfg.Faulty), s.f)
+	}
+
+	genesis, err := rumchaindata.CreateGenesisBlockByEthKey(s.groupId, s.pubkeys[0], s.nodes[0].ks, "")
+	if err != nil {
+		t.Fatalf("create genesis block failed: %s", err)
+	}
+
+	for _, node := range s.nodes {
+		if err := nodectx.GetNodeCtx().GetChainStorage().AddBlock(genesis, false, node.nodename); err != nil {
+			t.Fatalf("add genesis block failed: %s", err)
+		}
+		cfg := NewConfig(s.pubkeys, nil, DEFAULT_BATCH_SIZE, node.pubkey)
+		producer := &MolassesProducer{
+			grpItem: &quorumpb.GroupItem{
+				GroupId:        s.groupId,
+				UserSignPubkey: node.pubkey,
+				OwnerPubKey:    s.pubkeys[0],
+				ConsenseType:   quorumpb.GroupConsenseType_POA,
+			},
+			nodename: node.nodename,
+			cIface:   node.chain,
+			groupId:  s.groupId,
+			seenTrxs: rumchaindata.NewSeenTrxIndex(rumchaindata.MAX_SEEN_TRX_COUNT),
+		}
+		producer.bft = newTrxBft(cfg, producer, node.ks, &simLink{sim: s, from: node})
+		producer.bft.manual = true
+		//producers share the storage, each of them needs its own trx buffer
+		producer.bft.txBuffer = NewTrxBuffer(s.groupId + "_" + node.nodename)
+		node.producer = producer
+	}
+
+	t.Cleanup(func() {
+		for _, node := range s.nodes {
+			node.producer.bft.dkg.Stop()
+		}
+		DKG_RESEND_INTERVAL = resendInterval
+	})
+	return s
+}
+
+// Run runs the simulation till all honest producers committed cfg.Epochs and packaged trxs left, or the consensus stalled
+func (s *Simulator) Run() {
+	if s.cfg.Keys != nil {
+		for i, node := range s.nodes {
+			d := node.producer.bft.dkg
+			d.key, d.ready = s.cfg.Keys[i], s.cfg.Keys[i] != nil
+		}
+	} else {
+		s.runDkg()
+	}
+
+	for _, node := range s.nodes {
+		if s.isCrashed(node) {
+			continue
+		}
+		node := node
+		s.after(0, func() { s.startEpoch(node) })
+	}
+	if s.cfg.TrxInterval > 0 {
+		s.after(s.cfg.TrxInterval, s.sendTrx)
+	}
+
+	for !s.isDone() {
+		if s.events.Len() == 0 || s.isStalled() {
+			s.stalled = true
+			return
+		}
+		ev := heap.Pop(&s.events).(*simEvent)
+		s.now = ev.at
+		ev.run()
+	}
+}
+
+// runDkg generates the threshold key on a reliable network, faulty producers behave in it
+func (s *Simulator) runDkg() {
+	s.inDkg = true
+	defer func() { s.inDkg = false }()
+
+	for _, node := range s.nodes {
+		if !s.isCrashed(node) {
+			node.producer.bft.dkg.Start()
+		}
+	}
+	s.drainEvents()
+	if !s.dkgReady() {
+		//faulty producers never joined, go on without them like after DKG_JOIN_TIMEOUT
+		joinTimeout := DKG_JOIN_TIMEOUT
+		DKG_JOIN_TIMEOUT = 0
+		for _, node := range s.honestNodes() {
+			d := node.producer.bft.dkg
+			d.mu.Lock()
+			d.progress()
+			d.mu.Unlock()
+		}
+		s.drainEvents()
+		DKG_JOIN_TIMEOUT = joinTimeout
+	}
+	if !s.dkgReady() {
+		s.t.Fatalf("threshold key is not ready, epochs can not finish without the coin")
+	}
+	for _, node := range s.nodes {
+		node.lastCommit = s.now
+	}
+}
+
+func (s *Simulator) drainEvents() {
+	for s.events.Len() > 0 {
+		ev := heap.Pop(&s.events).(*simEvent)
+		s.now = ev.at
+		ev.run()
+	}
+}
+
+// ThresholdKeys return threshold keys of producers, the common coin depends on them
+func (s *Simulator) ThresholdKeys() []*localcrypto.ThresholdKey {
+	var keys []*localcrypto.ThresholdKey
+	for _, node := range s.nodes {
+		keys = append(keys, node.producer.bft.dkg.ReadyKey())
+	}
+	return keys
+}
+
+func (s *Simulator) dkgReady() bool {
+	for _, node := range s.honestNodes() {
+		if node.producer.bft.dkg.ReadyKey() == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Simulator) startEpoch(node *simNode) {
+	bft := node.producer.bft
+	task, _ := bft.NewProposeTask()
+	data, err := bft.buildProposal(task.Epoch)
+	if err != nil {
+		s.t.Fatalf("producer <%d> build proposal failed: %s", node.index, err)
+	}
+	task.ProposedData = data
+	node.epoch = task.Epoch
+	node.proposal = data
+
+	bft.acsMu.Lock()
+	bft.startAcs(task)
+	bft.acsMu.Unlock()
+	s.checkCommit(node)
+}
+
+// checkCommit starts the next epoch of the producer after its acs is done
+func (s *Simulator) checkCommit(node *simNode) {
+	if node.epoch == 0 || node.chain.GetCurrEpoch() < node.epoch {
+		return
+	}
+	node.epoch = 0
+	node.lastCommit = s.now
+	s.after(s.cfg.EpochPause, func() { s.startEpoch(node) })
+}
+
+// sendTrx sends a new trx to f + 1 random producers, so at least one honest producer proposes it
+func (s *Simulator) sendTrx() {
+	if s.minHonestEpoch() >= s.cfg.Epochs {
+		return
+	}
+
+	count := len(s.trxs) + 1
+	data := make([]byte, 64+s.rng.Intn(448))
+	s.rng.Read(data)
+	trx := &quorumpb.Trx{
+		TrxId:        fmt.Sprintf("%s-trx-%d", s.groupId, count),
+		Type:         quorumpb.TrxType_POST,
+		GroupId:      s.groupId,
+		SenderPubkey: fmt.Sprintf("user-%d", s.rng.Intn(8)),
+		Data:         data,
+		TimeStamp:    int64(count),
+		Expired:      SIM_TRX_EXPIRED,
+	}
+	s.trxs = append(s.trxs, trx.TrxId)
+
+	for _, i := range s.rng.Perm(len(s.nodes))[:s.f+1] {
+		node := s.nodes[i]
+		if s.isCrashed(node) {
+			continue
+		}
+		if err := node.producer.bft.AddTrx(trx); err != nil {
+			s.t.Fatalf("producer <%d> add trx failed: %s", node.index, err)
+		}
+	}
+	s.after(s.cfg.TrxInterval, s.sendTrx)
+}
+
+// send delivers a message from a producer to all producers (itself included), faulty producers
+// may tamper it for each receiver
+func (s *Simulator) send(from *simNode, hbmsg *quorumpb.HBMsgv1) {
+	for _, to := range s.nodes {
+		msgs := []*quorumpb.HBMsgv1{hbmsg}
+		if from.behavior != nil && to != from && !s.inDkg {
+			msgs = from.behavior.tamper(s, from, to, hbmsg)
+		}
+
+		for _, msg := range msgs {
+			if s.isCrashed(to) {
+				continue
+			}
+			delay := time.Duration(0)
+			if to != from {
+				if s.cfg.DropRate > 0 && !s.inDkg && s.rng.Float64() < s.cfg.DropRate {
+					s.dropped++
+					continue
+				}
+				delay = s.delay(from, to)
+			}
+			to, msg := to, msg
+			s.after(delay, func() { s.deliver(from, to, msg) })
+		}
+	}
+}
+
+// delay return a random delay of a message between two producers, messages across a partition wait till it heals
+func (s *Simulator) delay(from, to *simNode) time.Duration {
+	delay := s.cfg.MinDelay
+	if s.cfg.MaxDelay > s.cfg.MinDelay {
+		delay += time.Duration(s.rng.Int63n(int64(s.cfg.MaxDelay - s.cfg.MinDelay)))
+	}
+	if heal := s.healTime(from, to); heal > s.now {
+		delay += heal - s.now
+	}
+	return delay
+}
+
+func (s *Simulator) deliver(from, to *simNode, hbmsg *quorumpb.HBMsgv1) {
+	s.delivered++
+	var b [8]byte
+	for _, v := range []uint64{uint64(s.now), uint64(from.index), uint64(to.index), uint64(hbmsg.PayloadType), hbmsg.Epoch, uint64(len(hbmsg.Payload))} {
+		binary.BigEndian.PutUint64(b[:], v)
+		s.trace.Write(b[:])
+	}
+
+	if err := to.producer.bft.HandleMessage(hbmsg); err != nil {
+		to.rejected++
+	}
+	s.checkCommit(to)
+}
+
+// healTime return the time a message sent now from producer from to producer to can be delivered
+func (s *Simulator) healTime(from, to *simNode) time.Duration {
+	heal := s.now
+	for _, p := range s.cfg.Partitions {
+		if s.now < p.From || s.now >= p.To {
+			continue
+		}
+		if inPartition(p, from.index) != inPartition(p, to.index) && p.To > heal {
+			heal = p.To
+		}
+	}
+	return heal
+}
+
+func inPartition(p SimPartition, index int) bool {
+	for _, i := range p.Nodes {
+		if i == index {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Simulator) after(d time.Duration, run func()) {
+	s.seq++
+	heap.Push(&s.events, &simEvent{at: s.now + d, seq: s.seq, run: run})
+}
+
+func (s *Simulator) isCrashed(node *simNode) bool {
+	_, ok := node.behavior.(crashed)
+	return ok
+}
+
+func (s *Simulator) honestNodes() []*simNode {
+	var nodes []*simNode
+	for _, node := range s.nodes {
+		if node.behavior == nil {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+func (s *Simulator) minHonestEpoch() uint64 {
+	var min uint64
+	for i, node := range s.honestNodes() {
+		if epoch := node.chain.GetCurrEpoch(); i == 0 || epoch < min {
+			min = epoch
+		}
+	}
+	return min
+}
+
+// isStalled return true if an honest producer has not committed for SIM_STALL_TIMEOUT, even if others go on
+func (s *Simulator) isStalled() bool {
+	for _, node := range s.honestNodes() {
+		if s.now-node.lastCommit > SIM_STALL_TIMEOUT {
+			return true
+		}
+	}
+	return false
+}
+
+// isDone return true after trxs left are packaged and all honest producers committed epochs of them
+func (s *Simulator) isDone() bool {
+	epoch := s.minHonestEpoch()
+	if s.drainedEpoch > 0 {
+		return epoch >= s.drainedEpoch
+	}
+	if epoch < s.cfg.Epochs {
+		return false
+	}
+	if epoch < s.cfg.Epochs+SIM_DRAIN_EPOCHS {
+		for _, node := range s.honestNodes() {
+			if n, _ := node.producer.bft.txBuffer.GetBufferLen(); n > 0 {
+				return false
+			}
+		}
+	}
+	//trxs were packaged by some producers, others package them in the same epochs
+	for _, node := range s.honestNodes() {
+		if e := node.chain.GetCurrEpoch(); e > s.drainedEpoch {
+			s.drainedEpoch = e
+		}
+	}
+	return epoch >= s.drainedEpoch
+}
+
+// Trace return hash of all messages delivered, runs of the same config have the same trace
+func (s *Simulator) Trace() uint64 {
+	return s.trace.Sum64()
+}
+
+// CheckAgreement checks all honest producers packaged the same trxs in each epoch,
+// and no trx is packaged twice
+func (s *Simulator) CheckAgreement() {
+	honest := s.honestNodes()
+	for _, node := range honest {
+		seen := make(map[string]uint64)
+		for epoch, trxs := range node.blocks {
+			for _, trxId := range trxs {
+				if prev, ok := seen[trxId]; ok {
+					s.t.Errorf("seed <%d>: producer <%d> packaged trx <%s> in epoch <%d> and <%d>", s.cfg.Seed, node.index, trxId, prev, epoch)
+				}
+				seen[trxId] = epoch
+			}
+		}
+	}
+
+	for _, node := range honest[1:] {
+		ref := honest[0]
+		last := ref.chain.GetCurrEpoch()
+		if epoch := node.chain.GetCurrEpoch(); epoch < last {
+			last = epoch
+		}
+		for epoch := uint64(1); epoch <= last; epoch++ {
+			if a, b := strings.Join(ref.blocks[epoch], ","), strings.Join(node.blocks[epoch], ","); a != b {
+				s.t.Errorf("seed <%d>: producers <%d> and <%d> disagree on epoch <%d>:\n<%s>\n<%s>", s.cfg.Seed, ref.index, node.index, epoch, a, b)
+				return
+			}
+		}
+	}
+}
+
+// CheckValidity checks all trxs sent by client are packaged by all honest producers
+func (s *Simulator) CheckValidity() {
+	for _, node := range s.honestNodes() {
+		packaged := make(map[string]bool)
+		for _, trxs := range node.blocks {
+			for _, trxId := range trxs {
+				packaged[trxId] = true
+			}
+		}
+		missing := 0
+		for _, trxId := range s.trxs {
+			if !packaged[trxId] {
+				missing++
+			}
+		}
+		if missing > 0 {
+			s.t.Errorf("seed <%d>: <%d> of <%d> trxs not packaged by producer <%d>", s.cfg.Seed, missing, len(s.trxs), node.index)
+		}
+	}
+}
+
+// CheckLiveness checks all honest producers committed all epochs
+func (s *Simulator) CheckLiveness() {
+	if s.stalled {
+		s.t.Errorf("seed <%d>: consensus stalled\n%s", s.cfg.Seed, s.Describe())
+	}
+}
+
+// Describe return states of all producers, to find out why consensus stalled
+func (s *Simulator) Describe() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "virtual time <%s>, <%d> messages delivered, <%d> dropped\n", s.now, s.delivered, s.dropped)
+	for _, node := range s.nodes {
+		bft := node.producer.bft
+		fmt.Fprintf(&b, "producer <%d> %T: committed epoch <%d>, <%d> messages rejected", node.index, node.behavior, node.chain.GetCurrEpoch(), node.rejected)
+		if acs := bft.acsInsts; acs != nil && node.epoch != 0 {
+			var rbcDone, bbaDone []string
+			for pubkey := range acs.rbcOutput {
+				rbcDone = append(rbcDone, fmt.Sprint(s.index[pubkey]))
+			}
+			for pubkey, value := range acs.bbaOutput {
+				bbaDone = append(bbaDone, fmt.Sprintf("%d:%v", s.index[pubkey], value))
+			}
+			sort.Strings(rbcDone)
+			sort.Strings(bbaDone)
+			fmt.Fprintf(&b, ", running epoch <%d>, rbc done %v, bba decided %v, decrypted <%v>, <%d> future messages",
+				acs.Epoch, rbcDone, bbaDone, acs.done, len(bft.futureMsgs))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// simLink is the network of a simulated producer
+type simLink struct {
+	sim  *Simulator
+	from *simNode
+}
+
+func (l *simLink) BroadcastHBMsg(groupId string, hbmsg *quorumpb.HBMsgv1) error {
+	l.sim.send(l.from, hbmsg)
+	return nil
+}
+
+func (l *simLink) BroadcastBlock(groupId string, block *quorumpb.Block) error {
+	l.from.blocks[block.Epoch] = blockTrxIds(block)
+	return nil
+}
+
+func blockTrxIds(block *quorumpb.Block) []string {
+	var trxs []string
+	for _, trx := range block.Trxs {
+		trxs = append(trxs, trx.TrxId)
+	}
+	return trxs
+}
+
+// simChain is the chain of a simulated producer, packaged trxs are saved like a real chain,
+// so they will not be packaged again
+type simChain struct {
+	epoch      uint64
+	blockId    uint64
+	lastUpdate int64
+}
+
+func (c *simChain) GetTrxFactory() chaindef.TrxFactoryIface { return nil }
+func (c *simChain) SaveChainInfoToDb() error                { return nil }
+
+func (c *simChain) ApplyTrxsFullNode(trxs []*quorumpb.Trx, nodename string) error {
+	return c.ApplyTrxsProducerNode(trxs, nodename)
+}
+
+func (c *simChain) ApplyTrxsProducerNode(trxs []*quorumpb.Trx, nodename string) error {
+	for _, trx := range trxs {
+		if err := nodectx.GetNodeCtx().GetChainStorage().AddTrx(trx, nodename); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *simChain) SetCurrEpoch(currEpoch uint64)   { c.epoch = currEpoch }
+func (c *simChain) IncCurrEpoch()                   { c.epoch++ }
+func (c *simChain) GetCurrEpoch() uint64            { return c.epoch }
+func (c *simChain) SetCurrBlockId(currBlock uint64) { c.blockId = currBlock }
+func (c *simChain) IncCurrBlockId()                 { c.blockId++ }
+func (c *simChain) GetCurrBlockId() uint64          { return c.blockId }
+func (c *simChain) SetLastUpdate(lastUpdate int64)  { c.lastUpdate = lastUpdate }
+func (c *simChain) GetLastUpdate() int64            { return c.lastUpdate }
+func (c *simChain) TryCreateSnapshot()              {}
+
+// simKeystore holds the sign key of a simulated producer, only methods used by consensus are implemented
+type simKeystore struct {
+	localcrypto.Keystore
+	priv *ecdsa.PrivateKey
+}
+
+func newSimKeystore(rng *rand.Rand) *simKeystore {
+	for {
+		b := make([]byte, 32)
+		rng.Read(b)
+		if priv, err := ethcrypto.ToECDSA(b); err == nil {
+			return &simKeystore{priv: priv}
+		}
+	}
+}
+
+func (ks *simKeystore) EthSignByKeyName(keyname string, digestHash []byte, opts ...string) ([]byte, error) {
+	return ethcrypto.Sign(digestHash, ks.priv)
+}
+
+func (ks *simKeystore) EthVerifySign(digestHash, signature []byte, pubKey *ecdsa.PublicKey) bool {
+	sig := signature[:len(signature)-1] // remove recovery id
+	return ethcrypto.VerifySignature(ethcrypto.FromECDSAPub(pubKey), digestHash, sig)
+}
+
+type simEvent struct {
+	at  time.Duration
+	seq uint64
+	run func()
+}
+
+// simEvents is a heap of events ordered by time, events of the same time run in the order they were added
+type simEvents []*simEvent
+
+func (e simEvents) Len() int { return len(e) }
+func (e simEvents) Less(i, j int) bool {
+	if e[i].at != e[j].at {
+		return e[i].at < e[j].at
+	}
+	return e[i].seq < e[j].seq
+}
+func (e simEvents) Swap(i, j int)       { e[i], e[j] = e[j], e[i] }
+func (e *simEvents) Push(x interface{}) { *e = append(*e, x.(*simEvent)) }
+func (e *simEvents) Pop() interface{} {
+	old := *e
+	ev := old[len(old)-1]
+	*e = old[:len(old)-1]
+	return ev
+}
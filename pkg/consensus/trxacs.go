@@ -1,9 +1,11 @@
 package consensus
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/rumsystem/quorum/internal/pkg/logging"
+	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
 	quorumpb "github.com/rumsystem/quorum/pkg/pb"
 	"google.golang.org/protobuf/proto"
 )
@@ -15,8 +17,15 @@ type TrxACS struct {
 	bft          *TrxBft
 	Epoch        uint64
 	rbcInstances map[string]*TrxRBC
-	rbcOutput    map[string]bool
+	bbaInstances map[string]*BBA
+	rbcOutput    map[string]bool //rbc finished
+	bbaOutput    map[string]bool //bba decided value
 	rbcResults   map[string][]byte
+	decShares    map[string]map[int]*quorumpb.DecShare //proposer -> share index -> decryption share not verified yet
+	verified     map[string]map[int][]byte             //proposer -> share index -> verified decryption share
+	shareSent    map[string]bool
+	decided      bool //all bba decided, wait for encrypted proposals decrypted
+	done         bool
 }
 
 func NewTrxACS(cfg Config, bft *TrxBft, epoch uint64) *TrxACS {
@@ -27,12 +36,19 @@ func NewTrxACS(cfg Config, bft *TrxBft, epoch uint64) *TrxACS {
 		bft:          bft,
 		Epoch:        epoch,
 		rbcInstances: make(map[string]*TrxRBC),
+		bbaInstances: make(map[string]*BBA),
 		rbcOutput:    make(map[string]bool),
+		bbaOutput:    make(map[string]bool),
 		rbcResults:   make(map[string][]byte),
+		decShares:    make(map[string]map[int]*quorumpb.DecShare),
+		verified:     make(map[string]map[int][]byte),
+		shareSent:    make(map[string]bool),
 	}
 
+	groupId := bft.producer.groupId
 	for _, rbcInstPubkey := range cfg.Nodes {
-		acs.rbcInstances[rbcInstPubkey], _ = NewTrxRBC(cfg, acs, bft.producer.groupId, cfg.MyPubkey, rbcInstPubkey)
+		acs.rbcInstances[rbcInstPubkey], _ = NewTrxRBC(cfg, acs, groupId, cfg.MyPubkey, rbcInstPubkey)
+		acs.bbaInstances[rbcInstPubkey] = NewBBA(cfg, acs, groupId, rbcInstPubkey)
 	}
 
 	return acs
@@ -50,27 +66,161 @@ func (a *TrxACS) InputValue(val []byte) error {
 	return rbc.InputValue(val)
 }
 
-// rbc for proposerIs finished
+// rbc for proposerIs finished, vote 1 in bba of the proposer
 func (a *TrxACS) RbcDone(proposerPubkey string) {
 	trx_acs_log.Infof("RbcDone called, Epoch <%d>", a.Epoch)
+	if a.done || a.rbcOutput[proposerPubkey] {
+		return
+	}
+
 	a.rbcOutput[proposerPubkey] = true
-	if len(a.rbcOutput) == a.N-a.f {
-		trx_acs_log.Debugf("enough RBC done for consensus <%d>", a.N-a.f)
-		//this only works when producer nodes equals to 3!!
-		//TBD:should add BBA here
-		//1. set all NOT finished RBC to false
-		//2. start BBA process till finished
-		for rbcInst, _ := range a.rbcOutput {
-			//load all valid rbc results
-			a.rbcResults[rbcInst] = a.rbcInstances[rbcInst].Output()
-		}
-
-		//call hbb to get result
-		a.bft.AcsDone(a.Epoch, a.rbcResults)
-	} else {
-		trx_acs_log.Debugf("Wait for enough RBC done")
+	if bba, ok := a.bbaInstances[proposerPubkey]; ok && !bba.HasInput() {
+		if err := bba.InputValue(true); err != nil {
+			trx_acs_log.Warnf("input to bba <%s> failed: %s", proposerPubkey, err)
+		}
+	}
+	a.tryFinish()
+}
+
+// bba for proposer decided, after enough proposals are accepted vote 0 in all bba not voted yet,
+// so rbc of faulty proposers will not block the epoch
+func (a *TrxACS) BbaDone(proposerPubkey string, value bool) {
+	trx_acs_log.Infof("BbaDone called, Epoch <%d>, proposer <%s> value <%v>", a.Epoch, proposerPubkey, value)
+	if a.done {
+		return
+	}
+
+	a.bbaOutput[proposerPubkey] = value
+	if value && a.isQuorum(a.acceptedProposers()) {
+		for _, pubkey := range a.Nodes {
+			bba := a.bbaInstances[pubkey]
+			if bba.HasInput() {
+				continue
+			}
+			if err := bba.InputValue(false); err != nil {
+				trx_acs_log.Warnf("input to bba <%s> failed: %s", pubkey, err)
+			}
+		}
+	}
+	a.tryFinish()
+}
+
+func (a *TrxACS) acceptedProposers() map[string]bool {
+	accepted := make(map[string]bool)
+	for pubkey, value := range a.bbaOutput {
+		if value {
+			accepted[pubkey] = true
+		}
+	}
+	return accepted
+}
+
+// epoch finishes after all bba decided, rbc of all accepted proposers finished and their proposals decrypted
+func (a *TrxACS) tryFinish() {
+	if a.decided || len(a.bbaOutput) < len(a.bbaInstances) {
+		return
+	}
+
+	accepted := a.acceptedProposers()
+	for pubkey := range accepted {
+		if !a.rbcOutput[pubkey] {
+			trx_acs_log.Debugf("wait rbc of accepted proposer <%s>", pubkey)
+			return
+		}
+	}
+
+	trx_acs_log.Debugf("all bba decided, <%d> proposals accepted", len(accepted))
+	a.decided = true
+	for pubkey := range accepted {
+		a.rbcResults[pubkey] = a.rbcInstances[pubkey].Output()
+	}
+	a.tryDecrypt()
+}
+
+// proposals encrypted to the threshold key are decrypted only after the agreement, by decryption shares
+// of faultyNodes + 1 producers. an invalid ciphertext is taken as an empty proposal by all producers
+func (a *TrxACS) tryDecrypt() {
+	if a.done || !a.decided {
+		return
+	}
+
+	pending := 0
+	for _, proposer := range a.Nodes {
+		value, ok := a.rbcResults[proposer]
+		if !ok || !isEncryptedProposal(value) {
+			continue
+		}
+		ciphertext := value[len(ENCRYPTED_PROPOSAL_PREFIX):]
+		label := proposalLabel(a.bft.groupId, a.Epoch, proposer)
+		//the ciphertext is checked once, verified is created after it
+		if _, ok := a.verified[proposer]; !ok {
+			if _, _, err := localcrypto.VerifyCiphertext(ciphertext, label); err != nil {
+				trx_acs_log.Warnf("invalid encrypted proposal from <%s>: %s", proposer, err)
+				a.rbcResults[proposer] = []byte("EMPTY")
+				continue
+			}
+			a.verified[proposer] = make(map[int][]byte)
+		}
+		key := a.bft.dkg.ReadyKey()
+		if key == nil {
+			trx_acs_log.Warnf("threshold key to decrypt proposal from <%s> is not ready, wait for it", proposer)
+			return
+		}
+
+		if !a.shareSent[proposer] && key.Share != nil {
+			a.shareSent[proposer] = true
+			if err := a.sendDecShare(key, proposer, ciphertext, label); err != nil {
+				trx_acs_log.Warnf("send decryption share of <%s> failed: %s", proposer, err)
+			}
+		}
+
+		for index, ds := range a.decShares[proposer] {
+			//any Threshold shares decrypt the proposal, others are not verified
+			if len(a.verified[proposer]) >= key.Threshold {
+				break
+			}
+			if err := key.VerifyDecryptShare(index, ciphertext, label, ds.Share, ds.Proof); err != nil {
+				trx_acs_log.Warnf("invalid decryption share from <%s>: %s", ds.SenderPubkey, err)
+			} else {
+				a.verified[proposer][index] = ds.Share
+			}
+			delete(a.decShares[proposer], index)
+		}
+		if len(a.verified[proposer]) < key.Threshold {
+			pending++
+			continue
+		}
+
+		plain, err := key.Combine(ciphertext, label, a.verified[proposer])
+		if err != nil {
+			trx_acs_log.Warnf("decrypt proposal from <%s> failed: %s", proposer, err)
+			plain = []byte("EMPTY")
+		}
+		a.rbcResults[proposer] = plain
+	}
+
+	if pending > 0 {
+		trx_acs_log.Debugf("wait decryption shares of <%d> proposals", pending)
 		return
 	}
+
+	a.done = true
+	//call hbb to get result
+	a.bft.AcsDone(a.Epoch, a.rbcResults)
+}
+
+func (a *TrxACS) sendDecShare(key *localcrypto.ThresholdKey, proposer string, ciphertext []byte, label []byte) error {
+	share, proof, err := key.DecryptShare(ciphertext, label)
+	if err != nil {
+		return err
+	}
+	decShare := &quorumpb.DecShare{
+		ProposerPubkey: proposer,
+		SenderPubkey:   a.MyPubkey,
+		Share:          share,
+		Proof:          proof,
+	}
+	return SendHBDecMsg(a.bft.broadcaster, a.bft.groupId, decShare, a.Epoch)
 }
 
 func (a *TrxACS) HandleMessage(hbmsg *quorumpb.HBMsgv1) error {
@@ -81,6 +231,8 @@ func (a *TrxACS) HandleMessage(hbmsg *quorumpb.HBMsgv1) error {
 		return a.handleRbc(hbmsg.Payload)
 	case quorumpb.HBMsgPayloadType_BBA:
 		return a.handleBba(hbmsg.Payload)
+	case quorumpb.HBMsgPayloadType_DEC:
+		return a.handleDec(hbmsg.Payload)
 	default:
 		return fmt.Errorf("received unknown type BlockMsg <%s>", hbmsg.PayloadType.String())
 	}
@@ -150,7 +302,125 @@ func (a *TrxACS) handleRbc(payload []byte) error {
 }
 
 func (a *TrxACS) handleBba(payload []byte) error {
-	//TBD
-	//Implement BBA
+	trx_acs_log.Debugf("handleBba called, Epoch <%d>", a.Epoch)
+
+	bbaMsg := &quorumpb.BBAMsg{}
+	err := proto.Unmarshal(payload, bbaMsg)
+	if err != nil {
+		return err
+	}
+
+	switch bbaMsg.Type {
+	case quorumpb.BBAMsgType_BVAL:
+		bval := &quorumpb.Bval{}
+		err := proto.Unmarshal(bbaMsg.Payload, bval)
+		if err != nil {
+			return err
+		}
+		bba, err := a.verifyBbaMsg(bval, bval.ProposerId, bval.SenderPubkey, bval.AcsEpoch)
+		if err != nil {
+			return fmt.Errorf("invalid BVAL: %s", err)
+		}
+		return bba.handleBvalMsg(bval)
+	case quorumpb.BBAMsgType_AUX:
+		aux := &quorumpb.Aux{}
+		err := proto.Unmarshal(bbaMsg.Payload, aux)
+		if err != nil {
+			return err
+		}
+		bba, err := a.verifyBbaMsg(aux, aux.ProposerId, aux.SenderPubkey, aux.AcsEpoch)
+		if err != nil {
+			return fmt.Errorf("invalid AUX: %s", err)
+		}
+		return bba.handleAuxMsg(aux)
+	case quorumpb.BBAMsgType_CONF:
+		conf := &quorumpb.Conf{}
+		err := proto.Unmarshal(bbaMsg.Payload, conf)
+		if err != nil {
+			return err
+		}
+		bba, err := a.verifyBbaMsg(conf, conf.ProposerId, conf.SenderPubkey, conf.AcsEpoch)
+		if err != nil {
+			return fmt.Errorf("invalid CONF: %s", err)
+		}
+		return bba.handleConfMsg(conf)
+	case quorumpb.BBAMsgType_COIN:
+		coin := &quorumpb.CoinShare{}
+		err := proto.Unmarshal(bbaMsg.Payload, coin)
+		if err != nil {
+			return err
+		}
+		bba, err := a.verifyBbaMsg(coin, coin.ProposerId, coin.SenderPubkey, coin.AcsEpoch)
+		if err != nil {
+			return fmt.Errorf("invalid COIN: %s", err)
+		}
+		return bba.handleCoinMsg(coin)
+	default:
+		return fmt.Errorf("received unknown bba message, type (%s)", bbaMsg.Type)
+	}
+}
+
+// verifyBbaMsg checks a bba message is signed by a producer for this epoch, return the bba instance to handle it
+func (a *TrxACS) verifyBbaMsg(msg proto.Message, proposer, sender string, epoch uint64) (*BBA, error) {
+	if !a.isNode(sender) {
+		return nil, fmt.Errorf("from <%s> which is not a producer", sender)
+	}
+	if epoch != a.Epoch {
+		return nil, fmt.Errorf("from <%s> of epoch <%d>, expect <%d>", sender, epoch, a.Epoch)
+	}
+	bba, ok := a.bbaInstances[proposer]
+	if !ok {
+		return nil, fmt.Errorf("could not find bba instance for <%s>", proposer)
+	}
+	if err := VerifyBBASign(a.bft.ks, msg); err != nil {
+		return nil, err
+	}
+	return bba, nil
+}
+
+// decryption shares may arrive before the agreement is done, they are verified after proposals are known
+func (a *TrxACS) handleDec(payload []byte) error {
+	trx_acs_log.Debugf("handleDec called, Epoch <%d>", a.Epoch)
+
+	decShare := &quorumpb.DecShare{}
+	err := proto.Unmarshal(payload, decShare)
+	if err != nil {
+		return err
+	}
+	index := a.bft.dkg.indexOf(decShare.SenderPubkey)
+	if index < 0 {
+		return fmt.Errorf("decryption share from <%s> which is not a producer", decShare.SenderPubkey)
+	}
+	if _, ok := a.rbcInstances[decShare.ProposerPubkey]; !ok {
+		return fmt.Errorf("decryption share for unknown proposer <%s>", decShare.ProposerPubkey)
+	}
+
+	if _, ok := a.decShares[decShare.ProposerPubkey]; !ok {
+		a.decShares[decShare.ProposerPubkey] = make(map[int]*quorumpb.DecShare)
+	}
+	if _, ok := a.verified[decShare.ProposerPubkey][index]; ok {
+		return nil
+	}
+	a.decShares[decShare.ProposerPubkey][index] = decShare
+	a.tryDecrypt()
 	return nil
 }
+
+func isEncryptedProposal(value []byte) bool {
+	return bytes.HasPrefix(value, []byte(ENCRYPTED_PROPOSAL_PREFIX))
+}
+
+// proposalLabel binds an encrypted proposal to its group, epoch and proposer, a ciphertext copied
+// into the proposal of another producer or epoch is invalid, so it can not be decrypted before the agreement
+func proposalLabel(groupId string, epoch uint64, proposer string) []byte {
+	return []byte(fmt.Sprintf("%s:%d:%s", groupId, epoch, proposer))
+}
+
+func (a *TrxACS) isNode(pubkey string) bool {
+	for _, nodePubkey := range a.Nodes {
+		if nodePubkey == pubkey {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package consensus
+
+import (
+	"bytes"
+	"testing"
+
+	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAcsRejectReplayedCiphertext(t *testing.T) {
+	net := newBbaNet(t, 4)
+	victim, attacker := net.pubkeys[0], net.pubkeys[1]
+	acs := net.acs[2]
+	key := acs.bft.dkg.ReadyKey()
+
+	ciphertext, err := localcrypto.ThresholdEncrypt(key.GroupPubkey, []byte("proposal"), proposalLabel("bbagroup", acs.Epoch, victim))
+	if err != nil {
+		t.Fatalf("ThresholdEncrypt failed: %s", err)
+	}
+	value := append([]byte(ENCRYPTED_PROPOSAL_PREFIX), ciphertext...)
+
+	//the attacker proposes a copy of the encrypted proposal of the victim, to learn it before the agreement
+	acs.decided = true
+	acs.rbcResults[victim] = value
+	acs.rbcResults[attacker] = append([]byte{}, value...)
+	acs.tryDecrypt()
+
+	if !bytes.Equal(acs.rbcResults[attacker], []byte("EMPTY")) {
+		t.Fatal("replayed ciphertext should be taken as an empty proposal")
+	}
+	if acs.shareSent[attacker] || !acs.shareSent[victim] {
+		t.Fatalf("decryption share should be sent for the victim only, sent <%v>", acs.shareSent)
+	}
+	for _, hbmsg := range net.queue {
+		if hbmsg.PayloadType != quorumpb.HBMsgPayloadType_DEC {
+			continue
+		}
+		decShare := &quorumpb.DecShare{}
+		if err := proto.Unmarshal(hbmsg.Payload, decShare); err != nil {
+			t.Fatalf("decode decryption share failed: %s", err)
+		}
+		if decShare.ProposerPubkey != victim {
+			t.Fatalf("decryption share sent for <%s>", decShare.ProposerPubkey)
+		}
+	}
+	if acs.done {
+		t.Fatal("acs should wait for decryption shares of the victim")
+	}
+}
@@ -3,10 +3,10 @@ package consensus
 import (
 	"errors"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/golang/protobuf/proto"
-	"github.com/rumsystem/quorum/internal/pkg/conn"
 	"github.com/rumsystem/quorum/internal/pkg/logging"
 	"github.com/rumsystem/quorum/internal/pkg/nodectx"
 	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
@@ -16,14 +16,17 @@ import (
 
 var trx_bft_log = logging.Logger("tbft")
 
-var DEFAULT_PROPOSE_PULSE = 1 * 1000       // 1s
+var DEFAULT_PROPOSE_PULSE = 1 * 1000       // 1s, default base interval of epoch pacing
 var MAXIMUM_TRX_BUNDLE_LENGTH = 900 * 1024 //900Kib
 var TRX_DATA_LENGTH = 300 * 1024           //300Kib
+var MAX_FUTURE_HBMSGS = 4096               //maximum messages of future epochs kept before their acs start
+var MAX_FUTURE_EPOCHS uint64 = 8           //messages of this many epochs after the running one are kept, so a producer fell behind can catch up
+
+const ENCRYPTED_PROPOSAL_PREFIX = "TPKE:" //proposal encrypted to the threshold key of producers
 
 type ProposeTask struct {
-	Epoch          uint64
-	ProposedData   []byte
-	DelayStartTime int
+	Epoch        uint64
+	ProposedData []byte //built when the epoch starts, see waitEpoch
 }
 
 type ProposeStatus uint
@@ -36,12 +39,23 @@ const (
 
 type TrxBft struct {
 	Config
-	groupId  string
-	producer *MolassesProducer
-	CurrTask *ProposeTask
-	acsInsts *TrxACS
-	txBuffer *TrxBuffer
-	taskq    chan *ProposeTask
+	groupId    string
+	producer   *MolassesProducer
+	CurrTask   *ProposeTask
+	acsInsts   *TrxACS
+	prevAcs    *TrxACS             //acs of the last epoch, keeps running bba for producers not finished yet
+	futureMsgs []*quorumpb.HBMsgv1 //messages of future epochs, see MAX_FUTURE_EPOCHS
+	acsMu      sync.Mutex
+	txBuffer   *TrxBuffer
+	dkg        *DKG
+	taskq      chan *ProposeTask
+	wake       chan struct{} //wakes up the task waiting for its epoch
+
+	futureSenders map[uint64]map[string]bool //epoch -> producers signed messages of the future epoch, see epochStarters
+
+	ks          localcrypto.Keystore //signs consensus messages and blocks
+	broadcaster Broadcaster          //sends consensus messages and blocks to other nodes
+	manual      bool                 //epochs are started by the caller instead of the task queue, see startAcs
 
 	taskdone   chan struct{}
 	stopnotify chan struct{}
@@ -50,22 +64,53 @@ type TrxBft struct {
 }
 
 func NewTrxBft(cfg Config, producer *MolassesProducer) *TrxBft {
+	return newTrxBft(cfg, producer, localcrypto.GetKeystore(), connBroadcaster{})
+}
+
+func newTrxBft(cfg Config, producer *MolassesProducer, ks localcrypto.Keystore, broadcaster Broadcaster) *TrxBft {
 	trx_bft_log.Debugf("<%s> NewTrxBft called", producer.groupId)
-	return &TrxBft{
-		Config:     cfg,
-		groupId:    producer.groupId,
-		producer:   producer,
-		txBuffer:   NewTrxBuffer(producer.groupId),
-		taskq:      make(chan *ProposeTask),
-		taskdone:   make(chan struct{}),
-		stopnotify: make(chan struct{}),
-		status:     IDLE,
+	bft := &TrxBft{
+		Config:        cfg,
+		groupId:       producer.groupId,
+		producer:      producer,
+		txBuffer:      NewTrxBuffer(producer.groupId),
+		dkg:           NewDKG(cfg, producer.groupId, producer.nodename, ks, broadcaster),
+		taskq:         make(chan *ProposeTask),
+		wake:          make(chan struct{}, 1),
+		ks:            ks,
+		futureSenders: make(map[uint64]map[string]bool),
+		broadcaster:   broadcaster,
+		taskdone:      make(chan struct{}),
+		stopnotify:    make(chan struct{}),
+		status:        IDLE,
+	}
+	bft.dkg.onReady = bft.thresholdKeyReady
+	return bft
+}
+
+// thresholdKeyReady resumes acs waiting for the threshold key to decrypt proposals or to get coins
+func (bft *TrxBft) thresholdKeyReady() {
+	bft.acsMu.Lock()
+	defer bft.acsMu.Unlock()
+	for _, acs := range []*TrxACS{bft.prevAcs, bft.acsInsts} {
+		if acs == nil {
+			continue
+		}
+		for _, bba := range acs.bbaInstances {
+			if err := bba.processRound(); err != nil {
+				trx_bft_log.Warnf("<%s> bba of epoch <%d> failed: %s", bft.groupId, acs.Epoch, err)
+			}
+		}
+		acs.tryDecrypt()
 	}
 }
 
 func (bft *TrxBft) StartPropose() {
 	trx_bft_log.Debugf("<%s> StartPropose called", bft.groupId)
 
+	//generate threshold key to encrypt proposals and make coins of bba, epochs can not finish before it is ready
+	bft.dkg.Start()
+
 	//start taskq
 	go func() {
 		for task := range bft.taskq {
@@ -103,26 +148,99 @@ func (bft *TrxBft) addTask(task *ProposeTask) {
 
 func (bft *TrxBft) runTask(task *ProposeTask) error {
 	trx_bft_log.Debugf("<%s> runTask called, epoch <%d>", bft.groupId, task.Epoch)
+	bft.acsMu.Lock()
+	bft.CurrTask = task
+	bft.acsMu.Unlock()
+
+	cancel := make(chan struct{})
 	go func() {
+		//wait till the epoch should start, an idle group may wait here for a long time
+		if !bft.waitEpoch(task, cancel) {
+			trx_bft_log.Debugf("<%s> task of epoch <%d> cancelled before started", bft.groupId, task.Epoch)
+			return
+		}
+
 		//create new acs and try propose something
-		trx_bft_log.Debugf("<%s> wait <%d> ms", bft.groupId, task.DelayStartTime)
-		time.Sleep(time.Duration(task.DelayStartTime) * time.Millisecond)
+		data, err := bft.buildProposal(task.Epoch)
+		if err != nil {
+			trx_bft_log.Warnf("<%s> build proposal failed <%s>, propose EMPTY", bft.groupId, err.Error())
+			data = []byte("EMPTY")
+		}
 
-		bft.CurrTask = task
-		bft.acsInsts = NewTrxACS(bft.Config, bft, task.Epoch)
-		bft.acsInsts.InputValue(task.ProposedData)
+		bft.acsMu.Lock()
+		defer bft.acsMu.Unlock()
+		select {
+		case <-cancel:
+			return
+		default:
+		}
+		task.ProposedData = data
+		bft.startAcs(task)
 	}()
 
 	//wait here
 	<-bft.taskdone
+	close(cancel)
 	return nil
 }
 
+// startAcs creates acs of the task with its proposed data, and handles messages of the epoch arrived
+// before it started, caller must hold acsMu
+func (bft *TrxBft) startAcs(task *ProposeTask) {
+	bft.prevAcs = bft.acsInsts
+	bft.acsInsts = NewTrxACS(bft.Config, bft, task.Epoch)
+	if err := bft.acsInsts.InputValue(task.ProposedData); err != nil {
+		trx_bft_log.Warnf("<%s> input proposal of epoch <%d> failed <%s>", bft.groupId, task.Epoch, err.Error())
+	}
+
+	//handle messages arrived before the acs started, keep messages of later epochs
+	for epoch := range bft.futureSenders {
+		if epoch <= task.Epoch {
+			delete(bft.futureSenders, epoch)
+		}
+	}
+	msgs := bft.futureMsgs
+	bft.futureMsgs = nil
+	for _, msg := range msgs {
+		if msg.Epoch > task.Epoch {
+			bft.futureMsgs = append(bft.futureMsgs, msg)
+		}
+		if msg.Epoch != task.Epoch {
+			continue
+		}
+		if err := bft.acsInsts.HandleMessage(msg); err != nil {
+			trx_bft_log.Debugf("<%s> handle cached message failed: %s", bft.groupId, err)
+		}
+	}
+}
+
 func (bft *TrxBft) NewProposeTask() (*ProposeTask, error) {
 	trx_bft_log.Debugf("<%s> NewProposeTask called", bft.groupId)
 
+	currEpoch := bft.producer.cIface.GetCurrEpoch()
+	proposedEpoch := currEpoch + 1
+
+	task := &ProposeTask{
+		Epoch: proposedEpoch,
+	}
+
+	return task, nil
+}
+
+// buildProposal selects trxs from buffer and bundles them as the proposal of this producer for epoch
+func (bft *TrxBft) buildProposal(epoch uint64) ([]byte, error) {
+	trx_bft_log.Debugf("<%s> buildProposal called", bft.groupId)
+
+	//policy is read for each epoch, so changes from group owner take effect immediately
+	policy := GetMempoolPolicy(bft.groupId, bft.producer.nodename)
+
+	//drop expired and stale trxs from buffer
+	if err := bft.txBuffer.Evict(policy); err != nil {
+		return nil, err
+	}
+
 	//select some trxs from buffer
-	trxs, err := bft.txBuffer.GetNRandTrx(bft.BatchSize)
+	trxs, err := bft.txBuffer.SelectTrxs(policy, MAXIMUM_TRX_BUNDLE_LENGTH)
 	if err != nil {
 		return nil, err
 	}
@@ -135,7 +253,7 @@ func (bft *TrxBft) NewProposeTask() (*ProposeTask, error) {
 
 	var datab []byte
 	for {
-		trxBundle := &quorumpb.HBTrxBundle{}
+		trxBundle := &quorumpb.HBTrxBundle{TimeStamp: time.Now().UnixNano()}
 		trxBundle.Trxs = append(trxBundle.Trxs, trxs...)
 
 		datab, err = proto.Marshal(trxBundle)
@@ -164,21 +282,24 @@ func (bft *TrxBft) NewProposeTask() (*ProposeTask, error) {
 		}
 	}
 
-	currEpoch := bft.producer.cIface.GetCurrEpoch()
-	proposedEpoch := currEpoch + 1
-
-	task := &ProposeTask{
-		Epoch:          proposedEpoch,
-		ProposedData:   datab,
-		DelayStartTime: DEFAULT_PROPOSE_PULSE,
+	//encrypt proposal, so other producers can not censor trxs in it before the agreement
+	if key := bft.dkg.ReadyKey(); key != nil {
+		label := proposalLabel(bft.groupId, epoch, bft.MyPubkey)
+		ciphertext, err := localcrypto.ThresholdEncrypt(key.GroupPubkey, datab, label)
+		if err != nil {
+			trx_bft_log.Errorf("<%s> encrypt proposal error <%s>, propose in plain text", bft.groupId, err.Error())
+		} else {
+			datab = append([]byte(ENCRYPTED_PROPOSAL_PREFIX), ciphertext...)
+		}
 	}
 
-	return task, nil
+	return datab, nil
 }
 
 func (bft *TrxBft) StopPropose() {
 	trx_bft_log.Debugf("<%s> StopPropose called", bft.groupId)
 	bft.status = CLOSED
+	bft.dkg.Stop()
 	safeCloseTaskQ(bft.taskq)
 	safeClose(bft.taskdone)
 	if bft.stopnotify != nil {
@@ -229,36 +350,81 @@ func (bft *TrxBft) AddTrx(tx *quorumpb.Trx) error {
 	}
 
 	bft.txBuffer.Push(tx)
-	//for debug only added by cuicat
-	//list all trxs in buffer
-	trxs, err := bft.txBuffer.GetAllTrxInBuffer()
+	bft.wakeUp()
+
+	//keep buffer in size, expired and stale trxs are removed when a proposal is built
+	evicted, err := bft.txBuffer.EvictOverflow(GetMempoolPolicy(bft.groupId, bft.producer.nodename))
 	if err != nil {
 		return err
 	}
 
-	trx_bft_log.Debugf("<%s> all trx in buffer", bft.groupId)
-	for _, trx := range trxs {
-		trx_bft_log.Debugf("<%s> TrxId <%s>", bft.groupId, trx.TrxId)
+	for _, trxId := range evicted {
+		trx_bft_log.Debugf("<%s> TrxId <%s> evicted", bft.groupId, trxId)
 	}
 
 	return nil
 }
 
+func (bft *TrxBft) isTrxPackaged(trxId string) bool {
+	if bft.producer.seenTrxs.IsSeen(trxId) {
+		return true
+	}
+	isExist, _ := nodectx.GetNodeCtx().GetChainStorage().IsTrxExist(bft.groupId, trxId, bft.producer.nodename)
+	return isExist
+}
+
 func (bft *TrxBft) HandleMessage(hbmsg *quorumpb.HBMsgv1) error {
 	trx_bft_log.Debugf("<%s> HandleMessage called, Epoch <%d>", bft.groupId, hbmsg.Epoch)
 
-	if bft.acsInsts != nil && hbmsg.Epoch < bft.acsInsts.Epoch {
-		trx_bft_log.Warnf("message from old epoch, ignore")
-		return nil
+	if hbmsg.PayloadType == quorumpb.HBMsgPayloadType_DKG {
+		return bft.dkg.HandleMessage(hbmsg.Payload)
+	}
+
+	bft.acsMu.Lock()
+	defer bft.acsMu.Unlock()
+
+	acs := bft.acsInsts
+	var nextEpoch uint64
+	if acs != nil {
+		nextEpoch = acs.Epoch + 1
+	} else {
+		nextEpoch = bft.producer.cIface.GetCurrEpoch() + 1
 	}
 
-	//handle msg
-	return bft.acsInsts.HandleMessage(hbmsg)
+	switch {
+	case acs != nil && hbmsg.Epoch == acs.Epoch:
+		return acs.HandleMessage(hbmsg)
+	case hbmsg.Epoch >= nextEpoch && hbmsg.Epoch < nextEpoch+MAX_FUTURE_EPOCHS:
+		//acs of the epoch is not started yet, keep it, other producers may be some epochs ahead of us
+		sender, err := bft.hbMsgSender(hbmsg)
+		if err != nil {
+			return err
+		}
+		if len(bft.futureMsgs) < MAX_FUTURE_HBMSGS {
+			bft.futureMsgs = append(bft.futureMsgs, hbmsg)
+		}
+		//other producers may have started the epoch, check if enough of them did
+		if sender != "" && sender != bft.MyPubkey && !bft.futureSenders[hbmsg.Epoch][sender] {
+			if bft.futureSenders[hbmsg.Epoch] == nil {
+				bft.futureSenders[hbmsg.Epoch] = make(map[string]bool)
+			}
+			bft.futureSenders[hbmsg.Epoch][sender] = true
+			bft.wakeUp()
+		}
+		return nil
+	case bft.prevAcs != nil && hbmsg.Epoch == bft.prevAcs.Epoch && hbmsg.PayloadType == quorumpb.HBMsgPayloadType_BBA:
+		//other producers may still need our bba messages to decide
+		return bft.prevAcs.HandleMessage(hbmsg)
+	default:
+		trx_bft_log.Warnf("message from epoch <%d> not handled, ignore", hbmsg.Epoch)
+		return nil
+	}
 }
 
 func (bft *TrxBft) AcsDone(epoch uint64, result map[string][]byte) {
 	trx_bft_log.Debugf("<%s> AcsDone called, Epoch <%d>", bft.producer.groupId, epoch)
 	trxs := make(map[string]*quorumpb.Trx) //trx_id
+	timestamps := make(map[string]int64)   //proposer -> proposer time
 
 	//decode trxs
 	for key, value := range result {
@@ -270,10 +436,14 @@ func (bft *TrxBft) AcsDone(epoch uint64, result map[string][]byte) {
 		trxBundle := &quorumpb.HBTrxBundle{}
 		err := proto.Unmarshal(value, trxBundle)
 		if err != nil {
+			//all producers got the same value, so all of them skip it and go on to the next epoch
 			trx_bft_log.Warningf("decode trxs failed for rbc inst <%s> with error <%s>", key, err.Error())
-			return
+			continue
 		}
 
+		if trxBundle.TimeStamp > 0 {
+			timestamps[key] = trxBundle.TimeStamp
+		}
 		for _, trx := range trxBundle.Trxs {
 			if _, ok := trxs[trx.TrxId]; !ok {
 				trxs[trx.TrxId] = trx
@@ -281,6 +451,27 @@ func (bft *TrxBft) AcsDone(epoch uint64, result map[string][]byte) {
 		}
 	}
 
+	//remove trxs expired at the time agreed by producers, the seen trx index is bounded and trxs on chain may be pruned,
+	//so an old trx can not be found by them. all producers got the same result, so they drop the same trxs
+	if agreedTime := medianTimeStamp(timestamps, bft.Weights); agreedTime > 0 {
+		for trxId, trx := range trxs {
+			if rumchaindata.GetTrxExpiredAt(trx) < agreedTime {
+				trx_bft_log.Debugf("<%s> trx <%s> expired, skip", bft.producer.groupId, trxId)
+				delete(trxs, trxId)
+				bft.txBuffer.Delete(trxId)
+			}
+		}
+	}
+
+	//remove trxs already packaged in previous epochs
+	for trxId := range trxs {
+		if bft.isTrxPackaged(trxId) {
+			trx_bft_log.Debugf("<%s> trx <%s> already packaged, skip", bft.producer.groupId, trxId)
+			delete(trxs, trxId)
+			bft.txBuffer.Delete(trxId)
+		}
+	}
+
 	//try package trxs with a new block
 	if len(trxs) != 0 {
 		//Try build block and broadcast it
@@ -290,7 +481,8 @@ func (bft *TrxBft) AcsDone(epoch uint64, result map[string][]byte) {
 			return
 		}
 		//remove packaged trxs from buffer
-		for trxId := range trxs {
+		for trxId, trx := range trxs {
+			bft.producer.seenTrxs.Add(trxId, rumchaindata.GetTrxExpiredAt(trx))
 			err := bft.txBuffer.Delete(trxId)
 			trx_bft_log.Debugf("<%s> remove packaged trx <%s>", bft.producer.groupId, trxId)
 			if err != nil {
@@ -320,6 +512,12 @@ func (bft *TrxBft) AcsDone(epoch uint64, result map[string][]byte) {
 	bft.producer.cIface.SaveChainInfoToDb()
 	trx_bft_log.Debugf("<%s> ChainInfo updated", bft.producer.groupId)
 
+	bft.producer.cIface.TryCreateSnapshot()
+
+	if bft.manual {
+		return
+	}
+
 	//finish current task
 	bft.taskdone <- struct{}{}
 
@@ -364,9 +562,7 @@ func (bft *TrxBft) buildBlock(epoch uint64, trxs map[string]*quorumpb.Trx) error
 		return err
 	} else {
 		trx_bft_log.Debugf("<%s> start build block with parent <%d> ", bft.producer.groupId, parent.BlockId)
-		ks := localcrypto.GetKeystore()
-
-		newBlock, err := rumchaindata.CreateBlockByEthKey(parent, epoch, trxToPackage, false, bft.producer.grpItem.UserSignPubkey, ks, "", bft.producer.nodename)
+		newBlock, err := rumchaindata.CreateBlockByEthKey(parent, epoch, trxToPackage, false, bft.producer.grpItem.UserSignPubkey, bft.ks, "", bft.producer.nodename)
 
 		if err != nil {
 			trx_bft_log.Debugf("<%s> build block failed <%s>", bft.producer.groupId, err.Error())
@@ -389,11 +585,7 @@ func (bft *TrxBft) buildBlock(epoch uint64, trxs map[string]*quorumpb.Trx) error
 
 		//broadcast it
 		trx_bft_log.Debugf("<%s> broadcast block just built to user channel", bft.producer.groupId)
-		connMgr, err := conn.GetConn().GetConnMgr(bft.producer.groupId)
-		if err != nil {
-			return err
-		}
-		err = connMgr.BroadcastBlock(newBlock)
+		err = bft.broadcaster.BroadcastBlock(bft.producer.groupId, newBlock)
 		if err != nil {
 			trx_acs_log.Debugf("<%s> Broadcast failed <%s>", bft.producer.groupId, err.Error())
 		}
@@ -454,3 +646,25 @@ func (bft *TrxBft) sortTrx(trxs map[string]*quorumpb.Trx) []*quorumpb.Trx {
 
 	return result
 }
+
+// medianTimeStamp return the median of proposer times (proposer -> time) in an acs result weighted by stake, 0 if none.
+// agreed proposals are from a quorum, faulty producers hold less than half of their stake, so the median is
+// bounded by times of honest ones
+func medianTimeStamp(timestamps map[string]int64, weights map[string]int64) int64 {
+	var proposers []string
+	var total int64
+	for proposer := range timestamps {
+		proposers = append(proposers, proposer)
+		total += weights[proposer]
+	}
+	sort.Slice(proposers, func(i, j int) bool { return timestamps[proposers[i]] < timestamps[proposers[j]] })
+
+	var stake int64
+	for _, proposer := range proposers {
+		stake += weights[proposer]
+		if stake*2 > total {
+			return timestamps[proposer]
+		}
+	}
+	return 0
+}
@@ -1,39 +1,174 @@
 package consensus
 
 import (
-	"math/rand"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/rumsystem/quorum/internal/pkg/metric"
 	"github.com/rumsystem/quorum/internal/pkg/nodectx"
+	rumchaindata "github.com/rumsystem/quorum/pkg/data"
 	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
 )
 
-// just a simple wrap of HBB Trx Buffer DB
+var DEFAULT_BATCH_SIZE = 20         //maximum trxs proposed in one epoch if not set by group owner
+var DEFAULT_MAX_BUFFER_SIZE = 10000 //maximum trxs kept in trx buffer if not set by group owner
+
+// MempoolPolicy controls how trxs are kept in trx buffer and selected to propose,
+// group owner can change it by SET_MEMPOOL_POLICY chain config
+type MempoolPolicy struct {
+	BatchSize        int           // maximum trxs proposed in one epoch
+	MaxTrxsPerSender int           // maximum trxs of one sender in a proposal, 0 for no limit
+	MaxBufferSize    int           // maximum trxs kept in buffer, trxs of the sender with most trxs are evicted first
+	MaxTrxAge        time.Duration // how long a trx can wait in buffer, 0 for no limit before it expired
+}
+
+// GetMempoolPolicy return mempool policy of the group, default values are used for fields not set
+func GetMempoolPolicy(groupId string, prefix ...string) *MempoolPolicy {
+	policy := &MempoolPolicy{
+		BatchSize:     DEFAULT_BATCH_SIZE,
+		MaxBufferSize: DEFAULT_MAX_BUFFER_SIZE,
+	}
+
+	item, err := nodectx.GetNodeCtx().GetChainStorage().GetMempoolPolicy(groupId, prefix...)
+	if err != nil {
+		trx_bft_log.Warnf("<%s> get mempool policy failed, use default, err <%s>", groupId, err.Error())
+		return policy
+	}
+	if item == nil {
+		return policy
+	}
+
+	if item.BatchSize > 0 {
+		policy.BatchSize = int(item.BatchSize)
+	}
+	if item.MaxTrxsPerSender > 0 {
+		policy.MaxTrxsPerSender = int(item.MaxTrxsPerSender)
+	}
+	if item.MaxBufferSize > 0 {
+		policy.MaxBufferSize = int(item.MaxBufferSize)
+	}
+	if item.MaxTrxAge > 0 {
+		policy.MaxTrxAge = time.Duration(item.MaxTrxAge) * time.Second
+	}
+	return policy
+}
+
+// just a simple wrap of HBB Trx Buffer DB, trx ids of each sender are indexed in memory,
+// so the buffer size is kept for each new trx without reading all trxs from db
 type TrxBuffer struct {
 	queueId string
+
+	mu      sync.Mutex
+	indexed bool                     //index is loaded from db
+	queues  map[string][]bufferedTrx //sender -> trxs from old to new
+	senders map[string]string        //trxId -> sender
+}
+
+type bufferedTrx struct {
+	trxId     string
+	timeStamp int64
 }
 
 func NewTrxBuffer(queueId string) *TrxBuffer {
 	b := &TrxBuffer{
 		queueId: queueId,
 	}
-	rand.Seed(time.Now().UnixNano())
 	return b
 }
 
+// loadIndex builds the index from trxs in db, caller must hold b.mu
+func (b *TrxBuffer) loadIndex() error {
+	if b.indexed {
+		return nil
+	}
+	trxs, err := nodectx.GetNodeCtx().GetChainStorage().GetAllTrxHBB(b.queueId)
+	if err != nil {
+		return err
+	}
+	b.queues = make(map[string][]bufferedTrx)
+	b.senders = make(map[string]string)
+	queues, _ := groupTrxsBySender(trxs)
+	for sender, q := range queues {
+		for _, trx := range q {
+			b.queues[sender] = append(b.queues[sender], bufferedTrx{trxId: trx.TrxId, timeStamp: trx.TimeStamp})
+			b.senders[trx.TrxId] = sender
+		}
+	}
+	b.indexed = true
+	return nil
+}
+
+// unindex removes a trx from the index, caller must hold b.mu
+func (b *TrxBuffer) unindex(trxId string) {
+	sender, ok := b.senders[trxId]
+	if !ok {
+		return
+	}
+	delete(b.senders, trxId)
+	q := b.queues[sender]
+	for i := range q {
+		if q[i].trxId == trxId {
+			q = append(q[:i], q[i+1:]...)
+			break
+		}
+	}
+	if len(q) == 0 {
+		delete(b.queues, sender)
+	} else {
+		b.queues[sender] = q
+	}
+}
+
 func (b *TrxBuffer) GetBufferLen() (int, error) {
-	return nodectx.GetNodeCtx().GetChainStorage().GeBufferedTrxLenHBB(b.queueId)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.loadIndex(); err != nil {
+		return -1, err
+	}
+	return len(b.senders), nil
 }
 
 func (b *TrxBuffer) Push(trx *quorumpb.Trx) error {
-	return nodectx.GetNodeCtx().GetChainStorage().AddTrxHBB(trx, b.queueId)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.loadIndex(); err != nil {
+		return err
+	}
+	if err := nodectx.GetNodeCtx().GetChainStorage().AddTrxHBB(trx, b.queueId); err != nil {
+		return err
+	}
+
+	//trxs mostly arrive in order, insert from the tail
+	q := b.queues[trx.SenderPubkey]
+	i := len(q)
+	for i > 0 && (q[i-1].timeStamp > trx.TimeStamp || (q[i-1].timeStamp == trx.TimeStamp && q[i-1].trxId > trx.TrxId)) {
+		i--
+	}
+	q = append(q, bufferedTrx{})
+	copy(q[i+1:], q[i:])
+	q[i] = bufferedTrx{trxId: trx.TrxId, timeStamp: trx.TimeStamp}
+	b.queues[trx.SenderPubkey] = q
+	b.senders[trx.TrxId] = trx.SenderPubkey
+
+	metric.MempoolTrxs.WithLabelValues(b.queueId).Set(float64(len(b.senders)))
+	metric.MempoolSenders.WithLabelValues(b.queueId).Set(float64(len(b.queues)))
+	return nil
 }
 
 func (b *TrxBuffer) Delete(trxId string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.unindex(trxId)
 	return nodectx.GetNodeCtx().GetChainStorage().RemoveTrxHBB(trxId, b.queueId)
 }
 
 func (b *TrxBuffer) Clear() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.indexed = false
 	return nodectx.GetNodeCtx().GetChainStorage().RemoveAllTrxHBB(b.queueId)
 }
 
@@ -45,23 +180,165 @@ func (b *TrxBuffer) GetAllTrxInBuffer() ([]*quorumpb.Trx, error) {
 	return nodectx.GetNodeCtx().GetChainStorage().GetAllTrxHBB(b.queueId)
 }
 
-// since trx is buffered in *random" way, no sequence is created
-// just return the first n items in the slice is enough
-// caller should check the length of return trx slice
-func (b *TrxBuffer) GetNRandTrx(n int) ([]*quorumpb.Trx, error) {
-	//get len
-	len, err := nodectx.GetNodeCtx().GetChainStorage().GeBufferedTrxLenHBB(b.queueId)
+// Evict removes expired trxs and trxs waited longer than MaxTrxAge, then trims the buffer to MaxBufferSize
+// by EvictOverflow. it reads all trxs in buffer, so it runs once for each proposal instead of each new trx
+func (b *TrxBuffer) Evict(policy *MempoolPolicy) error {
+	trxs, err := b.GetAllTrxInBuffer()
 	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixNano()
+	for _, trx := range trxs {
+		reason := ""
+		if now > rumchaindata.GetTrxExpiredAt(trx) {
+			reason = "expired"
+		} else if policy.MaxTrxAge > 0 && now-trx.TimeStamp > policy.MaxTrxAge.Nanoseconds() {
+			reason = "stale"
+		}
+		if reason == "" {
+			continue
+		}
+		trx_bft_log.Debugf("<%s> remove %s trx <%s> from buffer", b.queueId, reason, trx.TrxId)
+		if err := b.Delete(trx.TrxId); err != nil {
+			return err
+		}
+		metric.MempoolEvictedTotal.WithLabelValues(b.queueId, reason).Inc()
+	}
+
+	if _, err := b.EvictOverflow(policy); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	metric.MempoolTrxs.WithLabelValues(b.queueId).Set(float64(len(b.senders)))
+	metric.MempoolSenders.WithLabelValues(b.queueId).Set(float64(len(b.queues)))
+	return nil
+}
+
+// EvictOverflow removes the newest trxs of the sender with most trxs till the buffer fits MaxBufferSize,
+// so a flooding sender can not push out others. it works on the index only, cheap enough for each new trx.
+// return ids of trxs removed
+func (b *TrxBuffer) EvictOverflow(policy *MempoolPolicy) ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.loadIndex(); err != nil {
 		return nil, err
 	}
+	if policy.MaxBufferSize <= 0 {
+		return nil, nil
+	}
 
-	trxs, err := nodectx.GetNodeCtx().GetChainStorage().GetAllTrxHBB(b.queueId)
+	var evicted []string
+	for len(b.senders) > policy.MaxBufferSize {
+		heaviest := ""
+		for sender, q := range b.queues {
+			//ties go to the smaller pubkey, so all producers evict the same trxs
+			if heaviest == "" || len(q) > len(b.queues[heaviest]) || (len(q) == len(b.queues[heaviest]) && sender < heaviest) {
+				heaviest = sender
+			}
+		}
+		q := b.queues[heaviest]
+		trxId := q[len(q)-1].trxId
+		trx_bft_log.Debugf("<%s> buffer is full, remove trx <%s> of sender <%s>", b.queueId, trxId, heaviest)
+		b.unindex(trxId)
+		if err := nodectx.GetNodeCtx().GetChainStorage().RemoveTrxHBB(trxId, b.queueId); err != nil {
+			return evicted, err
+		}
+		metric.MempoolEvictedTotal.WithLabelValues(b.queueId, "overflow").Inc()
+		evicted = append(evicted, trxId)
+	}
+	return evicted, nil
+}
 
-	if n >= len {
-		//return all trxs in buffer
-		return trxs, err
-	} else {
-		//return first n trxs in buffer
-		return trxs[:n], err
+// SelectTrxs picks trxs to propose, senders take turns to contribute their oldest trx, so a sender
+// with lots of trxs can not starve others, and trxs of a sender are proposed in the order they were sent.
+// a trx is skipped (with all later trxs of its sender) if the bundle will exceed maxBytes with it.
+// turns are taken on the index, only trxs selected are read from db
+func (b *TrxBuffer) SelectTrxs(policy *MempoolPolicy, maxBytes int) ([]*quorumpb.Trx, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.loadIndex(); err != nil {
+		return nil, err
 	}
+
+	//senders ordered by their oldest trx
+	queues := make(map[string][]bufferedTrx)
+	var senders []string
+	for sender, q := range b.queues {
+		queues[sender] = q
+		senders = append(senders, sender)
+	}
+	sort.Slice(senders, func(i, j int) bool {
+		x, y := queues[senders[i]][0], queues[senders[j]][0]
+		if x.timeStamp != y.timeStamp {
+			return x.timeStamp < y.timeStamp
+		}
+		return x.trxId < y.trxId
+	})
+	taken := make(map[string]int)
+
+	var selected []*quorumpb.Trx
+	size := 0
+	for len(selected) < policy.BatchSize {
+		progress := false
+		for _, sender := range senders {
+			if len(selected) >= policy.BatchSize {
+				break
+			}
+			q := queues[sender]
+			if len(q) == 0 || (policy.MaxTrxsPerSender > 0 && taken[sender] >= policy.MaxTrxsPerSender) {
+				continue
+			}
+
+			trx, err := b.GetTrxById(q[0].trxId)
+			if err != nil {
+				return nil, err
+			}
+			trxSize := bundledTrxSize(trx)
+			if size+trxSize > maxBytes {
+				queues[sender] = nil
+				continue
+			}
+			selected = append(selected, trx)
+			size += trxSize
+			taken[sender]++
+			queues[sender] = q[1:]
+			progress = true
+		}
+		if !progress {
+			break
+		}
+	}
+	return selected, nil
+}
+
+// groupTrxsBySender return trxs of each sender from old to new,
+// and senders ordered by their oldest trx
+func groupTrxsBySender(trxs []*quorumpb.Trx) (map[string][]*quorumpb.Trx, []string) {
+	sorted := make([]*quorumpb.Trx, len(trxs))
+	copy(sorted, trxs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].TimeStamp != sorted[j].TimeStamp {
+			return sorted[i].TimeStamp < sorted[j].TimeStamp
+		}
+		return sorted[i].TrxId < sorted[j].TrxId
+	})
+
+	queues := make(map[string][]*quorumpb.Trx)
+	var senders []string
+	for _, trx := range sorted {
+		if _, ok := queues[trx.SenderPubkey]; !ok {
+			senders = append(senders, trx.SenderPubkey)
+		}
+		queues[trx.SenderPubkey] = append(queues[trx.SenderPubkey], trx)
+	}
+	return queues, senders
+}
+
+// bytes a trx takes in a marshaled HBTrxBundle
+func bundledTrxSize(trx *quorumpb.Trx) int {
+	n := proto.Size(trx)
+	return protowire.SizeTag(1) + protowire.SizeBytes(n)
 }
@@ -0,0 +1,150 @@
+package consensus
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+)
+
+var trxBufferTestRuns int
+
+func newTestTrxBuffer(t *testing.T) *TrxBuffer {
+	initSimCtx()
+	trxBufferTestRuns++
+	return NewTrxBuffer(fmt.Sprintf("buffertest-%d", trxBufferTestRuns))
+}
+
+// pushTrxs pushes count trxs of sender, the i-th one sent at base + i seconds, ids are <sender><i>
+func pushTrxs(t *testing.T, b *TrxBuffer, sender string, count int, base time.Time) {
+	for i := 0; i < count; i++ {
+		trx := &quorumpb.Trx{
+			TrxId:        fmt.Sprintf("%s%d", sender, i),
+			SenderPubkey: sender,
+			TimeStamp:    base.Add(time.Duration(i) * time.Second).UnixNano(),
+			Data:         []byte("data"),
+		}
+		if err := b.Push(trx); err != nil {
+			t.Fatalf("push trx failed: %s", err)
+		}
+	}
+}
+
+func trxIds(trxs []*quorumpb.Trx) string {
+	var ids []string
+	for _, trx := range trxs {
+		ids = append(ids, trx.TrxId)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestSelectTrxsFairness(t *testing.T) {
+	b := newTestTrxBuffer(t)
+	now := time.Now()
+	//a floods the buffer before others sent anything
+	pushTrxs(t, b, "a", 10, now)
+	pushTrxs(t, b, "b", 2, now.Add(100*time.Second))
+	pushTrxs(t, b, "c", 1, now.Add(200*time.Second))
+
+	for _, tc := range []struct {
+		policy *MempoolPolicy
+		expect string
+	}{
+		{&MempoolPolicy{BatchSize: 6}, "a0,b0,c0,a1,b1,a2"},
+		{&MempoolPolicy{BatchSize: 20, MaxTrxsPerSender: 2}, "a0,b0,c0,a1,b1"},
+		{&MempoolPolicy{BatchSize: 2}, "a0,b0"},
+	} {
+		trxs, err := b.SelectTrxs(tc.policy, MAXIMUM_TRX_BUNDLE_LENGTH)
+		if err != nil {
+			t.Fatalf("SelectTrxs failed: %s", err)
+		}
+		if ids := trxIds(trxs); ids != tc.expect {
+			t.Fatalf("selected <%s> with policy <%+v>, expect <%s>", ids, *tc.policy, tc.expect)
+		}
+	}
+
+	//bundle size is limited, no trx is taken out of order
+	trx, _ := b.GetTrxById("a0")
+	trxs, err := b.SelectTrxs(&MempoolPolicy{BatchSize: 20}, 3*bundledTrxSize(trx))
+	if err != nil {
+		t.Fatalf("SelectTrxs failed: %s", err)
+	}
+	if ids := trxIds(trxs); ids != "a0,b0,c0" {
+		t.Fatalf("selected <%s> in <%d> bytes", ids, 3*bundledTrxSize(trx))
+	}
+}
+
+func TestTrxBufferEvictOverflow(t *testing.T) {
+	b := newTestTrxBuffer(t)
+	now := time.Now()
+	policy := &MempoolPolicy{BatchSize: 20, MaxBufferSize: 5}
+	pushTrxs(t, b, "b", 2, now)
+	pushTrxs(t, b, "a", 8, now.Add(time.Second))
+
+	evicted, err := b.EvictOverflow(policy)
+	if err != nil {
+		t.Fatalf("EvictOverflow failed: %s", err)
+	}
+	//newest trxs of the flooding sender are evicted, others are kept
+	if strings.Join(evicted, ",") != "a7,a6,a5,a4,a3" {
+		t.Fatalf("evicted <%v>", evicted)
+	}
+	if n, _ := b.GetBufferLen(); n != 5 {
+		t.Fatalf("<%d> trxs left in buffer, expect 5", n)
+	}
+	trxs, _ := b.GetAllTrxInBuffer()
+	if len(trxs) != 5 {
+		t.Fatalf("<%d> trxs left in db, expect 5", len(trxs))
+	}
+
+	//ties go to the smaller pubkey
+	pushTrxs(t, b, "c", 2, now.Add(time.Minute))
+	evicted, _ = b.EvictOverflow(&MempoolPolicy{MaxBufferSize: 4})
+	if strings.Join(evicted, ",") != "a2,a1,b1" {
+		t.Fatalf("evicted <%v>", evicted)
+	}
+
+	//the index is loaded from db by a new buffer of the same queue
+	reloaded := NewTrxBuffer(b.queueId)
+	if n, _ := reloaded.GetBufferLen(); n != 4 {
+		t.Fatalf("<%d> trxs in reloaded buffer, expect 4", n)
+	}
+	if evicted, _ := reloaded.EvictOverflow(&MempoolPolicy{MaxBufferSize: 4}); len(evicted) != 0 {
+		t.Fatalf("evicted <%v> from a buffer in size", evicted)
+	}
+}
+
+func TestTrxBufferEvict(t *testing.T) {
+	b := newTestTrxBuffer(t)
+	now := time.Now()
+	pushTrxs(t, b, "a", 2, now.Add(-5*time.Second))
+	pushTrxs(t, b, "b", 2, now)
+	expired := &quorumpb.Trx{TrxId: "expired", SenderPubkey: "c", TimeStamp: now.UnixNano(), Expired: now.Add(-time.Second).UnixNano()}
+	if err := b.Push(expired); err != nil {
+		t.Fatalf("push trx failed: %s", err)
+	}
+
+	if err := b.Evict(&MempoolPolicy{BatchSize: 20}); err != nil {
+		t.Fatalf("Evict failed: %s", err)
+	}
+	if _, err := b.GetTrxById("expired"); err == nil {
+		t.Fatal("expired trx is kept")
+	}
+	if n, _ := b.GetBufferLen(); n != 4 {
+		t.Fatalf("<%d> trxs left in buffer, expect 4", n)
+	}
+
+	//trxs of a waited longer than MaxTrxAge
+	if err := b.Evict(&MempoolPolicy{BatchSize: 20, MaxTrxAge: 3 * time.Second}); err != nil {
+		t.Fatalf("Evict failed: %s", err)
+	}
+	trxs, err := b.SelectTrxs(&MempoolPolicy{BatchSize: 20}, MAXIMUM_TRX_BUNDLE_LENGTH)
+	if err != nil {
+		t.Fatalf("SelectTrxs failed: %s", err)
+	}
+	if ids := trxIds(trxs); ids != "b0,b1" {
+		t.Fatalf("<%s> left in buffer, expect b0,b1", ids)
+	}
+}
@@ -1,7 +1,9 @@
 package consensus
 
 import (
+	"bytes"
 	"fmt"
+	"strconv"
 
 	"github.com/klauspost/reedsolomon"
 	"github.com/rumsystem/quorum/internal/pkg/logging"
@@ -24,37 +26,43 @@ type TrxRBC struct {
 	numDataShards   int
 	ecc             reedsolomon.Encoder
 
-	recvEchos  map[string]Echos             //key is string(roothash)
-	recvReadys map[string][]*quorumpb.Ready //key is string(roothash)
+	recvEchos    map[string]Echos             //key is rbcKey(roothash, size)
+	recvReadys   map[string][]*quorumpb.Ready //key is rbcKey(roothash, size)
+	echoSenders  map[string]bool              //only the first ECHO of a producer counts
+	readySenders map[string]bool              //only the first READY of a producer counts
+	decoded      map[string][]byte            //values decoded and checked with their roothash
+	aborted      map[string]bool              //roothash not matched with decoded value, faulty proposer
+	initpHandled bool                         //only the first INIT_PROPOSE from the proposer is echoed
 
 	output []byte
 
-	readySent    map[string]bool
+	readySent    bool
 	consenusDone bool
 
 	acs *TrxACS //for callback when finished
 }
 
-// f : maximum failable node
+// f : maximum failable stake, faultyNodes : maximum failable node, see Config
 // N : total node
-// request : f * 3 < N
-// for example
+// request : faultyNodes * 3 < N
+// for example, all nodes with the same stake (poa)
 //
 //	3 producers node (owner included), 0 * 3 < 3, 0 failable node
 //	4 producers node (owner included), 1 * 3 < 4, 1 failable node
 //	10 producers node (owner included), 3 * 3 < 10, 3 failable node
 //
-// ecc will encode data bytes into (N) pieces, each node needs (N - 2f) pieces to recover data
+// ecc will encode data bytes into (N) pieces, each node needs (N - 2 * faultyNodes) pieces to recover data,
+// a quorum always has that many nodes
 func NewTrxRBC(cfg Config, acs *TrxACS, groupId, myPubkey, rbcInstPubkey string) (*TrxRBC, error) {
 	trx_rbc_log.Infof("NewTrxRBC called, EPOCH <%d>, RBC Instance pubkey <%s>", acs.Epoch, rbcInstPubkey)
 
 	var (
-		parityShards = 2 * cfg.f            //2f
-		dataShards   = cfg.N - parityShards //N - 2f
+		parityShards = 2 * cfg.faultyNodes  //2 * faultyNodes
+		dataShards   = cfg.N - parityShards //N - 2 * faultyNodes
 	)
 
 	//initial reed solomon codec
-	ecc, err := reedsolomon.New(dataShards, parityShards) //DataShards N-2f parityShards: 2f , totally N pieces
+	ecc, err := reedsolomon.New(dataShards, parityShards) //totally N pieces
 	if err != nil {
 		return nil, err
 	}
@@ -70,9 +78,13 @@ func NewTrxRBC(cfg Config, acs *TrxACS, groupId, myPubkey, rbcInstPubkey string)
 		ecc:             ecc,
 		recvEchos:       make(map[string]Echos),
 		recvReadys:      make(map[string][]*quorumpb.Ready),
+		echoSenders:     make(map[string]bool),
+		readySenders:    make(map[string]bool),
+		decoded:         make(map[string][]byte),
+		aborted:         make(map[string]bool),
 		numParityShards: parityShards,
 		numDataShards:   dataShards,
-		readySent:       make(map[string]bool),
+		readySent:       false,
 		consenusDone:    false,
 	}
 
@@ -94,7 +106,7 @@ func (r *TrxRBC) InputValue(data []byte) error {
 
 	//create InitPropoeMsgs
 	originalDataSize := len(data)
-	initProposeMsgs, err := MakeRBCInitProposeMessage(r.groupId, r.acs.bft.producer.nodename, r.MyPubkey, shards, r.Config.Nodes, originalDataSize)
+	initProposeMsgs, err := MakeRBCInitProposeMessage(r.acs.bft.ks, r.groupId, r.acs.bft.producer.nodename, r.MyPubkey, shards, r.Config.Nodes, originalDataSize)
 
 	if err != nil {
 		trx_rbc_log.Debugf(err.Error())
@@ -103,7 +115,7 @@ func (r *TrxRBC) InputValue(data []byte) error {
 
 	// broadcast RBC msg out via pubsub
 	for _, initMsg := range initProposeMsgs {
-		err := SendHBRBCMsg(r.groupId, initMsg, r.acs.Epoch)
+		err := SendHBRBCMsg(r.acs.bft.broadcaster, r.groupId, initMsg, r.acs.Epoch)
 		if err != nil {
 			return err
 		}
@@ -114,12 +126,17 @@ func (r *TrxRBC) InputValue(data []byte) error {
 
 func (r *TrxRBC) handleInitProposeMsg(initp *quorumpb.InitPropose) error {
 	trx_rbc_log.Infof("<%s> handleInitProposeMsg: Proposer <%s>, receiver <%s>, epoch <%d>", r.rbcInstPubkey, initp.ProposerPubkey, initp.RecvNodePubkey, r.acs.Epoch)
-	if !r.IsProducer(initp.ProposerPubkey) {
-		return fmt.Errorf("<%s> receive proof from non producer <%s>", r.rbcInstPubkey, initp.ProposerPubkey)
+	if initp.ProposerPubkey != r.rbcInstPubkey {
+		return fmt.Errorf("<%s> receive proof from other proposer <%s>", r.rbcInstPubkey, initp.ProposerPubkey)
 	}
 
-	if !r.VerifySign() {
-		return fmt.Errorf("<%s> verify signature failed from producer <%s>", r.rbcInstPubkey, initp.ProposerPubkey)
+	if err := VerifyRBCSign(r.acs.bft.ks, initp); err != nil {
+		return fmt.Errorf("<%s> verify signature failed from producer <%s>: %s", r.rbcInstPubkey, initp.ProposerPubkey, err)
+	}
+
+	//shard i is for producer i
+	if initp.Index != int64(r.nodeIndex(r.MyPubkey)) || initp.Leaves != int64(r.N) {
+		return fmt.Errorf("<%s> receive InitPropose with wrong shard index <%d> from producer <%s>", r.rbcInstPubkey, initp.Index, initp.ProposerPubkey)
 	}
 
 	//valid initP msg
@@ -127,14 +144,21 @@ func (r *TrxRBC) handleInitProposeMsg(initp *quorumpb.InitPropose) error {
 		return fmt.Errorf("<%s> receive invalid InitPropose msg from producer<%s>", r.rbcInstPubkey, initp.ProposerPubkey)
 	}
 
+	//echo only once, a faulty proposer may send different InitPropose to make producers echo different roothash
+	if r.initpHandled {
+		trx_rbc_log.Debugf("<%s> InitPropose already echoed, ignore", r.rbcInstPubkey)
+		return nil
+	}
+	r.initpHandled = true
+
 	//make proof
-	proofMsg, err := MakeRBCEchoMessage(r.groupId, r.acs.bft.producer.nodename, r.MyPubkey, initp, int(initp.OriginalDataSize))
+	proofMsg, err := MakeRBCEchoMessage(r.acs.bft.ks, r.groupId, r.acs.bft.producer.nodename, r.MyPubkey, initp, int(initp.OriginalDataSize))
 	if err != nil {
 		return err
 	}
 
 	trx_rbc_log.Infof("<%s> create and send Echo msg for proposer <%s>", r.rbcInstPubkey, initp.ProposerPubkey)
-	return SendHBRBCMsg(r.groupId, proofMsg, r.acs.Epoch)
+	return SendHBRBCMsg(r.acs.bft.broadcaster, r.groupId, proofMsg, r.acs.Epoch)
 }
 
 func (r *TrxRBC) handleEchoMsg(echo *quorumpb.Echo) error {
@@ -144,80 +168,52 @@ func (r *TrxRBC) handleEchoMsg(echo *quorumpb.Echo) error {
 		return fmt.Errorf("<%s> receive ECHO from non producer node <%s>", r.rbcInstPubkey, echo.EchoProviderPubkey)
 	}
 
-	if !r.VerifySign() {
-		return fmt.Errorf("<%s> verify ECHO signature failed from producer node <%s>", r.rbcInstPubkey, echo.EchoProviderPubkey)
+	if err := VerifyRBCSign(r.acs.bft.ks, echo); err != nil {
+		return fmt.Errorf("<%s> verify ECHO signature failed from producer node <%s>: %s", r.rbcInstPubkey, echo.EchoProviderPubkey, err)
+	}
+
+	//a producer can only echo its own shard
+	if echo.Index != int64(r.nodeIndex(echo.EchoProviderPubkey)) || echo.Leaves != int64(r.N) {
+		return fmt.Errorf("<%s> received ECHO with wrong shard index <%d> from producer node <%s>", r.rbcInstPubkey, echo.Index, echo.EchoProviderPubkey)
 	}
 
 	if !ValidateEcho(echo) {
 		return fmt.Errorf("<%s> received invalid ECHO from producer node <%s>", r.rbcInstPubkey, echo.EchoProviderPubkey)
 	}
 
-	roothashS := string(echo.RootHash)
+	if r.echoSenders[echo.EchoProviderPubkey] {
+		trx_rbc_log.Debugf("<%s> ECHO from <%s> already received, ignore", r.rbcInstPubkey, echo.EchoProviderPubkey)
+		return nil
+	}
+	r.echoSenders[echo.EchoProviderPubkey] = true
+
+	key := rbcKey(echo.RootHash, echo.OriginalDataSize)
 	//save echo by using roothash
 	trx_rbc_log.Debugf("<%s> Save ECHO with roothash <%v>", r.rbcInstPubkey, echo.RootHash[:8])
 
-	r.recvEchos[roothashS] = append(r.recvEchos[roothashS], echo)
-
-	trx_rbc_log.Debugf("<%s> RootHash <%v>, Recvived <%d> ECHO", r.rbcInstPubkey, echo.RootHash[:8], r.recvEchos[roothashS].Len())
+	r.recvEchos[key] = append(r.recvEchos[key], echo)
 
-	if len(r.recvReadys[roothashS]) == 2*r.f+1 && r.recvEchos[roothashS].Len() >= r.N-2*r.f {
-		trx_rbc_log.Debugf("<%s> RootHash <%s>, Recvived <%d> READY, which is 2F + 1", r.rbcInstPubkey, roothashS, len(r.recvReadys))
-		trx_rbc_log.Debugf("<%s> RootHash <%s>, Received <%d> ECHO, which is morn than N - 2F", r.rbcInstPubkey, roothashS, r.recvEchos[roothashS].Len())
-		trx_rbc_log.Debugf("<%s> RootHash <%s>, try decode", r.rbcInstPubkey)
-
-		output, err := TryDecodeValue(r.recvEchos[roothashS], r.ecc, r.numParityShards, r.numDataShards)
-		if err != nil {
-			return err
-		}
-
-		trx_rbc_log.Debugf("<%s> RBC for roothash <%s> is done", r.rbcInstPubkey, roothashS)
-		r.acs.RbcDone(r.rbcInstPubkey)
-		r.consenusDone = true
-		r.output = output
-
-		return nil
-	}
+	trx_rbc_log.Debugf("<%s> RootHash <%v>, Recvived <%d> ECHO", r.rbcInstPubkey, echo.RootHash[:8], r.recvEchos[key].Len())
 
 	/*
-		• upon receiving valid ECHO(h,·,·) messages from N − f distinct parties,
-		– interpolate {s', j} from any N −2f leaves received
+		• upon receiving valid ECHO(h,·,·) messages from a quorum,
+		– interpolate {s', j} from any N − 2 * faultyNodes leaves received
 		– recompute Merkle root h0 and if h0 != h then abort
 		– if READY(h) has not yet been sent, multicast READY(h)
 	*/
-	if r.recvEchos[roothashS].Len() == r.N-r.f {
-		trx_rbc_log.Debugf("<%s> get N-F echo for rootHash <%v>, try decode", r.rbcInstPubkey, echo.RootHash[:8])
-		output, err := TryDecodeValue(r.recvEchos[roothashS], r.ecc, r.numParityShards, r.numDataShards)
-		if err != nil {
+	if !r.readySent && r.isQuorum(r.echoedBy(key)) {
+		trx_rbc_log.Debugf("<%s> get quorum echo for rootHash <%v>, try decode", r.rbcInstPubkey, echo.RootHash[:8])
+		if _, err := r.decode(key); err != nil {
 			return err
 		}
 
-		//TBD, recal merkle root hash h0, compare with original root hash
-
-		//check if ready sent
-		if r.readySent[roothashS] {
-			return nil
-		}
-
 		//multicast READY msg
-		trx_rbc_log.Debugf("<%s> broadcast READY msg", r.rbcInstPubkey)
-		readyMsg, err := MakeRBCReadyMessage(r.groupId, r.acs.bft.producer.nodename, r.MyPubkey, echo.OriginalProposerPubkey, echo.RootHash)
-		if err != nil {
-			return err
-		}
-
-		err = SendHBRBCMsg(r.groupId, readyMsg, r.acs.Epoch)
-		if err != nil {
+		if err := r.sendReady(echo.RootHash, echo.OriginalDataSize); err != nil {
 			return err
 		}
-
-		//set ready sent
-		r.readySent[roothashS] = true
-
-		//set output
-		r.output = output
 	}
 
-	return nil
+	return r.tryOutput(key)
 }
 
 func (r *TrxRBC) handleReadyMsg(ready *quorumpb.Ready) error {
@@ -227,80 +223,126 @@ func (r *TrxRBC) handleReadyMsg(ready *quorumpb.Ready) error {
 		return fmt.Errorf("<%s> receive READY from non producer node <%s>", r.rbcInstPubkey, ready.ReadyProviderPubkey)
 	}
 
-	if !r.VerifySign() {
-		return fmt.Errorf("<%s> verify READY signature failed from producer node <%s>", r.rbcInstPubkey, ready.ReadyProviderPubkey)
+	if err := VerifyRBCSign(r.acs.bft.ks, ready); err != nil {
+		return fmt.Errorf("<%s> verify READY signature failed from producer node <%s>: %s", r.rbcInstPubkey, ready.ReadyProviderPubkey, err)
 	}
 
-	roothashS := string(ready.RootHash)
+	if r.readySenders[ready.ReadyProviderPubkey] {
+		trx_rbc_log.Debugf("<%s> READY from <%s> already received, ignore", r.rbcInstPubkey, ready.ReadyProviderPubkey)
+		return nil
+	}
+	r.readySenders[ready.ReadyProviderPubkey] = true
+
+	key := rbcKey(ready.RootHash, ready.OriginalDataSize)
 
 	//save it
-	trx_rbc_log.Debugf("<%s> Save READY with roothash <%v>", r.rbcInstPubkey, ready.RootHash[:8])
-	r.recvReadys[roothashS] = append(r.recvReadys[roothashS], ready)
+	trx_rbc_log.Debugf("<%s> Save READY with roothash <%v>", r.rbcInstPubkey, ready.RootHash)
+	r.recvReadys[key] = append(r.recvReadys[key], ready)
+
+	trx_rbc_log.Debugf("<%s> RootHash <%v>, Recvived <%d> READY", r.rbcInstPubkey, ready.RootHash, len(r.recvReadys[key]))
+
+	/*
+		upon receiving matching READY(h) messages from nodes hold more than f, if READY has not yet been sent, multicast READY(h)
+	*/
+	if !r.readySent && r.exceeds(r.readiedBy(key), r.f) {
+		trx_rbc_log.Debugf("<%s> RootHash <%v>, get READY more than f <%d>, boradcast READY now", r.rbcInstPubkey, ready.RootHash, r.f)
+		if err := r.sendReady(ready.RootHash, ready.OriginalDataSize); err != nil {
+			return err
+		}
+	}
 
-	trx_rbc_log.Debugf("<%s> RootHash <%v>, Recvived <%d> READY", r.rbcInstPubkey, ready.RootHash[:8], len(r.recvReadys[roothashS]))
+	return r.tryOutput(key)
+}
+
+func (r *TrxRBC) sendReady(roothash []byte, originalDataSize int64) error {
+	trx_rbc_log.Debugf("<%s> broadcast READY msg", r.rbcInstPubkey)
+	readyMsg, err := MakeRBCReadyMessage(r.acs.bft.ks, r.groupId, r.acs.bft.producer.nodename, r.MyPubkey, r.rbcInstPubkey, roothash, originalDataSize)
+	if err != nil {
+		return err
+	}
 
+	//set ready sent, only one READY is sent by a producer
+	r.readySent = true
+	return SendHBRBCMsg(r.acs.bft.broadcaster, r.groupId, readyMsg, r.acs.Epoch)
+}
+
+/*
+upon receiving matching READY(h) messages from nodes hold more than 2f, wait for (at least) N − 2 * faultyNodes ECHO messages, then decode v
+*/
+func (r *TrxRBC) tryOutput(key string) error {
 	if r.consenusDone {
-		trx_rbc_log.Debugf("<%s> RootHash <%v>, RBC is done, do nothing", r.rbcInstPubkey, ready.RootHash[:8])
 		return nil
 	}
 
-	/*
-		upon receiving f +1 matching READY(h) messages, if READY has not yet been sent, multicast READY(h)
-	*/
-
-	if len(r.recvReadys[roothashS]) == r.f+1 {
-		trx_rbc_log.Debugf("<%s> RootHash <%v>, get f + 1 <%d> READY", r.rbcInstPubkey, ready.RootHash[:8], r.f+1)
-		if !r.readySent[roothashS] {
-			trx_rbc_log.Debugf("<%s> READY not send, boradcast now", r.rbcInstPubkey)
-			readyMsg, err := MakeRBCReadyMessage(r.groupId, r.acs.bft.producer.nodename, r.myPubkey, ready.OriginalProposerPubkey, ready.RootHash)
-			if err != nil {
-				return err
-			}
-
-			err = SendHBRBCMsg(r.groupId, readyMsg, r.acs.Epoch)
-			if err != nil {
-				return err
-			}
-
-			//set ready sent
-			r.readySent[roothashS] = true
-		}
+	if !r.exceeds(r.readiedBy(key), 2*r.f) {
+		trx_rbc_log.Debugf("<%s> wait for more READY", r.rbcInstPubkey)
+		return nil
+	}
+	if r.recvEchos[key].Len() < r.numDataShards {
+		trx_rbc_log.Debugf("<%s> get enough READY but wait for more ECHO(now has <%d> ECHO)", r.rbcInstPubkey, r.recvEchos[key].Len())
+		return nil
 	}
 
-	/*
-		upon receiving 2 f +1 matching READY(h) messages, wait for (at least) N −2f ECHO messages, then decode v
-	*/
-	if len(r.recvReadys[roothashS]) >= 2*r.f+1 {
-		trx_rbc_log.Debugf("<%s> RootHash <%v>, Recvived <%d> READY, which is more than 2F + 1", r.rbcInstPubkey, ready.RootHash[:8], len(r.recvReadys))
-		if r.recvEchos[roothashS].Len() >= r.N-2*r.f {
-			trx_rbc_log.Debugf("<%s> RootHash <%v>, Received <%d> ECHO, which is more than N - 2F", r.rbcInstPubkey, ready.RootHash[:8], r.recvEchos[roothashS].Len())
-			if r.output == nil {
-				trx_rbc_log.Debugf("<%s> RootHash <%v>, not decoded yet, try decode", r.rbcInstPubkey, ready.RootHash[:8])
-				output, err := TryDecodeValue(r.recvEchos[roothashS], r.ecc, r.numParityShards, r.numDataShards)
-				if err != nil {
-					return err
-				}
-				r.output = output
-			} else {
-				trx_rbc_log.Debugf("<%s> RootHash <%v>, already decoded", r.rbcInstPubkey, ready.RootHash[:8])
-			}
-
-			trx_rbc_log.Debugf("<%s> Roothash <%v>, RBC is done", r.rbcInstPubkey, ready.RootHash[:8])
-			r.consenusDone = true
-			r.acs.RbcDone(r.rbcInstPubkey)
-
-			return nil
-		} else {
-			trx_rbc_log.Debugf("<%s> RootHash <%v> get enough READY but wait for more ECHO(now has <%d> ECHO)", r.rbcInstPubkey, ready.RootHash[:8], r.recvEchos[roothashS].Len())
-			return nil
-		}
+	output, err := r.decode(key)
+	if err != nil {
+		return err
 	}
 
-	//wait till get enough READY
-	trx_rbc_log.Debugf("<%s> RootHash <%v> wait for more READY", r.rbcInstPubkey, ready.RootHash[:8])
+	trx_rbc_log.Debugf("<%s> RBC is done", r.rbcInstPubkey)
+	r.output = output
+	r.consenusDone = true
+	r.acs.RbcDone(r.rbcInstPubkey)
 	return nil
 }
 
+// echoedBy return producers sent ECHO of the key
+func (r *TrxRBC) echoedBy(key string) map[string]bool {
+	senders := make(map[string]bool)
+	for _, echo := range r.recvEchos[key] {
+		senders[echo.EchoProviderPubkey] = true
+	}
+	return senders
+}
+
+// readiedBy return producers sent READY of the key
+func (r *TrxRBC) readiedBy(key string) map[string]bool {
+	senders := make(map[string]bool)
+	for _, ready := range r.recvReadys[key] {
+		senders[ready.ReadyProviderPubkey] = true
+	}
+	return senders
+}
+
+// decode value from received ECHOs of the key, and check it by the roothash, so all producers
+// get the same value no matter which shards they used
+func (r *TrxRBC) decode(key string) ([]byte, error) {
+	if output, ok := r.decoded[key]; ok {
+		return output, nil
+	}
+	if r.aborted[key] {
+		return nil, fmt.Errorf("<%s> roothash not matched with shards, faulty proposer", r.rbcInstPubkey)
+	}
+
+	echos := r.recvEchos[key]
+	output, err := TryDecodeValue(echos, r.ecc, r.numParityShards, r.numDataShards)
+	if err != nil {
+		return nil, err
+	}
+
+	shards, err := MakeShards(r.ecc, output)
+	if err != nil {
+		r.aborted[key] = true
+		return nil, err
+	}
+	if !bytes.Equal(MerkleRoot(shards), echos[0].RootHash) {
+		r.aborted[key] = true
+		return nil, fmt.Errorf("<%s> roothash not matched with shards, faulty proposer", r.rbcInstPubkey)
+	}
+
+	r.decoded[key] = output
+	return output, nil
+}
+
 func (r *TrxRBC) Output() []byte {
 	if r.output != nil {
 		output := r.output
@@ -311,14 +353,19 @@ func (r *TrxRBC) Output() []byte {
 }
 
 func (r *TrxRBC) IsProducer(pubkey string) bool {
-	for _, nodePubkey := range r.Nodes {
+	return r.nodeIndex(pubkey) >= 0
+}
+
+func (r *TrxRBC) nodeIndex(pubkey string) int {
+	for i, nodePubkey := range r.Nodes {
 		if nodePubkey == pubkey {
-			return true
+			return i
 		}
 	}
-	return false
+	return -1
 }
 
-func (r *TrxRBC) VerifySign() bool {
-	return true
+// ECHOs and READYs are counted by roothash and data size together
+func rbcKey(roothash []byte, originalDataSize int64) string {
+	return string(roothash) + ":" + strconv.FormatInt(originalDataSize, 10)
 }
@@ -0,0 +1,510 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// threshold encryption on secp256k1 (hashed elgamal with a shared secret key), the secret key is generated by
+// a feldman dkg among n participants, any threshold of them can decrypt a ciphertext together, less can not.
+// participant i (0 based) holds the share of point i+1 of the key polynomial
+
+const pointLen = 33 // compressed point
+const scalarLen = 32
+
+// ThresholdKey is the result of a dkg for one participant, Share is nil if the participant has no valid share,
+// it can still verify and combine decryption shares of others
+type ThresholdKey struct {
+	Index       int
+	Threshold   int
+	GroupPubkey []byte
+	VerifyKeys  [][]byte // g^share of each participant
+	Share       []byte
+}
+
+func randScalar() (*secp256k1.ModNScalar, error) {
+	k, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return &k.Key, nil
+}
+
+func hashToScalar(data ...[]byte) *secp256k1.ModNScalar {
+	h := sha256.New()
+	for _, d := range data {
+		h.Write(d)
+	}
+	s := &secp256k1.ModNScalar{}
+	s.SetByteSlice(h.Sum(nil))
+	return s
+}
+
+func scalarBytes(s *secp256k1.ModNScalar) []byte {
+	b := s.Bytes()
+	return b[:]
+}
+
+func parseScalar(b []byte) (*secp256k1.ModNScalar, error) {
+	if len(b) != scalarLen {
+		return nil, fmt.Errorf("invalid scalar length %d", len(b))
+	}
+	s := &secp256k1.ModNScalar{}
+	if overflow := s.SetByteSlice(b); overflow {
+		return nil, errors.New("scalar overflows")
+	}
+	return s, nil
+}
+
+func pointBytes(p *secp256k1.JacobianPoint) []byte {
+	p.ToAffine()
+	return secp256k1.NewPublicKey(&p.X, &p.Y).SerializeCompressed()
+}
+
+func parsePoint(b []byte) (*secp256k1.JacobianPoint, error) {
+	pk, err := secp256k1.ParsePubKey(b)
+	if err != nil {
+		return nil, err
+	}
+	p := &secp256k1.JacobianPoint{}
+	pk.AsJacobian(p)
+	return p, nil
+}
+
+func baseMul(k *secp256k1.ModNScalar) *secp256k1.JacobianPoint {
+	p := &secp256k1.JacobianPoint{}
+	secp256k1.ScalarBaseMultNonConst(k, p)
+	return p
+}
+
+func mul(k *secp256k1.ModNScalar, point *secp256k1.JacobianPoint) *secp256k1.JacobianPoint {
+	p := &secp256k1.JacobianPoint{}
+	secp256k1.ScalarMultNonConst(k, point, p)
+	return p
+}
+
+func add(p1, p2 *secp256k1.JacobianPoint) *secp256k1.JacobianPoint {
+	p := &secp256k1.JacobianPoint{}
+	secp256k1.AddNonConst(p1, p2, p)
+	return p
+}
+
+// evalCommitments return g^a(x) from commitments g^a_k of polynomial a
+func evalCommitments(commitments []*secp256k1.JacobianPoint, x uint32) *secp256k1.JacobianPoint {
+	xs := new(secp256k1.ModNScalar).SetInt(x)
+	result := &secp256k1.JacobianPoint{}
+	for k := len(commitments) - 1; k >= 0; k-- {
+		result = add(mul(xs, result), commitments[k])
+	}
+	return result
+}
+
+// GenerateDHKey return a secp256k1 key pair used to encrypt dkg shares sent to a participant
+func GenerateDHKey() (priv []byte, pub []byte, err error) {
+	k, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	return k.Serialize(), k.PubKey().SerializeCompressed(), nil
+}
+
+// DHSharedKey return the aes key shared by owners of the two key pairs
+func DHSharedKey(priv []byte, pub []byte) ([]byte, error) {
+	pk, err := secp256k1.ParsePubKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	secret := secp256k1.GenerateSharedSecret(secp256k1.PrivKeyFromBytes(priv), pk)
+	key := sha256.Sum256(secret)
+	return key[:], nil
+}
+
+// DHRevealKey return the point shared by the key pair priv and the peer pub with a proof it is made by priv,
+// so others can get the aes key of DHSharedKey to check a dkg share sent to the owner of priv, without its private key
+func DHRevealKey(priv []byte, pub []byte) (shared []byte, proof []byte, err error) {
+	peer, err := parsePoint(pub)
+	if err != nil {
+		return nil, nil, err
+	}
+	k := secp256k1.PrivKeyFromBytes(priv)
+	shared = pointBytes(mul(&k.Key, peer))
+	proof, err = dleqProve(&k.Key, k.PubKey().SerializeCompressed(), peer, pub, shared)
+	if err != nil {
+		return nil, nil, err
+	}
+	return shared, proof, nil
+}
+
+// VerifyDHReveal checks the shared point revealed by the owner of pub for the peer, return the aes key of DHSharedKey
+func VerifyDHReveal(pub []byte, peerPub []byte, shared []byte, proof []byte) ([]byte, error) {
+	peer, err := parsePoint(peerPub)
+	if err != nil {
+		return nil, err
+	}
+	if err := dleqVerify(pub, peer, peerPub, shared, proof); err != nil {
+		return nil, fmt.Errorf("invalid shared key: %s", err)
+	}
+	//DHSharedKey hashes the x coordinate
+	key := sha256.Sum256(shared[1:])
+	return key[:], nil
+}
+
+// NewDkgDeal creates a random polynomial of degree threshold-1, return commitments of its coefficients and
+// shares for n participants
+func NewDkgDeal(threshold, n int) (commitments [][]byte, shares [][]byte, err error) {
+	if threshold < 1 || threshold > n {
+		return nil, nil, fmt.Errorf("invalid threshold %d of %d participants", threshold, n)
+	}
+	coeffs := make([]*secp256k1.ModNScalar, threshold)
+	for k := range coeffs {
+		if coeffs[k], err = randScalar(); err != nil {
+			return nil, nil, err
+		}
+		commitments = append(commitments, pointBytes(baseMul(coeffs[k])))
+	}
+	for i := 0; i < n; i++ {
+		x := new(secp256k1.ModNScalar).SetInt(uint32(i + 1))
+		share := &secp256k1.ModNScalar{}
+		for k := threshold - 1; k >= 0; k-- {
+			share.Mul(x).Add(coeffs[k])
+		}
+		shares = append(shares, scalarBytes(share))
+	}
+	return commitments, shares, nil
+}
+
+func parseCommitments(commitments [][]byte) ([]*secp256k1.JacobianPoint, error) {
+	var points []*secp256k1.JacobianPoint
+	for _, c := range commitments {
+		p, err := parsePoint(c)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+// VerifyDkgShare checks share of participant index against commitments of the dealer
+func VerifyDkgShare(commitments [][]byte, index int, share []byte) error {
+	points, err := parseCommitments(commitments)
+	if err != nil {
+		return err
+	}
+	s, err := parseScalar(share)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(pointBytes(baseMul(s)), pointBytes(evalCommitments(points, uint32(index+1)))) {
+		return errors.New("share does not match commitments")
+	}
+	return nil
+}
+
+// NewThresholdKey combines deals of all dealers, commitments[i] and shares[i] are from dealer i,
+// shares is nil if the participant only needs to verify and combine decryption shares
+func NewThresholdKey(index, threshold, n int, commitments [][][]byte, shares [][]byte) (*ThresholdKey, error) {
+	if len(commitments) == 0 {
+		return nil, errors.New("no deal")
+	}
+
+	key := &ThresholdKey{Index: index, Threshold: threshold}
+	groupPubkey := &secp256k1.JacobianPoint{}
+	verifyKeys := make([]*secp256k1.JacobianPoint, n)
+	for j := range verifyKeys {
+		verifyKeys[j] = &secp256k1.JacobianPoint{}
+	}
+	for _, c := range commitments {
+		if len(c) != threshold {
+			return nil, fmt.Errorf("deal has %d commitments, expect %d", len(c), threshold)
+		}
+		points, err := parseCommitments(c)
+		if err != nil {
+			return nil, err
+		}
+		groupPubkey = add(groupPubkey, points[0])
+		for j := range verifyKeys {
+			verifyKeys[j] = add(verifyKeys[j], evalCommitments(points, uint32(j+1)))
+		}
+	}
+	key.GroupPubkey = pointBytes(groupPubkey)
+	for _, vk := range verifyKeys {
+		key.VerifyKeys = append(key.VerifyKeys, pointBytes(vk))
+	}
+
+	if shares != nil {
+		if len(shares) != len(commitments) {
+			return nil, fmt.Errorf("got %d shares from %d dealers", len(shares), len(commitments))
+		}
+		secret := &secp256k1.ModNScalar{}
+		for _, share := range shares {
+			s, err := parseScalar(share)
+			if err != nil {
+				return nil, err
+			}
+			secret.Add(s)
+		}
+		key.Share = scalarBytes(secret)
+	}
+	return key, nil
+}
+
+// ThresholdEncrypt encrypts data to the group pubkey, the ciphertext is
+// U (g^r) | W (g^w) | z (w + c*r) | aes encrypted data, (W, z) proves the knowledge of r,
+// so a ciphertext can not be copied into another one to get it decrypted. c is bound to label,
+// the ciphertext is only valid with the same label, so it can not be replayed under another one
+func ThresholdEncrypt(groupPubkey []byte, data []byte, label []byte) ([]byte, error) {
+	y, err := parsePoint(groupPubkey)
+	if err != nil {
+		return nil, err
+	}
+	r, err := randScalar()
+	if err != nil {
+		return nil, err
+	}
+	aesKey := sha256.Sum256(pointBytes(mul(r, y)))
+	encrypted, err := AesEncrypt(data, aesKey[:])
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := randScalar()
+	if err != nil {
+		return nil, err
+	}
+	u := pointBytes(baseMul(r))
+	wp := pointBytes(baseMul(w))
+	c := hashToScalar(u, wp, encrypted, label)
+	z := new(secp256k1.ModNScalar).Mul2(c, r).Add(w)
+
+	ciphertext := make([]byte, 0, 2*pointLen+scalarLen+len(encrypted))
+	ciphertext = append(ciphertext, u...)
+	ciphertext = append(ciphertext, wp...)
+	ciphertext = append(ciphertext, scalarBytes(z)...)
+	return append(ciphertext, encrypted...), nil
+}
+
+// VerifyCiphertext checks the proof in ciphertext made with label, return U and the aes encrypted data
+func VerifyCiphertext(ciphertext []byte, label []byte) (*secp256k1.JacobianPoint, []byte, error) {
+	if len(ciphertext) < 2*pointLen+scalarLen {
+		return nil, nil, errors.New("ciphertext too short")
+	}
+	u, err := parsePoint(ciphertext[:pointLen])
+	if err != nil {
+		return nil, nil, err
+	}
+	z, err := parseScalar(ciphertext[2*pointLen : 2*pointLen+scalarLen])
+	if err != nil {
+		return nil, nil, err
+	}
+	encrypted := ciphertext[2*pointLen+scalarLen:]
+
+	//g^z == W * U^c
+	c := hashToScalar(ciphertext[:pointLen], ciphertext[pointLen:2*pointLen], encrypted, label)
+	w, err := parsePoint(ciphertext[pointLen : 2*pointLen])
+	if err != nil {
+		return nil, nil, err
+	}
+	if !bytes.Equal(pointBytes(baseMul(z)), pointBytes(add(w, mul(c, u)))) {
+		return nil, nil, errors.New("invalid ciphertext proof")
+	}
+	return u, encrypted, nil
+}
+
+func dleqChallenge(vk, u, d, a1, a2 []byte) *secp256k1.ModNScalar {
+	return hashToScalar(vk, u, d, a1, a2)
+}
+
+// dleqProve proves log_g(vk) == log_u(d) for d = u^x, ub is the encoded u
+func dleqProve(x *secp256k1.ModNScalar, vk []byte, u *secp256k1.JacobianPoint, ub []byte, d []byte) ([]byte, error) {
+	w, err := randScalar()
+	if err != nil {
+		return nil, err
+	}
+	c := dleqChallenge(vk, ub, d, pointBytes(baseMul(w)), pointBytes(mul(w, u)))
+	z := new(secp256k1.ModNScalar).Mul2(c, x).Add(w)
+	return append(scalarBytes(c), scalarBytes(z)...), nil
+}
+
+// dleqVerify checks the proof made by dleqProve
+func dleqVerify(vkb []byte, u *secp256k1.JacobianPoint, ub []byte, db []byte, proof []byte) error {
+	if len(proof) != 2*scalarLen {
+		return errors.New("invalid proof length")
+	}
+	d, err := parsePoint(db)
+	if err != nil {
+		return err
+	}
+	vk, err := parsePoint(vkb)
+	if err != nil {
+		return err
+	}
+	c, err := parseScalar(proof[:scalarLen])
+	if err != nil {
+		return err
+	}
+	z, err := parseScalar(proof[scalarLen:])
+	if err != nil {
+		return err
+	}
+
+	//a1 = g^z / vk^c, a2 = u^z / d^c
+	negc := new(secp256k1.ModNScalar).NegateVal(c)
+	a1 := add(baseMul(z), mul(negc, vk))
+	a2 := add(mul(z, u), mul(negc, d))
+	if !dleqChallenge(vkb, ub, db, pointBytes(a1), pointBytes(a2)).Equals(c) {
+		return errors.New("invalid dleq proof")
+	}
+	return nil
+}
+
+// DecryptShare return decryption share U^x of the participant with a proof that log_g(VerifyKey) == log_U(share)
+func (k *ThresholdKey) DecryptShare(ciphertext []byte, label []byte) (share []byte, proof []byte, err error) {
+	if k.Share == nil {
+		return nil, nil, errors.New("no key share")
+	}
+	u, _, err := VerifyCiphertext(ciphertext, label)
+	if err != nil {
+		return nil, nil, err
+	}
+	x, err := parseScalar(k.Share)
+	if err != nil {
+		return nil, nil, err
+	}
+	share = pointBytes(mul(x, u))
+	proof, err = dleqProve(x, k.VerifyKeys[k.Index], u, ciphertext[:pointLen], share)
+	if err != nil {
+		return nil, nil, err
+	}
+	return share, proof, nil
+}
+
+// VerifyDecryptShare checks the decryption share of participant index
+func (k *ThresholdKey) VerifyDecryptShare(index int, ciphertext []byte, label []byte, share []byte, proof []byte) error {
+	if index < 0 || index >= len(k.VerifyKeys) {
+		return fmt.Errorf("invalid participant index %d", index)
+	}
+	u, _, err := VerifyCiphertext(ciphertext, label)
+	if err != nil {
+		return err
+	}
+	if err := dleqVerify(k.VerifyKeys[index], u, ciphertext[:pointLen], share, proof); err != nil {
+		return fmt.Errorf("invalid decryption share: %s", err)
+	}
+	return nil
+}
+
+// Combine decrypts the ciphertext with verified decryption shares (participant index -> share), at least Threshold shares needed
+func (k *ThresholdKey) Combine(ciphertext []byte, label []byte, shares map[int][]byte) ([]byte, error) {
+	if len(shares) < k.Threshold {
+		return nil, fmt.Errorf("need %d decryption shares, got %d", k.Threshold, len(shares))
+	}
+	_, encrypted, err := VerifyCiphertext(ciphertext, label)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := k.interpolate(shares)
+	if err != nil {
+		return nil, err
+	}
+	aesKey := sha256.Sum256(pointBytes(secret))
+	return AesDecode(encrypted, aesKey[:])
+}
+
+// interpolate return h^x from shares h^x_i of Threshold participants, by lagrange interpolation at 0 in the exponent
+func (k *ThresholdKey) interpolate(shares map[int][]byte) (*secp256k1.JacobianPoint, error) {
+	var indexes []int
+	for i := range shares {
+		indexes = append(indexes, i)
+		if len(indexes) == k.Threshold {
+			break
+		}
+	}
+
+	secret := &secp256k1.JacobianPoint{}
+	for _, i := range indexes {
+		num := new(secp256k1.ModNScalar).SetInt(1)
+		den := new(secp256k1.ModNScalar).SetInt(1)
+		xi := new(secp256k1.ModNScalar).SetInt(uint32(i + 1))
+		for _, j := range indexes {
+			if j == i {
+				continue
+			}
+			xj := new(secp256k1.ModNScalar).SetInt(uint32(j + 1))
+			num.Mul(xj)
+			den.Mul(new(secp256k1.ModNScalar).NegateVal(xi).Add(xj))
+		}
+		lambda := num.Mul(den.InverseNonConst())
+		d, err := parsePoint(shares[i])
+		if err != nil {
+			return nil, err
+		}
+		secret = add(secret, mul(lambda, d))
+	}
+	return secret, nil
+}
+
+// hashToPoint maps data to a point nobody knows the discrete log of, by trying x = sha256(data | counter)
+func hashToPoint(data []byte) (*secp256k1.JacobianPoint, []byte) {
+	counter := make([]byte, 4)
+	for i := uint32(0); ; i++ {
+		binary.BigEndian.PutUint32(counter, i)
+		h := sha256.New()
+		h.Write(data)
+		h.Write(counter)
+		b := append([]byte{0x02}, h.Sum(nil)...)
+		if p, err := parsePoint(b); err == nil {
+			return p, b
+		}
+	}
+}
+
+// CoinShare return the share H(name)^x of the common coin of name with a proof like DecryptShare,
+// the coin is unknown till Threshold participants revealed their shares
+func (k *ThresholdKey) CoinShare(name []byte) (share []byte, proof []byte, err error) {
+	if k.Share == nil {
+		return nil, nil, errors.New("no key share")
+	}
+	x, err := parseScalar(k.Share)
+	if err != nil {
+		return nil, nil, err
+	}
+	h, hb := hashToPoint(name)
+	share = pointBytes(mul(x, h))
+	proof, err = dleqProve(x, k.VerifyKeys[k.Index], h, hb, share)
+	if err != nil {
+		return nil, nil, err
+	}
+	return share, proof, nil
+}
+
+// VerifyCoinShare checks the coin share of participant index
+func (k *ThresholdKey) VerifyCoinShare(index int, name []byte, share []byte, proof []byte) error {
+	if index < 0 || index >= len(k.VerifyKeys) {
+		return fmt.Errorf("invalid participant index %d", index)
+	}
+	h, hb := hashToPoint(name)
+	if err := dleqVerify(k.VerifyKeys[index], h, hb, share, proof); err != nil {
+		return fmt.Errorf("invalid coin share: %s", err)
+	}
+	return nil
+}
+
+// CombineCoin return the coin from verified coin shares (participant index -> share), at least Threshold shares needed,
+// all sets of shares give the same coin
+func (k *ThresholdKey) CombineCoin(shares map[int][]byte) (bool, error) {
+	if len(shares) < k.Threshold {
+		return false, fmt.Errorf("need %d coin shares, got %d", k.Threshold, len(shares))
+	}
+	sig, err := k.interpolate(shares)
+	if err != nil {
+		return false, err
+	}
+	h := sha256.Sum256(pointBytes(sig))
+	return h[0]&1 == 1, nil
+}
@@ -0,0 +1,237 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func newTestThresholdKeys(t *testing.T, threshold, n int) []*ThresholdKey {
+	var commitments [][][]byte
+	var shares [][][]byte
+	for i := 0; i < n; i++ {
+		c, s, err := NewDkgDeal(threshold, n)
+		if err != nil {
+			t.Fatalf("NewDkgDeal failed: %s", err)
+		}
+		commitments = append(commitments, c)
+		shares = append(shares, s)
+	}
+
+	var keys []*ThresholdKey
+	for j := 0; j < n; j++ {
+		var myShares [][]byte
+		for i := 0; i < n; i++ {
+			if err := VerifyDkgShare(commitments[i], j, shares[i][j]); err != nil {
+				t.Fatalf("VerifyDkgShare failed: %s", err)
+			}
+			myShares = append(myShares, shares[i][j])
+		}
+		key, err := NewThresholdKey(j, threshold, n, commitments, myShares)
+		if err != nil {
+			t.Fatalf("NewThresholdKey failed: %s", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestThresholdEncrypt(t *testing.T) {
+	keys := newTestThresholdKeys(t, 2, 4)
+	for _, k := range keys[1:] {
+		if !bytes.Equal(k.GroupPubkey, keys[0].GroupPubkey) {
+			t.Fatal("participants got different group pubkeys")
+		}
+	}
+
+	data := []byte("proposal of a producer")
+	label := []byte("group:1:producer")
+	ciphertext, err := ThresholdEncrypt(keys[0].GroupPubkey, data, label)
+	if err != nil {
+		t.Fatalf("ThresholdEncrypt failed: %s", err)
+	}
+
+	shares := make(map[int][]byte)
+	for _, i := range []int{3, 1} {
+		share, proof, err := keys[i].DecryptShare(ciphertext, label)
+		if err != nil {
+			t.Fatalf("DecryptShare failed: %s", err)
+		}
+		if err := keys[0].VerifyDecryptShare(i, ciphertext, label, share, proof); err != nil {
+			t.Fatalf("VerifyDecryptShare failed: %s", err)
+		}
+		if err := keys[0].VerifyDecryptShare(2, ciphertext, label, share, proof); err == nil {
+			t.Fatal("share of participant 2 should be invalid")
+		}
+		shares[i] = share
+	}
+
+	if _, err := keys[0].Combine(ciphertext, label, map[int][]byte{1: shares[1]}); err == nil {
+		t.Fatal("combine with less than threshold shares should fail")
+	}
+	decrypted, err := keys[0].Combine(ciphertext, label, shares)
+	if err != nil {
+		t.Fatalf("Combine failed: %s", err)
+	}
+	if !bytes.Equal(decrypted, data) {
+		t.Fatalf("decrypted data mismatch: %s", decrypted)
+	}
+}
+
+func TestThresholdCiphertextProof(t *testing.T) {
+	keys := newTestThresholdKeys(t, 1, 1)
+	ciphertext, err := ThresholdEncrypt(keys[0].GroupPubkey, []byte("data"), []byte("label"))
+	if err != nil {
+		t.Fatalf("ThresholdEncrypt failed: %s", err)
+	}
+	ciphertext[len(ciphertext)-1] ^= 1
+	if _, _, err := keys[0].DecryptShare(ciphertext, []byte("label")); err == nil {
+		t.Fatal("tampered ciphertext should be rejected")
+	}
+}
+
+func TestThresholdCiphertextReplay(t *testing.T) {
+	keys := newTestThresholdKeys(t, 2, 4)
+	data := []byte("proposal of producer a")
+	label := []byte("group:1:a")
+	ciphertext, err := ThresholdEncrypt(keys[0].GroupPubkey, data, label)
+	if err != nil {
+		t.Fatalf("ThresholdEncrypt failed: %s", err)
+	}
+
+	//producer b copies the ciphertext of a into its own proposal, or a replays it in a later epoch
+	for _, replayed := range [][]byte{[]byte("group:1:b"), []byte("group:2:a"), nil} {
+		if _, _, err := VerifyCiphertext(ciphertext, replayed); err == nil {
+			t.Fatalf("ciphertext replayed with label <%s> should be rejected", replayed)
+		}
+		if _, _, err := keys[1].DecryptShare(ciphertext, replayed); err == nil {
+			t.Fatalf("decryption share of ciphertext replayed with label <%s> should not be made", replayed)
+		}
+	}
+
+	shares := make(map[int][]byte)
+	for _, i := range []int{0, 2} {
+		share, proof, err := keys[i].DecryptShare(ciphertext, label)
+		if err != nil {
+			t.Fatalf("DecryptShare failed: %s", err)
+		}
+		if err := keys[0].VerifyDecryptShare(i, ciphertext, []byte("group:1:b"), share, proof); err == nil {
+			t.Fatal("decryption share should not be valid under another label")
+		}
+		shares[i] = share
+	}
+	if _, err := keys[0].Combine(ciphertext, []byte("group:1:b"), shares); err == nil {
+		t.Fatal("combine under another label should fail")
+	}
+	decrypted, err := keys[0].Combine(ciphertext, label, shares)
+	if err != nil || !bytes.Equal(decrypted, data) {
+		t.Fatalf("decrypt with the label failed: %v", err)
+	}
+}
+
+func TestVerifyDkgShare(t *testing.T) {
+	commitments, shares, err := NewDkgDeal(2, 3)
+	if err != nil {
+		t.Fatalf("NewDkgDeal failed: %s", err)
+	}
+	if err := VerifyDkgShare(commitments, 0, shares[1]); err == nil {
+		t.Fatal("share of participant 1 should not match participant 0")
+	}
+}
+
+func TestThresholdCoin(t *testing.T) {
+	keys := newTestThresholdKeys(t, 2, 4)
+	name := []byte("group:epoch:proposer:round")
+
+	shares := make(map[int][]byte)
+	for i, k := range keys {
+		share, proof, err := k.CoinShare(name)
+		if err != nil {
+			t.Fatalf("CoinShare failed: %s", err)
+		}
+		if err := keys[0].VerifyCoinShare(i, name, share, proof); err != nil {
+			t.Fatalf("VerifyCoinShare failed: %s", err)
+		}
+		if err := keys[0].VerifyCoinShare(i, []byte("another round"), share, proof); err == nil {
+			t.Fatal("share of another coin should be invalid")
+		}
+		if err := keys[0].VerifyCoinShare((i+1)%len(keys), name, share, proof); err == nil {
+			t.Fatal("share of another participant should be invalid")
+		}
+		shares[i] = share
+	}
+
+	if _, err := keys[0].CombineCoin(map[int][]byte{0: shares[0]}); err == nil {
+		t.Fatal("combine with less than threshold shares should fail")
+	}
+	coin, err := keys[0].CombineCoin(map[int][]byte{0: shares[0], 3: shares[3]})
+	if err != nil {
+		t.Fatalf("CombineCoin failed: %s", err)
+	}
+	for _, pair := range [][2]int{{0, 1}, {1, 2}, {2, 3}} {
+		c, err := keys[pair[0]].CombineCoin(map[int][]byte{pair[0]: shares[pair[0]], pair[1]: shares[pair[1]]})
+		if err != nil {
+			t.Fatalf("CombineCoin failed: %s", err)
+		}
+		if c != coin {
+			t.Fatalf("shares of %v give another coin", pair)
+		}
+	}
+
+	//coins of different names are independent, both values show up
+	values := make(map[bool]bool)
+	for i := 0; i < 16 && len(values) < 2; i++ {
+		name := []byte{byte(i)}
+		coinShares := make(map[int][]byte)
+		for _, j := range []int{1, 2} {
+			share, _, err := keys[j].CoinShare(name)
+			if err != nil {
+				t.Fatalf("CoinShare failed: %s", err)
+			}
+			coinShares[j] = share
+		}
+		c, err := keys[0].CombineCoin(coinShares)
+		if err != nil {
+			t.Fatalf("CombineCoin failed: %s", err)
+		}
+		values[c] = true
+	}
+	if len(values) != 2 {
+		t.Fatal("coins of 16 names are all the same")
+	}
+}
+
+func TestDHRevealKey(t *testing.T) {
+	priv1, pub1, err := GenerateDHKey()
+	if err != nil {
+		t.Fatalf("GenerateDHKey failed: %s", err)
+	}
+	priv2, pub2, err := GenerateDHKey()
+	if err != nil {
+		t.Fatalf("GenerateDHKey failed: %s", err)
+	}
+	expected, err := DHSharedKey(priv2, pub1)
+	if err != nil {
+		t.Fatalf("DHSharedKey failed: %s", err)
+	}
+
+	shared, proof, err := DHRevealKey(priv1, pub2)
+	if err != nil {
+		t.Fatalf("DHRevealKey failed: %s", err)
+	}
+	key, err := VerifyDHReveal(pub1, pub2, shared, proof)
+	if err != nil {
+		t.Fatalf("VerifyDHReveal failed: %s", err)
+	}
+	if !bytes.Equal(key, expected) {
+		t.Fatal("revealed key is not the shared key")
+	}
+
+	//a participant can not reveal a key in the name of another one
+	if _, err := VerifyDHReveal(pub2, pub1, shared, proof); err == nil {
+		t.Fatal("reveal of another participant should be invalid")
+	}
+	_, pub3, _ := GenerateDHKey()
+	if _, err := VerifyDHReveal(pub1, pub3, shared, proof); err == nil {
+		t.Fatal("reveal for another peer should be invalid")
+	}
+}
@@ -0,0 +1,219 @@
+package data
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// group archive layout:
+//
+//	magic | version(uint16) | header len(uint32) | header(json)
+//	block record: 1 | len(uint32) | block(protobuf) ...
+//	end record:   0 | block count(uint64) | sha256 of all bytes before the checksum
+const (
+	GROUP_ARCHIVE_MAGIC   = "RUMGRPAR"
+	GROUP_ARCHIVE_VERSION = uint16(1)
+
+	archiveRecordEnd   = byte(0)
+	archiveRecordBlock = byte(1)
+
+	maxArchiveRecordSize = 64 * 1024 * 1024
+)
+
+var ErrArchiveChecksum = errors.New("group archive checksum mismatch")
+
+type GroupArchiveHeader struct {
+	Version   uint16 `json:"version"`
+	GroupId   string `json:"group_id"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// GroupArchiveWriter writes blocks of a group as a stream, Close must be called to finish the archive
+type GroupArchiveWriter struct {
+	w     io.Writer
+	hash  hash.Hash
+	count uint64
+}
+
+func NewGroupArchiveWriter(w io.Writer, groupId string) (*GroupArchiveWriter, error) {
+	aw := &GroupArchiveWriter{w: w, hash: sha256.New()}
+
+	header, err := json.Marshal(&GroupArchiveHeader{
+		Version:   GROUP_ARCHIVE_VERSION,
+		GroupId:   groupId,
+		CreatedAt: time.Now().UnixNano(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBufferString(GROUP_ARCHIVE_MAGIC)
+	binary.Write(buf, binary.BigEndian, GROUP_ARCHIVE_VERSION)
+	binary.Write(buf, binary.BigEndian, uint32(len(header)))
+	buf.Write(header)
+	if err := aw.write(buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return aw, nil
+}
+
+func (aw *GroupArchiveWriter) WriteBlock(block *quorumpb.Block) error {
+	data, err := proto.Marshal(block)
+	if err != nil {
+		return err
+	}
+
+	buf := bytes.NewBuffer([]byte{archiveRecordBlock})
+	binary.Write(buf, binary.BigEndian, uint32(len(data)))
+	buf.Write(data)
+	if err := aw.write(buf.Bytes()); err != nil {
+		return err
+	}
+	aw.count++
+	return nil
+}
+
+// Close writes the end record with the block count and checksum, it does not close the underlying writer
+func (aw *GroupArchiveWriter) Close() error {
+	buf := bytes.NewBuffer([]byte{archiveRecordEnd})
+	binary.Write(buf, binary.BigEndian, aw.count)
+	if err := aw.write(buf.Bytes()); err != nil {
+		return err
+	}
+	_, err := aw.w.Write(aw.hash.Sum(nil))
+	return err
+}
+
+func (aw *GroupArchiveWriter) Count() uint64 {
+	return aw.count
+}
+
+func (aw *GroupArchiveWriter) write(data []byte) error {
+	if _, err := aw.w.Write(data); err != nil {
+		return err
+	}
+	aw.hash.Write(data)
+	return nil
+}
+
+// GroupArchiveReader reads blocks from a group archive stream,
+// Next returns io.EOF after the end record is read and the checksum is verified
+type GroupArchiveReader struct {
+	Header *GroupArchiveHeader
+	r      io.Reader
+	hash   hash.Hash
+	count  uint64
+	done   bool
+}
+
+func NewGroupArchiveReader(r io.Reader) (*GroupArchiveReader, error) {
+	ar := &GroupArchiveReader{hash: sha256.New()}
+	ar.r = io.TeeReader(bufio.NewReader(r), ar.hash)
+
+	magic := make([]byte, len(GROUP_ARCHIVE_MAGIC))
+	if _, err := io.ReadFull(ar.r, magic); err != nil {
+		return nil, err
+	}
+	if string(magic) != GROUP_ARCHIVE_MAGIC {
+		return nil, errors.New("not a group archive")
+	}
+
+	var version uint16
+	if err := binary.Read(ar.r, binary.BigEndian, &version); err != nil {
+		return nil, err
+	}
+	if version != GROUP_ARCHIVE_VERSION {
+		return nil, fmt.Errorf("unsupported group archive version %d", version)
+	}
+
+	header, err := ar.readRecord()
+	if err != nil {
+		return nil, err
+	}
+	ar.Header = &GroupArchiveHeader{}
+	if err := json.Unmarshal(header, ar.Header); err != nil {
+		return nil, err
+	}
+	return ar, nil
+}
+
+func (ar *GroupArchiveReader) Next() (*quorumpb.Block, error) {
+	if ar.done {
+		return nil, io.EOF
+	}
+
+	var rtype [1]byte
+	if _, err := io.ReadFull(ar.r, rtype[:]); err != nil {
+		return nil, err
+	}
+
+	switch rtype[0] {
+	case archiveRecordBlock:
+		data, err := ar.readRecord()
+		if err != nil {
+			return nil, err
+		}
+		block := &quorumpb.Block{}
+		if err := proto.Unmarshal(data, block); err != nil {
+			return nil, err
+		}
+		ar.count++
+		return block, nil
+	case archiveRecordEnd:
+		if err := ar.readEnd(); err != nil {
+			return nil, err
+		}
+		ar.done = true
+		return nil, io.EOF
+	default:
+		return nil, fmt.Errorf("unknown group archive record type %d", rtype[0])
+	}
+}
+
+func (ar *GroupArchiveReader) readRecord() ([]byte, error) {
+	var size uint32
+	if err := binary.Read(ar.r, binary.BigEndian, &size); err != nil {
+		return nil, err
+	}
+	if size > maxArchiveRecordSize {
+		return nil, fmt.Errorf("group archive record too large: %d", size)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(ar.r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (ar *GroupArchiveReader) readEnd() error {
+	var count uint64
+	if err := binary.Read(ar.r, binary.BigEndian, &count); err != nil {
+		return err
+	}
+	if count != ar.count {
+		return fmt.Errorf("group archive block count mismatch, expect %d, got %d", count, ar.count)
+	}
+
+	//the checksum itself is not part of the hashed content
+	expected := ar.hash.Sum(nil)
+	checksum := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(ar.r, checksum); err != nil {
+		return err
+	}
+	if !bytes.Equal(expected, checksum) {
+		return ErrArchiveChecksum
+	}
+	return nil
+}
@@ -15,6 +15,43 @@ import (
 	"time"
 )
 
+// TrxsRoot return hash of hashes of trxs in a block
+func TrxsRoot(trxs []*quorumpb.Trx) ([]byte, error) {
+	var buffer bytes.Buffer
+	for _, trx := range trxs {
+		tbytes, err := proto.Marshal(trx)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(localcrypto.Hash(tbytes))
+	}
+	return localcrypto.Hash(buffer.Bytes()), nil
+}
+
+// BlockHash return hash of a block without its hash and sign, it covers TrxsRoot instead of trxs, so a block
+// pruned to its header can still be verified. blocks without TrxsRoot (created by old version) are hashed with trxs
+func BlockHash(block *quorumpb.Block) ([]byte, error) {
+	header := &quorumpb.Block{
+		GroupId:        block.GroupId,
+		BlockId:        block.BlockId,
+		Epoch:          block.Epoch,
+		PrevHash:       block.PrevHash,
+		ProducerPubkey: block.ProducerPubkey,
+		Sudo:           block.Sudo,
+		TimeStamp:      block.TimeStamp,
+		TrxsRoot:       block.TrxsRoot,
+	}
+	if len(block.TrxsRoot) == 0 {
+		header.Trxs = block.Trxs
+	}
+
+	bbytes, err := proto.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	return localcrypto.Hash(bbytes), nil
+}
+
 func CreateBlockByEthKey(parentBlk *quorumpb.Block, epoch uint64, trxs []*quorumpb.Trx, sudo bool, groupPublicKey string, keystore localcrypto.Keystore, keyalias string, opts ...string) (*quorumpb.Block, error) {
 	newBlock := &quorumpb.Block{
 		GroupId:        parentBlk.GroupId,
@@ -27,12 +64,16 @@ func CreateBlockByEthKey(parentBlk *quorumpb.Block, epoch uint64, trxs []*quorum
 		TimeStamp:      time.Now().UnixNano(),
 	}
 
-	tbytes, err := proto.Marshal(newBlock)
+	trxsRoot, err := TrxsRoot(trxs)
 	if err != nil {
 		return nil, err
 	}
+	newBlock.TrxsRoot = trxsRoot
 
-	hash := localcrypto.Hash(tbytes)
+	hash, err := BlockHash(newBlock)
+	if err != nil {
+		return nil, err
+	}
 	newBlock.BlockHash = hash
 
 	var signature []byte
@@ -59,12 +100,16 @@ func RegenrateBlockWithParent(parentBlock *quorumpb.Block, orphanBlock *quorumpb
 	orphanBlock.PrevHash = parentBlock.BlockHash
 	orphanBlock.BlockId = parentBlock.BlockId + 1
 
-	tbytes, err := proto.Marshal(orphanBlock)
+	trxsRoot, err := TrxsRoot(orphanBlock.Trxs)
 	if err != nil {
 		return nil, err
 	}
+	orphanBlock.TrxsRoot = trxsRoot
 
-	hash := localcrypto.Hash(tbytes)
+	hash, err := BlockHash(orphanBlock)
+	if err != nil {
+		return nil, err
+	}
 	orphanBlock.BlockHash = hash
 
 	var signature []byte
@@ -124,41 +169,44 @@ func CreateGenesisBlockByEthKey(groupId string, groupPublicKey string, keystore
 }
 
 func ValidBlockWithParent(newBlock, parentBlock *quorumpb.Block) (bool, error) {
+	//check blockid and prevhash
+	if newBlock.BlockId != parentBlock.BlockId+1 {
+		return false, fmt.Errorf("blockid mismatch with parent block")
+	}
 
-	//step 1, check hash for newBlock
-	blkWithOutHashAndSign := &quorumpb.Block{
-		GroupId:        newBlock.GroupId,
-		BlockId:        newBlock.BlockId,
-		Epoch:          newBlock.Epoch,
-		PrevHash:       newBlock.PrevHash,
-		ProducerPubkey: newBlock.ProducerPubkey,
-		Trxs:           newBlock.Trxs,
-		Sudo:           newBlock.Sudo,
-		TimeStamp:      newBlock.TimeStamp,
-		BlockHash:      nil,
-		ProducerSign:   nil,
+	if !bytes.Equal(newBlock.PrevHash, parentBlock.BlockHash) {
+		return false, errors.New("prevhash mismatch with parent block")
+	}
+
+	return ValidBlock(newBlock)
+}
+
+// ValidBlock checks trxs, hash and producer sign of a block, it can be used before its parent is known
+func ValidBlock(newBlock *quorumpb.Block) (bool, error) {
+	if len(newBlock.TrxsRoot) > 0 {
+		trxsRoot, err := TrxsRoot(newBlock.Trxs)
+		if err != nil {
+			return false, err
+		}
+		if !bytes.Equal(trxsRoot, newBlock.TrxsRoot) {
+			return false, fmt.Errorf("trxs root for new block is invalid")
+		}
 	}
+	return ValidBlockHeader(newBlock)
+}
 
-	tbytes, err := proto.Marshal(blkWithOutHashAndSign)
+// ValidBlockHeader checks hash and producer sign of a block without its trxs, it is used for blocks pruned to
+// their headers. a block without TrxsRoot can not be verified without its trxs
+func ValidBlockHeader(newBlock *quorumpb.Block) (bool, error) {
+	hash, err := BlockHash(newBlock)
 	if err != nil {
 		return false, err
 	}
-
-	hash := localcrypto.Hash(tbytes)
 	if !bytes.Equal(hash, newBlock.BlockHash) {
 		return false, fmt.Errorf("hash for new block is invalid")
 	}
 
-	//step 2, check blockid and prevhash
-	if newBlock.BlockId != parentBlock.BlockId+1 {
-		return false, fmt.Errorf("blockid mismatch with parent block")
-	}
-
-	if !bytes.Equal(newBlock.PrevHash, parentBlock.BlockHash) {
-		return false, errors.New("prevhash mismatch with parent block")
-	}
-
-	//step 3, check producer sign
+	//step 2, check producer sign
 	bytespubkey, err := base64.RawURLEncoding.DecodeString(newBlock.ProducerPubkey)
 	if err == nil { //try eth key
 		ethpubkey, err := ethcrypto.DecompressPubkey(bytespubkey)
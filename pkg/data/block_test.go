@@ -0,0 +1,75 @@
+package data
+
+import (
+	"testing"
+
+	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestValidPrunedBlock(t *testing.T) {
+	groupitem := GetGroupItem()
+	_, pubkey, err := GetKeyStorePubKey(groupitem.GroupId, t.TempDir())
+	if err != nil {
+		t.Fatalf("keystore new key err : %s", err)
+	}
+	groupitem.UserSignPubkey = pubkey
+	ks := localcrypto.GetKeystore()
+
+	trxFactory := &TrxFactory{}
+	trxFactory.Init("1.0.0", groupitem, "default")
+	var trxs []*quorumpb.Trx
+	for _, content := range []string{"content 1", "content 2"} {
+		trx, err := trxFactory.GetPostAnyTrx("", []byte(content))
+		if err != nil {
+			t.Fatalf("create trx failed: %s", err)
+		}
+		trxs = append(trxs, trx)
+	}
+
+	genesis, err := CreateGenesisBlockByEthKey(groupitem.GroupId, pubkey, ks, "")
+	if err != nil {
+		t.Fatalf("create genesis block failed: %s", err)
+	}
+	block, err := CreateBlockByEthKey(genesis, 1, trxs, false, pubkey, ks, "")
+	if err != nil {
+		t.Fatalf("create block failed: %s", err)
+	}
+	if ok, err := ValidBlockWithParent(block, genesis); !ok {
+		t.Fatalf("valid block is rejected: %v", err)
+	}
+
+	//pruned to its header, the hash still can be checked
+	pruned := proto.Clone(block).(*quorumpb.Block)
+	pruned.Trxs = nil
+	if ok, err := ValidBlockHeader(pruned); !ok {
+		t.Fatalf("header of pruned block is rejected: %v", err)
+	}
+	if ok, _ := ValidBlock(pruned); ok {
+		t.Fatal("block without its trxs passed ValidBlock")
+	}
+
+	tampered := proto.Clone(block).(*quorumpb.Block)
+	tampered.Trxs = tampered.Trxs[:1]
+	if ok, _ := ValidBlock(tampered); ok {
+		t.Fatal("block with a trx dropped passed ValidBlock")
+	}
+	tampered = proto.Clone(pruned).(*quorumpb.Block)
+	tampered.TrxsRoot = localcrypto.Hash([]byte("other trxs"))
+	if ok, _ := ValidBlockHeader(tampered); ok {
+		t.Fatal("header with another trxs root passed ValidBlockHeader")
+	}
+
+	//block created by old version, its hash covers its trxs
+	old := &quorumpb.Block{GroupId: groupitem.GroupId, BlockId: 1, Epoch: 1, PrevHash: genesis.BlockHash, ProducerPubkey: pubkey, Trxs: trxs}
+	old.BlockHash, _ = BlockHash(old)
+	old.ProducerSign, _ = ks.EthSignByKeyName(groupitem.GroupId, old.BlockHash)
+	if ok, err := ValidBlock(old); !ok {
+		t.Fatalf("old block is rejected: %v", err)
+	}
+	old.Trxs = nil
+	if ok, _ := ValidBlockHeader(old); ok {
+		t.Fatal("old block without its trxs passed ValidBlockHeader")
+	}
+}
@@ -0,0 +1,70 @@
+package data
+
+import (
+	"sync"
+	"time"
+)
+
+var MAX_SEEN_TRX_COUNT = 10000
+
+// SeenTrxIndex is a bounded index of trxs packaged by a group,
+// trxs in it will not be accepted or packaged again.
+// an item can be dropped once the trx expired, since expired trx will be rejected anyway
+type SeenTrxIndex struct {
+	mu      sync.Mutex
+	maxSize int
+	items   map[string]int64 //trx_id -> expired at
+	order   []string
+}
+
+func NewSeenTrxIndex(maxSize int) *SeenTrxIndex {
+	return &SeenTrxIndex{
+		maxSize: maxSize,
+		items:   make(map[string]int64),
+	}
+}
+
+func (s *SeenTrxIndex) Add(trxId string, expiredAt int64) {
+	s.add(trxId, expiredAt, time.Now().UnixNano())
+}
+
+func (s *SeenTrxIndex) add(trxId string, expiredAt int64, now int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.items[trxId]; ok {
+		return
+	}
+
+	s.items[trxId] = expiredAt
+	s.order = append(s.order, trxId)
+	s.prune(now)
+}
+
+func (s *SeenTrxIndex) IsSeen(trxId string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.items[trxId]
+	return ok
+}
+
+func (s *SeenTrxIndex) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.items)
+}
+
+// prune drops expired items from the head, and the oldest items if the index is full
+func (s *SeenTrxIndex) prune(now int64) {
+	i := 0
+	for ; i < len(s.order); i++ {
+		trxId := s.order[i]
+		if len(s.order)-i <= s.maxSize && s.items[trxId] >= now {
+			break
+		}
+		delete(s.items, trxId)
+	}
+	s.order = s.order[i:]
+}
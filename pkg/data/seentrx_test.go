@@ -0,0 +1,39 @@
+package data
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSeenTrxIndex(t *testing.T) {
+	now := time.Now().UnixNano()
+	later := now + int64(time.Minute)
+
+	seen := NewSeenTrxIndex(3)
+	seen.add("trx1", later, now)
+	if !seen.IsSeen("trx1") {
+		t.Fatal("trx added is not seen, it can be replayed")
+	}
+	seen.add("trx1", later, now)
+	if seen.Len() != 1 {
+		t.Fatalf("trx added twice, index has <%d> items", seen.Len())
+	}
+
+	//full index drops the oldest trxs
+	for i := 2; i <= 4; i++ {
+		seen.add(fmt.Sprintf("trx%d", i), later, now)
+	}
+	if seen.Len() != 3 || seen.IsSeen("trx1") || !seen.IsSeen("trx4") {
+		t.Fatalf("index should keep the latest 3 trxs, got <%d>", seen.Len())
+	}
+
+	//expired trxs are dropped, they are rejected by CheckTrxTime anyway
+	seen = NewSeenTrxIndex(3)
+	seen.add("trx1", now+1, now)
+	seen.add("trx2", later, now)
+	seen.add("trx3", later, now+2)
+	if seen.IsSeen("trx1") || !seen.IsSeen("trx2") || !seen.IsSeen("trx3") {
+		t.Fatal("only the expired trx should be dropped")
+	}
+}
@@ -0,0 +1,159 @@
+package data
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+func CreateSnapshotByEthKey(groupId string, epoch uint64, block *quorumpb.Block, items []*quorumpb.SnapshotItem, senderPubkey string, keystore localcrypto.Keystore, keyalias string, opts ...string) (*quorumpb.Snapshot, error) {
+	snapshot := &quorumpb.Snapshot{
+		GroupId:      groupId,
+		Epoch:        epoch,
+		Block:        block,
+		Items:        items,
+		TimeStamp:    time.Now().UnixNano(),
+		SenderPubkey: senderPubkey,
+	}
+
+	sbytes, err := proto.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := localcrypto.Hash(sbytes)
+
+	var signature []byte
+	if keyalias == "" {
+		signature, err = keystore.EthSignByKeyName(groupId, hash, opts...)
+	} else {
+		signature, err = keystore.EthSignByKeyAlias(keyalias, hash, opts...)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(signature) == 0 {
+		return nil, errors.New("create signature failed")
+	}
+
+	snapshot.SenderSign = signature
+	return snapshot, nil
+}
+
+// VerifySnapshot check the signature of the snapshot and the block it carried
+func VerifySnapshot(snapshot *quorumpb.Snapshot) (bool, error) {
+	if snapshot.Block == nil || snapshot.Block.GroupId != snapshot.GroupId {
+		return false, fmt.Errorf("invalid block in snapshot")
+	}
+
+	//step 1, check block hash and producer sign, trxs of the block are not applied, it may be pruned to its header
+	if ok, err := ValidBlockHeader(snapshot.Block); !ok {
+		return false, fmt.Errorf("verify snapshot block failed, %v", err)
+	}
+
+	//step 2, check snapshot sign
+	snapshotWithoutSign := &quorumpb.Snapshot{
+		GroupId:      snapshot.GroupId,
+		Epoch:        snapshot.Epoch,
+		Block:        snapshot.Block,
+		Items:        snapshot.Items,
+		TimeStamp:    snapshot.TimeStamp,
+		SenderPubkey: snapshot.SenderPubkey,
+	}
+
+	sbytes, err := proto.Marshal(snapshotWithoutSign)
+	if err != nil {
+		return false, err
+	}
+
+	hash := localcrypto.Hash(sbytes)
+	return verifyEthSign(hash, snapshot.SenderSign, snapshot.SenderPubkey)
+}
+
+// SnapshotStateHash return hash of the chain state carried by a snapshot, snapshots created by producers at the same block have the same one.
+// each producer builds and signs its own block at a height, so only the agreed part of the block (id, epoch and trxs) is hashed
+func SnapshotStateHash(snapshot *quorumpb.Snapshot) ([]byte, error) {
+	if snapshot.Block == nil {
+		return nil, fmt.Errorf("invalid block in snapshot")
+	}
+
+	trxsRoot := snapshot.Block.TrxsRoot
+	if len(trxsRoot) == 0 {
+		//block created by old version
+		var err error
+		if trxsRoot, err = TrxsRoot(snapshot.Block.Trxs); err != nil {
+			return nil, err
+		}
+	}
+
+	state := &quorumpb.Snapshot{
+		GroupId: snapshot.GroupId,
+		Epoch:   snapshot.Epoch,
+		Block: &quorumpb.Block{
+			GroupId:  snapshot.Block.GroupId,
+			BlockId:  snapshot.Block.BlockId,
+			Epoch:    snapshot.Block.Epoch,
+			TrxsRoot: trxsRoot,
+		},
+		Items: snapshot.Items,
+	}
+
+	sbytes, err := proto.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	return localcrypto.Hash(sbytes), nil
+}
+
+// VerifySnapshotQuorum return a snapshot which state is signed by at least quorum distinct producers,
+// snapshots not signed by producers or with invalid signature are skipped
+func VerifySnapshotQuorum(snapshots []*quorumpb.Snapshot, producers map[string]bool, quorum int) (*quorumpb.Snapshot, error) {
+	signers := make(map[string]map[string]bool) //state hash -> producers signed it
+	for _, snapshot := range snapshots {
+		if !producers[snapshot.SenderPubkey] {
+			continue
+		}
+
+		if verified, _ := VerifySnapshot(snapshot); !verified {
+			continue
+		}
+
+		hash, err := SnapshotStateHash(snapshot)
+		if err != nil {
+			return nil, err
+		}
+
+		key := string(hash)
+		if signers[key] == nil {
+			signers[key] = make(map[string]bool)
+		}
+		signers[key][snapshot.SenderPubkey] = true
+		if len(signers[key]) >= quorum {
+			return snapshot, nil
+		}
+	}
+	return nil, fmt.Errorf("snapshot state is not signed by <%d> producers", quorum)
+}
+
+func verifyEthSign(hash []byte, sign []byte, pubkey string) (bool, error) {
+	bytespubkey, err := base64.RawURLEncoding.DecodeString(pubkey)
+	if err != nil {
+		return false, err
+	}
+
+	ethpubkey, err := ethcrypto.DecompressPubkey(bytespubkey)
+	if err != nil {
+		return false, err
+	}
+
+	ks := localcrypto.GetKeystore()
+	return ks.EthVerifySign(hash, sign, ethpubkey), nil
+}
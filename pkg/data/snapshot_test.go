@@ -0,0 +1,150 @@
+package data
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
+	quorumpb "github.com/rumsystem/quorum/pkg/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// snapshotProducers creates sign keys of producers, the first one is the group owner, it produced the genesis block
+func snapshotProducers(t *testing.T, groupId string, n int) (localcrypto.Keystore, []string, *quorumpb.Block) {
+	_, err := localcrypto.InitKeystore("defaultkeystore", t.TempDir())
+	if err != nil {
+		t.Fatalf("init keystore failed: %s", err)
+	}
+	ks := localcrypto.GetKeystore()
+	ks.Unlock(signKeyMap, "password")
+
+	var keynames []string
+	for i := 0; i < n; i++ {
+		keyname := groupId
+		if i > 0 {
+			keyname = groupId + string(rune('a'+i))
+		}
+		if _, err := ks.NewKeyWithDefaultPassword(keyname, localcrypto.Sign); err != nil {
+			t.Fatalf("new key failed: %s", err)
+		}
+		keynames = append(keynames, keyname)
+	}
+
+	ownerPubkey, _ := ks.GetEncodedPubkey(groupId, localcrypto.Sign)
+	block, err := CreateGenesisBlockByEthKey(groupId, ownerPubkey, ks, "")
+	if err != nil {
+		t.Fatalf("create block failed: %s", err)
+	}
+	return ks, keynames, block
+}
+
+// producerBlock builds and signs the block of a producer on parent, producers build their own blocks of the same trxs
+func producerBlock(t *testing.T, ks localcrypto.Keystore, keyname string, parent *quorumpb.Block, trxs []*quorumpb.Trx) *quorumpb.Block {
+	pubkey, _ := ks.GetEncodedPubkey(keyname, localcrypto.Sign)
+	trxsRoot, err := TrxsRoot(trxs)
+	if err != nil {
+		t.Fatalf("trxs root failed: %s", err)
+	}
+	block := &quorumpb.Block{
+		GroupId:        parent.GroupId,
+		BlockId:        parent.BlockId + 1,
+		Epoch:          parent.Epoch + 1,
+		PrevHash:       parent.BlockHash,
+		ProducerPubkey: pubkey,
+		Trxs:           trxs,
+		TimeStamp:      time.Now().UnixNano(),
+		TrxsRoot:       trxsRoot,
+	}
+	block.BlockHash, _ = BlockHash(block)
+	block.ProducerSign, err = ks.EthSignByKeyName(keyname, block.BlockHash)
+	if err != nil {
+		t.Fatalf("sign block failed: %s", err)
+	}
+	return block
+}
+
+func signSnapshot(t *testing.T, ks localcrypto.Keystore, keyname string, snapshot *quorumpb.Snapshot) *quorumpb.Snapshot {
+	snapshot = proto.Clone(snapshot).(*quorumpb.Snapshot)
+	snapshot.SenderPubkey, _ = ks.GetEncodedPubkey(keyname, localcrypto.Sign)
+	snapshot.SenderSign = nil
+
+	sbytes, err := proto.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("marshal snapshot failed: %s", err)
+	}
+	snapshot.SenderSign, err = ks.EthSignByKeyName(keyname, localcrypto.Hash(sbytes))
+	if err != nil {
+		t.Fatalf("sign snapshot failed: %s", err)
+	}
+	return snapshot
+}
+
+func TestVerifySnapshotQuorum(t *testing.T) {
+	groupId := GetGroupItem().GroupId
+	ks, keynames, genesis := snapshotProducers(t, groupId, 4)
+	producers := make(map[string]bool)
+	for _, keyname := range keynames {
+		pubkey, _ := ks.GetEncodedPubkey(keyname, localcrypto.Sign)
+		producers[pubkey] = true
+	}
+
+	//every producer snapshots its own block of the same trxs
+	trxs := []*quorumpb.Trx{{TrxId: "trx1", GroupId: groupId, Data: []byte("data")}}
+	state := &quorumpb.Snapshot{
+		GroupId: groupId,
+		Epoch:   genesis.Epoch + 1,
+		Items:   []*quorumpb.SnapshotItem{{Key: "producer_a", Value: []byte("a")}},
+	}
+	var snapshots []*quorumpb.Snapshot
+	for _, keyname := range keynames {
+		state.Block = producerBlock(t, ks, keyname, genesis, trxs)
+		snapshots = append(snapshots, signSnapshot(t, ks, keyname, state))
+	}
+	if bytes.Equal(snapshots[0].Block.BlockHash, snapshots[1].Block.BlockHash) {
+		t.Fatal("blocks of different producers have the same hash")
+	}
+
+	//4 producers, f + 1 = 2
+	if _, err := VerifySnapshotQuorum(snapshots[:1], producers, 2); err == nil {
+		t.Fatal("snapshot signed by a single producer is accepted")
+	}
+	if _, err := VerifySnapshotQuorum([]*quorumpb.Snapshot{snapshots[0], snapshots[0]}, producers, 2); err == nil {
+		t.Fatal("snapshot signed twice by the same producer is accepted")
+	}
+	if _, err := VerifySnapshotQuorum(snapshots[:2], producers, 2); err != nil {
+		t.Fatalf("snapshot signed by 2 producers is rejected: %s", err)
+	}
+
+	//another state signed by its sender, does not count for the state of others
+	tampered := proto.Clone(snapshots[1]).(*quorumpb.Snapshot)
+	tampered.Items[0].Value = []byte("b")
+	forged := signSnapshot(t, ks, keynames[1], tampered)
+	if _, err := VerifySnapshotQuorum([]*quorumpb.Snapshot{snapshots[0], forged}, producers, 2); err == nil {
+		t.Fatal("snapshots of different states are counted together")
+	}
+
+	//a block of other trxs at the same height
+	other := proto.Clone(snapshots[1]).(*quorumpb.Snapshot)
+	other.Block = producerBlock(t, ks, keynames[1], genesis, []*quorumpb.Trx{{TrxId: "trx2", GroupId: groupId}})
+	other = signSnapshot(t, ks, keynames[1], other)
+	if _, err := VerifySnapshotQuorum([]*quorumpb.Snapshot{snapshots[0], other}, producers, 2); err == nil {
+		t.Fatal("snapshots of blocks with different trxs are counted together")
+	}
+
+	//items changed after signed
+	changed := proto.Clone(snapshots[1]).(*quorumpb.Snapshot)
+	changed.Items[0].Value = []byte("b")
+	if ok, _ := VerifySnapshot(changed); ok {
+		t.Fatal("tampered snapshot passed verification")
+	}
+	if _, err := VerifySnapshotQuorum([]*quorumpb.Snapshot{snapshots[0], changed}, producers, 2); err == nil {
+		t.Fatal("tampered snapshot is counted")
+	}
+
+	//signed by a node not a producer
+	delete(producers, snapshots[1].SenderPubkey)
+	if _, err := VerifySnapshotQuorum(snapshots[:2], producers, 2); err == nil {
+		t.Fatal("snapshot signed by a non producer is counted")
+	}
+}
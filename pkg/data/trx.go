@@ -10,6 +10,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	ethcrypto "github.com/ethereum/go-ethereum/crypto"
 	guuid "github.com/google/uuid"
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
 	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
 	quorumpb "github.com/rumsystem/quorum/pkg/pb"
 	"google.golang.org/protobuf/proto"
@@ -21,6 +22,14 @@ const (
 	Sec   = 30
 )
 
+// trx with timestamp later than now + MAX_TRX_CLOCK_SKEW will be rejected by producers
+var MAX_TRX_CLOCK_SKEW = 10 * time.Second
+
+// GetTrxLifetime return the default lifetime of a trx
+func GetTrxLifetime() time.Duration {
+	return Hours*time.Hour + Mins*time.Minute + Sec*time.Second
+}
+
 func CreateTrxWithoutSign(nodename string, version string, groupItem *quorumpb.GroupItem, msgType quorumpb.TrxType, data []byte, encryptto ...[]string) (*quorumpb.Trx, []byte, error) {
 	var trx quorumpb.Trx
 
@@ -60,6 +69,7 @@ func CreateTrxWithoutSign(nodename string, version string, groupItem *quorumpb.G
 	trx.Data = encryptdData
 	trx.Version = version
 	trx.TimeStamp = time.Now().UnixNano()
+	trx.Expired = time.Now().Add(GetTrxLifetime()).UnixNano()
 
 	bytes, err := proto.Marshal(&trx)
 	if err != nil {
@@ -91,6 +101,26 @@ func CreateTrxByEthKey(nodename string, version string, groupItem *quorumpb.Grou
 	return trx, nil
 }
 
+// GetTrxExpiredAt return the time (in nanoseconds) after which the trx can't be packaged,
+// trx without Expired (created by old version) is treated as expired after the default lifetime
+func GetTrxExpiredAt(trx *quorumpb.Trx) int64 {
+	if trx.Expired != 0 {
+		return trx.Expired
+	}
+	return trx.TimeStamp + int64(GetTrxLifetime())
+}
+
+// CheckTrxTime reject trx which is expired or with a timestamp too far in the future
+func CheckTrxTime(trx *quorumpb.Trx, now time.Time) error {
+	if now.UnixNano() > GetTrxExpiredAt(trx) {
+		return rumerrors.ErrTrxExpired
+	}
+	if trx.TimeStamp > now.Add(MAX_TRX_CLOCK_SKEW).UnixNano() {
+		return rumerrors.ErrTrxFromFuture
+	}
+	return nil
+}
+
 func VerifyTrx(trx *quorumpb.Trx) (bool, error) {
 	//clone trxMsg to verify
 	clonetrxmsg := &quorumpb.Trx{
@@ -2,7 +2,9 @@ package data
 
 import (
 	"testing"
+	"time"
 
+	rumerrors "github.com/rumsystem/quorum/internal/pkg/errors"
 	"github.com/rumsystem/quorum/internal/pkg/logging"
 	localcrypto "github.com/rumsystem/quorum/pkg/crypto"
 	quorumpb "github.com/rumsystem/quorum/pkg/pb"
@@ -14,15 +16,6 @@ var (
 	logger = logging.Logger("data")
 )
 
-type TestNonce struct {
-	nonce uint64
-}
-
-func (tn *TestNonce) GetNextNonce(groupId string, prefix ...string) (nonce uint64, err error) {
-	tn.nonce++
-	return tn.nonce, nil
-}
-
 func GetGroupItem() *quorumpb.GroupItem {
 	var group *quorumpb.GroupItem
 	group = &quorumpb.GroupItem{}
@@ -47,21 +40,16 @@ func GetKeyStorePubKey(groupid string, path string) (string, string, error) {
 
 func TestVerifyTrx(t *testing.T) {
 	keystoreDir := t.TempDir()
-	tn := &TestNonce{}
 	trxFactory := &TrxFactory{}
 	groupitem := GetGroupItem()
-	trxFactory.Init("1.0.0", groupitem, "default", tn)
+	trxFactory.Init("1.0.0", groupitem, "default")
 	addr, pubkey, err := GetKeyStorePubKey(groupitem.GroupId, keystoreDir)
 	logger.Debugf("new eth key addr:%s", addr)
 	if err != nil {
 		t.Errorf("keystore new key err : %s", err)
 	}
 	groupitem.UserSignPubkey = pubkey
-	obj := &quorumpb.Object{Type: "Note", Name: "test name 1", Content: "test content", Id: "0001"}
-	target := &quorumpb.Object{Id: groupitem.GroupId, Type: "Group"}
-	postobj := &quorumpb.Activity{Type: "Add", Object: obj, Target: target}
-
-	trx, err := trxFactory.GetPostAnyTrx("", postobj)
+	trx, err := trxFactory.GetPostAnyTrx("", []byte("test content"))
 	result, err := VerifyTrx(trx)
 	if result != true {
 		t.Errorf("verify trx sig with pubkey error")
@@ -70,23 +58,38 @@ func TestVerifyTrx(t *testing.T) {
 
 func TestVerifyTrxByAddress(t *testing.T) {
 	keystoreDir := t.TempDir()
-	tn := &TestNonce{}
 	trxFactory := &TrxFactory{}
 	groupitem := GetGroupItem()
-	trxFactory.Init("1.0.0", groupitem, "default", tn)
+	trxFactory.Init("1.0.0", groupitem, "default")
 	addr, _, err := GetKeyStorePubKey(groupitem.GroupId, keystoreDir)
 	logger.Debugf("new eth key addr:%s", addr)
 	if err != nil {
 		t.Errorf("keystore new key err : %s", err)
 	}
 	groupitem.UserSignPubkey = addr
-	obj := &quorumpb.Object{Type: "Note", Name: "test name 1", Content: "test content", Id: "0001"}
-	target := &quorumpb.Object{Id: groupitem.GroupId, Type: "Group"}
-	postobj := &quorumpb.Activity{Type: "Add", Object: obj, Target: target}
-
-	trx, err := trxFactory.GetPostAnyTrx("", postobj)
+	trx, err := trxFactory.GetPostAnyTrx("", []byte("test content"))
 	result, err := VerifyTrx(trx)
 	if result != true {
 		t.Errorf("verify trx sig with pubkey error:%s", err)
 	}
 }
+
+func TestCheckTrxTime(t *testing.T) {
+	now := time.Now()
+	for _, tc := range []struct {
+		name string
+		trx  *quorumpb.Trx
+		err  error
+	}{
+		{"valid", &quorumpb.Trx{TimeStamp: now.UnixNano(), Expired: now.Add(GetTrxLifetime()).UnixNano()}, nil},
+		{"expired", &quorumpb.Trx{TimeStamp: now.Add(-time.Minute).UnixNano(), Expired: now.Add(-time.Second).UnixNano()}, rumerrors.ErrTrxExpired},
+		{"without expired, in default lifetime", &quorumpb.Trx{TimeStamp: now.Add(-GetTrxLifetime() / 2).UnixNano()}, nil},
+		{"without expired, out of default lifetime", &quorumpb.Trx{TimeStamp: now.Add(-GetTrxLifetime() - time.Second).UnixNano()}, rumerrors.ErrTrxExpired},
+		{"clock skew allowed", &quorumpb.Trx{TimeStamp: now.Add(MAX_TRX_CLOCK_SKEW / 2).UnixNano(), Expired: now.Add(time.Hour).UnixNano()}, nil},
+		{"from future", &quorumpb.Trx{TimeStamp: now.Add(MAX_TRX_CLOCK_SKEW + time.Second).UnixNano(), Expired: now.Add(time.Hour).UnixNano()}, rumerrors.ErrTrxFromFuture},
+	} {
+		if err := CheckTrxTime(tc.trx, now); err != tc.err {
+			t.Errorf("%s: CheckTrxTime got <%v>, expect <%v>", tc.name, err, tc.err)
+		}
+	}
+}
@@ -49,6 +49,14 @@ func (factory *TrxFactory) GetRegProducerBundleTrx(keyalias string, item *quorum
 	return factory.CreateTrxByEthKey(quorumpb.TrxType_PRODUCER, encodedcontent, keyalias)
 }
 
+func (factory *TrxFactory) GetStakeTrx(keyalias string, item *quorumpb.StakeItem) (*quorumpb.Trx, error) {
+	encodedcontent, err := proto.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+	return factory.CreateTrxByEthKey(quorumpb.TrxType_STAKE, encodedcontent, keyalias)
+}
+
 func (factory *TrxFactory) GetRegUserTrx(keyalias string, item *quorumpb.UserItem) (*quorumpb.Trx, error) {
 	encodedcontent, err := proto.Marshal(item)
 	if err != nil {
@@ -103,6 +111,36 @@ func (factory *TrxFactory) GetReqBlocksRespTrx(keyalias string, groupId string,
 	return factory.CreateTrxByEthKey(quorumpb.TrxType_REQ_BLOCK_RESP, bItemBytes, keyalias)
 }
 
+func (factory *TrxFactory) GetReqSnapshotTrx(keyalias string, groupId string) (*quorumpb.Trx, error) {
+	reqSnapshotItem := &quorumpb.ReqSnapshot{
+		GroupId:   groupId,
+		ReqPubkey: factory.groupItem.UserSignPubkey,
+	}
+
+	bItemBytes, err := proto.Marshal(reqSnapshotItem)
+	if err != nil {
+		return nil, err
+	}
+
+	return factory.CreateTrxByEthKey(quorumpb.TrxType_REQ_SNAPSHOT, bItemBytes, keyalias)
+}
+
+func (factory *TrxFactory) GetReqSnapshotRespTrx(keyalias string, groupId string, requester string, snapshot *quorumpb.Snapshot) (*quorumpb.Trx, error) {
+	reqSnapshotRespItem := &quorumpb.ReqSnapshotResp{
+		GroupId:         groupId,
+		RequesterPubkey: requester,
+		ProviderPubkey:  factory.groupItem.UserSignPubkey,
+		Snapshot:        snapshot,
+	}
+
+	bItemBytes, err := proto.Marshal(reqSnapshotRespItem)
+	if err != nil {
+		return nil, err
+	}
+
+	return factory.CreateTrxByEthKey(quorumpb.TrxType_REQ_SNAPSHOT_RESP, bItemBytes, keyalias)
+}
+
 func (factory *TrxFactory) GetPostAnyTrx(keyalias string, content []byte, encryptto ...[]string) (*quorumpb.Trx, error) {
 	if _, err := IsTrxDataWithinSizeLimit(content); err != nil {
 		return nil, err
@@ -259,37 +259,46 @@ func (TrxStroageType) EnumDescriptor() ([]byte, []int) {
 type TrxType int32
 
 const (
-	TrxType_POST           TrxType = 0 // post to group
-	TrxType_ANNOUNCE       TrxType = 1 // producer or user self announce
-	TrxType_PRODUCER       TrxType = 2 // owner update group producer
-	TrxType_USER           TrxType = 3 // owner update group user
-	TrxType_REQ_BLOCK      TrxType = 4 // request block
-	TrxType_REQ_BLOCK_RESP TrxType = 5 // response request block
-	TrxType_CHAIN_CONFIG   TrxType = 6 // chain configuration
-	TrxType_APP_CONFIG     TrxType = 7 // app configuration
+	TrxType_POST              TrxType = 0  // post to group
+	TrxType_ANNOUNCE          TrxType = 1  // producer or user self announce
+	TrxType_PRODUCER          TrxType = 2  // owner update group producer
+	TrxType_USER              TrxType = 3  // owner update group user
+	TrxType_REQ_BLOCK         TrxType = 4  // request block
+	TrxType_REQ_BLOCK_RESP    TrxType = 5  // response request block
+	TrxType_CHAIN_CONFIG      TrxType = 6  // chain configuration
+	TrxType_APP_CONFIG        TrxType = 7  // app configuration
+	TrxType_STAKE             TrxType = 8  // producer update stake (pos group only)
+	TrxType_REQ_SNAPSHOT      TrxType = 9  // request latest snapshot
+	TrxType_REQ_SNAPSHOT_RESP TrxType = 10 // response request snapshot
 )
 
 // Enum value maps for TrxType.
 var (
 	TrxType_name = map[int32]string{
-		0: "POST",
-		1: "ANNOUNCE",
-		2: "PRODUCER",
-		3: "USER",
-		4: "REQ_BLOCK",
-		5: "REQ_BLOCK_RESP",
-		6: "CHAIN_CONFIG",
-		7: "APP_CONFIG",
+		0:  "POST",
+		1:  "ANNOUNCE",
+		2:  "PRODUCER",
+		3:  "USER",
+		4:  "REQ_BLOCK",
+		5:  "REQ_BLOCK_RESP",
+		6:  "CHAIN_CONFIG",
+		7:  "APP_CONFIG",
+		8:  "STAKE",
+		9:  "REQ_SNAPSHOT",
+		10: "REQ_SNAPSHOT_RESP",
 	}
 	TrxType_value = map[string]int32{
-		"POST":           0,
-		"ANNOUNCE":       1,
-		"PRODUCER":       2,
-		"USER":           3,
-		"REQ_BLOCK":      4,
-		"REQ_BLOCK_RESP": 5,
-		"CHAIN_CONFIG":   6,
-		"APP_CONFIG":     7,
+		"POST":              0,
+		"ANNOUNCE":          1,
+		"PRODUCER":          2,
+		"USER":              3,
+		"REQ_BLOCK":         4,
+		"REQ_BLOCK_RESP":    5,
+		"CHAIN_CONFIG":      6,
+		"APP_CONFIG":        7,
+		"STAKE":             8,
+		"REQ_SNAPSHOT":      9,
+		"REQ_SNAPSHOT_RESP": 10,
 	}
 )
 
@@ -510,9 +519,11 @@ func (RoleV0) EnumDescriptor() ([]byte, []int) {
 type ChainConfigType int32
 
 const (
-	ChainConfigType_SET_TRX_AUTH_MODE ChainConfigType = 0
-	ChainConfigType_UPD_DNY_LIST      ChainConfigType = 1
-	ChainConfigType_UPD_ALW_LIST      ChainConfigType = 2
+	ChainConfigType_SET_TRX_AUTH_MODE  ChainConfigType = 0
+	ChainConfigType_UPD_DNY_LIST       ChainConfigType = 1
+	ChainConfigType_UPD_ALW_LIST       ChainConfigType = 2
+	ChainConfigType_SET_MEMPOOL_POLICY ChainConfigType = 3
+	ChainConfigType_SET_EPOCH_PACING   ChainConfigType = 4
 )
 
 // Enum value maps for ChainConfigType.
@@ -521,11 +532,15 @@ var (
 		0: "SET_TRX_AUTH_MODE",
 		1: "UPD_DNY_LIST",
 		2: "UPD_ALW_LIST",
+		3: "SET_MEMPOOL_POLICY",
+		4: "SET_EPOCH_PACING",
 	}
 	ChainConfigType_value = map[string]int32{
-		"SET_TRX_AUTH_MODE": 0,
-		"UPD_DNY_LIST":      1,
-		"UPD_ALW_LIST":      2,
+		"SET_TRX_AUTH_MODE":  0,
+		"UPD_DNY_LIST":       1,
+		"UPD_ALW_LIST":       2,
+		"SET_MEMPOOL_POLICY": 3,
+		"SET_EPOCH_PACING":   4,
 	}
 )
 
@@ -702,6 +717,8 @@ type HBMsgPayloadType int32
 const (
 	HBMsgPayloadType_RBC HBMsgPayloadType = 0
 	HBMsgPayloadType_BBA HBMsgPayloadType = 1
+	HBMsgPayloadType_DEC HBMsgPayloadType = 2 // decryption share of an encrypted proposal
+	HBMsgPayloadType_DKG HBMsgPayloadType = 3 // threshold key generation among producers
 )
 
 // Enum value maps for HBMsgPayloadType.
@@ -709,10 +726,14 @@ var (
 	HBMsgPayloadType_name = map[int32]string{
 		0: "RBC",
 		1: "BBA",
+		2: "DEC",
+		3: "DKG",
 	}
 	HBMsgPayloadType_value = map[string]int32{
 		"RBC": 0,
 		"BBA": 1,
+		"DEC": 2,
+		"DKG": 3,
 	}
 )
 
@@ -797,6 +818,8 @@ type BBAMsgType int32
 const (
 	BBAMsgType_BVAL BBAMsgType = 0
 	BBAMsgType_AUX  BBAMsgType = 1
+	BBAMsgType_COIN BBAMsgType = 2
+	BBAMsgType_CONF BBAMsgType = 3
 )
 
 // Enum value maps for BBAMsgType.
@@ -804,10 +827,14 @@ var (
 	BBAMsgType_name = map[int32]string{
 		0: "BVAL",
 		1: "AUX",
+		2: "COIN",
+		3: "CONF",
 	}
 	BBAMsgType_value = map[string]int32{
 		"BVAL": 0,
 		"AUX":  1,
+		"COIN": 2,
+		"CONF": 3,
 	}
 )
 
@@ -838,6 +865,58 @@ func (BBAMsgType) EnumDescriptor() ([]byte, []int) {
 	return file_chain_proto_rawDescGZIP(), []int{16}
 }
 
+type DKGMsgType int32
+
+const (
+	DKGMsgType_DKG_KEY  DKGMsgType = 0 //dh pubkey used to encrypt shares
+	DKGMsgType_DKG_DEAL DKGMsgType = 1 //commitments and encrypted shares of a dealer
+	DKGMsgType_DKG_ACK  DKGMsgType = 2 //group pubkey generated by the sender
+	DKGMsgType_DKG_ECHO DKGMsgType = 3 //deal of a dealer received by the sender
+)
+
+// Enum value maps for DKGMsgType.
+var (
+	DKGMsgType_name = map[int32]string{
+		0: "DKG_KEY",
+		1: "DKG_DEAL",
+		2: "DKG_ACK",
+		3: "DKG_ECHO",
+	}
+	DKGMsgType_value = map[string]int32{
+		"DKG_KEY":  0,
+		"DKG_DEAL": 1,
+		"DKG_ACK":  2,
+		"DKG_ECHO": 3,
+	}
+)
+
+func (x DKGMsgType) Enum() *DKGMsgType {
+	p := new(DKGMsgType)
+	*p = x
+	return p
+}
+
+func (x DKGMsgType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DKGMsgType) Descriptor() protoreflect.EnumDescriptor {
+	return file_chain_proto_enumTypes[17].Descriptor()
+}
+
+func (DKGMsgType) Type() protoreflect.EnumType {
+	return &file_chain_proto_enumTypes[17]
+}
+
+func (x DKGMsgType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DKGMsgType.Descriptor instead.
+func (DKGMsgType) EnumDescriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{17}
+}
+
 type Package struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1035,6 +1114,7 @@ type Block struct {
 	TimeStamp      int64  `protobuf:"varint,8,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty,string"`
 	BlockHash      []byte `protobuf:"bytes,9,opt,name=BlockHash,proto3" json:"BlockHash,omitempty"`
 	ProducerSign   []byte `protobuf:"bytes,10,opt,name=ProducerSign,proto3" json:"ProducerSign,omitempty"`
+	TrxsRoot       []byte `protobuf:"bytes,11,opt,name=TrxsRoot,proto3" json:"TrxsRoot,omitempty"` //hash of trx hashes, block hash covers it instead of trxs, empty for old blocks
 }
 
 func (x *Block) Reset() {
@@ -1139,6 +1219,13 @@ func (x *Block) GetProducerSign() []byte {
 	return nil
 }
 
+func (x *Block) GetTrxsRoot() []byte {
+	if x != nil {
+		return x.TrxsRoot
+	}
+	return nil
+}
+
 type ReqBlock struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1360,19 +1447,17 @@ func (x *ReqBlockResp) GetBlocks() *BlocksBundle {
 	return nil
 }
 
-type PostItem struct {
+type SnapshotItem struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	TrxId        string `protobuf:"bytes,1,opt,name=TrxId,proto3" json:"TrxId,omitempty"`
-	SenderPubkey string `protobuf:"bytes,2,opt,name=SenderPubkey,proto3" json:"SenderPubkey,omitempty"`
-	Content      []byte `protobuf:"bytes,3,opt,name=Content,proto3" json:"Content,omitempty"`
-	TimeStamp    int64  `protobuf:"varint,4,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty,string"`
+	Key   string `protobuf:"bytes,1,opt,name=Key,proto3" json:"Key,omitempty"` //db key without node prefix
+	Value []byte `protobuf:"bytes,2,opt,name=Value,proto3" json:"Value,omitempty"`
 }
 
-func (x *PostItem) Reset() {
-	*x = PostItem{}
+func (x *SnapshotItem) Reset() {
+	*x = SnapshotItem{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_chain_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1380,13 +1465,13 @@ func (x *PostItem) Reset() {
 	}
 }
 
-func (x *PostItem) String() string {
+func (x *SnapshotItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PostItem) ProtoMessage() {}
+func (*SnapshotItem) ProtoMessage() {}
 
-func (x *PostItem) ProtoReflect() protoreflect.Message {
+func (x *SnapshotItem) ProtoReflect() protoreflect.Message {
 	mi := &file_chain_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1398,56 +1483,41 @@ func (x *PostItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PostItem.ProtoReflect.Descriptor instead.
-func (*PostItem) Descriptor() ([]byte, []int) {
+// Deprecated: Use SnapshotItem.ProtoReflect.Descriptor instead.
+func (*SnapshotItem) Descriptor() ([]byte, []int) {
 	return file_chain_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *PostItem) GetTrxId() string {
-	if x != nil {
-		return x.TrxId
-	}
-	return ""
-}
-
-func (x *PostItem) GetSenderPubkey() string {
+func (x *SnapshotItem) GetKey() string {
 	if x != nil {
-		return x.SenderPubkey
+		return x.Key
 	}
 	return ""
 }
 
-func (x *PostItem) GetContent() []byte {
+func (x *SnapshotItem) GetValue() []byte {
 	if x != nil {
-		return x.Content
+		return x.Value
 	}
 	return nil
 }
 
-func (x *PostItem) GetTimeStamp() int64 {
-	if x != nil {
-		return x.TimeStamp
-	}
-	return 0
-}
-
-type ProducerItem struct {
+type Snapshot struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GroupId          string     `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
-	ProducerPubkey   string     `protobuf:"bytes,2,opt,name=ProducerPubkey,proto3" json:"ProducerPubkey,omitempty"`
-	GroupOwnerPubkey string     `protobuf:"bytes,3,opt,name=GroupOwnerPubkey,proto3" json:"GroupOwnerPubkey,omitempty"`
-	GroupOwnerSign   string     `protobuf:"bytes,4,opt,name=GroupOwnerSign,proto3" json:"GroupOwnerSign,omitempty"`
-	Action           ActionType `protobuf:"varint,5,opt,name=Action,proto3,enum=quorum.pb.ActionType" json:"Action,omitempty"`
-	WithnessBlocks   int64      `protobuf:"varint,6,opt,name=WithnessBlocks,proto3" json:"WithnessBlocks,omitempty"`
-	TimeStamp        int64      `protobuf:"varint,7,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty,string"`
-	Memo             string     `protobuf:"bytes,8,opt,name=Memo,proto3" json:"Memo,omitempty"`
+	GroupId      string          `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	Epoch        uint64          `protobuf:"varint,2,opt,name=Epoch,proto3" json:"Epoch,omitempty"`
+	Block        *Block          `protobuf:"bytes,3,opt,name=Block,proto3" json:"Block,omitempty"` //the last block applied when snapshot created
+	Items        []*SnapshotItem `protobuf:"bytes,4,rep,name=Items,proto3" json:"Items,omitempty"`
+	TimeStamp    int64           `protobuf:"varint,5,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty,string"`
+	SenderPubkey string          `protobuf:"bytes,6,opt,name=SenderPubkey,proto3" json:"SenderPubkey,omitempty"`
+	SenderSign   []byte          `protobuf:"bytes,7,opt,name=SenderSign,proto3" json:"SenderSign,omitempty"`
 }
 
-func (x *ProducerItem) Reset() {
-	*x = ProducerItem{}
+func (x *Snapshot) Reset() {
+	*x = Snapshot{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_chain_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1455,13 +1525,13 @@ func (x *ProducerItem) Reset() {
 	}
 }
 
-func (x *ProducerItem) String() string {
+func (x *Snapshot) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProducerItem) ProtoMessage() {}
+func (*Snapshot) ProtoMessage() {}
 
-func (x *ProducerItem) ProtoReflect() protoreflect.Message {
+func (x *Snapshot) ProtoReflect() protoreflect.Message {
 	mi := &file_chain_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1473,77 +1543,71 @@ func (x *ProducerItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProducerItem.ProtoReflect.Descriptor instead.
-func (*ProducerItem) Descriptor() ([]byte, []int) {
+// Deprecated: Use Snapshot.ProtoReflect.Descriptor instead.
+func (*Snapshot) Descriptor() ([]byte, []int) {
 	return file_chain_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *ProducerItem) GetGroupId() string {
+func (x *Snapshot) GetGroupId() string {
 	if x != nil {
 		return x.GroupId
 	}
 	return ""
 }
 
-func (x *ProducerItem) GetProducerPubkey() string {
-	if x != nil {
-		return x.ProducerPubkey
-	}
-	return ""
-}
-
-func (x *ProducerItem) GetGroupOwnerPubkey() string {
+func (x *Snapshot) GetEpoch() uint64 {
 	if x != nil {
-		return x.GroupOwnerPubkey
+		return x.Epoch
 	}
-	return ""
+	return 0
 }
 
-func (x *ProducerItem) GetGroupOwnerSign() string {
+func (x *Snapshot) GetBlock() *Block {
 	if x != nil {
-		return x.GroupOwnerSign
+		return x.Block
 	}
-	return ""
+	return nil
 }
 
-func (x *ProducerItem) GetAction() ActionType {
+func (x *Snapshot) GetItems() []*SnapshotItem {
 	if x != nil {
-		return x.Action
+		return x.Items
 	}
-	return ActionType_ADD
+	return nil
 }
 
-func (x *ProducerItem) GetWithnessBlocks() int64 {
+func (x *Snapshot) GetTimeStamp() int64 {
 	if x != nil {
-		return x.WithnessBlocks
+		return x.TimeStamp
 	}
 	return 0
 }
 
-func (x *ProducerItem) GetTimeStamp() int64 {
+func (x *Snapshot) GetSenderPubkey() string {
 	if x != nil {
-		return x.TimeStamp
+		return x.SenderPubkey
 	}
-	return 0
+	return ""
 }
 
-func (x *ProducerItem) GetMemo() string {
+func (x *Snapshot) GetSenderSign() []byte {
 	if x != nil {
-		return x.Memo
+		return x.SenderSign
 	}
-	return ""
+	return nil
 }
 
-type BFTProducerBundleItem struct {
+type ReqSnapshot struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Producers []*ProducerItem `protobuf:"bytes,1,rep,name=Producers,proto3" json:"Producers,omitempty"`
+	GroupId   string `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	ReqPubkey string `protobuf:"bytes,2,opt,name=ReqPubkey,proto3" json:"ReqPubkey,omitempty"` //requester pubkey
 }
 
-func (x *BFTProducerBundleItem) Reset() {
-	*x = BFTProducerBundleItem{}
+func (x *ReqSnapshot) Reset() {
+	*x = ReqSnapshot{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_chain_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1551,13 +1615,13 @@ func (x *BFTProducerBundleItem) Reset() {
 	}
 }
 
-func (x *BFTProducerBundleItem) String() string {
+func (x *ReqSnapshot) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BFTProducerBundleItem) ProtoMessage() {}
+func (*ReqSnapshot) ProtoMessage() {}
 
-func (x *BFTProducerBundleItem) ProtoReflect() protoreflect.Message {
+func (x *ReqSnapshot) ProtoReflect() protoreflect.Message {
 	mi := &file_chain_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1569,35 +1633,38 @@ func (x *BFTProducerBundleItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BFTProducerBundleItem.ProtoReflect.Descriptor instead.
-func (*BFTProducerBundleItem) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReqSnapshot.ProtoReflect.Descriptor instead.
+func (*ReqSnapshot) Descriptor() ([]byte, []int) {
 	return file_chain_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *BFTProducerBundleItem) GetProducers() []*ProducerItem {
+func (x *ReqSnapshot) GetGroupId() string {
 	if x != nil {
-		return x.Producers
+		return x.GroupId
 	}
-	return nil
+	return ""
 }
 
-type UserItem struct {
+func (x *ReqSnapshot) GetReqPubkey() string {
+	if x != nil {
+		return x.ReqPubkey
+	}
+	return ""
+}
+
+type ReqSnapshotResp struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GroupId          string     `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
-	UserPubkey       string     `protobuf:"bytes,2,opt,name=UserPubkey,proto3" json:"UserPubkey,omitempty"`
-	EncryptPubkey    string     `protobuf:"bytes,3,opt,name=EncryptPubkey,proto3" json:"EncryptPubkey,omitempty"`
-	GroupOwnerPubkey string     `protobuf:"bytes,4,opt,name=GroupOwnerPubkey,proto3" json:"GroupOwnerPubkey,omitempty"`
-	GroupOwnerSign   string     `protobuf:"bytes,5,opt,name=GroupOwnerSign,proto3" json:"GroupOwnerSign,omitempty"`
-	TimeStamp        int64      `protobuf:"varint,6,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty,string"`
-	Action           ActionType `protobuf:"varint,7,opt,name=Action,proto3,enum=quorum.pb.ActionType" json:"Action,omitempty"`
-	Memo             string     `protobuf:"bytes,8,opt,name=Memo,proto3" json:"Memo,omitempty"`
+	GroupId         string    `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	RequesterPubkey string    `protobuf:"bytes,2,opt,name=RequesterPubkey,proto3" json:"RequesterPubkey,omitempty"`
+	ProviderPubkey  string    `protobuf:"bytes,3,opt,name=ProviderPubkey,proto3" json:"ProviderPubkey,omitempty"`
+	Snapshot        *Snapshot `protobuf:"bytes,4,opt,name=Snapshot,proto3" json:"Snapshot,omitempty"`
 }
 
-func (x *UserItem) Reset() {
-	*x = UserItem{}
+func (x *ReqSnapshotResp) Reset() {
+	*x = ReqSnapshotResp{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_chain_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1605,13 +1672,13 @@ func (x *UserItem) Reset() {
 	}
 }
 
-func (x *UserItem) String() string {
+func (x *ReqSnapshotResp) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserItem) ProtoMessage() {}
+func (*ReqSnapshotResp) ProtoMessage() {}
 
-func (x *UserItem) ProtoReflect() protoreflect.Message {
+func (x *ReqSnapshotResp) ProtoReflect() protoreflect.Message {
 	mi := &file_chain_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1623,87 +1690,52 @@ func (x *UserItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserItem.ProtoReflect.Descriptor instead.
-func (*UserItem) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReqSnapshotResp.ProtoReflect.Descriptor instead.
+func (*ReqSnapshotResp) Descriptor() ([]byte, []int) {
 	return file_chain_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *UserItem) GetGroupId() string {
+func (x *ReqSnapshotResp) GetGroupId() string {
 	if x != nil {
 		return x.GroupId
 	}
 	return ""
 }
 
-func (x *UserItem) GetUserPubkey() string {
-	if x != nil {
-		return x.UserPubkey
-	}
-	return ""
-}
-
-func (x *UserItem) GetEncryptPubkey() string {
-	if x != nil {
-		return x.EncryptPubkey
-	}
-	return ""
-}
-
-func (x *UserItem) GetGroupOwnerPubkey() string {
+func (x *ReqSnapshotResp) GetRequesterPubkey() string {
 	if x != nil {
-		return x.GroupOwnerPubkey
+		return x.RequesterPubkey
 	}
 	return ""
 }
 
-func (x *UserItem) GetGroupOwnerSign() string {
+func (x *ReqSnapshotResp) GetProviderPubkey() string {
 	if x != nil {
-		return x.GroupOwnerSign
+		return x.ProviderPubkey
 	}
 	return ""
 }
 
-func (x *UserItem) GetTimeStamp() int64 {
-	if x != nil {
-		return x.TimeStamp
-	}
-	return 0
-}
-
-func (x *UserItem) GetAction() ActionType {
-	if x != nil {
-		return x.Action
-	}
-	return ActionType_ADD
-}
-
-func (x *UserItem) GetMemo() string {
+func (x *ReqSnapshotResp) GetSnapshot() *Snapshot {
 	if x != nil {
-		return x.Memo
+		return x.Snapshot
 	}
-	return ""
+	return nil
 }
 
-type AnnounceItem struct {
+type PostItem struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GroupId            string       `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
-	SignPubkey         string       `protobuf:"bytes,2,opt,name=SignPubkey,proto3" json:"SignPubkey,omitempty"`
-	EncryptPubkey      string       `protobuf:"bytes,3,opt,name=EncryptPubkey,proto3" json:"EncryptPubkey,omitempty"`
-	AnnouncerSignature string       `protobuf:"bytes,4,opt,name=AnnouncerSignature,proto3" json:"AnnouncerSignature,omitempty"`
-	Type               AnnounceType `protobuf:"varint,5,opt,name=Type,proto3,enum=quorum.pb.AnnounceType" json:"Type,omitempty"`
-	OwnerPubkey        string       `protobuf:"bytes,6,opt,name=OwnerPubkey,proto3" json:"OwnerPubkey,omitempty"`
-	OwnerSignature     string       `protobuf:"bytes,7,opt,name=OwnerSignature,proto3" json:"OwnerSignature,omitempty"`
-	Result             ApproveType  `protobuf:"varint,8,opt,name=Result,proto3,enum=quorum.pb.ApproveType" json:"Result,omitempty"`
-	TimeStamp          int64        `protobuf:"varint,9,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty,string"`
-	Action             ActionType   `protobuf:"varint,10,opt,name=Action,proto3,enum=quorum.pb.ActionType" json:"Action,omitempty"`
-	Memo               string       `protobuf:"bytes,11,opt,name=Memo,proto3" json:"Memo,omitempty"`
+	TrxId        string `protobuf:"bytes,1,opt,name=TrxId,proto3" json:"TrxId,omitempty"`
+	SenderPubkey string `protobuf:"bytes,2,opt,name=SenderPubkey,proto3" json:"SenderPubkey,omitempty"`
+	Content      []byte `protobuf:"bytes,3,opt,name=Content,proto3" json:"Content,omitempty"`
+	TimeStamp    int64  `protobuf:"varint,4,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty,string"`
 }
 
-func (x *AnnounceItem) Reset() {
-	*x = AnnounceItem{}
+func (x *PostItem) Reset() {
+	*x = PostItem{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_chain_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1711,13 +1743,13 @@ func (x *AnnounceItem) Reset() {
 	}
 }
 
-func (x *AnnounceItem) String() string {
+func (x *PostItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AnnounceItem) ProtoMessage() {}
+func (*PostItem) ProtoMessage() {}
 
-func (x *AnnounceItem) ProtoReflect() protoreflect.Message {
+func (x *PostItem) ProtoReflect() protoreflect.Message {
 	mi := &file_chain_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1729,108 +1761,56 @@ func (x *AnnounceItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AnnounceItem.ProtoReflect.Descriptor instead.
-func (*AnnounceItem) Descriptor() ([]byte, []int) {
+// Deprecated: Use PostItem.ProtoReflect.Descriptor instead.
+func (*PostItem) Descriptor() ([]byte, []int) {
 	return file_chain_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *AnnounceItem) GetGroupId() string {
+func (x *PostItem) GetTrxId() string {
 	if x != nil {
-		return x.GroupId
+		return x.TrxId
 	}
 	return ""
 }
 
-func (x *AnnounceItem) GetSignPubkey() string {
+func (x *PostItem) GetSenderPubkey() string {
 	if x != nil {
-		return x.SignPubkey
+		return x.SenderPubkey
 	}
 	return ""
 }
 
-func (x *AnnounceItem) GetEncryptPubkey() string {
+func (x *PostItem) GetContent() []byte {
 	if x != nil {
-		return x.EncryptPubkey
+		return x.Content
 	}
-	return ""
+	return nil
 }
 
-func (x *AnnounceItem) GetAnnouncerSignature() string {
+func (x *PostItem) GetTimeStamp() int64 {
 	if x != nil {
-		return x.AnnouncerSignature
+		return x.TimeStamp
 	}
-	return ""
+	return 0
 }
 
-func (x *AnnounceItem) GetType() AnnounceType {
-	if x != nil {
-		return x.Type
-	}
-	return AnnounceType_AS_USER
+type ProducerItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupId          string     `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	ProducerPubkey   string     `protobuf:"bytes,2,opt,name=ProducerPubkey,proto3" json:"ProducerPubkey,omitempty"`
+	GroupOwnerPubkey string     `protobuf:"bytes,3,opt,name=GroupOwnerPubkey,proto3" json:"GroupOwnerPubkey,omitempty"`
+	GroupOwnerSign   string     `protobuf:"bytes,4,opt,name=GroupOwnerSign,proto3" json:"GroupOwnerSign,omitempty"`
+	Action           ActionType `protobuf:"varint,5,opt,name=Action,proto3,enum=quorum.pb.ActionType" json:"Action,omitempty"`
+	WithnessBlocks   int64      `protobuf:"varint,6,opt,name=WithnessBlocks,proto3" json:"WithnessBlocks,omitempty"`
+	TimeStamp        int64      `protobuf:"varint,7,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty,string"`
+	Memo             string     `protobuf:"bytes,8,opt,name=Memo,proto3" json:"Memo,omitempty"`
 }
 
-func (x *AnnounceItem) GetOwnerPubkey() string {
-	if x != nil {
-		return x.OwnerPubkey
-	}
-	return ""
-}
-
-func (x *AnnounceItem) GetOwnerSignature() string {
-	if x != nil {
-		return x.OwnerSignature
-	}
-	return ""
-}
-
-func (x *AnnounceItem) GetResult() ApproveType {
-	if x != nil {
-		return x.Result
-	}
-	return ApproveType_ANNOUNCED
-}
-
-func (x *AnnounceItem) GetTimeStamp() int64 {
-	if x != nil {
-		return x.TimeStamp
-	}
-	return 0
-}
-
-func (x *AnnounceItem) GetAction() ActionType {
-	if x != nil {
-		return x.Action
-	}
-	return ActionType_ADD
-}
-
-func (x *AnnounceItem) GetMemo() string {
-	if x != nil {
-		return x.Memo
-	}
-	return ""
-}
-
-type GroupItem struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	GroupId           string            `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
-	GroupName         string            `protobuf:"bytes,2,opt,name=GroupName,proto3" json:"GroupName,omitempty"`
-	OwnerPubKey       string            `protobuf:"bytes,3,opt,name=OwnerPubKey,proto3" json:"OwnerPubKey,omitempty"`
-	UserSignPubkey    string            `protobuf:"bytes,4,opt,name=UserSignPubkey,proto3" json:"UserSignPubkey,omitempty"`
-	UserEncryptPubkey string            `protobuf:"bytes,5,opt,name=UserEncryptPubkey,proto3" json:"UserEncryptPubkey,omitempty"`
-	LastUpdate        int64             `protobuf:"varint,6,opt,name=LastUpdate,proto3" json:"LastUpdate,omitempty"`
-	GenesisBlock      *Block            `protobuf:"bytes,7,opt,name=GenesisBlock,proto3" json:"GenesisBlock,omitempty"`
-	EncryptType       GroupEncryptType  `protobuf:"varint,8,opt,name=EncryptType,proto3,enum=quorum.pb.GroupEncryptType" json:"EncryptType,omitempty"`
-	ConsenseType      GroupConsenseType `protobuf:"varint,9,opt,name=ConsenseType,proto3,enum=quorum.pb.GroupConsenseType" json:"ConsenseType,omitempty"`
-	CipherKey         string            `protobuf:"bytes,10,opt,name=CipherKey,proto3" json:"CipherKey,omitempty"`
-	AppKey            string            `protobuf:"bytes,11,opt,name=AppKey,proto3" json:"AppKey,omitempty"`
-}
-
-func (x *GroupItem) Reset() {
-	*x = GroupItem{}
+func (x *ProducerItem) Reset() {
+	*x = ProducerItem{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_chain_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1838,13 +1818,13 @@ func (x *GroupItem) Reset() {
 	}
 }
 
-func (x *GroupItem) String() string {
+func (x *ProducerItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GroupItem) ProtoMessage() {}
+func (*ProducerItem) ProtoMessage() {}
 
-func (x *GroupItem) ProtoReflect() protoreflect.Message {
+func (x *ProducerItem) ProtoReflect() protoreflect.Message {
 	mi := &file_chain_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1856,119 +1836,144 @@ func (x *GroupItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GroupItem.ProtoReflect.Descriptor instead.
-func (*GroupItem) Descriptor() ([]byte, []int) {
+// Deprecated: Use ProducerItem.ProtoReflect.Descriptor instead.
+func (*ProducerItem) Descriptor() ([]byte, []int) {
 	return file_chain_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *GroupItem) GetGroupId() string {
+func (x *ProducerItem) GetGroupId() string {
 	if x != nil {
 		return x.GroupId
 	}
 	return ""
 }
 
-func (x *GroupItem) GetGroupName() string {
+func (x *ProducerItem) GetProducerPubkey() string {
 	if x != nil {
-		return x.GroupName
+		return x.ProducerPubkey
 	}
 	return ""
 }
 
-func (x *GroupItem) GetOwnerPubKey() string {
+func (x *ProducerItem) GetGroupOwnerPubkey() string {
 	if x != nil {
-		return x.OwnerPubKey
+		return x.GroupOwnerPubkey
 	}
 	return ""
 }
 
-func (x *GroupItem) GetUserSignPubkey() string {
+func (x *ProducerItem) GetGroupOwnerSign() string {
 	if x != nil {
-		return x.UserSignPubkey
+		return x.GroupOwnerSign
 	}
 	return ""
 }
 
-func (x *GroupItem) GetUserEncryptPubkey() string {
+func (x *ProducerItem) GetAction() ActionType {
 	if x != nil {
-		return x.UserEncryptPubkey
+		return x.Action
 	}
-	return ""
+	return ActionType_ADD
 }
 
-func (x *GroupItem) GetLastUpdate() int64 {
+func (x *ProducerItem) GetWithnessBlocks() int64 {
 	if x != nil {
-		return x.LastUpdate
+		return x.WithnessBlocks
 	}
 	return 0
 }
 
-func (x *GroupItem) GetGenesisBlock() *Block {
+func (x *ProducerItem) GetTimeStamp() int64 {
 	if x != nil {
-		return x.GenesisBlock
+		return x.TimeStamp
 	}
-	return nil
+	return 0
 }
 
-func (x *GroupItem) GetEncryptType() GroupEncryptType {
+func (x *ProducerItem) GetMemo() string {
 	if x != nil {
-		return x.EncryptType
+		return x.Memo
 	}
-	return GroupEncryptType_PUBLIC
+	return ""
 }
 
-func (x *GroupItem) GetConsenseType() GroupConsenseType {
-	if x != nil {
-		return x.ConsenseType
+type BFTProducerBundleItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Producers []*ProducerItem `protobuf:"bytes,1,rep,name=Producers,proto3" json:"Producers,omitempty"`
+}
+
+func (x *BFTProducerBundleItem) Reset() {
+	*x = BFTProducerBundleItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return GroupConsenseType_POA
 }
 
-func (x *GroupItem) GetCipherKey() string {
-	if x != nil {
-		return x.CipherKey
+func (x *BFTProducerBundleItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BFTProducerBundleItem) ProtoMessage() {}
+
+func (x *BFTProducerBundleItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *GroupItem) GetAppKey() string {
+// Deprecated: Use BFTProducerBundleItem.ProtoReflect.Descriptor instead.
+func (*BFTProducerBundleItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BFTProducerBundleItem) GetProducers() []*ProducerItem {
 	if x != nil {
-		return x.AppKey
+		return x.Producers
 	}
-	return ""
+	return nil
 }
 
-type ChainConfigItem struct {
+type StakeItem struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GroupId        string          `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
-	Type           ChainConfigType `protobuf:"varint,2,opt,name=Type,proto3,enum=quorum.pb.ChainConfigType" json:"Type,omitempty"`
-	Data           []byte          `protobuf:"bytes,3,opt,name=Data,proto3" json:"Data,omitempty"`
-	OwnerPubkey    string          `protobuf:"bytes,4,opt,name=OwnerPubkey,proto3" json:"OwnerPubkey,omitempty"`
-	OwnerSignature string          `protobuf:"bytes,5,opt,name=OwnerSignature,proto3" json:"OwnerSignature,omitempty"`
-	TimeStamp      int64           `protobuf:"varint,6,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty,string"`
-	Memo           string          `protobuf:"bytes,7,opt,name=Memo,proto3" json:"Memo,omitempty"`
+	GroupId      string     `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	StakerPubkey string     `protobuf:"bytes,2,opt,name=StakerPubkey,proto3" json:"StakerPubkey,omitempty"`
+	Action       ActionType `protobuf:"varint,3,opt,name=Action,proto3,enum=quorum.pb.ActionType" json:"Action,omitempty"` //ADD to increase stake, REMOVE to withdraw
+	Amount       int64      `protobuf:"varint,4,opt,name=Amount,proto3" json:"Amount,omitempty"`                           //amount in trx, total stake when saved
+	TimeStamp    int64      `protobuf:"varint,5,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty,string"`
+	Memo         string     `protobuf:"bytes,6,opt,name=Memo,proto3" json:"Memo,omitempty"`
 }
 
-func (x *ChainConfigItem) Reset() {
-	*x = ChainConfigItem{}
+func (x *StakeItem) Reset() {
+	*x = StakeItem{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[12]
+		mi := &file_chain_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ChainConfigItem) String() string {
+func (x *StakeItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChainConfigItem) ProtoMessage() {}
+func (*StakeItem) ProtoMessage() {}
 
-func (x *ChainConfigItem) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[12]
+func (x *StakeItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1979,87 +1984,85 @@ func (x *ChainConfigItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChainConfigItem.ProtoReflect.Descriptor instead.
-func (*ChainConfigItem) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use StakeItem.ProtoReflect.Descriptor instead.
+func (*StakeItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *ChainConfigItem) GetGroupId() string {
+func (x *StakeItem) GetGroupId() string {
 	if x != nil {
 		return x.GroupId
 	}
 	return ""
 }
 
-func (x *ChainConfigItem) GetType() ChainConfigType {
-	if x != nil {
-		return x.Type
-	}
-	return ChainConfigType_SET_TRX_AUTH_MODE
-}
-
-func (x *ChainConfigItem) GetData() []byte {
+func (x *StakeItem) GetStakerPubkey() string {
 	if x != nil {
-		return x.Data
+		return x.StakerPubkey
 	}
-	return nil
+	return ""
 }
 
-func (x *ChainConfigItem) GetOwnerPubkey() string {
+func (x *StakeItem) GetAction() ActionType {
 	if x != nil {
-		return x.OwnerPubkey
+		return x.Action
 	}
-	return ""
+	return ActionType_ADD
 }
 
-func (x *ChainConfigItem) GetOwnerSignature() string {
+func (x *StakeItem) GetAmount() int64 {
 	if x != nil {
-		return x.OwnerSignature
+		return x.Amount
 	}
-	return ""
+	return 0
 }
 
-func (x *ChainConfigItem) GetTimeStamp() int64 {
+func (x *StakeItem) GetTimeStamp() int64 {
 	if x != nil {
 		return x.TimeStamp
 	}
 	return 0
 }
 
-func (x *ChainConfigItem) GetMemo() string {
+func (x *StakeItem) GetMemo() string {
 	if x != nil {
 		return x.Memo
 	}
 	return ""
 }
 
-type ChainSendTrxRuleListItem struct {
+type UserItem struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Action ActionType `protobuf:"varint,1,opt,name=Action,proto3,enum=quorum.pb.ActionType" json:"Action,omitempty"`
-	Pubkey string     `protobuf:"bytes,3,opt,name=Pubkey,proto3" json:"Pubkey,omitempty"`
-	Type   []TrxType  `protobuf:"varint,4,rep,packed,name=Type,proto3,enum=quorum.pb.TrxType" json:"Type,omitempty"`
+	GroupId          string     `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	UserPubkey       string     `protobuf:"bytes,2,opt,name=UserPubkey,proto3" json:"UserPubkey,omitempty"`
+	EncryptPubkey    string     `protobuf:"bytes,3,opt,name=EncryptPubkey,proto3" json:"EncryptPubkey,omitempty"`
+	GroupOwnerPubkey string     `protobuf:"bytes,4,opt,name=GroupOwnerPubkey,proto3" json:"GroupOwnerPubkey,omitempty"`
+	GroupOwnerSign   string     `protobuf:"bytes,5,opt,name=GroupOwnerSign,proto3" json:"GroupOwnerSign,omitempty"`
+	TimeStamp        int64      `protobuf:"varint,6,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty,string"`
+	Action           ActionType `protobuf:"varint,7,opt,name=Action,proto3,enum=quorum.pb.ActionType" json:"Action,omitempty"`
+	Memo             string     `protobuf:"bytes,8,opt,name=Memo,proto3" json:"Memo,omitempty"`
 }
 
-func (x *ChainSendTrxRuleListItem) Reset() {
-	*x = ChainSendTrxRuleListItem{}
+func (x *UserItem) Reset() {
+	*x = UserItem{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[13]
+		mi := &file_chain_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ChainSendTrxRuleListItem) String() string {
+func (x *UserItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChainSendTrxRuleListItem) ProtoMessage() {}
+func (*UserItem) ProtoMessage() {}
 
-func (x *ChainSendTrxRuleListItem) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[13]
+func (x *UserItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2070,58 +2073,1122 @@ func (x *ChainSendTrxRuleListItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChainSendTrxRuleListItem.ProtoReflect.Descriptor instead.
-func (*ChainSendTrxRuleListItem) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use UserItem.ProtoReflect.Descriptor instead.
+func (*UserItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *ChainSendTrxRuleListItem) GetAction() ActionType {
+func (x *UserItem) GetGroupId() string {
 	if x != nil {
-		return x.Action
+		return x.GroupId
 	}
-	return ActionType_ADD
+	return ""
 }
 
-func (x *ChainSendTrxRuleListItem) GetPubkey() string {
+func (x *UserItem) GetUserPubkey() string {
 	if x != nil {
-		return x.Pubkey
+		return x.UserPubkey
 	}
 	return ""
 }
 
-func (x *ChainSendTrxRuleListItem) GetType() []TrxType {
+func (x *UserItem) GetEncryptPubkey() string {
 	if x != nil {
-		return x.Type
+		return x.EncryptPubkey
 	}
-	return nil
+	return ""
 }
 
-type SetTrxAuthModeItem struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Type TrxType     `protobuf:"varint,1,opt,name=Type,proto3,enum=quorum.pb.TrxType" json:"Type,omitempty"`
+func (x *UserItem) GetGroupOwnerPubkey() string {
+	if x != nil {
+		return x.GroupOwnerPubkey
+	}
+	return ""
+}
+
+func (x *UserItem) GetGroupOwnerSign() string {
+	if x != nil {
+		return x.GroupOwnerSign
+	}
+	return ""
+}
+
+func (x *UserItem) GetTimeStamp() int64 {
+	if x != nil {
+		return x.TimeStamp
+	}
+	return 0
+}
+
+func (x *UserItem) GetAction() ActionType {
+	if x != nil {
+		return x.Action
+	}
+	return ActionType_ADD
+}
+
+func (x *UserItem) GetMemo() string {
+	if x != nil {
+		return x.Memo
+	}
+	return ""
+}
+
+type AnnounceItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupId            string       `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	SignPubkey         string       `protobuf:"bytes,2,opt,name=SignPubkey,proto3" json:"SignPubkey,omitempty"`
+	EncryptPubkey      string       `protobuf:"bytes,3,opt,name=EncryptPubkey,proto3" json:"EncryptPubkey,omitempty"`
+	AnnouncerSignature string       `protobuf:"bytes,4,opt,name=AnnouncerSignature,proto3" json:"AnnouncerSignature,omitempty"`
+	Type               AnnounceType `protobuf:"varint,5,opt,name=Type,proto3,enum=quorum.pb.AnnounceType" json:"Type,omitempty"`
+	OwnerPubkey        string       `protobuf:"bytes,6,opt,name=OwnerPubkey,proto3" json:"OwnerPubkey,omitempty"`
+	OwnerSignature     string       `protobuf:"bytes,7,opt,name=OwnerSignature,proto3" json:"OwnerSignature,omitempty"`
+	Result             ApproveType  `protobuf:"varint,8,opt,name=Result,proto3,enum=quorum.pb.ApproveType" json:"Result,omitempty"`
+	TimeStamp          int64        `protobuf:"varint,9,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty,string"`
+	Action             ActionType   `protobuf:"varint,10,opt,name=Action,proto3,enum=quorum.pb.ActionType" json:"Action,omitempty"`
+	Memo               string       `protobuf:"bytes,11,opt,name=Memo,proto3" json:"Memo,omitempty"`
+}
+
+func (x *AnnounceItem) Reset() {
+	*x = AnnounceItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AnnounceItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnnounceItem) ProtoMessage() {}
+
+func (x *AnnounceItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnnounceItem.ProtoReflect.Descriptor instead.
+func (*AnnounceItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AnnounceItem) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *AnnounceItem) GetSignPubkey() string {
+	if x != nil {
+		return x.SignPubkey
+	}
+	return ""
+}
+
+func (x *AnnounceItem) GetEncryptPubkey() string {
+	if x != nil {
+		return x.EncryptPubkey
+	}
+	return ""
+}
+
+func (x *AnnounceItem) GetAnnouncerSignature() string {
+	if x != nil {
+		return x.AnnouncerSignature
+	}
+	return ""
+}
+
+func (x *AnnounceItem) GetType() AnnounceType {
+	if x != nil {
+		return x.Type
+	}
+	return AnnounceType_AS_USER
+}
+
+func (x *AnnounceItem) GetOwnerPubkey() string {
+	if x != nil {
+		return x.OwnerPubkey
+	}
+	return ""
+}
+
+func (x *AnnounceItem) GetOwnerSignature() string {
+	if x != nil {
+		return x.OwnerSignature
+	}
+	return ""
+}
+
+func (x *AnnounceItem) GetResult() ApproveType {
+	if x != nil {
+		return x.Result
+	}
+	return ApproveType_ANNOUNCED
+}
+
+func (x *AnnounceItem) GetTimeStamp() int64 {
+	if x != nil {
+		return x.TimeStamp
+	}
+	return 0
+}
+
+func (x *AnnounceItem) GetAction() ActionType {
+	if x != nil {
+		return x.Action
+	}
+	return ActionType_ADD
+}
+
+func (x *AnnounceItem) GetMemo() string {
+	if x != nil {
+		return x.Memo
+	}
+	return ""
+}
+
+type GroupItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupId           string            `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	GroupName         string            `protobuf:"bytes,2,opt,name=GroupName,proto3" json:"GroupName,omitempty"`
+	OwnerPubKey       string            `protobuf:"bytes,3,opt,name=OwnerPubKey,proto3" json:"OwnerPubKey,omitempty"`
+	UserSignPubkey    string            `protobuf:"bytes,4,opt,name=UserSignPubkey,proto3" json:"UserSignPubkey,omitempty"`
+	UserEncryptPubkey string            `protobuf:"bytes,5,opt,name=UserEncryptPubkey,proto3" json:"UserEncryptPubkey,omitempty"`
+	LastUpdate        int64             `protobuf:"varint,6,opt,name=LastUpdate,proto3" json:"LastUpdate,omitempty"`
+	GenesisBlock      *Block            `protobuf:"bytes,7,opt,name=GenesisBlock,proto3" json:"GenesisBlock,omitempty"`
+	EncryptType       GroupEncryptType  `protobuf:"varint,8,opt,name=EncryptType,proto3,enum=quorum.pb.GroupEncryptType" json:"EncryptType,omitempty"`
+	ConsenseType      GroupConsenseType `protobuf:"varint,9,opt,name=ConsenseType,proto3,enum=quorum.pb.GroupConsenseType" json:"ConsenseType,omitempty"`
+	CipherKey         string            `protobuf:"bytes,10,opt,name=CipherKey,proto3" json:"CipherKey,omitempty"`
+	AppKey            string            `protobuf:"bytes,11,opt,name=AppKey,proto3" json:"AppKey,omitempty"`
+}
+
+func (x *GroupItem) Reset() {
+	*x = GroupItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GroupItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupItem) ProtoMessage() {}
+
+func (x *GroupItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupItem.ProtoReflect.Descriptor instead.
+func (*GroupItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GroupItem) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *GroupItem) GetGroupName() string {
+	if x != nil {
+		return x.GroupName
+	}
+	return ""
+}
+
+func (x *GroupItem) GetOwnerPubKey() string {
+	if x != nil {
+		return x.OwnerPubKey
+	}
+	return ""
+}
+
+func (x *GroupItem) GetUserSignPubkey() string {
+	if x != nil {
+		return x.UserSignPubkey
+	}
+	return ""
+}
+
+func (x *GroupItem) GetUserEncryptPubkey() string {
+	if x != nil {
+		return x.UserEncryptPubkey
+	}
+	return ""
+}
+
+func (x *GroupItem) GetLastUpdate() int64 {
+	if x != nil {
+		return x.LastUpdate
+	}
+	return 0
+}
+
+func (x *GroupItem) GetGenesisBlock() *Block {
+	if x != nil {
+		return x.GenesisBlock
+	}
+	return nil
+}
+
+func (x *GroupItem) GetEncryptType() GroupEncryptType {
+	if x != nil {
+		return x.EncryptType
+	}
+	return GroupEncryptType_PUBLIC
+}
+
+func (x *GroupItem) GetConsenseType() GroupConsenseType {
+	if x != nil {
+		return x.ConsenseType
+	}
+	return GroupConsenseType_POA
+}
+
+func (x *GroupItem) GetCipherKey() string {
+	if x != nil {
+		return x.CipherKey
+	}
+	return ""
+}
+
+func (x *GroupItem) GetAppKey() string {
+	if x != nil {
+		return x.AppKey
+	}
+	return ""
+}
+
+type ChainConfigItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupId        string          `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	Type           ChainConfigType `protobuf:"varint,2,opt,name=Type,proto3,enum=quorum.pb.ChainConfigType" json:"Type,omitempty"`
+	Data           []byte          `protobuf:"bytes,3,opt,name=Data,proto3" json:"Data,omitempty"`
+	OwnerPubkey    string          `protobuf:"bytes,4,opt,name=OwnerPubkey,proto3" json:"OwnerPubkey,omitempty"`
+	OwnerSignature string          `protobuf:"bytes,5,opt,name=OwnerSignature,proto3" json:"OwnerSignature,omitempty"`
+	TimeStamp      int64           `protobuf:"varint,6,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty,string"`
+	Memo           string          `protobuf:"bytes,7,opt,name=Memo,proto3" json:"Memo,omitempty"`
+}
+
+func (x *ChainConfigItem) Reset() {
+	*x = ChainConfigItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChainConfigItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChainConfigItem) ProtoMessage() {}
+
+func (x *ChainConfigItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChainConfigItem.ProtoReflect.Descriptor instead.
+func (*ChainConfigItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ChainConfigItem) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *ChainConfigItem) GetType() ChainConfigType {
+	if x != nil {
+		return x.Type
+	}
+	return ChainConfigType_SET_TRX_AUTH_MODE
+}
+
+func (x *ChainConfigItem) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ChainConfigItem) GetOwnerPubkey() string {
+	if x != nil {
+		return x.OwnerPubkey
+	}
+	return ""
+}
+
+func (x *ChainConfigItem) GetOwnerSignature() string {
+	if x != nil {
+		return x.OwnerSignature
+	}
+	return ""
+}
+
+func (x *ChainConfigItem) GetTimeStamp() int64 {
+	if x != nil {
+		return x.TimeStamp
+	}
+	return 0
+}
+
+func (x *ChainConfigItem) GetMemo() string {
+	if x != nil {
+		return x.Memo
+	}
+	return ""
+}
+
+type ChainSendTrxRuleListItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Action ActionType `protobuf:"varint,1,opt,name=Action,proto3,enum=quorum.pb.ActionType" json:"Action,omitempty"`
+	Pubkey string     `protobuf:"bytes,3,opt,name=Pubkey,proto3" json:"Pubkey,omitempty"`
+	Type   []TrxType  `protobuf:"varint,4,rep,packed,name=Type,proto3,enum=quorum.pb.TrxType" json:"Type,omitempty"`
+}
+
+func (x *ChainSendTrxRuleListItem) Reset() {
+	*x = ChainSendTrxRuleListItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChainSendTrxRuleListItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChainSendTrxRuleListItem) ProtoMessage() {}
+
+func (x *ChainSendTrxRuleListItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChainSendTrxRuleListItem.ProtoReflect.Descriptor instead.
+func (*ChainSendTrxRuleListItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ChainSendTrxRuleListItem) GetAction() ActionType {
+	if x != nil {
+		return x.Action
+	}
+	return ActionType_ADD
+}
+
+func (x *ChainSendTrxRuleListItem) GetPubkey() string {
+	if x != nil {
+		return x.Pubkey
+	}
+	return ""
+}
+
+func (x *ChainSendTrxRuleListItem) GetType() []TrxType {
+	if x != nil {
+		return x.Type
+	}
+	return nil
+}
+
+type SetTrxAuthModeItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type TrxType     `protobuf:"varint,1,opt,name=Type,proto3,enum=quorum.pb.TrxType" json:"Type,omitempty"`
 	Mode TrxAuthMode `protobuf:"varint,2,opt,name=Mode,proto3,enum=quorum.pb.TrxAuthMode" json:"Mode,omitempty"`
 }
 
-func (x *SetTrxAuthModeItem) Reset() {
-	*x = SetTrxAuthModeItem{}
+func (x *SetTrxAuthModeItem) Reset() {
+	*x = SetTrxAuthModeItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetTrxAuthModeItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTrxAuthModeItem) ProtoMessage() {}
+
+func (x *SetTrxAuthModeItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTrxAuthModeItem.ProtoReflect.Descriptor instead.
+func (*SetTrxAuthModeItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SetTrxAuthModeItem) GetType() TrxType {
+	if x != nil {
+		return x.Type
+	}
+	return TrxType_POST
+}
+
+func (x *SetTrxAuthModeItem) GetMode() TrxAuthMode {
+	if x != nil {
+		return x.Mode
+	}
+	return TrxAuthMode_FOLLOW_ALW_LIST
+}
+
+type MempoolPolicyItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BatchSize        int64 `protobuf:"varint,1,opt,name=BatchSize,proto3" json:"BatchSize,omitempty"`               //maximum trxs proposed by a producer in one epoch
+	MaxTrxsPerSender int64 `protobuf:"varint,2,opt,name=MaxTrxsPerSender,proto3" json:"MaxTrxsPerSender,omitempty"` //maximum trxs of one sender in a proposal, 0 for no limit
+	MaxBufferSize    int64 `protobuf:"varint,3,opt,name=MaxBufferSize,proto3" json:"MaxBufferSize,omitempty"`       //maximum trxs kept in trx buffer
+	MaxTrxAge        int64 `protobuf:"varint,4,opt,name=MaxTrxAge,proto3" json:"MaxTrxAge,omitempty"`               //seconds a trx can wait in trx buffer, 0 for no limit before it expired
+}
+
+func (x *MempoolPolicyItem) Reset() {
+	*x = MempoolPolicyItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MempoolPolicyItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MempoolPolicyItem) ProtoMessage() {}
+
+func (x *MempoolPolicyItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MempoolPolicyItem.ProtoReflect.Descriptor instead.
+func (*MempoolPolicyItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *MempoolPolicyItem) GetBatchSize() int64 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+func (x *MempoolPolicyItem) GetMaxTrxsPerSender() int64 {
+	if x != nil {
+		return x.MaxTrxsPerSender
+	}
+	return 0
+}
+
+func (x *MempoolPolicyItem) GetMaxBufferSize() int64 {
+	if x != nil {
+		return x.MaxBufferSize
+	}
+	return 0
+}
+
+func (x *MempoolPolicyItem) GetMaxTrxAge() int64 {
+	if x != nil {
+		return x.MaxTrxAge
+	}
+	return 0
+}
+
+type EpochPacingItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MinInterval     int64 `protobuf:"varint,1,opt,name=MinInterval,proto3" json:"MinInterval,omitempty"`         //milliseconds to wait before next epoch when trx buffer holds a full batch
+	BaseInterval    int64 `protobuf:"varint,2,opt,name=BaseInterval,proto3" json:"BaseInterval,omitempty"`       //milliseconds to wait before next epoch when trx buffer holds some trxs
+	MaxIdleInterval int64 `protobuf:"varint,3,opt,name=MaxIdleInterval,proto3" json:"MaxIdleInterval,omitempty"` //maximum milliseconds between two checks of an empty trx buffer
+}
+
+func (x *EpochPacingItem) Reset() {
+	*x = EpochPacingItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EpochPacingItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EpochPacingItem) ProtoMessage() {}
+
+func (x *EpochPacingItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EpochPacingItem.ProtoReflect.Descriptor instead.
+func (*EpochPacingItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *EpochPacingItem) GetMinInterval() int64 {
+	if x != nil {
+		return x.MinInterval
+	}
+	return 0
+}
+
+func (x *EpochPacingItem) GetBaseInterval() int64 {
+	if x != nil {
+		return x.BaseInterval
+	}
+	return 0
+}
+
+func (x *EpochPacingItem) GetMaxIdleInterval() int64 {
+	if x != nil {
+		return x.MaxIdleInterval
+	}
+	return 0
+}
+
+type AppConfigItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupId     string        `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	Action      ActionType    `protobuf:"varint,2,opt,name=Action,proto3,enum=quorum.pb.ActionType" json:"Action,omitempty"`
+	Name        string        `protobuf:"bytes,3,opt,name=Name,proto3" json:"Name,omitempty"`
+	Type        AppConfigType `protobuf:"varint,4,opt,name=Type,proto3,enum=quorum.pb.AppConfigType" json:"Type,omitempty"`
+	Value       string        `protobuf:"bytes,5,opt,name=Value,proto3" json:"Value,omitempty"`
+	OwnerPubkey string        `protobuf:"bytes,6,opt,name=OwnerPubkey,proto3" json:"OwnerPubkey,omitempty"`
+	OwnerSign   string        `protobuf:"bytes,7,opt,name=OwnerSign,proto3" json:"OwnerSign,omitempty"`
+	Memo        string        `protobuf:"bytes,8,opt,name=Memo,proto3" json:"Memo,omitempty"`
+	TimeStamp   int64         `protobuf:"varint,9,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty,string"`
+}
+
+func (x *AppConfigItem) Reset() {
+	*x = AppConfigItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AppConfigItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppConfigItem) ProtoMessage() {}
+
+func (x *AppConfigItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppConfigItem.ProtoReflect.Descriptor instead.
+func (*AppConfigItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *AppConfigItem) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *AppConfigItem) GetAction() ActionType {
+	if x != nil {
+		return x.Action
+	}
+	return ActionType_ADD
+}
+
+func (x *AppConfigItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AppConfigItem) GetType() AppConfigType {
+	if x != nil {
+		return x.Type
+	}
+	return AppConfigType_INT
+}
+
+func (x *AppConfigItem) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *AppConfigItem) GetOwnerPubkey() string {
+	if x != nil {
+		return x.OwnerPubkey
+	}
+	return ""
+}
+
+func (x *AppConfigItem) GetOwnerSign() string {
+	if x != nil {
+		return x.OwnerSign
+	}
+	return ""
+}
+
+func (x *AppConfigItem) GetMemo() string {
+	if x != nil {
+		return x.Memo
+	}
+	return ""
+}
+
+func (x *AppConfigItem) GetTimeStamp() int64 {
+	if x != nil {
+		return x.TimeStamp
+	}
+	return 0
+}
+
+type GroupSeed struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GenesisBlock   *Block `protobuf:"bytes,1,opt,name=GenesisBlock,proto3" json:"GenesisBlock,omitempty"`
+	GroupId        string `protobuf:"bytes,2,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	GroupName      string `protobuf:"bytes,3,opt,name=GroupName,proto3" json:"GroupName,omitempty"`
+	OwnerPubkey    string `protobuf:"bytes,4,opt,name=OwnerPubkey,proto3" json:"OwnerPubkey,omitempty"`
+	ConsensusType  string `protobuf:"bytes,5,opt,name=ConsensusType,proto3" json:"ConsensusType,omitempty"`
+	EncryptionType string `protobuf:"bytes,6,opt,name=EncryptionType,proto3" json:"EncryptionType,omitempty"`
+	CipherKey      string `protobuf:"bytes,7,opt,name=CipherKey,proto3" json:"CipherKey,omitempty"`
+	AppKey         string `protobuf:"bytes,8,opt,name=AppKey,proto3" json:"AppKey,omitempty"`
+	Signature      string `protobuf:"bytes,9,opt,name=Signature,proto3" json:"Signature,omitempty"`
+}
+
+func (x *GroupSeed) Reset() {
+	*x = GroupSeed{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GroupSeed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupSeed) ProtoMessage() {}
+
+func (x *GroupSeed) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupSeed.ProtoReflect.Descriptor instead.
+func (*GroupSeed) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GroupSeed) GetGenesisBlock() *Block {
+	if x != nil {
+		return x.GenesisBlock
+	}
+	return nil
+}
+
+func (x *GroupSeed) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *GroupSeed) GetGroupName() string {
+	if x != nil {
+		return x.GroupName
+	}
+	return ""
+}
+
+func (x *GroupSeed) GetOwnerPubkey() string {
+	if x != nil {
+		return x.OwnerPubkey
+	}
+	return ""
+}
+
+func (x *GroupSeed) GetConsensusType() string {
+	if x != nil {
+		return x.ConsensusType
+	}
+	return ""
+}
+
+func (x *GroupSeed) GetEncryptionType() string {
+	if x != nil {
+		return x.EncryptionType
+	}
+	return ""
+}
+
+func (x *GroupSeed) GetCipherKey() string {
+	if x != nil {
+		return x.CipherKey
+	}
+	return ""
+}
+
+func (x *GroupSeed) GetAppKey() string {
+	if x != nil {
+		return x.AppKey
+	}
+	return ""
+}
+
+func (x *GroupSeed) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+type NodeSDKGroupItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Group        *GroupItem `protobuf:"bytes,1,opt,name=Group,proto3" json:"Group,omitempty"`
+	EncryptAlias string     `protobuf:"bytes,2,opt,name=EncryptAlias,proto3" json:"EncryptAlias,omitempty"`
+	SignAlias    string     `protobuf:"bytes,3,opt,name=SignAlias,proto3" json:"SignAlias,omitempty"`
+	ApiUrl       []string   `protobuf:"bytes,4,rep,name=ApiUrl,proto3" json:"ApiUrl,omitempty"`
+	GroupSeed    string     `protobuf:"bytes,5,opt,name=GroupSeed,proto3" json:"GroupSeed,omitempty"`
+}
+
+func (x *NodeSDKGroupItem) Reset() {
+	*x = NodeSDKGroupItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NodeSDKGroupItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeSDKGroupItem) ProtoMessage() {}
+
+func (x *NodeSDKGroupItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeSDKGroupItem.ProtoReflect.Descriptor instead.
+func (*NodeSDKGroupItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *NodeSDKGroupItem) GetGroup() *GroupItem {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+func (x *NodeSDKGroupItem) GetEncryptAlias() string {
+	if x != nil {
+		return x.EncryptAlias
+	}
+	return ""
+}
+
+func (x *NodeSDKGroupItem) GetSignAlias() string {
+	if x != nil {
+		return x.SignAlias
+	}
+	return ""
+}
+
+func (x *NodeSDKGroupItem) GetApiUrl() []string {
+	if x != nil {
+		return x.ApiUrl
+	}
+	return nil
+}
+
+func (x *NodeSDKGroupItem) GetGroupSeed() string {
+	if x != nil {
+		return x.GroupSeed
+	}
+	return ""
+}
+
+type HBTrxBundle struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Trxs      []*Trx `protobuf:"bytes,1,rep,name=Trxs,proto3" json:"Trxs,omitempty"`
+	TimeStamp int64  `protobuf:"varint,2,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty,string"` //time of the proposer, trxs expired before the median of agreed proposals are dropped
+}
+
+func (x *HBTrxBundle) Reset() {
+	*x = HBTrxBundle{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HBTrxBundle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HBTrxBundle) ProtoMessage() {}
+
+func (x *HBTrxBundle) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HBTrxBundle.ProtoReflect.Descriptor instead.
+func (*HBTrxBundle) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *HBTrxBundle) GetTrxs() []*Trx {
+	if x != nil {
+		return x.Trxs
+	}
+	return nil
+}
+
+func (x *HBTrxBundle) GetTimeStamp() int64 {
+	if x != nil {
+		return x.TimeStamp
+	}
+	return 0
+}
+
+type HBMsgv1 struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MsgId       string           `protobuf:"bytes,1,opt,name=MsgId,proto3" json:"MsgId,omitempty"`
+	Epoch       uint64           `protobuf:"varint,2,opt,name=Epoch,proto3" json:"Epoch,omitempty"`
+	PayloadType HBMsgPayloadType `protobuf:"varint,3,opt,name=PayloadType,proto3,enum=quorum.pb.HBMsgPayloadType" json:"PayloadType,omitempty"` // RBC, BBA, DEC or DKG
+	Payload     []byte           `protobuf:"bytes,4,opt,name=Payload,proto3" json:"Payload,omitempty"`
+}
+
+func (x *HBMsgv1) Reset() {
+	*x = HBMsgv1{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HBMsgv1) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HBMsgv1) ProtoMessage() {}
+
+func (x *HBMsgv1) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HBMsgv1.ProtoReflect.Descriptor instead.
+func (*HBMsgv1) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *HBMsgv1) GetMsgId() string {
+	if x != nil {
+		return x.MsgId
+	}
+	return ""
+}
+
+func (x *HBMsgv1) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *HBMsgv1) GetPayloadType() HBMsgPayloadType {
+	if x != nil {
+		return x.PayloadType
+	}
+	return HBMsgPayloadType_RBC
+}
+
+func (x *HBMsgv1) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+// RBC
+type RBCMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type    RBCMsgType `protobuf:"varint,1,opt,name=Type,proto3,enum=quorum.pb.RBCMsgType" json:"Type,omitempty"` //INIT_PROPOSE / PROOF / READY
+	Payload []byte     `protobuf:"bytes,2,opt,name=Payload,proto3" json:"Payload,omitempty"`
+}
+
+func (x *RBCMsg) Reset() {
+	*x = RBCMsg{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[14]
+		mi := &file_chain_proto_msgTypes[27]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SetTrxAuthModeItem) String() string {
+func (x *RBCMsg) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetTrxAuthModeItem) ProtoMessage() {}
+func (*RBCMsg) ProtoMessage() {}
 
-func (x *SetTrxAuthModeItem) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[14]
+func (x *RBCMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[27]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2132,58 +3199,57 @@ func (x *SetTrxAuthModeItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetTrxAuthModeItem.ProtoReflect.Descriptor instead.
-func (*SetTrxAuthModeItem) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use RBCMsg.ProtoReflect.Descriptor instead.
+func (*RBCMsg) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *SetTrxAuthModeItem) GetType() TrxType {
+func (x *RBCMsg) GetType() RBCMsgType {
 	if x != nil {
 		return x.Type
 	}
-	return TrxType_POST
+	return RBCMsgType_INIT_PROPOSE
 }
 
-func (x *SetTrxAuthModeItem) GetMode() TrxAuthMode {
+func (x *RBCMsg) GetPayload() []byte {
 	if x != nil {
-		return x.Mode
+		return x.Payload
 	}
-	return TrxAuthMode_FOLLOW_ALW_LIST
+	return nil
 }
 
-type AppConfigItem struct {
+type InitPropose struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GroupId     string        `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
-	Action      ActionType    `protobuf:"varint,2,opt,name=Action,proto3,enum=quorum.pb.ActionType" json:"Action,omitempty"`
-	Name        string        `protobuf:"bytes,3,opt,name=Name,proto3" json:"Name,omitempty"`
-	Type        AppConfigType `protobuf:"varint,4,opt,name=Type,proto3,enum=quorum.pb.AppConfigType" json:"Type,omitempty"`
-	Value       string        `protobuf:"bytes,5,opt,name=Value,proto3" json:"Value,omitempty"`
-	OwnerPubkey string        `protobuf:"bytes,6,opt,name=OwnerPubkey,proto3" json:"OwnerPubkey,omitempty"`
-	OwnerSign   string        `protobuf:"bytes,7,opt,name=OwnerSign,proto3" json:"OwnerSign,omitempty"`
-	Memo        string        `protobuf:"bytes,8,opt,name=Memo,proto3" json:"Memo,omitempty"`
-	TimeStamp   int64         `protobuf:"varint,9,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty,string"`
+	RootHash         []byte   `protobuf:"bytes,1,opt,name=RootHash,proto3" json:"RootHash,omitempty"`
+	Proof            [][]byte `protobuf:"bytes,2,rep,name=Proof,proto3" json:"Proof,omitempty"`
+	Index            int64    `protobuf:"varint,3,opt,name=Index,proto3" json:"Index,omitempty"`
+	Leaves           int64    `protobuf:"varint,4,opt,name=Leaves,proto3" json:"Leaves,omitempty"`
+	OriginalDataSize int64    `protobuf:"varint,5,opt,name=OriginalDataSize,proto3" json:"OriginalDataSize,omitempty"`
+	RecvNodePubkey   string   `protobuf:"bytes,6,opt,name=RecvNodePubkey,proto3" json:"RecvNodePubkey,omitempty"` //producer which should handle this ecc data shard
+	ProposerPubkey   string   `protobuf:"bytes,7,opt,name=ProposerPubkey,proto3" json:"ProposerPubkey,omitempty"` //producer which make this propose (part of ecc shards)
+	ProposerSign     []byte   `protobuf:"bytes,8,opt,name=ProposerSign,proto3" json:"ProposerSign,omitempty"`     //signature of producer made this propose
 }
 
-func (x *AppConfigItem) Reset() {
-	*x = AppConfigItem{}
+func (x *InitPropose) Reset() {
+	*x = InitPropose{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[15]
+		mi := &file_chain_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AppConfigItem) String() string {
+func (x *InitPropose) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AppConfigItem) ProtoMessage() {}
+func (*InitPropose) ProtoMessage() {}
 
-func (x *AppConfigItem) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[15]
+func (x *InitPropose) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2194,107 +3260,199 @@ func (x *AppConfigItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AppConfigItem.ProtoReflect.Descriptor instead.
-func (*AppConfigItem) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use InitPropose.ProtoReflect.Descriptor instead.
+func (*InitPropose) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *AppConfigItem) GetGroupId() string {
+func (x *InitPropose) GetRootHash() []byte {
 	if x != nil {
-		return x.GroupId
+		return x.RootHash
 	}
-	return ""
+	return nil
 }
 
-func (x *AppConfigItem) GetAction() ActionType {
+func (x *InitPropose) GetProof() [][]byte {
 	if x != nil {
-		return x.Action
+		return x.Proof
 	}
-	return ActionType_ADD
+	return nil
 }
 
-func (x *AppConfigItem) GetName() string {
+func (x *InitPropose) GetIndex() int64 {
 	if x != nil {
-		return x.Name
+		return x.Index
 	}
-	return ""
+	return 0
 }
 
-func (x *AppConfigItem) GetType() AppConfigType {
+func (x *InitPropose) GetLeaves() int64 {
 	if x != nil {
-		return x.Type
+		return x.Leaves
 	}
-	return AppConfigType_INT
+	return 0
 }
 
-func (x *AppConfigItem) GetValue() string {
+func (x *InitPropose) GetOriginalDataSize() int64 {
 	if x != nil {
-		return x.Value
+		return x.OriginalDataSize
+	}
+	return 0
+}
+
+func (x *InitPropose) GetRecvNodePubkey() string {
+	if x != nil {
+		return x.RecvNodePubkey
 	}
 	return ""
 }
 
-func (x *AppConfigItem) GetOwnerPubkey() string {
+func (x *InitPropose) GetProposerPubkey() string {
 	if x != nil {
-		return x.OwnerPubkey
+		return x.ProposerPubkey
 	}
 	return ""
 }
 
-func (x *AppConfigItem) GetOwnerSign() string {
+func (x *InitPropose) GetProposerSign() []byte {
 	if x != nil {
-		return x.OwnerSign
+		return x.ProposerSign
+	}
+	return nil
+}
+
+type Echo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RootHash               []byte   `protobuf:"bytes,1,opt,name=RootHash,proto3" json:"RootHash,omitempty"`
+	Proof                  [][]byte `protobuf:"bytes,2,rep,name=Proof,proto3" json:"Proof,omitempty"`
+	Index                  int64    `protobuf:"varint,3,opt,name=Index,proto3" json:"Index,omitempty"`
+	Leaves                 int64    `protobuf:"varint,4,opt,name=Leaves,proto3" json:"Leaves,omitempty"`
+	OriginalDataSize       int64    `protobuf:"varint,5,opt,name=OriginalDataSize,proto3" json:"OriginalDataSize,omitempty"`
+	OriginalProposerPubkey string   `protobuf:"bytes,6,opt,name=OriginalProposerPubkey,proto3" json:"OriginalProposerPubkey,omitempty"` //producer make this original input
+	EchoProviderPubkey     string   `protobuf:"bytes,7,opt,name=EchoProviderPubkey,proto3" json:"EchoProviderPubkey,omitempty"`         //producer which broadcast this Echo
+	EchoProviderSign       []byte   `protobuf:"bytes,8,opt,name=EchoProviderSign,proto3" json:"EchoProviderSign,omitempty"`             //signature of producer broadcast this Echo
+}
+
+func (x *Echo) Reset() {
+	*x = Echo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Echo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Echo) ProtoMessage() {}
+
+func (x *Echo) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Echo.ProtoReflect.Descriptor instead.
+func (*Echo) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *Echo) GetRootHash() []byte {
+	if x != nil {
+		return x.RootHash
+	}
+	return nil
+}
+
+func (x *Echo) GetProof() [][]byte {
+	if x != nil {
+		return x.Proof
+	}
+	return nil
+}
+
+func (x *Echo) GetIndex() int64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *Echo) GetLeaves() int64 {
+	if x != nil {
+		return x.Leaves
+	}
+	return 0
+}
+
+func (x *Echo) GetOriginalDataSize() int64 {
+	if x != nil {
+		return x.OriginalDataSize
+	}
+	return 0
+}
+
+func (x *Echo) GetOriginalProposerPubkey() string {
+	if x != nil {
+		return x.OriginalProposerPubkey
 	}
 	return ""
 }
 
-func (x *AppConfigItem) GetMemo() string {
+func (x *Echo) GetEchoProviderPubkey() string {
 	if x != nil {
-		return x.Memo
+		return x.EchoProviderPubkey
 	}
 	return ""
 }
 
-func (x *AppConfigItem) GetTimeStamp() int64 {
+func (x *Echo) GetEchoProviderSign() []byte {
 	if x != nil {
-		return x.TimeStamp
+		return x.EchoProviderSign
 	}
-	return 0
+	return nil
 }
 
-type GroupSeed struct {
+type Ready struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GenesisBlock   *Block `protobuf:"bytes,1,opt,name=GenesisBlock,proto3" json:"GenesisBlock,omitempty"`
-	GroupId        string `protobuf:"bytes,2,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
-	GroupName      string `protobuf:"bytes,3,opt,name=GroupName,proto3" json:"GroupName,omitempty"`
-	OwnerPubkey    string `protobuf:"bytes,4,opt,name=OwnerPubkey,proto3" json:"OwnerPubkey,omitempty"`
-	ConsensusType  string `protobuf:"bytes,5,opt,name=ConsensusType,proto3" json:"ConsensusType,omitempty"`
-	EncryptionType string `protobuf:"bytes,6,opt,name=EncryptionType,proto3" json:"EncryptionType,omitempty"`
-	CipherKey      string `protobuf:"bytes,7,opt,name=CipherKey,proto3" json:"CipherKey,omitempty"`
-	AppKey         string `protobuf:"bytes,8,opt,name=AppKey,proto3" json:"AppKey,omitempty"`
-	Signature      string `protobuf:"bytes,9,opt,name=Signature,proto3" json:"Signature,omitempty"`
+	RootHash               []byte `protobuf:"bytes,1,opt,name=RootHash,proto3" json:"RootHash,omitempty"`
+	OriginalProposerPubkey string `protobuf:"bytes,2,opt,name=OriginalProposerPubkey,proto3" json:"OriginalProposerPubkey,omitempty"`
+	ReadyProviderPubkey    string `protobuf:"bytes,3,opt,name=ReadyProviderPubkey,proto3" json:"ReadyProviderPubkey,omitempty"`
+	ReadyProviderSign      []byte `protobuf:"bytes,4,opt,name=ReadyProviderSign,proto3" json:"ReadyProviderSign,omitempty"`
+	OriginalDataSize       int64  `protobuf:"varint,5,opt,name=OriginalDataSize,proto3" json:"OriginalDataSize,omitempty"` //data size of the RootHash, same shards can be cut to different values by size
 }
 
-func (x *GroupSeed) Reset() {
-	*x = GroupSeed{}
+func (x *Ready) Reset() {
+	*x = Ready{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[16]
+		mi := &file_chain_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GroupSeed) String() string {
+func (x *Ready) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GroupSeed) ProtoMessage() {}
+func (*Ready) ProtoMessage() {}
 
-func (x *GroupSeed) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[16]
+func (x *Ready) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2305,103 +3463,73 @@ func (x *GroupSeed) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GroupSeed.ProtoReflect.Descriptor instead.
-func (*GroupSeed) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use Ready.ProtoReflect.Descriptor instead.
+func (*Ready) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *GroupSeed) GetGenesisBlock() *Block {
+func (x *Ready) GetRootHash() []byte {
 	if x != nil {
-		return x.GenesisBlock
+		return x.RootHash
 	}
 	return nil
 }
 
-func (x *GroupSeed) GetGroupId() string {
-	if x != nil {
-		return x.GroupId
-	}
-	return ""
-}
-
-func (x *GroupSeed) GetGroupName() string {
-	if x != nil {
-		return x.GroupName
-	}
-	return ""
-}
-
-func (x *GroupSeed) GetOwnerPubkey() string {
-	if x != nil {
-		return x.OwnerPubkey
-	}
-	return ""
-}
-
-func (x *GroupSeed) GetConsensusType() string {
-	if x != nil {
-		return x.ConsensusType
-	}
-	return ""
-}
-
-func (x *GroupSeed) GetEncryptionType() string {
+func (x *Ready) GetOriginalProposerPubkey() string {
 	if x != nil {
-		return x.EncryptionType
+		return x.OriginalProposerPubkey
 	}
 	return ""
 }
 
-func (x *GroupSeed) GetCipherKey() string {
+func (x *Ready) GetReadyProviderPubkey() string {
 	if x != nil {
-		return x.CipherKey
+		return x.ReadyProviderPubkey
 	}
 	return ""
 }
 
-func (x *GroupSeed) GetAppKey() string {
+func (x *Ready) GetReadyProviderSign() []byte {
 	if x != nil {
-		return x.AppKey
+		return x.ReadyProviderSign
 	}
-	return ""
+	return nil
 }
 
-func (x *GroupSeed) GetSignature() string {
+func (x *Ready) GetOriginalDataSize() int64 {
 	if x != nil {
-		return x.Signature
+		return x.OriginalDataSize
 	}
-	return ""
+	return 0
 }
 
-type NodeSDKGroupItem struct {
+// BBA
+type BBAMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Group        *GroupItem `protobuf:"bytes,1,opt,name=Group,proto3" json:"Group,omitempty"`
-	EncryptAlias string     `protobuf:"bytes,2,opt,name=EncryptAlias,proto3" json:"EncryptAlias,omitempty"`
-	SignAlias    string     `protobuf:"bytes,3,opt,name=SignAlias,proto3" json:"SignAlias,omitempty"`
-	ApiUrl       []string   `protobuf:"bytes,4,rep,name=ApiUrl,proto3" json:"ApiUrl,omitempty"`
-	GroupSeed    string     `protobuf:"bytes,5,opt,name=GroupSeed,proto3" json:"GroupSeed,omitempty"`
+	Type    BBAMsgType `protobuf:"varint,1,opt,name=Type,proto3,enum=quorum.pb.BBAMsgType" json:"Type,omitempty"` //BVAL, AUX or COIN
+	Payload []byte     `protobuf:"bytes,2,opt,name=Payload,proto3" json:"Payload,omitempty"`
 }
 
-func (x *NodeSDKGroupItem) Reset() {
-	*x = NodeSDKGroupItem{}
+func (x *BBAMsg) Reset() {
+	*x = BBAMsg{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[17]
+		mi := &file_chain_proto_msgTypes[31]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *NodeSDKGroupItem) String() string {
+func (x *BBAMsg) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NodeSDKGroupItem) ProtoMessage() {}
+func (*BBAMsg) ProtoMessage() {}
 
-func (x *NodeSDKGroupItem) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[17]
+func (x *BBAMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[31]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2412,71 +3540,55 @@ func (x *NodeSDKGroupItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NodeSDKGroupItem.ProtoReflect.Descriptor instead.
-func (*NodeSDKGroupItem) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{17}
-}
-
-func (x *NodeSDKGroupItem) GetGroup() *GroupItem {
-	if x != nil {
-		return x.Group
-	}
-	return nil
-}
-
-func (x *NodeSDKGroupItem) GetEncryptAlias() string {
-	if x != nil {
-		return x.EncryptAlias
-	}
-	return ""
+// Deprecated: Use BBAMsg.ProtoReflect.Descriptor instead.
+func (*BBAMsg) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *NodeSDKGroupItem) GetSignAlias() string {
+func (x *BBAMsg) GetType() BBAMsgType {
 	if x != nil {
-		return x.SignAlias
+		return x.Type
 	}
-	return ""
+	return BBAMsgType_BVAL
 }
 
-func (x *NodeSDKGroupItem) GetApiUrl() []string {
+func (x *BBAMsg) GetPayload() []byte {
 	if x != nil {
-		return x.ApiUrl
+		return x.Payload
 	}
 	return nil
 }
 
-func (x *NodeSDKGroupItem) GetGroupSeed() string {
-	if x != nil {
-		return x.GroupSeed
-	}
-	return ""
-}
-
-type HBTrxBundle struct {
+type Bval struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Trxs []*Trx `protobuf:"bytes,1,rep,name=Trxs,proto3" json:"Trxs,omitempty"`
+	ProposerId   string `protobuf:"bytes,1,opt,name=ProposerId,proto3" json:"ProposerId,omitempty"`
+	SenderPubkey string `protobuf:"bytes,2,opt,name=SenderPubkey,proto3" json:"SenderPubkey,omitempty"`
+	Epoch        int64  `protobuf:"varint,3,opt,name=Epoch,proto3" json:"Epoch,omitempty"` //bba round
+	Value        bool   `protobuf:"varint,4,opt,name=Value,proto3" json:"Value,omitempty"`
+	AcsEpoch     uint64 `protobuf:"varint,5,opt,name=AcsEpoch,proto3" json:"AcsEpoch,omitempty"`
+	SenderSign   []byte `protobuf:"bytes,6,opt,name=SenderSign,proto3" json:"SenderSign,omitempty"`
 }
 
-func (x *HBTrxBundle) Reset() {
-	*x = HBTrxBundle{}
+func (x *Bval) Reset() {
+	*x = Bval{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[18]
+		mi := &file_chain_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *HBTrxBundle) String() string {
+func (x *Bval) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HBTrxBundle) ProtoMessage() {}
+func (*Bval) ProtoMessage() {}
 
-func (x *HBTrxBundle) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[18]
+func (x *Bval) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2487,46 +3599,83 @@ func (x *HBTrxBundle) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HBTrxBundle.ProtoReflect.Descriptor instead.
-func (*HBTrxBundle) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use Bval.ProtoReflect.Descriptor instead.
+func (*Bval) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *HBTrxBundle) GetTrxs() []*Trx {
+func (x *Bval) GetProposerId() string {
 	if x != nil {
-		return x.Trxs
+		return x.ProposerId
+	}
+	return ""
+}
+
+func (x *Bval) GetSenderPubkey() string {
+	if x != nil {
+		return x.SenderPubkey
+	}
+	return ""
+}
+
+func (x *Bval) GetEpoch() int64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *Bval) GetValue() bool {
+	if x != nil {
+		return x.Value
+	}
+	return false
+}
+
+func (x *Bval) GetAcsEpoch() uint64 {
+	if x != nil {
+		return x.AcsEpoch
+	}
+	return 0
+}
+
+func (x *Bval) GetSenderSign() []byte {
+	if x != nil {
+		return x.SenderSign
 	}
 	return nil
 }
 
-type HBMsgv1 struct {
+type Aux struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	MsgId       string           `protobuf:"bytes,1,opt,name=MsgId,proto3" json:"MsgId,omitempty"`
-	Epoch       uint64           `protobuf:"varint,2,opt,name=Epoch,proto3" json:"Epoch,omitempty"`
-	PayloadType HBMsgPayloadType `protobuf:"varint,3,opt,name=PayloadType,proto3,enum=quorum.pb.HBMsgPayloadType" json:"PayloadType,omitempty"` // RBC or BBA
-	Payload     []byte           `protobuf:"bytes,4,opt,name=Payload,proto3" json:"Payload,omitempty"`
+	ProposerId   string `protobuf:"bytes,1,opt,name=ProposerId,proto3" json:"ProposerId,omitempty"`
+	SenderPubkey string `protobuf:"bytes,2,opt,name=SenderPubkey,proto3" json:"SenderPubkey,omitempty"`
+	Epoch        uint64 `protobuf:"varint,3,opt,name=Epoch,proto3" json:"Epoch,omitempty"` //bba round
+	Value        bool   `protobuf:"varint,4,opt,name=Value,proto3" json:"Value,omitempty"`
+	AcsEpoch     uint64 `protobuf:"varint,5,opt,name=AcsEpoch,proto3" json:"AcsEpoch,omitempty"`
+	SenderSign   []byte `protobuf:"bytes,6,opt,name=SenderSign,proto3" json:"SenderSign,omitempty"`
 }
 
-func (x *HBMsgv1) Reset() {
-	*x = HBMsgv1{}
+func (x *Aux) Reset() {
+	*x = Aux{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[19]
+		mi := &file_chain_proto_msgTypes[33]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *HBMsgv1) String() string {
+func (x *Aux) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HBMsgv1) ProtoMessage() {}
+func (*Aux) ProtoMessage() {}
 
-func (x *HBMsgv1) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[19]
+func (x *Aux) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2537,66 +3686,84 @@ func (x *HBMsgv1) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HBMsgv1.ProtoReflect.Descriptor instead.
-func (*HBMsgv1) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{19}
+// Deprecated: Use Aux.ProtoReflect.Descriptor instead.
+func (*Aux) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *HBMsgv1) GetMsgId() string {
+func (x *Aux) GetProposerId() string {
 	if x != nil {
-		return x.MsgId
+		return x.ProposerId
 	}
 	return ""
 }
 
-func (x *HBMsgv1) GetEpoch() uint64 {
+func (x *Aux) GetSenderPubkey() string {
+	if x != nil {
+		return x.SenderPubkey
+	}
+	return ""
+}
+
+func (x *Aux) GetEpoch() uint64 {
 	if x != nil {
 		return x.Epoch
 	}
 	return 0
 }
 
-func (x *HBMsgv1) GetPayloadType() HBMsgPayloadType {
+func (x *Aux) GetValue() bool {
 	if x != nil {
-		return x.PayloadType
+		return x.Value
 	}
-	return HBMsgPayloadType_RBC
+	return false
 }
 
-func (x *HBMsgv1) GetPayload() []byte {
+func (x *Aux) GetAcsEpoch() uint64 {
 	if x != nil {
-		return x.Payload
+		return x.AcsEpoch
+	}
+	return 0
+}
+
+func (x *Aux) GetSenderSign() []byte {
+	if x != nil {
+		return x.SenderSign
 	}
 	return nil
 }
 
-// RBC
-type RBCMsg struct {
+// bin values of the sender after it got AUX from N - f producers, the coin of the round is revealed after CONF
+type Conf struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Type    RBCMsgType `protobuf:"varint,1,opt,name=Type,proto3,enum=quorum.pb.RBCMsgType" json:"Type,omitempty"` //INIT_PROPOSE / PROOF / READY
-	Payload []byte     `protobuf:"bytes,2,opt,name=Payload,proto3" json:"Payload,omitempty"`
+	ProposerId   string `protobuf:"bytes,1,opt,name=ProposerId,proto3" json:"ProposerId,omitempty"`
+	SenderPubkey string `protobuf:"bytes,2,opt,name=SenderPubkey,proto3" json:"SenderPubkey,omitempty"`
+	Epoch        uint64 `protobuf:"varint,3,opt,name=Epoch,proto3" json:"Epoch,omitempty"` //bba round
+	Values       []bool `protobuf:"varint,4,rep,packed,name=Values,proto3" json:"Values,omitempty"`
+	AcsEpoch     uint64 `protobuf:"varint,5,opt,name=AcsEpoch,proto3" json:"AcsEpoch,omitempty"`
+	SenderSign   []byte `protobuf:"bytes,6,opt,name=SenderSign,proto3" json:"SenderSign,omitempty"`
 }
 
-func (x *RBCMsg) Reset() {
-	*x = RBCMsg{}
+func (x *Conf) Reset() {
+	*x = Conf{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[20]
+		mi := &file_chain_proto_msgTypes[34]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RBCMsg) String() string {
+func (x *Conf) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RBCMsg) ProtoMessage() {}
+func (*Conf) ProtoMessage() {}
 
-func (x *RBCMsg) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[20]
+func (x *Conf) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[34]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2607,57 +3774,85 @@ func (x *RBCMsg) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RBCMsg.ProtoReflect.Descriptor instead.
-func (*RBCMsg) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use Conf.ProtoReflect.Descriptor instead.
+func (*Conf) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *Conf) GetProposerId() string {
+	if x != nil {
+		return x.ProposerId
+	}
+	return ""
+}
+
+func (x *Conf) GetSenderPubkey() string {
+	if x != nil {
+		return x.SenderPubkey
+	}
+	return ""
+}
+
+func (x *Conf) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *Conf) GetValues() []bool {
+	if x != nil {
+		return x.Values
+	}
+	return nil
 }
 
-func (x *RBCMsg) GetType() RBCMsgType {
+func (x *Conf) GetAcsEpoch() uint64 {
 	if x != nil {
-		return x.Type
+		return x.AcsEpoch
 	}
-	return RBCMsgType_INIT_PROPOSE
+	return 0
 }
 
-func (x *RBCMsg) GetPayload() []byte {
+func (x *Conf) GetSenderSign() []byte {
 	if x != nil {
-		return x.Payload
+		return x.SenderSign
 	}
 	return nil
 }
 
-type InitPropose struct {
+// share of the threshold common coin of a bba round
+type CoinShare struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RootHash         []byte   `protobuf:"bytes,1,opt,name=RootHash,proto3" json:"RootHash,omitempty"`
-	Proof            [][]byte `protobuf:"bytes,2,rep,name=Proof,proto3" json:"Proof,omitempty"`
-	Index            int64    `protobuf:"varint,3,opt,name=Index,proto3" json:"Index,omitempty"`
-	Leaves           int64    `protobuf:"varint,4,opt,name=Leaves,proto3" json:"Leaves,omitempty"`
-	OriginalDataSize int64    `protobuf:"varint,5,opt,name=OriginalDataSize,proto3" json:"OriginalDataSize,omitempty"`
-	RecvNodePubkey   string   `protobuf:"bytes,6,opt,name=RecvNodePubkey,proto3" json:"RecvNodePubkey,omitempty"` //producer which should handle this ecc data shard
-	ProposerPubkey   string   `protobuf:"bytes,7,opt,name=ProposerPubkey,proto3" json:"ProposerPubkey,omitempty"` //producer which make this propose (part of ecc shards)
-	ProposerSign     []byte   `protobuf:"bytes,8,opt,name=ProposerSign,proto3" json:"ProposerSign,omitempty"`     //signature of producer made this propose
+	ProposerId   string `protobuf:"bytes,1,opt,name=ProposerId,proto3" json:"ProposerId,omitempty"`
+	SenderPubkey string `protobuf:"bytes,2,opt,name=SenderPubkey,proto3" json:"SenderPubkey,omitempty"`
+	Epoch        uint64 `protobuf:"varint,3,opt,name=Epoch,proto3" json:"Epoch,omitempty"` //bba round
+	AcsEpoch     uint64 `protobuf:"varint,4,opt,name=AcsEpoch,proto3" json:"AcsEpoch,omitempty"`
+	Share        []byte `protobuf:"bytes,5,opt,name=Share,proto3" json:"Share,omitempty"`
+	Proof        []byte `protobuf:"bytes,6,opt,name=Proof,proto3" json:"Proof,omitempty"` //proof that the share is made by key share of the sender
+	SenderSign   []byte `protobuf:"bytes,7,opt,name=SenderSign,proto3" json:"SenderSign,omitempty"`
 }
 
-func (x *InitPropose) Reset() {
-	*x = InitPropose{}
+func (x *CoinShare) Reset() {
+	*x = CoinShare{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[21]
+		mi := &file_chain_proto_msgTypes[35]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *InitPropose) String() string {
+func (x *CoinShare) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InitPropose) ProtoMessage() {}
+func (*CoinShare) ProtoMessage() {}
 
-func (x *InitPropose) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[21]
+func (x *CoinShare) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[35]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2668,99 +3863,89 @@ func (x *InitPropose) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InitPropose.ProtoReflect.Descriptor instead.
-func (*InitPropose) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{21}
-}
-
-func (x *InitPropose) GetRootHash() []byte {
-	if x != nil {
-		return x.RootHash
-	}
-	return nil
+// Deprecated: Use CoinShare.ProtoReflect.Descriptor instead.
+func (*CoinShare) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{35}
 }
 
-func (x *InitPropose) GetProof() [][]byte {
+func (x *CoinShare) GetProposerId() string {
 	if x != nil {
-		return x.Proof
+		return x.ProposerId
 	}
-	return nil
+	return ""
 }
 
-func (x *InitPropose) GetIndex() int64 {
+func (x *CoinShare) GetSenderPubkey() string {
 	if x != nil {
-		return x.Index
+		return x.SenderPubkey
 	}
-	return 0
+	return ""
 }
 
-func (x *InitPropose) GetLeaves() int64 {
+func (x *CoinShare) GetEpoch() uint64 {
 	if x != nil {
-		return x.Leaves
+		return x.Epoch
 	}
 	return 0
 }
 
-func (x *InitPropose) GetOriginalDataSize() int64 {
+func (x *CoinShare) GetAcsEpoch() uint64 {
 	if x != nil {
-		return x.OriginalDataSize
+		return x.AcsEpoch
 	}
 	return 0
 }
 
-func (x *InitPropose) GetRecvNodePubkey() string {
+func (x *CoinShare) GetShare() []byte {
 	if x != nil {
-		return x.RecvNodePubkey
+		return x.Share
 	}
-	return ""
+	return nil
 }
 
-func (x *InitPropose) GetProposerPubkey() string {
+func (x *CoinShare) GetProof() []byte {
 	if x != nil {
-		return x.ProposerPubkey
+		return x.Proof
 	}
-	return ""
+	return nil
 }
 
-func (x *InitPropose) GetProposerSign() []byte {
+func (x *CoinShare) GetSenderSign() []byte {
 	if x != nil {
-		return x.ProposerSign
+		return x.SenderSign
 	}
 	return nil
 }
 
-type Echo struct {
+// threshold decryption
+type DecShare struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RootHash               []byte   `protobuf:"bytes,1,opt,name=RootHash,proto3" json:"RootHash,omitempty"`
-	Proof                  [][]byte `protobuf:"bytes,2,rep,name=Proof,proto3" json:"Proof,omitempty"`
-	Index                  int64    `protobuf:"varint,3,opt,name=Index,proto3" json:"Index,omitempty"`
-	Leaves                 int64    `protobuf:"varint,4,opt,name=Leaves,proto3" json:"Leaves,omitempty"`
-	OriginalDataSize       int64    `protobuf:"varint,5,opt,name=OriginalDataSize,proto3" json:"OriginalDataSize,omitempty"`
-	OriginalProposerPubkey string   `protobuf:"bytes,6,opt,name=OriginalProposerPubkey,proto3" json:"OriginalProposerPubkey,omitempty"` //producer make this original input
-	EchoProviderPubkey     string   `protobuf:"bytes,7,opt,name=EchoProviderPubkey,proto3" json:"EchoProviderPubkey,omitempty"`         //producer which broadcast this Echo
-	EchoProviderSign       []byte   `protobuf:"bytes,8,opt,name=EchoProviderSign,proto3" json:"EchoProviderSign,omitempty"`             //signature of producer broadcast this Echo
+	ProposerPubkey string `protobuf:"bytes,1,opt,name=ProposerPubkey,proto3" json:"ProposerPubkey,omitempty"` //proposer of the encrypted proposal
+	SenderPubkey   string `protobuf:"bytes,2,opt,name=SenderPubkey,proto3" json:"SenderPubkey,omitempty"`
+	Share          []byte `protobuf:"bytes,3,opt,name=Share,proto3" json:"Share,omitempty"`
+	Proof          []byte `protobuf:"bytes,4,opt,name=Proof,proto3" json:"Proof,omitempty"` //proof that the share is made by key share of the sender
 }
 
-func (x *Echo) Reset() {
-	*x = Echo{}
+func (x *DecShare) Reset() {
+	*x = DecShare{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[22]
+		mi := &file_chain_proto_msgTypes[36]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Echo) String() string {
+func (x *DecShare) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Echo) ProtoMessage() {}
+func (*DecShare) ProtoMessage() {}
 
-func (x *Echo) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[22]
+func (x *DecShare) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[36]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2771,95 +3956,69 @@ func (x *Echo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Echo.ProtoReflect.Descriptor instead.
-func (*Echo) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{22}
-}
-
-func (x *Echo) GetRootHash() []byte {
-	if x != nil {
-		return x.RootHash
-	}
-	return nil
-}
-
-func (x *Echo) GetProof() [][]byte {
-	if x != nil {
-		return x.Proof
-	}
-	return nil
-}
-
-func (x *Echo) GetIndex() int64 {
-	if x != nil {
-		return x.Index
-	}
-	return 0
-}
-
-func (x *Echo) GetLeaves() int64 {
-	if x != nil {
-		return x.Leaves
-	}
-	return 0
+// Deprecated: Use DecShare.ProtoReflect.Descriptor instead.
+func (*DecShare) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *Echo) GetOriginalDataSize() int64 {
+func (x *DecShare) GetProposerPubkey() string {
 	if x != nil {
-		return x.OriginalDataSize
+		return x.ProposerPubkey
 	}
-	return 0
+	return ""
 }
 
-func (x *Echo) GetOriginalProposerPubkey() string {
+func (x *DecShare) GetSenderPubkey() string {
 	if x != nil {
-		return x.OriginalProposerPubkey
+		return x.SenderPubkey
 	}
 	return ""
 }
 
-func (x *Echo) GetEchoProviderPubkey() string {
+func (x *DecShare) GetShare() []byte {
 	if x != nil {
-		return x.EchoProviderPubkey
+		return x.Share
 	}
-	return ""
+	return nil
 }
 
-func (x *Echo) GetEchoProviderSign() []byte {
+func (x *DecShare) GetProof() []byte {
 	if x != nil {
-		return x.EchoProviderSign
+		return x.Proof
 	}
 	return nil
 }
 
-type Ready struct {
+// DKG
+type DKGMsg struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RootHash               []byte `protobuf:"bytes,1,opt,name=RootHash,proto3" json:"RootHash,omitempty"`
-	OriginalProposerPubkey string `protobuf:"bytes,2,opt,name=OriginalProposerPubkey,proto3" json:"OriginalProposerPubkey,omitempty"`
-	ReadyProviderPubkey    string `protobuf:"bytes,3,opt,name=ReadyProviderPubkey,proto3" json:"ReadyProviderPubkey,omitempty"`
-	ReadyProviderSign      []byte `protobuf:"bytes,4,opt,name=ReadyProviderSign,proto3" json:"ReadyProviderSign,omitempty"`
+	Type         DKGMsgType `protobuf:"varint,1,opt,name=Type,proto3,enum=quorum.pb.DKGMsgType" json:"Type,omitempty"` //DKG_KEY / DKG_DEAL / DKG_ACK
+	Session      string     `protobuf:"bytes,2,opt,name=Session,proto3" json:"Session,omitempty"`                      //hash of the producer list
+	SenderPubkey string     `protobuf:"bytes,3,opt,name=SenderPubkey,proto3" json:"SenderPubkey,omitempty"`
+	Payload      []byte     `protobuf:"bytes,4,opt,name=Payload,proto3" json:"Payload,omitempty"`
+	SenderSign   []byte     `protobuf:"bytes,5,opt,name=SenderSign,proto3" json:"SenderSign,omitempty"`
 }
 
-func (x *Ready) Reset() {
-	*x = Ready{}
+func (x *DKGMsg) Reset() {
+	*x = DKGMsg{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[23]
+		mi := &file_chain_proto_msgTypes[37]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Ready) String() string {
+func (x *DKGMsg) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Ready) ProtoMessage() {}
+func (*DKGMsg) ProtoMessage() {}
 
-func (x *Ready) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[23]
+func (x *DKGMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[37]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2870,66 +4029,72 @@ func (x *Ready) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Ready.ProtoReflect.Descriptor instead.
-func (*Ready) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{23}
+// Deprecated: Use DKGMsg.ProtoReflect.Descriptor instead.
+func (*DKGMsg) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *Ready) GetRootHash() []byte {
+func (x *DKGMsg) GetType() DKGMsgType {
 	if x != nil {
-		return x.RootHash
+		return x.Type
 	}
-	return nil
+	return DKGMsgType_DKG_KEY
 }
 
-func (x *Ready) GetOriginalProposerPubkey() string {
+func (x *DKGMsg) GetSession() string {
 	if x != nil {
-		return x.OriginalProposerPubkey
+		return x.Session
 	}
 	return ""
 }
 
-func (x *Ready) GetReadyProviderPubkey() string {
+func (x *DKGMsg) GetSenderPubkey() string {
 	if x != nil {
-		return x.ReadyProviderPubkey
+		return x.SenderPubkey
 	}
 	return ""
 }
 
-func (x *Ready) GetReadyProviderSign() []byte {
+func (x *DKGMsg) GetPayload() []byte {
 	if x != nil {
-		return x.ReadyProviderSign
+		return x.Payload
 	}
 	return nil
 }
 
-// BBA
-type BBAMsg struct {
+func (x *DKGMsg) GetSenderSign() []byte {
+	if x != nil {
+		return x.SenderSign
+	}
+	return nil
+}
+
+type DKGDeal struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Type    BBAMsgType `protobuf:"varint,1,opt,name=Type,proto3,enum=quorum.pb.BBAMsgType" json:"Type,omitempty"` //BVAL or AUX
-	Payload []byte     `protobuf:"bytes,2,opt,name=Payload,proto3" json:"Payload,omitempty"`
+	Commitments [][]byte `protobuf:"bytes,1,rep,name=Commitments,proto3" json:"Commitments,omitempty"`
+	EncShares   [][]byte `protobuf:"bytes,2,rep,name=EncShares,proto3" json:"EncShares,omitempty"` //in order of the sorted producer list
 }
 
-func (x *BBAMsg) Reset() {
-	*x = BBAMsg{}
+func (x *DKGDeal) Reset() {
+	*x = DKGDeal{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[24]
+		mi := &file_chain_proto_msgTypes[38]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *BBAMsg) String() string {
+func (x *DKGDeal) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BBAMsg) ProtoMessage() {}
+func (*DKGDeal) ProtoMessage() {}
 
-func (x *BBAMsg) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[24]
+func (x *DKGDeal) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[38]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2940,53 +4105,53 @@ func (x *BBAMsg) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BBAMsg.ProtoReflect.Descriptor instead.
-func (*BBAMsg) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use DKGDeal.ProtoReflect.Descriptor instead.
+func (*DKGDeal) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *BBAMsg) GetType() BBAMsgType {
+func (x *DKGDeal) GetCommitments() [][]byte {
 	if x != nil {
-		return x.Type
+		return x.Commitments
 	}
-	return BBAMsgType_BVAL
+	return nil
 }
 
-func (x *BBAMsg) GetPayload() []byte {
+func (x *DKGDeal) GetEncShares() [][]byte {
 	if x != nil {
-		return x.Payload
+		return x.EncShares
 	}
 	return nil
 }
 
-type Bval struct {
+type DKGEcho struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ProposerId   string `protobuf:"bytes,1,opt,name=ProposerId,proto3" json:"ProposerId,omitempty"`
-	SenderPubkey string `protobuf:"bytes,2,opt,name=SenderPubkey,proto3" json:"SenderPubkey,omitempty"`
-	Epoch        int64  `protobuf:"varint,3,opt,name=Epoch,proto3" json:"Epoch,omitempty"`
-	Value        bool   `protobuf:"varint,4,opt,name=Value,proto3" json:"Value,omitempty"`
+	Dealer    string `protobuf:"bytes,1,opt,name=Dealer,proto3" json:"Dealer,omitempty"`
+	DealHash  []byte `protobuf:"bytes,2,opt,name=DealHash,proto3" json:"DealHash,omitempty"`   //hash of the DKG_DEAL payload
+	SharedKey []byte `protobuf:"bytes,3,opt,name=SharedKey,proto3" json:"SharedKey,omitempty"` //complaint, dh key shared with the dealer to show the share is invalid
+	Proof     []byte `protobuf:"bytes,4,opt,name=Proof,proto3" json:"Proof,omitempty"`         //proof that SharedKey is made by dh key of the sender
 }
 
-func (x *Bval) Reset() {
-	*x = Bval{}
+func (x *DKGEcho) Reset() {
+	*x = DKGEcho{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[25]
+		mi := &file_chain_proto_msgTypes[39]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Bval) String() string {
+func (x *DKGEcho) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Bval) ProtoMessage() {}
+func (*DKGEcho) ProtoMessage() {}
 
-func (x *Bval) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[25]
+func (x *DKGEcho) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[39]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2997,67 +4162,126 @@ func (x *Bval) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Bval.ProtoReflect.Descriptor instead.
-func (*Bval) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{25}
+// Deprecated: Use DKGEcho.ProtoReflect.Descriptor instead.
+func (*DKGEcho) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *Bval) GetProposerId() string {
+func (x *DKGEcho) GetDealer() string {
 	if x != nil {
-		return x.ProposerId
+		return x.Dealer
 	}
 	return ""
 }
 
-func (x *Bval) GetSenderPubkey() string {
+func (x *DKGEcho) GetDealHash() []byte {
 	if x != nil {
-		return x.SenderPubkey
+		return x.DealHash
 	}
-	return ""
+	return nil
 }
 
-func (x *Bval) GetEpoch() int64 {
+func (x *DKGEcho) GetSharedKey() []byte {
 	if x != nil {
-		return x.Epoch
+		return x.SharedKey
 	}
-	return 0
+	return nil
 }
 
-func (x *Bval) GetValue() bool {
+func (x *DKGEcho) GetProof() []byte {
 	if x != nil {
-		return x.Value
+		return x.Proof
 	}
-	return false
+	return nil
 }
 
-type Aux struct {
+type DKGAck struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ProposerId   string `protobuf:"bytes,1,opt,name=ProposerId,proto3" json:"ProposerId,omitempty"`
-	SenderPubkey string `protobuf:"bytes,2,opt,name=SenderPubkey,proto3" json:"SenderPubkey,omitempty"`
-	Epoch        uint64 `protobuf:"varint,3,opt,name=Epoch,proto3" json:"Epoch,omitempty"`
-	Value        bool   `protobuf:"varint,4,opt,name=Value,proto3" json:"Value,omitempty"`
+	GroupPubkey []byte   `protobuf:"bytes,1,opt,name=GroupPubkey,proto3" json:"GroupPubkey,omitempty"`
+	Dealers     []string `protobuf:"bytes,2,rep,name=Dealers,proto3" json:"Dealers,omitempty"` //dealers of the key
 }
 
-func (x *Aux) Reset() {
-	*x = Aux{}
+func (x *DKGAck) Reset() {
+	*x = DKGAck{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[26]
+		mi := &file_chain_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DKGAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DKGAck) ProtoMessage() {}
+
+func (x *DKGAck) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DKGAck.ProtoReflect.Descriptor instead.
+func (*DKGAck) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *DKGAck) GetGroupPubkey() []byte {
+	if x != nil {
+		return x.GroupPubkey
+	}
+	return nil
+}
+
+func (x *DKGAck) GetDealers() []string {
+	if x != nil {
+		return x.Dealers
+	}
+	return nil
+}
+
+type HBThresholdKey struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Session     string   `protobuf:"bytes,1,opt,name=Session,proto3" json:"Session,omitempty"`
+	Nodes       []string `protobuf:"bytes,2,rep,name=Nodes,proto3" json:"Nodes,omitempty"`
+	Threshold   int32    `protobuf:"varint,3,opt,name=Threshold,proto3" json:"Threshold,omitempty"`
+	GroupPubkey []byte   `protobuf:"bytes,4,opt,name=GroupPubkey,proto3" json:"GroupPubkey,omitempty"`
+	VerifyKeys  [][]byte `protobuf:"bytes,5,rep,name=VerifyKeys,proto3" json:"VerifyKeys,omitempty"`
+	Share       []byte   `protobuf:"bytes,6,opt,name=Share,proto3" json:"Share,omitempty"`
+	Ready       bool     `protobuf:"varint,7,opt,name=Ready,proto3" json:"Ready,omitempty"`    //acked by enough producers, proposals can be encrypted with it
+	Dealers     []string `protobuf:"bytes,9,rep,name=Dealers,proto3" json:"Dealers,omitempty"` //dealers of the key
+}
+
+func (x *HBThresholdKey) Reset() {
+	*x = HBThresholdKey{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[41]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Aux) String() string {
+func (x *HBThresholdKey) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Aux) ProtoMessage() {}
+func (*HBThresholdKey) ProtoMessage() {}
 
-func (x *Aux) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[26]
+func (x *HBThresholdKey) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[41]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3068,39 +4292,67 @@ func (x *Aux) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Aux.ProtoReflect.Descriptor instead.
-func (*Aux) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{26}
+// Deprecated: Use HBThresholdKey.ProtoReflect.Descriptor instead.
+func (*HBThresholdKey) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{41}
 }
 
-func (x *Aux) GetProposerId() string {
+func (x *HBThresholdKey) GetSession() string {
 	if x != nil {
-		return x.ProposerId
+		return x.Session
 	}
 	return ""
 }
 
-func (x *Aux) GetSenderPubkey() string {
+func (x *HBThresholdKey) GetNodes() []string {
 	if x != nil {
-		return x.SenderPubkey
+		return x.Nodes
 	}
-	return ""
+	return nil
 }
 
-func (x *Aux) GetEpoch() uint64 {
+func (x *HBThresholdKey) GetThreshold() int32 {
 	if x != nil {
-		return x.Epoch
+		return x.Threshold
 	}
 	return 0
 }
 
-func (x *Aux) GetValue() bool {
+func (x *HBThresholdKey) GetGroupPubkey() []byte {
 	if x != nil {
-		return x.Value
+		return x.GroupPubkey
+	}
+	return nil
+}
+
+func (x *HBThresholdKey) GetVerifyKeys() [][]byte {
+	if x != nil {
+		return x.VerifyKeys
+	}
+	return nil
+}
+
+func (x *HBThresholdKey) GetShare() []byte {
+	if x != nil {
+		return x.Share
+	}
+	return nil
+}
+
+func (x *HBThresholdKey) GetReady() bool {
+	if x != nil {
+		return x.Ready
 	}
 	return false
 }
 
+func (x *HBThresholdKey) GetDealers() []string {
+	if x != nil {
+		return x.Dealers
+	}
+	return nil
+}
+
 //old proto msg
 type GroupItemV0 struct {
 	state         protoimpl.MessageState
@@ -3126,7 +4378,7 @@ type GroupItemV0 struct {
 func (x *GroupItemV0) Reset() {
 	*x = GroupItemV0{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[27]
+		mi := &file_chain_proto_msgTypes[42]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3139,7 +4391,7 @@ func (x *GroupItemV0) String() string {
 func (*GroupItemV0) ProtoMessage() {}
 
 func (x *GroupItemV0) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[27]
+	mi := &file_chain_proto_msgTypes[42]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3152,7 +4404,7 @@ func (x *GroupItemV0) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GroupItemV0.ProtoReflect.Descriptor instead.
 func (*GroupItemV0) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{27}
+	return file_chain_proto_rawDescGZIP(), []int{42}
 }
 
 func (x *GroupItemV0) GetGroupId() string {
@@ -3285,7 +4537,7 @@ var file_chain_proto_rawDesc = []byte{
 	0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62,
 	0x2e, 0x54, 0x72, 0x78, 0x53, 0x74, 0x72, 0x6f, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52,
 	0x0b, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x4a, 0x04, 0x08, 0x09,
-	0x10, 0x0a, 0x22, 0xad, 0x02, 0x0a, 0x05, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x18, 0x0a, 0x07,
+	0x10, 0x0a, 0x22, 0xc9, 0x02, 0x0a, 0x05, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x18, 0x0a, 0x07,
 	0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47,
 	0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49,
 	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64,
@@ -3304,309 +4556,463 @@ var file_chain_proto_rawDesc = []byte{
 	0x01, 0x28, 0x0c, 0x52, 0x09, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x12, 0x22,
 	0x0a, 0x0c, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x0a,
 	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x53, 0x69,
-	0x67, 0x6e, 0x22, 0x86, 0x01, 0x0a, 0x08, 0x52, 0x65, 0x71, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12,
-	0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x46, 0x72, 0x6f,
-	0x6d, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x46, 0x72,
-	0x6f, 0x6d, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x24, 0x0a, 0x0d, 0x42, 0x6c, 0x6b, 0x73, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d,
-	0x42, 0x6c, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x12, 0x1c, 0x0a,
-	0x09, 0x52, 0x65, 0x71, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x09, 0x52, 0x65, 0x71, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x22, 0x38, 0x0a, 0x0c, 0x42,
-	0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x12, 0x28, 0x0a, 0x06, 0x42,
-	0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x71, 0x75,
-	0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x06, 0x42,
-	0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x22, 0xc4, 0x02, 0x0a, 0x0c, 0x52, 0x65, 0x71, 0x42, 0x6c, 0x6f,
-	0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64,
-	0x12, 0x28, 0x0a, 0x0f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x72, 0x50, 0x75, 0x62,
-	0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x50, 0x72,
-	0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0e, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b,
-	0x65, 0x79, 0x12, 0x2f, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x04, 0x20, 0x01,
-	0x28, 0x0e, 0x32, 0x17, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x52,
-	0x65, 0x71, 0x42, 0x6c, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x52, 0x65, 0x73,
-	0x75, 0x6c, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x46, 0x72, 0x6f, 0x6d, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x46, 0x72, 0x6f, 0x6d, 0x42, 0x6c, 0x6f, 0x63,
-	0x6b, 0x12, 0x24, 0x0a, 0x0d, 0x42, 0x6c, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x42, 0x6c, 0x6b, 0x73, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x42, 0x6c, 0x6b, 0x73, 0x50,
-	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x42,
-	0x6c, 0x6b, 0x73, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x64, 0x12, 0x2f, 0x0a, 0x06, 0x42,
-	0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x71, 0x75,
-	0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x42, 0x75,
-	0x6e, 0x64, 0x6c, 0x65, 0x52, 0x06, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x22, 0x7c, 0x0a, 0x08,
-	0x50, 0x6f, 0x73, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x72, 0x78, 0x49,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x54, 0x72, 0x78, 0x49, 0x64, 0x12, 0x22,
-	0x0a, 0x0c, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b,
-	0x65, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09,
-	0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x22, 0xad, 0x02, 0x0a, 0x0c, 0x50,
-	0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47,
+	0x67, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x54, 0x72, 0x78, 0x73, 0x52, 0x6f, 0x6f, 0x74, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x54, 0x72, 0x78, 0x73, 0x52, 0x6f, 0x6f, 0x74, 0x22, 0x86,
+	0x01, 0x0a, 0x08, 0x52, 0x65, 0x71, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x47,
 	0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72,
-	0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65,
-	0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x50,
-	0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2a, 0x0a,
-	0x10, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65,
-	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77,
-	0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x47, 0x72, 0x6f,
-	0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67,
-	0x6e, 0x12, 0x2d, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x0e, 0x32, 0x15, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x12, 0x26, 0x0a, 0x0e, 0x57, 0x69, 0x74, 0x68, 0x6e, 0x65, 0x73, 0x73, 0x42, 0x6c, 0x6f, 0x63,
-	0x6b, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x57, 0x69, 0x74, 0x68, 0x6e, 0x65,
-	0x73, 0x73, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65,
-	0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d,
-	0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x18, 0x08,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x22, 0x4e, 0x0a, 0x15, 0x42, 0x46,
-	0x54, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x49,
-	0x74, 0x65, 0x6d, 0x12, 0x35, 0x0a, 0x09, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e,
-	0x70, 0x62, 0x2e, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x49, 0x74, 0x65, 0x6d, 0x52,
-	0x09, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x73, 0x22, 0x9f, 0x02, 0x0a, 0x08, 0x55,
-	0x73, 0x65, 0x72, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70,
-	0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49,
-	0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x55, 0x73, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x55, 0x73, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65,
-	0x79, 0x12, 0x24, 0x0a, 0x0d, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x50, 0x75, 0x62, 0x6b,
-	0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70,
-	0x74, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70,
-	0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62,
-	0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65,
-	0x72, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x47, 0x72, 0x6f,
-	0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x54,
-	0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x46, 0x72, 0x6f, 0x6d, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x46, 0x72, 0x6f, 0x6d, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x12, 0x24, 0x0a, 0x0d, 0x42, 0x6c, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x42, 0x6c, 0x6b, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x52, 0x65, 0x71,
+	0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x52, 0x65,
+	0x71, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x22, 0x38, 0x0a, 0x0c, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x73, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x12, 0x28, 0x0a, 0x06, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d,
+	0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x06, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x73, 0x22, 0xc4, 0x02, 0x0a, 0x0c, 0x52, 0x65, 0x71, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x65,
+	0x73, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x28, 0x0a, 0x0f,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x72,
+	0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64,
+	0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2f,
+	0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17,
+	0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x42, 0x6c,
+	0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12,
+	0x1c, 0x0a, 0x09, 0x46, 0x72, 0x6f, 0x6d, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x09, 0x46, 0x72, 0x6f, 0x6d, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x24, 0x0a,
+	0x0d, 0x42, 0x6c, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x42, 0x6c, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x65, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x42, 0x6c, 0x6b, 0x73, 0x50, 0x72, 0x6f, 0x76, 0x69,
+	0x64, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x42, 0x6c, 0x6b, 0x73, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x64, 0x12, 0x2f, 0x0a, 0x06, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d,
+	0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65,
+	0x52, 0x06, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x22, 0x36, 0x0a, 0x0c, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x10, 0x0a, 0x03, 0x4b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x4b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x22, 0xf3, 0x01, 0x0a, 0x08, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x18, 0x0a,
+	0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x26, 0x0a,
+	0x05, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x71,
+	0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x05,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x2d, 0x0a, 0x05, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62,
+	0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x49,
+	0x74, 0x65, 0x6d, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d,
+	0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61,
+	0x6d, 0x70, 0x12, 0x22, 0x0a, 0x0c, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b,
+	0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72,
+	0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72,
+	0x53, 0x69, 0x67, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x53, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x22, 0x45, 0x0a, 0x0b, 0x52, 0x65, 0x71, 0x53, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12,
+	0x1c, 0x0a, 0x09, 0x52, 0x65, 0x71, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x52, 0x65, 0x71, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x22, 0xae, 0x01,
+	0x0a, 0x0f, 0x52, 0x65, 0x71, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x28, 0x0a, 0x0f, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x72, 0x50,
+	0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65,
+	0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2f, 0x0a,
+	0x08, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x13, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x52, 0x08, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x22, 0x7c,
+	0x0a, 0x08, 0x50, 0x6f, 0x73, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x72,
+	0x78, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x54, 0x72, 0x78, 0x49, 0x64,
+	0x12, 0x22, 0x0a, 0x0c, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x50, 0x75,
+	0x62, 0x6b, 0x65, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x22, 0xad, 0x02, 0x0a,
+	0x0c, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a,
+	0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x64, 0x75,
+	0x63, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0e, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12,
+	0x2a, 0x0a, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62,
+	0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53,
+	0x69, 0x67, 0x6e, 0x12, 0x2d, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x0e, 0x57, 0x69, 0x74, 0x68, 0x6e, 0x65, 0x73, 0x73, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x57, 0x69, 0x74, 0x68,
+	0x6e, 0x65, 0x73, 0x73, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69,
+	0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54,
+	0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x4d, 0x65, 0x6d, 0x6f,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x22, 0x4e, 0x0a, 0x15,
+	0x42, 0x46, 0x54, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x42, 0x75, 0x6e, 0x64, 0x6c,
+	0x65, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x35, 0x0a, 0x09, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65,
+	0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75,
+	0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x49, 0x74, 0x65,
+	0x6d, 0x52, 0x09, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x73, 0x22, 0xc2, 0x01, 0x0a,
+	0x09, 0x53, 0x74, 0x61, 0x6b, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x53, 0x74, 0x61, 0x6b, 0x65, 0x72, 0x50, 0x75,
+	0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x53, 0x74, 0x61, 0x6b,
+	0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2d, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75,
+	0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x6d, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x12, 0x0a,
+	0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4d, 0x65, 0x6d,
+	0x6f, 0x22, 0x9f, 0x02, 0x0a, 0x08, 0x55, 0x73, 0x65, 0x72, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x18,
+	0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x55, 0x73, 0x65, 0x72,
+	0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x55, 0x73,
+	0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x45, 0x6e, 0x63, 0x72,
+	0x79, 0x70, 0x74, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2a,
+	0x0a, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b,
+	0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f,
+	0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69,
+	0x67, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70,
+	0x12, 0x2d, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x15, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4d,
+	0x65, 0x6d, 0x6f, 0x22, 0xa6, 0x03, 0x0a, 0x0c, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65,
+	0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x1e,
+	0x0a, 0x0a, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x24,
+	0x0a, 0x0d, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x50, 0x75,
+	0x62, 0x6b, 0x65, 0x79, 0x12, 0x2e, 0x0a, 0x12, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65,
+	0x72, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x12, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x12, 0x2b, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x17, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x41,
+	0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x20, 0x0a, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62,
+	0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x4f, 0x77, 0x6e,
+	0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x2e, 0x0a, 0x06, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x71, 0x75,
+	0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x54,
+	0x79, 0x70, 0x65, 0x52, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x54,
+	0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
 	0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x2d, 0x0a, 0x06, 0x41, 0x63, 0x74,
-	0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x71, 0x75, 0x6f, 0x72,
+	0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x71, 0x75, 0x6f, 0x72,
 	0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65,
 	0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x4d, 0x65, 0x6d, 0x6f,
-	0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x22, 0xa6, 0x03, 0x0a,
-	0x0c, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a,
-	0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
-	0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x53, 0x69, 0x67, 0x6e, 0x50,
-	0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x53, 0x69, 0x67,
-	0x6e, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x45, 0x6e, 0x63, 0x72, 0x79,
-	0x70, 0x74, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d,
-	0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2e, 0x0a,
-	0x12, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74,
-	0x75, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x41, 0x6e, 0x6e, 0x6f, 0x75,
-	0x6e, 0x63, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x2b, 0x0a,
-	0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x71, 0x75,
-	0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65,
-	0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x4f, 0x77,
-	0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e,
-	0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x07,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x61,
-	0x74, 0x75, 0x72, 0x65, 0x12, 0x2e, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x08,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62,
-	0x2e, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06, 0x52, 0x65,
-	0x73, 0x75, 0x6c, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d,
-	0x70, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61,
-	0x6d, 0x70, 0x12, 0x2d, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x22, 0xc8, 0x03, 0x0a,
+	0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65,
+	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75,
+	0x62, 0x4b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x55, 0x73, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e,
+	0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x55, 0x73,
+	0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x11,
+	0x55, 0x73, 0x65, 0x72, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x50, 0x75, 0x62, 0x6b, 0x65,
+	0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x55, 0x73, 0x65, 0x72, 0x45, 0x6e, 0x63,
+	0x72, 0x79, 0x70, 0x74, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x4c, 0x61,
+	0x73, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a,
+	0x4c, 0x61, 0x73, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x34, 0x0a, 0x0c, 0x47, 0x65,
+	0x6e, 0x65, 0x73, 0x69, 0x73, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x10, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x52, 0x0c, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x12, 0x3d, 0x0a, 0x0b, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70,
+	0x62, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x0b, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x40, 0x0a, 0x0c, 0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1c, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70,
+	0x62, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x65, 0x54,
+	0x79, 0x70, 0x65, 0x52, 0x0c, 0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x65, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x12,
+	0x16, 0x0a, 0x06, 0x41, 0x70, 0x70, 0x4b, 0x65, 0x79, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x41, 0x70, 0x70, 0x4b, 0x65, 0x79, 0x22, 0xeb, 0x01, 0x0a, 0x0f, 0x43, 0x68, 0x61, 0x69,
+	0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x2e, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e,
+	0x43, 0x68, 0x61, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x04, 0x44, 0x61, 0x74, 0x61, 0x12, 0x20, 0x0a, 0x0b, 0x4f, 0x77, 0x6e,
+	0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x4f,
+	0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0e, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d,
+	0x70, 0x12, 0x12, 0x0a, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x22, 0x89, 0x01, 0x0a, 0x18, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x53,
+	0x65, 0x6e, 0x64, 0x54, 0x72, 0x78, 0x52, 0x75, 0x6c, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x74,
+	0x65, 0x6d, 0x12, 0x2d, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x0e, 0x32, 0x15, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x41,
 	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x12, 0x0a, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x22, 0xc8, 0x03, 0x0a, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49,
-	0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x1c, 0x0a,
-	0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x4f,
-	0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x26, 0x0a,
-	0x0e, 0x55, 0x73, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x55, 0x73, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x50,
-	0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x11, 0x55, 0x73, 0x65, 0x72, 0x45, 0x6e, 0x63,
-	0x72, 0x79, 0x70, 0x74, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x11, 0x55, 0x73, 0x65, 0x72, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x50, 0x75, 0x62,
-	0x6b, 0x65, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x4c, 0x61, 0x73, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74,
-	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x4c, 0x61, 0x73, 0x74, 0x55, 0x70, 0x64,
-	0x61, 0x74, 0x65, 0x12, 0x34, 0x0a, 0x0c, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x42, 0x6c,
-	0x6f, 0x63, 0x6b, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x71, 0x75, 0x6f, 0x72,
-	0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x0c, 0x47, 0x65, 0x6e,
-	0x65, 0x73, 0x69, 0x73, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x3d, 0x0a, 0x0b, 0x45, 0x6e, 0x63,
-	0x72, 0x79, 0x70, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b,
-	0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70,
-	0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0b, 0x45, 0x6e, 0x63,
-	0x72, 0x79, 0x70, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x40, 0x0a, 0x0c, 0x43, 0x6f, 0x6e, 0x73,
-	0x65, 0x6e, 0x73, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1c,
-	0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70,
-	0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0c, 0x43, 0x6f,
-	0x6e, 0x73, 0x65, 0x6e, 0x73, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x69,
-	0x70, 0x68, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x43,
-	0x69, 0x70, 0x68, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x70, 0x70, 0x4b,
-	0x65, 0x79, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x41, 0x70, 0x70, 0x4b, 0x65, 0x79,
-	0x22, 0xeb, 0x01, 0x0a, 0x0f, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x2e,
-	0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x71,
-	0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x43, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12,
-	0x0a, 0x04, 0x44, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x44, 0x61,
-	0x74, 0x61, 0x12, 0x20, 0x0a, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65,
-	0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75,
-	0x62, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67,
-	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x4f, 0x77,
-	0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x1c, 0x0a, 0x09,
-	0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x4d, 0x65,
-	0x6d, 0x6f, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x22, 0x89,
-	0x01, 0x0a, 0x18, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x72, 0x78, 0x52,
-	0x75, 0x6c, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x2d, 0x0a, 0x06, 0x41,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x71, 0x75,
-	0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79,
-	0x70, 0x65, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x50, 0x75,
-	0x62, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x50, 0x75, 0x62, 0x6b,
-	0x65, 0x79, 0x12, 0x26, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0e,
-	0x32, 0x12, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x54, 0x72, 0x78,
-	0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x22, 0x68, 0x0a, 0x12, 0x53, 0x65,
-	0x74, 0x54, 0x72, 0x78, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x49, 0x74, 0x65, 0x6d,
-	0x12, 0x26, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12,
-	0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x54, 0x72, 0x78, 0x54, 0x79,
-	0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2a, 0x0a, 0x04, 0x4d, 0x6f, 0x64, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e,
-	0x70, 0x62, 0x2e, 0x54, 0x72, 0x78, 0x41, 0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04,
-	0x4d, 0x6f, 0x64, 0x65, 0x22, 0xa2, 0x02, 0x0a, 0x0d, 0x41, 0x70, 0x70, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64,
-	0x12, 0x2d, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
-	0x32, 0x15, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x63, 0x74,
-	0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
-	0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e,
-	0x61, 0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x0e, 0x32, 0x18, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x70,
-	0x70, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70,
-	0x65, 0x12, 0x14, 0x0a, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72,
-	0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x4f, 0x77,
-	0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x4f, 0x77, 0x6e,
-	0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x4f, 0x77,
-	0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x18,
-	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x54,
-	0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
-	0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x22, 0xbd, 0x02, 0x0a, 0x09, 0x47, 0x72,
-	0x6f, 0x75, 0x70, 0x53, 0x65, 0x65, 0x64, 0x12, 0x34, 0x0a, 0x0c, 0x47, 0x65, 0x6e, 0x65, 0x73,
-	0x69, 0x73, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e,
-	0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52,
-	0x0c, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x18, 0x0a,
-	0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
-	0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70,
-	0x4e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x47, 0x72, 0x6f, 0x75,
-	0x70, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75,
-	0x62, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x4f, 0x77, 0x6e, 0x65,
-	0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x73, 0x65,
-	0x6e, 0x73, 0x75, 0x73, 0x54, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d,
-	0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x26, 0x0a,
-	0x0e, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x18,
-	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f,
-	0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x4b,
-	0x65, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72,
-	0x4b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x70, 0x70, 0x4b, 0x65, 0x79, 0x18, 0x08, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x06, 0x41, 0x70, 0x70, 0x4b, 0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x53,
-	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
-	0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0xb6, 0x01, 0x0a, 0x10, 0x4e, 0x6f,
-	0x64, 0x65, 0x53, 0x44, 0x4b, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x2a,
-	0x0a, 0x05, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
-	0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49,
-	0x74, 0x65, 0x6d, 0x52, 0x05, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x22, 0x0a, 0x0c, 0x45, 0x6e,
-	0x63, 0x72, 0x79, 0x70, 0x74, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0c, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x1c,
-	0x0a, 0x09, 0x53, 0x69, 0x67, 0x6e, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x09, 0x53, 0x69, 0x67, 0x6e, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x16, 0x0a, 0x06,
-	0x41, 0x70, 0x69, 0x55, 0x72, 0x6c, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x41, 0x70,
-	0x69, 0x55, 0x72, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x53, 0x65, 0x65,
-	0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x53, 0x65,
-	0x65, 0x64, 0x22, 0x31, 0x0a, 0x0b, 0x48, 0x42, 0x54, 0x72, 0x78, 0x42, 0x75, 0x6e, 0x64, 0x6c,
-	0x65, 0x12, 0x22, 0x0a, 0x04, 0x54, 0x72, 0x78, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x0e, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x54, 0x72, 0x78, 0x52,
-	0x04, 0x54, 0x72, 0x78, 0x73, 0x22, 0x8e, 0x01, 0x0a, 0x07, 0x48, 0x42, 0x4d, 0x73, 0x67, 0x76,
-	0x31, 0x12, 0x14, 0x0a, 0x05, 0x4d, 0x73, 0x67, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x05, 0x4d, 0x73, 0x67, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x3d, 0x0a,
-	0x0b, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x48,
-	0x42, 0x4d, 0x73, 0x67, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x54, 0x79, 0x70, 0x65, 0x52,
-	0x0b, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07,
-	0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x50,
-	0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x4d, 0x0a, 0x06, 0x52, 0x42, 0x43, 0x4d, 0x73, 0x67,
-	0x12, 0x29, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15,
-	0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x42, 0x43, 0x4d, 0x73,
-	0x67, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x50,
-	0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x50, 0x61,
-	0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x8d, 0x02, 0x0a, 0x0b, 0x49, 0x6e, 0x69, 0x74, 0x50, 0x72,
-	0x6f, 0x70, 0x6f, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73,
+	0x6e, 0x12, 0x16, 0x0a, 0x06, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x04, 0x54, 0x79, 0x70,
+	0x65, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d,
+	0x2e, 0x70, 0x62, 0x2e, 0x54, 0x72, 0x78, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70,
+	0x65, 0x22, 0x68, 0x0a, 0x12, 0x53, 0x65, 0x74, 0x54, 0x72, 0x78, 0x41, 0x75, 0x74, 0x68, 0x4d,
+	0x6f, 0x64, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x26, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70,
+	0x62, 0x2e, 0x54, 0x72, 0x78, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x2a, 0x0a, 0x04, 0x4d, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e,
+	0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x54, 0x72, 0x78, 0x41, 0x75, 0x74,
+	0x68, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x4d, 0x6f, 0x64, 0x65, 0x22, 0xa1, 0x01, 0x0a, 0x11,
+	0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x49, 0x74, 0x65,
+	0x6d, 0x12, 0x1c, 0x0a, 0x09, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x69, 0x7a, 0x65, 0x12,
+	0x2a, 0x0a, 0x10, 0x4d, 0x61, 0x78, 0x54, 0x72, 0x78, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x6e,
+	0x64, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x4d, 0x61, 0x78, 0x54, 0x72,
+	0x78, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x24, 0x0a, 0x0d, 0x4d,
+	0x61, 0x78, 0x42, 0x75, 0x66, 0x66, 0x65, 0x72, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0d, 0x4d, 0x61, 0x78, 0x42, 0x75, 0x66, 0x66, 0x65, 0x72, 0x53, 0x69, 0x7a,
+	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x4d, 0x61, 0x78, 0x54, 0x72, 0x78, 0x41, 0x67, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x4d, 0x61, 0x78, 0x54, 0x72, 0x78, 0x41, 0x67, 0x65, 0x22,
+	0x81, 0x01, 0x0a, 0x0f, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x50, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x49,
+	0x74, 0x65, 0x6d, 0x12, 0x20, 0x0a, 0x0b, 0x4d, 0x69, 0x6e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x4d, 0x69, 0x6e, 0x49, 0x6e, 0x74,
+	0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x22, 0x0a, 0x0c, 0x42, 0x61, 0x73, 0x65, 0x49, 0x6e, 0x74,
+	0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x42, 0x61, 0x73,
+	0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x28, 0x0a, 0x0f, 0x4d, 0x61, 0x78,
+	0x49, 0x64, 0x6c, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0f, 0x4d, 0x61, 0x78, 0x49, 0x64, 0x6c, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72,
+	0x76, 0x61, 0x6c, 0x22, 0xa2, 0x02, 0x0a, 0x0d, 0x41, 0x70, 0x70, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12,
+	0x2d, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x15, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12,
+	0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x18, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x70, 0x70,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50,
+	0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x4f, 0x77, 0x6e,
+	0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x4f, 0x77, 0x6e, 0x65,
+	0x72, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x4f, 0x77, 0x6e,
+	0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69,
+	0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54,
+	0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x22, 0xbd, 0x02, 0x0a, 0x09, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x53, 0x65, 0x65, 0x64, 0x12, 0x34, 0x0a, 0x0c, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69,
+	0x73, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x71,
+	0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x0c,
+	0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x18, 0x0a, 0x07,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4e,
+	0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62,
+	0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72,
+	0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e,
+	0x73, 0x75, 0x73, 0x54, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x43,
+	0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x26, 0x0a, 0x0e,
+	0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x4b, 0x65,
+	0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x4b,
+	0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x70, 0x70, 0x4b, 0x65, 0x79, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x41, 0x70, 0x70, 0x4b, 0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x53, 0x69,
+	0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x53,
+	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0xb6, 0x01, 0x0a, 0x10, 0x4e, 0x6f, 0x64,
+	0x65, 0x53, 0x44, 0x4b, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x2a, 0x0a,
+	0x05, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x71,
+	0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x74,
+	0x65, 0x6d, 0x52, 0x05, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x22, 0x0a, 0x0c, 0x45, 0x6e, 0x63,
+	0x72, 0x79, 0x70, 0x74, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x1c, 0x0a,
+	0x09, 0x53, 0x69, 0x67, 0x6e, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x53, 0x69, 0x67, 0x6e, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x41,
+	0x70, 0x69, 0x55, 0x72, 0x6c, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x41, 0x70, 0x69,
+	0x55, 0x72, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x53, 0x65, 0x65, 0x64,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x53, 0x65, 0x65,
+	0x64, 0x22, 0x4f, 0x0a, 0x0b, 0x48, 0x42, 0x54, 0x72, 0x78, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65,
+	0x12, 0x22, 0x0a, 0x04, 0x54, 0x72, 0x78, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e,
+	0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x54, 0x72, 0x78, 0x52, 0x04,
+	0x54, 0x72, 0x78, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d,
+	0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61,
+	0x6d, 0x70, 0x22, 0x8e, 0x01, 0x0a, 0x07, 0x48, 0x42, 0x4d, 0x73, 0x67, 0x76, 0x31, 0x12, 0x14,
+	0x0a, 0x05, 0x4d, 0x73, 0x67, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x4d,
+	0x73, 0x67, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x3d, 0x0a, 0x0b, 0x50, 0x61,
+	0x79, 0x6c, 0x6f, 0x61, 0x64, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x1b, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x48, 0x42, 0x4d, 0x73,
+	0x67, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0b, 0x50, 0x61,
+	0x79, 0x6c, 0x6f, 0x61, 0x64, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x61, 0x79,
+	0x6c, 0x6f, 0x61, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x50, 0x61, 0x79, 0x6c,
+	0x6f, 0x61, 0x64, 0x22, 0x4d, 0x0a, 0x06, 0x52, 0x42, 0x43, 0x4d, 0x73, 0x67, 0x12, 0x29, 0x0a,
+	0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x71, 0x75,
+	0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x42, 0x43, 0x4d, 0x73, 0x67, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x61, 0x79, 0x6c,
+	0x6f, 0x61, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f,
+	0x61, 0x64, 0x22, 0x8d, 0x02, 0x0a, 0x0b, 0x49, 0x6e, 0x69, 0x74, 0x50, 0x72, 0x6f, 0x70, 0x6f,
+	0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x14,
+	0x0a, 0x05, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x05, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x12, 0x14, 0x0a, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x4c, 0x65,
+	0x61, 0x76, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x4c, 0x65, 0x61, 0x76,
+	0x65, 0x73, 0x12, 0x2a, 0x0a, 0x10, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x44, 0x61,
+	0x74, 0x61, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x4f, 0x72,
+	0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x26,
+	0x0a, 0x0e, 0x52, 0x65, 0x63, 0x76, 0x4e, 0x6f, 0x64, 0x65, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x52, 0x65, 0x63, 0x76, 0x4e, 0x6f, 0x64, 0x65,
+	0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73,
+	0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x22,
+	0x0a, 0x0c, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x53, 0x69,
+	0x67, 0x6e, 0x22, 0xa6, 0x02, 0x0a, 0x04, 0x45, 0x63, 0x68, 0x6f, 0x12, 0x1a, 0x0a, 0x08, 0x52,
+	0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x52,
+	0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x05, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x14, 0x0a,
+	0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x06, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x10, 0x4f,
+	0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x53, 0x69, 0x7a, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x44,
+	0x61, 0x74, 0x61, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x36, 0x0a, 0x16, 0x4f, 0x72, 0x69, 0x67, 0x69,
+	0x6e, 0x61, 0x6c, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65,
+	0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61,
+	0x6c, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12,
+	0x2e, 0x0a, 0x12, 0x45, 0x63, 0x68, 0x6f, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x50,
+	0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x45, 0x63, 0x68,
+	0x6f, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12,
+	0x2a, 0x0a, 0x10, 0x45, 0x63, 0x68, 0x6f, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x53,
+	0x69, 0x67, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x45, 0x63, 0x68, 0x6f, 0x50,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x22, 0xe7, 0x01, 0x0a, 0x05,
+	0x52, 0x65, 0x61, 0x64, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73,
 	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73,
-	0x68, 0x12, 0x14, 0x0a, 0x05, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c,
-	0x52, 0x05, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x14, 0x0a, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x16, 0x0a,
-	0x06, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x4c,
-	0x65, 0x61, 0x76, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x10, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61,
-	0x6c, 0x44, 0x61, 0x74, 0x61, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x10, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x53, 0x69, 0x7a,
-	0x65, 0x12, 0x26, 0x0a, 0x0e, 0x52, 0x65, 0x63, 0x76, 0x4e, 0x6f, 0x64, 0x65, 0x50, 0x75, 0x62,
-	0x6b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x52, 0x65, 0x63, 0x76, 0x4e,
-	0x6f, 0x64, 0x65, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x50, 0x72, 0x6f,
-	0x70, 0x6f, 0x73, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0e, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65,
-	0x79, 0x12, 0x22, 0x0a, 0x0c, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x53, 0x69, 0x67,
-	0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65,
-	0x72, 0x53, 0x69, 0x67, 0x6e, 0x22, 0xa6, 0x02, 0x0a, 0x04, 0x45, 0x63, 0x68, 0x6f, 0x12, 0x1a,
-	0x0a, 0x08, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x08, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x50, 0x72,
-	0x6f, 0x6f, 0x66, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x05, 0x50, 0x72, 0x6f, 0x6f, 0x66,
-	0x12, 0x14, 0x0a, 0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x05, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x73,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x4c, 0x65, 0x61, 0x76, 0x65, 0x73, 0x12, 0x2a,
-	0x0a, 0x10, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x53, 0x69,
-	0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e,
-	0x61, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x36, 0x0a, 0x16, 0x4f, 0x72,
-	0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x50, 0x75,
-	0x62, 0x6b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x4f, 0x72, 0x69, 0x67,
-	0x69, 0x6e, 0x61, 0x6c, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b,
-	0x65, 0x79, 0x12, 0x2e, 0x0a, 0x12, 0x45, 0x63, 0x68, 0x6f, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64,
-	0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12,
-	0x45, 0x63, 0x68, 0x6f, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b,
-	0x65, 0x79, 0x12, 0x2a, 0x0a, 0x10, 0x45, 0x63, 0x68, 0x6f, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64,
-	0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x45, 0x63,
-	0x68, 0x6f, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x22, 0xbb,
-	0x01, 0x0a, 0x05, 0x52, 0x65, 0x61, 0x64, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x52, 0x6f, 0x6f, 0x74,
-	0x48, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x52, 0x6f, 0x6f, 0x74,
-	0x48, 0x61, 0x73, 0x68, 0x12, 0x36, 0x0a, 0x16, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c,
-	0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x50, 0x72,
-	0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x30, 0x0a, 0x13,
-	0x52, 0x65, 0x61, 0x64, 0x79, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62,
-	0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x52, 0x65, 0x61, 0x64, 0x79,
-	0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2c,
-	0x0a, 0x11, 0x52, 0x65, 0x61, 0x64, 0x79, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x53,
-	0x69, 0x67, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x52, 0x65, 0x61, 0x64, 0x79,
-	0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x22, 0x4d, 0x0a, 0x06,
-	0x42, 0x42, 0x41, 0x4d, 0x73, 0x67, 0x12, 0x29, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62,
-	0x2e, 0x42, 0x42, 0x41, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70,
-	0x65, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x76, 0x0a, 0x04, 0x42,
-	0x76, 0x61, 0x6c, 0x12, 0x1e, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x49,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65,
-	0x72, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62,
-	0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x53, 0x65, 0x6e, 0x64, 0x65,
-	0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x14, 0x0a,
-	0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x56, 0x61,
-	0x6c, 0x75, 0x65, 0x22, 0x75, 0x0a, 0x03, 0x41, 0x75, 0x78, 0x12, 0x1e, 0x0a, 0x0a, 0x50, 0x72,
-	0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
-	0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x53, 0x65,
-	0x6e, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0c, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x14,
-	0x0a, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x45,
-	0x70, 0x6f, 0x63, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x22, 0xc7, 0x04, 0x0a, 0x0b, 0x47,
+	0x68, 0x12, 0x36, 0x0a, 0x16, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x50, 0x72, 0x6f,
+	0x70, 0x6f, 0x73, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x16, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x50, 0x72, 0x6f, 0x70, 0x6f,
+	0x73, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x30, 0x0a, 0x13, 0x52, 0x65, 0x61,
+	0x64, 0x79, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x52, 0x65, 0x61, 0x64, 0x79, 0x50, 0x72, 0x6f,
+	0x76, 0x69, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x11, 0x52,
+	0x65, 0x61, 0x64, 0x79, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x52, 0x65, 0x61, 0x64, 0x79, 0x50, 0x72, 0x6f,
+	0x76, 0x69, 0x64, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x12, 0x2a, 0x0a, 0x10, 0x4f, 0x72, 0x69,
+	0x67, 0x69, 0x6e, 0x61, 0x6c, 0x44, 0x61, 0x74, 0x61, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x10, 0x4f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x44, 0x61, 0x74,
+	0x61, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x4d, 0x0a, 0x06, 0x42, 0x42, 0x41, 0x4d, 0x73, 0x67, 0x12,
+	0x29, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e,
+	0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x42, 0x41, 0x4d, 0x73, 0x67,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x61,
+	0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x50, 0x61, 0x79,
+	0x6c, 0x6f, 0x61, 0x64, 0x22, 0xb2, 0x01, 0x0a, 0x04, 0x42, 0x76, 0x61, 0x6c, 0x12, 0x1e, 0x0a,
+	0x0a, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x22, 0x0a,
+	0x0c, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x41, 0x63, 0x73, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x08, 0x41, 0x63, 0x73, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x1e, 0x0a, 0x0a, 0x53, 0x65, 0x6e,
+	0x64, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x53,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x22, 0xb1, 0x01, 0x0a, 0x03, 0x41, 0x75,
+	0x78, 0x12, 0x1e, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x49,
+	0x64, 0x12, 0x22, 0x0a, 0x0c, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65,
+	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x50,
+	0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x41, 0x63, 0x73, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x08, 0x41, 0x63, 0x73, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x1e, 0x0a,
+	0x0a, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0a, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x22, 0xb4, 0x01,
+	0x0a, 0x04, 0x43, 0x6f, 0x6e, 0x66, 0x12, 0x1e, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73,
+	0x65, 0x72, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x50, 0x72, 0x6f, 0x70,
+	0x6f, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72,
+	0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x53, 0x65,
+	0x6e, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x45, 0x70,
+	0x6f, 0x63, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68,
+	0x12, 0x16, 0x0a, 0x06, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x08,
+	0x52, 0x06, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x41, 0x63, 0x73, 0x45,
+	0x70, 0x6f, 0x63, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x41, 0x63, 0x73, 0x45,
+	0x70, 0x6f, 0x63, 0x68, 0x12, 0x1e, 0x0a, 0x0a, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x53, 0x69,
+	0x67, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72,
+	0x53, 0x69, 0x67, 0x6e, 0x22, 0xcd, 0x01, 0x0a, 0x09, 0x43, 0x6f, 0x69, 0x6e, 0x53, 0x68, 0x61,
+	0x72, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72,
+	0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b,
+	0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72,
+	0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x1a, 0x0a, 0x08,
+	0x41, 0x63, 0x73, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08,
+	0x41, 0x63, 0x73, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x53, 0x68, 0x61, 0x72,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x53, 0x68, 0x61, 0x72, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x12, 0x1e, 0x0a, 0x0a, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x53, 0x69,
+	0x67, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72,
+	0x53, 0x69, 0x67, 0x6e, 0x22, 0x82, 0x01, 0x0a, 0x08, 0x44, 0x65, 0x63, 0x53, 0x68, 0x61, 0x72,
+	0x65, 0x12, 0x26, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x65, 0x72, 0x50, 0x75, 0x62,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x50, 0x72, 0x6f, 0x70, 0x6f,
+	0x73, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x22, 0x0a, 0x0c, 0x53, 0x65, 0x6e,
+	0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x53, 0x68, 0x61, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x53, 0x68,
+	0x61, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x05, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0xab, 0x01, 0x0a, 0x06, 0x44, 0x4b,
+	0x47, 0x4d, 0x73, 0x67, 0x12, 0x29, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x15, 0x2e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x70, 0x62, 0x2e, 0x44,
+	0x4b, 0x47, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x53, 0x65, 0x6e,
+	0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x18, 0x0a,
+	0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07,
+	0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x53, 0x65, 0x6e, 0x64, 0x65,
+	0x72, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x53, 0x65, 0x6e,
+	0x64, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x22, 0x49, 0x0a, 0x07, 0x44, 0x4b, 0x47, 0x44, 0x65,
+	0x61, 0x6c, 0x12, 0x20, 0x0a, 0x0b, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0b, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x45, 0x6e, 0x63, 0x53, 0x68, 0x61, 0x72, 0x65,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x09, 0x45, 0x6e, 0x63, 0x53, 0x68, 0x61, 0x72,
+	0x65, 0x73, 0x22, 0x71, 0x0a, 0x07, 0x44, 0x4b, 0x47, 0x45, 0x63, 0x68, 0x6f, 0x12, 0x16, 0x0a,
+	0x06, 0x44, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x44,
+	0x65, 0x61, 0x6c, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x44, 0x65, 0x61, 0x6c, 0x48, 0x61, 0x73,
+	0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x44, 0x65, 0x61, 0x6c, 0x48, 0x61, 0x73,
+	0x68, 0x12, 0x1c, 0x0a, 0x09, 0x53, 0x68, 0x61, 0x72, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x53, 0x68, 0x61, 0x72, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05,
+	0x50, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0x44, 0x0a, 0x06, 0x44, 0x4b, 0x47, 0x41, 0x63, 0x6b, 0x12,
+	0x20, 0x0a, 0x0b, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x50, 0x75, 0x62, 0x6b, 0x65,
+	0x79, 0x12, 0x18, 0x0a, 0x07, 0x44, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x07, 0x44, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x73, 0x22, 0xec, 0x01, 0x0a, 0x0e,
+	0x48, 0x42, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x4b, 0x65, 0x79, 0x12, 0x18,
+	0x0a, 0x07, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x4e, 0x6f, 0x64, 0x65,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x1c,
+	0x0a, 0x09, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x09, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x20, 0x0a, 0x0b,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0b, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x1e,
+	0x0a, 0x0a, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x4b, 0x65, 0x79, 0x73, 0x18, 0x05, 0x20, 0x03,
+	0x28, 0x0c, 0x52, 0x0a, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x14,
+	0x0a, 0x05, 0x53, 0x68, 0x61, 0x72, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x53,
+	0x68, 0x61, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x52, 0x65, 0x61, 0x64, 0x79, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x05, 0x52, 0x65, 0x61, 0x64, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x44, 0x65,
+	0x61, 0x6c, 0x65, 0x72, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x44, 0x65, 0x61,
+	0x6c, 0x65, 0x72, 0x73, 0x4a, 0x04, 0x08, 0x08, 0x10, 0x09, 0x22, 0xc7, 0x04, 0x0a, 0x0b, 0x47,
 	0x72, 0x6f, 0x75, 0x70, 0x49, 0x74, 0x65, 0x6d, 0x56, 0x30, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72,
 	0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f,
 	0x75, 0x70, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4e, 0x61, 0x6d,
@@ -3657,53 +5063,66 @@ var file_chain_proto_rawDesc = []byte{
 	0x0a, 0x0a, 0x06, 0x52, 0x45, 0x4d, 0x4f, 0x56, 0x45, 0x10, 0x01, 0x2a, 0x26, 0x0a, 0x0e, 0x54,
 	0x72, 0x78, 0x53, 0x74, 0x72, 0x6f, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x09, 0x0a,
 	0x05, 0x43, 0x48, 0x41, 0x49, 0x4e, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x43, 0x41, 0x43, 0x48,
-	0x45, 0x10, 0x01, 0x2a, 0x7e, 0x0a, 0x07, 0x54, 0x72, 0x78, 0x54, 0x79, 0x70, 0x65, 0x12, 0x08,
-	0x0a, 0x04, 0x50, 0x4f, 0x53, 0x54, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x4e, 0x4e, 0x4f,
-	0x55, 0x4e, 0x43, 0x45, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x50, 0x52, 0x4f, 0x44, 0x55, 0x43,
-	0x45, 0x52, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x55, 0x53, 0x45, 0x52, 0x10, 0x03, 0x12, 0x0d,
-	0x0a, 0x09, 0x52, 0x45, 0x51, 0x5f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x10, 0x04, 0x12, 0x12, 0x0a,
-	0x0e, 0x52, 0x45, 0x51, 0x5f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x52, 0x45, 0x53, 0x50, 0x10,
-	0x05, 0x12, 0x10, 0x0a, 0x0c, 0x43, 0x48, 0x41, 0x49, 0x4e, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49,
-	0x47, 0x10, 0x06, 0x12, 0x0e, 0x0a, 0x0a, 0x41, 0x50, 0x50, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49,
-	0x47, 0x10, 0x07, 0x2a, 0x50, 0x0a, 0x0c, 0x52, 0x65, 0x71, 0x42, 0x6c, 0x6b, 0x52, 0x65, 0x73,
-	0x75, 0x6c, 0x74, 0x12, 0x11, 0x0a, 0x0d, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x49, 0x4e, 0x5f,
-	0x52, 0x45, 0x53, 0x50, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f,
-	0x49, 0x4e, 0x5f, 0x52, 0x45, 0x53, 0x50, 0x5f, 0x4f, 0x4e, 0x5f, 0x54, 0x4f, 0x50, 0x10, 0x01,
-	0x12, 0x13, 0x0a, 0x0f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f,
-	0x55, 0x4e, 0x44, 0x10, 0x02, 0x2a, 0x2b, 0x0a, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x45, 0x6e,
-	0x63, 0x72, 0x79, 0x70, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x50, 0x55, 0x42,
-	0x4c, 0x49, 0x43, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x52, 0x49, 0x56, 0x41, 0x54, 0x45,
-	0x10, 0x01, 0x2a, 0x25, 0x0a, 0x11, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x6f, 0x6e, 0x73, 0x65,
-	0x6e, 0x73, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x4f, 0x41, 0x10, 0x00,
-	0x12, 0x07, 0x0a, 0x03, 0x50, 0x4f, 0x53, 0x10, 0x01, 0x2a, 0x2c, 0x0a, 0x06, 0x52, 0x6f, 0x6c,
-	0x65, 0x56, 0x30, 0x12, 0x12, 0x0a, 0x0e, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x50, 0x52, 0x4f,
-	0x44, 0x55, 0x43, 0x45, 0x52, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x47, 0x52, 0x4f, 0x55, 0x50,
-	0x5f, 0x55, 0x53, 0x45, 0x52, 0x10, 0x01, 0x2a, 0x4c, 0x0a, 0x0f, 0x43, 0x68, 0x61, 0x69, 0x6e,
-	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x45,
-	0x54, 0x5f, 0x54, 0x52, 0x58, 0x5f, 0x41, 0x55, 0x54, 0x48, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x10,
-	0x00, 0x12, 0x10, 0x0a, 0x0c, 0x55, 0x50, 0x44, 0x5f, 0x44, 0x4e, 0x59, 0x5f, 0x4c, 0x49, 0x53,
-	0x54, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x55, 0x50, 0x44, 0x5f, 0x41, 0x4c, 0x57, 0x5f, 0x4c,
-	0x49, 0x53, 0x54, 0x10, 0x02, 0x2a, 0x37, 0x0a, 0x0b, 0x54, 0x72, 0x78, 0x41, 0x75, 0x74, 0x68,
-	0x4d, 0x6f, 0x64, 0x65, 0x12, 0x13, 0x0a, 0x0f, 0x46, 0x4f, 0x4c, 0x4c, 0x4f, 0x57, 0x5f, 0x41,
-	0x4c, 0x57, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x46, 0x4f, 0x4c,
-	0x4c, 0x4f, 0x57, 0x5f, 0x44, 0x4e, 0x59, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10, 0x01, 0x2a, 0x2d,
-	0x0a, 0x0c, 0x41, 0x75, 0x74, 0x68, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0e,
-	0x0a, 0x0a, 0x41, 0x4c, 0x4c, 0x4f, 0x57, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10, 0x00, 0x12, 0x0d,
-	0x0a, 0x09, 0x44, 0x45, 0x4e, 0x59, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10, 0x01, 0x2a, 0x2e, 0x0a,
-	0x0d, 0x41, 0x70, 0x70, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x07,
-	0x0a, 0x03, 0x49, 0x4e, 0x54, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x42, 0x4f, 0x4f, 0x4c, 0x10,
-	0x01, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x54, 0x52, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x2a, 0x24, 0x0a,
-	0x10, 0x48, 0x42, 0x4d, 0x73, 0x67, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x54, 0x79, 0x70,
-	0x65, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x42, 0x43, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x42,
-	0x41, 0x10, 0x01, 0x2a, 0x33, 0x0a, 0x0a, 0x52, 0x42, 0x43, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70,
-	0x65, 0x12, 0x10, 0x0a, 0x0c, 0x49, 0x4e, 0x49, 0x54, 0x5f, 0x50, 0x52, 0x4f, 0x50, 0x4f, 0x53,
-	0x45, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x45, 0x43, 0x48, 0x4f, 0x10, 0x01, 0x12, 0x09, 0x0a,
-	0x05, 0x52, 0x45, 0x41, 0x44, 0x59, 0x10, 0x02, 0x2a, 0x1f, 0x0a, 0x0a, 0x42, 0x42, 0x41, 0x4d,
-	0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x42, 0x56, 0x41, 0x4c, 0x10, 0x00,
-	0x12, 0x07, 0x0a, 0x03, 0x41, 0x55, 0x58, 0x10, 0x01, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74,
-	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x72, 0x75, 0x6d, 0x73, 0x79, 0x73, 0x74, 0x65,
-	0x6d, 0x2f, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x62, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x45, 0x10, 0x01, 0x2a, 0xb2, 0x01, 0x0a, 0x07, 0x54, 0x72, 0x78, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x08, 0x0a, 0x04, 0x50, 0x4f, 0x53, 0x54, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x4e, 0x4e,
+	0x4f, 0x55, 0x4e, 0x43, 0x45, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x50, 0x52, 0x4f, 0x44, 0x55,
+	0x43, 0x45, 0x52, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x55, 0x53, 0x45, 0x52, 0x10, 0x03, 0x12,
+	0x0d, 0x0a, 0x09, 0x52, 0x45, 0x51, 0x5f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x10, 0x04, 0x12, 0x12,
+	0x0a, 0x0e, 0x52, 0x45, 0x51, 0x5f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x52, 0x45, 0x53, 0x50,
+	0x10, 0x05, 0x12, 0x10, 0x0a, 0x0c, 0x43, 0x48, 0x41, 0x49, 0x4e, 0x5f, 0x43, 0x4f, 0x4e, 0x46,
+	0x49, 0x47, 0x10, 0x06, 0x12, 0x0e, 0x0a, 0x0a, 0x41, 0x50, 0x50, 0x5f, 0x43, 0x4f, 0x4e, 0x46,
+	0x49, 0x47, 0x10, 0x07, 0x12, 0x09, 0x0a, 0x05, 0x53, 0x54, 0x41, 0x4b, 0x45, 0x10, 0x08, 0x12,
+	0x10, 0x0a, 0x0c, 0x52, 0x45, 0x51, 0x5f, 0x53, 0x4e, 0x41, 0x50, 0x53, 0x48, 0x4f, 0x54, 0x10,
+	0x09, 0x12, 0x15, 0x0a, 0x11, 0x52, 0x45, 0x51, 0x5f, 0x53, 0x4e, 0x41, 0x50, 0x53, 0x48, 0x4f,
+	0x54, 0x5f, 0x52, 0x45, 0x53, 0x50, 0x10, 0x0a, 0x2a, 0x50, 0x0a, 0x0c, 0x52, 0x65, 0x71, 0x42,
+	0x6c, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x11, 0x0a, 0x0d, 0x42, 0x4c, 0x4f, 0x43,
+	0x4b, 0x5f, 0x49, 0x4e, 0x5f, 0x52, 0x45, 0x53, 0x50, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x42,
+	0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x49, 0x4e, 0x5f, 0x52, 0x45, 0x53, 0x50, 0x5f, 0x4f, 0x4e, 0x5f,
+	0x54, 0x4f, 0x50, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x4e,
+	0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x02, 0x2a, 0x2b, 0x0a, 0x10, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0a,
+	0x0a, 0x06, 0x50, 0x55, 0x42, 0x4c, 0x49, 0x43, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x52,
+	0x49, 0x56, 0x41, 0x54, 0x45, 0x10, 0x01, 0x2a, 0x25, 0x0a, 0x11, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x07, 0x0a, 0x03,
+	0x50, 0x4f, 0x41, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x4f, 0x53, 0x10, 0x01, 0x2a, 0x2c,
+	0x0a, 0x06, 0x52, 0x6f, 0x6c, 0x65, 0x56, 0x30, 0x12, 0x12, 0x0a, 0x0e, 0x47, 0x52, 0x4f, 0x55,
+	0x50, 0x5f, 0x50, 0x52, 0x4f, 0x44, 0x55, 0x43, 0x45, 0x52, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a,
+	0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x55, 0x53, 0x45, 0x52, 0x10, 0x01, 0x2a, 0x7a, 0x0a, 0x0f,
+	0x43, 0x68, 0x61, 0x69, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x15, 0x0a, 0x11, 0x53, 0x45, 0x54, 0x5f, 0x54, 0x52, 0x58, 0x5f, 0x41, 0x55, 0x54, 0x48, 0x5f,
+	0x4d, 0x4f, 0x44, 0x45, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x55, 0x50, 0x44, 0x5f, 0x44, 0x4e,
+	0x59, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x55, 0x50, 0x44, 0x5f,
+	0x41, 0x4c, 0x57, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10, 0x02, 0x12, 0x16, 0x0a, 0x12, 0x53, 0x45,
+	0x54, 0x5f, 0x4d, 0x45, 0x4d, 0x50, 0x4f, 0x4f, 0x4c, 0x5f, 0x50, 0x4f, 0x4c, 0x49, 0x43, 0x59,
+	0x10, 0x03, 0x12, 0x14, 0x0a, 0x10, 0x53, 0x45, 0x54, 0x5f, 0x45, 0x50, 0x4f, 0x43, 0x48, 0x5f,
+	0x50, 0x41, 0x43, 0x49, 0x4e, 0x47, 0x10, 0x04, 0x2a, 0x37, 0x0a, 0x0b, 0x54, 0x72, 0x78, 0x41,
+	0x75, 0x74, 0x68, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x13, 0x0a, 0x0f, 0x46, 0x4f, 0x4c, 0x4c, 0x4f,
+	0x57, 0x5f, 0x41, 0x4c, 0x57, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f,
+	0x46, 0x4f, 0x4c, 0x4c, 0x4f, 0x57, 0x5f, 0x44, 0x4e, 0x59, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10,
+	0x01, 0x2a, 0x2d, 0x0a, 0x0c, 0x41, 0x75, 0x74, 0x68, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x0e, 0x0a, 0x0a, 0x41, 0x4c, 0x4c, 0x4f, 0x57, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10,
+	0x00, 0x12, 0x0d, 0x0a, 0x09, 0x44, 0x45, 0x4e, 0x59, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10, 0x01,
+	0x2a, 0x2e, 0x0a, 0x0d, 0x41, 0x70, 0x70, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x4e, 0x54, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x42, 0x4f,
+	0x4f, 0x4c, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x54, 0x52, 0x49, 0x4e, 0x47, 0x10, 0x02,
+	0x2a, 0x36, 0x0a, 0x10, 0x48, 0x42, 0x4d, 0x73, 0x67, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x42, 0x43, 0x10, 0x00, 0x12, 0x07, 0x0a,
+	0x03, 0x42, 0x42, 0x41, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x44, 0x45, 0x43, 0x10, 0x02, 0x12,
+	0x07, 0x0a, 0x03, 0x44, 0x4b, 0x47, 0x10, 0x03, 0x2a, 0x33, 0x0a, 0x0a, 0x52, 0x42, 0x43, 0x4d,
+	0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x49, 0x4e, 0x49, 0x54, 0x5f, 0x50,
+	0x52, 0x4f, 0x50, 0x4f, 0x53, 0x45, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x45, 0x43, 0x48, 0x4f,
+	0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x52, 0x45, 0x41, 0x44, 0x59, 0x10, 0x02, 0x2a, 0x33, 0x0a,
+	0x0a, 0x42, 0x42, 0x41, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x42,
+	0x56, 0x41, 0x4c, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x55, 0x58, 0x10, 0x01, 0x12, 0x08,
+	0x0a, 0x04, 0x43, 0x4f, 0x49, 0x4e, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x43, 0x4f, 0x4e, 0x46,
+	0x10, 0x03, 0x2a, 0x42, 0x0a, 0x0a, 0x44, 0x4b, 0x47, 0x4d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x0b, 0x0a, 0x07, 0x44, 0x4b, 0x47, 0x5f, 0x4b, 0x45, 0x59, 0x10, 0x00, 0x12, 0x0c, 0x0a,
+	0x08, 0x44, 0x4b, 0x47, 0x5f, 0x44, 0x45, 0x41, 0x4c, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x44,
+	0x4b, 0x47, 0x5f, 0x41, 0x43, 0x4b, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x44, 0x4b, 0x47, 0x5f,
+	0x45, 0x43, 0x48, 0x4f, 0x10, 0x03, 0x42, 0x24, 0x5a, 0x22, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x72, 0x75, 0x6d, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x2f, 0x71,
+	0x75, 0x6f, 0x72, 0x75, 0x6d, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -3718,8 +5137,8 @@ func file_chain_proto_rawDescGZIP() []byte {
 	return file_chain_proto_rawDescData
 }
 
-var file_chain_proto_enumTypes = make([]protoimpl.EnumInfo, 17)
-var file_chain_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
+var file_chain_proto_enumTypes = make([]protoimpl.EnumInfo, 18)
+var file_chain_proto_msgTypes = make([]protoimpl.MessageInfo, 43)
 var file_chain_proto_goTypes = []interface{}{
 	(PackageType)(0),                 // 0: quorum.pb.PackageType
 	(AnnounceType)(0),                // 1: quorum.pb.AnnounceType
@@ -3738,74 +5157,95 @@ var file_chain_proto_goTypes = []interface{}{
 	(HBMsgPayloadType)(0),            // 14: quorum.pb.HBMsgPayloadType
 	(RBCMsgType)(0),                  // 15: quorum.pb.RBCMsgType
 	(BBAMsgType)(0),                  // 16: quorum.pb.BBAMsgType
-	(*Package)(nil),                  // 17: quorum.pb.Package
-	(*Trx)(nil),                      // 18: quorum.pb.Trx
-	(*Block)(nil),                    // 19: quorum.pb.Block
-	(*ReqBlock)(nil),                 // 20: quorum.pb.ReqBlock
-	(*BlocksBundle)(nil),             // 21: quorum.pb.BlocksBundle
-	(*ReqBlockResp)(nil),             // 22: quorum.pb.ReqBlockResp
-	(*PostItem)(nil),                 // 23: quorum.pb.PostItem
-	(*ProducerItem)(nil),             // 24: quorum.pb.ProducerItem
-	(*BFTProducerBundleItem)(nil),    // 25: quorum.pb.BFTProducerBundleItem
-	(*UserItem)(nil),                 // 26: quorum.pb.UserItem
-	(*AnnounceItem)(nil),             // 27: quorum.pb.AnnounceItem
-	(*GroupItem)(nil),                // 28: quorum.pb.GroupItem
-	(*ChainConfigItem)(nil),          // 29: quorum.pb.ChainConfigItem
-	(*ChainSendTrxRuleListItem)(nil), // 30: quorum.pb.ChainSendTrxRuleListItem
-	(*SetTrxAuthModeItem)(nil),       // 31: quorum.pb.SetTrxAuthModeItem
-	(*AppConfigItem)(nil),            // 32: quorum.pb.AppConfigItem
-	(*GroupSeed)(nil),                // 33: quorum.pb.GroupSeed
-	(*NodeSDKGroupItem)(nil),         // 34: quorum.pb.NodeSDKGroupItem
-	(*HBTrxBundle)(nil),              // 35: quorum.pb.HBTrxBundle
-	(*HBMsgv1)(nil),                  // 36: quorum.pb.HBMsgv1
-	(*RBCMsg)(nil),                   // 37: quorum.pb.RBCMsg
-	(*InitPropose)(nil),              // 38: quorum.pb.InitPropose
-	(*Echo)(nil),                     // 39: quorum.pb.Echo
-	(*Ready)(nil),                    // 40: quorum.pb.Ready
-	(*BBAMsg)(nil),                   // 41: quorum.pb.BBAMsg
-	(*Bval)(nil),                     // 42: quorum.pb.Bval
-	(*Aux)(nil),                      // 43: quorum.pb.Aux
-	(*GroupItemV0)(nil),              // 44: quorum.pb.GroupItemV0
+	(DKGMsgType)(0),                  // 17: quorum.pb.DKGMsgType
+	(*Package)(nil),                  // 18: quorum.pb.Package
+	(*Trx)(nil),                      // 19: quorum.pb.Trx
+	(*Block)(nil),                    // 20: quorum.pb.Block
+	(*ReqBlock)(nil),                 // 21: quorum.pb.ReqBlock
+	(*BlocksBundle)(nil),             // 22: quorum.pb.BlocksBundle
+	(*ReqBlockResp)(nil),             // 23: quorum.pb.ReqBlockResp
+	(*SnapshotItem)(nil),             // 24: quorum.pb.SnapshotItem
+	(*Snapshot)(nil),                 // 25: quorum.pb.Snapshot
+	(*ReqSnapshot)(nil),              // 26: quorum.pb.ReqSnapshot
+	(*ReqSnapshotResp)(nil),          // 27: quorum.pb.ReqSnapshotResp
+	(*PostItem)(nil),                 // 28: quorum.pb.PostItem
+	(*ProducerItem)(nil),             // 29: quorum.pb.ProducerItem
+	(*BFTProducerBundleItem)(nil),    // 30: quorum.pb.BFTProducerBundleItem
+	(*StakeItem)(nil),                // 31: quorum.pb.StakeItem
+	(*UserItem)(nil),                 // 32: quorum.pb.UserItem
+	(*AnnounceItem)(nil),             // 33: quorum.pb.AnnounceItem
+	(*GroupItem)(nil),                // 34: quorum.pb.GroupItem
+	(*ChainConfigItem)(nil),          // 35: quorum.pb.ChainConfigItem
+	(*ChainSendTrxRuleListItem)(nil), // 36: quorum.pb.ChainSendTrxRuleListItem
+	(*SetTrxAuthModeItem)(nil),       // 37: quorum.pb.SetTrxAuthModeItem
+	(*MempoolPolicyItem)(nil),        // 38: quorum.pb.MempoolPolicyItem
+	(*EpochPacingItem)(nil),          // 39: quorum.pb.EpochPacingItem
+	(*AppConfigItem)(nil),            // 40: quorum.pb.AppConfigItem
+	(*GroupSeed)(nil),                // 41: quorum.pb.GroupSeed
+	(*NodeSDKGroupItem)(nil),         // 42: quorum.pb.NodeSDKGroupItem
+	(*HBTrxBundle)(nil),              // 43: quorum.pb.HBTrxBundle
+	(*HBMsgv1)(nil),                  // 44: quorum.pb.HBMsgv1
+	(*RBCMsg)(nil),                   // 45: quorum.pb.RBCMsg
+	(*InitPropose)(nil),              // 46: quorum.pb.InitPropose
+	(*Echo)(nil),                     // 47: quorum.pb.Echo
+	(*Ready)(nil),                    // 48: quorum.pb.Ready
+	(*BBAMsg)(nil),                   // 49: quorum.pb.BBAMsg
+	(*Bval)(nil),                     // 50: quorum.pb.Bval
+	(*Aux)(nil),                      // 51: quorum.pb.Aux
+	(*Conf)(nil),                     // 52: quorum.pb.Conf
+	(*CoinShare)(nil),                // 53: quorum.pb.CoinShare
+	(*DecShare)(nil),                 // 54: quorum.pb.DecShare
+	(*DKGMsg)(nil),                   // 55: quorum.pb.DKGMsg
+	(*DKGDeal)(nil),                  // 56: quorum.pb.DKGDeal
+	(*DKGEcho)(nil),                  // 57: quorum.pb.DKGEcho
+	(*DKGAck)(nil),                   // 58: quorum.pb.DKGAck
+	(*HBThresholdKey)(nil),           // 59: quorum.pb.HBThresholdKey
+	(*GroupItemV0)(nil),              // 60: quorum.pb.GroupItemV0
 }
 var file_chain_proto_depIdxs = []int32{
 	0,  // 0: quorum.pb.Package.type:type_name -> quorum.pb.PackageType
 	5,  // 1: quorum.pb.Trx.Type:type_name -> quorum.pb.TrxType
 	4,  // 2: quorum.pb.Trx.StorageType:type_name -> quorum.pb.TrxStroageType
-	18, // 3: quorum.pb.Block.Trxs:type_name -> quorum.pb.Trx
-	19, // 4: quorum.pb.BlocksBundle.Blocks:type_name -> quorum.pb.Block
+	19, // 3: quorum.pb.Block.Trxs:type_name -> quorum.pb.Trx
+	20, // 4: quorum.pb.BlocksBundle.Blocks:type_name -> quorum.pb.Block
 	6,  // 5: quorum.pb.ReqBlockResp.Result:type_name -> quorum.pb.ReqBlkResult
-	21, // 6: quorum.pb.ReqBlockResp.Blocks:type_name -> quorum.pb.BlocksBundle
-	3,  // 7: quorum.pb.ProducerItem.Action:type_name -> quorum.pb.ActionType
-	24, // 8: quorum.pb.BFTProducerBundleItem.Producers:type_name -> quorum.pb.ProducerItem
-	3,  // 9: quorum.pb.UserItem.Action:type_name -> quorum.pb.ActionType
-	1,  // 10: quorum.pb.AnnounceItem.Type:type_name -> quorum.pb.AnnounceType
-	2,  // 11: quorum.pb.AnnounceItem.Result:type_name -> quorum.pb.ApproveType
-	3,  // 12: quorum.pb.AnnounceItem.Action:type_name -> quorum.pb.ActionType
-	19, // 13: quorum.pb.GroupItem.GenesisBlock:type_name -> quorum.pb.Block
-	7,  // 14: quorum.pb.GroupItem.EncryptType:type_name -> quorum.pb.GroupEncryptType
-	8,  // 15: quorum.pb.GroupItem.ConsenseType:type_name -> quorum.pb.GroupConsenseType
-	10, // 16: quorum.pb.ChainConfigItem.Type:type_name -> quorum.pb.ChainConfigType
-	3,  // 17: quorum.pb.ChainSendTrxRuleListItem.Action:type_name -> quorum.pb.ActionType
-	5,  // 18: quorum.pb.ChainSendTrxRuleListItem.Type:type_name -> quorum.pb.TrxType
-	5,  // 19: quorum.pb.SetTrxAuthModeItem.Type:type_name -> quorum.pb.TrxType
-	11, // 20: quorum.pb.SetTrxAuthModeItem.Mode:type_name -> quorum.pb.TrxAuthMode
-	3,  // 21: quorum.pb.AppConfigItem.Action:type_name -> quorum.pb.ActionType
-	13, // 22: quorum.pb.AppConfigItem.Type:type_name -> quorum.pb.AppConfigType
-	19, // 23: quorum.pb.GroupSeed.GenesisBlock:type_name -> quorum.pb.Block
-	28, // 24: quorum.pb.NodeSDKGroupItem.Group:type_name -> quorum.pb.GroupItem
-	18, // 25: quorum.pb.HBTrxBundle.Trxs:type_name -> quorum.pb.Trx
-	14, // 26: quorum.pb.HBMsgv1.PayloadType:type_name -> quorum.pb.HBMsgPayloadType
-	15, // 27: quorum.pb.RBCMsg.Type:type_name -> quorum.pb.RBCMsgType
-	16, // 28: quorum.pb.BBAMsg.Type:type_name -> quorum.pb.BBAMsgType
-	9,  // 29: quorum.pb.GroupItemV0.UserRole:type_name -> quorum.pb.RoleV0
-	19, // 30: quorum.pb.GroupItemV0.GenesisBlock:type_name -> quorum.pb.Block
-	7,  // 31: quorum.pb.GroupItemV0.EncryptType:type_name -> quorum.pb.GroupEncryptType
-	8,  // 32: quorum.pb.GroupItemV0.ConsenseType:type_name -> quorum.pb.GroupConsenseType
-	33, // [33:33] is the sub-list for method output_type
-	33, // [33:33] is the sub-list for method input_type
-	33, // [33:33] is the sub-list for extension type_name
-	33, // [33:33] is the sub-list for extension extendee
-	0,  // [0:33] is the sub-list for field type_name
+	22, // 6: quorum.pb.ReqBlockResp.Blocks:type_name -> quorum.pb.BlocksBundle
+	20, // 7: quorum.pb.Snapshot.Block:type_name -> quorum.pb.Block
+	24, // 8: quorum.pb.Snapshot.Items:type_name -> quorum.pb.SnapshotItem
+	25, // 9: quorum.pb.ReqSnapshotResp.Snapshot:type_name -> quorum.pb.Snapshot
+	3,  // 10: quorum.pb.ProducerItem.Action:type_name -> quorum.pb.ActionType
+	29, // 11: quorum.pb.BFTProducerBundleItem.Producers:type_name -> quorum.pb.ProducerItem
+	3,  // 12: quorum.pb.StakeItem.Action:type_name -> quorum.pb.ActionType
+	3,  // 13: quorum.pb.UserItem.Action:type_name -> quorum.pb.ActionType
+	1,  // 14: quorum.pb.AnnounceItem.Type:type_name -> quorum.pb.AnnounceType
+	2,  // 15: quorum.pb.AnnounceItem.Result:type_name -> quorum.pb.ApproveType
+	3,  // 16: quorum.pb.AnnounceItem.Action:type_name -> quorum.pb.ActionType
+	20, // 17: quorum.pb.GroupItem.GenesisBlock:type_name -> quorum.pb.Block
+	7,  // 18: quorum.pb.GroupItem.EncryptType:type_name -> quorum.pb.GroupEncryptType
+	8,  // 19: quorum.pb.GroupItem.ConsenseType:type_name -> quorum.pb.GroupConsenseType
+	10, // 20: quorum.pb.ChainConfigItem.Type:type_name -> quorum.pb.ChainConfigType
+	3,  // 21: quorum.pb.ChainSendTrxRuleListItem.Action:type_name -> quorum.pb.ActionType
+	5,  // 22: quorum.pb.ChainSendTrxRuleListItem.Type:type_name -> quorum.pb.TrxType
+	5,  // 23: quorum.pb.SetTrxAuthModeItem.Type:type_name -> quorum.pb.TrxType
+	11, // 24: quorum.pb.SetTrxAuthModeItem.Mode:type_name -> quorum.pb.TrxAuthMode
+	3,  // 25: quorum.pb.AppConfigItem.Action:type_name -> quorum.pb.ActionType
+	13, // 26: quorum.pb.AppConfigItem.Type:type_name -> quorum.pb.AppConfigType
+	20, // 27: quorum.pb.GroupSeed.GenesisBlock:type_name -> quorum.pb.Block
+	34, // 28: quorum.pb.NodeSDKGroupItem.Group:type_name -> quorum.pb.GroupItem
+	19, // 29: quorum.pb.HBTrxBundle.Trxs:type_name -> quorum.pb.Trx
+	14, // 30: quorum.pb.HBMsgv1.PayloadType:type_name -> quorum.pb.HBMsgPayloadType
+	15, // 31: quorum.pb.RBCMsg.Type:type_name -> quorum.pb.RBCMsgType
+	16, // 32: quorum.pb.BBAMsg.Type:type_name -> quorum.pb.BBAMsgType
+	17, // 33: quorum.pb.DKGMsg.Type:type_name -> quorum.pb.DKGMsgType
+	9,  // 34: quorum.pb.GroupItemV0.UserRole:type_name -> quorum.pb.RoleV0
+	20, // 35: quorum.pb.GroupItemV0.GenesisBlock:type_name -> quorum.pb.Block
+	7,  // 36: quorum.pb.GroupItemV0.EncryptType:type_name -> quorum.pb.GroupEncryptType
+	8,  // 37: quorum.pb.GroupItemV0.ConsenseType:type_name -> quorum.pb.GroupConsenseType
+	38, // [38:38] is the sub-list for method output_type
+	38, // [38:38] is the sub-list for method input_type
+	38, // [38:38] is the sub-list for extension type_name
+	38, // [38:38] is the sub-list for extension extendee
+	0,  // [0:38] is the sub-list for field type_name
 }
 
 func init() { file_chain_proto_init() }
@@ -3887,7 +5327,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PostItem); i {
+			switch v := v.(*SnapshotItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3899,7 +5339,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProducerItem); i {
+			switch v := v.(*Snapshot); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3911,7 +5351,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BFTProducerBundleItem); i {
+			switch v := v.(*ReqSnapshot); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3923,7 +5363,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UserItem); i {
+			switch v := v.(*ReqSnapshotResp); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3935,7 +5375,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AnnounceItem); i {
+			switch v := v.(*PostItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3947,7 +5387,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GroupItem); i {
+			switch v := v.(*ProducerItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3959,7 +5399,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ChainConfigItem); i {
+			switch v := v.(*BFTProducerBundleItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3971,7 +5411,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ChainSendTrxRuleListItem); i {
+			switch v := v.(*StakeItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3983,7 +5423,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetTrxAuthModeItem); i {
+			switch v := v.(*UserItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -3995,7 +5435,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AppConfigItem); i {
+			switch v := v.(*AnnounceItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4007,7 +5447,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GroupSeed); i {
+			switch v := v.(*GroupItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4019,7 +5459,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NodeSDKGroupItem); i {
+			switch v := v.(*ChainConfigItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4031,7 +5471,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HBTrxBundle); i {
+			switch v := v.(*ChainSendTrxRuleListItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4043,7 +5483,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HBMsgv1); i {
+			switch v := v.(*SetTrxAuthModeItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4055,7 +5495,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RBCMsg); i {
+			switch v := v.(*MempoolPolicyItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4067,7 +5507,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InitPropose); i {
+			switch v := v.(*EpochPacingItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4079,7 +5519,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Echo); i {
+			switch v := v.(*AppConfigItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4091,7 +5531,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Ready); i {
+			switch v := v.(*GroupSeed); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4103,7 +5543,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BBAMsg); i {
+			switch v := v.(*NodeSDKGroupItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4115,7 +5555,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Bval); i {
+			switch v := v.(*HBTrxBundle); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4127,7 +5567,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Aux); i {
+			switch v := v.(*HBMsgv1); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4139,6 +5579,186 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RBCMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InitPropose); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Echo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Ready); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BBAMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Bval); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Aux); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Conf); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CoinShare); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DecShare); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DKGMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DKGDeal); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DKGEcho); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DKGAck); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HBThresholdKey); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GroupItemV0); i {
 			case 0:
 				return &v.state
@@ -4156,8 +5776,8 @@ func file_chain_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_chain_proto_rawDesc,
-			NumEnums:      17,
-			NumMessages:   28,
+			NumEnums:      18,
+			NumMessages:   43,
 			NumExtensions: 0,
 			NumServices:   0,
 		},